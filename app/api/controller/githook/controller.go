@@ -27,6 +27,7 @@ import (
 	eventsrepo "github.com/easysoft/gitfox/app/events/repo"
 	"github.com/easysoft/gitfox/app/services/codeowners"
 	"github.com/easysoft/gitfox/app/services/protection"
+	"github.com/easysoft/gitfox/app/services/publickey"
 	"github.com/easysoft/gitfox/app/services/settings"
 	"github.com/easysoft/gitfox/app/store"
 	"github.com/easysoft/gitfox/app/url"
@@ -57,6 +58,7 @@ type Controller struct {
 	preReceiveExtender  PreReceiveExtender
 	updateExtender      UpdateExtender
 	postReceiveExtender PostReceiveExtender
+	signatureVerifier   *publickey.SignatureVerifier
 }
 
 func NewController(
@@ -77,6 +79,7 @@ func NewController(
 	postReceiveExtender PostReceiveExtender,
 	codeOwners *codeowners.Service,
 	reviewerStore store.PullReqReviewerStore,
+	signatureVerifier *publickey.SignatureVerifier,
 ) *Controller {
 	return &Controller{
 		authorizer:          authorizer,
@@ -96,6 +99,7 @@ func NewController(
 		postReceiveExtender: postReceiveExtender,
 		codeOwners:          codeOwners,
 		reviewerStore:       reviewerStore,
+		signatureVerifier:   signatureVerifier,
 	}
 }
 
@@ -91,7 +91,7 @@ func (c *Controller) PreReceive(
 
 		dummySession := &auth.Session{Principal: *principal, Metadata: nil}
 
-		err = c.checkProtectionRules(ctx, dummySession, repo, refUpdates, &output)
+		err = c.checkProtectionRules(ctx, rgit, dummySession, repo, in, refUpdates, &output)
 		if output.Error != nil {
 			return output, nil
 		}
@@ -144,8 +144,10 @@ func (c *Controller) blockPullReqRefUpdate(refUpdates changedRefs, state enum.Re
 
 func (c *Controller) checkProtectionRules(
 	ctx context.Context,
+	rgit RestrictedGIT,
 	session *auth.Session,
 	repo *types.Repository,
+	in types.GithookPreReceiveInput,
 	refUpdates changedRefs,
 	output *hook.Output,
 ) error {
@@ -176,6 +178,9 @@ func (c *Controller) checkProtectionRules(
 			RefAction:   refAction,
 			RefType:     refType,
 			RefNames:    names,
+			UnsignedCommits: func(ctx context.Context, branch string) ([]string, error) {
+				return c.findUnsignedCommits(ctx, rgit, repo, in, branch)
+			},
 		})
 		if err != nil {
 			errCheckAction = fmt.Errorf("failed to verify protection rules for git push: %w", err)
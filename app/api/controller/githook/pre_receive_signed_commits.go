@@ -0,0 +1,71 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package githook
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/easysoft/gitfox/errors"
+	"github.com/easysoft/gitfox/git"
+	"github.com/easysoft/gitfox/types"
+)
+
+// findUnsignedCommits returns the pushed commits of the branch without a verified signature.
+// The commits added to the branch are checked, even if another branch already has them. For a new branch
+// the commits after its merge base with the default branch are checked.
+func (c *Controller) findUnsignedCommits(
+	ctx context.Context,
+	rgit RestrictedGIT,
+	repo *types.Repository,
+	in types.GithookPreReceiveInput,
+	branch string,
+) ([]string, error) {
+	for _, refUpdate := range in.RefUpdates {
+		if refUpdate.Ref != gitReferenceNamePrefixBranch+branch || refUpdate.New.IsNil() {
+			continue
+		}
+
+		readParams := git.ReadParams{
+			RepoUID:             repo.GitUID,
+			AlternateObjectDirs: in.Environment.AlternateObjectDirs,
+		}
+
+		var baseBranch string
+		if refUpdate.Old.IsNil() && branch != repo.DefaultBranch {
+			_, err := rgit.GetBranch(ctx, &git.GetBranchParams{
+				ReadParams: readParams,
+				BranchName: repo.DefaultBranch,
+			})
+			switch {
+			case errors.IsNotFound(err):
+				// the default branch wasn't created yet, all commits of the branch are new.
+			case err != nil:
+				return nil, fmt.Errorf("failed to get default branch: %w", err)
+			default:
+				baseBranch = repo.DefaultBranch
+			}
+		}
+
+		commitSHAs, err := rgit.ListNewCommitSHAs(ctx, &git.ListNewCommitsParams{
+			ReadParams: readParams,
+			SHA:        refUpdate.New,
+			OldSHA:     refUpdate.Old,
+			BaseBranch: baseBranch,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list new commits: %w", err)
+		}
+		if len(commitSHAs) == 0 {
+			return nil, nil
+		}
+
+		return c.signatureVerifier.FindUnsigned(ctx, repo, in.Environment.AlternateObjectDirs, commitSHAs)
+	}
+
+	return nil, nil
+}
@@ -22,6 +22,7 @@ import (
 	eventsrepo "github.com/easysoft/gitfox/app/events/repo"
 	"github.com/easysoft/gitfox/app/services/codeowners"
 	"github.com/easysoft/gitfox/app/services/protection"
+	"github.com/easysoft/gitfox/app/services/publickey"
 	"github.com/easysoft/gitfox/app/services/settings"
 	"github.com/easysoft/gitfox/app/store"
 	"github.com/easysoft/gitfox/app/url"
@@ -61,6 +62,7 @@ func ProvideController(
 	postReceiveExtender PostReceiveExtender,
 	codeOwners *codeowners.Service,
 	reviewerStore store.PullReqReviewerStore,
+	signatureVerifier *publickey.SignatureVerifier,
 ) *Controller {
 	ctrl := NewController(
 		authorizer,
@@ -80,6 +82,7 @@ func ProvideController(
 		postReceiveExtender,
 		codeOwners,
 		reviewerStore,
+		signatureVerifier,
 	)
 
 	// TODO: improve wiring if possible
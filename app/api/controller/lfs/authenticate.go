@@ -0,0 +1,68 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package lfs
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/easysoft/gitfox/app/api/usererror"
+	"github.com/easysoft/gitfox/app/auth"
+	"github.com/easysoft/gitfox/app/jwt"
+	"github.com/easysoft/gitfox/types/enum"
+)
+
+// tokenLifetime is the lifetime of the token authenticated over ssh, the lfs client authenticates again once expired.
+const tokenLifetime = 10 * time.Minute
+
+// Authenticate answers the git-lfs-authenticate ssh command, it returns a short-lived token
+// permitted for the operation on the repository only, the token is accepted by the git lfs api only.
+func (c *Controller) Authenticate(
+	ctx context.Context,
+	session *auth.Session,
+	repoRef string,
+	operation string,
+) (*AuthenticateResponse, error) {
+	permissions := []enum.Permission{enum.PermissionRepoView}
+	switch operation {
+	case OperationDownload:
+	case OperationUpload:
+		permissions = append(permissions, enum.PermissionRepoPush)
+	default:
+		return nil, usererror.BadRequestf("unsupported lfs operation %q", operation)
+	}
+
+	repo, err := c.getRepoCheckAccess(ctx, session, repoRef, permissions[len(permissions)-1])
+	if err != nil {
+		return nil, err
+	}
+
+	// the token is signed with the salt of the principal, as the tokens issued for the principal
+	principal, err := c.principalStore.Find(ctx, session.Principal.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find principal: %w", err)
+	}
+
+	lifetime := tokenLifetime
+	token, err := jwt.GenerateForTokenWithAccessPermissions(principal.ID, &lifetime, principal.Salt,
+		&jwt.SubClaimsAccessPermissions{
+			Source: jwt.LFSSource,
+			Permissions: []jwt.AccessPermissions{
+				{SpaceID: repo.ParentID, RepoID: repo.ID, Permissions: permissions},
+			},
+		})
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate lfs token: %w", err)
+	}
+
+	return &AuthenticateResponse{
+		Href:      c.urlProvider.GenerateGITCloneURL(ctx, repo.Path) + "/info/lfs",
+		Header:    map[string]string{"Authorization": "Bearer " + token},
+		ExpiresIn: int64(tokenLifetime.Seconds()),
+	}, nil
+}
@@ -0,0 +1,108 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package lfs
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/easysoft/gitfox/app/api/usererror"
+	"github.com/easysoft/gitfox/app/auth"
+	"github.com/easysoft/gitfox/git/parser"
+	gitfox_store "github.com/easysoft/gitfox/store"
+	"github.com/easysoft/gitfox/types/enum"
+
+	"golang.org/x/exp/slices"
+)
+
+// Batch answers the lfs batch request with the basic transfer actions of the objects.
+// The authorization of the request is forwarded in the action headers,
+// so the transfers are authenticated the same way as the batch request.
+func (c *Controller) Batch(
+	ctx context.Context,
+	session *auth.Session,
+	repoRef string,
+	in *BatchRequest,
+	authorization string,
+) (*BatchResponse, error) {
+	permission := enum.PermissionRepoView
+	switch in.Operation {
+	case OperationDownload:
+	case OperationUpload:
+		permission = enum.PermissionRepoPush
+	default:
+		return nil, usererror.UnprocessableEntityf("unsupported lfs operation %q", in.Operation)
+	}
+	if in.HashAlgo != "" && in.HashAlgo != HashAlgoSHA256 {
+		return nil, usererror.Newf(http.StatusConflict, "unsupported hash algorithm %q", in.HashAlgo)
+	}
+	if len(in.Transfers) > 0 && !slices.Contains(in.Transfers, TransferBasic) {
+		return nil, usererror.UnprocessableEntityf("only the %s transfer is supported", TransferBasic)
+	}
+
+	repo, err := c.getRepoCheckAccess(ctx, session, repoRef, permission)
+	if err != nil {
+		return nil, err
+	}
+	if in.Operation == OperationUpload && repo.Mirror {
+		return nil, gitfox_store.ErrReadOnlyMirrorRepo
+	}
+
+	oids := make([]string, 0, len(in.Objects))
+	for _, obj := range in.Objects {
+		if parser.IsValidLFSObjectID(obj.OID) {
+			oids = append(oids, obj.OID)
+		}
+	}
+	existing, err := c.lfsStore.FindMany(ctx, repo.ID, oids)
+	if err != nil {
+		return nil, err
+	}
+	sizes := make(map[string]int64, len(existing))
+	for _, obj := range existing {
+		sizes[obj.OID] = obj.Size
+	}
+
+	var header map[string]string
+	if authorization != "" {
+		header = map[string]string{"Authorization": authorization}
+	}
+	objectsURL := c.urlProvider.GenerateGITCloneURL(ctx, repo.Path) + "/info/lfs/objects/"
+
+	out := &BatchResponse{
+		Transfer: TransferBasic,
+		Objects:  make([]*ObjectResponse, len(in.Objects)),
+		HashAlgo: HashAlgoSHA256,
+	}
+	for i, obj := range in.Objects {
+		resp := &ObjectResponse{Pointer: obj}
+		out.Objects[i] = resp
+
+		if !parser.IsValidLFSObjectID(obj.OID) || obj.Size < 0 {
+			resp.Error = &ObjectError{Code: http.StatusUnprocessableEntity, Message: "invalid object"}
+			continue
+		}
+
+		size, exists := sizes[obj.OID]
+		switch {
+		case in.Operation == OperationDownload && !exists:
+			resp.Error = &ObjectError{Code: http.StatusNotFound, Message: "object not found"}
+		case in.Operation == OperationDownload:
+			resp.Size = size
+			resp.Actions = map[string]*Action{
+				OperationDownload: {Href: objectsURL + obj.OID, Header: header},
+			}
+		case !exists:
+			resp.Actions = map[string]*Action{
+				OperationUpload: {Href: objectsURL + obj.OID, Header: header},
+			}
+		}
+		// the object uploaded already is answered without actions
+	}
+
+	return out, nil
+}
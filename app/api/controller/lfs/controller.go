@@ -0,0 +1,80 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package lfs
+
+import (
+	"context"
+	"fmt"
+
+	apiauth "github.com/easysoft/gitfox/app/api/auth"
+	"github.com/easysoft/gitfox/app/api/controller/repo"
+	"github.com/easysoft/gitfox/app/auth"
+	"github.com/easysoft/gitfox/app/auth/authz"
+	"github.com/easysoft/gitfox/app/jwt"
+	lfsservice "github.com/easysoft/gitfox/app/services/lfs"
+	"github.com/easysoft/gitfox/app/store"
+	"github.com/easysoft/gitfox/app/url"
+	"github.com/easysoft/gitfox/types"
+	"github.com/easysoft/gitfox/types/enum"
+)
+
+type Controller struct {
+	authorizer         authz.Authorizer
+	repoStore          store.RepoStore
+	principalStore     store.PrincipalStore
+	principalInfoCache store.PrincipalInfoCache
+	lfsStore           store.LFSObjectStore
+	lockStore          store.LFSLockStore
+	lfsSvc             *lfsservice.Service
+	urlProvider        url.Provider
+}
+
+func NewController(
+	authorizer authz.Authorizer,
+	repoStore store.RepoStore,
+	principalStore store.PrincipalStore,
+	principalInfoCache store.PrincipalInfoCache,
+	lfsStore store.LFSObjectStore,
+	lockStore store.LFSLockStore,
+	lfsSvc *lfsservice.Service,
+	urlProvider url.Provider,
+) *Controller {
+	return &Controller{
+		authorizer:         authorizer,
+		repoStore:          repoStore,
+		principalStore:     principalStore,
+		principalInfoCache: principalInfoCache,
+		lfsStore:           lfsStore,
+		lockStore:          lockStore,
+		lfsSvc:             lfsSvc,
+		urlProvider:        urlProvider,
+	}
+}
+
+func (c *Controller) getRepoCheckAccess(
+	ctx context.Context,
+	session *auth.Session,
+	repoRef string,
+	permission enum.Permission,
+) (*types.Repository, error) {
+	r, err := repo.GetRepoCheckAccess(ctx, c.repoStore, c.authorizer, session, repoRef, permission,
+		[]enum.RepoState{enum.RepoStateActive})
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify repo access: %w", err)
+	}
+
+	// the lfs token grants access to the repository it was issued for only
+	if md, ok := session.Metadata.(*auth.AccessPermissionMetadata); ok &&
+		md.AccessPermissions.Source == jwt.LFSSource {
+		for _, p := range md.AccessPermissions.Permissions {
+			if p.RepoID != r.ID {
+				return nil, apiauth.ErrNotAuthorized
+			}
+		}
+	}
+	return r, nil
+}
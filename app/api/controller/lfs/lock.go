@@ -0,0 +1,253 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package lfs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	apiauth "github.com/easysoft/gitfox/app/api/auth"
+	"github.com/easysoft/gitfox/app/api/usererror"
+	"github.com/easysoft/gitfox/app/auth"
+	gitfox_store "github.com/easysoft/gitfox/store"
+	"github.com/easysoft/gitfox/types"
+	"github.com/easysoft/gitfox/types/enum"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	defaultLockLimit = 100
+	maxLockLimit     = 100
+)
+
+var errLockNotFound = usererror.NotFound("lfs lock not found")
+
+// LockConflictError is returned if the path is locked already.
+type LockConflictError struct {
+	Lock *Lock
+}
+
+func (e *LockConflictError) Error() string {
+	return fmt.Sprintf("path %s is locked already", e.Lock.Path)
+}
+
+// CreateLock locks the path of the repository for the principal.
+func (c *Controller) CreateLock(
+	ctx context.Context,
+	session *auth.Session,
+	repoRef string,
+	in *CreateLockInput,
+) (*Lock, error) {
+	repo, err := c.getRepoCheckAccess(ctx, session, repoRef, enum.PermissionRepoPush)
+	if err != nil {
+		return nil, err
+	}
+	if repo.Mirror {
+		return nil, gitfox_store.ErrReadOnlyMirrorRepo
+	}
+
+	lockPath := strings.TrimPrefix(strings.TrimSpace(in.Path), "/")
+	if lockPath == "" {
+		return nil, usererror.BadRequest("lock path is required")
+	}
+
+	lock := &types.LFSLock{
+		RepoID:    repo.ID,
+		Path:      lockPath,
+		Created:   time.Now().UnixMilli(),
+		CreatedBy: session.Principal.ID,
+	}
+	if in.Ref != nil {
+		lock.Ref = in.Ref.Name
+	}
+
+	err = c.lockStore.Create(ctx, lock)
+	if errors.Is(err, gitfox_store.ErrDuplicate) {
+		locks, err := c.lockStore.List(ctx, repo.ID, &types.LFSLockFilter{Path: lockPath})
+		if err != nil {
+			return nil, err
+		}
+		if len(locks) == 0 {
+			return nil, usererror.Conflict("path is locked already")
+		}
+		return nil, &LockConflictError{Lock: c.mapLock(ctx, locks[0])}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return c.mapLock(ctx, lock), nil
+}
+
+// ListLocks lists the locks of the repository, the locks are paged by the cursor.
+func (c *Controller) ListLocks(
+	ctx context.Context,
+	session *auth.Session,
+	repoRef string,
+	in *ListLocksInput,
+) (*ListLocksResponse, error) {
+	repo, err := c.getRepoCheckAccess(ctx, session, repoRef, enum.PermissionRepoView)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := &types.LFSLockFilter{
+		Path: in.Path,
+		Ref:  in.Ref,
+	}
+	if in.ID != "" {
+		if filter.ID, err = strconv.ParseInt(in.ID, 10, 64); err != nil {
+			return &ListLocksResponse{Locks: []*Lock{}}, nil
+		}
+	}
+
+	locks, next, err := c.listLocks(ctx, repo.ID, filter, in.Cursor, in.Limit)
+	if err != nil {
+		return nil, err
+	}
+	return &ListLocksResponse{
+		Locks:      c.mapLocks(ctx, locks),
+		NextCursor: next,
+	}, nil
+}
+
+// VerifyLocks lists the locks of the repository separated by the owner,
+// the lfs client verifies no file locked by others is pushed.
+func (c *Controller) VerifyLocks(
+	ctx context.Context,
+	session *auth.Session,
+	repoRef string,
+	in *VerifyLocksInput,
+) (*VerifyLocksResponse, error) {
+	repo, err := c.getRepoCheckAccess(ctx, session, repoRef, enum.PermissionRepoPush)
+	if err != nil {
+		return nil, err
+	}
+
+	locks, next, err := c.listLocks(ctx, repo.ID, &types.LFSLockFilter{}, in.Cursor, in.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &VerifyLocksResponse{
+		Ours:       make([]*Lock, 0),
+		Theirs:     make([]*Lock, 0),
+		NextCursor: next,
+	}
+	for _, lock := range locks {
+		if lock.CreatedBy == session.Principal.ID {
+			out.Ours = append(out.Ours, c.mapLock(ctx, lock))
+		} else {
+			out.Theirs = append(out.Theirs, c.mapLock(ctx, lock))
+		}
+	}
+	return out, nil
+}
+
+// Unlock removes the lock, the lock of others is only removed with force by the repository admins.
+func (c *Controller) Unlock(
+	ctx context.Context,
+	session *auth.Session,
+	repoRef string,
+	id string,
+	in *UnlockInput,
+) (*Lock, error) {
+	repo, err := c.getRepoCheckAccess(ctx, session, repoRef, enum.PermissionRepoPush)
+	if err != nil {
+		return nil, err
+	}
+
+	lockID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return nil, errLockNotFound
+	}
+	lock, err := c.lockStore.Find(ctx, repo.ID, lockID)
+	if errors.Is(err, gitfox_store.ErrResourceNotFound) {
+		return nil, errLockNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if lock.CreatedBy != session.Principal.ID {
+		if !in.Force {
+			return nil, usererror.Forbidden("lfs lock is owned by another user")
+		}
+		if err = apiauth.CheckRepo(ctx, c.authorizer, session, repo, enum.PermissionRepoEdit); err != nil {
+			return nil, err
+		}
+		log.Ctx(ctx).Info().Msgf("lfs lock %d of %s is removed by force", lock.ID, lock.Path)
+	}
+
+	err = c.lockStore.Delete(ctx, repo.ID, lock.ID)
+	if errors.Is(err, gitfox_store.ErrResourceNotFound) {
+		return nil, errLockNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return c.mapLock(ctx, lock), nil
+}
+
+// listLocks lists a page of the locks, the next cursor is the id of the last lock if more locks are left.
+func (c *Controller) listLocks(
+	ctx context.Context,
+	repoID int64,
+	filter *types.LFSLockFilter,
+	cursor string,
+	limit int,
+) ([]*types.LFSLock, string, error) {
+	if limit <= 0 || limit > maxLockLimit {
+		limit = defaultLockLimit
+	}
+	if cursor != "" {
+		var err error
+		if filter.Cursor, err = strconv.ParseInt(cursor, 10, 64); err != nil {
+			return nil, "", usererror.BadRequest("invalid cursor")
+		}
+	}
+	filter.Limit = limit + 1
+
+	locks, err := c.lockStore.List(ctx, repoID, filter)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(locks) <= limit {
+		return locks, "", nil
+	}
+	locks = locks[:limit]
+	return locks, strconv.FormatInt(locks[limit-1].ID, 10), nil
+}
+
+func (c *Controller) mapLocks(ctx context.Context, locks []*types.LFSLock) []*Lock {
+	res := make([]*Lock, len(locks))
+	for i := range locks {
+		res[i] = c.mapLock(ctx, locks[i])
+	}
+	return res
+}
+
+func (c *Controller) mapLock(ctx context.Context, lock *types.LFSLock) *Lock {
+	res := &Lock{
+		ID:       strconv.FormatInt(lock.ID, 10),
+		Path:     lock.Path,
+		LockedAt: time.UnixMilli(lock.Created).UTC(),
+	}
+	owner, err := c.principalInfoCache.Get(ctx, lock.CreatedBy)
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msgf("failed to find the owner of lfs lock %d", lock.ID)
+		return res
+	}
+	res.Owner = &LockOwner{Name: owner.DisplayName}
+	return res
+}
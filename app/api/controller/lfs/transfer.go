@@ -0,0 +1,52 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package lfs
+
+import (
+	"context"
+	"io"
+
+	"github.com/easysoft/gitfox/app/auth"
+	"github.com/easysoft/gitfox/git/parser"
+	gitfox_store "github.com/easysoft/gitfox/store"
+	"github.com/easysoft/gitfox/types/enum"
+)
+
+// Upload stores the content of the lfs object, the content is verified against the oid and size.
+func (c *Controller) Upload(
+	ctx context.Context,
+	session *auth.Session,
+	repoRef string,
+	pointer Pointer,
+	content io.Reader,
+) error {
+	repo, err := c.getRepoCheckAccess(ctx, session, repoRef, enum.PermissionRepoPush)
+	if err != nil {
+		return err
+	}
+	if repo.Mirror {
+		return gitfox_store.ErrReadOnlyMirrorRepo
+	}
+
+	return c.lfsSvc.StoreObject(ctx, repo.ID, session.Principal.ID,
+		parser.LFSPointer{OID: pointer.OID, Size: pointer.Size}, content)
+}
+
+// Download returns the content of the lfs object and its size.
+func (c *Controller) Download(
+	ctx context.Context,
+	session *auth.Session,
+	repoRef string,
+	oid string,
+) (io.ReadCloser, int64, error) {
+	repo, err := c.getRepoCheckAccess(ctx, session, repoRef, enum.PermissionRepoView)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return c.lfsSvc.OpenObject(ctx, repo.ID, oid)
+}
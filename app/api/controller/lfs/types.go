@@ -0,0 +1,131 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package lfs
+
+import (
+	"time"
+)
+
+// The types of the git lfs batch and locking api,
+// see https://github.com/git-lfs/git-lfs/blob/main/docs/api/batch.md
+// and https://github.com/git-lfs/git-lfs/blob/main/docs/api/locking.md.
+
+const (
+	OperationDownload = "download"
+	OperationUpload   = "upload"
+
+	TransferBasic  = "basic"
+	HashAlgoSHA256 = "sha256"
+
+	// MediaType is the content type of the lfs api requests and responses.
+	MediaType = "application/vnd.git-lfs+json"
+)
+
+type Reference struct {
+	Name string `json:"name"`
+}
+
+type Pointer struct {
+	OID  string `json:"oid"`
+	Size int64  `json:"size"`
+}
+
+type BatchRequest struct {
+	Operation string     `json:"operation"`
+	Transfers []string   `json:"transfers,omitempty"`
+	Ref       *Reference `json:"ref,omitempty"`
+	Objects   []Pointer  `json:"objects"`
+	HashAlgo  string     `json:"hash_algo,omitempty"`
+}
+
+type BatchResponse struct {
+	Transfer string            `json:"transfer,omitempty"`
+	Objects  []*ObjectResponse `json:"objects"`
+	HashAlgo string            `json:"hash_algo,omitempty"`
+}
+
+type ObjectResponse struct {
+	Pointer
+	Authenticated bool               `json:"authenticated,omitempty"`
+	Actions       map[string]*Action `json:"actions,omitempty"`
+	Error         *ObjectError       `json:"error,omitempty"`
+}
+
+type Action struct {
+	Href      string            `json:"href"`
+	Header    map[string]string `json:"header,omitempty"`
+	ExpiresIn int64             `json:"expires_in,omitempty"`
+}
+
+type ObjectError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type LockOwner struct {
+	Name string `json:"name"`
+}
+
+type Lock struct {
+	ID       string     `json:"id"`
+	Path     string     `json:"path"`
+	LockedAt time.Time  `json:"locked_at"`
+	Owner    *LockOwner `json:"owner,omitempty"`
+}
+
+type CreateLockInput struct {
+	Path string     `json:"path"`
+	Ref  *Reference `json:"ref,omitempty"`
+}
+
+type LockResponse struct {
+	Lock *Lock `json:"lock"`
+}
+
+// LockConflictResponse is returned with 409 if the path is locked already.
+type LockConflictResponse struct {
+	Lock    *Lock  `json:"lock"`
+	Message string `json:"message"`
+}
+
+type ListLocksInput struct {
+	ID     string
+	Path   string
+	Ref    string
+	Cursor string
+	Limit  int
+}
+
+type ListLocksResponse struct {
+	Locks      []*Lock `json:"locks"`
+	NextCursor string  `json:"next_cursor,omitempty"`
+}
+
+type VerifyLocksInput struct {
+	Ref    *Reference `json:"ref,omitempty"`
+	Cursor string     `json:"cursor,omitempty"`
+	Limit  int        `json:"limit,omitempty"`
+}
+
+type VerifyLocksResponse struct {
+	Ours       []*Lock `json:"ours"`
+	Theirs     []*Lock `json:"theirs"`
+	NextCursor string  `json:"next_cursor,omitempty"`
+}
+
+type UnlockInput struct {
+	Force bool       `json:"force,omitempty"`
+	Ref   *Reference `json:"ref,omitempty"`
+}
+
+// AuthenticateResponse is the output of the git-lfs-authenticate ssh command,
+// the lfs client uses the href and header to request the lfs api.
+type AuthenticateResponse struct {
+	Href      string            `json:"href"`
+	Header    map[string]string `json:"header"`
+	ExpiresIn int64             `json:"expires_in"`
+}
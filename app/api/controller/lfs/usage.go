@@ -0,0 +1,28 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package lfs
+
+import (
+	"context"
+
+	"github.com/easysoft/gitfox/app/auth"
+	"github.com/easysoft/gitfox/types"
+	"github.com/easysoft/gitfox/types/enum"
+)
+
+// Usage returns the lfs objects and the size stored in the repository.
+func (c *Controller) Usage(
+	ctx context.Context,
+	session *auth.Session,
+	repoRef string,
+) (*types.LFSUsage, error) {
+	repo, err := c.getRepoCheckAccess(ctx, session, repoRef, enum.PermissionRepoView)
+	if err != nil {
+		return nil, err
+	}
+	return c.lfsStore.Usage(ctx, repo.ID)
+}
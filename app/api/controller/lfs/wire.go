@@ -0,0 +1,35 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package lfs
+
+import (
+	"github.com/easysoft/gitfox/app/auth/authz"
+	lfsservice "github.com/easysoft/gitfox/app/services/lfs"
+	"github.com/easysoft/gitfox/app/store"
+	"github.com/easysoft/gitfox/app/url"
+
+	"github.com/google/wire"
+)
+
+// WireSet provides a wire set for this package.
+var WireSet = wire.NewSet(
+	ProvideController,
+)
+
+func ProvideController(
+	authorizer authz.Authorizer,
+	repoStore store.RepoStore,
+	principalStore store.PrincipalStore,
+	principalInfoCache store.PrincipalInfoCache,
+	lfsStore store.LFSObjectStore,
+	lockStore store.LFSLockStore,
+	lfsSvc *lfsservice.Service,
+	urlProvider url.Provider,
+) *Controller {
+	return NewController(authorizer, repoStore, principalStore, principalInfoCache,
+		lfsStore, lockStore, lfsSvc, urlProvider)
+}
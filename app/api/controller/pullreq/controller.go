@@ -20,6 +20,7 @@ import (
 	"unicode/utf8"
 
 	apiauth "github.com/easysoft/gitfox/app/api/auth"
+	"github.com/easysoft/gitfox/app/api/controller"
 	"github.com/easysoft/gitfox/app/api/usererror"
 	"github.com/easysoft/gitfox/app/auth"
 	"github.com/easysoft/gitfox/app/auth/authz"
@@ -31,6 +32,7 @@ import (
 	locker "github.com/easysoft/gitfox/app/services/locker"
 	"github.com/easysoft/gitfox/app/services/migrate"
 	"github.com/easysoft/gitfox/app/services/protection"
+	"github.com/easysoft/gitfox/app/services/publickey"
 	"github.com/easysoft/gitfox/app/services/pullreq"
 	"github.com/easysoft/gitfox/app/services/usergroup"
 	"github.com/easysoft/gitfox/app/sse"
@@ -78,6 +80,8 @@ type Controller struct {
 	labelSvc               *label.Service
 	instrumentation        instrument.Service
 	userGroupService       usergroup.SearchService
+	signatureVerifier      *publickey.SignatureVerifier
+	serverSigner           *publickey.ServerSigner
 }
 
 func NewController(
@@ -112,6 +116,8 @@ func NewController(
 	labelSvc *label.Service,
 	instrumentation instrument.Service,
 	userGroupService usergroup.SearchService,
+	signatureVerifier *publickey.SignatureVerifier,
+	serverSigner *publickey.ServerSigner,
 ) *Controller {
 	return &Controller{
 		tx:                     tx,
@@ -145,6 +151,8 @@ func NewController(
 		labelSvc:               labelSvc,
 		instrumentation:        instrumentation,
 		userGroupService:       userGroupService,
+		signatureVerifier:      signatureVerifier,
+		serverSigner:           serverSigner,
 	}
 }
 
@@ -174,6 +182,34 @@ func (c *Controller) verifyBranchExistence(ctx context.Context,
 	return ref.SHA, nil
 }
 
+// fetchSourceCommit makes the commit of the source repository available in the target repository,
+// it's required for the pull requests from a fork. No reference of the target repository is updated.
+func (c *Controller) fetchSourceCommit(ctx context.Context,
+	session *auth.Session,
+	sourceRepo, targetRepo *types.Repository,
+	commitSHA sha.SHA,
+) error {
+	if sourceRepo.ID == targetRepo.ID {
+		return nil
+	}
+
+	writeParams, err := controller.CreateRPCInternalWriteParams(ctx, c.urlProvider, session, targetRepo)
+	if err != nil {
+		return fmt.Errorf("failed to create RPC write params: %w", err)
+	}
+
+	err = c.git.FetchObjects(ctx, &git.FetchObjectsParams{
+		WriteParams:   writeParams,
+		SourceRepoUID: sourceRepo.GitUID,
+		ObjectSHAs:    []sha.SHA{commitSHA},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to fetch source commit into the target repository: %w", err)
+	}
+
+	return nil
+}
+
 func (c *Controller) getRepo(ctx context.Context, repoRef string) (*types.Repository, error) {
 	if repoRef == "" {
 		return nil, usererror.BadRequest("A valid repository reference must be provided.")
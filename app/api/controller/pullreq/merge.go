@@ -206,6 +206,11 @@ func (c *Controller) Merge(
 		return nil, nil, fmt.Errorf("CODEOWNERS evaluation failed: %w", err)
 	}
 
+	serverSignedMethods, err := c.serverSignedMethods(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	ruleOut, violations, err := protectionRules.MergeVerify(ctx, protection.MergeVerifyInput{
 		ResolveUserGroupID: c.userGroupService.ListUserIDsByGroupIDs,
 		Actor:              &session.Principal,
@@ -218,6 +223,10 @@ func (c *Controller) Merge(
 		Method:             in.Method, // the method can be empty for dry run or dry run rules
 		CheckResults:       checkResults,
 		CodeOwners:         codeOwnerWithApproval,
+		UnsignedCommits: func(ctx context.Context) ([]string, error) {
+			return c.findUnsignedCommits(ctx, targetRepo, pr)
+		},
+		ServerSignedMethods: serverSignedMethods,
 	})
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to verify protection rules: %w", err)
@@ -639,3 +648,34 @@ func (c *Controller) Merge(
 		RuleViolations: violations,
 	}, nil, nil
 }
+
+// findUnsignedCommits returns the commits of the pull request without a verified signature.
+func (c *Controller) findUnsignedCommits(
+	ctx context.Context,
+	targetRepo *types.Repository,
+	pr *types.PullReq,
+) ([]string, error) {
+	commitSHAs, err := c.git.ListCommitSHAs(ctx, &git.ListCommitsParams{
+		ReadParams: git.CreateReadParams(targetRepo),
+		GitREF:     pr.SourceSHA,
+		After:      pr.MergeBaseSHA,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pull request commits: %w", err)
+	}
+
+	return c.signatureVerifier.FindUnsigned(ctx, targetRepo, nil, commitSHAs)
+}
+
+// serverSignedMethods returns the merge methods which leave only commits signed by the instance on the target
+// branch. The merge method keeps the commits of the source branch and fast-forward creates no commit at all.
+func (c *Controller) serverSignedMethods(ctx context.Context) ([]enum.MergeMethod, error) {
+	enabled, err := c.serverSigner.Enabled(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check signing key of the instance: %w", err)
+	}
+	if !enabled {
+		return nil, nil
+	}
+	return []enum.MergeMethod{enum.MergeMethodSquash, enum.MergeMethodRebase}, nil
+}
@@ -20,6 +20,7 @@ import (
 	"strings"
 	"time"
 
+	apiauth "github.com/easysoft/gitfox/app/api/auth"
 	"github.com/easysoft/gitfox/app/api/usererror"
 	"github.com/easysoft/gitfox/app/auth"
 	pullreqevents "github.com/easysoft/gitfox/app/events/pullreq"
@@ -70,7 +71,7 @@ func (c *Controller) Create(
 		return nil, err
 	}
 
-	targetRepo, err := c.getRepoCheckAccess(ctx, session, repoRef, enum.PermissionRepoPush)
+	targetRepo, err := c.getRepoCheckAccess(ctx, session, repoRef, enum.PermissionRepoView)
 	if err != nil {
 		return nil, fmt.Errorf("failed to acquire access to target repo: %w", err)
 	}
@@ -90,6 +91,17 @@ func (c *Controller) Create(
 		}
 	}
 
+	// a pull request within the repository requires push access,
+	// a pull request from a fork requires push access to the fork only.
+	if sourceRepo.ID == targetRepo.ID {
+		err = apiauth.CheckRepo(ctx, c.authorizer, session, targetRepo, enum.PermissionRepoPush)
+		if err != nil {
+			return nil, fmt.Errorf("failed to acquire access to target repo: %w", err)
+		}
+	} else if sourceRepo.ForkID != targetRepo.ID {
+		return nil, usererror.BadRequest("Pull requests can only be opened from a fork into its upstream repository.")
+	}
+
 	if sourceRepo.ID == targetRepo.ID && in.TargetBranch == in.SourceBranch {
 		return nil, usererror.BadRequest("target and source branch can't be the same")
 	}
@@ -108,9 +120,13 @@ func (c *Controller) Create(
 		return nil, err
 	}
 
+	if err = c.fetchSourceCommit(ctx, session, sourceRepo, targetRepo, sourceSHA); err != nil {
+		return nil, err
+	}
+
 	mergeBaseResult, err := c.git.MergeBase(ctx, git.MergeBaseParams{
-		ReadParams: git.ReadParams{RepoUID: sourceRepo.GitUID},
-		Ref1:       in.SourceBranch,
+		ReadParams: git.ReadParams{RepoUID: targetRepo.GitUID},
+		Ref1:       sourceSHA.String(),
 		Ref2:       in.TargetBranch,
 	})
 	if err != nil {
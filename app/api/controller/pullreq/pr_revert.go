@@ -0,0 +1,180 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package pullreq
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/easysoft/gitfox/app/api/controller"
+	"github.com/easysoft/gitfox/app/api/usererror"
+	"github.com/easysoft/gitfox/app/auth"
+	"github.com/easysoft/gitfox/app/services/protection"
+	"github.com/easysoft/gitfox/git"
+	"github.com/easysoft/gitfox/git/sha"
+	"github.com/easysoft/gitfox/types"
+	"github.com/easysoft/gitfox/types/enum"
+
+	"github.com/rs/zerolog/log"
+)
+
+// RevertInput holds the options for reverting a merged pull request.
+type RevertInput struct {
+	// RevertBranch is the branch created with the revert commit (optional, default: revert-<number>-<source branch>).
+	RevertBranch string `json:"revert_branch"`
+
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	IsDraft     bool   `json:"is_draft"`
+
+	DryRunRules bool `json:"dry_run_rules"`
+	BypassRules bool `json:"bypass_rules"`
+}
+
+func (in *RevertInput) sanitize(pr *types.PullReq) error {
+	in.RevertBranch = strings.TrimSpace(in.RevertBranch)
+	in.Title = strings.TrimSpace(in.Title)
+	in.Description = strings.TrimSpace(in.Description)
+
+	if in.RevertBranch == "" {
+		in.RevertBranch = fmt.Sprintf("revert-%d-%s", pr.Number, pr.SourceBranch)
+	}
+	if in.Title == "" {
+		in.Title = fmt.Sprintf("Revert %q", pr.Title)
+	}
+	if in.Description == "" {
+		in.Description = fmt.Sprintf("Reverts #%d", pr.Number)
+	}
+
+	if err := validateTitle(in.Title); err != nil {
+		return err
+	}
+
+	return validateDescription(in.Description)
+}
+
+// RevertOutput holds the pull request opened to revert a merged pull request.
+type RevertOutput struct {
+	PullReq *types.PullReq `json:"pull_request,omitempty"`
+	types.DryRunRulesOutput
+}
+
+// Revert creates a branch with a commit reverting all changes of the merged pull request
+// and opens a new pull request from the branch to the target branch of the merged pull request.
+func (c *Controller) Revert(ctx context.Context,
+	session *auth.Session,
+	repoRef string,
+	pullreqNum int64,
+	in *RevertInput,
+) (RevertOutput, []types.RuleViolations, error) {
+	repo, err := c.getRepoCheckAccess(ctx, session, repoRef, enum.PermissionRepoPush)
+	if err != nil {
+		return RevertOutput{}, nil, fmt.Errorf("failed to acquire access to repo: %w", err)
+	}
+
+	pr, err := c.pullreqStore.FindByNumber(ctx, repo.ID, pullreqNum)
+	if err != nil {
+		return RevertOutput{}, nil, fmt.Errorf("failed to get pull request by number: %w", err)
+	}
+	if pr.Merged == nil || pr.MergeSHA == nil || pr.MergeTargetSHA == nil {
+		return RevertOutput{}, nil, usererror.BadRequest("Only merged pull requests can be reverted.")
+	}
+
+	mergeSHA, err := sha.New(*pr.MergeSHA)
+	if err != nil {
+		return RevertOutput{}, nil, fmt.Errorf("failed to parse merge commit SHA: %w", err)
+	}
+	mergeTargetSHA, err := sha.New(*pr.MergeTargetSHA)
+	if err != nil {
+		return RevertOutput{}, nil, fmt.Errorf("failed to parse merge target SHA: %w", err)
+	}
+
+	if err = in.sanitize(pr); err != nil {
+		return RevertOutput{}, nil, err
+	}
+
+	rules, isRepoOwner, err := c.fetchRules(ctx, session, repo)
+	if err != nil {
+		return RevertOutput{}, nil, fmt.Errorf("failed to fetch rules: %w", err)
+	}
+	violations, err := rules.RefChangeVerify(ctx, protection.RefChangeVerifyInput{
+		ResolveUserGroupID: c.userGroupService.ListUserIDsByGroupIDs,
+		Actor:              &session.Principal,
+		AllowBypass:        in.BypassRules,
+		IsRepoOwner:        isRepoOwner,
+		Repo:               repo,
+		RefAction:          protection.RefActionCreate,
+		RefType:            protection.RefTypeBranch,
+		RefNames:           []string{in.RevertBranch},
+	})
+	if err != nil {
+		return RevertOutput{}, nil, fmt.Errorf("failed to verify protection rules: %w", err)
+	}
+
+	if in.DryRunRules {
+		return RevertOutput{
+			DryRunRulesOutput: types.DryRunRulesOutput{
+				DryRunRules:    true,
+				RuleViolations: violations,
+			},
+		}, nil, nil
+	}
+
+	if protection.IsCritical(violations) {
+		return RevertOutput{}, violations, nil
+	}
+
+	writeParams, err := controller.CreateRPCInternalWriteParams(ctx, c.urlProvider, session, repo)
+	if err != nil {
+		return RevertOutput{}, nil, fmt.Errorf("failed to create RPC write params: %w", err)
+	}
+
+	// all changes of the pull request are reverted as a single commit regardless of the merge method,
+	// the merge target is the parent of the changes introduced by the merge.
+	now := time.Now()
+	_, err = c.git.Revert(ctx, &git.CherryPickParams{
+		WriteParams: writeParams,
+		CommitSHAs:  []sha.SHA{mergeSHA},
+		ParentSHA:   mergeTargetSHA,
+		Branch:      pr.TargetBranch,
+		NewBranch:   in.RevertBranch,
+		Message: fmt.Sprintf("Revert %q (#%d)\n\nThis reverts pull request #%d, merged as commit %s.",
+			pr.Title, pr.Number, pr.Number, mergeSHA),
+		Committer:     controller.SystemServicePrincipalInfo(),
+		CommitterDate: &now,
+		Author:        controller.IdentityFromPrincipalInfo(*session.Principal.ToPrincipalInfo()),
+	})
+	if err != nil {
+		return RevertOutput{}, nil, err
+	}
+
+	revertPR, err := c.Create(ctx, session, repoRef, &CreateInput{
+		IsDraft:      in.IsDraft,
+		Title:        in.Title,
+		Description:  in.Description,
+		SourceBranch: in.RevertBranch,
+		TargetBranch: pr.TargetBranch,
+	})
+	if err != nil {
+		if errDelete := c.git.DeleteBranch(ctx, &git.DeleteBranchParams{
+			WriteParams: writeParams,
+			BranchName:  in.RevertBranch,
+		}); errDelete != nil {
+			log.Ctx(ctx).Warn().Err(errDelete).Msgf("failed to delete revert branch %q", in.RevertBranch)
+		}
+		return RevertOutput{}, nil, fmt.Errorf("failed to create revert pull request: %w", err)
+	}
+
+	return RevertOutput{
+		PullReq: revertPR,
+		DryRunRulesOutput: types.DryRunRulesOutput{
+			RuleViolations: violations,
+		},
+	}, nil, nil
+}
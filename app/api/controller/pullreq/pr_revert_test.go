@@ -0,0 +1,62 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package pullreq
+
+import (
+	"testing"
+
+	"github.com/easysoft/gitfox/types"
+)
+
+func TestRevertInputSanitize(t *testing.T) {
+	pr := &types.PullReq{
+		Number:       7,
+		Title:        "Add feature",
+		SourceBranch: "feature/x",
+	}
+
+	tests := []struct {
+		name  string
+		input RevertInput
+		want  RevertInput
+	}{
+		{
+			name:  "defaults",
+			input: RevertInput{},
+			want: RevertInput{
+				RevertBranch: "revert-7-feature/x",
+				Title:        `Revert "Add feature"`,
+				Description:  "Reverts #7",
+			},
+		},
+		{
+			name: "provided",
+			input: RevertInput{
+				RevertBranch: " revert-feature ",
+				Title:        " Undo feature ",
+				Description:  "Broke the build.",
+			},
+			want: RevertInput{
+				RevertBranch: "revert-feature",
+				Title:        "Undo feature",
+				Description:  "Broke the build.",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			in := test.input
+			if err := in.sanitize(pr); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if in != test.want {
+				t.Errorf("got %+v, want %+v", in, test.want)
+			}
+		})
+	}
+}
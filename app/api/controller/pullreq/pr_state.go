@@ -121,9 +121,13 @@ func (c *Controller) State(ctx context.Context,
 			return nil, err
 		}
 
+		if err = c.fetchSourceCommit(ctx, session, sourceRepo, targetRepo, sourceSHA); err != nil {
+			return nil, err
+		}
+
 		mergeBaseResult, err := c.git.MergeBase(ctx, git.MergeBaseParams{
-			ReadParams: git.ReadParams{RepoUID: sourceRepo.GitUID},
-			Ref1:       pr.SourceBranch,
+			ReadParams: git.ReadParams{RepoUID: targetRepo.GitUID},
+			Ref1:       sourceSHA.String(),
 			Ref2:       pr.TargetBranch,
 		})
 		if err != nil {
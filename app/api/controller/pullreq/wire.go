@@ -24,6 +24,7 @@ import (
 	"github.com/easysoft/gitfox/app/services/locker"
 	"github.com/easysoft/gitfox/app/services/migrate"
 	"github.com/easysoft/gitfox/app/services/protection"
+	"github.com/easysoft/gitfox/app/services/publickey"
 	"github.com/easysoft/gitfox/app/services/pullreq"
 	"github.com/easysoft/gitfox/app/services/usergroup"
 	"github.com/easysoft/gitfox/app/sse"
@@ -65,6 +66,8 @@ func ProvideController(
 	labelSvc *label.Service,
 	instrumentation instrument.Service,
 	userGroupService usergroup.SearchService,
+	signatureVerifier *publickey.SignatureVerifier,
+	serverSigner *publickey.ServerSigner,
 ) *Controller {
 	return NewController(tx,
 		urlProvider,
@@ -97,5 +100,7 @@ func ProvideController(
 		labelSvc,
 		instrumentation,
 		userGroupService,
+		signatureVerifier,
+		serverSigner,
 	)
 }
@@ -0,0 +1,256 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/easysoft/gitfox/app/api/controller"
+	"github.com/easysoft/gitfox/app/api/controller/pullreq"
+	"github.com/easysoft/gitfox/app/api/usererror"
+	"github.com/easysoft/gitfox/app/auth"
+	"github.com/easysoft/gitfox/app/bootstrap"
+	"github.com/easysoft/gitfox/app/paths"
+	"github.com/easysoft/gitfox/app/services/protection"
+	"github.com/easysoft/gitfox/audit"
+	"github.com/easysoft/gitfox/git"
+	"github.com/easysoft/gitfox/git/sha"
+	"github.com/easysoft/gitfox/store"
+	"github.com/easysoft/gitfox/types"
+	"github.com/easysoft/gitfox/types/enum"
+
+	"github.com/rs/zerolog/log"
+)
+
+// CherryPickInput holds the commits to cherry-pick or revert.
+type CherryPickInput struct {
+	CommitSHAs []string `json:"commit_shas"`
+
+	// Branch is the branch the commits are applied to, the default branch is used if not provided.
+	Branch string `json:"branch"`
+	// NewBranch is created with the commits rather than updating the Branch,
+	// it's required for opening a pull request.
+	NewBranch string `json:"new_branch"`
+
+	// Message commits all changes as a single commit with the message, if provided.
+	Message string `json:"message"`
+
+	// OpenPullReq opens a pull request from the NewBranch to the Branch.
+	OpenPullReq bool   `json:"open_pull_request"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+
+	DryRunRules bool `json:"dry_run_rules"`
+	BypassRules bool `json:"bypass_rules"`
+}
+
+func (in *CherryPickInput) sanitize() ([]sha.SHA, error) {
+	in.Branch = strings.TrimSpace(in.Branch)
+	in.NewBranch = strings.TrimSpace(in.NewBranch)
+	in.Message = strings.TrimSpace(in.Message)
+	in.Title = strings.TrimSpace(in.Title)
+	in.Description = strings.TrimSpace(in.Description)
+
+	if len(in.CommitSHAs) == 0 {
+		return nil, usererror.BadRequest("At least one commit is required.")
+	}
+
+	if in.OpenPullReq && (in.NewBranch == "" || in.NewBranch == in.Branch) {
+		return nil, usererror.BadRequest("A new branch is required for opening a pull request.")
+	}
+
+	commitSHAs := make([]sha.SHA, len(in.CommitSHAs))
+	for i, s := range in.CommitSHAs {
+		commitSHA, err := sha.New(s)
+		if err != nil {
+			return nil, usererror.BadRequestf("Invalid commit SHA %q.", s)
+		}
+		commitSHAs[i] = commitSHA
+	}
+
+	return commitSHAs, nil
+}
+
+// CherryPick applies the changes of the commits to the branch,
+// a pull request is opened for the changes if requested.
+func (c *Controller) CherryPick(ctx context.Context,
+	session *auth.Session,
+	repoRef string,
+	in *CherryPickInput,
+) (types.CherryPickResponse, []types.RuleViolations, error) {
+	return c.applyCommits(ctx, session, repoRef, in, c.git.CherryPick)
+}
+
+// Revert applies the inverse of the changes of the commits to the branch,
+// a pull request is opened for the changes if requested.
+func (c *Controller) Revert(ctx context.Context,
+	session *auth.Session,
+	repoRef string,
+	in *CherryPickInput,
+) (types.CherryPickResponse, []types.RuleViolations, error) {
+	return c.applyCommits(ctx, session, repoRef, in, c.git.Revert)
+}
+
+func (c *Controller) applyCommits(ctx context.Context,
+	session *auth.Session,
+	repoRef string,
+	in *CherryPickInput,
+	apply func(ctx context.Context, params *git.CherryPickParams) (git.CherryPickOutput, error),
+) (types.CherryPickResponse, []types.RuleViolations, error) {
+	repo, err := c.getRepoCheckAccess(ctx, session, repoRef, enum.PermissionRepoPush)
+	if err != nil {
+		return types.CherryPickResponse{}, nil, err
+	}
+
+	if repo.Mirror {
+		return types.CherryPickResponse{}, nil, store.ErrReadOnlyMirrorRepo
+	}
+
+	commitSHAs, err := in.sanitize()
+	if err != nil {
+		return types.CherryPickResponse{}, nil, err
+	}
+
+	if in.Branch == "" {
+		in.Branch = repo.DefaultBranch
+	}
+
+	rules, isRepoOwner, err := c.fetchRules(ctx, session, repo)
+	if err != nil {
+		return types.CherryPickResponse{}, nil, err
+	}
+
+	refAction := protection.RefActionUpdate
+	branchName := in.Branch
+	if in.NewBranch != "" && in.NewBranch != in.Branch {
+		refAction = protection.RefActionCreate
+		branchName = in.NewBranch
+	}
+
+	violations, err := rules.RefChangeVerify(ctx, protection.RefChangeVerifyInput{
+		ResolveUserGroupID: c.userGroupService.ListUserIDsByGroupIDs,
+		Actor:              &session.Principal,
+		AllowBypass:        in.BypassRules,
+		IsRepoOwner:        isRepoOwner,
+		Repo:               repo,
+		RefAction:          refAction,
+		RefType:            protection.RefTypeBranch,
+		RefNames:           []string{branchName},
+	})
+	if err != nil {
+		return types.CherryPickResponse{}, nil, fmt.Errorf("failed to verify protection rules: %w", err)
+	}
+
+	if in.DryRunRules {
+		return types.CherryPickResponse{
+			DryRunRulesOutput: types.DryRunRulesOutput{
+				DryRunRules:    true,
+				RuleViolations: violations,
+			},
+		}, nil, nil
+	}
+
+	if protection.IsCritical(violations) {
+		return types.CherryPickResponse{}, violations, nil
+	}
+
+	// Create internal write params. Note: This will skip the pre-commit protection rules check.
+	writeParams, err := controller.CreateRPCInternalWriteParams(ctx, c.urlProvider, session, repo)
+	if err != nil {
+		return types.CherryPickResponse{}, nil, fmt.Errorf("failed to create RPC write params: %w", err)
+	}
+
+	now := time.Now()
+	out, err := apply(ctx, &git.CherryPickParams{
+		WriteParams:   writeParams,
+		CommitSHAs:    commitSHAs,
+		Branch:        in.Branch,
+		NewBranch:     in.NewBranch,
+		Message:       in.Message,
+		Committer:     identityFromPrincipal(bootstrap.NewSystemServiceSession().Principal),
+		CommitterDate: &now,
+		Author:        identityFromPrincipal(session.Principal),
+	})
+	if err != nil {
+		return types.CherryPickResponse{}, nil, err
+	}
+
+	if protection.IsBypassed(violations) {
+		err = c.auditService.Log(ctx,
+			session.Principal,
+			audit.NewResource(
+				audit.ResourceTypeRepository,
+				repo.Identifier,
+				audit.RepoPath,
+				repo.Path,
+				audit.BypassAction,
+				audit.BypassActionCommitted,
+				audit.BypassedResourceType,
+				audit.BypassedResourceTypeCommit,
+				audit.BypassedResourceName,
+				out.CommitSHA.String(),
+				audit.ResourceName,
+				fmt.Sprintf(
+					audit.BypassSHALabelFormat,
+					repo.Identifier,
+					out.CommitSHA.String()[0:6],
+				),
+			),
+			audit.ActionBypassed,
+			paths.Parent(repo.Path),
+			audit.WithNewObject(audit.CommitObject{
+				CommitSHA:      out.CommitSHA.String(),
+				RepoPath:       repo.Path,
+				RuleViolations: violations,
+			}),
+		)
+		if err != nil {
+			log.Ctx(ctx).Warn().Msgf("failed to insert audit log for cherry-pick operation: %s", err)
+		}
+	}
+
+	response := types.CherryPickResponse{
+		CommitID: out.CommitSHA.String(),
+		Branch:   out.Branch,
+		DryRunRulesOutput: types.DryRunRulesOutput{
+			RuleViolations: violations,
+		},
+	}
+
+	if !in.OpenPullReq {
+		return response, nil, nil
+	}
+
+	title := in.Title
+	if title == "" {
+		title, _, _ = strings.Cut(in.Message, "\n")
+	}
+	if title == "" {
+		title = out.Branch
+	}
+
+	response.PullReq, err = c.pullreqCtrl.Create(ctx, session, repoRef, &pullreq.CreateInput{
+		Title:        title,
+		Description:  in.Description,
+		SourceBranch: out.Branch,
+		TargetBranch: in.Branch,
+	})
+	if err != nil {
+		if errDelete := c.git.DeleteBranch(ctx, &git.DeleteBranchParams{
+			WriteParams: writeParams,
+			BranchName:  out.Branch,
+		}); errDelete != nil {
+			log.Ctx(ctx).Warn().Err(errDelete).Msgf("failed to delete branch %q", out.Branch)
+		}
+		return types.CherryPickResponse{}, nil, fmt.Errorf("failed to create pull request: %w", err)
+	}
+
+	return response, nil, nil
+}
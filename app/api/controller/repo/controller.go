@@ -23,18 +23,23 @@ import (
 
 	apiauth "github.com/easysoft/gitfox/app/api/auth"
 	"github.com/easysoft/gitfox/app/api/controller/limiter"
+	"github.com/easysoft/gitfox/app/api/controller/pullreq"
 	"github.com/easysoft/gitfox/app/api/usererror"
 	"github.com/easysoft/gitfox/app/auth"
 	"github.com/easysoft/gitfox/app/auth/authz"
 	repoevents "github.com/easysoft/gitfox/app/events/repo"
 	"github.com/easysoft/gitfox/app/services/codeowners"
+	"github.com/easysoft/gitfox/app/services/housekeeping"
 	"github.com/easysoft/gitfox/app/services/importer"
 	"github.com/easysoft/gitfox/app/services/instrument"
 	"github.com/easysoft/gitfox/app/services/keywordsearch"
 	"github.com/easysoft/gitfox/app/services/label"
 	"github.com/easysoft/gitfox/app/services/locker"
+	"github.com/easysoft/gitfox/app/services/mirror"
 	"github.com/easysoft/gitfox/app/services/protection"
 	"github.com/easysoft/gitfox/app/services/publicaccess"
+	"github.com/easysoft/gitfox/app/services/publickey"
+	"github.com/easysoft/gitfox/app/services/pushmirror"
 	"github.com/easysoft/gitfox/app/services/settings"
 	"github.com/easysoft/gitfox/app/services/usergroup"
 	"github.com/easysoft/gitfox/app/store"
@@ -103,6 +108,12 @@ type Controller struct {
 	publicAccess       publicaccess.Service
 	labelSvc           *label.Service
 	instrumentation    instrument.Service
+	mirrorSvc          *mirror.Service
+	pushMirrorStore    store.PushMirrorStore
+	pushMirrorSvc      *pushmirror.Service
+	housekeepingSvc    *housekeeping.Service
+	signatureVerifier  *publickey.SignatureVerifier
+	pullreqCtrl        *pullreq.Controller
 }
 
 func NewController(
@@ -138,6 +149,12 @@ func NewController(
 	instrumentation instrument.Service,
 	userGroupStore store.UserGroupStore,
 	userGroupService usergroup.SearchService,
+	mirrorSvc *mirror.Service,
+	pushMirrorStore store.PushMirrorStore,
+	pushMirrorSvc *pushmirror.Service,
+	housekeepingSvc *housekeeping.Service,
+	signatureVerifier *publickey.SignatureVerifier,
+	pullreqCtrl *pullreq.Controller,
 ) *Controller {
 	return &Controller{
 		defaultBranch:      config.Git.DefaultBranch,
@@ -172,6 +189,12 @@ func NewController(
 		instrumentation:    instrumentation,
 		userGroupStore:     userGroupStore,
 		userGroupService:   userGroupService,
+		mirrorSvc:          mirrorSvc,
+		pushMirrorStore:    pushMirrorStore,
+		pushMirrorSvc:      pushMirrorSvc,
+		housekeepingSvc:    housekeepingSvc,
+		signatureVerifier:  signatureVerifier,
+		pullreqCtrl:        pullreqCtrl,
 	}
 }
 
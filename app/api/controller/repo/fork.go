@@ -0,0 +1,512 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	apiauth "github.com/easysoft/gitfox/app/api/auth"
+	"github.com/easysoft/gitfox/app/api/controller"
+	"github.com/easysoft/gitfox/app/api/controller/limiter"
+	"github.com/easysoft/gitfox/app/api/usererror"
+	"github.com/easysoft/gitfox/app/auth"
+	"github.com/easysoft/gitfox/app/githook"
+	"github.com/easysoft/gitfox/app/paths"
+	"github.com/easysoft/gitfox/app/services/instrument"
+	"github.com/easysoft/gitfox/app/services/protection"
+	"github.com/easysoft/gitfox/audit"
+	"github.com/easysoft/gitfox/errors"
+	"github.com/easysoft/gitfox/git"
+	gitenum "github.com/easysoft/gitfox/git/enum"
+	"github.com/easysoft/gitfox/git/sha"
+	"github.com/easysoft/gitfox/store"
+	"github.com/easysoft/gitfox/types"
+	"github.com/easysoft/gitfox/types/check"
+	"github.com/easysoft/gitfox/types/enum"
+
+	"github.com/rs/zerolog/log"
+)
+
+var errRepositoryNotFork = usererror.BadRequest("Repository is not a fork.")
+
+type ForkInput struct {
+	ParentRef   string `json:"parent_ref"`
+	Identifier  string `json:"identifier"`
+	Description string `json:"description"`
+	IsPublic    bool   `json:"is_public"`
+}
+
+func (c *Controller) sanitizeForkInput(in *ForkInput, upstream *types.Repository) error {
+	if err := ValidateParentRef(in.ParentRef); err != nil {
+		return err
+	}
+
+	if in.Identifier == "" {
+		in.Identifier = upstream.Identifier
+	}
+	if err := c.identifierCheck(in.Identifier); err != nil {
+		return err
+	}
+
+	in.Description = strings.TrimSpace(in.Description)
+	if in.Description == "" {
+		in.Description = upstream.Description
+	}
+
+	return check.Description(in.Description)
+}
+
+// Fork creates a fork of the repository in the space. The git objects are shared with the upstream
+// repository, only the branches and tags are copied.
+//
+//nolint:gocognit
+func (c *Controller) Fork(
+	ctx context.Context,
+	session *auth.Session,
+	repoRef string,
+	in *ForkInput,
+) (*RepositoryOutput, error) {
+	upstream, err := c.getRepoCheckAccess(ctx, session, repoRef, enum.PermissionRepoView)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = c.sanitizeForkInput(in, upstream); err != nil {
+		return nil, fmt.Errorf("failed to sanitize input: %w", err)
+	}
+
+	parentSpace, err := c.getSpaceCheckAuthRepoCreation(ctx, session, in.ParentRef)
+	if err != nil {
+		return nil, err
+	}
+
+	isPublicAccessSupported, err := c.publicAccess.IsPublicAccessSupported(ctx, parentSpace.Path)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to check if public access is supported for parent space %q: %w",
+			parentSpace.Path,
+			err,
+		)
+	}
+	if in.IsPublic && !isPublicAccessSupported {
+		return nil, errPublicRepoCreationDisabled
+	}
+
+	err = c.repoCheck.Create(ctx, session, &CreateInput{
+		ParentRef:     in.ParentRef,
+		Identifier:    in.Identifier,
+		DefaultBranch: upstream.DefaultBranch,
+		Description:   in.Description,
+		IsPublic:      in.IsPublic,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	gitUID, err := c.forkGitRepository(ctx, session, upstream)
+	if err != nil {
+		return nil, fmt.Errorf("error forking repository on git: %w", err)
+	}
+
+	var repo *types.Repository
+	err = c.tx.WithTx(ctx, func(ctx context.Context) error {
+		if err := c.resourceLimiter.RepoCount(ctx, parentSpace.ID, 1); err != nil {
+			return fmt.Errorf("resource limit exceeded: %w", limiter.ErrMaxNumReposReached)
+		}
+
+		// lock the space for update during repo creation to prevent racing conditions with space soft delete.
+		parentSpace, err = c.spaceStore.FindForUpdate(ctx, parentSpace.ID)
+		if err != nil {
+			return fmt.Errorf("failed to find the parent space: %w", err)
+		}
+
+		now := time.Now().UnixMilli()
+		repo = &types.Repository{
+			Version:       0,
+			ParentID:      parentSpace.ID,
+			Identifier:    in.Identifier,
+			GitUID:        gitUID,
+			Description:   in.Description,
+			CreatedBy:     session.Principal.ID,
+			Created:       now,
+			Updated:       now,
+			ForkID:        upstream.ID,
+			DefaultBranch: upstream.DefaultBranch,
+			IsEmpty:       upstream.IsEmpty,
+		}
+
+		if err := c.repoStore.Create(ctx, repo); err != nil {
+			return err
+		}
+
+		_, err = c.repoStore.UpdateOptLock(ctx, upstream, func(r *types.Repository) error {
+			r.NumForks++
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update number of forks of the upstream repo: %w", err)
+		}
+
+		return nil
+	}, sql.TxOptions{Isolation: sql.LevelSerializable})
+	if err != nil {
+		// best effort cleanup
+		if dErr := c.DeleteGitRepository(ctx, session, gitUID); dErr != nil {
+			log.Ctx(ctx).Warn().Err(dErr).Msg("failed to delete forked repo for cleanup")
+		}
+		return nil, err
+	}
+
+	err = c.publicAccess.Set(ctx, enum.PublicResourceTypeRepo, repo.Path, in.IsPublic)
+	if err != nil {
+		if dErr := c.publicAccess.Delete(ctx, enum.PublicResourceTypeRepo, repo.Path); dErr != nil {
+			return nil, fmt.Errorf("failed to set repo public access (and public access cleanup: %w): %w", dErr, err)
+		}
+
+		// only cleanup repo itself if cleanup of public access succeeded (to avoid leaking public access)
+		if dErr := c.PurgeNoAuth(ctx, session, repo); dErr != nil {
+			return nil, fmt.Errorf("failed to set repo public access (and repo purge: %w): %w", dErr, err)
+		}
+
+		return nil, fmt.Errorf("failed to set repo public access (succesfull cleanup): %w", err)
+	}
+
+	// backfil GitURL
+	repo.GitURL = c.urlProvider.GenerateGITCloneURL(ctx, repo.Path)
+	repo.GitSSHURL = c.urlProvider.GenerateGITCloneSSHURL(ctx, repo.Path)
+
+	repoOutput := GetRepoOutputWithAccess(ctx, in.IsPublic, repo)
+
+	err = c.auditService.Log(ctx,
+		session.Principal,
+		audit.NewResource(audit.ResourceTypeRepository, repo.Identifier),
+		audit.ActionCreated,
+		paths.Parent(repo.Path),
+		audit.WithNewObject(audit.RepositoryObject{
+			Repository: repoOutput.Repository,
+			IsPublic:   repoOutput.IsPublic,
+		}),
+	)
+	if err != nil {
+		log.Ctx(ctx).Warn().Msgf("failed to insert audit log for fork repository operation: %s", err)
+	}
+	err = c.instrumentation.Track(ctx, instrument.Event{
+		Type:      instrument.EventTypeRepositoryCreate,
+		Principal: session.Principal.ToPrincipalInfo(),
+		Path:      repo.Path,
+		Properties: map[instrument.Property]any{
+			instrument.PropertyRepositoryID:           repo.ID,
+			instrument.PropertyRepositoryName:         repo.Identifier,
+			instrument.PropertyRepositoryCreationType: instrument.CreationTypeFork,
+		},
+	})
+	if err != nil {
+		log.Ctx(ctx).Warn().Msgf("failed to insert instrumentation record for fork repository operation: %s", err)
+	}
+
+	if !repo.IsEmpty {
+		err = c.indexer.Index(ctx, repo)
+		if err != nil {
+			log.Ctx(ctx).Warn().Err(err).Int64("repo_id", repo.ID).Msg("failed to index repo")
+		}
+	}
+
+	return repoOutput, nil
+}
+
+func (c *Controller) forkGitRepository(
+	ctx context.Context,
+	session *auth.Session,
+	upstream *types.Repository,
+) (string, error) {
+	gitUID, err := git.NewRepositoryUID()
+	if err != nil {
+		return "", fmt.Errorf("failed to create new uid: %w", err)
+	}
+
+	// generate envars (add everything githook CLI needs for execution)
+	envVars, err := githook.GenerateEnvironmentVariables(
+		ctx,
+		c.urlProvider.GetInternalAPIURL(ctx),
+		0,
+		session.Principal.ID,
+		true,
+		true,
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate git hook environment variables: %w", err)
+	}
+
+	err = c.git.ForkRepository(ctx, &git.ForkRepositoryParams{
+		WriteParams: git.WriteParams{
+			RepoUID: gitUID,
+			Actor:   *identityFromPrincipal(session.Principal),
+			EnvVars: envVars,
+		},
+		UpstreamRepoUID: upstream.GitUID,
+		DefaultBranch:   upstream.DefaultBranch,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return gitUID, nil
+}
+
+// ListForks lists the forks of the repository the principal has access to.
+func (c *Controller) ListForks(
+	ctx context.Context,
+	session *auth.Session,
+	repoRef string,
+	filter *types.RepoFilter,
+) ([]*types.Repository, int64, error) {
+	repo, err := c.getRepoCheckAccess(ctx, session, repoRef, enum.PermissionRepoView)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// the forks are spread over the spaces, they are filtered by the access before paginating.
+	forks, err := c.repoStore.ListForks(ctx, repo.ID, &types.RepoFilter{
+		Page:  1,
+		Size:  int(math.MaxInt),
+		Query: filter.Query,
+		Sort:  filter.Sort,
+		Order: filter.Order,
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list forks: %w", err)
+	}
+
+	visible := make([]*types.Repository, 0, len(forks))
+	for _, fork := range forks {
+		err = apiauth.CheckRepo(ctx, c.authorizer, session, fork, enum.PermissionRepoView)
+		if errors.Is(err, apiauth.ErrNotAuthorized) {
+			continue
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+		visible = append(visible, fork)
+	}
+
+	count := int64(len(visible))
+	start := min((max(filter.Page, 1)-1)*filter.Size, len(visible))
+	end := min(start+filter.Size, len(visible))
+	visible = visible[start:end]
+
+	// backfill URLs
+	for _, fork := range visible {
+		fork.GitURL = c.urlProvider.GenerateGITCloneURL(ctx, fork.Path)
+	}
+
+	return visible, count, nil
+}
+
+type SyncForkInput struct {
+	// Branch is synced with the same branch of the upstream repository (optional, default: default branch).
+	Branch string `json:"branch"`
+
+	DryRunRules bool `json:"dry_run_rules"`
+	BypassRules bool `json:"bypass_rules"`
+}
+
+type SyncForkOutput struct {
+	Branch  string  `json:"branch"`
+	OldSHA  sha.SHA `json:"old_sha"`
+	NewSHA  sha.SHA `json:"new_sha"`
+	Updated bool    `json:"updated"`
+	types.DryRunRulesOutput
+}
+
+// SyncFork fast-forwards the branch of the fork to the same branch of the upstream repository.
+// The branch is created if it doesn't exist in the fork, a branch diverged from the upstream isn't updated.
+func (c *Controller) SyncFork(
+	ctx context.Context,
+	session *auth.Session,
+	repoRef string,
+	in *SyncForkInput,
+) (*SyncForkOutput, []types.RuleViolations, error) {
+	repo, err := c.getRepoCheckAccess(ctx, session, repoRef, enum.PermissionRepoPush)
+	if err != nil {
+		return nil, nil, err
+	}
+	if repo.ForkID == 0 {
+		return nil, nil, errRepositoryNotFork
+	}
+
+	upstream, err := c.repoStore.Find(ctx, repo.ForkID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to find the upstream repository: %w", err)
+	}
+	if err = apiauth.CheckRepo(ctx, c.authorizer, session, upstream, enum.PermissionRepoView); err != nil {
+		return nil, nil, err
+	}
+
+	branch := in.Branch
+	if branch == "" {
+		branch = repo.DefaultBranch
+	}
+
+	upstreamBranch, err := c.git.GetBranch(ctx, &git.GetBranchParams{
+		ReadParams: git.CreateReadParams(upstream),
+		BranchName: branch,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get upstream branch: %w", err)
+	}
+	newSHA := upstreamBranch.Branch.SHA
+
+	refAction := protection.RefActionUpdate
+	oldSHA := sha.Nil // the branch must not exist if it's created
+	forkBranch, err := c.git.GetBranch(ctx, &git.GetBranchParams{
+		ReadParams: git.CreateReadParams(repo),
+		BranchName: branch,
+	})
+	switch {
+	case errors.IsNotFound(err):
+		refAction = protection.RefActionCreate
+	case err != nil:
+		return nil, nil, fmt.Errorf("failed to get fork branch: %w", err)
+	default:
+		oldSHA = forkBranch.Branch.SHA
+	}
+
+	out := &SyncForkOutput{Branch: branch, OldSHA: oldSHA, NewSHA: newSHA}
+	if oldSHA.Equal(newSHA) {
+		return out, nil, nil
+	}
+
+	if refAction == protection.RefActionUpdate {
+		// the fork borrows the upstream objects, so the upstream commit is available in the fork.
+		ancestor, err := c.git.IsAncestor(ctx, git.IsAncestorParams{
+			ReadParams:          git.CreateReadParams(repo),
+			AncestorCommitSHA:   oldSHA,
+			DescendantCommitSHA: newSHA,
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to check if the branch can be fast-forwarded: %w", err)
+		}
+		if !ancestor.Ancestor {
+			return nil, nil, usererror.Conflict(fmt.Sprintf(
+				"Branch %q has diverged from the upstream repository and can't be fast-forwarded.", branch))
+		}
+	}
+
+	rules, isRepoOwner, err := c.fetchRules(ctx, session, repo)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	violations, err := rules.RefChangeVerify(ctx, protection.RefChangeVerifyInput{
+		ResolveUserGroupID: c.userGroupService.ListUserIDsByGroupIDs,
+		Actor:              &session.Principal,
+		AllowBypass:        in.BypassRules,
+		IsRepoOwner:        isRepoOwner,
+		Repo:               repo,
+		RefAction:          refAction,
+		RefType:            protection.RefTypeBranch,
+		RefNames:           []string{branch},
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to verify protection rules: %w", err)
+	}
+
+	if in.DryRunRules {
+		out.DryRunRulesOutput = types.DryRunRulesOutput{
+			DryRunRules:    true,
+			RuleViolations: violations,
+		}
+		return out, nil, nil
+	}
+
+	if protection.IsCritical(violations) {
+		return nil, violations, nil
+	}
+
+	writeParams, err := controller.CreateRPCInternalWriteParams(ctx, c.urlProvider, session, repo)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create RPC write params: %w", err)
+	}
+
+	err = c.git.UpdateRef(ctx, git.UpdateRefParams{
+		WriteParams: writeParams,
+		Type:        gitenum.RefTypeBranch,
+		Name:        branch,
+		NewValue:    newSHA,
+		OldValue:    oldSHA,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to update branch of the fork: %w", err)
+	}
+
+	out.Updated = true
+	return out, nil, nil
+}
+
+// CleanupForks has to be called when the repository is purged, the fork is removed from the number of forks
+// of its upstream. The forks of the repository have to be dissociated before, see DissociateForks.
+func (c *Controller) CleanupForks(ctx context.Context, repo *types.Repository) {
+	if repo.ForkID == 0 {
+		return
+	}
+
+	upstream, err := c.repoStore.Find(ctx, repo.ForkID)
+	if errors.Is(err, store.ErrResourceNotFound) {
+		return
+	}
+	if err == nil {
+		_, err = c.repoStore.UpdateOptLock(ctx, upstream, func(r *types.Repository) error {
+			r.NumForks = max(r.NumForks-1, 0)
+			return nil
+		})
+	}
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Int64("repo_id", repo.ID).Msg("failed to update number of forks of the upstream")
+	}
+}
+
+// DissociateForks copies the borrowed objects into the forks of the repository, including the deleted forks,
+// it has to succeed before the git repository is deleted, otherwise the forks lose the objects they borrow.
+// The forks purged together with the repository are skipped. The forks keep the reference to the upstream.
+func (c *Controller) DissociateForks(
+	ctx context.Context,
+	session *auth.Session,
+	repo *types.Repository,
+	purged map[int64]bool,
+) error {
+	now := time.Now().UnixMilli()
+	for _, filter := range []*types.RepoFilter{
+		{Page: 1, Size: int(math.MaxInt)},
+		{Page: 1, Size: int(math.MaxInt), DeletedBeforeOrAt: &now},
+	} {
+		forks, err := c.repoStore.ListForks(ctx, repo.ID, filter)
+		if err != nil {
+			return fmt.Errorf("failed to list forks of repo %d: %w", repo.ID, err)
+		}
+
+		for _, fork := range forks {
+			if purged[fork.ID] {
+				continue
+			}
+			err = c.git.DissociateRepository(ctx, &git.DissociateRepositoryParams{
+				WriteParams: git.WriteParams{
+					RepoUID: fork.GitUID,
+					Actor:   *identityFromPrincipal(session.Principal),
+				},
+			})
+			if err != nil {
+				return fmt.Errorf("failed to dissociate fork %d: %w", fork.ID, err)
+			}
+		}
+	}
+	return nil
+}
@@ -50,5 +50,11 @@ func (c *Controller) GetCommit(ctx context.Context,
 		return nil, fmt.Errorf("failed to map commit: %w", err)
 	}
 
+	signatures, err := c.signatureVerifier.Verify(ctx, repo, []string{commit.SHA})
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify commit signature: %w", err)
+	}
+	commit.Signature = signatures[commit.SHA]
+
 	return commit, nil
 }
@@ -0,0 +1,51 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/easysoft/gitfox/app/api/usererror"
+	"github.com/easysoft/gitfox/app/auth"
+	"github.com/easysoft/gitfox/types"
+	"github.com/easysoft/gitfox/types/enum"
+)
+
+// Housekeeping returns the object counts of the repository and the tasks of the next scheduled housekeeping.
+func (c *Controller) Housekeeping(ctx context.Context,
+	session *auth.Session,
+	repoRef string,
+) (*types.RepositoryHousekeeping, error) {
+	repo, err := c.getRepoCheckAccess(ctx, session, repoRef, enum.PermissionRepoView)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.housekeepingSvc.Inspect(ctx, repo)
+}
+
+// OptimizeRepo starts the housekeeping of the repository right away, regardless of the object counts.
+// It's restricted to the admin because a forced gc repacks all objects of the repository.
+func (c *Controller) OptimizeRepo(ctx context.Context,
+	session *auth.Session,
+	repoRef string,
+) error {
+	if !session.Principal.Admin {
+		return usererror.ErrForbidden
+	}
+
+	repo, err := c.getRepoCheckAccess(ctx, session, repoRef, enum.PermissionRepoEdit)
+	if err != nil {
+		return err
+	}
+
+	if err = c.housekeepingSvc.Trigger(ctx, repo); err != nil {
+		return fmt.Errorf("failed to start repo housekeeping: %w", err)
+	}
+	return nil
+}
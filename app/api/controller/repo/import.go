@@ -42,6 +42,8 @@ type ImportInput struct {
 
 	// Mirror indicates whether the repository should be imported as a mirror.
 	Mirror bool `json:"mirror,omitempty"`
+	// LFS indicates whether the lfs objects are fetched into the mirror.
+	LFS bool `json:"lfs,omitempty"`
 }
 
 // Import creates a new empty repository and starts git import to it from a remote repository.
@@ -90,6 +92,7 @@ func (c *Controller) Import(ctx context.Context, session *auth.Session, in *Impo
 			remoteRepository.CloneURL,
 			in.Pipelines,
 			in.Mirror,
+			in.LFS,
 		)
 		if err != nil {
 			return fmt.Errorf("failed to start import repository job: %w", err)
@@ -33,6 +33,9 @@ type CommitTag struct {
 	Message     string           `json:"message,omitempty"`
 	Tagger      *types.Signature `json:"tagger,omitempty"`
 	Commit      *types.Commit    `json:"commit,omitempty"`
+
+	// Signature is the verification result of the signature of the annotated tag.
+	Signature *types.GitSignatureResult `json:"signature,omitempty"`
 }
 
 // ListCommitTags lists the commit tags of a repo.
@@ -68,9 +71,43 @@ func (c *Controller) ListCommitTags(ctx context.Context,
 		}
 	}
 
+	if err = c.verifyTagSignatures(ctx, repo, tags); err != nil {
+		return nil, err
+	}
+
 	return tags, nil
 }
 
+// verifyTagSignatures populates the signatures of the annotated tags and of the included commits.
+func (c *Controller) verifyTagSignatures(ctx context.Context, repo *types.Repository, tags []CommitTag) error {
+	objectSHAs := make([]string, 0, 2*len(tags))
+	for i := range tags {
+		if tags[i].IsAnnotated {
+			objectSHAs = append(objectSHAs, tags[i].SHA)
+		}
+		if tags[i].Commit != nil {
+			objectSHAs = append(objectSHAs, tags[i].Commit.SHA)
+		}
+	}
+	if len(objectSHAs) == 0 {
+		return nil
+	}
+
+	signatures, err := c.signatureVerifier.Verify(ctx, repo, objectSHAs)
+	if err != nil {
+		return fmt.Errorf("failed to verify tag signatures: %w", err)
+	}
+	for i := range tags {
+		if tags[i].IsAnnotated {
+			tags[i].Signature = signatures[tags[i].SHA]
+		}
+		if tags[i].Commit != nil {
+			tags[i].Commit.Signature = signatures[tags[i].Commit.SHA]
+		}
+	}
+	return nil
+}
+
 func mapToRPCTagSortOption(o enum.TagSortOption) git.TagSortOption {
 	switch o {
 	case enum.TagSortOptionDate:
@@ -75,6 +75,7 @@ func (c *Controller) ListCommits(ctx context.Context,
 	}
 
 	commits := make([]types.Commit, len(rpcOut.Commits))
+	commitSHAs := make([]string, len(rpcOut.Commits))
 	for i := range rpcOut.Commits {
 		var commit *types.Commit
 		commit, err = controller.MapCommit(&rpcOut.Commits[i])
@@ -82,6 +83,15 @@ func (c *Controller) ListCommits(ctx context.Context,
 			return types.ListCommitResponse{}, fmt.Errorf("failed to map commit: %w", err)
 		}
 		commits[i] = *commit
+		commitSHAs[i] = commit.SHA
+	}
+
+	signatures, err := c.signatureVerifier.Verify(ctx, repo, commitSHAs)
+	if err != nil {
+		return types.ListCommitResponse{}, fmt.Errorf("failed to verify commit signatures: %w", err)
+	}
+	for i := range commits {
+		commits[i].Signature = signatures[commits[i].SHA]
 	}
 
 	renameDetailList := make([]types.RenameDetails, len(rpcOut.RenameDetails))
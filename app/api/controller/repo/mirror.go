@@ -0,0 +1,58 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/easysoft/gitfox/app/api/usererror"
+	"github.com/easysoft/gitfox/app/auth"
+	"github.com/easysoft/gitfox/types"
+	"github.com/easysoft/gitfox/types/enum"
+)
+
+var errRepositoryNotMirror = usererror.BadRequest("the repository isn't a mirror")
+
+// MirrorStatus returns the sync status of the mirror, including the error of the last sync.
+func (c *Controller) MirrorStatus(ctx context.Context,
+	session *auth.Session,
+	repoRef string,
+) (*types.RepositoryMirror, error) {
+	repo, err := c.getRepoCheckAccess(ctx, session, repoRef, enum.PermissionRepoView)
+	if err != nil {
+		return nil, err
+	}
+	if !repo.Mirror {
+		return nil, errRepositoryNotMirror
+	}
+
+	mirror, err := c.repoStore.GetMirror(ctx, repo.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find mirror: %w", err)
+	}
+	return mirror, nil
+}
+
+// SyncMirror starts the sync of the mirror right away, regardless of the sync interval.
+func (c *Controller) SyncMirror(ctx context.Context,
+	session *auth.Session,
+	repoRef string,
+) error {
+	repo, err := c.getRepoCheckAccess(ctx, session, repoRef, enum.PermissionRepoEdit)
+	if err != nil {
+		return err
+	}
+	if !repo.Mirror {
+		return errRepositoryNotMirror
+	}
+
+	if err = c.mirrorSvc.Trigger(ctx, repo); err != nil {
+		return fmt.Errorf("failed to start mirror sync: %w", err)
+	}
+	return nil
+}
@@ -71,10 +71,17 @@ func (c *Controller) PurgeNoAuth(
 		}
 	}
 
+	// the repository stays deleted and is purged again later if its forks can't be dissociated
+	if err := c.DissociateForks(ctx, session, repo, nil); err != nil {
+		return err
+	}
+
 	if err := c.repoStore.Purge(ctx, repo.ID, repo.Deleted); err != nil {
 		return fmt.Errorf("failed to delete repo from db: %w", err)
 	}
 
+	c.CleanupForks(ctx, repo)
+
 	if err := c.DeleteGitRepository(ctx, session, repo.GitUID); err != nil {
 		log.Ctx(ctx).Err(err).Msg("failed to remove git repository")
 	}
@@ -0,0 +1,92 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/easysoft/gitfox/app/auth"
+	"github.com/easysoft/gitfox/app/store"
+	"github.com/easysoft/gitfox/git"
+	"github.com/easysoft/gitfox/types"
+	"github.com/easysoft/gitfox/types/enum"
+)
+
+func TestPurgeNoAuth_DissociateFails(t *testing.T) {
+	deleted := int64(1)
+	upstream := &types.Repository{ID: 1, GitUID: "upstream", State: enum.RepoStateActive, Deleted: &deleted}
+	repoStore := &fakeForkRepoStore{forks: []*types.Repository{{ID: 2, GitUID: "fork", ForkID: 1}}}
+	gitSvc := &fakeForkGit{dissociateErr: errors.New("disk full")}
+	c := &Controller{repoStore: repoStore, git: gitSvc}
+
+	err := c.PurgeNoAuth(context.Background(), &auth.Session{}, upstream)
+	if err == nil {
+		t.Fatal("expected the purge to fail when the fork can't be dissociated")
+	}
+	if repoStore.purged {
+		t.Error("the repository must not be purged from the db before its forks are dissociated")
+	}
+	if gitSvc.deleted {
+		t.Error("the git repository must not be deleted before its forks are dissociated")
+	}
+	if len(gitSvc.dissociated) != 1 || gitSvc.dissociated[0] != "fork" {
+		t.Errorf("got dissociated repos %v, want [fork]", gitSvc.dissociated)
+	}
+}
+
+func TestDissociateForks_SkipPurged(t *testing.T) {
+	upstream := &types.Repository{ID: 1, GitUID: "upstream"}
+	repoStore := &fakeForkRepoStore{forks: []*types.Repository{
+		{ID: 2, GitUID: "fork-purged", ForkID: 1},
+		{ID: 3, GitUID: "fork-kept", ForkID: 1},
+	}}
+	gitSvc := &fakeForkGit{}
+	c := &Controller{repoStore: repoStore, git: gitSvc}
+
+	err := c.DissociateForks(context.Background(), &auth.Session{}, upstream, map[int64]bool{1: true, 2: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	// the forks are listed twice, for the active and for the deleted forks
+	if len(gitSvc.dissociated) != 2 || gitSvc.dissociated[0] != "fork-kept" || gitSvc.dissociated[1] != "fork-kept" {
+		t.Errorf("got dissociated repos %v, want only fork-kept", gitSvc.dissociated)
+	}
+}
+
+type fakeForkRepoStore struct {
+	store.RepoStore
+	forks  []*types.Repository
+	purged bool
+}
+
+func (f *fakeForkRepoStore) ListForks(context.Context, int64, *types.RepoFilter) ([]*types.Repository, error) {
+	return f.forks, nil
+}
+
+func (f *fakeForkRepoStore) Purge(context.Context, int64, *int64) error {
+	f.purged = true
+	return nil
+}
+
+type fakeForkGit struct {
+	git.Interface
+	dissociateErr error
+	dissociated   []string
+	deleted       bool
+}
+
+func (f *fakeForkGit) DissociateRepository(_ context.Context, params *git.DissociateRepositoryParams) error {
+	f.dissociated = append(f.dissociated, params.RepoUID)
+	return f.dissociateErr
+}
+
+func (f *fakeForkGit) DeleteRepository(context.Context, *git.DeleteRepositoryParams) error {
+	f.deleted = true
+	return nil
+}
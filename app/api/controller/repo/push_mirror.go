@@ -0,0 +1,304 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/easysoft/gitfox/app/api/usererror"
+	"github.com/easysoft/gitfox/app/auth"
+	"github.com/easysoft/gitfox/app/paths"
+	"github.com/easysoft/gitfox/app/services/pushmirror"
+	"github.com/easysoft/gitfox/audit"
+	"github.com/easysoft/gitfox/types"
+	"github.com/easysoft/gitfox/types/enum"
+
+	"github.com/guregu/null"
+	"github.com/rs/zerolog/log"
+)
+
+type PushMirrorCreateInput struct {
+	URL      string `json:"url"`
+	Username string `json:"username"`
+	// Secret is the identifier of the secret stores the password or the access token
+	Secret string `json:"secret"`
+	// Connector is the identifier of the connector provides the credentials
+	Connector string `json:"connector"`
+	// BranchFilter lists the branches pushed separated by comma like 'main,release/*', all branches if empty
+	BranchFilter string `json:"branch_filter"`
+	Force        bool   `json:"force"`
+	Prune        bool   `json:"prune"`
+	SyncOnPush   bool   `json:"sync_on_push"`
+	SyncInterval int64  `json:"sync_interval"`
+	Enabled      *bool  `json:"enabled"`
+}
+
+type PushMirrorUpdateInput struct {
+	URL          *string `json:"url"`
+	Username     *string `json:"username"`
+	Secret       *string `json:"secret"`
+	Connector    *string `json:"connector"`
+	BranchFilter *string `json:"branch_filter"`
+	Force        *bool   `json:"force"`
+	Prune        *bool   `json:"prune"`
+	SyncOnPush   *bool   `json:"sync_on_push"`
+	SyncInterval *int64  `json:"sync_interval"`
+	Enabled      *bool   `json:"enabled"`
+}
+
+// PushMirrorList lists the push mirrors of the repository.
+func (c *Controller) PushMirrorList(ctx context.Context,
+	session *auth.Session,
+	repoRef string,
+) ([]*types.PushMirror, error) {
+	repo, err := c.getRepoCheckAccess(ctx, session, repoRef, enum.PermissionRepoView)
+	if err != nil {
+		return nil, err
+	}
+
+	mirrors, err := c.pushMirrorStore.List(ctx, repo.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list push mirrors: %w", err)
+	}
+	for _, mirror := range mirrors {
+		c.pushMirrorSvc.PopulateCredentials(ctx, mirror)
+	}
+	return mirrors, nil
+}
+
+// PushMirrorFind returns the push mirror of the repository, including the status of the last push.
+func (c *Controller) PushMirrorFind(ctx context.Context,
+	session *auth.Session,
+	repoRef string,
+	id int64,
+) (*types.PushMirror, error) {
+	repo, err := c.getRepoCheckAccess(ctx, session, repoRef, enum.PermissionRepoView)
+	if err != nil {
+		return nil, err
+	}
+
+	mirror, err := c.pushMirrorStore.FindByRepo(ctx, repo.ID, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find push mirror: %w", err)
+	}
+	c.pushMirrorSvc.PopulateCredentials(ctx, mirror)
+	return mirror, nil
+}
+
+// PushMirrorCreate creates a push mirror of the repository, the mirror is pushed right away.
+func (c *Controller) PushMirrorCreate(ctx context.Context,
+	session *auth.Session,
+	repoRef string,
+	in *PushMirrorCreateInput,
+) (*types.PushMirror, error) {
+	repo, err := c.getRepoCheckAccess(ctx, session, repoRef, enum.PermissionRepoEdit)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	mirror := &types.PushMirror{
+		RepoID:    repo.ID,
+		Enabled:   true,
+		NextSync:  now.Unix(),
+		CreatedBy: session.Principal.ID,
+		Created:   now.UnixMilli(),
+		Updated:   now.UnixMilli(),
+	}
+	err = c.applyPushMirrorInput(ctx, session, repo, mirror, &PushMirrorUpdateInput{
+		URL: &in.URL, Username: &in.Username, Secret: &in.Secret, Connector: &in.Connector,
+		BranchFilter: &in.BranchFilter, Force: &in.Force, Prune: &in.Prune,
+		SyncOnPush: &in.SyncOnPush, SyncInterval: &in.SyncInterval, Enabled: in.Enabled,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = c.pushMirrorStore.Create(ctx, mirror); err != nil {
+		return nil, fmt.Errorf("failed to create push mirror: %w", err)
+	}
+	c.pushMirrorSvc.PopulateCredentials(ctx, mirror)
+
+	c.auditPushMirror(ctx, session, repo, mirror, audit.ActionCreated, audit.WithNewObject(mirror))
+
+	if mirror.Enabled {
+		if err = c.pushMirrorSvc.Trigger(ctx, mirror); err != nil {
+			log.Ctx(ctx).Warn().Err(err).Msgf("failed to trigger push mirror %d", mirror.ID)
+		}
+	}
+
+	return mirror, nil
+}
+
+// PushMirrorUpdate updates the settings of the push mirror.
+func (c *Controller) PushMirrorUpdate(ctx context.Context,
+	session *auth.Session,
+	repoRef string,
+	id int64,
+	in *PushMirrorUpdateInput,
+) (*types.PushMirror, error) {
+	repo, err := c.getRepoCheckAccess(ctx, session, repoRef, enum.PermissionRepoEdit)
+	if err != nil {
+		return nil, err
+	}
+
+	mirror, err := c.pushMirrorStore.FindByRepo(ctx, repo.ID, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find push mirror: %w", err)
+	}
+	c.pushMirrorSvc.PopulateCredentials(ctx, mirror)
+	oldMirror := *mirror
+
+	if err = c.applyPushMirrorInput(ctx, session, repo, mirror, in); err != nil {
+		return nil, err
+	}
+	// the new schedule starts from now
+	if in.SyncInterval != nil && *in.SyncInterval != oldMirror.SyncInterval {
+		mirror.NextSync = 0
+		if mirror.SyncInterval > 0 {
+			mirror.NextSync = time.Now().Unix() + mirror.SyncInterval*60
+		}
+	}
+
+	if err = c.pushMirrorStore.Update(ctx, mirror); err != nil {
+		return nil, fmt.Errorf("failed to update push mirror: %w", err)
+	}
+	mirror.Secret, mirror.Connector = "", ""
+	c.pushMirrorSvc.PopulateCredentials(ctx, mirror)
+
+	c.auditPushMirror(ctx, session, repo, mirror, audit.ActionUpdated,
+		audit.WithOldObject(oldMirror), audit.WithNewObject(mirror))
+
+	return mirror, nil
+}
+
+// PushMirrorDelete deletes the push mirror of the repository.
+func (c *Controller) PushMirrorDelete(ctx context.Context,
+	session *auth.Session,
+	repoRef string,
+	id int64,
+) error {
+	repo, err := c.getRepoCheckAccess(ctx, session, repoRef, enum.PermissionRepoEdit)
+	if err != nil {
+		return err
+	}
+
+	mirror, err := c.pushMirrorStore.FindByRepo(ctx, repo.ID, id)
+	if err != nil {
+		return fmt.Errorf("failed to find push mirror: %w", err)
+	}
+	c.pushMirrorSvc.PopulateCredentials(ctx, mirror)
+
+	if err = c.pushMirrorStore.Delete(ctx, repo.ID, id); err != nil {
+		return fmt.Errorf("failed to delete push mirror: %w", err)
+	}
+
+	c.auditPushMirror(ctx, session, repo, mirror, audit.ActionDeleted, audit.WithOldObject(mirror))
+
+	return nil
+}
+
+// PushMirrorSync starts the push of the mirror right away, regardless of the sync interval.
+func (c *Controller) PushMirrorSync(ctx context.Context,
+	session *auth.Session,
+	repoRef string,
+	id int64,
+) error {
+	repo, err := c.getRepoCheckAccess(ctx, session, repoRef, enum.PermissionRepoEdit)
+	if err != nil {
+		return err
+	}
+
+	mirror, err := c.pushMirrorStore.FindByRepo(ctx, repo.ID, id)
+	if err != nil {
+		return fmt.Errorf("failed to find push mirror: %w", err)
+	}
+	if !mirror.Enabled {
+		return usererror.BadRequest("the push mirror is disabled")
+	}
+
+	if err = c.pushMirrorSvc.Trigger(ctx, mirror); err != nil {
+		return fmt.Errorf("failed to start push mirror: %w", err)
+	}
+	return nil
+}
+
+func (c *Controller) applyPushMirrorInput(ctx context.Context, session *auth.Session, repo *types.Repository,
+	mirror *types.PushMirror, in *PushMirrorUpdateInput,
+) error {
+	oldURL, oldSecretID, oldConnectorID := mirror.URL, mirror.SecretID, mirror.ConnectorID
+
+	var err error
+	if in.URL != nil {
+		if mirror.URL, err = pushmirror.SanitizeURL(*in.URL); err != nil {
+			return err
+		}
+	}
+	if in.Username != nil {
+		mirror.Username = strings.TrimSpace(*in.Username)
+	}
+	if in.BranchFilter != nil {
+		if mirror.BranchFilter, err = pushmirror.SanitizeBranchFilter(*in.BranchFilter); err != nil {
+			return err
+		}
+	}
+	if in.Force != nil {
+		mirror.Force = *in.Force
+	}
+	if in.Prune != nil {
+		mirror.Prune = *in.Prune
+	}
+	if in.SyncOnPush != nil {
+		mirror.SyncOnPush = *in.SyncOnPush
+	}
+	if in.SyncInterval != nil {
+		if *in.SyncInterval < 0 {
+			return usererror.BadRequest("sync interval must not be negative")
+		}
+		mirror.SyncInterval = *in.SyncInterval
+	}
+	if in.Enabled != nil {
+		mirror.Enabled = *in.Enabled
+	}
+
+	if err = c.pushMirrorSvc.ApplyCredentials(ctx, repo.ParentID, mirror, in.Secret, in.Connector); err != nil {
+		return err
+	}
+
+	// the credentials are sent to the url, so the principal changing either of them must be allowed to use
+	// the credentials, and the later pushes are done on behalf of the principal.
+	if mirror.URL == oldURL && mirror.SecretID == oldSecretID && mirror.ConnectorID == oldConnectorID {
+		return nil
+	}
+	if err = c.pushMirrorSvc.AuthorizeCredentials(ctx, session, mirror); err != nil {
+		return err
+	}
+	mirror.CredentialsBy = null.Int{}
+	if mirror.SecretID.Valid || mirror.ConnectorID.Valid {
+		mirror.CredentialsBy = null.IntFrom(session.Principal.ID)
+	}
+	return nil
+}
+
+func (c *Controller) auditPushMirror(ctx context.Context, session *auth.Session, repo *types.Repository,
+	mirror *types.PushMirror, action audit.Action, options ...audit.Option,
+) {
+	err := c.auditService.Log(ctx,
+		session.Principal,
+		audit.NewResource(audit.ResourceTypePushMirror, strconv.FormatInt(mirror.ID, 10), audit.RepoName, repo.Identifier),
+		action,
+		paths.Parent(repo.Path),
+		options...,
+	)
+	if err != nil {
+		log.Ctx(ctx).Warn().Msgf("failed to insert audit log for %s push mirror operation: %s", action, err)
+	}
+}
@@ -16,16 +16,21 @@ package repo
 
 import (
 	"github.com/easysoft/gitfox/app/api/controller/limiter"
+	"github.com/easysoft/gitfox/app/api/controller/pullreq"
 	"github.com/easysoft/gitfox/app/auth/authz"
 	repoevents "github.com/easysoft/gitfox/app/events/repo"
 	"github.com/easysoft/gitfox/app/services/codeowners"
+	"github.com/easysoft/gitfox/app/services/housekeeping"
 	"github.com/easysoft/gitfox/app/services/importer"
 	"github.com/easysoft/gitfox/app/services/instrument"
 	"github.com/easysoft/gitfox/app/services/keywordsearch"
 	"github.com/easysoft/gitfox/app/services/label"
 	"github.com/easysoft/gitfox/app/services/locker"
+	"github.com/easysoft/gitfox/app/services/mirror"
 	"github.com/easysoft/gitfox/app/services/protection"
 	"github.com/easysoft/gitfox/app/services/publicaccess"
+	"github.com/easysoft/gitfox/app/services/publickey"
+	"github.com/easysoft/gitfox/app/services/pushmirror"
 	"github.com/easysoft/gitfox/app/services/settings"
 	"github.com/easysoft/gitfox/app/services/usergroup"
 	"github.com/easysoft/gitfox/app/store"
@@ -78,6 +83,12 @@ func ProvideController(
 	instrumentation instrument.Service,
 	userGroupStore store.UserGroupStore,
 	userGroupService usergroup.SearchService,
+	mirrorSvc *mirror.Service,
+	pushMirrorStore store.PushMirrorStore,
+	pushMirrorSvc *pushmirror.Service,
+	housekeepingSvc *housekeeping.Service,
+	signatureVerifier *publickey.SignatureVerifier,
+	pullreqCtrl *pullreq.Controller,
 ) *Controller {
 	return NewController(config, tx, urlProvider,
 		authorizer,
@@ -85,7 +96,8 @@ func ProvideController(
 		principalStore, ruleStore, checkStore, pullReqStore, settings,
 		principalInfoCache, protectionManager, rpcClient, importer,
 		codeOwners, reporeporter, indexer, limiter, locker, auditService, mtxManager, identifierCheck,
-		repoChecks, publicAccess, labelSvc, instrumentation, userGroupStore, userGroupService)
+		repoChecks, publicAccess, labelSvc, instrumentation, userGroupStore, userGroupService,
+		mirrorSvc, pushMirrorStore, pushMirrorSvc, housekeepingSvc, signatureVerifier, pullreqCtrl)
 }
 
 func ProvideRepoCheck() Check {
@@ -35,6 +35,8 @@ type ProviderInput struct {
 	ProviderSpace string                  `json:"provider_space"`
 	Pipelines     importer.PipelineOption `json:"pipelines"`
 	Mirror        bool                    `json:"mirror,omitempty"`
+	// LFS indicates whether the lfs objects are fetched into the mirrors.
+	LFS bool `json:"lfs,omitempty"`
 }
 
 type ImportInput struct {
@@ -115,6 +117,7 @@ func (c *Controller) Import(ctx context.Context, session *auth.Session, in *Impo
 			cloneURLs,
 			in.Pipelines,
 			in.Mirror,
+			in.LFS,
 		)
 		if err != nil {
 			return fmt.Errorf("failed to start import repository jobs: %w", err)
@@ -157,6 +157,7 @@ func (c *Controller) ImportRepositories(
 			cloneURLs,
 			in.Pipelines,
 			in.Mirror,
+			in.LFS,
 		)
 		if err != nil {
 			return fmt.Errorf("failed to start import repository jobs: %w", err)
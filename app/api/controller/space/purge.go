@@ -67,6 +67,11 @@ func (c *Controller) PurgeNoAuth(
 	)
 	defer cancel()
 
+	// the space stays deleted and is purged again later if the forks of its repositories can't be dissociated
+	if err := c.dissociateForks(ctx, session, space.ID, *space.Deleted); err != nil {
+		return err
+	}
+
 	var toBeDeletedRepos []*types.Repository
 	var err error
 	err = c.tx.WithTx(ctx, func(ctx context.Context) error {
@@ -80,6 +85,8 @@ func (c *Controller) PurgeNoAuth(
 	// permanently purge all repositories in the space and its subspaces after successful space purge tnx.
 	// cleanup will handle failed repository deletions.
 	for _, repo := range toBeDeletedRepos {
+		c.repoCtrl.CleanupForks(ctx, repo)
+
 		err := c.repoCtrl.DeleteGitRepository(ctx, session, repo.GitUID)
 		if err != nil {
 			log.Ctx(ctx).Warn().Err(err).
@@ -98,16 +105,7 @@ func (c *Controller) purgeSpaceInnerInTx(
 	spaceID int64,
 	deletedAt int64,
 ) ([]*types.Repository, error) {
-	filter := &types.RepoFilter{
-		Page:              1,
-		Size:              int(math.MaxInt),
-		Query:             "",
-		Order:             enum.OrderAsc,
-		Sort:              enum.RepoAttrDeleted,
-		DeletedBeforeOrAt: &deletedAt,
-		Recursive:         true,
-	}
-	repos, err := c.repoStore.List(ctx, spaceID, filter)
+	repos, err := c.repoStore.List(ctx, spaceID, purgedReposFilter(deletedAt))
 	if err != nil {
 		return nil, fmt.Errorf("failed to list space repositories: %w", err)
 	}
@@ -120,3 +118,40 @@ func (c *Controller) purgeSpaceInnerInTx(
 
 	return repos, nil
 }
+
+// dissociateForks dissociates the forks of the repositories purged with the space before their git repositories
+// are deleted, the forks purged with the space too are skipped.
+func (c *Controller) dissociateForks(
+	ctx context.Context,
+	session *auth.Session,
+	spaceID int64,
+	deletedAt int64,
+) error {
+	repos, err := c.repoStore.List(ctx, spaceID, purgedReposFilter(deletedAt))
+	if err != nil {
+		return fmt.Errorf("failed to list space repositories: %w", err)
+	}
+
+	purged := make(map[int64]bool, len(repos))
+	for _, repo := range repos {
+		purged[repo.ID] = true
+	}
+	for _, repo := range repos {
+		if err = c.repoCtrl.DissociateForks(ctx, session, repo, purged); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func purgedReposFilter(deletedAt int64) *types.RepoFilter {
+	return &types.RepoFilter{
+		Page:              1,
+		Size:              int(math.MaxInt),
+		Query:             "",
+		Order:             enum.OrderAsc,
+		Sort:              enum.RepoAttrDeleted,
+		DeletedBeforeOrAt: &deletedAt,
+		Recursive:         true,
+	}
+}
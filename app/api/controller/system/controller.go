@@ -17,6 +17,7 @@ package system
 import (
 	"context"
 
+	"github.com/easysoft/gitfox/app/services/publickey"
 	"github.com/easysoft/gitfox/app/store"
 	"github.com/easysoft/gitfox/types"
 )
@@ -24,12 +25,18 @@ import (
 type Controller struct {
 	principalStore store.PrincipalStore
 	config         *types.Config
+	serverSigner   *publickey.ServerSigner
 }
 
-func NewController(principalStore store.PrincipalStore, config *types.Config) *Controller {
+func NewController(
+	principalStore store.PrincipalStore,
+	config *types.Config,
+	serverSigner *publickey.ServerSigner,
+) *Controller {
 	return &Controller{
 		principalStore: principalStore,
 		config:         config,
+		serverSigner:   serverSigner,
 	}
 }
 
@@ -0,0 +1,46 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package system
+
+import (
+	"context"
+	"strings"
+
+	"github.com/easysoft/gitfox/app/api/usererror"
+	"github.com/easysoft/gitfox/app/auth"
+	"github.com/easysoft/gitfox/types"
+)
+
+// FindSigningKey returns the key signing the commits and tags created by the server.
+func (c *Controller) FindSigningKey(ctx context.Context) (*types.ServerSigningKey, error) {
+	key, err := c.serverSigner.Find(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if key == nil {
+		return nil, usererror.NotFound("Signing key of the instance is not configured")
+	}
+	return key, nil
+}
+
+// SaveSigningKey replaces the key signing the commits and tags created by the server.
+func (c *Controller) SaveSigningKey(
+	ctx context.Context,
+	session *auth.Session,
+	in *types.ServerSigningKeyInput,
+) (*types.ServerSigningKey, error) {
+	if strings.TrimSpace(in.PrivateKey) == "" {
+		return nil, usererror.BadRequest("Private key is required")
+	}
+	return c.serverSigner.Save(ctx, in, session.Principal.ID)
+}
+
+// DeleteSigningKey removes the key signing the commits and tags created by the server,
+// the commits and tags created afterwards are unsigned.
+func (c *Controller) DeleteSigningKey(ctx context.Context) error {
+	return c.serverSigner.Delete(ctx)
+}
@@ -15,6 +15,7 @@
 package system
 
 import (
+	"github.com/easysoft/gitfox/app/services/publickey"
 	"github.com/easysoft/gitfox/app/store"
 	"github.com/easysoft/gitfox/types"
 
@@ -26,6 +27,10 @@ var WireSet = wire.NewSet(
 	NewController,
 )
 
-func ProvideController(principalStore store.PrincipalStore, config *types.Config) *Controller {
-	return NewController(principalStore, config)
+func ProvideController(
+	principalStore store.PrincipalStore,
+	config *types.Config,
+	serverSigner *publickey.ServerSigner,
+) *Controller {
+	return NewController(principalStore, config, serverSigner)
 }
@@ -35,6 +35,7 @@ type Controller struct {
 	tokenStore        store.TokenStore
 	membershipStore   store.MembershipStore
 	publicKeyStore    store.PublicKeyStore
+	signatureStore    store.GitSignatureResultStore
 }
 
 func NewController(
@@ -45,6 +46,7 @@ func NewController(
 	tokenStore store.TokenStore,
 	membershipStore store.MembershipStore,
 	publicKeyStore store.PublicKeyStore,
+	signatureStore store.GitSignatureResultStore,
 ) *Controller {
 	return &Controller{
 		tx:                tx,
@@ -54,6 +56,7 @@ func NewController(
 		tokenStore:        tokenStore,
 		membershipStore:   membershipStore,
 		publicKeyStore:    publicKeyStore,
+		signatureStore:    signatureStore,
 	}
 }
 
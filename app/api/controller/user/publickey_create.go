@@ -54,23 +54,44 @@ func (c *Controller) CreatePublicKey(
 		return nil, err
 	}
 
-	key, comment, err := publickey.ParseString(in.Content)
-	if err != nil {
-		return nil, errors.InvalidArgument("could not parse public key")
-	}
-
-	now := time.Now().UnixMilli()
-
 	k := &types.PublicKey{
 		PrincipalID: user.ID,
-		Created:     now,
+		Created:     time.Now().UnixMilli(),
 		Verified:    nil, // the key is created as unverified
 		Identifier:  in.Identifier,
 		Usage:       in.Usage,
-		Fingerprint: key.Fingerprint(),
 		Content:     in.Content,
-		Comment:     comment,
-		Type:        key.Type(),
+	}
+
+	// matches reports whether an existing key with the same fingerprint is the same key
+	var matches func(content string) bool
+	if publickey.IsPGP(in.Content) {
+		if in.Usage != enum.PublicKeyUsageSign {
+			return nil, errors.InvalidArgument("PGP keys can only be used for signing")
+		}
+
+		key, err := publickey.ParsePGP([]byte(in.Content))
+		if err != nil {
+			return nil, err
+		}
+
+		k.Scheme = enum.PublicKeySchemePGP
+		k.Fingerprint = key.Fingerprint()
+		k.Comment = key.Comment()
+		k.Type = key.Type()
+		k.KeyIDs = key.KeyIDs()
+		matches = func(string) bool { return true }
+	} else {
+		key, comment, err := publickey.ParseString(in.Content)
+		if err != nil {
+			return nil, errors.InvalidArgument("could not parse public key")
+		}
+
+		k.Scheme = enum.PublicKeySchemeSSH
+		k.Fingerprint = key.Fingerprint()
+		k.Comment = comment
+		k.Type = key.Type()
+		matches = key.Matches
 	}
 
 	err = c.tx.WithTx(ctx, func(ctx context.Context) error {
@@ -80,7 +101,7 @@ func (c *Controller) CreatePublicKey(
 		}
 
 		for _, existingKey := range existingKeys {
-			if key.Matches(existingKey.Content) {
+			if existingKey.Scheme == k.Scheme && matches(existingKey.Content) {
 				return errors.InvalidArgument("Key is already in use")
 			}
 		}
@@ -90,7 +111,8 @@ func (c *Controller) CreatePublicKey(
 			return fmt.Errorf("failed to insert public key: %w", err)
 		}
 
-		return nil
+		// the signatures of the key might be cached as signed by an unknown key
+		return c.invalidateSignatureResults(ctx, k)
 	})
 	if err != nil {
 		return nil, err
@@ -117,3 +139,20 @@ func sanitizeCreatePublicKeyInput(in *CreatePublicKeyInput) error {
 
 	return nil
 }
+
+// invalidateSignatureResults removes the cached verification results of the signatures made by the signing key.
+func (c *Controller) invalidateSignatureResults(ctx context.Context, k *types.PublicKey) error {
+	if k.Usage != enum.PublicKeyUsageSign {
+		return nil
+	}
+
+	keyIDs := []string{k.Fingerprint}
+	if k.Scheme == enum.PublicKeySchemePGP {
+		keyIDs = k.KeyIDs
+	}
+
+	if err := c.signatureStore.DeleteByKeyIDs(ctx, keyIDs); err != nil {
+		return fmt.Errorf("failed to delete signature results of the key: %w", err)
+	}
+	return nil
+}
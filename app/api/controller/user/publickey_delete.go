@@ -38,10 +38,17 @@ func (c *Controller) DeletePublicKey(
 		return err
 	}
 
-	err = c.publicKeyStore.DeleteByIdentifier(ctx, user.ID, identifier)
+	key, err := c.publicKeyStore.FindByIdentifier(ctx, user.ID, identifier)
 	if err != nil {
-		return fmt.Errorf("failed to delete public key by id: %w", err)
+		return fmt.Errorf("failed to find public key by identifier: %w", err)
 	}
 
-	return nil
+	return c.tx.WithTx(ctx, func(ctx context.Context) error {
+		err = c.publicKeyStore.DeleteByIdentifier(ctx, user.ID, identifier)
+		if err != nil {
+			return fmt.Errorf("failed to delete public key by id: %w", err)
+		}
+
+		return c.invalidateSignatureResults(ctx, key)
+	})
 }
@@ -0,0 +1,50 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package artifact
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/easysoft/gitfox/app/api/render"
+	artctl "github.com/easysoft/gitfox/app/artifact/controller"
+	"github.com/easysoft/gitfox/types"
+)
+
+func HandMigrateBlobs(artCtl *artctl.Controller) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		in := new(types.ArtifactBlobMigrationInput)
+		if err := json.NewDecoder(r.Body).Decode(in); err != nil {
+			render.BadRequestf(ctx, w, "Invalid Request Body: %s.", err)
+			return
+		}
+
+		data, err := artCtl.MigrateBlobs(ctx, in)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		render.JSON(w, http.StatusAccepted, data)
+	}
+}
+
+func HandBlobMigrationProgress(artCtl *artctl.Controller) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		data, err := artCtl.BlobMigrationProgress(ctx)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		render.JSON(w, http.StatusOK, data)
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package artifact
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/easysoft/gitfox/app/api/render"
+	"github.com/easysoft/gitfox/app/api/request"
+	artctl "github.com/easysoft/gitfox/app/artifact/controller"
+)
+
+const (
+	debianDefaultDistribution = "stable"
+	debianDefaultComponent    = "main"
+)
+
+// HandDebianUpload uploads a .deb file, the distribution and the component are given by the query parameters.
+func HandDebianUpload(artCtl *artctl.Controller) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		baseReq, err := artCtl.LoadBaseRequest(ctx, r)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		distribution := request.QueryParamOrDefault(r, "distribution", debianDefaultDistribution)
+		component := request.QueryParamOrDefault(r, "component", debianDefaultComponent)
+		resWriter, err := artCtl.UploadDebian(ctx, r, baseReq, distribution, component)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		resWriter.Write(w)
+	}
+}
+
+func HandGetDebianKey(artCtl *artctl.Controller) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		spaceRef, err := request.GetSpaceRefFromPath(r)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+		view, err := artCtl.ParseSpaceView(ctx, spaceRef)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		data, err := artCtl.GetDebianKey(ctx, view)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		render.JSON(w, http.StatusOK, data)
+	}
+}
+
+func HandImportDebianKey(artCtl *artctl.Controller) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		baseReq, err := artCtl.LoadBaseRequest(ctx, r)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		in := new(artctl.DebianKeyInput)
+		if err = json.NewDecoder(r.Body).Decode(in); err != nil {
+			render.BadRequestf(ctx, w, "Invalid Request Body: %s.", err)
+			return
+		}
+
+		data, err := artCtl.ImportDebianKey(ctx, baseReq, in)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		render.JSON(w, http.StatusOK, data)
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package artifact
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/easysoft/gitfox/app/api/render"
+	"github.com/easysoft/gitfox/app/api/request"
+	artctl "github.com/easysoft/gitfox/app/artifact/controller"
+)
+
+func HandPromoteVersion(artCtl *artctl.Controller) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		baseReq, err := artCtl.LoadBaseRequest(ctx, r)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		in := new(artctl.PromoteInput)
+		if err = json.NewDecoder(r.Body).Decode(in); err != nil {
+			render.BadRequestf(ctx, w, "Invalid Request Body: %s.", err)
+			return
+		}
+
+		data, err := artCtl.PromoteVersion(ctx, baseReq, in)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		render.JSON(w, http.StatusCreated, data)
+	}
+}
+
+func HandListPromotions(artCtl *artctl.Controller) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		baseReq, err := artCtl.LoadBaseRequest(ctx, r)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		data, err := artCtl.ListPromotions(ctx, baseReq, request.ParsePage(r), request.ParseLimit(r))
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		render.JSON(w, http.StatusOK, data)
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package lfs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	apiauth "github.com/easysoft/gitfox/app/api/auth"
+	"github.com/easysoft/gitfox/app/api/controller/lfs"
+	"github.com/easysoft/gitfox/app/api/render"
+	"github.com/easysoft/gitfox/app/auth"
+	"github.com/easysoft/gitfox/app/url"
+
+	"github.com/rs/zerolog/log"
+)
+
+// renderError writes the error of the lfs api, the anonymous session is asked for the credentials,
+// the lfs client prompts for the credentials like git does.
+func renderError(
+	ctx context.Context,
+	w http.ResponseWriter,
+	session *auth.Session,
+	urlProvider url.Provider,
+	err error,
+) {
+	if errors.Is(err, apiauth.ErrNotAuthorized) && auth.IsAnonymousSession(session) {
+		w.Header().Add("LFS-Authenticate", fmt.Sprintf(`Basic realm="%s"`, urlProvider.GetAPIHostname(ctx)))
+		render.Unauthorized(ctx, w)
+		return
+	}
+
+	var conflict *lfs.LockConflictError
+	if errors.As(err, &conflict) {
+		renderJSON(ctx, w, http.StatusConflict, &lfs.LockConflictResponse{
+			Lock:    conflict.Lock,
+			Message: conflict.Error(),
+		})
+		return
+	}
+
+	render.TranslatedUserError(ctx, w, err)
+}
+
+// renderJSON writes the json response with the lfs media type.
+func renderJSON(ctx context.Context, w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", lfs.MediaType)
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Ctx(ctx).Err(err).Msg("failed to write lfs response")
+	}
+}
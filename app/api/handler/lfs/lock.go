@@ -0,0 +1,131 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package lfs
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/easysoft/gitfox/app/api/controller/lfs"
+	"github.com/easysoft/gitfox/app/api/render"
+	"github.com/easysoft/gitfox/app/api/request"
+	"github.com/easysoft/gitfox/app/url"
+)
+
+// HandleCreateLock handles locking a path of the repository.
+func HandleCreateLock(lfsCtrl *lfs.Controller, urlProvider url.Provider) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		session, _ := request.AuthSessionFrom(ctx)
+		repoRef, err := request.GetRepoRefFromPath(r)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		in := new(lfs.CreateLockInput)
+		err = json.NewDecoder(r.Body).Decode(in)
+		if err != nil {
+			render.BadRequestf(ctx, w, "Invalid request body: %s.", err)
+			return
+		}
+
+		lock, err := lfsCtrl.CreateLock(ctx, session, repoRef, in)
+		if err != nil {
+			renderError(ctx, w, session, urlProvider, err)
+			return
+		}
+		renderJSON(ctx, w, http.StatusCreated, &lfs.LockResponse{Lock: lock})
+	}
+}
+
+// HandleListLocks handles listing the locks of the repository.
+func HandleListLocks(lfsCtrl *lfs.Controller, urlProvider url.Provider) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		session, _ := request.AuthSessionFrom(ctx)
+		repoRef, err := request.GetRepoRefFromPath(r)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		out, err := lfsCtrl.ListLocks(ctx, session, repoRef, &lfs.ListLocksInput{
+			ID:     request.QueryParamOrDefault(r, request.QueryParamLFSID, ""),
+			Path:   request.QueryParamOrDefault(r, request.QueryParamLFSPath, ""),
+			Ref:    request.QueryParamOrDefault(r, request.QueryParamLFSRefspec, ""),
+			Cursor: request.QueryParamOrDefault(r, request.QueryParamLFSCursor, ""),
+			Limit:  request.ParseLimit(r),
+		})
+		if err != nil {
+			renderError(ctx, w, session, urlProvider, err)
+			return
+		}
+		renderJSON(ctx, w, http.StatusOK, out)
+	}
+}
+
+// HandleVerifyLocks handles listing the locks of the repository separated by the owner.
+func HandleVerifyLocks(lfsCtrl *lfs.Controller, urlProvider url.Provider) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		session, _ := request.AuthSessionFrom(ctx)
+		repoRef, err := request.GetRepoRefFromPath(r)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		in := new(lfs.VerifyLocksInput)
+		err = json.NewDecoder(r.Body).Decode(in)
+		if err != nil && !errors.Is(err, io.EOF) {
+			render.BadRequestf(ctx, w, "Invalid request body: %s.", err)
+			return
+		}
+
+		out, err := lfsCtrl.VerifyLocks(ctx, session, repoRef, in)
+		if err != nil {
+			renderError(ctx, w, session, urlProvider, err)
+			return
+		}
+		renderJSON(ctx, w, http.StatusOK, out)
+	}
+}
+
+// HandleUnlock handles removing a lock of the repository.
+func HandleUnlock(lfsCtrl *lfs.Controller, urlProvider url.Provider) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		session, _ := request.AuthSessionFrom(ctx)
+		repoRef, err := request.GetRepoRefFromPath(r)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+		lockID, err := request.GetLFSLockIDFromPath(r)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		in := new(lfs.UnlockInput)
+		err = json.NewDecoder(r.Body).Decode(in)
+		if err != nil && !errors.Is(err, io.EOF) {
+			render.BadRequestf(ctx, w, "Invalid request body: %s.", err)
+			return
+		}
+
+		lock, err := lfsCtrl.Unlock(ctx, session, repoRef, lockID, in)
+		if err != nil {
+			renderError(ctx, w, session, urlProvider, err)
+			return
+		}
+		renderJSON(ctx, w, http.StatusOK, &lfs.LockResponse{Lock: lock})
+	}
+}
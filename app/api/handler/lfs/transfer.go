@@ -0,0 +1,81 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package lfs
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/easysoft/gitfox/app/api/controller/lfs"
+	"github.com/easysoft/gitfox/app/api/render"
+	"github.com/easysoft/gitfox/app/api/request"
+	"github.com/easysoft/gitfox/app/url"
+
+	"github.com/rs/zerolog/log"
+)
+
+// HandleUpload handles the basic transfer upload of the lfs object.
+func HandleUpload(lfsCtrl *lfs.Controller, urlProvider url.Provider) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		session, _ := request.AuthSessionFrom(ctx)
+		repoRef, err := request.GetRepoRefFromPath(r)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+		oid, err := request.GetLFSObjectIDFromPath(r)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+		if r.ContentLength < 0 {
+			render.BadRequestf(ctx, w, "Content-Length is required.")
+			return
+		}
+
+		err = lfsCtrl.Upload(ctx, session, repoRef, lfs.Pointer{OID: oid, Size: r.ContentLength}, r.Body)
+		if err != nil {
+			renderError(ctx, w, session, urlProvider, err)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+// HandleDownload handles the basic transfer download of the lfs object.
+func HandleDownload(lfsCtrl *lfs.Controller, urlProvider url.Provider) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		session, _ := request.AuthSessionFrom(ctx)
+		repoRef, err := request.GetRepoRefFromPath(r)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+		oid, err := request.GetLFSObjectIDFromPath(r)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		content, size, err := lfsCtrl.Download(ctx, session, repoRef, oid)
+		if err != nil {
+			renderError(ctx, w, session, urlProvider, err)
+			return
+		}
+		defer func() {
+			if cErr := content.Close(); cErr != nil {
+				log.Ctx(ctx).Warn().Err(cErr).Msgf("failed to close lfs object %s", oid)
+			}
+		}()
+
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Header().Set("Content-Length", strconv.FormatInt(size, 10))
+		render.Reader(ctx, w, http.StatusOK, content)
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/easysoft/gitfox/app/api/controller/repo"
+	"github.com/easysoft/gitfox/app/api/render"
+	"github.com/easysoft/gitfox/app/api/request"
+	"github.com/easysoft/gitfox/app/auth"
+	"github.com/easysoft/gitfox/types"
+)
+
+type applyCommitsFunc func(ctx context.Context, session *auth.Session, repoRef string,
+	in *repo.CherryPickInput) (types.CherryPickResponse, []types.RuleViolations, error)
+
+// HandleCherryPick applies the changes of the commits to a branch,
+// a pull request is opened for the changes if requested.
+func HandleCherryPick(repoCtrl *repo.Controller) http.HandlerFunc {
+	return handleApplyCommits(repoCtrl.CherryPick)
+}
+
+// HandleRevert reverts the changes of the commits on a branch,
+// a pull request is opened for the changes if requested.
+func HandleRevert(repoCtrl *repo.Controller) http.HandlerFunc {
+	return handleApplyCommits(repoCtrl.Revert)
+}
+
+func handleApplyCommits(apply applyCommitsFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		session, _ := request.AuthSessionFrom(ctx)
+		repoRef, err := request.GetRepoRefFromPath(r)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		in := new(repo.CherryPickInput)
+		err = json.NewDecoder(r.Body).Decode(in)
+		if err != nil {
+			render.BadRequestf(ctx, w, "Invalid request body: %s.", err)
+			return
+		}
+
+		response, violations, err := apply(ctx, session, repoRef, in)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+		if violations != nil {
+			render.Violations(w, violations)
+			return
+		}
+
+		if response.PullReq != nil {
+			render.JSON(w, http.StatusCreated, response)
+			return
+		}
+
+		render.JSON(w, http.StatusOK, response)
+	}
+}
@@ -1588,6 +1588,31 @@ func repoOperations(reflector *openapi3.Reflector) {
 	_ = reflector.SetJSONResponse(&opStatistics, new(usererror.Error), http.StatusForbidden)
 	_ = reflector.SetJSONResponse(&opStatistics, new(usererror.Error), http.StatusNotFound)
 	_ = reflector.Spec.AddOperation(http.MethodGet, "/repos/{repo_ref}/statistics", opStatistics)
+
+	opHousekeeping := openapi3.Operation{}
+	opHousekeeping.WithTags("repository")
+	opHousekeeping.WithMapOfAnything(
+		map[string]interface{}{"operationId": "getRepoHousekeeping"})
+	_ = reflector.SetRequest(&opHousekeeping, new(repoRequest), http.MethodGet)
+	_ = reflector.SetJSONResponse(&opHousekeeping, new(types.RepositoryHousekeeping), http.StatusOK)
+	_ = reflector.SetJSONResponse(&opHousekeeping, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.SetJSONResponse(&opHousekeeping, new(usererror.Error), http.StatusUnauthorized)
+	_ = reflector.SetJSONResponse(&opHousekeeping, new(usererror.Error), http.StatusForbidden)
+	_ = reflector.SetJSONResponse(&opHousekeeping, new(usererror.Error), http.StatusNotFound)
+	_ = reflector.Spec.AddOperation(http.MethodGet, "/repos/{repo_ref}/housekeeping", opHousekeeping)
+
+	opOptimize := openapi3.Operation{}
+	opOptimize.WithTags("repository")
+	opOptimize.WithMapOfAnything(
+		map[string]interface{}{"operationId": "optimizeRepo"})
+	_ = reflector.SetRequest(&opOptimize, new(repoRequest), http.MethodPost)
+	_ = reflector.SetJSONResponse(&opOptimize, nil, http.StatusAccepted)
+	_ = reflector.SetJSONResponse(&opOptimize, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.SetJSONResponse(&opOptimize, new(usererror.Error), http.StatusUnauthorized)
+	_ = reflector.SetJSONResponse(&opOptimize, new(usererror.Error), http.StatusForbidden)
+	_ = reflector.SetJSONResponse(&opOptimize, new(usererror.Error), http.StatusNotFound)
+	_ = reflector.SetJSONResponse(&opOptimize, new(usererror.Error), http.StatusConflict)
+	_ = reflector.Spec.AddOperation(http.MethodPost, "/repos/{repo_ref}/housekeeping", opOptimize)
 	opDefineLabel := openapi3.Operation{}
 	opDefineLabel.WithTags("repository")
 	opDefineLabel.WithMapOfAnything(
@@ -133,4 +133,33 @@ func buildAdmin(reflector *openapi3.Reflector) {
 	_ = reflector.SetJSONResponse(&opDelete, new(usererror.Error), http.StatusInternalServerError)
 	_ = reflector.SetJSONResponse(&opDelete, new(usererror.Error), http.StatusNotFound)
 	_ = reflector.Spec.AddOperation(http.MethodDelete, "/admin/users/{user_uid}", opDelete)
+
+	opFindSigningKey := openapi3.Operation{}
+	opFindSigningKey.WithTags("admin")
+	opFindSigningKey.WithSummary("Get the key signing the commits and tags created by the server")
+	opFindSigningKey.WithMapOfAnything(map[string]interface{}{"operationId": "adminGetSigningKey"})
+	_ = reflector.SetRequest(&opFindSigningKey, nil, http.MethodGet)
+	_ = reflector.SetJSONResponse(&opFindSigningKey, new(types.ServerSigningKey), http.StatusOK)
+	_ = reflector.SetJSONResponse(&opFindSigningKey, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.SetJSONResponse(&opFindSigningKey, new(usererror.Error), http.StatusNotFound)
+	_ = reflector.Spec.AddOperation(http.MethodGet, "/admin/signing-key", opFindSigningKey)
+
+	opSaveSigningKey := openapi3.Operation{}
+	opSaveSigningKey.WithTags("admin")
+	opSaveSigningKey.WithSummary("Set the key signing the commits and tags created by the server")
+	opSaveSigningKey.WithMapOfAnything(map[string]interface{}{"operationId": "adminSaveSigningKey"})
+	_ = reflector.SetRequest(&opSaveSigningKey, new(types.ServerSigningKeyInput), http.MethodPut)
+	_ = reflector.SetJSONResponse(&opSaveSigningKey, new(types.ServerSigningKey), http.StatusOK)
+	_ = reflector.SetJSONResponse(&opSaveSigningKey, new(usererror.Error), http.StatusBadRequest)
+	_ = reflector.SetJSONResponse(&opSaveSigningKey, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.Spec.AddOperation(http.MethodPut, "/admin/signing-key", opSaveSigningKey)
+
+	opDeleteSigningKey := openapi3.Operation{}
+	opDeleteSigningKey.WithTags("admin")
+	opDeleteSigningKey.WithSummary("Remove the key signing the commits and tags created by the server")
+	opDeleteSigningKey.WithMapOfAnything(map[string]interface{}{"operationId": "adminDeleteSigningKey"})
+	_ = reflector.SetRequest(&opDeleteSigningKey, nil, http.MethodDelete)
+	_ = reflector.SetJSONResponse(&opDeleteSigningKey, nil, http.StatusNoContent)
+	_ = reflector.SetJSONResponse(&opDeleteSigningKey, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.Spec.AddOperation(http.MethodDelete, "/admin/signing-key", opDeleteSigningKey)
 }
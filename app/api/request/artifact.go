@@ -22,10 +22,16 @@ const (
 	PathParamUUID  = "uuid"
 	PathParamRefer = "reference"
 
+	PathParamProxyIdentifier = "proxy_identifier"
+	PathParamArtifactFormat  = "artifact_format"
+	PathParamArtifactView    = "artifact_view"
+
 	QueryParamPackage = "package"
 	QueryParamGroup   = "group"
 	QueryParamVersion = "version"
 
+	QueryParamComponentName = "name"
+
 	QueryParamNodeLevel = "level"
 )
 
@@ -33,6 +33,7 @@ const (
 	spaceKey
 	repoKey
 	requestIDKey
+	lfsRequestKey
 )
 
 // WithAuthSession returns a copy of parent in which the principal
@@ -0,0 +1,41 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package request
+
+import (
+	"context"
+	"net/http"
+)
+
+const (
+	PathParamLFSObjectID = "lfs_oid"
+	PathParamLFSLockID   = "lfs_lock_id"
+
+	QueryParamLFSID      = "id"
+	QueryParamLFSPath    = "path"
+	QueryParamLFSRefspec = "refspec"
+	QueryParamLFSCursor  = "cursor"
+)
+
+func GetLFSObjectIDFromPath(r *http.Request) (string, error) {
+	return PathParamOrError(r, PathParamLFSObjectID)
+}
+
+func GetLFSLockIDFromPath(r *http.Request) (string, error) {
+	return PathParamOrError(r, PathParamLFSLockID)
+}
+
+// WithLFSRequest returns a copy of parent marked as a request of the git lfs api.
+func WithLFSRequest(parent context.Context) context.Context {
+	return context.WithValue(parent, lfsRequestKey, true)
+}
+
+// IsLFSRequest returns true if the request is of the git lfs api, the only api accepting the lfs tokens.
+func IsLFSRequest(ctx context.Context) bool {
+	v, ok := ctx.Value(lfsRequestKey).(bool)
+	return ok && v
+}
@@ -49,6 +49,7 @@ var (
 	ErrSizeInvalid         = &RegistryErr{Code: "SIZE_INVALID", HTTPStatusValue: http.StatusBadRequest}
 	ErrUnauthorized        = &RegistryErr{Code: "UNAUTHORIZED", HTTPStatusValue: http.StatusUnauthorized}
 	ErrDenied              = &RegistryErr{Code: "DENIED", Message: "requested access to the resource is denied", HTTPStatusValue: http.StatusForbidden}
+	ErrQuotaExceeded       = &RegistryErr{Code: "DENIED", Message: "storage quota exceeded", HTTPStatusValue: http.StatusRequestEntityTooLarge}
 	ErrUnsupported         = &RegistryErr{Code: "UNSUPPORTED", HTTPStatusValue: http.StatusNotImplemented}
 	ErrCodeRangeInvalid    = &RegistryErr{Code: "RANGE_INVALID", Message: "invalid content range", HTTPStatusValue: http.StatusRequestedRangeNotSatisfiable}
 	ErrClientClosed        = &RegistryErr{Code: "CLIENT_CLOSED", HTTPStatusValue: 499}
@@ -136,6 +136,19 @@ func Statistic(ctx context.Context, artStore store.ArtifactStore, fileStore stor
 		tags = append(tags, t)
 	}
 
+	versions, err := artStore.Versions().Search(ctx, artifacts.PackageFormatOption{Format: types.ArtifactContainerFormat})
+	if err != nil {
+		return nil, err
+	}
+	versionMap := make(map[int64]*types.ArtifactVersionInfo, len(versions))
+	for _, version := range versions {
+		versionMap[version.ID] = version
+	}
+
+	if err = markReferrers(ctx, artStore, digestRefsMap, assetMarkMap, versionMap, manifestGetter); err != nil {
+		return nil, err
+	}
+
 	deleteList := make([]*types.ArtifactRecycleBlobDesc, 0)
 	for dgst, d := range digestRefsMap {
 		if d.count != 0 {
@@ -154,3 +167,79 @@ func Statistic(ctx context.Context, artStore store.ArtifactStore, fileStore stor
 
 	return &types.ArtifactStatisticReport{DeleteList: deleteList, TagList: tags}, nil
 }
+
+// referrerKey is the manifest in a repository of a view, the referrers are indexed by the repository,
+// so the referrers are kept only in the repositories where their subject is alive.
+type referrerKey struct {
+	viewID     int64
+	repository string
+	digest     digest.Digest
+}
+
+// markReferrers keeps the referrers (signatures, sboms etc.) of the marked manifests alive,
+// a referrer may have referrers too, so it's marked until no more digest is found.
+func markReferrers(ctx context.Context, artStore store.ArtifactStore, digestRefsMap map[digest.Digest]*digestRef,
+	assetMarkMap map[int64]*manifestRefs, versionMap map[int64]*types.ArtifactVersionInfo, manifestGetter *manifestStore,
+) error {
+	referrers, err := artStore.Referrers().List(ctx)
+	if err != nil {
+		return err
+	}
+
+	subjectMap := make(map[referrerKey][]digest.Digest)
+	for _, referrer := range referrers {
+		subject := referrerKey{viewID: referrer.ViewID, repository: referrer.Repository, digest: digest.Digest(referrer.Subject)}
+		subjectMap[subject] = append(subjectMap[subject], digest.Digest(referrer.Digest))
+	}
+
+	// the manifests marked by a tag are alive in the repository of the tag
+	visited := make(map[referrerKey]bool)
+	queue := make([]referrerKey, 0)
+	for _, assetMark := range assetMarkMap {
+		version, ok := versionMap[assetMark.obj.VersionID.Int64]
+		if !ok {
+			continue
+		}
+		for _, dgst := range assetMark.digests {
+			key := referrerKey{viewID: version.ViewID, repository: version.PackageName, digest: dgst}
+			if !visited[key] {
+				visited[key] = true
+				queue = append(queue, key)
+			}
+		}
+	}
+
+	ingester := func(d digest.Digest) {
+		if ref, exist := digestRefsMap[d]; exist {
+			ref.count += 1
+		}
+	}
+
+	for len(queue) > 0 {
+		subject := queue[0]
+		queue = queue[1:]
+
+		for _, dgst := range subjectMap[subject] {
+			key := referrerKey{viewID: subject.viewID, repository: subject.repository, digest: dgst}
+			if visited[key] {
+				continue
+			}
+			visited[key] = true
+
+			ref, exist := digestRefsMap[dgst]
+			if !exist {
+				continue
+			}
+			// the referrer alive in another repository has its references marked already
+			if ref.count == 0 {
+				log.Ctx(ctx).Debug().Msgf("keep referrer %s of subject %s in %s", dgst, subject.digest, subject.repository)
+				ref.count += 1
+				if e := markManifestReferences(ctx, dgst, manifestGetter, ingester); e != nil {
+					return e
+				}
+			}
+			queue = append(queue, key)
+		}
+	}
+	return nil
+}
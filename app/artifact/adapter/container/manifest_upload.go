@@ -9,11 +9,13 @@ package container
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 
 	"github.com/easysoft/gitfox/app/artifact/adapter"
+	"github.com/easysoft/gitfox/app/artifact/adapter/container/schema"
 	"github.com/easysoft/gitfox/app/artifact/adapter/request"
 	"github.com/easysoft/gitfox/app/store"
 	"github.com/easysoft/gitfox/pkg/storage"
@@ -26,6 +28,15 @@ type manifestUploader struct {
 	digest    string
 	tag       string
 	uploadReq *request.ArtifactUploadRequest
+	artStore  store.ArtifactStore
+	view      *adapter.ViewDescriptor
+
+	// repository is required to index the referrers of the manifest
+	repository string
+	subject    string
+	manifest   schema.Manifest
+	// verify rejects the content does not match the digest
+	verify bool
 	//base      *base.HostedUploader
 	store storage.ContentStorage
 
@@ -39,6 +50,8 @@ func NewManifestUploader(artStore store.ArtifactStore, view *adapter.ViewDescrip
 	up := &manifestUploader{
 		digest:      dgst,
 		uploadReq:   request.NewUpload(view, artStore),
+		artStore:    artStore,
+		view:        view,
 		store:       view.Store,
 		storeLayout: adapter.StorageLayoutBlob,
 		descriptor:  adapter.NewEmptyPackageDescriptor(),
@@ -51,10 +64,32 @@ func (h *manifestUploader) Prepare(fn func(desc *adapter.PackageDescriptor)) {
 	fn(h.descriptor)
 }
 
+// WithReferrers enables indexing the manifest as a referrer if it has a subject
+func (h *manifestUploader) WithReferrers(repository string) *manifestUploader {
+	h.repository = repository
+	return h
+}
+
+// WithVerifiedDigest rejects the content if its digest is not the expected one
+func (h *manifestUploader) WithVerifiedDigest() *manifestUploader {
+	h.verify = true
+	return h
+}
+
 func (h *manifestUploader) Descriptor() *adapter.PackageDescriptor {
 	return h.descriptor
 }
 
+// Manifest returns the parsed manifest after it's saved, it's nil if the manifest is unknown or not parsed
+func (h *manifestUploader) Manifest() schema.Manifest {
+	return h.manifest
+}
+
+// Subject returns the subject digest of the saved manifest, it's empty if the manifest has no subject
+func (h *manifestUploader) Subject() string {
+	return h.subject
+}
+
 func (h *manifestUploader) Serve(ctx context.Context, req *http.Request) (int64, error) {
 	mediaType := req.Header.Get("Content-Type")
 	fw, ref, err := adapter.NewRandomBlobWriter(ctx, h.store)
@@ -62,11 +97,16 @@ func (h *manifestUploader) Serve(ctx context.Context, req *http.Request) (int64,
 		return 0, err
 	}
 
-	buf := bytes.NewBuffer(nil)
-	h.bufReader = buf
+	// content is kept only for parsing the referrer, blobs may be large
+	var extra []io.Writer
+	if h.repository != "" {
+		buf := bytes.NewBuffer(nil)
+		h.bufReader = buf
+		extra = append(extra, buf)
+	}
 
 	h.uploadReq.RegisterWriter(fw)
-	size, hash, err := request.Write(req.Body, fw, buf)
+	size, hash, err := request.Write(req.Body, fw, extra...)
 	if err != nil {
 		return 0, err
 	}
@@ -96,6 +136,9 @@ func (h *manifestUploader) IsValid(ctx context.Context) error {
 	log.Ctx(ctx).Info().Msgf("digest: %s", h.digest)
 	log.Ctx(ctx).Info().Msgf("hash sha256: %s", h.descriptor.MainAsset.Hash.Sha256)
 	h.descriptor.MainAsset.Path = fmt.Sprintf("sha256:%s", h.descriptor.MainAsset.Hash.Sha256)
+	if h.verify && h.descriptor.MainAsset.Path != h.digest {
+		return ErrDigestInvalid.WithDetail(fmt.Sprintf("expect %s, got %s", h.digest, h.descriptor.MainAsset.Path))
+	}
 	h.descriptor.MainAsset.Format = types.ArtifactContainerFormat
 	h.uploadReq.Descriptor = h.descriptor
 	return nil
@@ -107,6 +150,50 @@ func (h *manifestUploader) Save(ctx context.Context) error {
 		_ = h.uploadReq.Cancel(ctx)
 		return err
 	}
+
+	if h.repository == "" {
+		return nil
+	}
+
+	content, err := io.ReadAll(h.bufReader)
+	if err != nil {
+		return err
+	}
+	manifest, err := schema.UnmarshalManifest(h.descriptor.MainAsset.ContentType, content)
+	if err != nil {
+		log.Ctx(ctx).Debug().Err(err).Msg("skip indexing referrer of the unknown manifest")
+		return nil
+	}
+	h.manifest = manifest
+	return h.indexReferrer(ctx)
+}
+
+func (h *manifestUploader) indexReferrer(ctx context.Context) error {
+	referrer, ok := h.manifest.(schema.Referrer)
+	if !ok || referrer.GetSubject() == nil {
+		return nil
+	}
+
+	annotations, err := json.Marshal(referrer.GetAnnotations())
+	if err != nil {
+		return err
+	}
+
+	subject := referrer.GetSubject().Digest.String()
+	if err = h.artStore.Referrers().Create(ctx, &types.ArtifactReferrer{
+		ViewID:       h.view.ViewID,
+		Repository:   h.repository,
+		Subject:      subject,
+		Digest:       h.descriptor.MainAsset.Path,
+		MediaType:    h.descriptor.MainAsset.ContentType,
+		ArtifactType: referrer.GetArtifactType(),
+		Size:         h.descriptor.MainAsset.Size,
+		Annotations:  string(annotations),
+	}); err != nil {
+		return err
+	}
+
+	h.subject = subject
 	return nil
 }
 
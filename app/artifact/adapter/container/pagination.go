@@ -0,0 +1,66 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package container
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+)
+
+// MaxPageSize limits the entries of a page of the catalog and the tags list.
+const MaxPageSize = 1000
+
+var ErrPaginationNumberInvalid = &RegistryErr{Code: "PAGINATION_NUMBER_INVALID",
+	Message: "invalid number of results requested", HTTPStatusValue: http.StatusBadRequest}
+
+// Pagination is the 'n' and 'last' query of the catalog and the tags list,
+// see https://github.com/opencontainers/distribution-spec/blob/main/spec.md#listing-tags.
+// N is negative if the client doesn't limit the results.
+type Pagination struct {
+	N    int
+	Last string
+}
+
+func ParsePagination(query url.Values) (*Pagination, error) {
+	p := &Pagination{N: -1, Last: query.Get("last")}
+	if s := query.Get("n"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 0 {
+			return nil, ErrPaginationNumberInvalid.WithDetail(s)
+		}
+		p.N = min(n, MaxPageSize)
+	}
+	return p, nil
+}
+
+// Paginate sorts the names lexically and returns the page after Last,
+// more reports whether there are names after the page.
+func (p *Pagination) Paginate(names []string) ([]string, bool) {
+	sort.Strings(names)
+	start := 0
+	if p.Last != "" {
+		start = sort.SearchStrings(names, p.Last)
+		if start < len(names) && names[start] == p.Last {
+			start++
+		}
+	}
+	names = names[start:]
+	if p.N < 0 || len(names) <= p.N {
+		return names, false
+	}
+	// no link to the next page is given for an empty page
+	return names[:p.N], p.N > 0
+}
+
+// NextLink is the Link header pointing to the page after the last name.
+func (p *Pagination) NextLink(path, last string) string {
+	query := url.Values{"last": []string{last}, "n": []string{strconv.Itoa(p.N)}}
+	return fmt.Sprintf(`<%s?%s>; rel="next"`, path, query.Encode())
+}
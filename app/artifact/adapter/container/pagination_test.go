@@ -0,0 +1,60 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package container
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePagination(t *testing.T) {
+	p, err := ParsePagination(url.Values{})
+	require.NoError(t, err)
+	require.Equal(t, &Pagination{N: -1}, p)
+
+	p, err = ParsePagination(url.Values{"n": []string{"5000"}, "last": []string{"v1"}})
+	require.NoError(t, err)
+	require.Equal(t, &Pagination{N: MaxPageSize, Last: "v1"}, p)
+
+	for _, n := range []string{"-1", "abc"} {
+		_, err = ParsePagination(url.Values{"n": []string{n}})
+		var regErr *RegistryErr
+		require.ErrorAs(t, err, &regErr)
+		require.Equal(t, ErrPaginationNumberInvalid.Code, regErr.Code)
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	names := func() []string { return []string{"v3", "latest", "v1", "v2"} }
+	tests := []struct {
+		name     string
+		page     Pagination
+		want     []string
+		wantMore bool
+	}{
+		{name: "all", page: Pagination{N: -1}, want: []string{"latest", "v1", "v2", "v3"}},
+		{name: "first page", page: Pagination{N: 2}, want: []string{"latest", "v1"}, wantMore: true},
+		{name: "next page", page: Pagination{N: 2, Last: "v1"}, want: []string{"v2", "v3"}},
+		{name: "last not listed", page: Pagination{N: 1, Last: "u"}, want: []string{"v1"}, wantMore: true},
+		{name: "after the end", page: Pagination{N: 2, Last: "v3"}, want: []string{}},
+		{name: "empty page", page: Pagination{N: 0}, want: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, more := tt.page.Paginate(names())
+			require.Equal(t, tt.want, got)
+			require.Equal(t, tt.wantMore, more)
+		})
+	}
+}
+
+func TestNextLink(t *testing.T) {
+	p := &Pagination{N: 2}
+	require.Equal(t, `</v2/space/app/tags/list?last=v1&n=2>; rel="next"`, p.NextLink("/v2/space/app/tags/list", "v1"))
+}
@@ -0,0 +1,275 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/easysoft/gitfox/app/artifact/adapter/container/schema"
+	_ "github.com/easysoft/gitfox/app/artifact/adapter/container/schema/ocischema" // register manifest media types
+	_ "github.com/easysoft/gitfox/app/artifact/adapter/container/schema/schema2"   // register manifest media types
+
+	"github.com/opencontainers/go-digest"
+)
+
+const (
+	dockerHubHost      = "registry-1.docker.io"
+	headerDigest       = "Docker-Content-Digest"
+	headerAuthenticate = "Www-Authenticate"
+
+	defaultTimeout = time.Minute * 10
+)
+
+var (
+	ErrNotFound        = errors.New("upstream resource not found")
+	ErrUnauthorized    = errors.New("upstream authentication failed")
+	ErrDigestMismatch  = errors.New("upstream content digest mismatch")
+	ErrInvalidEndpoint = errors.New("invalid upstream url")
+)
+
+// Credentials authenticates to the upstream registry by basic auth or exchanging a bearer token,
+// the password may be an access token of the registry.
+type Credentials struct {
+	Username string
+	Password string
+}
+
+// Manifest is a manifest fetched from the upstream registry.
+type Manifest struct {
+	Content   []byte
+	MediaType string
+	Digest    digest.Digest
+}
+
+// Client pulls manifests and blobs from an upstream registry implementing the distribution api,
+// the bearer token auth of the registry is handled transparently.
+type Client struct {
+	endpoint *url.URL
+	creds    Credentials
+	client   *http.Client
+
+	mu     sync.Mutex
+	tokens map[string]string
+}
+
+func NewClient(endpoint string, creds Credentials) (*Client, error) {
+	u, err := url.Parse(strings.TrimSuffix(endpoint, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidEndpoint, err.Error())
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidEndpoint, endpoint)
+	}
+
+	return &Client{
+		endpoint: u,
+		creds:    creds,
+		client:   &http.Client{Timeout: defaultTimeout},
+		tokens:   make(map[string]string),
+	}, nil
+}
+
+// HeadManifest returns the digest of the manifest without downloading it.
+func (c *Client) HeadManifest(ctx context.Context, repository, reference string) (digest.Digest, error) {
+	res, err := c.do(ctx, http.MethodHead, repository, "manifests", reference)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	return digest.Parse(res.Header.Get(headerDigest))
+}
+
+// GetManifest downloads the manifest by tag or digest, the content is verified if the reference is a digest.
+func (c *Client) GetManifest(ctx context.Context, repository, reference string) (*Manifest, error) {
+	res, err := c.do(ctx, http.MethodGet, repository, "manifests", reference)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	content, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	dgst := digest.FromBytes(content)
+	if expect, e := digest.Parse(reference); e == nil && expect != dgst {
+		return nil, ErrDigestMismatch
+	}
+
+	return &Manifest{
+		Content:   content,
+		MediaType: res.Header.Get("Content-Type"),
+		Digest:    dgst,
+	}, nil
+}
+
+// GetBlob opens the blob content, the caller should close the reader.
+func (c *Client) GetBlob(ctx context.Context, repository string, dgst digest.Digest) (io.ReadCloser, int64, error) {
+	res, err := c.do(ctx, http.MethodGet, repository, "blobs", dgst.String())
+	if err != nil {
+		return nil, 0, err
+	}
+	return res.Body, res.ContentLength, nil
+}
+
+func (c *Client) do(ctx context.Context, method, repository string, segments ...string) (*http.Response, error) {
+	repository = c.repositoryName(repository)
+	target := c.endpoint.JoinPath(append([]string{"v2", repository}, segments...)...)
+
+	res, err := c.send(ctx, method, target.String(), repository, false)
+	if err != nil {
+		return nil, err
+	}
+
+	// authenticate with the challenge of the registry, then try again
+	if res.StatusCode == http.StatusUnauthorized {
+		challenge := res.Header.Get(headerAuthenticate)
+		res.Body.Close()
+		if err = c.authorize(ctx, repository, challenge); err != nil {
+			return nil, err
+		}
+		if res, err = c.send(ctx, method, target.String(), repository, true); err != nil {
+			return nil, err
+		}
+	}
+
+	switch {
+	case res.StatusCode == http.StatusNotFound:
+		res.Body.Close()
+		return nil, ErrNotFound
+	case res.StatusCode == http.StatusUnauthorized || res.StatusCode == http.StatusForbidden:
+		res.Body.Close()
+		return nil, ErrUnauthorized
+	case res.StatusCode >= http.StatusBadRequest:
+		res.Body.Close()
+		return nil, fmt.Errorf("upstream responded with status %d", res.StatusCode)
+	}
+	return res, nil
+}
+
+func (c *Client) send(ctx context.Context, method, target, repository string, retry bool) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", strings.Join(schema.MediaList, ", "))
+
+	switch {
+	case c.token(repository) != "":
+		req.Header.Set("Authorization", "Bearer "+c.token(repository))
+	case retry && c.creds.Username != "":
+		// registry asks for basic auth in the challenge
+		req.SetBasicAuth(c.creds.Username, c.creds.Password)
+	}
+	return c.client.Do(req)
+}
+
+// authorize requests a bearer token from the realm of the challenge, basic challenge is answered on retry.
+func (c *Client) authorize(ctx context.Context, repository, challenge string) error {
+	scheme, params := parseChallenge(challenge)
+	if strings.EqualFold(scheme, "basic") {
+		if c.creds.Username == "" {
+			return ErrUnauthorized
+		}
+		return nil
+	}
+	if !strings.EqualFold(scheme, "bearer") || params["realm"] == "" {
+		return ErrUnauthorized
+	}
+
+	realm, err := url.Parse(params["realm"])
+	if err != nil {
+		return fmt.Errorf("invalid token realm: %w", err)
+	}
+	query := realm.Query()
+	if service := params["service"]; service != "" {
+		query.Set("service", service)
+	}
+	query.Set("scope", fmt.Sprintf("repository:%s:pull", repository))
+	realm.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return err
+	}
+	if c.creds.Username != "" {
+		req.SetBasicAuth(c.creds.Username, c.creds.Password)
+	}
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return ErrUnauthorized
+	}
+
+	var token struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err = json.NewDecoder(res.Body).Decode(&token); err != nil {
+		return fmt.Errorf("decode token response failed: %w", err)
+	}
+	if token.Token == "" {
+		token.Token = token.AccessToken
+	}
+	if token.Token == "" {
+		return ErrUnauthorized
+	}
+
+	c.mu.Lock()
+	c.tokens[repository] = token.Token
+	c.mu.Unlock()
+	return nil
+}
+
+func (c *Client) token(repository string) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.tokens[repository]
+}
+
+// repositoryName adds the implicit 'library' namespace of the official images on docker hub.
+func (c *Client) repositoryName(repository string) string {
+	if c.endpoint.Host == dockerHubHost && !strings.Contains(repository, "/") {
+		return "library/" + repository
+	}
+	return repository
+}
+
+// parseChallenge parses the header like 'Bearer realm="https://auth.example.com/token",service="registry"'.
+func parseChallenge(header string) (string, map[string]string) {
+	params := make(map[string]string)
+	scheme, rest, _ := strings.Cut(strings.TrimSpace(header), " ")
+
+	for rest != "" {
+		var key, value string
+		key, rest, _ = strings.Cut(strings.TrimLeft(rest, " ,"), "=")
+		if strings.HasPrefix(rest, `"`) {
+			value, rest, _ = strings.Cut(rest[1:], `"`)
+		} else {
+			value, rest, _ = strings.Cut(rest, ",")
+		}
+		if key = strings.TrimSpace(key); key != "" {
+			params[strings.ToLower(key)] = value
+		}
+	}
+	return scheme, params
+}
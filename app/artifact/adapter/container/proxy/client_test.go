@@ -0,0 +1,149 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testManifest = `{"schemaVersion":2,"mediaType":"application/vnd.oci.image.manifest.v1+json","config":{},"layers":[]}`
+	testBlob     = "layer content"
+	testToken    = "upstream-token"
+)
+
+// newTestRegistry starts a registry stand-in serves the 'library/alpine' repository,
+// it requires the token issued by '/token' if tokenAuth is set, or basic auth otherwise.
+func newTestRegistry(t *testing.T, tokenAuth bool) *httptest.Server {
+	manifestDigest := digest.FromString(testManifest)
+	blobDigest := digest.FromString(testBlob)
+
+	mux := http.NewServeMux()
+	var server *httptest.Server
+
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if u, p, ok := r.BasicAuth(); !ok || u != "admin" || p != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Query().Get("scope") != "repository:library/alpine:pull" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		fmt.Fprintf(w, `{"token":"%s"}`, testToken)
+	})
+
+	mux.HandleFunc("/v2/", func(w http.ResponseWriter, r *http.Request) {
+		if tokenAuth && r.Header.Get("Authorization") != "Bearer "+testToken {
+			w.Header().Set(headerAuthenticate, fmt.Sprintf(`Bearer realm="%s/token",service="test"`, server.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if u, p, ok := r.BasicAuth(); !tokenAuth && (!ok || u != "admin" || p != "secret") {
+			w.Header().Set(headerAuthenticate, `Basic realm="test"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/v2/library/alpine/manifests/latest", "/v2/library/alpine/manifests/" + manifestDigest.String():
+			w.Header().Set("Content-Type", v1.MediaTypeImageManifest)
+			w.Header().Set(headerDigest, manifestDigest.String())
+			if r.Method == http.MethodHead {
+				return
+			}
+			io.WriteString(w, testManifest)
+		case "/v2/library/alpine/blobs/" + blobDigest.String():
+			io.WriteString(w, testBlob)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	server = httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestClient(t *testing.T) {
+	ctx := context.Background()
+
+	for _, tokenAuth := range []bool{true, false} {
+		t.Run(fmt.Sprintf("tokenAuth=%v", tokenAuth), func(t *testing.T) {
+			server := newTestRegistry(t, tokenAuth)
+			client, err := NewClient(server.URL, Credentials{Username: "admin", Password: "secret"})
+			require.NoError(t, err)
+
+			m, err := client.GetManifest(ctx, "library/alpine", "latest")
+			require.NoError(t, err)
+			require.Equal(t, testManifest, string(m.Content))
+			require.Equal(t, v1.MediaTypeImageManifest, m.MediaType)
+			require.Equal(t, digest.FromString(testManifest), m.Digest)
+
+			dgst, err := client.HeadManifest(ctx, "library/alpine", "latest")
+			require.NoError(t, err)
+			require.Equal(t, m.Digest, dgst)
+
+			_, err = client.GetManifest(ctx, "library/alpine", m.Digest.String())
+			require.NoError(t, err)
+
+			r, _, err := client.GetBlob(ctx, "library/alpine", digest.FromString(testBlob))
+			require.NoError(t, err)
+			content, err := io.ReadAll(r)
+			require.NoError(t, err)
+			require.NoError(t, r.Close())
+			require.Equal(t, testBlob, string(content))
+
+			_, err = client.GetManifest(ctx, "library/alpine", "missing")
+			require.ErrorIs(t, err, ErrNotFound)
+		})
+	}
+}
+
+func TestClientUnauthorized(t *testing.T) {
+	server := newTestRegistry(t, true)
+	client, err := NewClient(server.URL, Credentials{Username: "admin", Password: "wrong"})
+	require.NoError(t, err)
+
+	_, err = client.GetManifest(context.Background(), "library/alpine", "latest")
+	require.ErrorIs(t, err, ErrUnauthorized)
+}
+
+func TestNewClient(t *testing.T) {
+	for _, endpoint := range []string{"", "registry.example.com", "ftp://registry.example.com", "https://"} {
+		_, err := NewClient(endpoint, Credentials{})
+		require.ErrorIs(t, err, ErrInvalidEndpoint, endpoint)
+	}
+
+	client, err := NewClient("https://"+dockerHubHost+"/", Credentials{})
+	require.NoError(t, err)
+	require.Equal(t, "library/alpine", client.repositoryName("alpine"))
+	require.Equal(t, "bitnami/redis", client.repositoryName("bitnami/redis"))
+}
+
+func TestParseChallenge(t *testing.T) {
+	scheme, params := parseChallenge(`Bearer realm="https://auth.docker.io/token",service="registry.docker.io",scope="repository:library/alpine:pull,push"`)
+	require.Equal(t, "Bearer", scheme)
+	require.Equal(t, map[string]string{
+		"realm":   "https://auth.docker.io/token",
+		"service": "registry.docker.io",
+		"scope":   "repository:library/alpine:pull,push",
+	}, params)
+
+	scheme, params = parseChallenge(`Basic realm=registry`)
+	require.Equal(t, "Basic", scheme)
+	require.Equal(t, "registry", params["realm"])
+}
@@ -300,6 +300,10 @@ func (crr *ResumeRequest) Finish(ctx context.Context, r *http.Request, dgst *dig
 		log.Ctx(ctx).Err(err).Msg("save progress failed")
 		return err
 	}
+	// the blob is shared by the spaces, it's counted once in the space uploading it
+	if err = crr.artStore.Usages().AddBlob(ctx, crr.view.OwnerID, dgst.String(), dbBlob.Size); err != nil {
+		return err
+	}
 
 	now := time.Now().UnixMilli()
 	asset := types.ArtifactAsset{
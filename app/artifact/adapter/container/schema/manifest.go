@@ -39,6 +39,9 @@ type Descriptor struct {
 	// Platform describes the platform which the image in the manifest runs on.
 	// This should only be used when referring to a manifest.
 	Platform *v1.Platform `json:"platform,omitempty"`
+
+	// ArtifactType is the IANA media type of this artifact.
+	ArtifactType string `json:"artifactType,omitempty"`
 }
 
 type Kind string
@@ -54,6 +57,15 @@ type Manifest interface {
 	References() []Descriptor
 }
 
+// Referrer is implemented by the manifests support the subject field of OCI 1.1,
+// the subject is nil if the manifest does not refer to another one.
+type Referrer interface {
+	Manifest
+	GetSubject() *Descriptor
+	GetArtifactType() string
+	GetAnnotations() map[string]string
+}
+
 type ImageManifest interface {
 	Manifest
 	GetLayers() []Descriptor
@@ -29,12 +29,15 @@ func init() {
 	}
 }
 
-var _ schema.Manifest = (*Index)(nil)
+var _ schema.Referrer = (*Index)(nil)
 
 type Index struct {
 	specs.Versioned
-	MediaType string `json:"mediaType,omitempty"`
-	Manifests []schema.Descriptor
+	MediaType    string              `json:"mediaType,omitempty"`
+	ArtifactType string              `json:"artifactType,omitempty"`
+	Manifests    []schema.Descriptor `json:"manifests"`
+	Subject      *schema.Descriptor  `json:"subject,omitempty"`
+	Annotations  map[string]string   `json:"annotations,omitempty"`
 }
 
 func (i Index) GetMediaType() string {
@@ -47,6 +50,12 @@ func (i Index) GetKind() schema.Kind {
 
 func (i Index) References() []schema.Descriptor { return i.Manifests }
 
+func (i Index) GetSubject() *schema.Descriptor { return i.Subject }
+
+func (i Index) GetArtifactType() string { return i.ArtifactType }
+
+func (i Index) GetAnnotations() map[string]string { return i.Annotations }
+
 type DeserializedIndex struct {
 	Index
 }
@@ -29,10 +29,15 @@ func init() {
 	}
 }
 
+var _ schema.Referrer = (*Manifest)(nil)
+
 type Manifest struct {
 	specs.Versioned
 	MediaType string `json:"mediaType,omitempty"`
 
+	// ArtifactType is the IANA media type of the artifact this manifest describes.
+	ArtifactType string `json:"artifactType,omitempty"`
+
 	// Config references the image configuration as a blob.
 	Config schema.Descriptor `json:"config"`
 
@@ -40,6 +45,9 @@ type Manifest struct {
 	// configuration.
 	Layers []schema.Descriptor `json:"layers"`
 
+	// Subject references another manifest this manifest is an artifact of.
+	Subject *schema.Descriptor `json:"subject,omitempty"`
+
 	// Annotations contains arbitrary metadata for the image manifest.
 	Annotations map[string]string `json:"annotations,omitempty"`
 }
@@ -64,6 +72,22 @@ func (m Manifest) GetLayers() []schema.Descriptor {
 	return m.Layers
 }
 
+func (m Manifest) GetSubject() *schema.Descriptor {
+	return m.Subject
+}
+
+// GetArtifactType falls back to the config media type as the referrers api requires.
+func (m Manifest) GetArtifactType() string {
+	if m.ArtifactType != "" {
+		return m.ArtifactType
+	}
+	return m.Config.MediaType
+}
+
+func (m Manifest) GetAnnotations() map[string]string {
+	return m.Annotations
+}
+
 type DeserializedManifest struct {
 	Manifest
 }
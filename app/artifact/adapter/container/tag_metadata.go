@@ -24,7 +24,9 @@ type TagMetadataReader struct {
 }
 
 type TagMetadata struct {
-	Images []*ImageMeta `json:"images"`
+	Images     []*ImageMeta `json:"images"`
+	Downloads  int64        `json:"downloads"`
+	LastPulled int64        `json:"last_pulled"`
 }
 
 type ImageMeta struct {
@@ -0,0 +1,232 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package debian
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	_arMagic      = "!<arch>\n"
+	_arHeaderSize = 60
+
+	// _controlSizeLimit limits the control archive read into memory
+	_controlSizeLimit = 8 << 20
+)
+
+var (
+	// https://www.debian.org/doc/debian-policy/ch-controlfields.html#source
+	nameRegexp = regexp.MustCompile(`^[a-z0-9][a-z0-9+\-.]+$`)
+	// https://www.debian.org/doc/debian-policy/ch-controlfields.html#version
+	versionRegexp = regexp.MustCompile(`^(?:[0-9]+:)?[0-9][A-Za-z0-9.+~\-]*$`)
+	archRegexp    = regexp.MustCompile(`^[a-z0-9][a-z0-9\-]*$`)
+	// distRegexp validates the distribution and the component
+	distRegexp = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9\-_.]*$`)
+
+	// generatedFields are written by the repository into the Packages index
+	generatedFields = map[string]bool{
+		"filename": true, "size": true, "md5sum": true, "sha1": true, "sha256": true, "sha512": true,
+	}
+)
+
+// Field is a field of the control paragraph, the multiline value keeps the continuation lines.
+type Field struct {
+	Name  string
+	Value string
+}
+
+// Control is the control file of the binary package.
+type Control struct {
+	Package      string
+	Version      string
+	Architecture string
+	Description  string
+	Fields       []Field
+}
+
+// ParseDeb reads the control file from the control archive of the .deb package,
+// see https://manpages.debian.org/deb.5.
+func ParseDeb(r io.Reader) (*Control, error) {
+	magic := make([]byte, len(_arMagic))
+	if _, err := io.ReadFull(r, magic); err != nil || string(magic) != _arMagic {
+		return nil, errors.New("not a debian binary package")
+	}
+
+	header := make([]byte, _arHeaderSize)
+	for {
+		if _, err := io.ReadFull(r, header); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, errors.New("control archive not found")
+			}
+			return nil, err
+		}
+		name := strings.TrimSuffix(strings.TrimSpace(string(header[0:16])), "/")
+		size, err := strconv.ParseInt(strings.TrimSpace(string(header[48:58])), 10, 64)
+		if err != nil || size < 0 {
+			return nil, fmt.Errorf("invalid archive member size of %s", name)
+		}
+
+		if strings.HasPrefix(name, "control.tar") {
+			if size > _controlSizeLimit {
+				return nil, errors.New("control archive is too large")
+			}
+			return readControlArchive(name, io.LimitReader(r, size))
+		}
+
+		// members are aligned to even offsets
+		if _, err = io.CopyN(io.Discard, r, size+size%2); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func readControlArchive(name string, r io.Reader) (*Control, error) {
+	switch name {
+	case "control.tar":
+	case "control.tar.gz":
+		gr, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		defer gr.Close()
+		r = gr
+	default:
+		return nil, ErrUnsupportedArchive.WithDetail(name)
+	}
+
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("control file not found")
+		}
+		if err != nil {
+			return nil, err
+		}
+		if hdr.Typeflag != tar.TypeReg || path.Clean(hdr.Name) != "control" {
+			continue
+		}
+		return ParseControl(tr)
+	}
+}
+
+// ParseControl parses the control paragraph, the Package, Version and Architecture fields are required.
+func ParseControl(r io.Reader) (*Control, error) {
+	c := &Control{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			// only the first paragraph is read
+			if len(c.Fields) > 0 {
+				break
+			}
+			continue
+		}
+		if line[0] == ' ' || line[0] == '\t' {
+			if len(c.Fields) == 0 {
+				return nil, errors.New("invalid control continuation line")
+			}
+			c.Fields[len(c.Fields)-1].Value += "\n" + line
+			continue
+		}
+
+		name, value, ok := strings.Cut(line, ":")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid control line %q", line)
+		}
+		c.Fields = append(c.Fields, Field{Name: name, Value: strings.TrimSpace(value)})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, f := range c.Fields {
+		switch strings.ToLower(f.Name) {
+		case "package":
+			c.Package = f.Value
+		case "version":
+			c.Version = f.Value
+		case "architecture":
+			c.Architecture = f.Value
+		case "description":
+			c.Description, _, _ = strings.Cut(f.Value, "\n")
+		}
+	}
+
+	if !nameRegexp.MatchString(c.Package) {
+		return nil, fmt.Errorf("invalid package name %q", c.Package)
+	}
+	if !versionRegexp.MatchString(c.Version) {
+		return nil, fmt.Errorf("invalid package version %q", c.Version)
+	}
+	if !archRegexp.MatchString(c.Architecture) {
+		return nil, fmt.Errorf("invalid package architecture %q", c.Architecture)
+	}
+	return c, nil
+}
+
+// Paragraph formats the fields of the control, the fields generated by the repository are excluded.
+func (c *Control) Paragraph() string {
+	buf := bytes.NewBuffer(nil)
+	for _, f := range c.Fields {
+		if generatedFields[strings.ToLower(f.Name)] {
+			continue
+		}
+		buf.WriteString(f.Name + ": " + f.Value + "\n")
+	}
+	return buf.String()
+}
+
+// Filename is the conventional name of the package file, the epoch of the version is omitted.
+func (c *Control) Filename() string {
+	return c.Package + "_" + FileVersion(c.Version) + "_" + c.Architecture + ".deb"
+}
+
+// FileVersion strips the epoch of the version used in the file name.
+func FileVersion(version string) string {
+	if _, v, ok := strings.Cut(version, ":"); ok {
+		return v
+	}
+	return version
+}
+
+// ParseFilename splits the package file name into the name, the version without epoch and the architecture.
+func ParseFilename(filename string) (name, version, arch string, err error) {
+	base, ok := strings.CutSuffix(filename, ".deb")
+	if !ok {
+		return "", "", "", errors.New("invalid package file name")
+	}
+	parts := strings.Split(base, "_")
+	if len(parts) != 3 || !nameRegexp.MatchString(parts[0]) || !archRegexp.MatchString(parts[2]) {
+		return "", "", "", errors.New("invalid package file name")
+	}
+	return parts[0], parts[1], parts[2], nil
+}
+
+// ValidateDistribution checks the distribution and the component used in the repository paths.
+func ValidateDistribution(distribution, component string) error {
+	if !distRegexp.MatchString(distribution) || !distRegexp.MatchString(component) {
+		return ErrInvalidDistribution
+	}
+	return nil
+}
+
+// PoolPath is the path of the package file in the repository.
+func PoolPath(distribution, component, filename string) string {
+	return path.Join("pool", distribution, component, filename)
+}
@@ -0,0 +1,182 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+// Package debian implements the apt repository, the packages are uploaded into the pool of
+// a distribution and component, and the signed indexes are generated per distribution.
+package debian
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/easysoft/gitfox/app/artifact/adapter"
+	"github.com/easysoft/gitfox/app/artifact/adapter/request"
+	"github.com/easysoft/gitfox/app/services/protection"
+	"github.com/easysoft/gitfox/app/store"
+	"github.com/easysoft/gitfox/pkg/storage"
+	gitfox_store "github.com/easysoft/gitfox/store"
+	"github.com/easysoft/gitfox/types"
+)
+
+const _debContentType = "application/vnd.debian.binary-package"
+
+// AssetMetadata keeps the control paragraph of the package file to rebuild the Packages indexes.
+type AssetMetadata struct {
+	Distribution string `json:"distribution"`
+	Component    string `json:"component"`
+	Architecture string `json:"architecture"`
+	Control      string `json:"control"`
+}
+
+func (m *AssetMetadata) ToJSON() (json.RawMessage, error) {
+	return protection.ToJSON(m)
+}
+
+type VersionMetadata struct {
+	CreatorName string `json:"creator_name"`
+	Description string `json:"description,omitempty"`
+}
+
+func (m *VersionMetadata) ToJSON() (json.RawMessage, error) {
+	return protection.ToJSON(m)
+}
+
+type uploader struct {
+	uploadReq *request.ArtifactUploadRequest
+	artStore  store.ArtifactStore
+	store     storage.ContentStorage
+	view      *adapter.ViewDescriptor
+
+	distribution string
+	component    string
+
+	descriptor *adapter.PackageDescriptor
+	control    *Control
+	controlErr error
+}
+
+// NewUploader accepts the .deb file as the request body, the file is added to the pool of the distribution component.
+func NewUploader(contentStore storage.ContentStorage, artStore store.ArtifactStore, view *adapter.ViewDescriptor,
+	distribution, component string,
+) adapter.ArtifactPackageUploader {
+	return &uploader{
+		uploadReq:    request.NewUpload(view, artStore),
+		artStore:     artStore,
+		store:        contentStore,
+		view:         view,
+		distribution: distribution,
+		component:    component,
+		descriptor:   adapter.NewEmptyPackageDescriptor(),
+	}
+}
+
+func (h *uploader) Serve(ctx context.Context, req *http.Request) (int64, error) {
+	if err := ValidateDistribution(h.distribution, h.component); err != nil {
+		return 0, err
+	}
+
+	fw, ref, err := adapter.NewRandomBlobWriter(ctx, h.store)
+	if err != nil {
+		return 0, err
+	}
+
+	// the control archive is parsed as the package streams to the storage, the package isn't buffered.
+	pr, pw := io.Pipe()
+	parsed := make(chan struct{})
+	go func() {
+		defer close(parsed)
+		h.control, h.controlErr = ParseDeb(pr)
+		// drain the data archive, the writes to the pipe must not block
+		_, _ = io.Copy(io.Discard, pr)
+	}()
+
+	h.uploadReq.RegisterWriter(fw)
+	size, hash, err := request.Write(req.Body, fw, pw)
+	_ = pw.CloseWithError(err)
+	<-parsed
+
+	h.descriptor.MainAsset.Size = size
+	h.descriptor.MainAsset.Hash = hash
+	h.descriptor.MainAsset.Ref = ref
+	h.descriptor.MainAsset.ContentType = _debContentType
+	h.descriptor.MainAsset.Format = types.ArtifactDebianFormat
+	h.descriptor.MainAsset.Kind = types.AssetKindMain
+	h.descriptor.MainAsset.Attr = adapter.AttrAssetNormal
+	return size, err
+}
+
+func (h *uploader) IsValid(ctx context.Context) error {
+	control, err := h.control, h.controlErr
+	if errors.Is(err, ErrUnsupportedArchive) {
+		return err
+	} else if err != nil {
+		return adapter.ErrInvalidPackageContent.WithDetail(err.Error())
+	}
+
+	h.descriptor.Name = control.Package
+	h.descriptor.Namespace = ""
+	h.descriptor.Version = control.Version
+	h.descriptor.Format = types.ArtifactDebianFormat
+	h.descriptor.MainAsset.Path = PoolPath(h.distribution, h.component, control.Filename())
+	h.descriptor.MainAsset.Metadata = &AssetMetadata{
+		Distribution: h.distribution,
+		Component:    h.component,
+		Architecture: control.Architecture,
+		Control:      control.Paragraph(),
+	}
+
+	if err = h.checkExists(ctx); err != nil {
+		return err
+	}
+
+	u := h.uploadReq.LoadCreator(ctx)
+	h.descriptor.VersionMetadata = &VersionMetadata{CreatorName: u.UID, Description: control.Description}
+	h.uploadReq.Descriptor = h.descriptor
+	return nil
+}
+
+func (h *uploader) Save(ctx context.Context) error {
+	if err := h.uploadReq.Commit(ctx); err != nil {
+		_ = h.uploadReq.Cancel(ctx)
+		return err
+	}
+	return nil
+}
+
+func (h *uploader) Cancel(ctx context.Context) error {
+	return h.uploadReq.Cancel(ctx)
+}
+
+// checkExists rejects overwriting a package file, apt clients cache the files by the hashes in the index
+func (h *uploader) checkExists(ctx context.Context) error {
+	pkg, err := h.artStore.Packages().GetByName(ctx, h.descriptor.Name, "", h.view.OwnerID, types.ArtifactDebianFormat)
+	if errors.Is(err, gitfox_store.ErrResourceNotFound) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	ver, err := h.artStore.Versions().GetByVersion(ctx, pkg.ID, h.view.ViewID, h.descriptor.Version)
+	if errors.Is(err, gitfox_store.ErrResourceNotFound) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if ver.IsDeleted() {
+		return nil
+	}
+
+	asset, err := h.artStore.Assets().GetVersionAsset(ctx, h.descriptor.MainAsset.Path, ver.ID)
+	if err == nil && asset.Deleted == 0 {
+		return ErrFileExists
+	} else if err != nil && !errors.Is(err, gitfox_store.ErrResourceNotFound) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,258 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package debian_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/easysoft/gitfox/app/artifact/adapter"
+	"github.com/easysoft/gitfox/app/artifact/adapter/debian"
+	"github.com/easysoft/gitfox/app/artifact/adapter/testsuite"
+	"github.com/easysoft/gitfox/types"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/clearsign"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const _control = `Package: demo-tool
+Version: 1:1.2.0-1
+Architecture: %s
+Maintainer: Dev <dev@example.com>
+Depends: libc6 (>= 2.31)
+Description: A demo tool
+ The demo tool does nothing.
+ .
+ It is only used in tests.
+`
+
+type DebianSuite struct {
+	testsuite.BaseSuite
+}
+
+func TestDebianSuite(t *testing.T) {
+	ctx := context.Background()
+
+	st := &DebianSuite{
+		BaseSuite: testsuite.BaseSuite{
+			Ctx:  ctx,
+			Name: "debian",
+		},
+	}
+
+	st.BaseSuite.Constructor = func(ts *testsuite.TestStore) {
+	}
+
+	suite.Run(t, st)
+}
+
+func (suite *DebianSuite) TestUploadAndIndex() {
+	invalidTests := []struct {
+		distribution string
+		component    string
+		content      []byte
+		expectErr    error
+	}{
+		{distribution: "../stable", component: "main", content: buildDeb(suite.T(), "amd64", "control.tar.gz"), expectErr: debian.ErrInvalidDistribution},
+		{distribution: "stable", component: "", content: buildDeb(suite.T(), "amd64", "control.tar.gz"), expectErr: debian.ErrInvalidDistribution},
+		{distribution: "stable", component: "main", content: []byte("not a deb"), expectErr: adapter.ErrInvalidPackageContent},
+		{distribution: "stable", component: "main", content: buildDeb(suite.T(), "amd64", "control.tar.xz"), expectErr: debian.ErrUnsupportedArchive},
+	}
+	for idx, test := range invalidTests {
+		err := suite.upload(test.distribution, test.component, test.content)
+		require.ErrorContains(suite.T(), err, test.expectErr.Error(), "loop id: %d", idx)
+	}
+
+	require.NoError(suite.T(), suite.upload("stable", "main", buildDeb(suite.T(), "amd64", "control.tar.gz")))
+	require.NoError(suite.T(), suite.upload("stable", "main", buildDeb(suite.T(), "arm64", "control.tar")))
+	require.NoError(suite.T(), suite.upload("stable", "contrib", buildDeb(suite.T(), "all", "control.tar.gz")))
+	err := suite.upload("stable", "main", buildDeb(suite.T(), "amd64", "control.tar.gz"))
+	require.ErrorContains(suite.T(), err, debian.ErrFileExists.Error())
+
+	pkg, err := suite.Store.Artifacts.Packages().GetByName(suite.Ctx, "demo-tool", "", suite.DefaultView.OwnerID, types.ArtifactDebianFormat)
+	require.NoError(suite.T(), err)
+	ver, err := suite.Store.Artifacts.Versions().GetByVersion(suite.Ctx, pkg.ID, suite.DefaultView.ViewID, "1:1.2.0-1")
+	require.NoError(suite.T(), err)
+	_, err = suite.Store.Artifacts.Assets().GetVersionAsset(suite.Ctx, "pool/stable/main/demo-tool_1.2.0-1_arm64.deb", ver.ID)
+	require.NoError(suite.T(), err)
+
+	key, err := debian.GenerateKey("demo")
+	require.NoError(suite.T(), err)
+	require.NoError(suite.T(), debian.NewIndex(suite.Store.Artifacts, suite.DefaultView, key).UpdateRepo(suite.Ctx))
+
+	packages := suite.readIndex("dists/stable/main/binary-amd64/Packages")
+	require.Contains(suite.T(), packages, "Package: demo-tool\nVersion: 1:1.2.0-1\nArchitecture: amd64\n")
+	require.Contains(suite.T(), packages, " .\n It is only used in tests.\nFilename: pool/stable/main/demo-tool_1.2.0-1_amd64.deb\n")
+	require.NotContains(suite.T(), packages, "arm64")
+
+	// the package of the 'all' architecture is listed for every architecture
+	packages = suite.readIndex("dists/stable/contrib/binary-arm64/Packages")
+	require.Contains(suite.T(), packages, "Filename: pool/stable/contrib/demo-tool_1.2.0-1_all.deb\n")
+
+	release := suite.readIndex("dists/stable/Release")
+	require.Contains(suite.T(), release, "Architectures: amd64 arm64\nComponents: contrib main\n")
+	require.Contains(suite.T(), release, " main/binary-arm64/Packages.gz\n")
+
+	keyring := openpgp.EntityList{key}
+	_, err = openpgp.CheckArmoredDetachedSignature(keyring, strings.NewReader(release),
+		strings.NewReader(suite.readIndex("dists/stable/Release.gpg")), nil)
+	require.NoError(suite.T(), err)
+
+	block, _ := clearsign.Decode([]byte(suite.readIndex("dists/stable/InRelease")))
+	require.NotNil(suite.T(), block)
+	require.Equal(suite.T(), release, string(block.Plaintext))
+	_, err = openpgp.CheckDetachedSignature(keyring, bytes.NewReader(block.Bytes), block.ArmoredSignature.Body, nil)
+	require.NoError(suite.T(), err)
+}
+
+func (suite *DebianSuite) upload(distribution, component string, content []byte) error {
+	req, err := http.NewRequest(http.MethodPut, "http://localhost:8080", bytes.NewReader(content))
+	require.NoError(suite.T(), err)
+
+	uploader := debian.NewUploader(suite.ArtifactStore, suite.Store.Artifacts, suite.DefaultView, distribution, component)
+	if _, err = uploader.Serve(suite.Ctx, req); err != nil {
+		return err
+	}
+	if err = uploader.IsValid(suite.Ctx); err != nil {
+		return err
+	}
+	return uploader.Save(suite.Ctx)
+}
+
+func (suite *DebianSuite) readIndex(path string) string {
+	asset, err := suite.Store.Artifacts.Assets().GetMetaAsset(suite.Ctx, path, suite.DefaultView.ViewID, types.ArtifactDebianFormat)
+	require.NoError(suite.T(), err, path)
+	blob, err := suite.Store.Artifacts.Blobs().GetById(suite.Ctx, asset.BlobID)
+	require.NoError(suite.T(), err)
+	r, err := suite.ArtifactStore.Open(suite.Ctx, adapter.BlobPath(blob.Ref))
+	require.NoError(suite.T(), err)
+	defer r.Close()
+	content, err := io.ReadAll(r)
+	require.NoError(suite.T(), err)
+	return string(content)
+}
+
+// buildDeb creates a package with the control archive, the compression of the archive isn't applied for xz
+func buildDeb(t *testing.T, arch, controlName string) []byte {
+	control := fmt.Sprintf(_control, arch)
+	tarBuf := bytes.NewBuffer(nil)
+	tw := tar.NewWriter(tarBuf)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "./", Mode: 0o755, Typeflag: tar.TypeDir}))
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "./control", Mode: 0o644, Size: int64(len(control)), Typeflag: tar.TypeReg}))
+	_, err := tw.Write([]byte(control))
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+
+	controlArchive := tarBuf.Bytes()
+	if controlName == "control.tar.gz" {
+		gzBuf := bytes.NewBuffer(nil)
+		gw := gzip.NewWriter(gzBuf)
+		_, err = gw.Write(controlArchive)
+		require.NoError(t, err)
+		require.NoError(t, gw.Close())
+		controlArchive = gzBuf.Bytes()
+	}
+
+	buf := bytes.NewBufferString("!<arch>\n")
+	for _, member := range []struct {
+		name    string
+		content []byte
+	}{
+		{"debian-binary", []byte("2.0\n")},
+		{controlName, controlArchive},
+		{"data.tar.gz", []byte("data")},
+	} {
+		fmt.Fprintf(buf, "%-16s%-12d%-6d%-6d%-8s%-10d`\n", member.name, 0, 0, 0, "100644", len(member.content))
+		buf.Write(member.content)
+		if len(member.content)%2 == 1 {
+			buf.WriteString("\n")
+		}
+	}
+	return buf.Bytes()
+}
+
+func TestParseControl(t *testing.T) {
+	control, err := debian.ParseControl(strings.NewReader(fmt.Sprintf(_control, "amd64") + "\nPackage: ignored\n"))
+	require.NoError(t, err)
+	require.Equal(t, "demo-tool", control.Package)
+	require.Equal(t, "1:1.2.0-1", control.Version)
+	require.Equal(t, "A demo tool", control.Description)
+	require.Equal(t, "demo-tool_1.2.0-1_amd64.deb", control.Filename())
+	require.Equal(t, fmt.Sprintf(_control, "amd64"), control.Paragraph())
+
+	for _, invalid := range []string{
+		"Package: Demo\nVersion: 1.0\nArchitecture: amd64\n",
+		"Package: demo\nVersion: v1.0\nArchitecture: amd64\n",
+		"Package: demo\nVersion: 1.0\n",
+		" continuation\nPackage: demo\n",
+	} {
+		_, err = debian.ParseControl(strings.NewReader(invalid))
+		require.Error(t, err, invalid)
+	}
+}
+
+func TestParseFilename(t *testing.T) {
+	name, version, arch, err := debian.ParseFilename("demo-tool_1.2.0-1_amd64.deb")
+	require.NoError(t, err)
+	require.Equal(t, []string{"demo-tool", "1.2.0-1", "amd64"}, []string{name, version, arch})
+
+	for _, invalid := range []string{"demo-tool_1.2.0_amd64.tar.gz", "demo-tool_amd64.deb", "../demo_1.0_amd64.deb"} {
+		_, _, _, err = debian.ParseFilename(invalid)
+		require.Error(t, err, invalid)
+	}
+}
+
+func TestBuildIndex(t *testing.T) {
+	dist := debian.NewDistribution("stable")
+	dist.Add(&debian.Entry{
+		Path: "pool/stable/main/demo_1.0_all.deb",
+		Size: 10,
+		Hash: adapter.Hash{Md5: "m", Sha1: "s1", Sha256: "s256", Sha512: "s512"},
+		Meta: debian.AssetMetadata{Distribution: "stable", Component: "main", Architecture: "all",
+			Control: "Package: demo\nVersion: 1.0\nArchitecture: all\n"},
+	})
+
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	files, err := debian.BuildIndex(dist, "space", now)
+	require.NoError(t, err)
+	require.Len(t, files, 3)
+	require.Equal(t, "Release", files[0].Path)
+	require.Equal(t, "main/binary-all/Packages", files[1].Path)
+	require.Equal(t, "Package: demo\nVersion: 1.0\nArchitecture: all\nFilename: pool/stable/main/demo_1.0_all.deb\n"+
+		"Size: 10\nMD5sum: m\nSHA1: s1\nSHA256: s256\n", string(files[1].Content))
+
+	release := string(files[0].Content)
+	require.True(t, strings.HasPrefix(release, "Origin: space\nLabel: space\nSuite: stable\nCodename: stable\n"+
+		"Date: Tue, 02 Jan 2024 03:04:05 UTC\nArchitectures: all\nComponents: main\nMD5Sum:\n"), release)
+	require.Contains(t, release, fmt.Sprintf(" %d main/binary-all/Packages.gz\n", len(files[2].Content)))
+}
+
+func TestReadKey(t *testing.T) {
+	key, err := debian.GenerateKey("demo")
+	require.NoError(t, err)
+	armored, err := debian.ArmorPrivateKey(key)
+	require.NoError(t, err)
+
+	imported, err := debian.ReadKey(armored, "")
+	require.NoError(t, err)
+	require.Equal(t, debian.Fingerprint(key), debian.Fingerprint(imported))
+
+	public, err := debian.ArmorPublicKey(key)
+	require.NoError(t, err)
+	_, err = debian.ReadKey(public, "")
+	require.ErrorContains(t, err, debian.ErrSigningKeyInvalid.Error())
+}
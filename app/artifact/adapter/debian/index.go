@@ -0,0 +1,340 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package debian
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/easysoft/gitfox/app/artifact/adapter"
+	"github.com/easysoft/gitfox/app/artifact/adapter/request"
+	"github.com/easysoft/gitfox/app/store"
+	"github.com/easysoft/gitfox/types"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	ContentTypeText      = "text/plain; charset=utf-8"
+	ContentTypeGzip      = "application/gzip"
+	ContentTypeSignature = "application/pgp-signature"
+
+	_archAll = "all"
+)
+
+// Entry is a package file listed in the Packages index.
+type Entry struct {
+	Path string
+	Size int64
+	Hash adapter.Hash
+	Meta AssetMetadata
+}
+
+// Distribution collects the package files of the components, the architectures emptied by
+// the removals are kept to overwrite their previous indexes.
+type Distribution struct {
+	Name string
+	// Components maps the component to the entries by architecture
+	Components map[string]map[string][]*Entry
+}
+
+func NewDistribution(name string) *Distribution {
+	return &Distribution{Name: name, Components: make(map[string]map[string][]*Entry)}
+}
+
+func (d *Distribution) touch(component, arch string) map[string][]*Entry {
+	archs, ok := d.Components[component]
+	if !ok {
+		archs = make(map[string][]*Entry)
+		d.Components[component] = archs
+	}
+	if _, ok = archs[arch]; !ok {
+		archs[arch] = nil
+	}
+	return archs
+}
+
+// Add lists the package file in the index of its component and architecture.
+func (d *Distribution) Add(e *Entry) {
+	archs := d.touch(e.Meta.Component, e.Meta.Architecture)
+	archs[e.Meta.Architecture] = append(archs[e.Meta.Architecture], e)
+}
+
+// Architectures returns the architectures of the distribution, the packages of the 'all' architecture
+// are listed in the index of every architecture, they have an own index only in a distribution without others.
+func (d *Distribution) Architectures() []string {
+	set := make(map[string]bool)
+	for _, archs := range d.Components {
+		for arch := range archs {
+			if arch != _archAll {
+				set[arch] = true
+			}
+		}
+	}
+	if len(set) == 0 {
+		return []string{_archAll}
+	}
+	return sortedKeys(set)
+}
+
+// IndexFile is a generated file of the distribution, Path is relative to 'dists/{distribution}'.
+type IndexFile struct {
+	Path        string
+	Content     []byte
+	ContentType string
+}
+
+// BuildIndex generates the Packages indexes of the distribution and the Release file listing them, unsigned.
+func BuildIndex(d *Distribution, origin string, now time.Time) ([]*IndexFile, error) {
+	archs := d.Architectures()
+	components := sortedKeys(d.Components)
+
+	files := make([]*IndexFile, 0, len(components)*len(archs)*2+1)
+	for _, component := range components {
+		for _, arch := range archs {
+			entries := d.Components[component][arch]
+			if arch != _archAll {
+				entries = append(entries, d.Components[component][_archAll]...)
+			}
+			packages := buildPackages(entries)
+			compressed, err := gzipContent(packages)
+			if err != nil {
+				return nil, err
+			}
+
+			dir := path.Join(component, "binary-"+arch)
+			files = append(files,
+				&IndexFile{Path: path.Join(dir, "Packages"), Content: packages, ContentType: ContentTypeText},
+				&IndexFile{Path: path.Join(dir, "Packages.gz"), Content: compressed, ContentType: ContentTypeGzip},
+			)
+		}
+	}
+
+	release := buildRelease(d.Name, origin, now, archs, components, files)
+	return append([]*IndexFile{{Path: "Release", Content: release, ContentType: ContentTypeText}}, files...), nil
+}
+
+func buildPackages(entries []*Entry) []byte {
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Path < entries[j].Path
+	})
+
+	buf := bytes.NewBuffer(nil)
+	for i, e := range entries {
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+		buf.WriteString(strings.TrimRight(e.Meta.Control, "\n") + "\n")
+		fmt.Fprintf(buf, "Filename: %s\n", e.Path)
+		fmt.Fprintf(buf, "Size: %d\n", e.Size)
+		fmt.Fprintf(buf, "MD5sum: %s\n", e.Hash.Md5)
+		fmt.Fprintf(buf, "SHA1: %s\n", e.Hash.Sha1)
+		fmt.Fprintf(buf, "SHA256: %s\n", e.Hash.Sha256)
+	}
+	return buf.Bytes()
+}
+
+// buildRelease writes the Release file, see https://wiki.debian.org/DebianRepository/Format#A.22Release.22_files
+func buildRelease(name, origin string, now time.Time, archs, components []string, files []*IndexFile) []byte {
+	buf := bytes.NewBuffer(nil)
+	// an empty field would end with a trailing space, which is stripped from the clear signed InRelease
+	if origin != "" {
+		fmt.Fprintf(buf, "Origin: %s\n", origin)
+		fmt.Fprintf(buf, "Label: %s\n", origin)
+	}
+	fmt.Fprintf(buf, "Suite: %s\n", name)
+	fmt.Fprintf(buf, "Codename: %s\n", name)
+	fmt.Fprintf(buf, "Date: %s\n", now.UTC().Format(time.RFC1123))
+	fmt.Fprintf(buf, "Architectures: %s\n", strings.Join(archs, " "))
+	fmt.Fprintf(buf, "Components: %s\n", strings.Join(components, " "))
+
+	hashes := make([]*adapter.Hash, len(files))
+	for i, f := range files {
+		hw := adapter.NewHashWriter()
+		_, _ = hw.Write(f.Content)
+		hashes[i] = hw.Sum()
+	}
+	for _, section := range []struct {
+		name string
+		hash func(h *adapter.Hash) string
+	}{
+		{"MD5Sum", func(h *adapter.Hash) string { return h.Md5 }},
+		{"SHA1", func(h *adapter.Hash) string { return h.Sha1 }},
+		{"SHA256", func(h *adapter.Hash) string { return h.Sha256 }},
+		{"SHA512", func(h *adapter.Hash) string { return h.Sha512 }},
+	} {
+		buf.WriteString(section.name + ":\n")
+		for i, f := range files {
+			fmt.Fprintf(buf, " %s %d %s\n", section.hash(hashes[i]), len(f.Content), f.Path)
+		}
+	}
+	return buf.Bytes()
+}
+
+func gzipContent(content []byte) ([]byte, error) {
+	buf := bytes.NewBuffer(nil)
+	gw := gzip.NewWriter(buf)
+	if _, err := gw.Write(content); err != nil {
+		return nil, err
+	}
+	if err := gw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+type debianIndex struct {
+	artStore store.ArtifactStore
+	view     *adapter.ViewDescriptor
+	signer   *openpgp.Entity
+}
+
+func NewIndex(artStore store.ArtifactStore, view *adapter.ViewDescriptor, signer *openpgp.Entity) *debianIndex {
+	return &debianIndex{artStore: artStore, view: view, signer: signer}
+}
+
+// UpdateRepo regenerates and signs the indexes of all distributions in the view.
+func (d *debianIndex) UpdateRepo(ctx context.Context) error {
+	dists, err := d.collect(ctx)
+	if err != nil {
+		return err
+	}
+
+	origin := ""
+	if d.view.Space != nil {
+		origin = d.view.Space.Path
+	}
+	now := time.Now()
+	for _, name := range sortedKeys(dists) {
+		files, e := BuildIndex(dists[name], origin, now)
+		if e != nil {
+			return e
+		}
+		if e = d.commit(ctx, name, files); e != nil {
+			return fmt.Errorf("write index of distribution %s failed: %w", name, e)
+		}
+	}
+	return nil
+}
+
+// collect walks the package files of the view, the removed files only keep their distributions listed.
+func (d *debianIndex) collect(ctx context.Context) (map[string]*Distribution, error) {
+	logger := log.Ctx(ctx)
+
+	pkgs, err := d.artStore.Packages().ListByNamespace(ctx, d.view.OwnerID, "", types.ArtifactDebianFormat, false, true)
+	if err != nil {
+		return nil, err
+	}
+
+	dists := make(map[string]*Distribution)
+	for _, pkg := range pkgs {
+		versions, e := adapter.ListVersions(ctx, d.artStore, types.SearchVersionOption{
+			PackageId: pkg.ID, ViewId: d.view.ViewID, IncludeDeleted: true,
+		})
+		if e != nil {
+			return nil, e
+		}
+		for _, ver := range versions {
+			assets, e := d.artStore.Assets().SearchExtendBlob(ctx,
+				types.SearchAssetOption{Kind: types.AssetKindMain, VersionId: ver.ID})
+			if e != nil {
+				return nil, e
+			}
+			for _, asset := range assets {
+				var meta AssetMetadata
+				if e = json.Unmarshal([]byte(asset.Metadata), &meta); e != nil {
+					logger.Warn().Err(e).Msgf("ignore invalid debian metadata of %s", asset.Path)
+					continue
+				}
+				dist, ok := dists[meta.Distribution]
+				if !ok {
+					dist = NewDistribution(meta.Distribution)
+					dists[meta.Distribution] = dist
+				}
+				if pkg.IsDeleted() || ver.IsDeleted() || asset.Deleted != 0 {
+					dist.touch(meta.Component, meta.Architecture)
+					continue
+				}
+
+				var hash adapter.Hash
+				if e = json.Unmarshal([]byte(asset.CheckSum), &hash); e != nil {
+					logger.Warn().Msgf("ignore invalid checksum asset of %s", asset.Path)
+					continue
+				}
+				dist.Add(&Entry{Path: asset.Path, Size: asset.Size, Hash: hash, Meta: meta})
+			}
+		}
+	}
+	return dists, nil
+}
+
+// commit signs the Release file and saves the index files of the distribution as the meta assets of the view.
+func (d *debianIndex) commit(ctx context.Context, name string, files []*IndexFile) error {
+	inRelease, detached, err := SignRelease(d.signer, files[0].Content)
+	if err != nil {
+		return err
+	}
+	files = append(files,
+		&IndexFile{Path: "InRelease", Content: inRelease, ContentType: ContentTypeText},
+		&IndexFile{Path: "Release.gpg", Content: detached, ContentType: ContentTypeSignature},
+	)
+
+	indexReq := request.NewIndex(d.view, d.artStore)
+	descriptor := adapter.NewEmptyPackageMetaDescriptor()
+	descriptor.Format = types.ArtifactDebianFormat
+	for i, f := range files {
+		fw, ref, e := adapter.NewRandomBlobWriter(ctx, d.view.Store)
+		if e != nil {
+			_ = indexReq.Cancel(ctx)
+			return e
+		}
+		indexReq.RegisterWriter(fw)
+		size, hash, e := request.Write(bytes.NewReader(f.Content), fw)
+		if e != nil {
+			_ = indexReq.Cancel(ctx)
+			return e
+		}
+
+		asset := &adapter.AssetDescriptor{
+			Path:        path.Join("dists", name, f.Path),
+			Ref:         ref,
+			Size:        size,
+			Hash:        hash,
+			ContentType: f.ContentType,
+			Format:      types.ArtifactDebianFormat,
+		}
+		if i == 0 {
+			descriptor.MainAsset = asset
+		} else {
+			descriptor.AddSub(asset)
+		}
+	}
+
+	indexReq.Descriptor = descriptor
+	if err = indexReq.Commit(ctx); err != nil {
+		_ = indexReq.Cancel(ctx)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package debian
+
+import (
+	"bytes"
+	"crypto"
+	"fmt"
+	"strings"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/ProtonMail/go-crypto/openpgp/clearsign"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+)
+
+const _keyBits = 3072
+
+var signConfig = &packet.Config{DefaultHash: crypto.SHA256}
+
+// GenerateKey creates the rsa key signing the repository of the space.
+func GenerateKey(name string) (*openpgp.Entity, error) {
+	return openpgp.NewEntity(name, "Artifact Signing Key", "", &packet.Config{
+		DefaultHash: crypto.SHA256,
+		RSABits:     _keyBits,
+	})
+}
+
+// ReadKey reads the armored private key, the encrypted key is decrypted by the passphrase.
+func ReadKey(armored, passphrase string) (*openpgp.Entity, error) {
+	entities, err := openpgp.ReadArmoredKeyRing(strings.NewReader(armored))
+	if err != nil {
+		return nil, ErrSigningKeyInvalid.WithDetail(err.Error())
+	}
+	if len(entities) != 1 {
+		return nil, ErrSigningKeyInvalid.WithDetail("exactly one key is required")
+	}
+
+	entity := entities[0]
+	if entity.PrivateKey == nil {
+		return nil, ErrSigningKeyInvalid.WithDetail("private key is required")
+	}
+	keys := []*packet.PrivateKey{entity.PrivateKey}
+	for _, subkey := range entity.Subkeys {
+		if subkey.PrivateKey != nil {
+			keys = append(keys, subkey.PrivateKey)
+		}
+	}
+	for _, key := range keys {
+		if !key.Encrypted {
+			continue
+		}
+		if err = key.Decrypt([]byte(passphrase)); err != nil {
+			return nil, ErrSigningKeyInvalid.WithDetail("failed to decrypt private key")
+		}
+	}
+	return entity, nil
+}
+
+// ArmorPrivateKey exports the decrypted private key.
+func ArmorPrivateKey(entity *openpgp.Entity) (string, error) {
+	buf := bytes.NewBuffer(nil)
+	w, err := armor.Encode(buf, openpgp.PrivateKeyType, nil)
+	if err != nil {
+		return "", err
+	}
+	if err = entity.SerializePrivate(w, signConfig); err != nil {
+		return "", err
+	}
+	if err = w.Close(); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// ArmorPublicKey exports the public key imported by the apt clients.
+func ArmorPublicKey(entity *openpgp.Entity) (string, error) {
+	buf := bytes.NewBuffer(nil)
+	w, err := armor.Encode(buf, openpgp.PublicKeyType, nil)
+	if err != nil {
+		return "", err
+	}
+	if err = entity.Serialize(w); err != nil {
+		return "", err
+	}
+	if err = w.Close(); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// Fingerprint is the hex fingerprint of the primary key.
+func Fingerprint(entity *openpgp.Entity) string {
+	return fmt.Sprintf("%X", entity.PrimaryKey.Fingerprint)
+}
+
+// SignRelease signs the Release file, it returns the clear signed InRelease and the detached Release.gpg.
+func SignRelease(entity *openpgp.Entity, release []byte) ([]byte, []byte, error) {
+	inRelease := bytes.NewBuffer(nil)
+	w, err := clearsign.Encode(inRelease, entity.PrivateKey, signConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, err = w.Write(release); err != nil {
+		return nil, nil, err
+	}
+	if err = w.Close(); err != nil {
+		return nil, nil, err
+	}
+
+	detached := bytes.NewBuffer(nil)
+	if err = openpgp.ArmoredDetachSign(detached, entity, bytes.NewReader(release), signConfig); err != nil {
+		return nil, nil, err
+	}
+	return inRelease.Bytes(), detached.Bytes(), nil
+}
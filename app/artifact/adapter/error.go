@@ -18,6 +18,8 @@ var (
 	ErrInvalidPackageVersion    = usererror.BadRequest("invalid package version")
 	ErrInvalidGroupName         = usererror.BadRequest("invalid group name")
 	ErrInvalidPackageContent    = usererror.BadRequest("invalid package content")
+	ErrInvalidPackagePath       = usererror.BadRequest("invalid package path")
+	ErrChecksumMismatch         = usererror.BadRequest("checksum mismatch")
 	ErrStorageFileAlreadyExists = usererror.BadRequest("storage file already exists")
 	ErrStorageFileNotChanged    = usererror.BadRequest("storage file not changed")
 )
@@ -0,0 +1,214 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+// Package goproxy implements the GOPROXY protocol for the go modules in the git repositories,
+// see https://go.dev/ref/mod#goproxy-protocol.
+package goproxy
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/easysoft/gitfox/app/api/usererror"
+
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+type RequestKind string
+
+const (
+	RequestList   RequestKind = "list"
+	RequestLatest RequestKind = "latest"
+	RequestInfo   RequestKind = "info"
+	RequestMod    RequestKind = "mod"
+	RequestZip    RequestKind = "zip"
+)
+
+var (
+	ErrInvalidRequest = usererror.NotFound("invalid go module proxy request")
+	ErrVersionUnknown = usererror.NotFound("unknown module version")
+
+	// queryRegexp limits the queries resolved as git revisions, e.g. branch names or commit hashes
+	queryRegexp = regexp.MustCompile(`^[A-Za-z0-9_.][A-Za-z0-9_./\-]*$`)
+)
+
+// Request is a parsed GOPROXY request, Version is set for the info, mod and zip requests.
+type Request struct {
+	Module  string
+	Kind    RequestKind
+	Version string
+}
+
+// ParseRequest parses the request path relative to the proxy root,
+// like 'example.com/space/repo/@v/v1.0.0.info'.
+func ParseRequest(p string) (*Request, error) {
+	p = strings.TrimPrefix(p, "/")
+
+	if escaped, ok := strings.CutSuffix(p, "/@latest"); ok {
+		modPath, err := module.UnescapePath(escaped)
+		if err != nil {
+			return nil, ErrInvalidRequest.WithDetail(err.Error())
+		}
+		return &Request{Module: modPath, Kind: RequestLatest}, nil
+	}
+
+	escaped, file, ok := strings.Cut(p, "/@v/")
+	if !ok {
+		return nil, ErrInvalidRequest
+	}
+	modPath, err := module.UnescapePath(escaped)
+	if err != nil {
+		return nil, ErrInvalidRequest.WithDetail(err.Error())
+	}
+	if file == "list" {
+		return &Request{Module: modPath, Kind: RequestList}, nil
+	}
+
+	for _, kind := range []RequestKind{RequestInfo, RequestMod, RequestZip} {
+		escapedVer, ok := strings.CutSuffix(file, "."+string(kind))
+		if !ok {
+			continue
+		}
+		version, err := module.UnescapeVersion(escapedVer)
+		if err != nil {
+			return nil, ErrInvalidRequest.WithDetail(err.Error())
+		}
+		// only the info request resolves a query, the others require a canonical version
+		if kind != RequestInfo && semver.Canonical(version) != version {
+			return nil, ErrVersionUnknown.WithDetail(version)
+		}
+		return &Request{Module: modPath, Kind: kind, Version: version}, nil
+	}
+	return nil, ErrInvalidRequest
+}
+
+// Module locates a go module in a repository, the module is in the Dir of the repository,
+// and its versions are tagged like '<Dir>/v1.2.3'.
+type Module struct {
+	Path     string
+	RepoPath string
+	Dir      string
+	// PathMajor is the major version suffix of the module path like '/v2'
+	PathMajor string
+}
+
+// Candidates lists the possible locations of the module, the module path starts with the host,
+// which is followed by the repository path and the directory. The longest repository path comes first.
+func Candidates(modPath string) ([]*Module, error) {
+	if err := module.CheckPath(modPath); err != nil {
+		return nil, ErrInvalidRequest.WithDetail(err.Error())
+	}
+	prefix, pathMajor, ok := module.SplitPathVersion(modPath)
+	if !ok {
+		return nil, ErrInvalidRequest.WithDetail("invalid major version suffix")
+	}
+
+	elems := strings.Split(prefix, "/")[1:]
+	candidates := make([]*Module, 0, len(elems))
+	// a repository is at least in one space
+	for i := len(elems); i >= 2; i-- {
+		candidates = append(candidates, &Module{
+			Path:      modPath,
+			RepoPath:  strings.Join(elems[:i], "/"),
+			Dir:       strings.Join(elems[i:], "/"),
+			PathMajor: pathMajor,
+		})
+	}
+	return candidates, nil
+}
+
+// TagPrefix is the prefix of the version tags of the module.
+func (m *Module) TagPrefix() string {
+	if m.Dir == "" {
+		return ""
+	}
+	return m.Dir + "/"
+}
+
+// TagVersion returns the module version of the tag, false if the tag isn't a version of the module.
+func (m *Module) TagVersion(tag string) (string, bool) {
+	version, ok := strings.CutPrefix(tag, m.TagPrefix())
+	if !ok || semver.Canonical(version) != version || semver.Build(version) != "" {
+		return "", false
+	}
+	if module.CheckPathMajor(version, m.PathMajor) != nil {
+		return "", false
+	}
+	return version, true
+}
+
+// Tag returns the tag name of the version.
+func (m *Module) Tag(version string) string {
+	return m.TagPrefix() + version
+}
+
+// PseudoVersion returns the pseudo-version of the untagged commit.
+func (m *Module) PseudoVersion(t time.Time, commitSHA string) string {
+	rev := commitSHA
+	if len(rev) > 12 {
+		rev = rev[:12]
+	}
+	return module.PseudoVersion(module.PathMajorPrefix(m.PathMajor), "", t, rev)
+}
+
+// GoModPath is the path of the go.mod file of the module in the repository.
+func (m *Module) GoModPath() string {
+	if m.Dir == "" {
+		return "go.mod"
+	}
+	return m.Dir + "/go.mod"
+}
+
+// SortVersions sorts the versions ascending in the semver order.
+func SortVersions(versions []string) {
+	sort.Slice(versions, func(i, j int) bool {
+		return semver.Compare(versions[i], versions[j]) < 0
+	})
+}
+
+// LatestVersion returns the highest release version, or the highest pre-release version
+// if no release is tagged.
+func LatestVersion(versions []string) string {
+	var latest, latestPre string
+	for _, v := range versions {
+		if semver.Prerelease(v) == "" {
+			if latest == "" || semver.Compare(v, latest) > 0 {
+				latest = v
+			}
+		} else if latestPre == "" || semver.Compare(v, latestPre) > 0 {
+			latestPre = v
+		}
+	}
+	if latest != "" {
+		return latest
+	}
+	return latestPre
+}
+
+// IsQuery reports whether the version is a revision query instead of a semantic version,
+// the query is resolved as a git revision.
+func IsQuery(version string) bool {
+	return !semver.IsValid(version)
+}
+
+// ValidQuery reports whether the query is safe to pass to git as a revision.
+func ValidQuery(query string) bool {
+	return queryRegexp.MatchString(query) && !strings.Contains(query, "..")
+}
+
+// Info is the json of the info request.
+type Info struct {
+	Version string    `json:"Version"`
+	Time    time.Time `json:"Time"`
+}
+
+// DefaultGoMod is the go.mod served for the module without a go.mod file.
+func DefaultGoMod(modPath string) []byte {
+	return []byte("module " + modPath + "\n")
+}
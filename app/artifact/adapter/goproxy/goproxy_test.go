@@ -0,0 +1,160 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package goproxy
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRequest(t *testing.T) {
+	tests := []struct {
+		path    string
+		want    *Request
+		wantErr bool
+	}{
+		{path: "example.com/space/repo/@v/list", want: &Request{Module: "example.com/space/repo", Kind: RequestList}},
+		{path: "/example.com/space/repo/@latest", want: &Request{Module: "example.com/space/repo", Kind: RequestLatest}},
+		{path: "example.com/space/!repo/@v/v1.0.0.info",
+			want: &Request{Module: "example.com/space/Repo", Kind: RequestInfo, Version: "v1.0.0"}},
+		{path: "example.com/space/repo/v2/@v/v2.1.0.mod",
+			want: &Request{Module: "example.com/space/repo/v2", Kind: RequestMod, Version: "v2.1.0"}},
+		{path: "example.com/space/repo/@v/v1.0.0-rc.1.zip",
+			want: &Request{Module: "example.com/space/repo", Kind: RequestZip, Version: "v1.0.0-rc.1"}},
+		{path: "example.com/space/repo/@v/main.info", want: &Request{Module: "example.com/space/repo", Kind: RequestInfo, Version: "main"}},
+		{path: "example.com/space/repo/@v/main.zip", wantErr: true},
+		{path: "example.com/space/repo/@v/v1.0.zip", wantErr: true},
+		{path: "example.com/space/repo/@v/v1.0.0.tar", wantErr: true},
+		{path: "example.com/space/repo", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := ParseRequest(tt.path)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCandidates(t *testing.T) {
+	candidates, err := Candidates("example.com/space/sub/repo/pkg/v2")
+	require.NoError(t, err)
+	require.Equal(t, []*Module{
+		{Path: "example.com/space/sub/repo/pkg/v2", RepoPath: "space/sub/repo/pkg", PathMajor: "/v2"},
+		{Path: "example.com/space/sub/repo/pkg/v2", RepoPath: "space/sub/repo", Dir: "pkg", PathMajor: "/v2"},
+		{Path: "example.com/space/sub/repo/pkg/v2", RepoPath: "space/sub", Dir: "repo/pkg", PathMajor: "/v2"},
+	}, candidates)
+
+	_, err = Candidates("example.com/space/repo/v1")
+	require.Error(t, err)
+}
+
+func TestModuleTagVersion(t *testing.T) {
+	root := &Module{Path: "example.com/space/repo", RepoPath: "space/repo"}
+	sub := &Module{Path: "example.com/space/repo/pkg/v2", RepoPath: "space/repo", Dir: "pkg", PathMajor: "/v2"}
+
+	tests := []struct {
+		mod     *Module
+		tag     string
+		version string
+		ok      bool
+	}{
+		{mod: root, tag: "v1.2.3", version: "v1.2.3", ok: true},
+		{mod: root, tag: "v1.2.3-beta.1", version: "v1.2.3-beta.1", ok: true},
+		{mod: root, tag: "v1.2", ok: false},
+		{mod: root, tag: "v1.2.3+build", ok: false},
+		{mod: root, tag: "v2.0.0", ok: false},
+		{mod: root, tag: "v2.0.0+incompatible", ok: false},
+		{mod: root, tag: "pkg/v2.0.0", ok: false},
+		{mod: sub, tag: "pkg/v2.0.1", version: "v2.0.1", ok: true},
+		{mod: sub, tag: "pkg/v1.0.0", ok: false},
+		{mod: sub, tag: "v2.0.1", ok: false},
+	}
+	for _, tt := range tests {
+		version, ok := tt.mod.TagVersion(tt.tag)
+		require.Equal(t, tt.ok, ok, tt.tag)
+		require.Equal(t, tt.version, version, tt.tag)
+	}
+
+	require.Equal(t, "pkg/v2.0.1", sub.Tag("v2.0.1"))
+	require.Equal(t, "pkg/go.mod", sub.GoModPath())
+	require.Equal(t, "go.mod", root.GoModPath())
+}
+
+func TestModulePseudoVersion(t *testing.T) {
+	when := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	sha := "0123456789abcdef0123456789abcdef01234567"
+
+	root := &Module{Path: "example.com/space/repo"}
+	require.Equal(t, "v0.0.0-20240506070809-0123456789ab", root.PseudoVersion(when, sha))
+
+	major := &Module{Path: "example.com/space/repo/v3", PathMajor: "/v3"}
+	require.Equal(t, "v3.0.0-20240506070809-0123456789ab", major.PseudoVersion(when, sha))
+}
+
+func TestLatestVersion(t *testing.T) {
+	versions := []string{"v1.10.0", "v1.2.0", "v2.0.0-rc.1", "v1.9.9"}
+	SortVersions(versions)
+	require.Equal(t, []string{"v1.2.0", "v1.9.9", "v1.10.0", "v2.0.0-rc.1"}, versions)
+	require.Equal(t, "v1.10.0", LatestVersion(versions))
+	require.Equal(t, "v2.0.0-rc.1", LatestVersion([]string{"v2.0.0-rc.1", "v1.0.0-alpha"}))
+	require.Equal(t, "", LatestVersion(nil))
+}
+
+func TestValidQuery(t *testing.T) {
+	require.True(t, ValidQuery("main"))
+	require.True(t, ValidQuery("feature/go-proxy"))
+	require.True(t, ValidQuery("0123456789ab"))
+	require.False(t, ValidQuery("--output=/tmp/x"))
+	require.False(t, ValidQuery("main..dev"))
+	require.False(t, ValidQuery("HEAD@{1}"))
+}
+
+func TestCreateZip(t *testing.T) {
+	var archive bytes.Buffer
+	zw := zip.NewWriter(&archive)
+	for name, content := range map[string]string{
+		"README.md":            "root",
+		"pkg/go.mod":           "module example.com/space/repo/pkg\n",
+		"pkg/lib.go":           "package pkg\n",
+		"pkg/vendor/x/x.go":    "package x\n",
+		"pkg/nested/go.mod":    "module example.com/space/repo/pkg/nested\n",
+		"pkg/nested/nested.go": "package nested\n",
+	} {
+		f, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = f.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+
+	archiveReader, err := zip.NewReader(bytes.NewReader(archive.Bytes()), int64(archive.Len()))
+	require.NoError(t, err)
+
+	mod := &Module{Path: "example.com/space/repo/pkg", RepoPath: "space/repo", Dir: "pkg"}
+	var out bytes.Buffer
+	require.NoError(t, CreateZip(&out, mod, "v1.0.0", archiveReader))
+
+	modReader, err := zip.NewReader(bytes.NewReader(out.Bytes()), int64(out.Len()))
+	require.NoError(t, err)
+	names := make([]string, 0, len(modReader.File))
+	for _, f := range modReader.File {
+		names = append(names, f.Name)
+	}
+	require.ElementsMatch(t, []string{
+		"example.com/space/repo/pkg@v1.0.0/go.mod",
+		"example.com/space/repo/pkg@v1.0.0/lib.go",
+	}, names)
+}
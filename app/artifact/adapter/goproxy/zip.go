@@ -0,0 +1,55 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package goproxy
+
+import (
+	"archive/zip"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/mod/module"
+	modzip "golang.org/x/mod/zip"
+)
+
+// archiveFile is a file of the git archive in the module directory.
+type archiveFile struct {
+	path string
+	file *zip.File
+}
+
+func (f *archiveFile) Path() string {
+	return f.path
+}
+
+func (f *archiveFile) Lstat() (os.FileInfo, error) {
+	return f.file.FileInfo(), nil
+}
+
+func (f *archiveFile) Open() (io.ReadCloser, error) {
+	return f.file.Open()
+}
+
+// CreateZip repacks the zip archive of the repository into the module zip, the files out of the
+// module directory are skipped, the vendor directories and nested modules are excluded by modzip.
+func CreateZip(w io.Writer, m *Module, version string, archive *zip.Reader) error {
+	prefix := m.TagPrefix()
+
+	files := make([]modzip.File, 0, len(archive.File))
+	for _, f := range archive.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		p, ok := strings.CutPrefix(f.Name, prefix)
+		if !ok {
+			continue
+		}
+		files = append(files, &archiveFile{path: p, file: f})
+	}
+
+	return modzip.Create(w, module.Version{Path: m.Path, Version: version}, files)
+}
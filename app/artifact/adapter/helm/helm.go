@@ -26,11 +26,19 @@ import (
 const (
 	_chartPkgType = "application/x-compressed-tar"
 
+	// MediaTypeConfig and MediaTypeChartContent identify a chart pushed to the container registry by 'helm push oci://'
+	MediaTypeConfig       = "application/vnd.cncf.helm.config.v1+json"
+	MediaTypeChartContent = "application/vnd.cncf.helm.chart.content.v1.tar+gzip"
+
+	_chartFormField = "chart"
+
 	// https://kubernetes.io/docs/concepts/overview/working-with-objects/names/#rfc-1035-label-names
 	_regexChartName = `^[a-z][a-z0-9\-]+[a-z0-9]$`
 )
 
 type helmUploader struct {
+	// formField is the multipart field of the chart, the whole body is the chart if it's empty
+	formField string
 	uploadReq *request.ArtifactUploadRequest
 	//base      *base.HostedUploader
 	store storage.ContentStorage
@@ -42,6 +50,17 @@ type helmUploader struct {
 }
 
 func NewUploader(contentStore storage.ContentStorage, artStore store.ArtifactStore, view *adapter.ViewDescriptor) adapter.ArtifactPackageUploader {
+	return &helmUploader{
+		formField:   _chartFormField,
+		uploadReq:   request.NewUpload(view, artStore),
+		store:       contentStore,
+		storeLayout: adapter.StorageLayoutBlob,
+		descriptor:  adapter.NewEmptyPackageDescriptor(),
+	}
+}
+
+// NewArchiveUploader accepts the chart archive as the request body, it's used for the chart pushed by the oci protocol.
+func NewArchiveUploader(contentStore storage.ContentStorage, artStore store.ArtifactStore, view *adapter.ViewDescriptor) adapter.ArtifactPackageUploader {
 	return &helmUploader{
 		uploadReq:   request.NewUpload(view, artStore),
 		store:       contentStore,
@@ -51,10 +70,14 @@ func NewUploader(contentStore storage.ContentStorage, artStore store.ArtifactSto
 }
 
 func (h *helmUploader) Serve(ctx context.Context, req *http.Request) (int64, error) {
-	_ = req.ParseMultipartForm(32 << 20)
-	file, _, err := req.FormFile("chart")
-	if err != nil {
-		return 0, err
+	var file io.Reader = req.Body
+	if h.formField != "" {
+		_ = req.ParseMultipartForm(32 << 20)
+		formFile, _, err := req.FormFile(h.formField)
+		if err != nil {
+			return 0, err
+		}
+		file = formFile
 	}
 
 	fw, ref, err := adapter.NewRandomBlobWriter(ctx, h.store)
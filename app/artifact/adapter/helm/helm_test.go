@@ -7,6 +7,9 @@
 package helm_test
 
 import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
 	"context"
 	"net/http"
 	"testing"
@@ -79,6 +82,54 @@ func (suite *HelmSuite) TestUpload() {
 	}
 }
 
+func (suite *HelmSuite) TestUploadArchive() {
+	tests := []struct {
+		name, version string
+		expectErr     error
+	}{
+		{"nginx", "1.0.0", nil},
+		{"2nginx", "1.0.0", adapter.ErrInvalidPackageName},
+	}
+
+	for _, test := range tests {
+		body, err := createChartArchive(test.name, test.version)
+		require.NoError(suite.T(), err)
+
+		req, err := http.NewRequest(http.MethodPost, "http://localhost:8080", body)
+		require.NoError(suite.T(), err)
+
+		uploader := helm.NewArchiveUploader(suite.ArtifactStore, suite.Store.Artifacts, suite.DefaultView)
+		err = handleUpload(suite.Ctx, uploader, req)
+		if test.expectErr != nil {
+			require.ErrorContains(suite.T(), err, test.expectErr.Error())
+		} else {
+			require.NoError(suite.T(), err)
+		}
+	}
+}
+
+// createChartArchive packs a minimal chart like the layer pushed by 'helm push oci://'
+func createChartArchive(name, version string) (*bytes.Buffer, error) {
+	buf := bytes.NewBuffer(nil)
+	gw := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gw)
+
+	content := []byte("apiVersion: v2\nname: " + name + "\nversion: " + version + "\n")
+	if err := tw.WriteHeader(&tar.Header{Name: name + "/Chart.yaml", Mode: 0o644, Size: int64(len(content))}); err != nil {
+		return nil, err
+	}
+	if _, err := tw.Write(content); err != nil {
+		return nil, err
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gw.Close(); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
 func handleUpload(ctx context.Context, uploader adapter.ArtifactPackageUploader, req *http.Request) error {
 	_, err := uploader.Serve(ctx, req)
 	if err != nil {
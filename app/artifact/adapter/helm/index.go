@@ -116,8 +116,10 @@ func (h *helmIndex) buildIndex(ctx context.Context, contextPath string) (*IndexF
 	index.ServerInfo = map[string]interface{}{
 		"contextPath": contextPath,
 	}
+	// the yanked charts are left out, they are still downloaded by the url pinned in the lock files
 	items, err := h.artStore.Versions().Find(ctx, types.SearchVersionOption{
-		ViewId: h.view.ViewID,
+		ViewId:        h.view.ViewID,
+		ExcludeYanked: true,
 	})
 	if err != nil {
 		return nil, err
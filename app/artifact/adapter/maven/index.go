@@ -0,0 +1,173 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package maven
+
+import (
+	"context"
+	"encoding/xml"
+	"sort"
+	"time"
+
+	"github.com/easysoft/gitfox/app/artifact/adapter"
+	"github.com/easysoft/gitfox/app/store"
+	"github.com/easysoft/gitfox/app/store/database/artifacts"
+	gitfox_store "github.com/easysoft/gitfox/store"
+	"github.com/easysoft/gitfox/types"
+)
+
+const (
+	MetadataContentType = "text/xml"
+
+	_lastUpdatedLayout = "20060102150405"
+)
+
+// Metadata is the maven-metadata.xml document, for the package level
+// it lists all versions, for a snapshot version it lists the latest build of every file.
+type Metadata struct {
+	XMLName      xml.Name   `xml:"metadata"`
+	ModelVersion string     `xml:"modelVersion,attr,omitempty"`
+	GroupID      string     `xml:"groupId"`
+	ArtifactID   string     `xml:"artifactId"`
+	Version      string     `xml:"version,omitempty"`
+	Versioning   Versioning `xml:"versioning"`
+}
+
+type Versioning struct {
+	Latest           string            `xml:"latest,omitempty"`
+	Release          string            `xml:"release,omitempty"`
+	Snapshot         *Snapshot         `xml:"snapshot,omitempty"`
+	Versions         []string          `xml:"versions>version,omitempty"`
+	LastUpdated      string            `xml:"lastUpdated"`
+	SnapshotVersions []SnapshotVersion `xml:"snapshotVersions>snapshotVersion,omitempty"`
+}
+
+type Snapshot struct {
+	Timestamp   string `xml:"timestamp,omitempty"`
+	BuildNumber int    `xml:"buildNumber,omitempty"`
+	LocalCopy   bool   `xml:"localCopy,omitempty"`
+}
+
+type SnapshotVersion struct {
+	Classifier string `xml:"classifier,omitempty"`
+	Extension  string `xml:"extension"`
+	Value      string `xml:"value"`
+	Updated    string `xml:"updated"`
+}
+
+type metadataIndex struct {
+	artStore store.ArtifactStore
+	view     *adapter.ViewDescriptor
+}
+
+func NewMetadataIndex(artStore store.ArtifactStore, view *adapter.ViewDescriptor) *metadataIndex {
+	return &metadataIndex{artStore: artStore, view: view}
+}
+
+// PackageMetadata generates the artifact-level maven-metadata.xml,
+// versions are ordered by upload time as maven deploy does.
+func (m *metadataIndex) PackageMetadata(ctx context.Context, pkg *types.ArtifactPackage) ([]byte, error) {
+	versions, err := adapter.ListVersions(ctx, m.artStore, types.SearchVersionOption{
+		PackageId: pkg.ID, ViewId: m.view.ViewID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(versions) == 0 {
+		return nil, gitfox_store.ErrResourceNotFound
+	}
+
+	sort.Slice(versions, func(i, j int) bool {
+		if versions[i].Created != versions[j].Created {
+			return versions[i].Created < versions[j].Created
+		}
+		return versions[i].ID < versions[j].ID
+	})
+
+	meta := Metadata{GroupID: pkg.Namespace, ArtifactID: pkg.Name}
+	var lastUpdated int64
+	for _, ver := range versions {
+		meta.Versioning.Versions = append(meta.Versioning.Versions, ver.Version)
+		meta.Versioning.Latest = ver.Version
+		if !IsSnapshot(ver.Version) {
+			meta.Versioning.Release = ver.Version
+		}
+		lastUpdated = max(lastUpdated, ver.Updated)
+	}
+	meta.Versioning.LastUpdated = formatTime(lastUpdated)
+
+	return marshal(&meta)
+}
+
+// SnapshotMetadata generates the version-level maven-metadata.xml of a snapshot version.
+func (m *metadataIndex) SnapshotMetadata(ctx context.Context, pkg *types.ArtifactPackage, ver *types.ArtifactVersion) ([]byte, error) {
+	assets, err := m.artStore.Assets().Search(ctx,
+		types.SearchAssetOption{VersionId: ver.ID},
+		artifacts.AssetExcludeDeletedOption{})
+	if err != nil {
+		return nil, err
+	}
+
+	meta := Metadata{ModelVersion: "1.1.0", GroupID: pkg.Namespace, ArtifactID: pkg.Name, Version: ver.Version}
+	snapshot := &Snapshot{}
+	latest := make(map[[2]string]*FileInfo)
+	updated := make(map[[2]string]int64)
+
+	for _, asset := range assets {
+		info, e := ParseFilename(pkg.Name, ver.Version, asset.Path)
+		if e != nil {
+			continue
+		}
+		key := [2]string{info.Classifier, info.Extension}
+		if curr, ok := latest[key]; ok && curr.BuildNumber > info.BuildNumber {
+			continue
+		}
+		latest[key] = info
+		updated[key] = asset.Updated
+
+		if info.BuildNumber > snapshot.BuildNumber {
+			snapshot.BuildNumber = info.BuildNumber
+			snapshot.Timestamp = info.Timestamp
+		}
+	}
+
+	if snapshot.BuildNumber == 0 {
+		// non-unique snapshot deployed without timestamps
+		snapshot.LocalCopy = true
+	}
+	meta.Versioning.Snapshot = snapshot
+
+	for key, info := range latest {
+		meta.Versioning.SnapshotVersions = append(meta.Versioning.SnapshotVersions, SnapshotVersion{
+			Classifier: info.Classifier,
+			Extension:  info.Extension,
+			Value:      info.SnapshotVersion(ver.Version),
+			Updated:    formatTime(updated[key]),
+		})
+	}
+	sort.Slice(meta.Versioning.SnapshotVersions, func(i, j int) bool {
+		a, b := meta.Versioning.SnapshotVersions[i], meta.Versioning.SnapshotVersions[j]
+		if a.Extension != b.Extension {
+			return a.Extension < b.Extension
+		}
+		return a.Classifier < b.Classifier
+	})
+	meta.Versioning.LastUpdated = formatTime(ver.Updated)
+
+	return marshal(&meta)
+}
+
+func marshal(meta *Metadata) ([]byte, error) {
+	content, err := xml.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), content...), nil
+}
+
+func formatTime(milli int64) string {
+	return time.UnixMilli(milli).UTC().Format(_lastUpdatedLayout)
+}
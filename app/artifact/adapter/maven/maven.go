@@ -0,0 +1,136 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package maven
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/easysoft/gitfox/app/artifact/adapter"
+	"github.com/easysoft/gitfox/app/artifact/adapter/request"
+	"github.com/easysoft/gitfox/app/store"
+	"github.com/easysoft/gitfox/pkg/storage"
+	"github.com/easysoft/gitfox/types"
+)
+
+const (
+	_pomType     = "text/xml"
+	_archiveType = "application/java-archive"
+	_defaultType = "application/octet-stream"
+)
+
+type uploader struct {
+	uploadReq *request.ArtifactUploadRequest
+	store     storage.ContentStorage
+
+	storeLayout adapter.StorageLayout
+
+	path       *Path
+	fileInfo   *FileInfo
+	descriptor *adapter.PackageDescriptor
+	bufReader  io.Reader
+}
+
+func NewUploader(contentStore storage.ContentStorage, artStore store.ArtifactStore, view *adapter.ViewDescriptor, path *Path) adapter.ArtifactPackageUploader {
+	return &uploader{
+		uploadReq:   request.NewUpload(view, artStore),
+		store:       contentStore,
+		storeLayout: adapter.StorageLayoutBlob,
+		path:        path,
+		descriptor:  adapter.NewEmptyPackageDescriptor(),
+	}
+}
+
+func (h *uploader) Serve(ctx context.Context, req *http.Request) (int64, error) {
+	if h.path.Version == "" || h.path.IsMetadata() || h.path.Checksum != "" {
+		return 0, adapter.ErrInvalidPackagePath
+	}
+
+	info, err := ParseFilename(h.path.ArtifactID, h.path.Version, h.path.Filename)
+	if err != nil {
+		return 0, err
+	}
+	h.fileInfo = info
+
+	fw, ref, err := adapter.NewRandomBlobWriter(ctx, h.store)
+	if err != nil {
+		return 0, err
+	}
+
+	extraWriters := make([]io.Writer, 0)
+	if h.isPom() {
+		buf := bytes.NewBuffer(nil)
+		h.bufReader = buf
+		extraWriters = append(extraWriters, buf)
+	}
+
+	h.uploadReq.RegisterWriter(fw)
+	size, hash, err := request.Write(req.Body, fw, extraWriters...)
+
+	h.descriptor.MainAsset.Path = h.path.Filename
+	h.descriptor.MainAsset.Size = size
+	h.descriptor.MainAsset.Hash = hash
+	h.descriptor.MainAsset.Ref = ref
+	h.descriptor.MainAsset.ContentType = contentType(info.Extension)
+	h.descriptor.MainAsset.Format = types.ArtifactMavenFormat
+	h.descriptor.MainAsset.Kind = types.AssetKindMain
+	h.descriptor.MainAsset.Attr = adapter.AttrAssetNormal
+
+	h.descriptor.Name = h.path.ArtifactID
+	h.descriptor.Namespace = h.path.GroupID
+	h.descriptor.Version = h.path.Version
+	h.descriptor.Format = types.ArtifactMavenFormat
+	return size, err
+}
+
+func (h *uploader) IsValid(ctx context.Context) error {
+	if h.isPom() {
+		pom, err := ParsePom(h.bufReader)
+		if err != nil {
+			return adapter.ErrInvalidPackageContent.WithDetail(err.Error())
+		}
+		if !pom.Matches(h.path) {
+			return adapter.ErrInvalidPackageContent.WithDetail("pom coordinates do not match the upload path")
+		}
+		h.descriptor.MainAsset.Metadata = &AssetMetadata{data: pom}
+	}
+
+	u := h.uploadReq.LoadCreator(ctx)
+	h.descriptor.VersionMetadata = &adapter.VersionMetadata{CreatorName: u.UID}
+
+	h.uploadReq.Descriptor = h.descriptor
+	return nil
+}
+
+func (h *uploader) Save(ctx context.Context) error {
+	if err := h.uploadReq.Commit(ctx); err != nil {
+		_ = h.uploadReq.Cancel(ctx)
+		return err
+	}
+	return nil
+}
+
+func (h *uploader) Cancel(ctx context.Context) error {
+	return h.uploadReq.Cancel(ctx)
+}
+
+func (h *uploader) isPom() bool {
+	return h.fileInfo != nil && h.fileInfo.Extension == "pom" && h.fileInfo.Classifier == ""
+}
+
+func contentType(extension string) string {
+	switch extension {
+	case "pom":
+		return _pomType
+	case "jar", "war", "ear":
+		return _archiveType
+	default:
+		return _defaultType
+	}
+}
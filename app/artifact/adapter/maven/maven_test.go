@@ -0,0 +1,221 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package maven_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/easysoft/gitfox/app/artifact/adapter"
+	"github.com/easysoft/gitfox/app/artifact/adapter/maven"
+	"github.com/easysoft/gitfox/app/artifact/adapter/testsuite"
+	"github.com/easysoft/gitfox/types"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const _pomTemplate = `<?xml version="1.0" encoding="UTF-8"?>
+<project xmlns="http://maven.apache.org/POM/4.0.0">
+  <modelVersion>4.0.0</modelVersion>
+  <groupId>%s</groupId>
+  <artifactId>%s</artifactId>
+  <version>%s</version>
+  <packaging>jar</packaging>
+</project>`
+
+type MavenSuite struct {
+	testsuite.BaseSuite
+}
+
+func TestMavenSuite(t *testing.T) {
+	ctx := context.Background()
+
+	st := &MavenSuite{
+		BaseSuite: testsuite.BaseSuite{
+			Ctx:  ctx,
+			Name: "maven",
+		},
+	}
+
+	st.BaseSuite.Constructor = func(ts *testsuite.TestStore) {
+	}
+
+	suite.Run(t, st)
+}
+
+func (suite *MavenSuite) SetupTest() {
+}
+
+func (suite *MavenSuite) TearDownTest() {
+}
+
+func (suite *MavenSuite) TestUpload() {
+	invalidTests := []struct {
+		path      string
+		content   string
+		expectErr error
+	}{
+		{path: "com/example/demo/1.0/demo-1.0.pom", content: "<project>", expectErr: adapter.ErrInvalidPackageContent},
+		{path: "com/example/demo/1.0/demo-1.0.pom",
+			content: fmt.Sprintf(_pomTemplate, "com.example", "other", "1.0"), expectErr: adapter.ErrInvalidPackageContent},
+		{path: "com/example/demo/1.0/other-1.0.jar", content: "jar", expectErr: adapter.ErrInvalidPackagePath},
+		{path: "com/example/demo/1.0/demo-2.0.jar", content: "jar", expectErr: adapter.ErrInvalidPackagePath},
+		{path: "com/example/demo/1.0-SNAPSHOT/demo-1.0-2024.jar", content: "jar", expectErr: adapter.ErrInvalidPackagePath},
+	}
+
+	successTests := []struct {
+		path    string
+		content string
+	}{
+		{path: "com/example/demo/1.0/demo-1.0.pom", content: fmt.Sprintf(_pomTemplate, "com.example", "demo", "1.0")},
+		{path: "com/example/demo/1.0/demo-1.0.jar", content: "release jar"},
+		{path: "com/example/demo/1.0/demo-1.0-sources.jar", content: "release sources"},
+		{path: "com/example/demo/1.1-SNAPSHOT/demo-1.1-20240102.030405-1.jar", content: "snapshot jar 1"},
+		{path: "com/example/demo/1.1-SNAPSHOT/demo-1.1-20240102.030405-1.pom",
+			content: fmt.Sprintf(_pomTemplate, "com.example", "demo", "1.1-SNAPSHOT")},
+		{path: "com/example/demo/1.1-SNAPSHOT/demo-1.1-20240103.030405-2.jar", content: "snapshot jar 2"},
+	}
+
+	for idx, test := range invalidTests {
+		err := suite.upload(test.path, test.content)
+		require.ErrorContains(suite.T(), err, test.expectErr.Error(), "loop id: %d", idx)
+	}
+
+	for idx, test := range successTests {
+		err := suite.upload(test.path, test.content)
+		require.NoError(suite.T(), err, "loop id: %d", idx)
+	}
+
+	pkg, err := suite.Store.Artifacts.Packages().GetByName(suite.Ctx, "demo", "com.example", suite.DefaultView.OwnerID, types.ArtifactMavenFormat)
+	require.NoError(suite.T(), err)
+
+	index := maven.NewMetadataIndex(suite.Store.Artifacts, suite.DefaultView)
+	content, err := index.PackageMetadata(suite.Ctx, pkg)
+	require.NoError(suite.T(), err)
+
+	var meta maven.Metadata
+	require.NoError(suite.T(), xml.Unmarshal(content, &meta))
+	require.Equal(suite.T(), "com.example", meta.GroupID)
+	require.Equal(suite.T(), []string{"1.0", "1.1-SNAPSHOT"}, meta.Versioning.Versions)
+	require.Equal(suite.T(), "1.1-SNAPSHOT", meta.Versioning.Latest)
+	require.Equal(suite.T(), "1.0", meta.Versioning.Release)
+
+	ver, err := suite.Store.Artifacts.Versions().GetByVersion(suite.Ctx, pkg.ID, suite.DefaultView.ViewID, "1.1-SNAPSHOT")
+	require.NoError(suite.T(), err)
+
+	content, err = index.SnapshotMetadata(suite.Ctx, pkg, ver)
+	require.NoError(suite.T(), err)
+
+	meta = maven.Metadata{}
+	require.NoError(suite.T(), xml.Unmarshal(content, &meta))
+	require.Equal(suite.T(), "1.1-SNAPSHOT", meta.Version)
+	require.Equal(suite.T(), &maven.Snapshot{Timestamp: "20240103.030405", BuildNumber: 2}, meta.Versioning.Snapshot)
+	require.Len(suite.T(), meta.Versioning.SnapshotVersions, 2)
+	require.Equal(suite.T(), "1.1-20240103.030405-2", meta.Versioning.SnapshotVersions[0].Value)
+	require.Equal(suite.T(), "jar", meta.Versioning.SnapshotVersions[0].Extension)
+	require.Equal(suite.T(), "1.1-20240102.030405-1", meta.Versioning.SnapshotVersions[1].Value)
+	require.Equal(suite.T(), "pom", meta.Versioning.SnapshotVersions[1].Extension)
+}
+
+func (suite *MavenSuite) upload(p, content string) error {
+	path, err := maven.ParsePath(p)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPut, "http://localhost:8080", bytes.NewBufferString(content))
+	require.NoError(suite.T(), err)
+
+	uploader := maven.NewUploader(suite.ArtifactStore, suite.Store.Artifacts, suite.DefaultView, path)
+	return handleUpload(suite.Ctx, uploader, req)
+}
+
+func TestParsePath(t *testing.T) {
+	tests := []struct {
+		path      string
+		expect    *maven.Path
+		expectErr error
+	}{
+		{path: "com/example/demo/1.0/demo-1.0.jar",
+			expect: &maven.Path{GroupID: "com.example", ArtifactID: "demo", Version: "1.0", Filename: "demo-1.0.jar"}},
+		{path: "/com/example/demo/1.0/demo-1.0.pom.sha1",
+			expect: &maven.Path{GroupID: "com.example", ArtifactID: "demo", Version: "1.0", Filename: "demo-1.0.pom", Checksum: "sha1"}},
+		{path: "com/example/demo/maven-metadata.xml",
+			expect: &maven.Path{GroupID: "com.example", ArtifactID: "demo", Filename: maven.MetadataFilename}},
+		{path: "com/example/demo/maven-metadata.xml.md5",
+			expect: &maven.Path{GroupID: "com.example", ArtifactID: "demo", Filename: maven.MetadataFilename, Checksum: "md5"}},
+		{path: "com/demo/1.0-SNAPSHOT/maven-metadata.xml",
+			expect: &maven.Path{GroupID: "com", ArtifactID: "demo", Version: "1.0-SNAPSHOT", Filename: maven.MetadataFilename}},
+		{path: "demo/1.0/demo-1.0.jar", expectErr: adapter.ErrInvalidPackagePath},
+		{path: "com/demo.jar", expectErr: adapter.ErrInvalidPackagePath},
+		{path: "com/example/-demo/1.0/-demo-1.0.jar", expectErr: adapter.ErrInvalidPackageName},
+		{path: "com/exa+mple/demo/1.0/demo-1.0.jar", expectErr: adapter.ErrInvalidGroupName},
+		{path: "com/example/demo/^1.0/demo-^1.0.jar", expectErr: adapter.ErrInvalidPackageVersion},
+	}
+
+	for idx, test := range tests {
+		p, err := maven.ParsePath(test.path)
+		if test.expectErr != nil {
+			require.ErrorContains(t, err, test.expectErr.Error(), "loop id: %d", idx)
+			continue
+		}
+		require.NoError(t, err, "loop id: %d", idx)
+		require.Equal(t, test.expect, p, "loop id: %d", idx)
+	}
+}
+
+func TestParseFilename(t *testing.T) {
+	tests := []struct {
+		version  string
+		filename string
+		expect   *maven.FileInfo
+	}{
+		{version: "1.0", filename: "demo-1.0.jar", expect: &maven.FileInfo{Extension: "jar"}},
+		{version: "1.0", filename: "demo-1.0-sources.jar", expect: &maven.FileInfo{Classifier: "sources", Extension: "jar"}},
+		{version: "1.0", filename: "demo-1.0.tar.gz", expect: &maven.FileInfo{Extension: "tar.gz"}},
+		{version: "1.0-SNAPSHOT", filename: "demo-1.0-SNAPSHOT.pom", expect: &maven.FileInfo{Extension: "pom"}},
+		{version: "1.0-SNAPSHOT", filename: "demo-1.0-20240102.030405-12-javadoc.jar",
+			expect: &maven.FileInfo{Classifier: "javadoc", Extension: "jar", Timestamp: "20240102.030405", BuildNumber: 12}},
+		{version: "1.0", filename: "demo-1.0"},
+		{version: "1.0", filename: "demo-1.0-.jar"},
+		{version: "1.0-SNAPSHOT", filename: "demo-1.0-latest.jar"},
+	}
+
+	for idx, test := range tests {
+		info, err := maven.ParseFilename("demo", test.version, test.filename)
+		if test.expect == nil {
+			require.Error(t, err, "loop id: %d", idx)
+			continue
+		}
+		require.NoError(t, err, "loop id: %d", idx)
+		require.Equal(t, test.expect, info, "loop id: %d", idx)
+	}
+}
+
+func handleUpload(ctx context.Context, uploader adapter.ArtifactPackageUploader, req *http.Request) error {
+	_, err := uploader.Serve(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	err = uploader.IsValid(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = uploader.Save(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,186 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package maven
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/easysoft/gitfox/app/artifact/adapter"
+)
+
+const (
+	MetadataFilename = "maven-metadata.xml"
+
+	snapshotSuffix = "-SNAPSHOT"
+
+	_regexSegment  = `^[a-zA-Z0-9_](?:[a-zA-Z0-9\-_\.]*[a-zA-Z0-9_])?$`
+	_regexVersion  = `^[a-zA-Z0-9](?:[a-zA-Z0-9\-_\.\+]*[a-zA-Z0-9])?$`
+	_regexSnapshot = `^(\d{8}\.\d{6})-(\d+)`
+)
+
+var (
+	segmentRegex  = regexp.MustCompile(_regexSegment)
+	versionRegex  = regexp.MustCompile(_regexVersion)
+	snapshotRegex = regexp.MustCompile(_regexSnapshot)
+
+	// checksumAlgorithms are the sidecar extensions maven clients upload and request
+	checksumAlgorithms = []string{"md5", "sha1", "sha256", "sha512"}
+)
+
+// Path is a parsed request path of the maven 2 repository layout:
+//
+//	{groupId as dirs}/{artifactId}/maven-metadata.xml
+//	{groupId as dirs}/{artifactId}/{version}/maven-metadata.xml (snapshot version only)
+//	{groupId as dirs}/{artifactId}/{version}/{filename}
+//
+// each of them may be followed by a checksum extension.
+type Path struct {
+	GroupID    string
+	ArtifactID string
+	// Version is empty for package-level metadata
+	Version  string
+	Filename string
+
+	// Checksum is the requested checksum algorithm, empty for a regular file
+	Checksum string
+}
+
+func ParsePath(p string) (*Path, error) {
+	frames := strings.Split(strings.Trim(p, "/"), "/")
+	fNum := len(frames)
+	if fNum < 3 {
+		return nil, adapter.ErrInvalidPackagePath
+	}
+
+	res := &Path{Filename: frames[fNum-1]}
+	for _, algo := range checksumAlgorithms {
+		if name := strings.TrimSuffix(res.Filename, "."+algo); name != res.Filename {
+			res.Filename = name
+			res.Checksum = algo
+			break
+		}
+	}
+
+	switch {
+	case res.Filename == MetadataFilename && (fNum < 4 || !IsSnapshot(frames[fNum-2])):
+		res.ArtifactID = frames[fNum-2]
+		res.GroupID = strings.Join(frames[0:fNum-2], ".")
+	case fNum >= 4:
+		res.Version = frames[fNum-2]
+		res.ArtifactID = frames[fNum-3]
+		res.GroupID = strings.Join(frames[0:fNum-3], ".")
+	default:
+		return nil, adapter.ErrInvalidPackagePath
+	}
+
+	if err := res.validate(); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (p *Path) validate() error {
+	if !segmentRegex.MatchString(p.ArtifactID) {
+		return adapter.ErrInvalidPackageName.WithDetail(fmt.Sprintf("require regex pattern: %s", _regexSegment))
+	}
+
+	for _, seg := range strings.Split(p.GroupID, ".") {
+		if !segmentRegex.MatchString(seg) {
+			return adapter.ErrInvalidGroupName
+		}
+	}
+
+	if p.Version != "" && !versionRegex.MatchString(p.Version) {
+		return adapter.ErrInvalidPackageVersion.WithDetail(fmt.Sprintf("require regex pattern: %s", _regexVersion))
+	}
+	return nil
+}
+
+// IsMetadata reports whether the path points to a maven-metadata.xml or its checksum
+func (p *Path) IsMetadata() bool {
+	return p.Filename == MetadataFilename
+}
+
+func IsSnapshot(version string) bool {
+	return strings.HasSuffix(version, snapshotSuffix)
+}
+
+// FileInfo holds the parts of an artifact filename,
+// e.g. demo-1.0-20240102.030405-3-sources.jar
+type FileInfo struct {
+	Classifier string
+	Extension  string
+
+	// Timestamp and BuildNumber are only set for unique snapshot files
+	Timestamp   string
+	BuildNumber int
+}
+
+func ParseFilename(artifactID, version, filename string) (*FileInfo, error) {
+	rest, found := strings.CutPrefix(filename, artifactID+"-")
+	if !found {
+		return nil, adapter.ErrInvalidPackagePath.WithDetail("filename must start with the artifactId")
+	}
+
+	info := &FileInfo{}
+	if r, ok := strings.CutPrefix(rest, version); ok {
+		rest = r
+	} else if r, ok = strings.CutPrefix(rest, strings.TrimSuffix(version, snapshotSuffix)+"-"); ok && IsSnapshot(version) {
+		match := snapshotRegex.FindStringSubmatch(r)
+		if match == nil {
+			return nil, adapter.ErrInvalidPackagePath.WithDetail("invalid snapshot timestamp")
+		}
+		info.Timestamp = match[1]
+		info.BuildNumber, _ = strconv.Atoi(match[2])
+		rest = r[len(match[0]):]
+	} else {
+		return nil, adapter.ErrInvalidPackagePath.WithDetail("filename does not match the version")
+	}
+
+	if r, ok := strings.CutPrefix(rest, "-"); ok {
+		idx := strings.Index(r, ".")
+		if idx < 1 {
+			return nil, adapter.ErrInvalidPackagePath.WithDetail("invalid classifier")
+		}
+		info.Classifier = r[:idx]
+		rest = r[idx:]
+	}
+
+	if len(rest) < 2 || rest[0] != '.' {
+		return nil, adapter.ErrInvalidPackagePath.WithDetail("missing file extension")
+	}
+	info.Extension = rest[1:]
+	return info, nil
+}
+
+// SnapshotVersion returns the resolved version of a unique snapshot file,
+// e.g. 1.0-20240102.030405-3
+func (f *FileInfo) SnapshotVersion(version string) string {
+	if f.Timestamp == "" {
+		return version
+	}
+	return fmt.Sprintf("%s-%s-%d", strings.TrimSuffix(version, snapshotSuffix), f.Timestamp, f.BuildNumber)
+}
+
+// Checksum returns the hex digest of the given algorithm
+func Checksum(h *adapter.Hash, algo string) string {
+	switch algo {
+	case "md5":
+		return h.Md5
+	case "sha1":
+		return h.Sha1
+	case "sha256":
+		return h.Sha256
+	case "sha512":
+		return h.Sha512
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package maven
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+)
+
+// Pom contains the subset of project descriptor fields kept as asset metadata
+type Pom struct {
+	XMLName     xml.Name   `xml:"project" json:"-"`
+	GroupID     string     `xml:"groupId" json:"group_id"`
+	ArtifactID  string     `xml:"artifactId" json:"artifact_id"`
+	Version     string     `xml:"version" json:"version"`
+	Packaging   string     `xml:"packaging" json:"packaging,omitempty"`
+	Name        string     `xml:"name" json:"name,omitempty"`
+	Description string     `xml:"description" json:"description,omitempty"`
+	URL         string     `xml:"url" json:"url,omitempty"`
+	Parent      *pomParent `xml:"parent" json:"-"`
+}
+
+type pomParent struct {
+	GroupID string `xml:"groupId"`
+	Version string `xml:"version"`
+}
+
+func ParsePom(r io.Reader) (*Pom, error) {
+	var pom Pom
+	if err := xml.NewDecoder(r).Decode(&pom); err != nil {
+		return nil, err
+	}
+
+	// groupId and version can be inherited from the parent project
+	if pom.Parent != nil {
+		if pom.GroupID == "" {
+			pom.GroupID = pom.Parent.GroupID
+		}
+		if pom.Version == "" {
+			pom.Version = pom.Parent.Version
+		}
+	}
+	return &pom, nil
+}
+
+// Matches checks the pom coordinates against the upload path,
+// properties like ${revision} can not be resolved and are skipped
+func (p *Pom) Matches(path *Path) bool {
+	if p.ArtifactID != path.ArtifactID {
+		return false
+	}
+	if p.GroupID != "" && !strings.Contains(p.GroupID, "${") && p.GroupID != path.GroupID {
+		return false
+	}
+	if p.Version != "" && !strings.Contains(p.Version, "${") && p.Version != path.Version {
+		return false
+	}
+	return true
+}
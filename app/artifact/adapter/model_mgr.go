@@ -197,9 +197,16 @@ func (mm *ModelManager) updateAsset(ctx context.Context, current *types.Artifact
 		return nil, err
 	}
 
+	previousBlob, err := mm.store.Blobs().GetById(ctx, previousBlobId)
+	if err != nil {
+		return nil, err
+	}
 	if err = mm.store.Blobs().SoftDeleteById(ctx, previousBlobId); err != nil {
 		return nil, err
 	}
+	if err = mm.store.Usages().Add(ctx, mm.view.OwnerID, -previousBlob.Size, 0); err != nil {
+		return nil, err
+	}
 	return current, nil
 }
 
@@ -253,6 +260,10 @@ func (mm *ModelManager) createBlob(ctx context.Context, descriptor *AssetDescrip
 	if err := mm.store.Blobs().Create(ctx, blobModel); err != nil {
 		return nil, err
 	}
+	// the blob is counted in the usage of the space uploading it
+	if err := mm.store.Usages().Add(ctx, mm.view.OwnerID, blobModel.Size, 0); err != nil {
+		return nil, err
+	}
 	return blobModel, nil
 }
 
@@ -347,6 +358,9 @@ func (mm *ModelManager) getOrCreatePackage(ctx context.Context) (*types.Artifact
 			if e := mm.store.Packages().UnDelete(ctx, currPkg); e != nil {
 				return nil, e
 			}
+			if e := mm.store.Usages().Add(ctx, mm.view.OwnerID, 0, 1); e != nil {
+				return nil, e
+			}
 		}
 		mm.pkgModel = currPkg
 		return currPkg, nil
@@ -365,6 +379,9 @@ func (mm *ModelManager) getOrCreatePackage(ctx context.Context) (*types.Artifact
 	if err = mm.store.Packages().Create(ctx, pkgModel); err != nil {
 		return nil, err
 	}
+	if err = mm.store.Usages().Add(ctx, mm.view.OwnerID, 0, 1); err != nil {
+		return nil, err
+	}
 	mm.pkgModel = pkgModel
 	return pkgModel, nil
 }
@@ -0,0 +1,19 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package npm
+
+import (
+	"github.com/easysoft/gitfox/app/api/usererror"
+)
+
+var (
+	ErrInvalidTag         = usererror.BadRequest("invalid dist-tag")
+	ErrLatestTagRequired  = usererror.BadRequest("the latest tag can't be removed")
+	ErrVersionExists      = usererror.Conflict("cannot publish over the previously published version")
+	ErrMissingAttachment  = usererror.BadRequest("publish request requires exactly one version with its tarball")
+	ErrAttachmentMismatch = usererror.BadRequest("tarball does not match the published version")
+)
@@ -0,0 +1,153 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package npm
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/easysoft/gitfox/app/artifact/adapter"
+	"github.com/easysoft/gitfox/app/store"
+	"github.com/easysoft/gitfox/app/store/database/artifacts"
+	gitfox_store "github.com/easysoft/gitfox/store"
+	"github.com/easysoft/gitfox/types"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	PackumentContentType = "application/json"
+
+	_timeLayout = "2006-01-02T15:04:05.000Z"
+)
+
+// TarballURLFunc returns the download url of a tarball
+type TarballURLFunc func(name *Name, filename string) string
+
+type packumentIndex struct {
+	artStore store.ArtifactStore
+	view     *adapter.ViewDescriptor
+}
+
+func NewPackumentIndex(artStore store.ArtifactStore, view *adapter.ViewDescriptor) *packumentIndex {
+	return &packumentIndex{artStore: artStore, view: view}
+}
+
+// Build generates the packument from the stored versions, the manifest of each version
+// is read from the metadata of its tarball asset.
+func (p *packumentIndex) Build(ctx context.Context, pkg *types.ArtifactPackage, urlFn TarballURLFunc) (*Packument, error) {
+	logger := log.Ctx(ctx)
+	name := &Name{Scope: pkg.Namespace, Name: pkg.Name}
+
+	versions, err := adapter.ListVersions(ctx, p.artStore, types.SearchVersionOption{
+		PackageId: pkg.ID, ViewId: p.view.ViewID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(versions) == 0 {
+		return nil, gitfox_store.ErrResourceNotFound
+	}
+
+	doc := &Packument{
+		ID:       name.String(),
+		Name:     name.String(),
+		DistTags: make(map[string]string),
+		Versions: make(map[string]map[string]any),
+		Time:     make(map[string]string),
+	}
+
+	var created, modified int64
+	for _, ver := range versions {
+		assets, e := p.artStore.Assets().Search(ctx,
+			types.SearchAssetOption{Kind: types.AssetKindMain, VersionId: ver.ID},
+			artifacts.AssetExcludeDeletedOption{})
+		if e != nil {
+			return nil, e
+		}
+		if len(assets) < 1 || assets[0].Metadata == "" {
+			logger.Warn().Msgf("ignore npm version without manifest of %s", ver.Version)
+			continue
+		}
+		asset := assets[0]
+
+		var manifest map[string]any
+		if e = json.Unmarshal([]byte(asset.Metadata), &manifest); e != nil {
+			logger.Warn().Err(e).Msgf("ignore invalid npm manifest of %s", ver.Version)
+			continue
+		}
+
+		dist, _ := manifest["dist"].(map[string]any)
+		if dist == nil {
+			dist = make(map[string]any)
+			manifest["dist"] = dist
+		}
+		dist["tarball"] = urlFn(name, asset.Path)
+		var hash adapter.Hash
+		if e = json.Unmarshal([]byte(asset.CheckSum), &hash); e == nil {
+			dist["shasum"] = hash.Sha1
+			dist["integrity"] = Integrity(&hash)
+		}
+		doc.Versions[ver.Version] = manifest
+		doc.Time[ver.Version] = formatTime(ver.Created)
+
+		for _, tag := range parseVersionMetadata(ctx, ver).DistTags {
+			doc.DistTags[tag] = ver.Version
+		}
+
+		if created == 0 || ver.Created < created {
+			created = ver.Created
+		}
+		modified = max(modified, ver.Updated)
+	}
+
+	if len(doc.Versions) == 0 {
+		return nil, gitfox_store.ErrResourceNotFound
+	}
+
+	if _, ok := doc.DistTags[TagLatest]; !ok {
+		doc.DistTags[TagLatest] = highestVersion(doc.Versions)
+	}
+	if latest, ok := doc.Versions[doc.DistTags[TagLatest]]; ok {
+		doc.Description, _ = latest["description"].(string)
+	}
+	doc.Time["created"] = formatTime(created)
+	doc.Time["modified"] = formatTime(modified)
+	return doc, nil
+}
+
+// highestVersion prefers stable releases, pre-releases are only used if there is no stable one
+func highestVersion(versions map[string]map[string]any) string {
+	var stable, highest *semver.Version
+	for v := range versions {
+		sv, err := semver.NewVersion(v)
+		if err != nil {
+			continue
+		}
+		if highest == nil || sv.GreaterThan(highest) {
+			highest = sv
+		}
+		if sv.Prerelease() == "" && (stable == nil || sv.GreaterThan(stable)) {
+			stable = sv
+		}
+	}
+
+	switch {
+	case stable != nil:
+		return stable.Original()
+	case highest != nil:
+		return highest.Original()
+	default:
+		return ""
+	}
+}
+
+func formatTime(milli int64) string {
+	return time.UnixMilli(milli).UTC().Format(_timeLayout)
+}
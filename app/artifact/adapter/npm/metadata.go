@@ -0,0 +1,32 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package npm
+
+import (
+	"encoding/json"
+
+	"github.com/easysoft/gitfox/app/services/protection"
+)
+
+// AssetMetadata keeps the version manifest of the publish request, it is used to rebuild the packument
+type AssetMetadata struct {
+	data json.RawMessage
+}
+
+func (am AssetMetadata) ToJSON() (json.RawMessage, error) {
+	return am.data, nil
+}
+
+// VersionMetadata extends adapter.VersionMetadata with the dist-tags pointing to the version
+type VersionMetadata struct {
+	CreatorName string   `json:"creator_name"`
+	DistTags    []string `json:"dist_tags,omitempty"`
+}
+
+func (m *VersionMetadata) ToJSON() (json.RawMessage, error) {
+	return protection.ToJSON(m)
+}
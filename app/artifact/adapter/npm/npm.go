@@ -0,0 +1,186 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package npm
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/easysoft/gitfox/app/artifact/adapter"
+	"github.com/easysoft/gitfox/app/artifact/adapter/request"
+	"github.com/easysoft/gitfox/app/store"
+	"github.com/easysoft/gitfox/pkg/storage"
+	gitfox_store "github.com/easysoft/gitfox/store"
+	"github.com/easysoft/gitfox/types"
+)
+
+const (
+	_tarballType = "application/octet-stream"
+)
+
+type uploader struct {
+	uploadReq *request.ArtifactUploadRequest
+	artStore  store.ArtifactStore
+	store     storage.ContentStorage
+	view      *adapter.ViewDescriptor
+
+	storeLayout adapter.StorageLayout
+
+	name       *Name
+	manifest   *VersionManifest
+	distTags   map[string]string
+	descriptor *adapter.PackageDescriptor
+}
+
+func NewUploader(contentStore storage.ContentStorage, artStore store.ArtifactStore, view *adapter.ViewDescriptor, name *Name) adapter.ArtifactPackageUploader {
+	return &uploader{
+		uploadReq:   request.NewUpload(view, artStore),
+		artStore:    artStore,
+		store:       contentStore,
+		view:        view,
+		storeLayout: adapter.StorageLayoutBlob,
+		name:        name,
+		descriptor:  adapter.NewEmptyPackageDescriptor(),
+	}
+}
+
+func (h *uploader) Serve(ctx context.Context, req *http.Request) (int64, error) {
+	var in PublishRequest
+	if err := json.NewDecoder(req.Body).Decode(&in); err != nil {
+		return 0, adapter.ErrInvalidPackageContent.WithDetail(err.Error())
+	}
+
+	if in.Name != h.name.String() {
+		return 0, adapter.ErrInvalidPackageName.WithDetail("package name does not match the request path")
+	}
+	if len(in.Versions) != 1 || len(in.Attachments) != 1 {
+		return 0, ErrMissingAttachment
+	}
+
+	var rawManifest json.RawMessage
+	for _, v := range in.Versions {
+		rawManifest = v
+	}
+	var manifest VersionManifest
+	if err := json.Unmarshal(rawManifest, &manifest); err != nil {
+		return 0, adapter.ErrInvalidPackageContent.WithDetail(err.Error())
+	}
+	if err := ValidateVersion(manifest.Version); err != nil {
+		return 0, err
+	}
+	h.manifest = &manifest
+	h.distTags = in.DistTags
+
+	attachment, ok := in.Attachments[h.name.String()+"-"+manifest.Version+".tgz"]
+	if !ok {
+		return 0, ErrAttachmentMismatch
+	}
+
+	fw, ref, err := adapter.NewRandomBlobWriter(ctx, h.store)
+	if err != nil {
+		return 0, err
+	}
+
+	h.uploadReq.RegisterWriter(fw)
+	size, hash, err := request.Write(base64.NewDecoder(base64.StdEncoding, strings.NewReader(attachment.Data)), fw)
+	if err != nil {
+		return 0, adapter.ErrInvalidPackageContent.WithDetail(err.Error())
+	}
+
+	h.descriptor.MainAsset.Path = h.name.TarballName(manifest.Version)
+	h.descriptor.MainAsset.Size = size
+	h.descriptor.MainAsset.Hash = hash
+	h.descriptor.MainAsset.Ref = ref
+	h.descriptor.MainAsset.ContentType = _tarballType
+	h.descriptor.MainAsset.Format = types.ArtifactNpmFormat
+	h.descriptor.MainAsset.Kind = types.AssetKindMain
+	h.descriptor.MainAsset.Attr = adapter.AttrAssetNormal
+	h.descriptor.MainAsset.Metadata = &AssetMetadata{data: rawManifest}
+
+	h.descriptor.Name = h.name.Name
+	h.descriptor.Namespace = h.name.Scope
+	h.descriptor.Version = manifest.Version
+	h.descriptor.Format = types.ArtifactNpmFormat
+	return size, nil
+}
+
+func (h *uploader) IsValid(ctx context.Context) error {
+	hash := h.descriptor.MainAsset.Hash
+	if h.manifest.Dist.Shasum != "" && !strings.EqualFold(h.manifest.Dist.Shasum, hash.Sha1) {
+		return adapter.ErrChecksumMismatch
+	}
+	if h.manifest.Dist.Integrity != "" && h.manifest.Dist.Integrity != Integrity(hash) {
+		return adapter.ErrChecksumMismatch
+	}
+
+	for tag := range h.distTags {
+		if err := ValidateTag(tag); err != nil {
+			return err
+		}
+	}
+
+	pkg, err := h.artStore.Packages().GetByName(ctx, h.name.Name, h.name.Scope, h.view.OwnerID, types.ArtifactNpmFormat)
+	if err == nil {
+		ver, e := h.artStore.Versions().GetByVersion(ctx, pkg.ID, h.view.ViewID, h.manifest.Version)
+		if e == nil && !ver.IsDeleted() {
+			return ErrVersionExists
+		}
+		if e != nil && !errors.Is(e, gitfox_store.ErrResourceNotFound) {
+			return e
+		}
+	} else if !errors.Is(err, gitfox_store.ErrResourceNotFound) {
+		return err
+	}
+
+	u := h.uploadReq.LoadCreator(ctx)
+	h.descriptor.VersionMetadata = &VersionMetadata{CreatorName: u.UID}
+
+	h.uploadReq.Descriptor = h.descriptor
+	return nil
+}
+
+func (h *uploader) Save(ctx context.Context) error {
+	if err := h.uploadReq.Commit(ctx); err != nil {
+		_ = h.uploadReq.Cancel(ctx)
+		return err
+	}
+
+	pkg, err := h.artStore.Packages().GetByName(ctx, h.name.Name, h.name.Scope, h.view.OwnerID, types.ArtifactNpmFormat)
+	if err != nil {
+		return err
+	}
+
+	tagMgr := NewTagManager(h.artStore, h.view)
+	for tag, version := range h.distTags {
+		// npm publish only sends the tag of the new version
+		if version != h.manifest.Version {
+			continue
+		}
+		if err = tagMgr.Set(ctx, pkg, tag, version); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *uploader) Cancel(ctx context.Context) error {
+	return h.uploadReq.Cancel(ctx)
+}
+
+// Integrity returns the subresource integrity string of the tarball
+func Integrity(hash *adapter.Hash) string {
+	raw, err := hex.DecodeString(hash.Sha512)
+	if err != nil {
+		return ""
+	}
+	return "sha512-" + base64.StdEncoding.EncodeToString(raw)
+}
@@ -0,0 +1,192 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package npm_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/easysoft/gitfox/app/artifact/adapter"
+	"github.com/easysoft/gitfox/app/artifact/adapter/npm"
+	"github.com/easysoft/gitfox/app/artifact/adapter/testsuite"
+	"github.com/easysoft/gitfox/types"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type NpmSuite struct {
+	testsuite.BaseSuite
+}
+
+func TestNpmSuite(t *testing.T) {
+	ctx := context.Background()
+
+	st := &NpmSuite{
+		BaseSuite: testsuite.BaseSuite{
+			Ctx:  ctx,
+			Name: "npm",
+		},
+	}
+
+	st.BaseSuite.Constructor = func(ts *testsuite.TestStore) {
+	}
+
+	suite.Run(t, st)
+}
+
+func (suite *NpmSuite) SetupTest() {
+}
+
+func (suite *NpmSuite) TearDownTest() {
+}
+
+func (suite *NpmSuite) TestPublish() {
+	name := &npm.Name{Scope: "@easycorp", Name: "demo"}
+
+	invalidTests := []struct {
+		body      []byte
+		expectErr error
+	}{
+		{body: []byte("{"), expectErr: adapter.ErrInvalidPackageContent},
+		{body: publishBody("@easycorp/other", "1.0.0", "latest", "content", ""), expectErr: adapter.ErrInvalidPackageName},
+		{body: publishBody(name.String(), "1.0", "latest", "content", ""), expectErr: adapter.ErrInvalidPackageVersion},
+		{body: publishBody(name.String(), "1.0.0", "1.x", "content", ""), expectErr: npm.ErrInvalidTag},
+		{body: publishBody(name.String(), "1.0.0", "latest", "content", "0000"), expectErr: adapter.ErrChecksumMismatch},
+	}
+
+	for idx, test := range invalidTests {
+		err := suite.publish(name, test.body)
+		require.ErrorContains(suite.T(), err, test.expectErr.Error(), "loop id: %d", idx)
+	}
+
+	require.NoError(suite.T(), suite.publish(name, publishBody(name.String(), "1.0.0", "latest", "v1", "")))
+	require.NoError(suite.T(), suite.publish(name, publishBody(name.String(), "1.1.0-beta.1", "beta", "v1.1 beta", "")))
+	require.NoError(suite.T(), suite.publish(name, publishBody(name.String(), "1.1.0", "latest", "v1.1", "")))
+
+	err := suite.publish(name, publishBody(name.String(), "1.1.0", "latest", "v1.1 again", ""))
+	require.ErrorContains(suite.T(), err, npm.ErrVersionExists.Error())
+
+	pkg, err := suite.Store.Artifacts.Packages().GetByName(suite.Ctx, name.Name, name.Scope, suite.DefaultView.OwnerID, types.ArtifactNpmFormat)
+	require.NoError(suite.T(), err)
+
+	tagMgr := npm.NewTagManager(suite.Store.Artifacts, suite.DefaultView)
+	tags, err := tagMgr.List(suite.Ctx, pkg)
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), map[string]string{"latest": "1.1.0", "beta": "1.1.0-beta.1"}, tags)
+
+	require.NoError(suite.T(), tagMgr.Set(suite.Ctx, pkg, "stable", "1.0.0"))
+	require.NoError(suite.T(), tagMgr.Set(suite.Ctx, pkg, "beta", "1.1.0"))
+	require.NoError(suite.T(), tagMgr.Delete(suite.Ctx, pkg, "stable"))
+	require.ErrorIs(suite.T(), tagMgr.Delete(suite.Ctx, pkg, npm.TagLatest), npm.ErrLatestTagRequired)
+	require.ErrorContains(suite.T(), tagMgr.Set(suite.Ctx, pkg, "1.0.0", "1.0.0"), npm.ErrInvalidTag.Error())
+
+	index := npm.NewPackumentIndex(suite.Store.Artifacts, suite.DefaultView)
+	doc, err := index.Build(suite.Ctx, pkg, func(n *npm.Name, filename string) string {
+		return "http://localhost/" + n.String() + "/-/" + filename
+	})
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), "@easycorp/demo", doc.Name)
+	require.Equal(suite.T(), map[string]string{"latest": "1.1.0", "beta": "1.1.0"}, doc.DistTags)
+	require.Len(suite.T(), doc.Versions, 3)
+
+	dist := doc.Versions["1.0.0"]["dist"].(map[string]any)
+	require.Equal(suite.T(), "http://localhost/@easycorp/demo/-/demo-1.0.0.tgz", dist["tarball"])
+	require.Equal(suite.T(), sha1Hex("v1"), dist["shasum"])
+}
+
+func (suite *NpmSuite) publish(name *npm.Name, body []byte) error {
+	req, err := http.NewRequest(http.MethodPut, "http://localhost:8080", bytes.NewReader(body))
+	require.NoError(suite.T(), err)
+
+	uploader := npm.NewUploader(suite.ArtifactStore, suite.Store.Artifacts, suite.DefaultView, name)
+	return handleUpload(suite.Ctx, uploader, req)
+}
+
+func publishBody(name, version, tag, content, shasum string) []byte {
+	if shasum == "" {
+		shasum = sha1Hex(content)
+	}
+	filename := name + "-" + version + ".tgz"
+	body := map[string]any{
+		"_id":       name,
+		"name":      name,
+		"dist-tags": map[string]string{tag: version},
+		"versions": map[string]any{
+			version: map[string]any{
+				"name":    name,
+				"version": version,
+				"dist":    map[string]string{"shasum": shasum, "tarball": "http://npm/" + filename},
+			},
+		},
+		"_attachments": map[string]any{
+			filename: map[string]any{
+				"content_type": "application/octet-stream",
+				"data":         base64.StdEncoding.EncodeToString([]byte(content)),
+				"length":       len(content),
+			},
+		},
+	}
+	data, _ := json.Marshal(body)
+	return data
+}
+
+func sha1Hex(content string) string {
+	sum := sha1.Sum([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestParseName(t *testing.T) {
+	tests := []struct {
+		path   string
+		expect *npm.Name
+	}{
+		{path: "demo", expect: &npm.Name{Name: "demo"}},
+		{path: "@easycorp/demo", expect: &npm.Name{Scope: "@easycorp", Name: "demo"}},
+		{path: "@easycorp%2fdemo", expect: &npm.Name{Scope: "@easycorp", Name: "demo"}},
+		{path: "@easycorp%2Fdemo.js", expect: &npm.Name{Scope: "@easycorp", Name: "demo.js"}},
+		{path: "Demo"},
+		{path: ".demo"},
+		{path: "@easycorp/"},
+		{path: "a/b"},
+	}
+
+	for idx, test := range tests {
+		name, err := npm.ParseName(test.path)
+		if test.expect == nil {
+			require.Error(t, err, "loop id: %d", idx)
+			continue
+		}
+		require.NoError(t, err, "loop id: %d", idx)
+		require.Equal(t, test.expect, name, "loop id: %d", idx)
+	}
+}
+
+func handleUpload(ctx context.Context, uploader adapter.ArtifactPackageUploader, req *http.Request) error {
+	_, err := uploader.Serve(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	err = uploader.IsValid(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = uploader.Save(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
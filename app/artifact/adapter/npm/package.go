@@ -0,0 +1,119 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package npm
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/easysoft/gitfox/app/artifact/adapter"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+const (
+	// https://github.com/npm/validate-npm-package-name
+	_regexPackageName = `^(?:@[a-z0-9\-~][a-z0-9\-._~]*/)?[a-z0-9\-~][a-z0-9\-._~]*$`
+	_maxNameLength    = 214
+
+	TagLatest = "latest"
+)
+
+var nameRegex = regexp.MustCompile(_regexPackageName)
+
+// Name is a npm package name, Scope keeps the '@' prefix and is used as the package namespace
+type Name struct {
+	Scope string
+	Name  string
+}
+
+// ParseName parses a package name from request path, the scope separator may be url encoded
+func ParseName(p string) (*Name, error) {
+	fullName, err := url.PathUnescape(strings.Trim(p, "/"))
+	if err != nil {
+		return nil, adapter.ErrInvalidPackageName
+	}
+
+	if len(fullName) > _maxNameLength || !nameRegex.MatchString(fullName) {
+		return nil, adapter.ErrInvalidPackageName.WithDetail(fmt.Sprintf("require regex pattern: %s", _regexPackageName))
+	}
+
+	if scope, name, found := strings.Cut(fullName, "/"); found {
+		return &Name{Scope: scope, Name: name}, nil
+	}
+	return &Name{Name: fullName}, nil
+}
+
+func (n *Name) String() string {
+	if n.Scope == "" {
+		return n.Name
+	}
+	return n.Scope + "/" + n.Name
+}
+
+// TarballName returns the tarball filename, the scope is not part of it
+func (n *Name) TarballName(version string) string {
+	return fmt.Sprintf("%s-%s.tgz", n.Name, version)
+}
+
+// ValidateVersion checks the version is a strict semantic version
+func ValidateVersion(version string) error {
+	if _, err := semver.StrictNewVersion(version); err != nil {
+		return adapter.ErrInvalidPackageVersion.WithDetail(err.Error())
+	}
+	return nil
+}
+
+// ValidateTag rejects tags which could be confused with a version or range
+func ValidateTag(tag string) error {
+	if tag == "" || url.PathEscape(tag) != tag {
+		return ErrInvalidTag
+	}
+	if _, err := semver.NewConstraint(tag); err == nil {
+		return ErrInvalidTag.WithDetail("tag must not be a valid semver range")
+	}
+	return nil
+}
+
+// PublishRequest is the document sent by npm publish
+type PublishRequest struct {
+	ID          string                     `json:"_id"`
+	Name        string                     `json:"name"`
+	DistTags    map[string]string          `json:"dist-tags"`
+	Versions    map[string]json.RawMessage `json:"versions"`
+	Attachments map[string]*Attachment     `json:"_attachments"`
+}
+
+type Attachment struct {
+	ContentType string `json:"content_type"`
+	Data        string `json:"data"`
+	Length      int64  `json:"length"`
+}
+
+// VersionManifest contains the fields of a version manifest used by the registry,
+// the full manifest is kept as asset metadata.
+type VersionManifest struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Dist    struct {
+		Shasum    string `json:"shasum"`
+		Integrity string `json:"integrity"`
+	} `json:"dist"`
+}
+
+// Packument is the package document served to npm install
+type Packument struct {
+	ID          string                    `json:"_id"`
+	Name        string                    `json:"name"`
+	Description string                    `json:"description,omitempty"`
+	DistTags    map[string]string         `json:"dist-tags"`
+	Versions    map[string]map[string]any `json:"versions"`
+	Time        map[string]string         `json:"time"`
+}
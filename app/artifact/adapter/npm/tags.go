@@ -0,0 +1,125 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package npm
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+
+	"github.com/easysoft/gitfox/app/artifact/adapter"
+	"github.com/easysoft/gitfox/app/store"
+	gitfox_store "github.com/easysoft/gitfox/store"
+	"github.com/easysoft/gitfox/types"
+
+	"github.com/rs/zerolog/log"
+)
+
+// tagManager maintains the dist-tags of a package, every tag is recorded in the metadata of the version it points to
+type tagManager struct {
+	artStore store.ArtifactStore
+	view     *adapter.ViewDescriptor
+}
+
+func NewTagManager(artStore store.ArtifactStore, view *adapter.ViewDescriptor) *tagManager {
+	return &tagManager{artStore: artStore, view: view}
+}
+
+func (t *tagManager) List(ctx context.Context, pkg *types.ArtifactPackage) (map[string]string, error) {
+	versions, err := adapter.ListVersions(ctx, t.artStore, types.SearchVersionOption{
+		PackageId: pkg.ID, ViewId: t.view.ViewID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make(map[string]string)
+	for _, ver := range versions {
+		for _, tag := range parseVersionMetadata(ctx, ver).DistTags {
+			tags[tag] = ver.Version
+		}
+	}
+	return tags, nil
+}
+
+func (t *tagManager) Set(ctx context.Context, pkg *types.ArtifactPackage, tag, version string) error {
+	if err := ValidateTag(tag); err != nil {
+		return err
+	}
+
+	target, err := t.artStore.Versions().GetByVersion(ctx, pkg.ID, t.view.ViewID, version)
+	if err != nil {
+		return err
+	}
+	if target.IsDeleted() {
+		return gitfox_store.ErrResourceNotFound
+	}
+
+	if err = t.remove(ctx, pkg, tag, target.ID); err != nil {
+		return err
+	}
+
+	meta := parseVersionMetadata(ctx, target)
+	if slices.Contains(meta.DistTags, tag) {
+		return nil
+	}
+	meta.DistTags = append(meta.DistTags, tag)
+	return t.updateMetadata(ctx, target, meta)
+}
+
+func (t *tagManager) Delete(ctx context.Context, pkg *types.ArtifactPackage, tag string) error {
+	if tag == TagLatest {
+		return ErrLatestTagRequired
+	}
+	return t.remove(ctx, pkg, tag, 0)
+}
+
+// remove drops the tag from all versions except the excluded one
+func (t *tagManager) remove(ctx context.Context, pkg *types.ArtifactPackage, tag string, excludeId int64) error {
+	versions, err := adapter.ListVersions(ctx, t.artStore, types.SearchVersionOption{
+		PackageId: pkg.ID, ViewId: t.view.ViewID,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, ver := range versions {
+		if ver.ID == excludeId {
+			continue
+		}
+		meta := parseVersionMetadata(ctx, ver)
+		idx := slices.Index(meta.DistTags, tag)
+		if idx < 0 {
+			continue
+		}
+		meta.DistTags = slices.Delete(meta.DistTags, idx, idx+1)
+		if err = t.updateMetadata(ctx, ver, meta); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *tagManager) updateMetadata(ctx context.Context, ver *types.ArtifactVersion, meta *VersionMetadata) error {
+	data, err := meta.ToJSON()
+	if err != nil {
+		return err
+	}
+	ver.Metadata = string(data)
+	return t.artStore.Versions().Update(ctx, ver)
+}
+
+func parseVersionMetadata(ctx context.Context, ver *types.ArtifactVersion) *VersionMetadata {
+	var meta VersionMetadata
+	if ver.Metadata == "" {
+		return &meta
+	}
+	if err := json.Unmarshal([]byte(ver.Metadata), &meta); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msgf("ignore invalid npm version metadata of %s", ver.Version)
+	}
+	return &meta
+}
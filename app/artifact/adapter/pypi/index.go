@@ -0,0 +1,228 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package pypi
+
+import (
+	"context"
+	"encoding/json"
+	"html/template"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/easysoft/gitfox/app/artifact/adapter"
+	"github.com/easysoft/gitfox/app/store"
+	"github.com/easysoft/gitfox/app/store/database/artifacts"
+	gitfox_store "github.com/easysoft/gitfox/store"
+	"github.com/easysoft/gitfox/types"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	ContentTypeSimpleHTML = "text/html"
+	ContentTypeSimpleJSON = "application/vnd.pypi.simple.v1+json"
+
+	_contentTypeSimpleV1HTML     = "application/vnd.pypi.simple.v1+html"
+	_contentTypeSimpleLatestHTML = "application/vnd.pypi.simple.latest+html"
+	_contentTypeSimpleLatestJSON = "application/vnd.pypi.simple.latest+json"
+
+	_apiVersion = "1.0"
+)
+
+var (
+	_projectListTemplate = template.Must(template.New("projects").Parse(`<!DOCTYPE html>
+<html>
+  <head>
+    <meta name="pypi:repository-version" content="{{ .Meta.APIVersion }}">
+    <title>Simple index</title>
+  </head>
+  <body>
+{{- range .Projects }}
+    <a href="{{ .Name }}/">{{ .Name }}</a>
+{{- end }}
+  </body>
+</html>
+`))
+
+	_projectTemplate = template.Must(template.New("project").Parse(`<!DOCTYPE html>
+<html>
+  <head>
+    <meta name="pypi:repository-version" content="{{ .Meta.APIVersion }}">
+    <title>Links for {{ .Name }}</title>
+  </head>
+  <body>
+    <h1>Links for {{ .Name }}</h1>
+{{- range .Files }}
+    <a href="{{ .URL }}#sha256={{ .Hashes.sha256 }}"{{ if .RequiresPython }} data-requires-python="{{ .RequiresPython }}"{{ end }}>{{ .Filename }}</a>
+{{- end }}
+  </body>
+</html>
+`))
+)
+
+type SimpleMeta struct {
+	APIVersion string `json:"api-version"`
+}
+
+// ProjectList is the root page of the simple repository api
+type ProjectList struct {
+	Meta     SimpleMeta      `json:"meta"`
+	Projects []*ProjectEntry `json:"projects"`
+}
+
+type ProjectEntry struct {
+	Name string `json:"name"`
+}
+
+// Project is the project detail page of the simple repository api
+type Project struct {
+	Meta  SimpleMeta `json:"meta"`
+	Name  string     `json:"name"`
+	Files []*File    `json:"files"`
+}
+
+type File struct {
+	Filename       string            `json:"filename"`
+	URL            string            `json:"url"`
+	Hashes         map[string]string `json:"hashes"`
+	RequiresPython string            `json:"requires-python,omitempty"`
+}
+
+// FileURLFunc returns the download url of a distribution file
+type FileURLFunc func(name, version, filename string) string
+
+type simpleIndex struct {
+	artStore store.ArtifactStore
+	view     *adapter.ViewDescriptor
+}
+
+func NewSimpleIndex(artStore store.ArtifactStore, view *adapter.ViewDescriptor) *simpleIndex {
+	return &simpleIndex{artStore: artStore, view: view}
+}
+
+// Projects lists the projects having at least one release in the view
+func (s *simpleIndex) Projects(ctx context.Context) (*ProjectList, error) {
+	pkgs, err := s.artStore.Packages().ListByNamespace(ctx, s.view.OwnerID, "", types.ArtifactPypiFormat, false, false)
+	if err != nil {
+		return nil, err
+	}
+
+	list := &ProjectList{Meta: SimpleMeta{APIVersion: _apiVersion}, Projects: make([]*ProjectEntry, 0, len(pkgs))}
+	for _, pkg := range pkgs {
+		versions, e := s.artStore.Versions().Find(ctx, types.SearchVersionOption{
+			PackageId: pkg.ID, ViewId: s.view.ViewID, Page: 1, Size: 1,
+		})
+		if e != nil {
+			return nil, e
+		}
+		if len(versions) == 0 {
+			continue
+		}
+		list.Projects = append(list.Projects, &ProjectEntry{Name: pkg.Name})
+	}
+
+	sort.Slice(list.Projects, func(i, j int) bool {
+		return list.Projects[i].Name < list.Projects[j].Name
+	})
+	return list, nil
+}
+
+// Project lists the distribution files of all releases of the package in the view
+func (s *simpleIndex) Project(ctx context.Context, pkg *types.ArtifactPackage, urlFn FileURLFunc) (*Project, error) {
+	logger := log.Ctx(ctx)
+
+	versions, err := adapter.ListVersions(ctx, s.artStore, types.SearchVersionOption{
+		PackageId: pkg.ID, ViewId: s.view.ViewID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	project := &Project{Meta: SimpleMeta{APIVersion: _apiVersion}, Name: pkg.Name, Files: make([]*File, 0)}
+	for _, ver := range versions {
+		assets, e := s.artStore.Assets().Search(ctx,
+			types.SearchAssetOption{Kind: types.AssetKindMain, VersionId: ver.ID},
+			artifacts.AssetExcludeDeletedOption{})
+		if e != nil {
+			return nil, e
+		}
+
+		for _, asset := range assets {
+			var hash adapter.Hash
+			if e = json.Unmarshal([]byte(asset.CheckSum), &hash); e != nil {
+				logger.Warn().Msgf("ignore invalid checksum asset of %s", asset.Path)
+				continue
+			}
+
+			var meta AssetMetadata
+			if asset.Metadata != "" {
+				if e = json.Unmarshal([]byte(asset.Metadata), &meta); e != nil {
+					logger.Warn().Err(e).Msgf("ignore invalid pypi metadata of %s", asset.Path)
+				}
+			}
+
+			project.Files = append(project.Files, &File{
+				Filename:       asset.Path,
+				URL:            urlFn(pkg.Name, ver.Version, asset.Path),
+				Hashes:         map[string]string{"sha256": hash.Sha256},
+				RequiresPython: meta.RequiresPython,
+			})
+		}
+	}
+
+	if len(project.Files) == 0 {
+		return nil, gitfox_store.ErrResourceNotFound
+	}
+
+	sort.Slice(project.Files, func(i, j int) bool {
+		return project.Files[i].Filename < project.Files[j].Filename
+	})
+	return project, nil
+}
+
+func (l *ProjectList) RenderHTML(w io.Writer) error {
+	return _projectListTemplate.Execute(w, l)
+}
+
+func (p *Project) RenderHTML(w io.Writer) error {
+	return _projectTemplate.Execute(w, p)
+}
+
+// NegotiateContentType selects the response format of the simple api from the Accept header,
+// html is served to clients without preference as described in PEP 691.
+func NegotiateContentType(accept string) string {
+	selected, quality := ContentTypeSimpleHTML, -1.0
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			k, v, found := strings.Cut(strings.TrimSpace(param), "=")
+			if found && k == "q" {
+				if f, err := strconv.ParseFloat(v, 64); err == nil {
+					q = f
+				}
+			}
+		}
+
+		var candidate string
+		switch strings.TrimSpace(mediaType) {
+		case ContentTypeSimpleJSON, _contentTypeSimpleLatestJSON:
+			candidate = ContentTypeSimpleJSON
+		case ContentTypeSimpleHTML, _contentTypeSimpleV1HTML, _contentTypeSimpleLatestHTML, "*/*":
+			candidate = ContentTypeSimpleHTML
+		default:
+			continue
+		}
+
+		if q > quality {
+			selected, quality = candidate, q
+		}
+	}
+	return selected
+}
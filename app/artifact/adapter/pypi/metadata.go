@@ -0,0 +1,185 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package pypi
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/mail"
+	"net/textproto"
+	"path"
+	"strings"
+
+	"github.com/easysoft/gitfox/app/services/protection"
+)
+
+const _maxMetadataSize = 1 << 20
+
+var errMetadataNotFound = errors.New("package metadata not found")
+
+// Metadata is the core metadata of a distribution,
+// https://packaging.python.org/en/latest/specifications/core-metadata/
+type Metadata struct {
+	MetadataVersion        string            `json:"metadata_version,omitempty"`
+	Name                   string            `json:"name"`
+	Version                string            `json:"version"`
+	Summary                string            `json:"summary,omitempty"`
+	Description            string            `json:"description,omitempty"`
+	DescriptionContentType string            `json:"description_content_type,omitempty"`
+	Keywords               string            `json:"keywords,omitempty"`
+	HomePage               string            `json:"home_page,omitempty"`
+	Author                 string            `json:"author,omitempty"`
+	AuthorEmail            string            `json:"author_email,omitempty"`
+	Maintainer             string            `json:"maintainer,omitempty"`
+	MaintainerEmail        string            `json:"maintainer_email,omitempty"`
+	License                string            `json:"license,omitempty"`
+	RequiresPython         string            `json:"requires_python,omitempty"`
+	RequiresDist           []string          `json:"requires_dist,omitempty"`
+	Classifiers            []string          `json:"classifiers,omitempty"`
+	ProjectURLs            map[string]string `json:"project_urls,omitempty"`
+}
+
+// ParseMetadata parses the email header formatted METADATA or PKG-INFO file
+func ParseMetadata(r io.Reader) (*Metadata, error) {
+	msg, err := mail.ReadMessage(io.LimitReader(r, _maxMetadataSize))
+	if err != nil {
+		return nil, err
+	}
+
+	header := textproto.MIMEHeader(msg.Header)
+	m := &Metadata{
+		MetadataVersion:        header.Get("Metadata-Version"),
+		Name:                   header.Get("Name"),
+		Version:                header.Get("Version"),
+		Summary:                header.Get("Summary"),
+		Description:            header.Get("Description"),
+		DescriptionContentType: header.Get("Description-Content-Type"),
+		Keywords:               header.Get("Keywords"),
+		HomePage:               header.Get("Home-Page"),
+		Author:                 header.Get("Author"),
+		AuthorEmail:            header.Get("Author-Email"),
+		Maintainer:             header.Get("Maintainer"),
+		MaintainerEmail:        header.Get("Maintainer-Email"),
+		License:                header.Get("License"),
+		RequiresPython:         header.Get("Requires-Python"),
+		RequiresDist:           header.Values("Requires-Dist"),
+		Classifiers:            header.Values("Classifier"),
+	}
+
+	for _, v := range header.Values("Project-Url") {
+		label, link, found := strings.Cut(v, ",")
+		if !found {
+			continue
+		}
+		if m.ProjectURLs == nil {
+			m.ProjectURLs = make(map[string]string)
+		}
+		m.ProjectURLs[strings.TrimSpace(label)] = strings.TrimSpace(link)
+	}
+
+	// since metadata 2.1 the description is allowed in the message body
+	if m.Description == "" {
+		body, e := io.ReadAll(msg.Body)
+		if e != nil {
+			return nil, e
+		}
+		m.Description = strings.TrimSpace(string(body))
+	}
+
+	if m.Name == "" || m.Version == "" {
+		return nil, errors.New("name and version are required in package metadata")
+	}
+	return m, nil
+}
+
+// ExtractMetadata reads the metadata from the '*.dist-info/METADATA' of a wheel or the top level
+// 'PKG-INFO' of a source distribution, only the metadata file is read from the distribution.
+func ExtractMetadata(info *FileInfo, r io.ReaderAt, size int64) (*Metadata, error) {
+	switch info.Extension {
+	case _extWheel:
+		return extractZipMetadata(r, size, func(name string) bool {
+			dir, file := path.Split(name)
+			return file == "METADATA" && strings.Count(dir, "/") == 1 && strings.HasSuffix(dir, ".dist-info/")
+		})
+	case _extZip:
+		return extractZipMetadata(r, size, isSdistMetadata)
+	default:
+		return extractTarMetadata(io.NewSectionReader(r, 0, size), isSdistMetadata)
+	}
+}
+
+func isSdistMetadata(name string) bool {
+	dir, file := path.Split(strings.TrimPrefix(name, "./"))
+	return file == "PKG-INFO" && strings.Count(dir, "/") == 1
+}
+
+func extractZipMetadata(r io.ReaderAt, size int64, match func(name string) bool) (*Metadata, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range zr.File {
+		if !match(f.Name) {
+			continue
+		}
+		r, e := f.Open()
+		if e != nil {
+			return nil, e
+		}
+		defer r.Close()
+		return ParseMetadata(r)
+	}
+	return nil, errMetadataNotFound
+}
+
+func extractTarMetadata(r io.Reader, match func(name string) bool) (*Metadata, error) {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer gr.Close()
+
+	tr := tar.NewReader(gr)
+	for {
+		hdr, e := tr.Next()
+		if e == io.EOF {
+			return nil, errMetadataNotFound
+		}
+		if e != nil {
+			return nil, e
+		}
+		if hdr.Typeflag == tar.TypeReg && match(hdr.Name) {
+			return ParseMetadata(tr)
+		}
+	}
+}
+
+// AssetMetadata keeps the upload attributes of a distribution file, they are listed in the simple index
+type AssetMetadata struct {
+	FileType       string `json:"filetype"`
+	PythonVersion  string `json:"pyversion,omitempty"`
+	RequiresPython string `json:"requires_python,omitempty"`
+}
+
+func (am *AssetMetadata) ToJSON() (json.RawMessage, error) {
+	return protection.ToJSON(am)
+}
+
+// VersionMetadata extends adapter.VersionMetadata with the core metadata of the release
+type VersionMetadata struct {
+	CreatorName string `json:"creator_name"`
+	*Metadata
+}
+
+func (m *VersionMetadata) ToJSON() (json.RawMessage, error) {
+	return protection.ToJSON(m)
+}
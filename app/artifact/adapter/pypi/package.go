@@ -0,0 +1,109 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package pypi
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/easysoft/gitfox/app/artifact/adapter"
+)
+
+const (
+	FileTypeSdist = "sdist"
+	FileTypeWheel = "bdist_wheel"
+
+	_extWheel = ".whl"
+	_extTarGz = ".tar.gz"
+	_extZip   = ".zip"
+)
+
+var (
+	// https://packaging.python.org/en/latest/specifications/name-normalization/
+	_regexName      = regexp.MustCompile(`(?i)^([a-z0-9]|[a-z0-9][a-z0-9._-]*[a-z0-9])$`)
+	_regexSeparator = regexp.MustCompile(`[-_.]+`)
+
+	// https://peps.python.org/pep-0440/#appendix-b-parsing-version-strings-with-regular-expressions
+	_regexVersion = regexp.MustCompile(`(?i)^v?(?:[0-9]+!)?[0-9]+(?:\.[0-9]+)*` +
+		`(?:[-_.]?(?:a|b|c|rc|alpha|beta|pre|preview)[-_.]?[0-9]*)?` +
+		`(?:-[0-9]+|[-_.]?(?:post|rev|r)[-_.]?[0-9]*)?` +
+		`(?:[-_.]?dev[-_.]?[0-9]*)?` +
+		`(?:\+[a-z0-9]+(?:[-_.][a-z0-9]+)*)?$`)
+)
+
+// NormalizeName returns the PEP 503 normalized form of a project name, packages are stored with it.
+func NormalizeName(name string) string {
+	return strings.ToLower(_regexSeparator.ReplaceAllString(name, "-"))
+}
+
+func ValidateName(name string) error {
+	if !_regexName.MatchString(name) {
+		return adapter.ErrInvalidPackageName
+	}
+	return nil
+}
+
+func ValidateVersion(version string) error {
+	if !_regexVersion.MatchString(version) {
+		return adapter.ErrInvalidPackageVersion
+	}
+	return nil
+}
+
+// FileInfo is the distribution information encoded in the filename
+type FileInfo struct {
+	Name      string
+	Version   string
+	Type      string
+	Extension string
+}
+
+// Matches reports whether the file belongs to the given project release, wheel filenames
+// escape '-' in the name and version with '_'.
+func (f *FileInfo) Matches(name, version string) bool {
+	return NormalizeName(f.Name) == NormalizeName(name) &&
+		strings.EqualFold(strings.ReplaceAll(f.Version, "_", "-"), strings.ReplaceAll(version, "_", "-"))
+}
+
+// ParseFilename parses the filename of a wheel '{name}-{version}(-{build})?-{python}-{abi}-{platform}.whl'
+// or a source distribution '{name}-{version}.tar.gz'.
+func ParseFilename(filename string) (*FileInfo, error) {
+	if strings.Contains(filename, "/") {
+		return nil, adapter.ErrInvalidPackagePath
+	}
+
+	switch {
+	case strings.HasSuffix(filename, _extWheel):
+		frames := strings.Split(strings.TrimSuffix(filename, _extWheel), "-")
+		if len(frames) != 5 && len(frames) != 6 {
+			return nil, adapter.ErrInvalidPackagePath.WithDetail("invalid wheel filename")
+		}
+		return &FileInfo{Name: frames[0], Version: frames[1], Type: FileTypeWheel, Extension: _extWheel}, nil
+	case strings.HasSuffix(filename, _extTarGz), strings.HasSuffix(filename, _extZip):
+		ext := _extTarGz
+		if strings.HasSuffix(filename, _extZip) {
+			ext = _extZip
+		}
+		base := strings.TrimSuffix(filename, ext)
+		idx := strings.LastIndex(base, "-")
+		if idx <= 0 || idx == len(base)-1 {
+			return nil, adapter.ErrInvalidPackagePath.WithDetail("invalid sdist filename")
+		}
+		return &FileInfo{Name: base[:idx], Version: base[idx+1:], Type: FileTypeSdist, Extension: ext}, nil
+	default:
+		return nil, adapter.ErrInvalidPackagePath.WithDetail("unsupported distribution type")
+	}
+}
+
+func contentType(extension string) string {
+	switch extension {
+	case _extTarGz:
+		return "application/gzip"
+	default:
+		return "application/zip"
+	}
+}
@@ -0,0 +1,222 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package pypi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/easysoft/gitfox/app/artifact/adapter"
+	"github.com/easysoft/gitfox/app/artifact/adapter/request"
+	"github.com/easysoft/gitfox/app/store"
+	"github.com/easysoft/gitfox/pkg/storage"
+	gitfox_store "github.com/easysoft/gitfox/store"
+	"github.com/easysoft/gitfox/types"
+)
+
+const (
+	_actionFileUpload = "file_upload"
+
+	_formAction         = ":action"
+	_formName           = "name"
+	_formVersion        = "version"
+	_formFileType       = "filetype"
+	_formPythonVersion  = "pyversion"
+	_formRequiresPython = "requires_python"
+	_formMd5Digest      = "md5_digest"
+	_formSha256Digest   = "sha256_digest"
+	_formContent        = "content"
+)
+
+type uploader struct {
+	uploadReq *request.ArtifactUploadRequest
+	artStore  store.ArtifactStore
+	store     storage.ContentStorage
+	view      *adapter.ViewDescriptor
+
+	storeLayout adapter.StorageLayout
+
+	form       *uploadForm
+	fileInfo   *FileInfo
+	descriptor *adapter.PackageDescriptor
+	meta       *Metadata
+	metaErr    error
+}
+
+// uploadForm is the multipart form of the legacy upload api used by twine
+type uploadForm struct {
+	name           string
+	version        string
+	fileType       string
+	pythonVersion  string
+	requiresPython string
+	md5Digest      string
+	sha256Digest   string
+}
+
+func NewUploader(contentStore storage.ContentStorage, artStore store.ArtifactStore, view *adapter.ViewDescriptor) adapter.ArtifactPackageUploader {
+	return &uploader{
+		uploadReq:   request.NewUpload(view, artStore),
+		artStore:    artStore,
+		store:       contentStore,
+		view:        view,
+		storeLayout: adapter.StorageLayoutBlob,
+		descriptor:  adapter.NewEmptyPackageDescriptor(),
+	}
+}
+
+func (h *uploader) Serve(ctx context.Context, req *http.Request) (int64, error) {
+	if err := req.ParseMultipartForm(32 << 20); err != nil {
+		return 0, ErrInvalidForm.WithDetail(err.Error())
+	}
+
+	if action := req.FormValue(_formAction); action != "" && action != _actionFileUpload {
+		return 0, ErrUnsupportedAction
+	}
+
+	h.form = &uploadForm{
+		name:           req.FormValue(_formName),
+		version:        req.FormValue(_formVersion),
+		fileType:       req.FormValue(_formFileType),
+		pythonVersion:  req.FormValue(_formPythonVersion),
+		requiresPython: req.FormValue(_formRequiresPython),
+		md5Digest:      req.FormValue(_formMd5Digest),
+		sha256Digest:   req.FormValue(_formSha256Digest),
+	}
+	if h.form.name == "" || h.form.version == "" {
+		return 0, adapter.ErrMissFormField
+	}
+	if err := ValidateName(h.form.name); err != nil {
+		return 0, err
+	}
+	if err := ValidateVersion(h.form.version); err != nil {
+		return 0, err
+	}
+
+	file, header, err := req.FormFile(_formContent)
+	if err != nil {
+		return 0, adapter.ErrMissFormField.WithDetail(err.Error())
+	}
+	defer file.Close()
+
+	info, err := ParseFilename(header.Filename)
+	if err != nil {
+		return 0, err
+	}
+	if !info.Matches(h.form.name, h.form.version) {
+		return 0, ErrFileMismatch
+	}
+	h.fileInfo = info
+
+	fw, ref, err := adapter.NewRandomBlobWriter(ctx, h.store)
+	if err != nil {
+		return 0, err
+	}
+
+	h.uploadReq.RegisterWriter(fw)
+	size, hash, err := request.Write(file, fw)
+	if err == nil {
+		// the form file is kept in memory or in a temporary file, the metadata is read from it
+		// instead of buffering the whole distribution.
+		h.meta, h.metaErr = ExtractMetadata(info, file, size)
+	}
+
+	h.descriptor.MainAsset.Path = header.Filename
+	h.descriptor.MainAsset.Size = size
+	h.descriptor.MainAsset.Hash = hash
+	h.descriptor.MainAsset.Ref = ref
+	h.descriptor.MainAsset.ContentType = contentType(info.Extension)
+	h.descriptor.MainAsset.Format = types.ArtifactPypiFormat
+	h.descriptor.MainAsset.Kind = types.AssetKindMain
+	h.descriptor.MainAsset.Attr = adapter.AttrAssetNormal
+
+	h.descriptor.Name = NormalizeName(h.form.name)
+	h.descriptor.Namespace = ""
+	h.descriptor.Version = h.form.version
+	h.descriptor.Format = types.ArtifactPypiFormat
+	return size, err
+}
+
+func (h *uploader) IsValid(ctx context.Context) error {
+	hash := h.descriptor.MainAsset.Hash
+	if h.form.md5Digest != "" && !strings.EqualFold(h.form.md5Digest, hash.Md5) {
+		return adapter.ErrChecksumMismatch
+	}
+	if h.form.sha256Digest != "" && !strings.EqualFold(h.form.sha256Digest, hash.Sha256) {
+		return adapter.ErrChecksumMismatch
+	}
+
+	if h.metaErr != nil {
+		return adapter.ErrInvalidPackageContent.WithDetail(h.metaErr.Error())
+	}
+	meta := h.meta
+	if NormalizeName(meta.Name) != h.descriptor.Name || meta.Version != h.form.version {
+		return ErrFileMismatch
+	}
+
+	if err := h.checkExists(ctx); err != nil {
+		return err
+	}
+
+	requiresPython := meta.RequiresPython
+	if requiresPython == "" {
+		requiresPython = h.form.requiresPython
+	}
+	h.descriptor.MainAsset.Metadata = &AssetMetadata{
+		FileType:       h.fileInfo.Type,
+		PythonVersion:  h.form.pythonVersion,
+		RequiresPython: requiresPython,
+	}
+
+	u := h.uploadReq.LoadCreator(ctx)
+	h.descriptor.VersionMetadata = &VersionMetadata{CreatorName: u.UID, Metadata: meta}
+
+	h.uploadReq.Descriptor = h.descriptor
+	return nil
+}
+
+func (h *uploader) Save(ctx context.Context) error {
+	if err := h.uploadReq.Commit(ctx); err != nil {
+		_ = h.uploadReq.Cancel(ctx)
+		return err
+	}
+	return nil
+}
+
+func (h *uploader) Cancel(ctx context.Context) error {
+	return h.uploadReq.Cancel(ctx)
+}
+
+// checkExists rejects overwriting a distribution file, the same as pypi.org does
+func (h *uploader) checkExists(ctx context.Context) error {
+	pkg, err := h.artStore.Packages().GetByName(ctx, h.descriptor.Name, "", h.view.OwnerID, types.ArtifactPypiFormat)
+	if errors.Is(err, gitfox_store.ErrResourceNotFound) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	ver, err := h.artStore.Versions().GetByVersion(ctx, pkg.ID, h.view.ViewID, h.descriptor.Version)
+	if errors.Is(err, gitfox_store.ErrResourceNotFound) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if ver.IsDeleted() {
+		return nil
+	}
+
+	_, err = h.artStore.Assets().GetVersionAsset(ctx, h.descriptor.MainAsset.Path, ver.ID)
+	if err == nil {
+		return ErrFileExists
+	} else if !errors.Is(err, gitfox_store.ErrResourceNotFound) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,288 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package pypi_test
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/easysoft/gitfox/app/artifact/adapter"
+	"github.com/easysoft/gitfox/app/artifact/adapter/pypi"
+	"github.com/easysoft/gitfox/app/artifact/adapter/testsuite"
+	"github.com/easysoft/gitfox/types"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const _metadata = `Metadata-Version: 2.1
+Name: Demo_Lib
+Version: 1.0.0
+Summary: A demo library
+Home-page: https://example.com/demo
+Author-email: dev@example.com
+Requires-Python: >=3.8
+Requires-Dist: requests (>=2.0)
+Requires-Dist: click
+Classifier: Programming Language :: Python :: 3
+Project-URL: Source, https://example.com/demo.git
+Description-Content-Type: text/markdown
+
+# Demo
+
+The demo library.
+`
+
+type PypiSuite struct {
+	testsuite.BaseSuite
+}
+
+func TestPypiSuite(t *testing.T) {
+	ctx := context.Background()
+
+	st := &PypiSuite{
+		BaseSuite: testsuite.BaseSuite{
+			Ctx:  ctx,
+			Name: "pypi",
+		},
+	}
+
+	st.BaseSuite.Constructor = func(ts *testsuite.TestStore) {
+	}
+
+	suite.Run(t, st)
+}
+
+func (suite *PypiSuite) SetupTest() {
+}
+
+func (suite *PypiSuite) TearDownTest() {
+}
+
+func (suite *PypiSuite) TestUpload() {
+	wheel := buildWheel(suite.T(), "demo_lib-1.0.0.dist-info/METADATA", _metadata)
+	sdist := buildSdist(suite.T(), "demo_lib-1.0.0/PKG-INFO", _metadata)
+
+	invalidTests := []struct {
+		fields    map[string]string
+		filename  string
+		content   []byte
+		expectErr error
+	}{
+		{fields: map[string]string{":action": "submit", "name": "demo-lib", "version": "1.0.0"}, filename: "demo_lib-1.0.0-py3-none-any.whl", content: wheel, expectErr: pypi.ErrUnsupportedAction},
+		{fields: map[string]string{"name": "demo-lib"}, filename: "demo_lib-1.0.0-py3-none-any.whl", content: wheel, expectErr: adapter.ErrMissFormField},
+		{fields: map[string]string{"name": "demo-lib", "version": "1.0.0"}, filename: "other-1.0.0-py3-none-any.whl", content: wheel, expectErr: pypi.ErrFileMismatch},
+		{fields: map[string]string{"name": "other", "version": "1.0.0"}, filename: "other-1.0.0-py3-none-any.whl", content: wheel, expectErr: pypi.ErrFileMismatch},
+		{fields: map[string]string{"name": "demo-lib", "version": "1.0.0"}, filename: "demo_lib-1.0.0.exe", content: wheel, expectErr: adapter.ErrInvalidPackagePath},
+		{fields: map[string]string{"name": "demo-lib", "version": "1.0.0", "sha256_digest": "0000"}, filename: "demo_lib-1.0.0-py3-none-any.whl", content: wheel, expectErr: adapter.ErrChecksumMismatch},
+		{fields: map[string]string{"name": "demo-lib", "version": "1.0.0"}, filename: "demo_lib-1.0.0-py3-none-any.whl", content: []byte("not a zip"), expectErr: adapter.ErrInvalidPackageContent},
+	}
+
+	for idx, test := range invalidTests {
+		err := suite.upload(test.fields, test.filename, test.content)
+		require.ErrorContains(suite.T(), err, test.expectErr.Error(), "loop id: %d", idx)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, "http://localhost:8080", strings.NewReader("--x\r\nbroken"))
+	require.NoError(suite.T(), err)
+	req.Header.Set("Content-Type", "multipart/form-data; boundary=x")
+	err = handleUpload(suite.Ctx, pypi.NewUploader(suite.ArtifactStore, suite.Store.Artifacts, suite.DefaultView), req)
+	require.ErrorContains(suite.T(), err, pypi.ErrInvalidForm.Error())
+
+	fields := map[string]string{
+		":action": "file_upload", "name": "Demo_Lib", "version": "1.0.0",
+		"filetype": pypi.FileTypeWheel, "pyversion": "py3", "sha256_digest": sha256Hex(wheel),
+	}
+	require.NoError(suite.T(), suite.upload(fields, "demo_lib-1.0.0-py3-none-any.whl", wheel))
+
+	fields["filetype"], fields["pyversion"], fields["sha256_digest"] = pypi.FileTypeSdist, "source", sha256Hex(sdist)
+	require.NoError(suite.T(), suite.upload(fields, "demo_lib-1.0.0.tar.gz", sdist))
+
+	err = suite.upload(fields, "demo_lib-1.0.0.tar.gz", sdist)
+	require.ErrorContains(suite.T(), err, pypi.ErrFileExists.Error())
+
+	pkg, err := suite.Store.Artifacts.Packages().GetByName(suite.Ctx, "demo-lib", "", suite.DefaultView.OwnerID, types.ArtifactPypiFormat)
+	require.NoError(suite.T(), err)
+
+	ver, err := suite.Store.Artifacts.Versions().GetByVersion(suite.Ctx, pkg.ID, suite.DefaultView.ViewID, "1.0.0")
+	require.NoError(suite.T(), err)
+	var verMeta pypi.VersionMetadata
+	require.NoError(suite.T(), json.Unmarshal([]byte(ver.Metadata), &verMeta))
+	require.Equal(suite.T(), "A demo library", verMeta.Summary)
+	require.Equal(suite.T(), []string{"requests (>=2.0)", "click"}, verMeta.RequiresDist)
+
+	index := pypi.NewSimpleIndex(suite.Store.Artifacts, suite.DefaultView)
+	projects, err := index.Projects(suite.Ctx)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), projects.Projects, 1)
+	require.Equal(suite.T(), "demo-lib", projects.Projects[0].Name)
+
+	project, err := index.Project(suite.Ctx, pkg, func(name, version, filename string) string {
+		return "http://localhost/files/" + name + "/" + version + "/" + filename
+	})
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), project.Files, 2)
+	require.Equal(suite.T(), "demo_lib-1.0.0-py3-none-any.whl", project.Files[0].Filename)
+	require.Equal(suite.T(), sha256Hex(wheel), project.Files[0].Hashes["sha256"])
+	require.Equal(suite.T(), ">=3.8", project.Files[0].RequiresPython)
+
+	buf := bytes.NewBuffer(nil)
+	require.NoError(suite.T(), project.RenderHTML(buf))
+	require.Contains(suite.T(), buf.String(),
+		`<a href="http://localhost/files/demo-lib/1.0.0/demo_lib-1.0.0.tar.gz#sha256=`+sha256Hex(sdist)+`" data-requires-python="&gt;=3.8">demo_lib-1.0.0.tar.gz</a>`)
+}
+
+func (suite *PypiSuite) upload(fields map[string]string, filename string, content []byte) error {
+	body := bytes.NewBuffer(nil)
+	mw := multipart.NewWriter(body)
+	for k, v := range fields {
+		require.NoError(suite.T(), mw.WriteField(k, v))
+	}
+	fw, err := mw.CreateFormFile("content", filename)
+	require.NoError(suite.T(), err)
+	_, err = fw.Write(content)
+	require.NoError(suite.T(), err)
+	require.NoError(suite.T(), mw.Close())
+
+	req, err := http.NewRequest(http.MethodPost, "http://localhost:8080", body)
+	require.NoError(suite.T(), err)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+
+	uploader := pypi.NewUploader(suite.ArtifactStore, suite.Store.Artifacts, suite.DefaultView)
+	return handleUpload(suite.Ctx, uploader, req)
+}
+
+func buildWheel(t *testing.T, name, metadata string) []byte {
+	buf := bytes.NewBuffer(nil)
+	zw := zip.NewWriter(buf)
+	f, err := zw.Create("demo_lib/__init__.py")
+	require.NoError(t, err)
+	_, _ = f.Write([]byte("VERSION = '1.0.0'\n"))
+	f, err = zw.Create(name)
+	require.NoError(t, err)
+	_, _ = f.Write([]byte(metadata))
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func buildSdist(t *testing.T, name, metadata string) []byte {
+	buf := bytes.NewBuffer(nil)
+	gw := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gw)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(metadata)), Typeflag: tar.TypeReg}))
+	_, err := tw.Write([]byte(metadata))
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+	require.NoError(t, gw.Close())
+	return buf.Bytes()
+}
+
+func sha256Hex(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+func TestParseFilename(t *testing.T) {
+	tests := []struct {
+		filename string
+		expect   *pypi.FileInfo
+	}{
+		{filename: "demo_lib-1.0.0-py3-none-any.whl", expect: &pypi.FileInfo{Name: "demo_lib", Version: "1.0.0", Type: pypi.FileTypeWheel, Extension: ".whl"}},
+		{filename: "demo_lib-1.0.0-1-cp311-cp311-manylinux_2_17_x86_64.whl", expect: &pypi.FileInfo{Name: "demo_lib", Version: "1.0.0", Type: pypi.FileTypeWheel, Extension: ".whl"}},
+		{filename: "demo-lib-1.0.0.tar.gz", expect: &pypi.FileInfo{Name: "demo-lib", Version: "1.0.0", Type: pypi.FileTypeSdist, Extension: ".tar.gz"}},
+		{filename: "demo-1.0rc1.zip", expect: &pypi.FileInfo{Name: "demo", Version: "1.0rc1", Type: pypi.FileTypeSdist, Extension: ".zip"}},
+		{filename: "demo-1.0.0-any.whl"},
+		{filename: "demo.tar.gz"},
+		{filename: "demo-1.0.0.exe"},
+		{filename: "../demo-1.0.0.tar.gz"},
+	}
+
+	for idx, test := range tests {
+		info, err := pypi.ParseFilename(test.filename)
+		if test.expect == nil {
+			require.Error(t, err, "loop id: %d", idx)
+			continue
+		}
+		require.NoError(t, err, "loop id: %d", idx)
+		require.Equal(t, test.expect, info, "loop id: %d", idx)
+	}
+}
+
+func TestNormalizeName(t *testing.T) {
+	for _, name := range []string{"Demo_Lib", "demo.lib", "DEMO--lib", "demo-_.lib"} {
+		require.Equal(t, "demo-lib", pypi.NormalizeName(name))
+	}
+}
+
+func TestValidateVersion(t *testing.T) {
+	for _, v := range []string{"1", "1.0.0", "1.0a1", "1.0.0rc2", "1.0.post1", "1.0.dev3", "2!1.0", "1.0+local.7"} {
+		require.NoError(t, pypi.ValidateVersion(v), v)
+	}
+	for _, v := range []string{"", "a1", "1.0-beta-x", "1..0"} {
+		require.Error(t, pypi.ValidateVersion(v), v)
+	}
+}
+
+func TestParseMetadata(t *testing.T) {
+	meta, err := pypi.ParseMetadata(strings.NewReader(_metadata))
+	require.NoError(t, err)
+	require.Equal(t, "Demo_Lib", meta.Name)
+	require.Equal(t, "https://example.com/demo", meta.HomePage)
+	require.Equal(t, "dev@example.com", meta.AuthorEmail)
+	require.Equal(t, map[string]string{"Source": "https://example.com/demo.git"}, meta.ProjectURLs)
+	require.Equal(t, "# Demo\n\nThe demo library.", meta.Description)
+
+	_, err = pypi.ParseMetadata(strings.NewReader("Metadata-Version: 2.1\nSummary: no name\n\n"))
+	require.Error(t, err)
+}
+
+func TestNegotiateContentType(t *testing.T) {
+	tests := []struct {
+		accept string
+		expect string
+	}{
+		{accept: "", expect: pypi.ContentTypeSimpleHTML},
+		{accept: "text/html", expect: pypi.ContentTypeSimpleHTML},
+		{accept: "application/vnd.pypi.simple.v1+json", expect: pypi.ContentTypeSimpleJSON},
+		{accept: "application/vnd.pypi.simple.v1+json, application/vnd.pypi.simple.v1+html;q=0.2, text/html;q=0.01", expect: pypi.ContentTypeSimpleJSON},
+		{accept: "application/vnd.pypi.simple.v1+json;q=0.1, text/html", expect: pypi.ContentTypeSimpleHTML},
+		{accept: "application/vnd.pypi.simple.latest+json", expect: pypi.ContentTypeSimpleJSON},
+	}
+
+	for idx, test := range tests {
+		require.Equal(t, test.expect, pypi.NegotiateContentType(test.accept), "loop id: %d", idx)
+	}
+}
+
+func handleUpload(ctx context.Context, uploader adapter.ArtifactPackageUploader, req *http.Request) error {
+	_, err := uploader.Serve(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	err = uploader.IsValid(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = uploader.Save(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,192 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package sbom
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/url"
+	"strings"
+)
+
+const (
+	FormatCycloneDX = "cyclonedx"
+	FormatSPDX      = "spdx"
+
+	// MediaTypeCycloneDX and MediaTypeSPDX are the artifact types of the sbom attached to images as referrers
+	MediaTypeCycloneDX = "application/vnd.cyclonedx+json"
+	MediaTypeSPDX      = "application/spdx+json"
+
+	_purlPrefix = "pkg:"
+)
+
+var (
+	ErrUnknownDocument = errors.New("unknown sbom document, only CycloneDX and SPDX json are supported")
+	ErrTooLarge        = errors.New("sbom document is too large")
+)
+
+// Component is a software component listed in the sbom.
+type Component struct {
+	Name    string
+	Group   string
+	Version string
+	Purl    string
+	Type    string
+}
+
+type Document struct {
+	Format     string
+	Components []*Component
+}
+
+func IsMediaType(mediaType string) bool {
+	return mediaType == MediaTypeCycloneDX || mediaType == MediaTypeSPDX
+}
+
+// Parse reads a CycloneDX or SPDX json document of at most maxSize bytes, the components are deduplicated.
+// ErrTooLarge is returned if the document exceeds maxSize.
+func Parse(r io.Reader, maxSize int64) (*Document, error) {
+	content, err := io.ReadAll(io.LimitReader(r, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(content)) > maxSize {
+		return nil, ErrTooLarge
+	}
+
+	var probe struct {
+		BomFormat   string `json:"bomFormat"`
+		SpdxVersion string `json:"spdxVersion"`
+	}
+	if err = json.Unmarshal(content, &probe); err != nil {
+		return nil, ErrUnknownDocument
+	}
+
+	var doc *Document
+	switch {
+	case probe.BomFormat == "CycloneDX":
+		doc, err = parseCycloneDX(content)
+	case strings.HasPrefix(probe.SpdxVersion, "SPDX-"):
+		doc, err = parseSPDX(content)
+	default:
+		return nil, ErrUnknownDocument
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	doc.Components = dedupe(doc.Components)
+	return doc, nil
+}
+
+type cdxComponent struct {
+	Type       string          `json:"type"`
+	Group      string          `json:"group"`
+	Name       string          `json:"name"`
+	Version    string          `json:"version"`
+	Purl       string          `json:"purl"`
+	Components []*cdxComponent `json:"components"`
+}
+
+func parseCycloneDX(content []byte) (*Document, error) {
+	var bom struct {
+		Components []*cdxComponent `json:"components"`
+	}
+	if err := json.Unmarshal(content, &bom); err != nil {
+		return nil, err
+	}
+
+	doc := &Document{Format: FormatCycloneDX, Components: make([]*Component, 0)}
+	// components may be nested, such as the libraries bundled in a jar
+	queue := bom.Components
+	for len(queue) > 0 {
+		c := queue[0]
+		queue = append(queue[1:], c.Components...)
+		if c.Name == "" {
+			continue
+		}
+
+		comp := &Component{Name: c.Name, Group: c.Group, Version: c.Version, Purl: c.Purl, Type: c.Type}
+		if comp.Group == "" {
+			comp.Group = purlNamespace(c.Purl)
+		}
+		doc.Components = append(doc.Components, comp)
+	}
+	return doc, nil
+}
+
+func parseSPDX(content []byte) (*Document, error) {
+	var spdx struct {
+		Packages []struct {
+			Name         string `json:"name"`
+			VersionInfo  string `json:"versionInfo"`
+			Purpose      string `json:"primaryPackagePurpose"`
+			ExternalRefs []struct {
+				ReferenceType    string `json:"referenceType"`
+				ReferenceLocator string `json:"referenceLocator"`
+			} `json:"externalRefs"`
+		} `json:"packages"`
+	}
+	if err := json.Unmarshal(content, &spdx); err != nil {
+		return nil, err
+	}
+
+	doc := &Document{Format: FormatSPDX, Components: make([]*Component, 0)}
+	for _, p := range spdx.Packages {
+		if p.Name == "" {
+			continue
+		}
+
+		comp := &Component{Name: p.Name, Version: p.VersionInfo, Type: strings.ToLower(p.Purpose)}
+		for _, ref := range p.ExternalRefs {
+			if ref.ReferenceType == "purl" {
+				comp.Purl = ref.ReferenceLocator
+				break
+			}
+		}
+		comp.Group = purlNamespace(comp.Purl)
+		doc.Components = append(doc.Components, comp)
+	}
+	return doc, nil
+}
+
+// purlNamespace returns the namespace of the package url like 'pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1'
+func purlNamespace(purl string) string {
+	if !strings.HasPrefix(purl, _purlPrefix) {
+		return ""
+	}
+
+	p := strings.TrimPrefix(purl, _purlPrefix)
+	p, _, _ = strings.Cut(p, "#")
+	p, _, _ = strings.Cut(p, "?")
+	p, _, _ = strings.Cut(p, "@")
+
+	segments := strings.Split(strings.Trim(p, "/"), "/")
+	if len(segments) < 3 {
+		return ""
+	}
+
+	namespace := strings.Join(segments[1:len(segments)-1], "/")
+	if unescaped, err := url.PathUnescape(namespace); err == nil {
+		return unescaped
+	}
+	return namespace
+}
+
+func dedupe(components []*Component) []*Component {
+	seen := make(map[Component]struct{}, len(components))
+	result := make([]*Component, 0, len(components))
+	for _, c := range components {
+		if _, ok := seen[*c]; ok {
+			continue
+		}
+		seen[*c] = struct{}{}
+		result = append(result, c)
+	}
+	return result
+}
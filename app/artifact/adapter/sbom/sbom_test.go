@@ -0,0 +1,90 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package sbom
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testCycloneDX = `{
+  "bomFormat": "CycloneDX",
+  "specVersion": "1.5",
+  "metadata": {"component": {"type": "application", "name": "demo"}},
+  "components": [
+    {
+      "type": "library",
+      "group": "org.apache.logging.log4j",
+      "name": "log4j-core",
+      "version": "2.14.1",
+      "purl": "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1",
+      "components": [
+        {"type": "library", "name": "log4j-api", "version": "2.14.1", "purl": "pkg:maven/org.apache.logging.log4j/log4j-api@2.14.1"}
+      ]
+    },
+    {"type": "library", "name": "lodash", "version": "4.17.21", "purl": "pkg:npm/lodash@4.17.21"},
+    {"type": "library", "name": "lodash", "version": "4.17.21", "purl": "pkg:npm/lodash@4.17.21"}
+  ]
+}`
+
+	testSPDX = `{
+  "spdxVersion": "SPDX-2.3",
+  "name": "demo",
+  "packages": [
+    {
+      "name": "log4j-core",
+      "versionInfo": "2.17.1",
+      "primaryPackagePurpose": "LIBRARY",
+      "externalRefs": [
+        {"referenceCategory": "PACKAGE-MANAGER", "referenceType": "purl", "referenceLocator": "pkg:maven/org.apache.logging.log4j/log4j-core@2.17.1"}
+      ]
+    },
+    {"name": "@babel/core", "versionInfo": "7.22.0", "externalRefs": [
+      {"referenceCategory": "PACKAGE-MANAGER", "referenceType": "purl", "referenceLocator": "pkg:npm/%40babel/core@7.22.0"}
+    ]}
+  ]
+}`
+)
+
+func TestParseCycloneDX(t *testing.T) {
+	doc, err := Parse(strings.NewReader(testCycloneDX), 1<<20)
+	require.NoError(t, err)
+	require.Equal(t, FormatCycloneDX, doc.Format)
+	require.Equal(t, []*Component{
+		{Name: "log4j-core", Group: "org.apache.logging.log4j", Version: "2.14.1", Purl: "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1", Type: "library"},
+		{Name: "lodash", Version: "4.17.21", Purl: "pkg:npm/lodash@4.17.21", Type: "library"},
+		{Name: "log4j-api", Group: "org.apache.logging.log4j", Version: "2.14.1", Purl: "pkg:maven/org.apache.logging.log4j/log4j-api@2.14.1", Type: "library"},
+	}, doc.Components)
+}
+
+func TestParseSPDX(t *testing.T) {
+	doc, err := Parse(strings.NewReader(testSPDX), 1<<20)
+	require.NoError(t, err)
+	require.Equal(t, FormatSPDX, doc.Format)
+	require.Equal(t, []*Component{
+		{Name: "log4j-core", Group: "org.apache.logging.log4j", Version: "2.17.1", Purl: "pkg:maven/org.apache.logging.log4j/log4j-core@2.17.1", Type: "library"},
+		{Name: "@babel/core", Group: "@babel", Version: "7.22.0", Purl: "pkg:npm/%40babel/core@7.22.0"},
+	}, doc.Components)
+}
+
+func TestParseUnknown(t *testing.T) {
+	for _, content := range []string{"", "not json", `{"name": "demo"}`} {
+		_, err := Parse(strings.NewReader(content), 1<<20)
+		require.ErrorIs(t, err, ErrUnknownDocument)
+	}
+}
+
+func TestParseTooLarge(t *testing.T) {
+	_, err := Parse(strings.NewReader(testCycloneDX), int64(len(testCycloneDX)-1))
+	require.ErrorIs(t, err, ErrTooLarge)
+
+	_, err = Parse(strings.NewReader(testCycloneDX), int64(len(testCycloneDX)))
+	require.NoError(t, err)
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/easysoft/gitfox/pkg/storage"
 	storagedriver "github.com/easysoft/gitfox/pkg/storage/driver"
 	"github.com/easysoft/gitfox/types"
+	"github.com/easysoft/gitfox/types/enum"
 
 	uuid "github.com/satori/go.uuid"
 )
@@ -118,4 +119,6 @@ type ViewDescriptor struct {
 	StorageID int64
 	Store     storage.ContentStorage
 	Space     *types.Space
+	// Kind marks the view as a proxy of the upstream registry
+	Kind enum.ArtifactRepoKind
 }
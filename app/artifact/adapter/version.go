@@ -0,0 +1,35 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package adapter
+
+import (
+	"context"
+
+	"github.com/easysoft/gitfox/app/store"
+	"github.com/easysoft/gitfox/types"
+)
+
+const _versionPageSize = 100
+
+// ListVersions finds all the versions matching the option page by page,
+// the page and size of the option are ignored.
+func ListVersions(
+	ctx context.Context, artStore store.ArtifactStore, opt types.SearchVersionOption,
+) ([]*types.ArtifactVersion, error) {
+	result := make([]*types.ArtifactVersion, 0)
+	opt.Size = _versionPageSize
+	for opt.Page = 1; ; opt.Page++ {
+		items, err := artStore.Versions().Find(ctx, opt)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, items...)
+		if len(items) < _versionPageSize {
+			return result, nil
+		}
+	}
+}
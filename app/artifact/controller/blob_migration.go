@@ -0,0 +1,27 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package controller
+
+import (
+	"context"
+
+	"github.com/easysoft/gitfox/app/services/blobmigrate"
+	"github.com/easysoft/gitfox/job"
+	"github.com/easysoft/gitfox/types"
+)
+
+// MigrateBlobs starts the job copying the artifact blobs from the source storage to the target storage.
+func (c *Controller) MigrateBlobs(ctx context.Context, in *types.ArtifactBlobMigrationInput) (*types.JobUIDResponse, error) {
+	if err := c.migrateSvc.Trigger(ctx, in); err != nil {
+		return nil, err
+	}
+	return &types.JobUIDResponse{UID: blobmigrate.JobUIDArtifactBlobMigration}, nil
+}
+
+func (c *Controller) BlobMigrationProgress(ctx context.Context) (job.Progress, error) {
+	return c.migrateSvc.Progress(ctx)
+}
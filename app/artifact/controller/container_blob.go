@@ -39,6 +39,12 @@ func (c *Controller) getContainerBlob(ctx context.Context, ctnReq *ContainerReq,
 	}
 
 	fr, meta, err := c.GetAssetReader(ctx, digest, types.ArtifactContainerFormat, nil)
+	if errors.Is(err, gitfox_store.ErrResourceNotFound) && isProxyView(ctnReq.view) {
+		if err = c.proxyContainerBlob(ctx, ctnReq, digest); err != nil {
+			return nil, err
+		}
+		fr, meta, err = c.GetAssetReader(ctx, digest, types.ArtifactContainerFormat, nil)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -69,6 +75,23 @@ func (c *Controller) DeleteContainerBlob(ctx context.Context, ctnReq *ContainerR
 		return nil, err
 	}
 	if e := c.tx.WithTx(ctx, func(ctx context.Context) error {
+		if err = c.artStore.Usages().RemoveBlob(ctx, ctnReq.view.OwnerID, meta.Path); err != nil {
+			return err
+		}
+		// the deleted manifest is no longer a referrer in this repository
+		if err = c.artStore.Referrers().DeleteByDigest(ctx, ctnReq.view.ViewID, ctnReq.repoName, meta.Path); err != nil {
+			return err
+		}
+
+		// the blob is shared by the spaces uploaded or mounted it, it's kept until the last space deletes it
+		var spaces []int64
+		if spaces, err = c.artStore.Usages().ListBlobSpaces(ctx, meta.Path); err != nil {
+			return err
+		}
+		if len(spaces) > 0 {
+			return nil
+		}
+
 		if err = c.artStore.Blobs().DeleteById(ctx, meta.BlobId); err != nil {
 			return err
 		}
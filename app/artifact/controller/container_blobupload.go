@@ -12,12 +12,19 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strings"
 
+	apiauth "github.com/easysoft/gitfox/app/api/auth"
 	"github.com/easysoft/gitfox/app/artifact/adapter/container"
+	"github.com/easysoft/gitfox/app/artifact/adapter/container/schema"
 	storagedriver "github.com/easysoft/gitfox/pkg/storage/driver"
+	gitfox_store "github.com/easysoft/gitfox/store"
+	"github.com/easysoft/gitfox/types"
+	"github.com/easysoft/gitfox/types/enum"
 
 	"github.com/opencontainers/go-digest"
 	"github.com/rs/zerolog/log"
+	"golang.org/x/exp/slices"
 )
 
 func (c *Controller) SaveContainerBlob(
@@ -27,11 +34,18 @@ func (c *Controller) SaveContainerBlob(
 	if c.checkAuthArtifactPush(ctx, ctnReq) != nil {
 		return nil, container.ErrDenied
 	}
+	if err := checkContainerPushable(ctnReq); err != nil {
+		return nil, err
+	}
+
+	if err := c.checkContainerQuota(ctx, ctnReq.view.Space, req.ContentLength, 0); err != nil {
+		return nil, err
+	}
 
 	refDigest := reference.String()
 	uploader := container.NewManifestUploader(c.artStore, ctnReq.view, refDigest)
 
-	if e := c.tx.WithTx(ctx, func(ctx context.Context) error {
+	if e := c.withinContainerQuota(ctx, ctnReq.view.Space, func(ctx context.Context) error {
 		return handleUpload(ctx, req, uploader)
 	}); e != nil {
 		return nil, e
@@ -46,12 +60,159 @@ func (c *Controller) SaveContainerBlob(
 	}), nil
 }
 
+// MountContainerBlob mounts the blob of the source repository 'from' to the repository of the request,
+// so the client doesn't upload the blob again. False is returned if the blob can't be mounted, the client
+// falls back to a regular upload then, e.g. the session isn't permitted to pull from the source space.
+func (c *Controller) MountContainerBlob(
+	ctx context.Context, ctnReq *ContainerReq, dgst digest.Digest, from string,
+) (HttpResponseWriter, bool, error) {
+	if c.checkAuthArtifactPush(ctx, ctnReq) != nil {
+		return nil, false, container.ErrDenied
+	}
+	if err := checkContainerPushable(ctnReq); err != nil {
+		return nil, false, err
+	}
+
+	idx := strings.LastIndex(from, "/")
+	if idx <= 0 {
+		return nil, false, nil
+	}
+	source, err := c.ParseSpaceView(ctx, from[:idx])
+	if errors.Is(err, gitfox_store.ErrResourceNotFound) {
+		return nil, false, nil
+	} else if err != nil {
+		return nil, false, err
+	}
+	err = apiauth.CheckSpaceScope(ctx, c.authorizer, ctnReq.session, source.Space,
+		enum.ResourceTypeArtifact, enum.PermissionArtifactPull)
+	if errors.Is(err, apiauth.ErrNotAuthorized) {
+		log.Ctx(ctx).Info().Msgf("mount blob %s from %s denied", dgst, from)
+		return nil, false, nil
+	} else if err != nil {
+		return nil, false, err
+	}
+
+	srcPkg, err := c.artStore.Packages().GetByName(ctx, from[idx+1:], "", source.OwnerID, types.ArtifactContainerFormat)
+	if errors.Is(err, gitfox_store.ErrResourceNotFound) {
+		return nil, false, nil
+	} else if err != nil {
+		return nil, false, err
+	}
+	if srcPkg.IsDeleted() {
+		return nil, false, nil
+	}
+
+	// only the blob referred by the source repository is mounted, any other blob in the storage isn't exposed
+	referred, err := c.packageRefersBlob(ctx, srcPkg.ID, source.ViewID, dgst)
+	if err != nil {
+		return nil, false, err
+	}
+	if !referred {
+		log.Ctx(ctx).Info().Msgf("mount blob %s from %s denied, the blob isn't referred by the repository", dgst, from)
+		return nil, false, nil
+	}
+
+	// the blobs are stored once by the digest, mounting only accounts the blob to the destination space,
+	// the blob is counted once in the space however often it's mounted
+	meta, err := c.GetAssetInfo(ctx, dgst.String(), types.ArtifactContainerFormat, nil)
+	if errors.Is(err, gitfox_store.ErrResourceNotFound) {
+		return nil, false, nil
+	} else if err != nil {
+		return nil, false, err
+	}
+	if e := c.withinContainerQuota(ctx, ctnReq.view.Space, func(ctx context.Context) error {
+		return c.artStore.Usages().AddBlob(ctx, ctnReq.view.OwnerID, dgst.String(), meta.Size)
+	}); e != nil {
+		return nil, false, e
+	}
+
+	log.Ctx(ctx).Info().Msgf("blob %s mounted from %s to %s", dgst, from, ctnReq.FullName())
+	nextUrl := c.urlProvider.GenerateRegistryURL(ctnReq.FullName(), "blobs", dgst.String())
+	return NewResponseWriter(func(w http.ResponseWriter) {
+		w.Header().Add(headerLocation, "/"+nextUrl.RequestURI())
+		w.Header().Add(headerContentLength, "0")
+		w.Header().Add(headerDigest, dgst.String())
+		w.WriteHeader(http.StatusCreated)
+	}), true, nil
+}
+
+// packageRefersBlob reports whether a tag of the package in the view refers to the blob,
+// the blob is the manifest of the tag or referred by it, e.g. a layer or a manifest of an image index.
+func (c *Controller) packageRefersBlob(ctx context.Context, packageId, viewId int64, dgst digest.Digest) (bool, error) {
+	versions, err := c.listPackageVersions(ctx, packageId)
+	if err != nil {
+		return false, err
+	}
+
+	visited := make(map[string]struct{})
+	for _, ver := range versions {
+		if ver.ViewID != viewId || ver.IsDeleted() {
+			continue
+		}
+		assets, e := c.artStore.Assets().FindMain(ctx, ver.ID)
+		if e != nil {
+			return false, e
+		}
+		for _, asset := range assets {
+			referred, e := c.manifestRefersBlob(ctx, asset.Path, dgst, visited)
+			if e != nil || referred {
+				return referred, e
+			}
+		}
+	}
+	return false, nil
+}
+
+func (c *Controller) manifestRefersBlob(ctx context.Context, manifestDigest string, dgst digest.Digest,
+	visited map[string]struct{},
+) (bool, error) {
+	if manifestDigest == dgst.String() {
+		return true, nil
+	}
+	if _, ok := visited[manifestDigest]; ok {
+		return false, nil
+	}
+	visited[manifestDigest] = struct{}{}
+
+	content, meta, err := c.GetAssetContent(ctx, manifestDigest, types.ArtifactContainerFormat, nil)
+	if errors.Is(err, gitfox_store.ErrResourceNotFound) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	manifest, err := schema.UnmarshalManifest(meta.ContentType, content)
+	if err != nil {
+		log.Ctx(ctx).Debug().Err(err).Msgf("skip the unknown manifest %s", manifestDigest)
+		return false, nil
+	}
+
+	for _, ref := range manifest.References() {
+		if ref.Digest == dgst {
+			return true, nil
+		}
+		if !slices.Contains(schema.MediaList, ref.MediaType) {
+			continue
+		}
+		referred, e := c.manifestRefersBlob(ctx, ref.Digest.String(), dgst, visited)
+		if e != nil || referred {
+			return referred, e
+		}
+	}
+	return false, nil
+}
+
 func (c *Controller) StartBlobUpload(
 	ctx context.Context, ctnReq *ContainerReq,
 ) (HttpResponseWriter, error) {
 	if c.checkAuthArtifactPush(ctx, ctnReq) != nil {
 		return nil, container.ErrDenied
 	}
+	if err := checkContainerPushable(ctnReq); err != nil {
+		return nil, err
+	}
+	if err := c.checkContainerQuota(ctx, ctnReq.view.Space, 0, 0); err != nil {
+		return nil, err
+	}
 
 	req := container.InitResumeRequest(ctnReq.FullName(), c.artStore, ctnReq.view)
 	return c.getUploadState(ctx, req)
@@ -102,6 +263,10 @@ func (c *Controller) AppendBlobContent(ctx context.Context, r *http.Request, upl
 	}
 
 	resumeReq := uploadRequest.resume
+	// the chunks are counted in the usage when the upload finishes, so the size uploaded is checked along
+	if err := c.checkContainerQuota(ctx, uploadRequest.view.Space, resumeReq.State.Offset+r.ContentLength, 0); err != nil {
+		return nil, err
+	}
 	if e := c.tx.WithTx(ctx, func(ctx context.Context) error {
 		err := resumeReq.AppendWrite(ctx, r)
 		return err
@@ -132,7 +297,7 @@ func (c *Controller) FinishBlobUpload(ctx context.Context, r *http.Request, uplo
 
 	resumeReq := uploadRequest.resume
 	var nextUrl *url.URL
-	if err = c.tx.WithTx(ctx, func(ctx context.Context) error {
+	if err = c.withinContainerQuota(ctx, uploadRequest.view.Space, func(ctx context.Context) error {
 		if e := resumeReq.Finish(ctx, r, &dgst); e != nil {
 			return e
 		}
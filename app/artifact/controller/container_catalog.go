@@ -0,0 +1,99 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+
+	apiauth "github.com/easysoft/gitfox/app/api/auth"
+	"github.com/easysoft/gitfox/app/artifact/adapter/container"
+	"github.com/easysoft/gitfox/app/auth"
+	"github.com/easysoft/gitfox/types"
+	"github.com/easysoft/gitfox/types/enum"
+)
+
+type catalog struct {
+	Repositories []string `json:"repositories"`
+}
+
+// ListCatalog lists the repositories in the spaces the session is permitted to pull from,
+// more reports whether there are repositories after the page.
+// The repositories are listed by space, only the page of the names is loaded from each space.
+func (c *Controller) ListCatalog(ctx context.Context, session *auth.Session, page *container.Pagination) (*catalog, bool, error) {
+	ownerIds, err := c.artStore.Packages().ListOwnerIDs(ctx, types.ArtifactContainerFormat)
+	if err != nil {
+		return nil, false, err
+	}
+
+	spaces := make([]*types.Space, 0, len(ownerIds))
+	for _, ownerId := range ownerIds {
+		space, e := c.findPullableSpace(ctx, session, ownerId)
+		if e != nil {
+			return nil, false, e
+		}
+		if space != nil {
+			spaces = append(spaces, space)
+		}
+	}
+	sort.Slice(spaces, func(i, j int) bool {
+		return spaces[i].Path+"/" < spaces[j].Path+"/"
+	})
+
+	// the page continues from the space of the last repository, the name has no slash
+	start, after := 0, ""
+	if idx := strings.LastIndex(page.Last, "/"); idx > 0 {
+		lastSpace := page.Last[:idx] + "/"
+		start = sort.Search(len(spaces), func(i int) bool {
+			return spaces[i].Path+"/" >= lastSpace
+		})
+		if start < len(spaces) && spaces[start].Path+"/" == lastSpace {
+			after = page.Last[idx+1:]
+		}
+	}
+
+	// one more name is loaded to know whether there are repositories after the page
+	names := make([]string, 0)
+	for _, space := range spaces[start:] {
+		limit := -1
+		if page.N >= 0 {
+			limit = page.N + 1 - len(names)
+		}
+		pkgNames, e := c.artStore.Packages().ListNames(ctx, space.ID, types.ArtifactContainerFormat, after, limit)
+		if e != nil {
+			return nil, false, e
+		}
+		for _, name := range pkgNames {
+			names = append(names, space.Path+"/"+name)
+		}
+		after = ""
+		if page.N >= 0 && len(names) > page.N {
+			// no link to the next page is given for an empty page
+			return &catalog{Repositories: names[:page.N]}, page.N > 0, nil
+		}
+	}
+
+	return &catalog{Repositories: names}, false, nil
+}
+
+// findPullableSpace returns the space if the session is permitted to pull from it, nil otherwise.
+func (c *Controller) findPullableSpace(ctx context.Context, session *auth.Session, spaceId int64) (*types.Space, error) {
+	space, err := c.spaceStore.Find(ctx, spaceId)
+	if err != nil {
+		return nil, err
+	}
+	err = apiauth.CheckSpaceScope(ctx, c.authorizer, session, space,
+		enum.ResourceTypeArtifact, enum.PermissionArtifactPull)
+	if errors.Is(err, apiauth.ErrNotAuthorized) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return space, nil
+}
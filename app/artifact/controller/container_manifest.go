@@ -15,6 +15,7 @@ import (
 
 	"github.com/easysoft/gitfox/app/artifact/adapter"
 	"github.com/easysoft/gitfox/app/artifact/adapter/container"
+	gitfox_store "github.com/easysoft/gitfox/store"
 	"github.com/easysoft/gitfox/types"
 )
 
@@ -39,16 +40,22 @@ func (c *Controller) PutManifest(ctx context.Context, req *http.Request, manifes
 	if c.checkAuthArtifactPush(ctx, manifestReq) != nil {
 		return nil, container.ErrDenied
 	}
+	if err := checkContainerPushable(manifestReq.ContainerReq); err != nil {
+		return nil, err
+	}
 
 	refDigest := manifestReq.Digest.String()
-	uploader := container.NewManifestUploader(c.artStore, manifestReq.view, refDigest)
+	uploader := container.NewManifestUploader(c.artStore, manifestReq.view, refDigest).WithReferrers(manifestReq.repoName)
 
-	if e := c.tx.WithTx(ctx, func(ctx context.Context) error {
+	if e := c.withinContainerQuota(ctx, manifestReq.view.Space, func(ctx context.Context) error {
 		err := handleUpload(ctx, req, uploader)
 		if errors.Is(err, adapter.ErrStorageFileNotChanged) {
 			return nil
 		}
-		return err
+		if err != nil {
+			return err
+		}
+		return c.ingestReferrerSbom(ctx, manifestReq, uploader.Manifest(), uploader.Subject())
 	}); e != nil {
 		return nil, e
 	}
@@ -59,6 +66,9 @@ func (c *Controller) PutManifest(ctx context.Context, req *http.Request, manifes
 		w.Header().Add(headerLocation, "/"+nextUrl.RequestURI())
 		w.Header().Add(headerContentLength, "0")
 		w.Header().Add(headerDigest, refDigest)
+		if subject := uploader.Subject(); subject != "" {
+			w.Header().Add(headerOCISubject, subject)
+		}
 		w.WriteHeader(http.StatusCreated)
 	}), nil
 }
@@ -74,6 +84,12 @@ func (c *Controller) getContainerManifest(ctx context.Context, req *http.Request
 	}
 
 	meta, err := c.GetAssetInfo(ctx, manifestReq.Digest.String(), types.ArtifactContainerFormat, nil)
+	if errors.Is(err, gitfox_store.ErrResourceNotFound) && isProxyView(manifestReq.view) {
+		if err = c.proxyContainerManifest(ctx, manifestReq); err != nil {
+			return nil, err
+		}
+		meta, err = c.GetAssetInfo(ctx, manifestReq.Digest.String(), types.ArtifactContainerFormat, nil)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,219 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/easysoft/gitfox/app/artifact/adapter"
+	"github.com/easysoft/gitfox/app/artifact/adapter/container"
+	"github.com/easysoft/gitfox/app/artifact/adapter/container/proxy"
+	gitfox_store "github.com/easysoft/gitfox/store"
+	"github.com/easysoft/gitfox/types"
+	"github.com/easysoft/gitfox/types/enum"
+
+	"github.com/opencontainers/go-digest"
+	"github.com/rs/zerolog/log"
+)
+
+func isProxyView(view *adapter.ViewDescriptor) bool {
+	return view.Kind == enum.ArtifactRepoKindProxy
+}
+
+// checkContainerPushable denies pushing to a proxy, its content comes from the upstream only
+func checkContainerPushable(req *ContainerReq) error {
+	if isProxyView(req.view) {
+		return container.ErrDenied.WithDetail("proxy repository is read-only")
+	}
+	return nil
+}
+
+func (c *Controller) getContainerUpstream(ctx context.Context, view *adapter.ViewDescriptor) (*proxy.Client, *types.ArtifactUpstream, error) {
+	upstream, err := c.artStore.Upstreams().GetByViewID(ctx, view.ViewID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("find upstream of view %d failed: %w", view.ViewID, err)
+	}
+
+	creds, err := c.getUpstreamCredentials(ctx, upstream)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	client, err := proxy.NewClient(upstream.URL, creds)
+	if err != nil {
+		return nil, nil, err
+	}
+	return client, upstream, nil
+}
+
+// getUpstreamCredentials resolves the password from the secret, or the credentials of the connector.
+func (c *Controller) getUpstreamCredentials(ctx context.Context, upstream *types.ArtifactUpstream) (proxy.Credentials, error) {
+	creds := proxy.Credentials{Username: upstream.Username}
+
+	if upstream.SecretID.Valid {
+		secret, err := c.secretStore.Find(ctx, upstream.SecretID.Int64)
+		if err != nil {
+			return creds, fmt.Errorf("find upstream secret failed: %w", err)
+		}
+		if creds.Password, err = c.encrypter.Decrypt([]byte(secret.Data)); err != nil {
+			return creds, fmt.Errorf("decrypt upstream secret failed: %w", err)
+		}
+	}
+
+	if upstream.ConnectorID.Valid {
+		connector, err := c.connectorStore.Find(ctx, upstream.ConnectorID.Int64)
+		if err != nil {
+			return creds, fmt.Errorf("find upstream connector failed: %w", err)
+		}
+		if connector.Github == nil || connector.Github.Auth == nil {
+			return creds, fmt.Errorf("connector %s has no credentials", connector.Identifier)
+		}
+
+		auth := connector.Github.Auth
+		var ref types.SecretRef
+		switch {
+		case auth.Basic != nil:
+			creds.Username = auth.Basic.Username
+			ref = auth.Basic.Password
+		case auth.Bearer != nil:
+			// registries accept the access token as the password
+			ref = auth.Bearer.Token
+		}
+
+		secret, err := c.secretStore.FindByIdentifier(ctx, connector.SpaceID, ref.Identifier)
+		if err != nil {
+			return creds, fmt.Errorf("find connector secret failed: %w", err)
+		}
+		if creds.Password, err = c.encrypter.Decrypt([]byte(secret.Data)); err != nil {
+			return creds, fmt.Errorf("decrypt connector secret failed: %w", err)
+		}
+	}
+	return creds, nil
+}
+
+// proxyContainerBlob caches the blob pulled from the upstream, the content is verified by the digest.
+func (c *Controller) proxyContainerBlob(ctx context.Context, req *ContainerReq, dgst string) error {
+	client, _, err := c.getContainerUpstream(ctx, req.view)
+	if err != nil {
+		return err
+	}
+
+	ref, err := digest.Parse(dgst)
+	if err != nil {
+		return container.ErrDigestInvalid
+	}
+
+	r, _, err := client.GetBlob(ctx, req.repoName, ref)
+	if err != nil {
+		return translateUpstreamError(err)
+	}
+	defer r.Close()
+
+	uploader := container.NewManifestUploader(c.artStore, req.view, dgst).WithVerifiedDigest()
+	return c.saveUpstreamContent(ctx, req.view, uploader, r, "application/octet-stream")
+}
+
+// proxyContainerManifest caches the manifest pulled from the upstream by digest.
+func (c *Controller) proxyContainerManifest(ctx context.Context, req *ContainerManifestRequest) error {
+	client, _, err := c.getContainerUpstream(ctx, req.view)
+	if err != nil {
+		return err
+	}
+
+	m, err := client.GetManifest(ctx, req.repoName, req.Digest.String())
+	if err != nil {
+		return translateUpstreamError(err)
+	}
+
+	uploader := container.NewManifestUploader(c.artStore, req.view, req.Digest.String()).
+		WithVerifiedDigest().WithReferrers(req.repoName)
+	return c.saveUpstreamContent(ctx, req.view, uploader, bytes.NewReader(m.Content), m.MediaType)
+}
+
+// proxyContainerTag returns the cached tag if it's within the manifest ttl, otherwise the tag is
+// refreshed from the upstream. The stale tag is still served if the upstream is unavailable.
+func (c *Controller) proxyContainerTag(ctx context.Context, req *ContainerManifestRequest, cached *tagInfo, findErr error) (*tagInfo, error) {
+	if findErr != nil && !errors.Is(findErr, gitfox_store.ErrResourceNotFound) {
+		return nil, findErr
+	}
+
+	client, upstream, err := c.getContainerUpstream(ctx, req.view)
+	if err != nil {
+		return nil, err
+	}
+
+	if cached != nil {
+		ttl := time.Duration(upstream.ManifestTTL) * time.Second
+		if time.Since(time.UnixMilli(cached.asset.Updated)) < ttl {
+			return cached, nil
+		}
+	}
+
+	if err = c.refreshContainerTag(ctx, client, req, cached); err != nil {
+		if cached != nil {
+			log.Ctx(ctx).Warn().Err(err).Msgf("refresh tag %s:%s from upstream failed, serve the cached one", req.repoName, req.Tag)
+			return cached, nil
+		}
+		return nil, translateUpstreamError(err)
+	}
+
+	view := req.view
+	return findContainerTag(ctx, c.artStore, view.ViewID, view.OwnerID, req.repoName, req.Tag)
+}
+
+func (c *Controller) refreshContainerTag(ctx context.Context, client *proxy.Client, req *ContainerManifestRequest, cached *tagInfo) error {
+	if cached != nil {
+		// the tag is not changed on the upstream, just renew the cached one
+		if dgst, err := client.HeadManifest(ctx, req.repoName, req.Tag); err == nil && dgst.String() == cached.asset.Path {
+			return c.artStore.Assets().Update(ctx, cached.asset)
+		}
+	}
+
+	m, err := client.GetManifest(ctx, req.repoName, req.Tag)
+	if err != nil {
+		return err
+	}
+
+	uploader := container.NewManifestUploader(c.artStore, req.view, "").WithReferrers(req.repoName)
+	uploader.Prepare(func(desc *adapter.PackageDescriptor) {
+		desc.Name = req.repoName
+		desc.Version = req.Tag
+	})
+	return c.saveUpstreamContent(ctx, req.view, uploader, bytes.NewReader(m.Content), m.MediaType)
+}
+
+// saveUpstreamContent caches the content pulled from the upstream, the cache is counted in the quota of the space.
+func (c *Controller) saveUpstreamContent(ctx context.Context, view *adapter.ViewDescriptor,
+	uploader adapter.ArtifactPackageUploader, r io.Reader, contentType string,
+) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, "", r)
+	if err != nil {
+		return err
+	}
+	req.Header.Set(headerContentType, contentType)
+
+	return c.withinContainerQuota(ctx, view.Space, func(ctx context.Context) error {
+		err := handleUpload(ctx, req, uploader)
+		if errors.Is(err, adapter.ErrStorageFileNotChanged) {
+			return nil
+		}
+		return err
+	})
+}
+
+func translateUpstreamError(err error) error {
+	if errors.Is(err, proxy.ErrNotFound) {
+		return gitfox_store.ErrResourceNotFound
+	}
+	return err
+}
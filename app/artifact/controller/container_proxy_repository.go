@@ -0,0 +1,204 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/easysoft/gitfox/app/api/usererror"
+	"github.com/easysoft/gitfox/app/artifact/adapter/container/proxy"
+	gitfox_store "github.com/easysoft/gitfox/store"
+	"github.com/easysoft/gitfox/types"
+	"github.com/easysoft/gitfox/types/check"
+	"github.com/easysoft/gitfox/types/enum"
+
+	"github.com/guregu/null"
+)
+
+const defaultManifestTTL = 3600
+
+type ProxyRepositoryCreateInput struct {
+	Identifier  string `json:"identifier"`
+	Description string `json:"description"`
+	URL         string `json:"url"`
+	Username    string `json:"username"`
+	// Secret is the identifier of the secret stores the password
+	Secret string `json:"secret"`
+	// Connector is the identifier of the connector provides the credentials
+	Connector   string `json:"connector"`
+	ManifestTTL *int64 `json:"manifest_ttl"`
+}
+
+type ProxyRepositoryUpdateInput struct {
+	URL         *string `json:"url"`
+	Username    *string `json:"username"`
+	Secret      *string `json:"secret"`
+	Connector   *string `json:"connector"`
+	ManifestTTL *int64  `json:"manifest_ttl"`
+}
+
+// CreateContainerProxy creates a view in the space proxies the upstream registry,
+// images are pulled by '{space}@{identifier}/{name}'.
+func (c *Controller) CreateContainerProxy(ctx context.Context, req *BaseReq, in *ProxyRepositoryCreateInput,
+) (*types.ArtifactProxyRepository, error) {
+	if err := c.checkAuthSpaceEdit(ctx, req); err != nil {
+		return nil, err
+	}
+
+	if err := check.Identifier(in.Identifier); err != nil {
+		return nil, err
+	}
+	if _, err := c.artStore.Views().GetByName(ctx, req.view.OwnerID, in.Identifier); err == nil {
+		return nil, usererror.Conflict("artifact view already exists")
+	} else if !errors.Is(err, gitfox_store.ErrResourceNotFound) {
+		return nil, err
+	}
+
+	upstream := &types.ArtifactUpstream{
+		Format:      types.ArtifactContainerFormat,
+		ManifestTTL: defaultManifestTTL,
+		CreatedBy:   req.session.Principal.ID,
+	}
+	if err := c.applyUpstreamInput(ctx, req, upstream, &ProxyRepositoryUpdateInput{
+		URL: &in.URL, Username: &in.Username, Secret: &in.Secret, Connector: &in.Connector, ManifestTTL: in.ManifestTTL,
+	}); err != nil {
+		return nil, err
+	}
+
+	view := &types.ArtifactView{
+		Name:        in.Identifier,
+		Description: strings.TrimSpace(in.Description),
+		SpaceID:     req.view.OwnerID,
+		Kind:        enum.ArtifactRepoKindProxy,
+	}
+	if err := c.tx.WithTx(ctx, func(ctx context.Context) error {
+		if err := c.artStore.Views().Create(ctx, view); err != nil {
+			return err
+		}
+		upstream.ViewID = view.ID
+		return c.artStore.Upstreams().Create(ctx, upstream)
+	}); err != nil {
+		return nil, err
+	}
+
+	return c.toProxyRepository(ctx, view, upstream), nil
+}
+
+func (c *Controller) ListContainerProxies(ctx context.Context, req *BaseReq) ([]*types.ArtifactProxyRepository, error) {
+	if err := c.checkAuthSpaceEdit(ctx, req); err != nil {
+		return nil, err
+	}
+
+	views, err := c.artStore.Views().ListByKind(ctx, req.view.OwnerID, enum.ArtifactRepoKindProxy)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*types.ArtifactProxyRepository, 0, len(views))
+	for _, view := range views {
+		upstream, err := c.artStore.Upstreams().GetByViewID(ctx, view.ID)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, c.toProxyRepository(ctx, view, upstream))
+	}
+	return result, nil
+}
+
+func (c *Controller) UpdateContainerProxy(ctx context.Context, req *BaseReq, identifier string, in *ProxyRepositoryUpdateInput,
+) (*types.ArtifactProxyRepository, error) {
+	if err := c.checkAuthSpaceEdit(ctx, req); err != nil {
+		return nil, err
+	}
+
+	view, err := c.artStore.Views().GetByName(ctx, req.view.OwnerID, identifier)
+	if err != nil {
+		return nil, err
+	}
+	if !view.IsProxy() {
+		return nil, usererror.BadRequest("artifact view is not a proxy")
+	}
+
+	upstream, err := c.artStore.Upstreams().GetByViewID(ctx, view.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err = c.applyUpstreamInput(ctx, req, upstream, in); err != nil {
+		return nil, err
+	}
+	if err = c.artStore.Upstreams().Update(ctx, upstream); err != nil {
+		return nil, err
+	}
+
+	return c.toProxyRepository(ctx, view, upstream), nil
+}
+
+func (c *Controller) applyUpstreamInput(ctx context.Context, req *BaseReq, upstream *types.ArtifactUpstream, in *ProxyRepositoryUpdateInput) error {
+	if in.URL != nil {
+		if _, err := proxy.NewClient(*in.URL, proxy.Credentials{}); err != nil {
+			return usererror.BadRequest(err.Error())
+		}
+		upstream.URL = strings.TrimSuffix(*in.URL, "/")
+	}
+	if in.Username != nil {
+		upstream.Username = strings.TrimSpace(*in.Username)
+	}
+	if in.ManifestTTL != nil {
+		if *in.ManifestTTL < 0 {
+			return usererror.BadRequest("manifest ttl must not be negative")
+		}
+		upstream.ManifestTTL = *in.ManifestTTL
+	}
+
+	if in.Secret != nil {
+		upstream.SecretID = null.Int{}
+		if *in.Secret != "" {
+			secret, err := c.secretStore.FindByIdentifier(ctx, req.view.OwnerID, *in.Secret)
+			if err != nil {
+				return err
+			}
+			upstream.SecretID = null.IntFrom(secret.ID)
+		}
+	}
+	if in.Connector != nil {
+		upstream.ConnectorID = null.Int{}
+		if *in.Connector != "" {
+			connector, err := c.connectorStore.FindByIdentifier(ctx, req.view.OwnerID, *in.Connector)
+			if err != nil {
+				return err
+			}
+			upstream.ConnectorID = null.IntFrom(connector.ID)
+		}
+	}
+
+	if upstream.SecretID.Valid && upstream.ConnectorID.Valid {
+		return usererror.BadRequest("either secret or connector is allowed")
+	}
+	return nil
+}
+
+func (c *Controller) toProxyRepository(ctx context.Context, view *types.ArtifactView, upstream *types.ArtifactUpstream) *types.ArtifactProxyRepository {
+	res := &types.ArtifactProxyRepository{
+		Identifier:  view.Name,
+		Description: view.Description,
+		Kind:        view.Kind,
+		Upstream:    upstream,
+	}
+	if upstream.SecretID.Valid {
+		if secret, err := c.secretStore.Find(ctx, upstream.SecretID.Int64); err == nil {
+			res.Secret = secret.Identifier
+		}
+	}
+	if upstream.ConnectorID.Valid {
+		if connector, err := c.connectorStore.Find(ctx, upstream.ConnectorID.Int64); err == nil {
+			res.Connector = connector.Identifier
+		}
+	}
+	return res
+}
@@ -0,0 +1,245 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	apiauth "github.com/easysoft/gitfox/app/api/auth"
+	"github.com/easysoft/gitfox/app/api/usererror"
+	"github.com/easysoft/gitfox/app/artifact/adapter"
+	"github.com/easysoft/gitfox/app/auth"
+	"github.com/easysoft/gitfox/app/auth/authz"
+	"github.com/easysoft/gitfox/app/store"
+	gitfox_store "github.com/easysoft/gitfox/store"
+	"github.com/easysoft/gitfox/types"
+	"github.com/easysoft/gitfox/types/enum"
+)
+
+func TestCreateContainerProxy(t *testing.T) {
+	views := &fakeProxyViewStore{}
+	upstreams := &fakeUpstreamStore{}
+	c := &Controller{
+		tx:          fakeTransactor{},
+		authorizer:  &fakeAuthorizer{allowed: true},
+		artStore:    &fakeProxyArtStore{views: views, upstreams: upstreams},
+		secretStore: &fakeSecretStore{secrets: []*types.Secret{{ID: 7, SpaceID: 1, Identifier: "hub-password"}}},
+	}
+
+	repo, err := c.CreateContainerProxy(context.Background(), newProxyTestRequest(), &ProxyRepositoryCreateInput{
+		Identifier: "hub",
+		URL:        "https://registry-1.docker.io/",
+		Username:   " max ",
+		Secret:     "hub-password",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(views.views) != 1 || views.views[0].Kind != enum.ArtifactRepoKindProxy || views.views[0].SpaceID != 1 {
+		t.Fatalf("got views %v, want one proxy view in the space", views.views)
+	}
+	if len(upstreams.upstreams) != 1 || upstreams.upstreams[0].ViewID != views.views[0].ID {
+		t.Fatalf("got upstreams %v, want one upstream of the proxy view", upstreams.upstreams)
+	}
+	upstream := upstreams.upstreams[0]
+	if upstream.URL != "https://registry-1.docker.io" || upstream.Username != "max" {
+		t.Errorf("got upstream %s as %q, want the trimmed url and username", upstream.URL, upstream.Username)
+	}
+	if upstream.ManifestTTL != defaultManifestTTL || upstream.SecretID.Int64 != 7 {
+		t.Errorf("got manifest ttl %d and secret %d, want %d and 7",
+			upstream.ManifestTTL, upstream.SecretID.Int64, defaultManifestTTL)
+	}
+	if repo.Identifier != "hub" || repo.Secret != "hub-password" {
+		t.Errorf("got proxy repository %s with secret %q, want hub with hub-password", repo.Identifier, repo.Secret)
+	}
+
+	_, err = c.CreateContainerProxy(context.Background(), newProxyTestRequest(), &ProxyRepositoryCreateInput{
+		Identifier: "hub",
+		URL:        "https://registry-1.docker.io",
+	})
+	if !isUserError(err, usererror.Conflict("")) {
+		t.Errorf("got error %v, want a conflict on the existing view", err)
+	}
+}
+
+func TestCreateContainerProxyInvalid(t *testing.T) {
+	negativeTTL := int64(-1)
+	tests := []struct {
+		name string
+		in   *ProxyRepositoryCreateInput
+	}{
+		{
+			name: "no scheme",
+			in:   &ProxyRepositoryCreateInput{Identifier: "hub", URL: "registry-1.docker.io"},
+		},
+		{
+			name: "negative manifest ttl",
+			in:   &ProxyRepositoryCreateInput{Identifier: "hub", URL: "https://registry-1.docker.io", ManifestTTL: &negativeTTL},
+		},
+		{
+			name: "secret and connector",
+			in: &ProxyRepositoryCreateInput{
+				Identifier: "hub", URL: "https://registry-1.docker.io", Secret: "hub-password", Connector: "hub",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			views := &fakeProxyViewStore{}
+			c := &Controller{
+				tx:             fakeTransactor{},
+				authorizer:     &fakeAuthorizer{allowed: true},
+				artStore:       &fakeProxyArtStore{views: views, upstreams: &fakeUpstreamStore{}},
+				secretStore:    &fakeSecretStore{secrets: []*types.Secret{{ID: 7, SpaceID: 1, Identifier: "hub-password"}}},
+				connectorStore: &fakeConnectorStore{connectors: []*types.Connector{{ID: 8, SpaceID: 1, Identifier: "hub"}}},
+			}
+
+			_, err := c.CreateContainerProxy(context.Background(), newProxyTestRequest(), test.in)
+			if !isUserError(err, usererror.BadRequest("")) {
+				t.Errorf("got error %v, want a bad request", err)
+			}
+			if len(views.views) != 0 {
+				t.Errorf("got views %v, want none created", views.views)
+			}
+		})
+	}
+}
+
+func TestCreateContainerProxyForbidden(t *testing.T) {
+	views := &fakeProxyViewStore{}
+	c := &Controller{
+		tx:         fakeTransactor{},
+		authorizer: &fakeAuthorizer{allowed: false},
+		artStore:   &fakeProxyArtStore{views: views, upstreams: &fakeUpstreamStore{}},
+	}
+
+	_, err := c.CreateContainerProxy(context.Background(), newProxyTestRequest(), &ProxyRepositoryCreateInput{
+		Identifier: "hub",
+		URL:        "https://registry-1.docker.io",
+	})
+	if !errors.Is(err, apiauth.ErrNotAuthorized) {
+		t.Errorf("got error %v, want not authorized", err)
+	}
+	if len(views.views) != 0 {
+		t.Errorf("got views %v, want none created", views.views)
+	}
+}
+
+func newProxyTestRequest() *BaseReq {
+	return &BaseReq{
+		session: &auth.Session{Principal: types.Principal{ID: 1}},
+		view: &adapter.ViewDescriptor{
+			ViewID:  1,
+			OwnerID: 1,
+			Space:   &types.Space{ID: 1, Path: "acme"},
+		},
+	}
+}
+
+func isUserError(err error, want *usererror.Error) bool {
+	var uErr *usererror.Error
+	return errors.As(err, &uErr) && uErr.Status == want.Status
+}
+
+type fakeTransactor struct{}
+
+func (fakeTransactor) WithTx(ctx context.Context, txFn func(ctx context.Context) error, _ ...interface{}) error {
+	return txFn(ctx)
+}
+
+type fakeAuthorizer struct {
+	authz.Authorizer
+	allowed bool
+}
+
+func (f *fakeAuthorizer) Check(context.Context, *auth.Session, *types.Scope, *types.Resource, enum.Permission,
+) (bool, error) {
+	return f.allowed, nil
+}
+
+type fakeProxyArtStore struct {
+	store.ArtifactStore
+	views     *fakeProxyViewStore
+	upstreams *fakeUpstreamStore
+}
+
+func (f *fakeProxyArtStore) Views() store.ArtifactViewInterface { return f.views }
+
+func (f *fakeProxyArtStore) Upstreams() store.ArtifactUpstreamInterface { return f.upstreams }
+
+type fakeProxyViewStore struct {
+	store.ArtifactViewInterface
+	views []*types.ArtifactView
+}
+
+func (f *fakeProxyViewStore) Create(_ context.Context, view *types.ArtifactView) error {
+	view.ID = int64(len(f.views) + 2)
+	f.views = append(f.views, view)
+	return nil
+}
+
+func (f *fakeProxyViewStore) GetByName(_ context.Context, spaceID int64, name string) (*types.ArtifactView, error) {
+	for _, view := range f.views {
+		if view.SpaceID == spaceID && view.Name == name {
+			return view, nil
+		}
+	}
+	return nil, gitfox_store.ErrResourceNotFound
+}
+
+type fakeUpstreamStore struct {
+	store.ArtifactUpstreamInterface
+	upstreams []*types.ArtifactUpstream
+}
+
+func (f *fakeUpstreamStore) Create(_ context.Context, upstream *types.ArtifactUpstream) error {
+	upstream.ID = int64(len(f.upstreams) + 1)
+	f.upstreams = append(f.upstreams, upstream)
+	return nil
+}
+
+type fakeSecretStore struct {
+	store.SecretStore
+	secrets []*types.Secret
+}
+
+func (f *fakeSecretStore) Find(_ context.Context, id int64) (*types.Secret, error) {
+	for _, secret := range f.secrets {
+		if secret.ID == id {
+			return secret, nil
+		}
+	}
+	return nil, gitfox_store.ErrResourceNotFound
+}
+
+func (f *fakeSecretStore) FindByIdentifier(_ context.Context, spaceID int64, identifier string) (*types.Secret, error) {
+	for _, secret := range f.secrets {
+		if secret.SpaceID == spaceID && secret.Identifier == identifier {
+			return secret, nil
+		}
+	}
+	return nil, gitfox_store.ErrResourceNotFound
+}
+
+type fakeConnectorStore struct {
+	store.ConnectorStore
+	connectors []*types.Connector
+}
+
+func (f *fakeConnectorStore) FindByIdentifier(
+	_ context.Context, spaceID int64, identifier string,
+) (*types.Connector, error) {
+	for _, connector := range f.connectors {
+		if connector.SpaceID == spaceID && connector.Identifier == identifier {
+			return connector, nil
+		}
+	}
+	return nil, gitfox_store.ErrResourceNotFound
+}
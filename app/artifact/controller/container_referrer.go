@@ -0,0 +1,75 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/easysoft/gitfox/app/artifact/adapter/container"
+	"github.com/easysoft/gitfox/app/artifact/adapter/container/schema"
+	"github.com/easysoft/gitfox/app/artifact/adapter/container/schema/ocischema"
+
+	"github.com/opencontainers/go-digest"
+	"github.com/opencontainers/image-spec/specs-go"
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	headerOCISubject        = "OCI-Subject"
+	headerOCIFiltersApplied = "OCI-Filters-Applied"
+)
+
+// ListContainerReferrers returns an image index of the manifests refer to the subject,
+// an empty index is returned if there is none.
+func (c *Controller) ListContainerReferrers(ctx context.Context, req *ContainerReq, subject digest.Digest, artifactType string,
+) (HttpResponseWriter, error) {
+	if c.checkAuthArtifactPull(ctx, req) != nil {
+		return nil, container.ErrDenied
+	}
+
+	referrers, err := c.artStore.Referrers().ListBySubject(ctx, req.view.ViewID, req.repoName, subject.String(), artifactType)
+	if err != nil {
+		return nil, err
+	}
+
+	index := ocischema.Index{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: v1.MediaTypeImageIndex,
+		Manifests: make([]schema.Descriptor, 0, len(referrers)),
+	}
+	for _, referrer := range referrers {
+		desc := schema.Descriptor{
+			MediaType:    referrer.MediaType,
+			Digest:       digest.Digest(referrer.Digest),
+			Size:         referrer.Size,
+			ArtifactType: referrer.ArtifactType,
+		}
+		if referrer.Annotations != "" {
+			if e := json.Unmarshal([]byte(referrer.Annotations), &desc.Annotations); e != nil {
+				log.Ctx(ctx).Warn().Err(e).Msgf("parse annotations of referrer %s failed", referrer.Digest)
+			}
+		}
+		index.Manifests = append(index.Manifests, desc)
+	}
+
+	content, err := json.Marshal(index)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewResponseWriter(func(w http.ResponseWriter) {
+		w.Header().Set(headerContentType, v1.MediaTypeImageIndex)
+		if artifactType != "" {
+			w.Header().Set(headerOCIFiltersApplied, "artifactType")
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write(content)
+	}), nil
+}
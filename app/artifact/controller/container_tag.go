@@ -72,10 +72,17 @@ func (c *Controller) getContainerTag(ctx context.Context, req *http.Request, man
 
 	view := manifestReq.view
 	t, err := findContainerTag(ctx, c.artStore, view.ViewID, view.OwnerID, manifestReq.repoName, manifestReq.Tag)
+	if isProxyView(view) {
+		t, err = c.proxyContainerTag(ctx, manifestReq, t, err)
+	}
 	if err != nil {
 		return nil, err
 	}
 
+	if !head && t.version != nil {
+		c.downloads.Record(t.version.PackageID, t.version.ID)
+	}
+
 	meta := newAssetMeta(t.asset, t.blob)
 	for _, headerVal := range req.Header["If-None-Match"] {
 		if headerVal == meta.Path || headerVal == fmt.Sprintf(`"%s"`, meta.Path) { // allow quoted or unquoted
@@ -92,20 +99,32 @@ func (c *Controller) PutContainerTag(ctx context.Context, req *http.Request, man
 	if c.checkAuthArtifactPush(ctx, manifestReq) != nil {
 		return nil, container.ErrDenied
 	}
+	if err := checkContainerPushable(manifestReq.ContainerReq); err != nil {
+		return nil, err
+	}
 
 	view := manifestReq.view
-	uploader := container.NewManifestUploader(c.artStore, view, "")
+	uploader := container.NewManifestUploader(c.artStore, view, "").WithReferrers(manifestReq.repoName)
 	uploader.Prepare(func(desc *adapter.PackageDescriptor) {
 		desc.Name = manifestReq.repoName
 		desc.Version = manifestReq.Tag
 	})
 
-	if e := c.tx.WithTx(ctx, func(ctx context.Context) error {
+	if e := c.withinContainerQuota(ctx, view.Space, func(ctx context.Context) error {
 		err := handleUpload(ctx, req, uploader)
 		if errors.Is(err, adapter.ErrStorageFileNotChanged) {
 			return nil
 		}
-		return err
+		if err != nil {
+			return err
+		}
+		if err = c.indexHelmOCIChart(ctx, manifestReq, uploader.Manifest()); err != nil {
+			return err
+		}
+		if err = c.ingestReferrerSbom(ctx, manifestReq, uploader.Manifest(), uploader.Subject()); err != nil {
+			return err
+		}
+		return c.linkSubjectSboms(ctx, manifestReq, uploader.Descriptor().MainAsset.Path)
 	}); e != nil {
 		log.Ctx(ctx).Err(e).Msg("save container tag failed")
 		return nil, e
@@ -117,6 +136,9 @@ func (c *Controller) PutContainerTag(ctx context.Context, req *http.Request, man
 		w.Header().Add(headerLocation, "/"+nextUrl.RequestURI())
 		w.Header().Add(headerContentLength, "0")
 		w.Header().Add(headerDigest, desc.MainAsset.Path)
+		if subject := uploader.Subject(); subject != "" {
+			w.Header().Add(headerOCISubject, subject)
+		}
 		w.WriteHeader(http.StatusCreated)
 	}), nil
 }
@@ -138,10 +160,20 @@ func (c *Controller) DeleteContainerTag(ctx context.Context, req *ContainerManif
 			return err
 		}
 
-		// todo: a blob is linked to multi asset
+		// the blob is shared by the assets of the promoted versions, it's released with the last asset
+		refs, e := c.artStore.Assets().CountByBlob(ctx, t.blob.ID, true)
+		if e != nil {
+			return e
+		}
+		if refs > 0 {
+			return nil
+		}
 		if err = c.artStore.Blobs().DeleteById(ctx, t.blob.ID); err != nil {
 			return err
 		}
+		if err = c.artStore.Usages().Add(ctx, req.view.OwnerID, -t.blob.Size, 0); err != nil {
+			return err
+		}
 		_ = c.fileStore.Delete(ctx, adapter.BlobPath(t.blob.Ref))
 		return nil
 	}); e != nil {
@@ -159,9 +191,11 @@ type tagList struct {
 	Tags []string `json:"tags"`
 }
 
-func (c *Controller) ListTags(ctx context.Context, req *ContainerReq) (*tagList, error) {
+// ListTags lists the tags of the repository in the lexical order, more reports whether
+// there are tags after the page.
+func (c *Controller) ListTags(ctx context.Context, req *ContainerReq, page *container.Pagination) (*tagList, bool, error) {
 	if c.checkAuthArtifactPull(ctx, req) != nil {
-		return nil, container.ErrDenied
+		return nil, false, container.ErrDenied
 	}
 
 	var result = tagList{
@@ -171,18 +205,20 @@ func (c *Controller) ListTags(ctx context.Context, req *ContainerReq) (*tagList,
 
 	pkgModel, err := c.artStore.Packages().GetByName(ctx, req.repoName, "", req.view.OwnerID, types.ArtifactContainerFormat)
 	if err != nil {
-		return &result, nil
+		return &result, false, nil
 	}
 
 	objects, err := c.artStore.Versions().Find(ctx, types.SearchVersionOption{
 		PackageId: pkgModel.ID, ViewId: req.view.ViewID,
 	})
 	if err != nil {
-		return &result, nil
+		return &result, false, nil
 	}
 
 	for _, object := range objects {
 		result.Tags = append(result.Tags, object.Version)
 	}
-	return &result, nil
+	var more bool
+	result.Tags, more = page.Paginate(result.Tags)
+	return &result, more, nil
 }
@@ -15,15 +15,22 @@ import (
 	"github.com/easysoft/gitfox/app/artifact/adapter"
 	"github.com/easysoft/gitfox/app/auth/authz"
 	"github.com/easysoft/gitfox/app/services/artifactgc"
+	"github.com/easysoft/gitfox/app/services/artifactstats"
+	"github.com/easysoft/gitfox/app/services/blobmigrate"
 	"github.com/easysoft/gitfox/app/services/settings"
 	"github.com/easysoft/gitfox/app/store"
 	"github.com/easysoft/gitfox/app/url"
+	"github.com/easysoft/gitfox/encrypt"
+	"github.com/easysoft/gitfox/git"
 	"github.com/easysoft/gitfox/pkg/storage"
 	"github.com/easysoft/gitfox/store/database/dbtx"
 	"github.com/easysoft/gitfox/types"
 	"github.com/easysoft/gitfox/types/enum"
 )
 
+// defaultStorageID is the storage the blobs of the views are written to.
+const defaultStorageID = 1
+
 type Controller struct {
 	tx          dbtx.Transactor
 	urlProvider url.Provider
@@ -33,7 +40,25 @@ type Controller struct {
 	fileStore   storage.ContentStorage
 	settings    *settings.Service
 
-	gcSvc *artifactgc.Service
+	gcSvc      *artifactgc.Service
+	migrateSvc *blobmigrate.Service
+
+	// secretStore, connectorStore and encrypter resolve the credentials of upstream registries
+	secretStore    store.SecretStore
+	connectorStore store.ConnectorStore
+	encrypter      encrypt.Encrypter
+
+	// repoStore and git serve the go modules of the repositories
+	repoStore store.RepoStore
+	git       git.Interface
+
+	// membershipStore resolves the roles allowed to promote into the protected views
+	membershipStore store.MembershipStore
+
+	downloads *artifactstats.Recorder
+
+	// sbomMaxSize is the maximum size in bytes of the parsed sbom documents
+	sbomMaxSize int64
 }
 
 func NewController(
@@ -43,6 +68,15 @@ func NewController(
 	fileStore storage.ContentStorage,
 	settings *settings.Service,
 	gcSvc *artifactgc.Service,
+	migrateSvc *blobmigrate.Service,
+	secretStore store.SecretStore,
+	connectorStore store.ConnectorStore,
+	encrypter encrypt.Encrypter,
+	repoStore store.RepoStore,
+	git git.Interface,
+	membershipStore store.MembershipStore,
+	downloads *artifactstats.Recorder,
+	sbomMaxSize int64,
 ) *Controller {
 	return &Controller{
 		tx:          tx,
@@ -53,6 +87,19 @@ func NewController(
 		fileStore:   fileStore,
 		settings:    settings,
 		gcSvc:       gcSvc,
+		migrateSvc:  migrateSvc,
+
+		secretStore:    secretStore,
+		connectorStore: connectorStore,
+		encrypter:      encrypter,
+
+		repoStore: repoStore,
+		git:       git,
+
+		membershipStore: membershipStore,
+		downloads:       downloads,
+
+		sbomMaxSize: sbomMaxSize,
 	}
 }
 
@@ -88,9 +135,15 @@ func (c *Controller) ParseSpaceView(ctx context.Context, spaceRef string) (*adap
 		return nil, err
 	}
 
+	return c.newViewDescriptor(space, view), nil
+}
+
+// newViewDescriptor describes the view of the space, the blobs are written to the default storage.
+func (c *Controller) newViewDescriptor(space *types.Space, view *types.ArtifactView) *adapter.ViewDescriptor {
 	return &adapter.ViewDescriptor{
-		ViewID: view.ID, OwnerID: space.ID, Space: space,
-		Store: c.fileStore, StorageID: 1}, nil
+		ViewID: view.ID, OwnerID: space.ID, Space: space, Kind: view.Kind,
+		Store: c.fileStore, StorageID: defaultStorageID,
+	}
 }
 
 func (c *Controller) checkAuthArtifactPush(
@@ -165,3 +218,8 @@ func (c *Controller) checkAuthArtifactDelete(
 
 	return nil
 }
+
+// checkAuthSpaceEdit is required to manage the settings of the artifact space, such as proxies and retention policies
+func (c *Controller) checkAuthSpaceEdit(ctx context.Context, req *BaseReq) error {
+	return apiauth.CheckSpace(ctx, c.authorizer, req.session, req.view.Space, enum.PermissionSpaceEdit)
+}
@@ -0,0 +1,227 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+
+	apiauth "github.com/easysoft/gitfox/app/api/auth"
+	"github.com/easysoft/gitfox/app/api/usererror"
+	"github.com/easysoft/gitfox/app/artifact/adapter"
+	"github.com/easysoft/gitfox/app/artifact/adapter/debian"
+	gitfox_store "github.com/easysoft/gitfox/store"
+	"github.com/easysoft/gitfox/types"
+	"github.com/easysoft/gitfox/types/enum"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/rs/zerolog/log"
+)
+
+type DebianKeyInput struct {
+	PrivateKey string `json:"private_key"`
+	Passphrase string `json:"passphrase"`
+}
+
+// UploadDebian adds the .deb file to the pool of the distribution component,
+// the indexes of the view are regenerated and signed with the key of the space.
+func (c *Controller) UploadDebian(ctx context.Context, r *http.Request, debReq *BaseReq, distribution, component string,
+) (HttpResponseWriter, error) {
+	if c.checkAuthArtifactPush(ctx, debReq) != nil {
+		return nil, usererror.ErrForbidden
+	}
+	if err := debian.ValidateDistribution(distribution, component); err != nil {
+		return nil, err
+	}
+	if err := c.checkQuota(ctx, debReq.view.Space, r.ContentLength, 0); err != nil {
+		return nil, err
+	}
+
+	u := debian.NewUploader(debReq.view.Store, c.artStore, debReq.view, distribution, component)
+	if e := c.withinQuota(ctx, debReq.view.Space, func(ctx context.Context) error {
+		return handleUpload(ctx, r, u)
+	}); e != nil {
+		return nil, e
+	}
+
+	if e := c.tx.WithTx(ctx, func(ctx context.Context) error {
+		return c.updateDebianIndex(ctx, debReq.view)
+	}); e != nil {
+		return nil, e
+	}
+
+	return NewResponseWriter(func(w http.ResponseWriter) {
+		w.WriteHeader(http.StatusCreated)
+	}), nil
+}
+
+// GetDebianPoolReader opens the package file by its name in the pool, the file name
+// doesn't contain the epoch of the version, so the version is matched without it.
+func (c *Controller) GetDebianPoolReader(ctx context.Context, view *adapter.ViewDescriptor,
+	distribution, component, filename string,
+) (io.ReadCloser, *AssetMeta, error) {
+	if err := debian.ValidateDistribution(distribution, component); err != nil {
+		return nil, nil, err
+	}
+	name, fileVersion, _, err := debian.ParseFilename(filename)
+	if err != nil {
+		return nil, nil, gitfox_store.ErrResourceNotFound
+	}
+
+	pkg, err := c.artStore.Packages().GetByName(ctx, name, "", view.OwnerID, types.ArtifactDebianFormat)
+	if err != nil {
+		return nil, nil, err
+	}
+	if pkg.IsDeleted() {
+		return nil, nil, gitfox_store.ErrResourceNotFound
+	}
+
+	versions, err := c.artStore.Versions().Find(ctx, types.SearchVersionOption{PackageId: pkg.ID, ViewId: view.ViewID})
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, v := range versions {
+		if debian.FileVersion(v.Version) != fileVersion {
+			continue
+		}
+		r, meta, e := c.GetVersionAssetReader(ctx, view.ViewID, pkg.ID, v.Version,
+			debian.PoolPath(distribution, component, filename))
+		if e != nil {
+			return nil, nil, e
+		}
+		return r, meta.AssetMeta, nil
+	}
+	return nil, nil, gitfox_store.ErrResourceNotFound
+}
+
+// GetDebianIndexReader opens an index file of a distribution, like 'stable/InRelease'.
+func (c *Controller) GetDebianIndexReader(ctx context.Context, view *adapter.ViewDescriptor, filePath string,
+) (io.ReadCloser, *AssetMeta, error) {
+	return c.GetMetaReader(ctx, path.Join("dists", filePath), types.ArtifactDebianFormat, view.ViewID)
+}
+
+// GetDebianKey returns the signing key of the space, the private key isn't exposed.
+func (c *Controller) GetDebianKey(ctx context.Context, view *adapter.ViewDescriptor) (*types.ArtifactSigningKey, error) {
+	return c.artStore.SigningKeys().GetBySpace(ctx, view.OwnerID)
+}
+
+// ImportDebianKey replaces the generated signing key of the space by an existing key,
+// the indexes of all views are signed again with the new key.
+func (c *Controller) ImportDebianKey(ctx context.Context, debReq *BaseReq, in *DebianKeyInput,
+) (*types.ArtifactSigningKey, error) {
+	space := debReq.view.Space
+	if err := apiauth.CheckSpace(ctx, c.authorizer, debReq.session, space, enum.PermissionSpaceEdit); err != nil {
+		return nil, err
+	}
+
+	entity, err := debian.ReadKey(in.PrivateKey, in.Passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	var key *types.ArtifactSigningKey
+	err = c.tx.WithTx(ctx, func(ctx context.Context) error {
+		key, err = c.artStore.SigningKeys().GetBySpace(ctx, space.ID)
+		if errors.Is(err, gitfox_store.ErrResourceNotFound) {
+			key = &types.ArtifactSigningKey{SpaceID: space.ID, CreatedBy: debReq.session.Principal.ID}
+		} else if err != nil {
+			return err
+		}
+		if err = c.fillSigningKey(key, entity); err != nil {
+			return err
+		}
+		if key.ID == 0 {
+			err = c.artStore.SigningKeys().Create(ctx, key)
+		} else {
+			err = c.artStore.SigningKeys().Update(ctx, key)
+		}
+		if err != nil {
+			return err
+		}
+
+		views, err := c.artStore.Views().List(ctx, space.ID)
+		if err != nil {
+			return err
+		}
+		for _, view := range views {
+			if err = c.updateDebianIndex(ctx, c.newViewRequest(space, view).view); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Ctx(ctx).Info().Msgf("debian signing key %s imported to space %s", key.Fingerprint, space.Path)
+	return key, nil
+}
+
+func (c *Controller) updateDebianIndex(ctx context.Context, view *adapter.ViewDescriptor) error {
+	signer, err := c.debianSigner(ctx, view.Space)
+	if err != nil {
+		return err
+	}
+	return debian.NewIndex(c.artStore, view, signer).UpdateRepo(ctx)
+}
+
+// debianSigner loads the signing key of the space, a key is generated for the space on the first use.
+func (c *Controller) debianSigner(ctx context.Context, space *types.Space) (*openpgp.Entity, error) {
+	key, err := c.artStore.SigningKeys().GetBySpace(ctx, space.ID)
+	if errors.Is(err, gitfox_store.ErrResourceNotFound) {
+		return c.generateDebianSigner(ctx, space)
+	} else if err != nil {
+		return nil, err
+	}
+
+	armored, err := c.encrypter.Decrypt(key.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt signing key of space %d: %w", space.ID, err)
+	}
+	return debian.ReadKey(armored, "")
+}
+
+func (c *Controller) generateDebianSigner(ctx context.Context, space *types.Space) (*openpgp.Entity, error) {
+	entity, err := debian.GenerateKey(space.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate signing key: %w", err)
+	}
+
+	key := &types.ArtifactSigningKey{SpaceID: space.ID}
+	if err = c.fillSigningKey(key, entity); err != nil {
+		return nil, err
+	}
+	if err = c.artStore.SigningKeys().Create(ctx, key); err != nil {
+		return nil, err
+	}
+
+	log.Ctx(ctx).Info().Msgf("debian signing key %s generated for space %s", key.Fingerprint, space.Path)
+	return entity, nil
+}
+
+// fillSigningKey stores the armored keys in the record, the private key is encrypted.
+func (c *Controller) fillSigningKey(key *types.ArtifactSigningKey, entity *openpgp.Entity) error {
+	privateKey, err := debian.ArmorPrivateKey(entity)
+	if err != nil {
+		return err
+	}
+	publicKey, err := debian.ArmorPublicKey(entity)
+	if err != nil {
+		return err
+	}
+	if key.PrivateKey, err = c.encrypter.Encrypt(privateKey); err != nil {
+		return fmt.Errorf("failed to encrypt signing key: %w", err)
+	}
+	key.PublicKey = publicKey
+	key.Fingerprint = debian.Fingerprint(entity)
+	return nil
+}
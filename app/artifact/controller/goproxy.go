@@ -0,0 +1,334 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package controller
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	apiauth "github.com/easysoft/gitfox/app/api/auth"
+	"github.com/easysoft/gitfox/app/api/render"
+	"github.com/easysoft/gitfox/app/api/usererror"
+	"github.com/easysoft/gitfox/app/artifact/adapter/goproxy"
+	"github.com/easysoft/gitfox/app/auth"
+	gitfox_errors "github.com/easysoft/gitfox/errors"
+	"github.com/easysoft/gitfox/git"
+	"github.com/easysoft/gitfox/git/api"
+	gitfox_store "github.com/easysoft/gitfox/store"
+	"github.com/easysoft/gitfox/types"
+	"github.com/easysoft/gitfox/types/enum"
+
+	"github.com/rs/zerolog/log"
+	"golang.org/x/mod/module"
+	modzip "golang.org/x/mod/zip"
+)
+
+// goProxyCacheDir keeps the generated module zips in the artifact storage,
+// the zips are keyed by the commit, so a moved tag generates a new zip.
+const goProxyCacheDir = "goproxy"
+
+// goModule is a go module located in the repository.
+type goModule struct {
+	*goproxy.Module
+	repo *types.Repository
+}
+
+// GoProxy serves the GOPROXY protocol for the go modules in the repositories of the space,
+// the module path is the host followed by the repository path, like 'example.com/space/repo/v2'.
+func (c *Controller) GoProxy(ctx context.Context, session *auth.Session, spaceRef, reqPath string) (HttpResponseWriter, error) {
+	req, err := goproxy.ParseRequest(reqPath)
+	if err != nil {
+		return nil, err
+	}
+
+	space, err := c.spaceStore.FindByRef(ctx, spaceRef)
+	if err != nil {
+		return nil, err
+	}
+	mod, err := c.findGoModule(ctx, session, space, req.Module)
+	if err != nil {
+		return nil, err
+	}
+
+	switch req.Kind {
+	case goproxy.RequestList:
+		versions, err := c.listGoVersions(ctx, mod)
+		if err != nil {
+			return nil, err
+		}
+		return newGoProxyTextWriter([]byte(strings.Join(versions, "\n"))), nil
+	case goproxy.RequestLatest:
+		info, err := c.latestGoVersion(ctx, mod)
+		if err != nil {
+			return nil, err
+		}
+		return newGoProxyJSONWriter(info), nil
+	case goproxy.RequestInfo:
+		info, _, err := c.resolveGoVersion(ctx, mod, req.Version)
+		if err != nil {
+			return nil, err
+		}
+		return newGoProxyJSONWriter(info), nil
+	case goproxy.RequestMod:
+		_, commitSHA, err := c.resolveGoVersion(ctx, mod, req.Version)
+		if err != nil {
+			return nil, err
+		}
+		content, err := c.getGoMod(ctx, mod, commitSHA)
+		if err != nil {
+			return nil, err
+		}
+		return newGoProxyTextWriter(content), nil
+	case goproxy.RequestZip:
+		_, commitSHA, err := c.resolveGoVersion(ctx, mod, req.Version)
+		if err != nil {
+			return nil, err
+		}
+		r, err := c.getGoZip(ctx, mod, req.Version, commitSHA)
+		if err != nil {
+			return nil, err
+		}
+		return NewResponseWriter(func(w http.ResponseWriter) {
+			defer r.Close()
+			w.Header().Set(headerContentType, "application/zip")
+			render.Reader(ctx, w, http.StatusOK, r)
+		}), nil
+	default:
+		return nil, goproxy.ErrInvalidRequest
+	}
+}
+
+// findGoModule finds the repository of the module in the space, the longest repository path matches first.
+func (c *Controller) findGoModule(ctx context.Context, session *auth.Session, space *types.Space, modPath string,
+) (*goModule, error) {
+	candidates, err := goproxy.Candidates(modPath)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, candidate := range candidates {
+		if !strings.HasPrefix(candidate.RepoPath, space.Path+"/") {
+			continue
+		}
+		repo, err := c.repoStore.FindByRef(ctx, candidate.RepoPath)
+		if errors.Is(err, gitfox_store.ErrResourceNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err = apiauth.CheckRepo(ctx, c.authorizer, session, repo, enum.PermissionRepoView); err != nil {
+			return nil, err
+		}
+		return &goModule{Module: candidate, repo: repo}, nil
+	}
+	return nil, usererror.NotFoundf("go module %s not found in space %s", modPath, space.Path)
+}
+
+func (c *Controller) listGoVersions(ctx context.Context, mod *goModule) ([]string, error) {
+	out, err := c.git.ListCommitTags(ctx, &git.ListCommitTagsParams{
+		ReadParams: git.CreateReadParams(mod.repo),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+
+	versions := make([]string, 0, len(out.Tags))
+	for _, tag := range out.Tags {
+		if v, ok := mod.TagVersion(tag.Name); ok {
+			versions = append(versions, v)
+		}
+	}
+	goproxy.SortVersions(versions)
+	return versions, nil
+}
+
+// latestGoVersion returns the latest tagged version, the untagged module is
+// served with the pseudo-version of the default branch.
+func (c *Controller) latestGoVersion(ctx context.Context, mod *goModule) (*goproxy.Info, error) {
+	versions, err := c.listGoVersions(ctx, mod)
+	if err != nil {
+		return nil, err
+	}
+	if latest := goproxy.LatestVersion(versions); latest != "" {
+		info, _, err := c.resolveGoVersion(ctx, mod, latest)
+		return info, err
+	}
+	info, _, err := c.resolveGoVersion(ctx, mod, mod.repo.DefaultBranch)
+	return info, err
+}
+
+// resolveGoVersion resolves the version to the commit, the version is a tagged version, a pseudo-version
+// or a query like a branch name or a commit hash, which is answered with the pseudo-version of the commit.
+func (c *Controller) resolveGoVersion(ctx context.Context, mod *goModule, version string,
+) (*goproxy.Info, string, error) {
+	var rev string
+	switch {
+	case goproxy.IsQuery(version):
+		if !goproxy.ValidQuery(version) {
+			return nil, "", goproxy.ErrVersionUnknown.WithDetail(version)
+		}
+		rev = version
+	case module.IsPseudoVersion(version):
+		pseudoRev, err := module.PseudoVersionRev(version)
+		if err != nil {
+			return nil, "", goproxy.ErrVersionUnknown.WithDetail(err.Error())
+		}
+		rev = pseudoRev
+	default:
+		if _, ok := mod.TagVersion(mod.Tag(version)); !ok {
+			return nil, "", goproxy.ErrVersionUnknown.WithDetail(version)
+		}
+		rev = "refs/tags/" + mod.Tag(version)
+	}
+
+	out, err := c.git.GetCommit(ctx, &git.GetCommitParams{
+		ReadParams: git.CreateReadParams(mod.repo),
+		Revision:   rev,
+	})
+	if gitfox_errors.IsNotFound(err) || gitfox_errors.IsInvalidArgument(err) {
+		return nil, "", goproxy.ErrVersionUnknown.WithDetail(version)
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get commit of %s: %w", rev, err)
+	}
+
+	commit := out.Commit
+	commitSHA := commit.SHA.String()
+	if module.IsPseudoVersion(version) && !strings.HasPrefix(commitSHA, rev) {
+		return nil, "", goproxy.ErrVersionUnknown.WithDetail(version)
+	}
+	if goproxy.IsQuery(version) {
+		version = mod.PseudoVersion(commit.Committer.When, commitSHA)
+	}
+
+	return &goproxy.Info{Version: version, Time: commit.Committer.When.UTC()}, commitSHA, nil
+}
+
+func (c *Controller) getGoMod(ctx context.Context, mod *goModule, commitSHA string) ([]byte, error) {
+	readParams := git.CreateReadParams(mod.repo)
+	node, err := c.git.GetTreeNode(ctx, &git.GetTreeNodeParams{
+		ReadParams: readParams,
+		GitREF:     commitSHA,
+		Path:       mod.GoModPath(),
+	})
+	if gitfox_errors.IsNotFound(err) {
+		return goproxy.DefaultGoMod(mod.Path), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get go.mod: %w", err)
+	}
+
+	blob, err := c.git.GetBlob(ctx, &git.GetBlobParams{
+		ReadParams: readParams,
+		SHA:        node.Node.SHA,
+		SizeLimit:  modzip.MaxGoMod,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read go.mod: %w", err)
+	}
+	defer blob.Content.Close()
+	return io.ReadAll(blob.Content)
+}
+
+// getGoZip opens the module zip from the cache, the zip is generated from the git archive if not cached.
+func (c *Controller) getGoZip(ctx context.Context, mod *goModule, version, commitSHA string) (io.ReadCloser, error) {
+	escapedPath, err := module.EscapePath(mod.Path)
+	if err != nil {
+		return nil, err
+	}
+	escapedVersion, err := module.EscapeVersion(version)
+	if err != nil {
+		return nil, err
+	}
+	cachePath := path.Join(goProxyCacheDir, strconv.FormatInt(mod.repo.ID, 10), commitSHA,
+		escapedPath+"@"+escapedVersion+".zip")
+
+	if _, err = c.fileStore.Stat(ctx, cachePath); err == nil {
+		return c.fileStore.Open(ctx, cachePath)
+	}
+
+	if err = c.createGoZip(ctx, mod, version, commitSHA, cachePath); err != nil {
+		return nil, err
+	}
+	return c.fileStore.Open(ctx, cachePath)
+}
+
+func (c *Controller) createGoZip(ctx context.Context, mod *goModule, version, commitSHA, cachePath string) error {
+	archive, err := os.CreateTemp("", "gitfox-goproxy-archive-*.zip")
+	if err != nil {
+		return err
+	}
+	defer removeTempFile(ctx, archive)
+
+	params := api.ArchiveParams{Format: api.ArchiveFormatZip, Treeish: commitSHA}
+	if mod.Dir != "" {
+		params.Paths = []string{mod.Dir}
+	}
+	if err = c.git.Archive(ctx, git.ArchiveParams{
+		ReadParams:    git.CreateReadParams(mod.repo),
+		ArchiveParams: params,
+	}, archive); err != nil {
+		return err
+	}
+
+	archiveInfo, err := archive.Stat()
+	if err != nil {
+		return err
+	}
+	archiveReader, err := zip.NewReader(archive, archiveInfo.Size())
+	if err != nil {
+		return fmt.Errorf("failed to read git archive: %w", err)
+	}
+
+	modZip, err := os.CreateTemp("", "gitfox-goproxy-module-*.zip")
+	if err != nil {
+		return err
+	}
+	defer removeTempFile(ctx, modZip)
+
+	if err = goproxy.CreateZip(modZip, mod.Module, version, archiveReader); err != nil {
+		return usererror.UnprocessableEntityf("failed to create module zip: %s", err)
+	}
+	size, err := modZip.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	if _, err = modZip.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	return c.fileStore.Save(ctx, cachePath, modZip, size)
+}
+
+func removeTempFile(ctx context.Context, f *os.File) {
+	_ = f.Close()
+	if err := os.Remove(f.Name()); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msgf("failed to remove temp file %s", f.Name())
+	}
+}
+
+func newGoProxyTextWriter(content []byte) HttpResponseWriter {
+	return NewResponseWriter(func(w http.ResponseWriter) {
+		w.Header().Set(headerContentType, "text/plain; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(content)
+	})
+}
+
+func newGoProxyJSONWriter(info *goproxy.Info) HttpResponseWriter {
+	return NewResponseWriter(func(w http.ResponseWriter) {
+		render.JSON(w, http.StatusOK, info)
+	})
+}
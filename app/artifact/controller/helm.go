@@ -8,12 +8,19 @@ package controller
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"path"
 
+	"github.com/easysoft/gitfox/app/api/render"
 	"github.com/easysoft/gitfox/app/api/usererror"
+	"github.com/easysoft/gitfox/app/artifact/adapter"
+	"github.com/easysoft/gitfox/app/artifact/adapter/container/schema"
+	"github.com/easysoft/gitfox/app/artifact/adapter/container/schema/ocischema"
 	"github.com/easysoft/gitfox/app/artifact/adapter/helm"
 	"github.com/easysoft/gitfox/app/url"
+	gitfox_store "github.com/easysoft/gitfox/store"
+	"github.com/easysoft/gitfox/types"
 
 	"github.com/rs/zerolog/log"
 )
@@ -24,8 +31,12 @@ func (c *Controller) UploadHelm(ctx context.Context, r *http.Request, helmReq *B
 	}
 
 	log.Ctx(ctx).Info().Msgf("start upload helm")
+	if err := c.checkQuota(ctx, helmReq.view.Space, r.ContentLength, 0); err != nil {
+		return nil, err
+	}
+
 	u := helm.NewUploader(helmReq.view.Store, c.artStore, helmReq.view)
-	if e := c.tx.WithTx(ctx, func(ctx context.Context) error {
+	if e := c.withinQuota(ctx, helmReq.view.Space, func(ctx context.Context) error {
 		return handleUpload(ctx, r, u)
 	}); e != nil {
 		return nil, e
@@ -42,3 +53,120 @@ func (c *Controller) UploadHelm(ctx context.Context, r *http.Request, helmReq *B
 		w.WriteHeader(http.StatusCreated)
 	}), nil
 }
+
+// DeleteHelmChart removes a chart version in the way of ChartMuseum 'DELETE /api/charts/{name}/{version}',
+// the version is soft removed and the index.yaml is regenerated without it.
+func (c *Controller) DeleteHelmChart(ctx context.Context, helmReq *BaseReq, name, version string) (HttpResponseWriter, error) {
+	if c.checkAuthArtifactDelete(ctx, helmReq) != nil {
+		return nil, usererror.ErrForbidden
+	}
+
+	view := helmReq.view
+	dbPkg, err := c.artStore.Packages().GetByName(ctx, name, "", view.OwnerID, types.ArtifactHelmFormat)
+	if err != nil {
+		return nil, err
+	}
+	dbVer, err := c.artStore.Versions().GetByVersion(ctx, dbPkg.ID, view.ViewID, version)
+	if err != nil {
+		return nil, err
+	}
+	if dbVer.IsDeleted() {
+		return nil, gitfox_store.ErrResourceNotFound
+	}
+
+	idx := &IndexUpdater{helm: true}
+	if e := c.tx.WithTx(ctx, func(ctx context.Context) error {
+		if err = c.softRemoveVersion(ctx, dbVer, &types.ArtifactNodeRemoveRes{}, idx); err != nil {
+			return err
+		}
+		return idx.Run(ctx, c, helmReq)
+	}); e != nil {
+		return nil, e
+	}
+
+	return NewResponseWriter(func(w http.ResponseWriter) {
+		render.JSON(w, http.StatusOK, map[string]bool{"deleted": true})
+	}), nil
+}
+
+// YankHelmChart yanks or restores a chart version, the yanked version is left out of the index.yaml
+// so new installs don't pick it, the chart file is kept for the installs already pinned to it.
+func (c *Controller) YankHelmChart(ctx context.Context, helmReq *BaseReq, name, version string, yanked bool) (HttpResponseWriter, error) {
+	if c.checkAuthArtifactDelete(ctx, helmReq) != nil {
+		return nil, usererror.ErrForbidden
+	}
+
+	view := helmReq.view
+	dbPkg, err := c.artStore.Packages().GetByName(ctx, name, "", view.OwnerID, types.ArtifactHelmFormat)
+	if err != nil {
+		return nil, err
+	}
+	dbVer, err := c.artStore.Versions().GetByVersion(ctx, dbPkg.ID, view.ViewID, version)
+	if err != nil {
+		return nil, err
+	}
+	if dbVer.IsDeleted() {
+		return nil, gitfox_store.ErrResourceNotFound
+	}
+
+	if dbVer.IsYanked() != yanked {
+		if e := c.tx.WithTx(ctx, func(ctx context.Context) error {
+			if yanked {
+				err = c.artStore.Versions().Yank(ctx, dbVer)
+			} else {
+				err = c.artStore.Versions().UnYank(ctx, dbVer)
+			}
+			if err != nil {
+				return err
+			}
+			return (&IndexUpdater{helm: true}).Run(ctx, c, helmReq)
+		}); e != nil {
+			return nil, e
+		}
+	}
+
+	return NewResponseWriter(func(w http.ResponseWriter) {
+		render.JSON(w, http.StatusOK, map[string]bool{"yanked": yanked})
+	}), nil
+}
+
+// indexHelmOCIChart registers the chart pushed by 'helm push oci://' as a helm package of the view,
+// so it's available in the index.yaml of the chart repository as well.
+func (c *Controller) indexHelmOCIChart(ctx context.Context, req *ContainerManifestRequest, m schema.Manifest) error {
+	manifest, ok := m.(*ocischema.DeserializedManifest)
+	if !ok || manifest.Config.MediaType != helm.MediaTypeConfig {
+		return nil
+	}
+
+	var chartLayer *schema.Descriptor
+	for i := range manifest.Layers {
+		if manifest.Layers[i].MediaType == helm.MediaTypeChartContent {
+			chartLayer = &manifest.Layers[i]
+			break
+		}
+	}
+	if chartLayer == nil {
+		return nil
+	}
+
+	fr, _, err := c.GetAssetReader(ctx, chartLayer.Digest.String(), types.ArtifactContainerFormat, nil)
+	if err != nil {
+		return err
+	}
+	defer fr.Close()
+
+	r, err := http.NewRequestWithContext(ctx, http.MethodPost, "", fr)
+	if err != nil {
+		return err
+	}
+
+	log.Ctx(ctx).Info().Msgf("index helm chart from oci artifact %s:%s", req.repoName, req.Tag)
+	helmReq := &BaseReq{spaceName: req.spaceName, session: req.session, view: req.view}
+	err = handleUpload(ctx, r, helm.NewArchiveUploader(req.view.Store, c.artStore, req.view))
+	if errors.Is(err, adapter.ErrStorageFileNotChanged) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	return (&IndexUpdater{helm: true}).Run(ctx, c, helmReq)
+}
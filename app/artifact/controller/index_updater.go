@@ -11,21 +11,37 @@ import (
 	"path"
 
 	"github.com/easysoft/gitfox/app/artifact/adapter/helm"
-	"github.com/easysoft/gitfox/app/store"
 	"github.com/easysoft/gitfox/app/url"
+	"github.com/easysoft/gitfox/types"
 )
 
 type IndexUpdater struct {
-	helm bool
+	helm   bool
+	debian bool
 }
 
-func (u *IndexUpdater) Run(ctx context.Context, store store.ArtifactStore, req *BaseReq) error {
+// mark records the format of a changed package, the index of the format is regenerated by Run.
+func (u *IndexUpdater) mark(format types.ArtifactFormat) {
+	switch format {
+	case types.ArtifactHelmFormat:
+		u.helm = true
+	case types.ArtifactDebianFormat:
+		u.debian = true
+	}
+}
+
+func (u *IndexUpdater) Run(ctx context.Context, c *Controller, req *BaseReq) error {
 	if u.helm {
-		i := helm.NewHelmIndex(store, req.view)
+		i := helm.NewHelmIndex(c.artStore, req.view)
 		if err := i.UpdateRepo(ctx, path.Join("/", url.ArtifactMount, req.spaceName, "helm")); err != nil {
 			return err
 		}
 	}
+	if u.debian {
+		if err := c.updateDebianIndex(ctx, req.view); err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
@@ -51,6 +51,21 @@ var (
 		return url.JoinPath("/", pathSegments...)
 	}
 
+	linkNpmFunc linkFunc = func(format types.ArtifactFormat, spaceName, pkgName, group, version, filePath string) (string, error) {
+		pathSegments := []string{url2.ArtifactMount, spaceName, string(format)}
+		if group != "" {
+			pathSegments = append(pathSegments, group)
+		}
+
+		pathSegments = append(pathSegments, pkgName, "-", filePath)
+		return url.JoinPath("/", pathSegments...)
+	}
+
+	linkPypiFunc linkFunc = func(format types.ArtifactFormat, spaceName, pkgName, group, version, filePath string) (string, error) {
+		pathSegments := []string{url2.ArtifactMount, spaceName, string(format), "files", pkgName, version, filePath}
+		return url.JoinPath("/", pathSegments...)
+	}
+
 	linkHelmFunc linkFunc = func(format types.ArtifactFormat, spaceName, pkgName, group, version, filePath string) (string, error) {
 		pathSegments := []string{url2.ArtifactMount, spaceName, string(format)}
 		pathSegments = append(pathSegments, filePath)
@@ -61,10 +76,14 @@ var (
 func addLink(data []*types.ArtifactAssetsRes, spaceName, packageName, group, version string, format types.ArtifactFormat) error {
 	var fn linkFunc
 	switch format {
-	case types.ArtifactRawFormat:
+	case types.ArtifactRawFormat, types.ArtifactMavenFormat:
 		fn = linkRawFunc
-	case types.ArtifactHelmFormat:
+	case types.ArtifactHelmFormat, types.ArtifactDebianFormat:
 		fn = linkHelmFunc
+	case types.ArtifactNpmFormat:
+		fn = linkNpmFunc
+	case types.ArtifactPypiFormat:
+		fn = linkPypiFunc
 	default:
 		fn = linkRawFunc
 	}
@@ -29,5 +29,7 @@ func (c *Controller) ListContainerImages(ctx context.Context, req *BaseReq, pack
 	if err != nil {
 		return nil, err
 	}
+	data.Downloads = t.version.Downloads
+	data.LastPulled = t.version.LastPulled
 	return data, nil
 }
@@ -43,6 +43,7 @@ func (c *Controller) ListVersions(ctx context.Context, req *BaseReq, format type
 	for _, version := range versions {
 		item := &types.ArtifactVersionsRes{
 			Version: version.Version, CreatorName: auth.AnonymousPrincipal.UID, Updated: version.Updated,
+			Downloads: version.Downloads, LastPulled: version.LastPulled,
 		}
 		if version.Metadata != "" {
 			var meta adapter.VersionMetadata
@@ -0,0 +1,183 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/easysoft/gitfox/app/api/usererror"
+	"github.com/easysoft/gitfox/app/artifact/adapter"
+	"github.com/easysoft/gitfox/app/artifact/adapter/maven"
+	"github.com/easysoft/gitfox/store"
+	"github.com/easysoft/gitfox/types"
+
+	"github.com/rs/zerolog/log"
+)
+
+const _checksumType = "text/plain"
+
+func (c *Controller) UploadMaven(ctx context.Context, r *http.Request, mavenReq *BaseReq, filePath string) (HttpResponseWriter, error) {
+	if c.checkAuthArtifactPush(ctx, mavenReq) != nil {
+		return nil, usererror.ErrForbidden
+	}
+
+	p, err := maven.ParsePath(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case p.IsMetadata():
+		// maven-metadata.xml is generated from the stored versions, the client copy is dropped
+		log.Ctx(ctx).Debug().Msgf("skip uploaded maven metadata '%s'", filePath)
+		_, _ = io.Copy(io.Discard, r.Body)
+	case p.Checksum != "":
+		if err = c.verifyMavenChecksum(ctx, r.Body, mavenReq.view, p); err != nil {
+			return nil, err
+		}
+	default:
+		if err = c.checkQuota(ctx, mavenReq.view.Space, r.ContentLength, 0); err != nil {
+			return nil, err
+		}
+		u := maven.NewUploader(mavenReq.view.Store, c.artStore, mavenReq.view, p)
+		if e := c.withinQuota(ctx, mavenReq.view.Space, func(ctx context.Context) error {
+			return handleUpload(ctx, r, u)
+		}); e != nil {
+			return nil, e
+		}
+	}
+
+	return NewResponseWriter(func(w http.ResponseWriter) {
+		w.WriteHeader(http.StatusCreated)
+	}), nil
+}
+
+// verifyMavenChecksum compares an uploaded checksum sidecar with the digest calculated on upload,
+// sidecars are served from the asset checksum so they are not stored.
+func (c *Controller) verifyMavenChecksum(ctx context.Context, r io.Reader, view *adapter.ViewDescriptor, p *maven.Path) error {
+	content, err := io.ReadAll(io.LimitReader(r, 1024))
+	if err != nil {
+		return err
+	}
+
+	hash, _, err := c.getMavenAssetHash(ctx, view, p)
+	if err != nil {
+		return err
+	}
+
+	// some clients append the filename after the digest
+	fields := strings.Fields(string(content))
+	if len(fields) == 0 || !strings.EqualFold(fields[0], maven.Checksum(hash, p.Checksum)) {
+		return adapter.ErrChecksumMismatch
+	}
+	return nil
+}
+
+func (c *Controller) getMavenAssetHash(ctx context.Context, view *adapter.ViewDescriptor, p *maven.Path) (*adapter.Hash, *VersionAssetMeta, error) {
+	pkg, err := c.artStore.Packages().GetByName(ctx, p.ArtifactID, p.GroupID, view.OwnerID, types.ArtifactMavenFormat)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	meta, err := c.GetVersionAssetInfo(ctx, view.ViewID, pkg.ID, p.Version, p.Filename)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	asset, err := c.artStore.Assets().GetById(ctx, meta.Id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var hash adapter.Hash
+	if err = json.Unmarshal([]byte(asset.CheckSum), &hash); err != nil {
+		return nil, nil, err
+	}
+	return &hash, meta, nil
+}
+
+// GetMavenReader serves files of the maven 2 layout, metadata and checksum sidecars are generated on request.
+func (c *Controller) GetMavenReader(ctx context.Context, view *adapter.ViewDescriptor, filePath string) (io.ReadCloser, *AssetMeta, error) {
+	p, err := maven.ParsePath(filePath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if p.IsMetadata() {
+		content, e := c.getMavenMetadata(ctx, view, p)
+		if e != nil {
+			return nil, nil, e
+		}
+		meta := &AssetMeta{Path: p.Filename, ContentType: maven.MetadataContentType, LastModify: time.Now().UnixMilli()}
+		if p.Checksum != "" {
+			hw := adapter.NewHashWriter()
+			_, _ = hw.Write(content)
+			content = []byte(maven.Checksum(hw.Sum(), p.Checksum))
+			meta.Path = p.Filename + "." + p.Checksum
+			meta.ContentType = _checksumType
+		}
+		meta.Size = int64(len(content))
+		return io.NopCloser(bytes.NewReader(content)), meta, nil
+	}
+
+	if p.Checksum != "" {
+		hash, verMeta, e := c.getMavenAssetHash(ctx, view, p)
+		if e != nil {
+			return nil, nil, e
+		}
+		content := []byte(maven.Checksum(hash, p.Checksum))
+		meta := &AssetMeta{
+			Path:        p.Filename + "." + p.Checksum,
+			Size:        int64(len(content)),
+			ContentType: _checksumType,
+			LastModify:  verMeta.LastModify,
+		}
+		return io.NopCloser(bytes.NewReader(content)), meta, nil
+	}
+
+	pkg, err := c.artStore.Packages().GetByName(ctx, p.ArtifactID, p.GroupID, view.OwnerID, types.ArtifactMavenFormat)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r, verMeta, err := c.GetVersionAssetReader(ctx, view.ViewID, pkg.ID, p.Version, p.Filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	return r, verMeta.AssetMeta, nil
+}
+
+func (c *Controller) getMavenMetadata(ctx context.Context, view *adapter.ViewDescriptor, p *maven.Path) ([]byte, error) {
+	pkg, err := c.artStore.Packages().GetByName(ctx, p.ArtifactID, p.GroupID, view.OwnerID, types.ArtifactMavenFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	if pkg.IsDeleted() {
+		return nil, store.ErrResourceNotFound
+	}
+
+	index := maven.NewMetadataIndex(c.artStore, view)
+	if p.Version == "" {
+		return index.PackageMetadata(ctx, pkg)
+	}
+
+	ver, err := c.artStore.Versions().GetByVersion(ctx, pkg.ID, view.ViewID, p.Version)
+	if err != nil {
+		return nil, err
+	}
+	if ver.IsDeleted() {
+		return nil, store.ErrResourceNotFound
+	}
+	return index.SnapshotMetadata(ctx, pkg, ver)
+}
@@ -0,0 +1,137 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package controller
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/easysoft/gitfox/app/api/usererror"
+	"github.com/easysoft/gitfox/app/artifact/adapter"
+	"github.com/easysoft/gitfox/app/artifact/adapter/npm"
+	"github.com/easysoft/gitfox/store"
+	"github.com/easysoft/gitfox/types"
+)
+
+func (c *Controller) UploadNpm(ctx context.Context, r *http.Request, npmReq *BaseReq, pkgPath string) (HttpResponseWriter, error) {
+	if c.checkAuthArtifactPush(ctx, npmReq) != nil {
+		return nil, usererror.ErrForbidden
+	}
+
+	name, err := npm.ParseName(pkgPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = c.checkQuota(ctx, npmReq.view.Space, r.ContentLength, 0); err != nil {
+		return nil, err
+	}
+
+	u := npm.NewUploader(npmReq.view.Store, c.artStore, npmReq.view, name)
+	if e := c.withinQuota(ctx, npmReq.view.Space, func(ctx context.Context) error {
+		return handleUpload(ctx, r, u)
+	}); e != nil {
+		return nil, e
+	}
+
+	return NewResponseWriter(func(w http.ResponseWriter) {
+		w.WriteHeader(http.StatusCreated)
+	}), nil
+}
+
+// GetNpmPackument generates the package document, tarball urls point to the requested space view.
+func (c *Controller) GetNpmPackument(ctx context.Context, spaceRef string, view *adapter.ViewDescriptor, pkgPath string) (*npm.Packument, error) {
+	pkg, err := c.findNpmPackage(ctx, view, pkgPath)
+	if err != nil {
+		return nil, err
+	}
+
+	index := npm.NewPackumentIndex(c.artStore, view)
+	return index.Build(ctx, pkg, func(name *npm.Name, filename string) string {
+		return c.urlProvider.GenerateArtifactURL(spaceRef, string(types.ArtifactNpmFormat), name.String(), "-", filename).String()
+	})
+}
+
+func (c *Controller) GetNpmTarballReader(ctx context.Context, view *adapter.ViewDescriptor, pkgPath, filename string) (io.ReadCloser, *AssetMeta, error) {
+	name, err := npm.ParseName(pkgPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	version := strings.TrimSuffix(strings.TrimPrefix(filename, name.Name+"-"), ".tgz")
+	if name.TarballName(version) != filename {
+		return nil, nil, adapter.ErrInvalidPackagePath
+	}
+
+	pkg, err := c.artStore.Packages().GetByName(ctx, name.Name, name.Scope, view.OwnerID, types.ArtifactNpmFormat)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r, meta, err := c.GetVersionAssetReader(ctx, view.ViewID, pkg.ID, version, filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	return r, meta.AssetMeta, nil
+}
+
+func (c *Controller) ListNpmDistTags(ctx context.Context, view *adapter.ViewDescriptor, pkgPath string) (map[string]string, error) {
+	pkg, err := c.findNpmPackage(ctx, view, pkgPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return npm.NewTagManager(c.artStore, view).List(ctx, pkg)
+}
+
+func (c *Controller) SetNpmDistTag(ctx context.Context, npmReq *BaseReq, pkgPath, tag, version string) error {
+	if c.checkAuthArtifactPush(ctx, npmReq) != nil {
+		return usererror.ErrForbidden
+	}
+
+	pkg, err := c.findNpmPackage(ctx, npmReq.view, pkgPath)
+	if err != nil {
+		return err
+	}
+
+	return c.tx.WithTx(ctx, func(ctx context.Context) error {
+		return npm.NewTagManager(c.artStore, npmReq.view).Set(ctx, pkg, tag, version)
+	})
+}
+
+func (c *Controller) DeleteNpmDistTag(ctx context.Context, npmReq *BaseReq, pkgPath, tag string) error {
+	if c.checkAuthArtifactPush(ctx, npmReq) != nil {
+		return usererror.ErrForbidden
+	}
+
+	pkg, err := c.findNpmPackage(ctx, npmReq.view, pkgPath)
+	if err != nil {
+		return err
+	}
+
+	return c.tx.WithTx(ctx, func(ctx context.Context) error {
+		return npm.NewTagManager(c.artStore, npmReq.view).Delete(ctx, pkg, tag)
+	})
+}
+
+func (c *Controller) findNpmPackage(ctx context.Context, view *adapter.ViewDescriptor, pkgPath string) (*types.ArtifactPackage, error) {
+	name, err := npm.ParseName(pkgPath)
+	if err != nil {
+		return nil, err
+	}
+
+	pkg, err := c.artStore.Packages().GetByName(ctx, name.Name, name.Scope, view.OwnerID, types.ArtifactNpmFormat)
+	if err != nil {
+		return nil, err
+	}
+	if pkg.IsDeleted() {
+		return nil, store.ErrResourceNotFound
+	}
+	return pkg, nil
+}
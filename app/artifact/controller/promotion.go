@@ -0,0 +1,278 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	apiauth "github.com/easysoft/gitfox/app/api/auth"
+	"github.com/easysoft/gitfox/app/api/usererror"
+	gitfox_store "github.com/easysoft/gitfox/store"
+	"github.com/easysoft/gitfox/types"
+	"github.com/easysoft/gitfox/types/enum"
+
+	"github.com/guregu/null"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/exp/slices"
+)
+
+// PromoteInput promotes a version of the request view to the target view,
+// the version is copied unless Move is set.
+type PromoteInput struct {
+	Format  types.ArtifactFormat `json:"format"`
+	Package string               `json:"package"`
+	Group   string               `json:"group"`
+	Version string               `json:"version"`
+	// Target is the identifier of the target view, the default view is used if empty
+	Target string `json:"target"`
+	Move   bool   `json:"move"`
+}
+
+// PromoteVersion copies or moves the version and its assets from the request view to the target view,
+// the assets refer to the same blobs, so nothing is uploaded again.
+func (c *Controller) PromoteVersion(ctx context.Context, req *BaseReq, in *PromoteInput) (*types.ArtifactPromotionRes, error) {
+	if in.Package == "" || in.Version == "" {
+		return nil, usererror.BadRequest("package and version are required")
+	}
+
+	source, err := c.artStore.Views().GetByID(ctx, req.view.ViewID)
+	if err != nil {
+		return nil, err
+	}
+	target, err := c.findView(ctx, req.view.OwnerID, in.Target)
+	if err != nil {
+		return nil, err
+	}
+	if source.ID == target.ID {
+		return nil, usererror.BadRequest("the version is already in the target view")
+	}
+	if target.IsProxy() {
+		return nil, usererror.BadRequest("can't promote to a proxy view")
+	}
+
+	if err = c.checkAuthPromote(ctx, req, target, in.Move); err != nil {
+		return nil, err
+	}
+
+	dbPkg, err := c.artStore.Packages().GetByName(ctx, in.Package, in.Group, req.view.OwnerID, in.Format)
+	if err != nil {
+		return nil, err
+	}
+	dbVer, err := c.artStore.Versions().GetByVersion(ctx, dbPkg.ID, source.ID, in.Version)
+	if err != nil {
+		return nil, err
+	}
+	if dbPkg.IsDeleted() || dbVer.IsDeleted() {
+		return nil, usererror.NotFound("version not found")
+	}
+
+	if _, err = c.artStore.Versions().GetByVersion(ctx, dbPkg.ID, target.ID, in.Version); err == nil {
+		return nil, usererror.Conflict("version already exists in the target view")
+	} else if !errors.Is(err, gitfox_store.ErrResourceNotFound) {
+		return nil, err
+	}
+
+	promotion := &types.ArtifactPromotion{
+		SpaceID:      req.view.OwnerID,
+		PackageID:    dbPkg.ID,
+		Version:      dbVer.Version,
+		Format:       dbPkg.Format,
+		SourceViewID: source.ID,
+		TargetViewID: target.ID,
+		Move:         in.Move,
+		CreatedBy:    req.session.Principal.ID,
+	}
+	err = c.tx.WithTx(ctx, func(ctx context.Context) error {
+		var e error
+		if in.Move {
+			e = c.moveVersion(ctx, dbVer, target)
+		} else {
+			e = c.copyVersion(ctx, dbVer, target)
+		}
+		if e != nil {
+			return e
+		}
+		promotion.VersionID = dbVer.ID
+		if e = c.artStore.Promotions().Create(ctx, promotion); e != nil {
+			return e
+		}
+
+		idx := &IndexUpdater{}
+		idx.mark(dbPkg.Format)
+		if e = idx.Run(ctx, c, c.newViewRequest(req.view.Space, target)); e != nil {
+			return e
+		}
+		if in.Move {
+			return idx.Run(ctx, c, c.newViewRequest(req.view.Space, source))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Ctx(ctx).Info().Msgf("%s version %s of package %d promoted from view %s to %s",
+		dbPkg.Format, dbVer.Version, dbPkg.ID, source.Name, target.Name)
+	return &types.ArtifactPromotionRes{
+		ArtifactPromotion: promotion,
+		Package:           dbPkg.Name,
+		Namespace:         dbPkg.Namespace,
+		SourceView:        source.Name,
+		TargetView:        target.Name,
+	}, nil
+}
+
+// ListPromotions lists the promotions of the space, the latest first.
+func (c *Controller) ListPromotions(ctx context.Context, req *BaseReq, page, size int) ([]*types.ArtifactPromotionRes, error) {
+	if err := apiauth.CheckSpace(ctx, c.authorizer, req.session, req.view.Space, enum.PermissionSpaceView); err != nil {
+		return nil, err
+	}
+
+	promotions, err := c.artStore.Promotions().List(ctx, req.view.OwnerID, page, size)
+	if err != nil {
+		return nil, err
+	}
+
+	viewNames := make(map[int64]string)
+	viewName := func(viewId int64) string {
+		if name, ok := viewNames[viewId]; ok {
+			return name
+		}
+		if view, e := c.artStore.Views().GetByID(ctx, viewId); e == nil {
+			viewNames[viewId] = view.Name
+		}
+		return viewNames[viewId]
+	}
+
+	result := make([]*types.ArtifactPromotionRes, 0, len(promotions))
+	for _, promotion := range promotions {
+		res := &types.ArtifactPromotionRes{
+			ArtifactPromotion: promotion,
+			SourceView:        viewName(promotion.SourceViewID),
+			TargetView:        viewName(promotion.TargetViewID),
+		}
+		if dbPkg, e := c.artStore.Packages().GetByID(ctx, promotion.PackageID); e == nil {
+			res.Package = dbPkg.Name
+			res.Namespace = dbPkg.Namespace
+		}
+		result = append(result, res)
+	}
+	return result, nil
+}
+
+// moveVersion moves the version and its assets to the target view.
+func (c *Controller) moveVersion(ctx context.Context, ver *types.ArtifactVersion, target *types.ArtifactView) error {
+	assets, err := c.artStore.Assets().FindMain(ctx, ver.ID)
+	if err != nil {
+		return err
+	}
+	for _, asset := range assets {
+		asset.ViewID = null.IntFrom(target.ID)
+		if err = c.artStore.Assets().Update(ctx, asset); err != nil {
+			return err
+		}
+	}
+
+	ver.ViewID = target.ID
+	return c.artStore.Versions().Update(ctx, ver)
+}
+
+// copyVersion copies the version to the target view, ver is replaced with the copy.
+func (c *Controller) copyVersion(ctx context.Context, ver *types.ArtifactVersion, target *types.ArtifactView) error {
+	assets, err := c.artStore.Assets().FindMain(ctx, ver.ID)
+	if err != nil {
+		return err
+	}
+
+	newVer := &types.ArtifactVersion{
+		PackageID: ver.PackageID,
+		Version:   ver.Version,
+		ViewID:    target.ID,
+		Metadata:  ver.Metadata,
+	}
+	if err = c.artStore.Versions().Create(ctx, newVer); err != nil {
+		return err
+	}
+
+	for _, asset := range assets {
+		newAsset := &types.ArtifactAsset{
+			VersionID:   null.IntFrom(newVer.ID),
+			ViewID:      null.IntFrom(target.ID),
+			Format:      asset.Format,
+			Path:        asset.Path,
+			ContentType: asset.ContentType,
+			Kind:        asset.Kind,
+			Metadata:    asset.Metadata,
+			BlobID:      asset.BlobID,
+			CheckSum:    asset.CheckSum,
+		}
+		if err = c.artStore.Assets().Create(ctx, newAsset); err != nil {
+			return fmt.Errorf("failed to copy asset %d: %w", asset.ID, err)
+		}
+	}
+
+	*ver = *newVer
+	return nil
+}
+
+// checkAuthPromote requires the push permission on the space, and the delete permission to move the version.
+// The principal promotes into a protected view must be an admin or have one of the promote roles of the view.
+func (c *Controller) checkAuthPromote(ctx context.Context, req *BaseReq, target *types.ArtifactView, move bool) error {
+	if err := c.checkAuthArtifactPush(ctx, req); err != nil {
+		return usererror.ErrForbidden
+	}
+	if move {
+		if err := c.checkAuthArtifactDelete(ctx, req); err != nil {
+			return usererror.ErrForbidden
+		}
+	}
+	if !target.Protected || req.session.Principal.Admin {
+		return nil
+	}
+
+	roles := target.GetPromoteRoles()
+	space := req.view.Space
+	for {
+		membership, err := c.membershipStore.Find(ctx, types.MembershipKey{
+			SpaceID:     space.ID,
+			PrincipalID: req.session.Principal.ID,
+		})
+		if err != nil && !errors.Is(err, gitfox_store.ErrResourceNotFound) {
+			return err
+		}
+		if membership != nil && slices.Contains(roles, membership.Role) {
+			return nil
+		}
+		if space.ParentID == 0 {
+			return usererror.Forbidden(fmt.Sprintf("promoting to the protected view %s is not allowed", target.Name))
+		}
+		if space, err = c.spaceStore.Find(ctx, space.ParentID); err != nil {
+			return err
+		}
+	}
+}
+
+func (c *Controller) findView(ctx context.Context, spaceId int64, identifier string) (*types.ArtifactView, error) {
+	if identifier == "" {
+		return c.artStore.Views().GetDefault(ctx, spaceId)
+	}
+	return c.artStore.Views().GetByName(ctx, spaceId, identifier)
+}
+
+// newViewRequest builds the request of the view, which is used to update the indexes of the view.
+func (c *Controller) newViewRequest(space *types.Space, view *types.ArtifactView) *BaseReq {
+	spaceName := space.Path
+	if !view.Default {
+		spaceName += "@" + view.Name
+	}
+	return &BaseReq{
+		spaceName: spaceName,
+		view:      c.newViewDescriptor(space, view),
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package controller
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/easysoft/gitfox/app/api/usererror"
+	"github.com/easysoft/gitfox/app/artifact/adapter"
+	"github.com/easysoft/gitfox/app/artifact/adapter/pypi"
+	"github.com/easysoft/gitfox/store"
+	"github.com/easysoft/gitfox/types"
+)
+
+func (c *Controller) UploadPypi(ctx context.Context, r *http.Request, pypiReq *BaseReq) (HttpResponseWriter, error) {
+	if c.checkAuthArtifactPush(ctx, pypiReq) != nil {
+		return nil, usererror.ErrForbidden
+	}
+
+	if err := c.checkQuota(ctx, pypiReq.view.Space, r.ContentLength, 0); err != nil {
+		return nil, err
+	}
+
+	u := pypi.NewUploader(pypiReq.view.Store, c.artStore, pypiReq.view)
+	if e := c.withinQuota(ctx, pypiReq.view.Space, func(ctx context.Context) error {
+		return handleUpload(ctx, r, u)
+	}); e != nil {
+		return nil, e
+	}
+
+	return NewResponseWriter(func(w http.ResponseWriter) {
+		w.WriteHeader(http.StatusCreated)
+	}), nil
+}
+
+func (c *Controller) GetPypiProjects(ctx context.Context, view *adapter.ViewDescriptor) (*pypi.ProjectList, error) {
+	return pypi.NewSimpleIndex(c.artStore, view).Projects(ctx)
+}
+
+// GetPypiProject generates the project page of the simple api, file urls point to the requested space view.
+func (c *Controller) GetPypiProject(ctx context.Context, spaceRef string, view *adapter.ViewDescriptor, name string) (*pypi.Project, error) {
+	pkg, err := c.findPypiPackage(ctx, view, name)
+	if err != nil {
+		return nil, err
+	}
+
+	index := pypi.NewSimpleIndex(c.artStore, view)
+	return index.Project(ctx, pkg, func(name, version, filename string) string {
+		return c.urlProvider.GenerateArtifactURL(spaceRef, string(types.ArtifactPypiFormat), "files", name, version, filename).String()
+	})
+}
+
+func (c *Controller) GetPypiFileReader(ctx context.Context, view *adapter.ViewDescriptor, name, version, filename string) (io.ReadCloser, *AssetMeta, error) {
+	pkg, err := c.findPypiPackage(ctx, view, name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r, meta, err := c.GetVersionAssetReader(ctx, view.ViewID, pkg.ID, version, filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	return r, meta.AssetMeta, nil
+}
+
+func (c *Controller) findPypiPackage(ctx context.Context, view *adapter.ViewDescriptor, name string) (*types.ArtifactPackage, error) {
+	if err := pypi.ValidateName(name); err != nil {
+		return nil, err
+	}
+
+	pkg, err := c.artStore.Packages().GetByName(ctx, pypi.NormalizeName(name), "", view.OwnerID, types.ArtifactPypiFormat)
+	if err != nil {
+		return nil, err
+	}
+	if pkg.IsDeleted() {
+		return nil, store.ErrResourceNotFound
+	}
+	return pkg, nil
+}
@@ -0,0 +1,215 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	apiauth "github.com/easysoft/gitfox/app/api/auth"
+	"github.com/easysoft/gitfox/app/api/usererror"
+	"github.com/easysoft/gitfox/app/artifact/adapter/container"
+	gitfox_store "github.com/easysoft/gitfox/store"
+	"github.com/easysoft/gitfox/types"
+	"github.com/easysoft/gitfox/types/enum"
+)
+
+type QuotaInput struct {
+	MaxSize     *int64 `json:"max_size"`
+	MaxPackages *int64 `json:"max_packages"`
+}
+
+// GetQuota returns the effective quota and the usage of the space including its sub spaces.
+func (c *Controller) GetQuota(ctx context.Context, req *BaseReq) (*types.ArtifactQuotaRes, error) {
+	if err := apiauth.CheckSpace(ctx, c.authorizer, req.session, req.view.Space, enum.PermissionSpaceView); err != nil {
+		return nil, err
+	}
+
+	quota, err := c.findQuota(ctx, req.view.Space)
+	if err != nil {
+		return nil, err
+	}
+	usage, err := c.subtreeUsage(ctx, req.view.OwnerID)
+	if err != nil {
+		return nil, err
+	}
+	usage.SpaceID = req.view.OwnerID
+
+	return &types.ArtifactQuotaRes{
+		Quota:     quota,
+		Inherited: quota != nil && quota.SpaceID != req.view.OwnerID,
+		Usage:     *usage,
+	}, nil
+}
+
+// SaveQuota creates or updates the quota of the space, it's restricted to the admin
+// because a quota of the space overrides the one inherited from the parent spaces.
+func (c *Controller) SaveQuota(ctx context.Context, req *BaseReq, in *QuotaInput) (*types.ArtifactQuota, error) {
+	if !req.session.Principal.Admin {
+		return nil, usererror.ErrForbidden
+	}
+
+	quota, err := c.artStore.Quotas().GetBySpace(ctx, req.view.OwnerID)
+	if errors.Is(err, gitfox_store.ErrResourceNotFound) {
+		quota = &types.ArtifactQuota{
+			SpaceID:   req.view.OwnerID,
+			CreatedBy: req.session.Principal.ID,
+		}
+	} else if err != nil {
+		return nil, err
+	}
+
+	if in.MaxSize != nil {
+		quota.MaxSize = *in.MaxSize
+	}
+	if in.MaxPackages != nil {
+		quota.MaxPackages = *in.MaxPackages
+	}
+	if quota.MaxSize < 0 || quota.MaxPackages < 0 {
+		return nil, usererror.BadRequest("quota limits must not be negative")
+	}
+
+	if quota.ID == 0 {
+		err = c.artStore.Quotas().Create(ctx, quota)
+	} else {
+		err = c.artStore.Quotas().Update(ctx, quota)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return quota, nil
+}
+
+// DeleteQuota removes the quota of the space, the space inherits the quota of the parent spaces then.
+func (c *Controller) DeleteQuota(ctx context.Context, req *BaseReq) error {
+	if !req.session.Principal.Admin {
+		return usererror.ErrForbidden
+	}
+
+	quota, err := c.artStore.Quotas().GetBySpace(ctx, req.view.OwnerID)
+	if err != nil {
+		return err
+	}
+	return c.artStore.Quotas().DeleteById(ctx, quota.ID)
+}
+
+// findQuota returns the quota of the space or the nearest parent space with a quota,
+// nil is returned if the storage is not limited.
+func (c *Controller) findQuota(ctx context.Context, space *types.Space) (*types.ArtifactQuota, error) {
+	current := space
+	for {
+		quota, err := c.artStore.Quotas().GetBySpace(ctx, current.ID)
+		if err == nil {
+			return quota, nil
+		}
+		if !errors.Is(err, gitfox_store.ErrResourceNotFound) {
+			return nil, err
+		}
+		if current.ParentID == 0 {
+			return nil, nil
+		}
+		if current, err = c.spaceStore.Find(ctx, current.ParentID); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// subtreeUsage sums the usage of the space and all its sub spaces.
+func (c *Controller) subtreeUsage(ctx context.Context, spaceID int64) (*types.ArtifactUsage, error) {
+	spaces, err := c.spaceStore.GetDescendantsData(ctx, spaceID)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int64, len(spaces))
+	for i := range spaces {
+		ids[i] = spaces[i].ID
+	}
+	return c.artStore.Usages().Sum(ctx, ids)
+}
+
+// checkQuota verifies the usage of the space stays in the quota after storing the extra size and packages,
+// the usage is summed up over the sub spaces of the space the quota is set for.
+// It's called with the request size before the upload to deny the upload early, withinQuota verifies
+// the usage after the upload.
+func (c *Controller) checkQuota(ctx context.Context, space *types.Space, size, packages int64) error {
+	quota, err := c.findQuota(ctx, space)
+	if err != nil {
+		return err
+	}
+	return c.verifyUsage(ctx, quota, size, packages)
+}
+
+// withinQuota runs the upload in a transaction and verifies the usage counted by the upload stays in the quota.
+// The quota is locked before anything else in the transaction, so the uploads into the spaces sharing the quota
+// are serialized and the usage read after the lock includes the uploads committed before.
+func (c *Controller) withinQuota(ctx context.Context, space *types.Space, upload func(ctx context.Context) error) error {
+	quota, err := c.findQuota(ctx, space)
+	if err != nil {
+		return err
+	}
+
+	return c.tx.WithTx(ctx, func(ctx context.Context) error {
+		locked := quota
+		if quota != nil {
+			locked, err = c.artStore.Quotas().GetBySpaceForUpdate(ctx, quota.SpaceID)
+			if errors.Is(err, gitfox_store.ErrResourceNotFound) {
+				// the quota is removed meanwhile
+				locked = nil
+			} else if err != nil {
+				return err
+			}
+		}
+
+		if err = upload(ctx); err != nil {
+			return err
+		}
+		return c.verifyUsage(ctx, locked, 0, 0)
+	})
+}
+
+// verifyUsage verifies the usage under the quota stays in it after storing the extra size and packages,
+// the storage is not limited if the quota is nil.
+func (c *Controller) verifyUsage(ctx context.Context, quota *types.ArtifactQuota, size, packages int64) error {
+	if quota == nil {
+		return nil
+	}
+	usage, err := c.subtreeUsage(ctx, quota.SpaceID)
+	if err != nil {
+		return err
+	}
+
+	if quota.MaxSize > 0 && usage.Size+max(size, 0) > quota.MaxSize {
+		return usererror.RequestTooLargef("artifact storage quota exceeded, %d of %d bytes used",
+			usage.Size, quota.MaxSize)
+	}
+	if quota.MaxPackages > 0 && usage.Packages+max(packages, 0) > quota.MaxPackages {
+		return usererror.RequestTooLargef("artifact package quota exceeded, %d of %d packages used",
+			usage.Packages, quota.MaxPackages)
+	}
+	return nil
+}
+
+// checkContainerQuota is checkQuota for the registry api, which denies the upload with the registry error.
+func (c *Controller) checkContainerQuota(ctx context.Context, space *types.Space, size, packages int64) error {
+	return containerQuotaError(c.checkQuota(ctx, space, size, packages))
+}
+
+// withinContainerQuota is withinQuota for the registry api, which denies the upload with the registry error.
+func (c *Controller) withinContainerQuota(ctx context.Context, space *types.Space,
+	upload func(ctx context.Context) error,
+) error {
+	return containerQuotaError(c.withinQuota(ctx, space, upload))
+}
+
+func containerQuotaError(err error) error {
+	var uErr *usererror.Error
+	if errors.As(err, &uErr) && uErr.Status == http.StatusRequestEntityTooLarge {
+		return container.ErrQuotaExceeded.WithDetail(uErr.Message)
+	}
+	return err
+}
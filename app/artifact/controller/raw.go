@@ -14,13 +14,17 @@ import (
 	"github.com/easysoft/gitfox/app/artifact/adapter/raw"
 )
 
-func (c *Controller) UploadRaw(ctx context.Context, r *http.Request, helmReq *BaseReq) (HttpResponseWriter, error) {
-	if c.checkAuthArtifactPush(ctx, helmReq) != nil {
+func (c *Controller) UploadRaw(ctx context.Context, r *http.Request, req *BaseReq) (HttpResponseWriter, error) {
+	if c.checkAuthArtifactPush(ctx, req) != nil {
 		return nil, usererror.ErrForbidden
 	}
 
-	u := raw.NewUploader(helmReq.view.Store, c.artStore, helmReq.view)
-	if e := c.tx.WithTx(ctx, func(ctx context.Context) error {
+	if err := c.checkQuota(ctx, req.view.Space, r.ContentLength, 0); err != nil {
+		return nil, err
+	}
+
+	u := raw.NewUploader(req.view.Store, c.artStore, req.view)
+	if e := c.withinQuota(ctx, req.view.Space, func(ctx context.Context) error {
 		return handleUpload(ctx, r, u)
 	}); e != nil {
 		return nil, e
@@ -78,7 +78,11 @@ func (c *Controller) GetVersionAssetReader(ctx context.Context, viewId, pkgId in
 		return nil, nil, err
 	}
 	r, err := c.fileStore.Open(ctx, adapter.BlobPath(meta.Ref))
-	return r, meta, err
+	if err != nil {
+		return nil, nil, err
+	}
+	c.downloads.Record(pkgId, meta.VersionId)
+	return r, meta, nil
 }
 
 func (c *Controller) GetVersionAssetInfo(ctx context.Context, viewId, pkgId int64, version, path string) (*VersionAssetMeta, error) {
@@ -100,6 +104,7 @@ func (c *Controller) GetVersionAssetInfo(ctx context.Context, viewId, pkgId int6
 	assetMeta := newAssetMeta(asset, blob)
 	verMeta := &VersionAssetMeta{
 		AssetMeta: assetMeta,
+		VersionId: ver.ID,
 	}
 	return verMeta, nil
 }
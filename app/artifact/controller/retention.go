@@ -0,0 +1,248 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/easysoft/gitfox/app/api/usererror"
+	"github.com/easysoft/gitfox/app/artifact/model"
+	gitfox_store "github.com/easysoft/gitfox/store"
+	"github.com/easysoft/gitfox/types"
+
+	"github.com/rs/zerolog/log"
+)
+
+const retentionVersionPageSize = 100
+
+type RetentionPolicyInput struct {
+	KeepLast      *int    `json:"keep_last"`
+	MaxAgeDays    *int    `json:"max_age_days"`
+	NotPulledDays *int    `json:"not_pulled_days"`
+	KeepPattern   *string `json:"keep_pattern"`
+	ProtectedTags *string `json:"protected_tags"`
+	Enabled       *bool   `json:"enabled"`
+}
+
+func (c *Controller) ListRetentionPolicies(ctx context.Context, req *BaseReq) ([]*types.ArtifactRetentionPolicy, error) {
+	if err := c.checkAuthSpaceEdit(ctx, req); err != nil {
+		return nil, err
+	}
+	return c.artStore.RetentionPolicies().ListBySpace(ctx, req.view.OwnerID)
+}
+
+// SaveRetentionPolicy creates or updates the retention policy of the format in the space.
+func (c *Controller) SaveRetentionPolicy(ctx context.Context, req *BaseReq, format types.ArtifactFormat, in *RetentionPolicyInput,
+) (*types.ArtifactRetentionPolicy, error) {
+	if err := c.checkAuthSpaceEdit(ctx, req); err != nil {
+		return nil, err
+	}
+	if !slices.Contains(types.AllArtifactFormatList, format) {
+		return nil, usererror.BadRequestf("unknown artifact format '%s'", format)
+	}
+
+	policy, err := c.artStore.RetentionPolicies().GetByFormat(ctx, req.view.OwnerID, format)
+	if errors.Is(err, gitfox_store.ErrResourceNotFound) {
+		policy = &types.ArtifactRetentionPolicy{
+			SpaceID:   req.view.OwnerID,
+			Format:    format,
+			CreatedBy: req.session.Principal.ID,
+		}
+	} else if err != nil {
+		return nil, err
+	}
+
+	if in.KeepLast != nil {
+		policy.KeepLast = *in.KeepLast
+	}
+	if in.MaxAgeDays != nil {
+		policy.MaxAgeDays = *in.MaxAgeDays
+	}
+	if in.NotPulledDays != nil {
+		policy.NotPulledDays = *in.NotPulledDays
+	}
+	if in.KeepPattern != nil {
+		policy.KeepPattern = *in.KeepPattern
+	}
+	if in.ProtectedTags != nil {
+		policy.ProtectedTags = *in.ProtectedTags
+	}
+	if in.Enabled != nil {
+		policy.Enabled = *in.Enabled
+	}
+	if _, err = model.NewRetentionRule(policy); err != nil {
+		return nil, usererror.BadRequest(err.Error())
+	}
+
+	if policy.ID == 0 {
+		err = c.artStore.RetentionPolicies().Create(ctx, policy)
+	} else {
+		err = c.artStore.RetentionPolicies().Update(ctx, policy)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+func (c *Controller) DeleteRetentionPolicy(ctx context.Context, req *BaseReq, format types.ArtifactFormat) error {
+	if err := c.checkAuthSpaceEdit(ctx, req); err != nil {
+		return err
+	}
+
+	policy, err := c.artStore.RetentionPolicies().GetByFormat(ctx, req.view.OwnerID, format)
+	if err != nil {
+		return err
+	}
+	return c.artStore.RetentionPolicies().DeleteById(ctx, policy.ID)
+}
+
+// DryRunRetentionPolicy reports the versions would be removed by the policy without removing them.
+func (c *Controller) DryRunRetentionPolicy(ctx context.Context, req *BaseReq, format types.ArtifactFormat) (*types.ArtifactRetentionReport, error) {
+	if err := c.checkAuthSpaceEdit(ctx, req); err != nil {
+		return nil, err
+	}
+
+	policy, err := c.artStore.RetentionPolicies().GetByFormat(ctx, req.view.OwnerID, format)
+	if err != nil {
+		return nil, err
+	}
+	return c.runRetentionPolicy(ctx, policy, true)
+}
+
+// ApplyRetentionPolicies soft removes the versions out of the enabled retention policies,
+// the content is recycled by the soft-remove garbage collection later.
+func (c *Controller) ApplyRetentionPolicies(ctx context.Context) ([]*types.ArtifactRetentionReport, error) {
+	policies, err := c.artStore.RetentionPolicies().ListEnabled(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reports := make([]*types.ArtifactRetentionReport, 0, len(policies))
+	for _, policy := range policies {
+		report, e := c.runRetentionPolicy(ctx, policy, false)
+		if e != nil {
+			log.Ctx(ctx).Warn().Err(e).Msgf("apply retention policy %d failed", policy.ID)
+			continue
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
+
+func (c *Controller) runRetentionPolicy(ctx context.Context, policy *types.ArtifactRetentionPolicy, dryRun bool,
+) (*types.ArtifactRetentionReport, error) {
+	rule, err := model.NewRetentionRule(policy)
+	if err != nil {
+		return nil, err
+	}
+
+	packages, err := c.artStore.Packages().List(ctx, policy.SpaceID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &types.ArtifactRetentionReport{
+		Policy:     policy,
+		DryRun:     dryRun,
+		Candidates: make([]*types.ArtifactRetentionCandidate, 0),
+	}
+	// versions are removed per view, the index of the view is regenerated after that
+	removals := make(map[int64][]*types.ArtifactVersion)
+	now := time.Now()
+	for _, pkg := range packages {
+		if pkg.Format != policy.Format {
+			continue
+		}
+
+		versions, e := c.listPackageVersions(ctx, pkg.ID)
+		if e != nil {
+			return nil, e
+		}
+
+		viewVersions := make(map[int64][]*types.ArtifactVersion)
+		for _, v := range versions {
+			viewVersions[v.ViewID] = append(viewVersions[v.ViewID], v)
+		}
+		for viewId, items := range viewVersions {
+			for _, res := range rule.Select(items, now) {
+				report.Candidates = append(report.Candidates, &types.ArtifactRetentionCandidate{
+					Package:   pkg.Name,
+					Namespace: pkg.Namespace,
+					Version:   res.Version.Version,
+					VersionID: res.Version.ID,
+					ViewID:    viewId,
+					Updated:   res.Version.Updated,
+					Reason:    res.Reason,
+				})
+				removals[viewId] = append(removals[viewId], res.Version)
+			}
+		}
+	}
+
+	if dryRun || len(removals) == 0 {
+		return report, nil
+	}
+
+	for viewId, versions := range removals {
+		removed, e := c.removeRetentionVersions(ctx, policy.SpaceID, viewId, versions)
+		report.Removed += removed
+		if e != nil {
+			return report, e
+		}
+	}
+	log.Ctx(ctx).Info().Msgf("retention policy %d removed %d %s versions of space %d",
+		policy.ID, report.Removed, policy.Format, policy.SpaceID)
+	return report, nil
+}
+
+func (c *Controller) removeRetentionVersions(ctx context.Context, spaceId, viewId int64, versions []*types.ArtifactVersion) (int, error) {
+	space, err := c.spaceStore.Find(ctx, spaceId)
+	if err != nil {
+		return 0, err
+	}
+	view, err := c.artStore.Views().GetByID(ctx, viewId)
+	if err != nil {
+		return 0, err
+	}
+
+	req := c.newViewRequest(space, view)
+
+	res := &types.ArtifactNodeRemoveRes{}
+	idx := &IndexUpdater{}
+	err = c.tx.WithTx(ctx, func(ctx context.Context) error {
+		for _, ver := range versions {
+			if e := c.softRemoveVersion(ctx, ver, res, idx); e != nil {
+				return e
+			}
+		}
+		return idx.Run(ctx, c, req)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return res.Versions, nil
+}
+
+func (c *Controller) listPackageVersions(ctx context.Context, packageId int64) ([]*types.ArtifactVersion, error) {
+	result := make([]*types.ArtifactVersion, 0)
+	for page := 1; ; page++ {
+		versions, err := c.artStore.Versions().Find(ctx, types.SearchVersionOption{
+			PackageId: packageId, Page: page, Size: retentionVersionPageSize,
+		})
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, versions...)
+		if len(versions) < retentionVersionPageSize {
+			return result, nil
+		}
+	}
+}
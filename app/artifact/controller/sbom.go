@@ -0,0 +1,263 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/easysoft/gitfox/app/api/usererror"
+	"github.com/easysoft/gitfox/app/artifact/adapter/container/schema"
+	"github.com/easysoft/gitfox/app/artifact/adapter/container/schema/ocischema"
+	"github.com/easysoft/gitfox/app/artifact/adapter/sbom"
+	gitfox_store "github.com/easysoft/gitfox/store"
+	"github.com/easysoft/gitfox/types"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/rs/zerolog/log"
+)
+
+// UploadSbom replaces the components of the version with the ones listed in the CycloneDX or SPDX document.
+func (c *Controller) UploadSbom(ctx context.Context, req *BaseReq,
+	packageName, group, version string, format types.ArtifactFormat, r io.Reader,
+) (*types.ArtifactSbomUploadRes, error) {
+	if err := c.checkAuthArtifactPush(ctx, req); err != nil {
+		return nil, err
+	}
+
+	dbVer, err := c.artStore.GetVersion(ctx, req.view.Space.ID, req.view.ViewID, packageName, group, version, format)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := sbom.Parse(r, c.sbomMaxSize)
+	if errors.Is(err, sbom.ErrTooLarge) {
+		return nil, usererror.RequestTooLargef("the sbom document exceeds the maximum size of %d bytes", c.sbomMaxSize)
+	} else if err != nil {
+		return nil, usererror.BadRequest(err.Error())
+	}
+
+	if err = c.tx.WithTx(ctx, func(ctx context.Context) error {
+		return c.saveSbomComponents(ctx, doc, dbVer.ID)
+	}); err != nil {
+		return nil, err
+	}
+	return &types.ArtifactSbomUploadRes{Format: doc.Format, Components: len(doc.Components)}, nil
+}
+
+func (c *Controller) ListSbomComponents(ctx context.Context, req *BaseReq,
+	packageName, group, version string, format types.ArtifactFormat,
+) ([]*types.ArtifactSbomComponent, error) {
+	dbVer, err := c.artStore.GetVersion(ctx, req.view.Space.ID, req.view.ViewID, packageName, group, version, format)
+	if err != nil {
+		return nil, err
+	}
+	return c.artStore.SbomComponents().ListByVersion(ctx, dbVer.ID)
+}
+
+// SearchSbomComponents finds the package versions in the view which contain the component,
+// the component version is filtered by the semver constraint like '< 2.17'.
+func (c *Controller) SearchSbomComponents(ctx context.Context, req *BaseReq, name, group, constraint string,
+) ([]*types.ArtifactSbomComponentMatch, error) {
+	if name == "" {
+		return nil, usererror.BadRequest("component name is required")
+	}
+
+	var cons *semver.Constraints
+	if constraint != "" {
+		var err error
+		if cons, err = semver.NewConstraint(constraint); err != nil {
+			return nil, usererror.BadRequestf("invalid version constraint '%s': %s", constraint, err)
+		}
+	}
+
+	matches, err := c.artStore.SbomComponents().SearchByName(ctx, req.view.OwnerID, req.view.ViewID, name, group)
+	if err != nil {
+		return nil, err
+	}
+	if cons == nil {
+		return matches, nil
+	}
+
+	result := make([]*types.ArtifactSbomComponentMatch, 0, len(matches))
+	for _, m := range matches {
+		v, e := semver.NewVersion(m.ComponentVersion)
+		if e != nil {
+			// the version out of semver only matches the constraint literally
+			if m.ComponentVersion == constraint {
+				result = append(result, m)
+			}
+			continue
+		}
+		if cons.Check(v) {
+			result = append(result, m)
+		}
+	}
+	return result, nil
+}
+
+// ingestReferrerSbom saves the components of the sbom attached to an image as a referrer,
+// they are bound to the tags of the subject image in the view. The tags put later are bound
+// by linkSubjectSboms.
+func (c *Controller) ingestReferrerSbom(ctx context.Context, req *ContainerManifestRequest, m schema.Manifest, subject string) error {
+	if subject == "" || sbomLayer(m) == nil {
+		return nil
+	}
+
+	versionIds, err := c.findSubjectTags(ctx, req, subject)
+	if err != nil {
+		return err
+	}
+	if len(versionIds) == 0 {
+		log.Ctx(ctx).Debug().Msgf("no tag of %s@%s found, skip the sbom", req.repoName, subject)
+		return nil
+	}
+
+	log.Ctx(ctx).Info().Msgf("index sbom of %s@%s", req.repoName, subject)
+	return c.saveReferrerSbom(ctx, req, m, versionIds...)
+}
+
+// linkSubjectSboms binds the sboms attached to the image before it's tagged to the tag.
+func (c *Controller) linkSubjectSboms(ctx context.Context, req *ContainerManifestRequest, subject string) error {
+	referrers, err := c.artStore.Referrers().ListBySubject(ctx, req.view.ViewID, req.repoName, subject, "")
+	if err != nil {
+		return err
+	}
+	if len(referrers) == 0 {
+		return nil
+	}
+
+	dbVer, err := c.artStore.GetVersion(ctx, req.view.Space.ID, req.view.ViewID, req.repoName, "", req.Tag,
+		types.ArtifactContainerFormat)
+	if err != nil {
+		return err
+	}
+
+	for _, ref := range referrers {
+		content, meta, e := c.GetAssetContent(ctx, ref.Digest, types.ArtifactContainerFormat, nil)
+		if errors.Is(e, gitfox_store.ErrResourceNotFound) {
+			continue
+		} else if e != nil {
+			return e
+		}
+		m, e := schema.UnmarshalManifest(meta.ContentType, content)
+		if e != nil {
+			log.Ctx(ctx).Debug().Err(e).Msgf("skip the unknown referrer %s", ref.Digest)
+			continue
+		}
+		if sbomLayer(m) == nil {
+			continue
+		}
+
+		log.Ctx(ctx).Info().Msgf("link sbom %s to %s:%s", ref.Digest, req.repoName, req.Tag)
+		if e = c.saveReferrerSbom(ctx, req, m, dbVer.ID); e != nil {
+			return e
+		}
+	}
+	return nil
+}
+
+// sbomLayer returns the layer of the sbom document in the referrer manifest, nil is returned if there is none.
+func sbomLayer(m schema.Manifest) *schema.Descriptor {
+	manifest, ok := m.(*ocischema.DeserializedManifest)
+	if !ok {
+		return nil
+	}
+
+	for i := range manifest.Layers {
+		if sbom.IsMediaType(manifest.Layers[i].MediaType) {
+			return &manifest.Layers[i]
+		}
+	}
+	if sbom.IsMediaType(manifest.GetArtifactType()) && len(manifest.Layers) == 1 {
+		return &manifest.Layers[0]
+	}
+	return nil
+}
+
+// saveReferrerSbom replaces the components of the versions with the ones of the sbom in the referrer manifest.
+func (c *Controller) saveReferrerSbom(ctx context.Context, req *ContainerManifestRequest, m schema.Manifest,
+	versionIds ...int64,
+) error {
+	layer := sbomLayer(m)
+	if layer == nil {
+		return nil
+	}
+
+	if layer.Size > c.sbomMaxSize {
+		log.Ctx(ctx).Warn().Msgf("skip sbom %s of %s, its size %d exceeds the maximum %d",
+			layer.Digest, req.repoName, layer.Size, c.sbomMaxSize)
+		return nil
+	}
+
+	fr, _, err := c.GetAssetReader(ctx, layer.Digest.String(), types.ArtifactContainerFormat, nil)
+	if err != nil {
+		return err
+	}
+	defer fr.Close()
+
+	doc, err := sbom.Parse(fr, c.sbomMaxSize)
+	if err != nil {
+		// an invalid sbom should not fail the push of the referrer
+		log.Ctx(ctx).Warn().Err(err).Msgf("parse sbom %s of %s failed", layer.Digest, req.repoName)
+		return nil
+	}
+
+	log.Ctx(ctx).Debug().Msgf("save %d sbom components of %s", len(doc.Components), req.repoName)
+	return c.saveSbomComponents(ctx, doc, versionIds...)
+}
+
+// findSubjectTags returns the ids of the tags point to the manifest digest.
+func (c *Controller) findSubjectTags(ctx context.Context, req *ContainerManifestRequest, subject string) ([]int64, error) {
+	dbPkg, err := c.artStore.Packages().GetByName(ctx, req.repoName, "", req.view.OwnerID, types.ArtifactContainerFormat)
+	if errors.Is(err, gitfox_store.ErrResourceNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	versions, err := c.listPackageVersions(ctx, dbPkg.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]int64, 0)
+	for _, ver := range versions {
+		if ver.ViewID != req.view.ViewID {
+			continue
+		}
+		assets, e := c.artStore.Assets().FindMain(ctx, ver.ID)
+		if e != nil {
+			return nil, e
+		}
+		if len(assets) == 1 && assets[0].Path == subject {
+			result = append(result, ver.ID)
+		}
+	}
+	return result, nil
+}
+
+func (c *Controller) saveSbomComponents(ctx context.Context, doc *sbom.Document, versionIds ...int64) error {
+	for _, id := range versionIds {
+		components := make([]*types.ArtifactSbomComponent, 0, len(doc.Components))
+		for _, comp := range doc.Components {
+			components = append(components, &types.ArtifactSbomComponent{
+				Name:    comp.Name,
+				Group:   comp.Group,
+				Version: comp.Version,
+				Purl:    comp.Purl,
+				Type:    comp.Type,
+				Source:  doc.Format,
+			})
+		}
+		if err := c.artStore.SbomComponents().ReplaceByVersion(ctx, id, components); err != nil {
+			return err
+		}
+	}
+	return nil
+}
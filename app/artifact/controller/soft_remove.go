@@ -30,7 +30,7 @@ func (c *Controller) SoftRemove(ctx context.Context, req *BaseReq, in *ListNodeI
 		if err != nil {
 			return err
 		}
-		err = idx.Run(ctx, c.artStore, req)
+		err = idx.Run(ctx, c, req)
 		return err
 	}); e != nil {
 		// sql rollback, nothing executed
@@ -89,7 +89,7 @@ OUTER:
 				}
 				continue OUTER
 			}
-			err = c.softRemoveAsset(ctx, asset, res)
+			err = c.softRemoveAsset(ctx, req.view.OwnerID, asset, res)
 			if err != nil {
 				errorList = append(errorList, err)
 				res.Status = types.ArtifactStatusUnknown
@@ -195,7 +195,9 @@ func (c *Controller) findPackagesByNode(ctx context.Context, n *types.ArtifactTr
 	return packages, nil
 }
 
-func (c *Controller) softRemoveAsset(ctx context.Context, asset *types.ArtifactAsset, res *types.ArtifactNodeRemoveRes) error {
+// softRemoveAsset soft removes the asset and its blob, the blob size is released from the usage of the space.
+// The blob shared with the assets of the promoted versions is kept until the last asset is removed.
+func (c *Controller) softRemoveAsset(ctx context.Context, spaceId int64, asset *types.ArtifactAsset, res *types.ArtifactNodeRemoveRes) error {
 	var err error
 	if err = c.artStore.Assets().SoftDeleteById(ctx, asset.ID); err != nil {
 		return err
@@ -204,6 +206,24 @@ func (c *Controller) softRemoveAsset(ctx context.Context, asset *types.ArtifactA
 		return nil
 	}
 
+	refs, err := c.artStore.Assets().CountByBlob(ctx, asset.BlobID, false)
+	if err != nil {
+		return err
+	}
+	if refs > 0 {
+		res.Assets += 1
+		return nil
+	}
+
+	blob, err := c.artStore.Blobs().GetById(ctx, asset.BlobID)
+	if err != nil {
+		return err
+	}
+	if !blob.Deleted.Valid || blob.Deleted.Int64 == 0 {
+		if err = c.artStore.Usages().Add(ctx, spaceId, -blob.Size, 0); err != nil {
+			return err
+		}
+	}
 	if err = c.artStore.Blobs().SoftDeleteById(ctx, asset.BlobID); err != nil {
 		return err
 	}
@@ -212,6 +232,11 @@ func (c *Controller) softRemoveAsset(ctx context.Context, asset *types.ArtifactA
 }
 
 func (c *Controller) softRemoveVersion(ctx context.Context, ver *types.ArtifactVersion, res *types.ArtifactNodeRemoveRes, idx *IndexUpdater) error {
+	dbPkg, e := c.artStore.Packages().GetByID(ctx, ver.PackageID)
+	if e != nil {
+		return e
+	}
+
 	// find assets of the version and soft remove them
 	assets, e := c.artStore.ListAssets(ctx, ver.ID)
 	if e != nil {
@@ -222,7 +247,7 @@ func (c *Controller) softRemoveVersion(ctx context.Context, ver *types.ArtifactV
 		if err != nil {
 			return err
 		}
-		if err = c.softRemoveAsset(ctx, dbAsset, res); err != nil {
+		if err = c.softRemoveAsset(ctx, dbPkg.OwnerID, dbAsset, res); err != nil {
 			return err
 		}
 	}
@@ -234,13 +259,7 @@ func (c *Controller) softRemoveVersion(ctx context.Context, ver *types.ArtifactV
 	res.Versions++
 
 	// find treeNode of the version and delete it
-	dbPkg, e := c.artStore.Packages().GetByID(ctx, ver.PackageID)
-	if e != nil {
-		return e
-	}
-	if dbPkg.Format == types.ArtifactHelmFormat {
-		idx.helm = true
-	}
+	idx.mark(dbPkg.Format)
 	nodePath, err := model.BuildPath(dbPkg.Namespace, dbPkg.Name, ver.Version)
 	if err != nil {
 		return err
@@ -269,13 +288,16 @@ func (c *Controller) softRemovePackage(ctx context.Context, pkg *types.ArtifactP
 	}
 
 	// soft remove the package
+	if !pkg.IsDeleted() {
+		if err = c.artStore.Usages().Add(ctx, pkg.OwnerID, 0, -1); err != nil {
+			return err
+		}
+	}
 	if err = c.artStore.Packages().SoftDelete(ctx, pkg); err != nil {
 		return err
 	}
 	res.Packages++
 
-	if pkg.Format == types.ArtifactHelmFormat {
-		idx.helm = true
-	}
+	idx.mark(pkg.Format)
 	return nil
 }
@@ -0,0 +1,138 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	apiauth "github.com/easysoft/gitfox/app/api/auth"
+	"github.com/easysoft/gitfox/app/api/usererror"
+	gitfox_store "github.com/easysoft/gitfox/store"
+	"github.com/easysoft/gitfox/types"
+	"github.com/easysoft/gitfox/types/check"
+	"github.com/easysoft/gitfox/types/enum"
+)
+
+type ViewCreateInput struct {
+	Identifier   string                `json:"identifier"`
+	Description  string                `json:"description"`
+	Protected    bool                  `json:"protected"`
+	PromoteRoles []enum.MembershipRole `json:"promote_roles"`
+}
+
+type ViewUpdateInput struct {
+	Description  *string                `json:"description"`
+	Protected    *bool                  `json:"protected"`
+	PromoteRoles *[]enum.MembershipRole `json:"promote_roles"`
+}
+
+// ListViews lists the artifact views of the space.
+func (c *Controller) ListViews(ctx context.Context, req *BaseReq) ([]*types.ArtifactViewRes, error) {
+	if err := apiauth.CheckSpace(ctx, c.authorizer, req.session, req.view.Space, enum.PermissionSpaceView); err != nil {
+		return nil, err
+	}
+
+	views, err := c.artStore.Views().List(ctx, req.view.OwnerID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*types.ArtifactViewRes, 0, len(views))
+	for _, view := range views {
+		result = append(result, toViewRes(view))
+	}
+	return result, nil
+}
+
+// CreateView creates a standalone view in the space, like a 'release' view the vetted versions are promoted to.
+func (c *Controller) CreateView(ctx context.Context, req *BaseReq, in *ViewCreateInput) (*types.ArtifactViewRes, error) {
+	if err := c.checkAuthSpaceEdit(ctx, req); err != nil {
+		return nil, err
+	}
+
+	if err := check.Identifier(in.Identifier); err != nil {
+		return nil, err
+	}
+	if _, err := c.artStore.Views().GetByName(ctx, req.view.OwnerID, in.Identifier); err == nil {
+		return nil, usererror.Conflict("artifact view already exists")
+	} else if !errors.Is(err, gitfox_store.ErrResourceNotFound) {
+		return nil, err
+	}
+
+	roles, err := sanitizePromoteRoles(in.PromoteRoles)
+	if err != nil {
+		return nil, err
+	}
+
+	view := &types.ArtifactView{
+		Name:         in.Identifier,
+		Description:  strings.TrimSpace(in.Description),
+		SpaceID:      req.view.OwnerID,
+		Kind:         enum.ArtifactRepoKindStandalone,
+		Protected:    in.Protected,
+		PromoteRoles: roles,
+	}
+	if err = c.artStore.Views().Create(ctx, view); err != nil {
+		return nil, err
+	}
+	return toViewRes(view), nil
+}
+
+// UpdateView updates the description and the promotion restriction of the view.
+func (c *Controller) UpdateView(ctx context.Context, req *BaseReq, identifier string, in *ViewUpdateInput,
+) (*types.ArtifactViewRes, error) {
+	if err := c.checkAuthSpaceEdit(ctx, req); err != nil {
+		return nil, err
+	}
+
+	view, err := c.artStore.Views().GetByName(ctx, req.view.OwnerID, identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	if in.Description != nil {
+		view.Description = strings.TrimSpace(*in.Description)
+	}
+	if in.Protected != nil {
+		view.Protected = *in.Protected
+	}
+	if in.PromoteRoles != nil {
+		if view.PromoteRoles, err = sanitizePromoteRoles(*in.PromoteRoles); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = c.artStore.Views().Update(ctx, view); err != nil {
+		return nil, err
+	}
+	return toViewRes(view), nil
+}
+
+func sanitizePromoteRoles(roles []enum.MembershipRole) (string, error) {
+	result := make([]string, 0, len(roles))
+	for _, role := range roles {
+		sanitized, ok := role.Sanitize()
+		if !ok || sanitized == "" {
+			return "", usererror.BadRequestf("invalid membership role '%s'", role)
+		}
+		result = append(result, string(sanitized))
+	}
+	return strings.Join(result, ","), nil
+}
+
+func toViewRes(view *types.ArtifactView) *types.ArtifactViewRes {
+	return &types.ArtifactViewRes{
+		Identifier:   view.Name,
+		Description:  view.Description,
+		Kind:         view.Kind,
+		Default:      view.Default,
+		Protected:    view.Protected,
+		PromoteRoles: view.GetPromoteRoles(),
+	}
+}
@@ -9,11 +9,16 @@ package controller
 import (
 	"github.com/easysoft/gitfox/app/auth/authz"
 	"github.com/easysoft/gitfox/app/services/artifactgc"
+	"github.com/easysoft/gitfox/app/services/artifactstats"
+	"github.com/easysoft/gitfox/app/services/blobmigrate"
 	"github.com/easysoft/gitfox/app/services/settings"
 	"github.com/easysoft/gitfox/app/store"
 	"github.com/easysoft/gitfox/app/url"
+	"github.com/easysoft/gitfox/encrypt"
+	"github.com/easysoft/gitfox/git"
 	"github.com/easysoft/gitfox/pkg/storage"
 	"github.com/easysoft/gitfox/store/database/dbtx"
+	"github.com/easysoft/gitfox/types"
 
 	"github.com/google/wire"
 )
@@ -25,7 +30,11 @@ var WireSet = wire.NewSet(
 func ProvideArtifactController(tx dbtx.Transactor,
 	urlProvider url.Provider, authorizer authz.Authorizer,
 	artStore store.ArtifactStore, spaceStore store.SpaceStore, fileStore storage.ContentStorage,
-	settings *settings.Service, gcSvc *artifactgc.Service,
+	settings *settings.Service, gcSvc *artifactgc.Service, migrateSvc *blobmigrate.Service,
+	secretStore store.SecretStore, connectorStore store.ConnectorStore, encrypter encrypt.Encrypter,
+	repoStore store.RepoStore, git git.Interface, membershipStore store.MembershipStore,
+	downloads *artifactstats.Recorder, config *types.Config,
 ) *Controller {
-	return NewController(tx, urlProvider, authorizer, artStore, spaceStore, fileStore, settings, gcSvc)
+	return NewController(tx, urlProvider, authorizer, artStore, spaceStore, fileStore, settings, gcSvc, migrateSvc,
+		secretStore, connectorStore, encrypter, repoStore, git, membershipStore, downloads, config.Artifact.SbomMaxSize)
 }
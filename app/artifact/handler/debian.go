@@ -0,0 +1,144 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/easysoft/gitfox/app/api/render"
+	"github.com/easysoft/gitfox/app/api/request"
+	artctl "github.com/easysoft/gitfox/app/artifact/controller"
+)
+
+const (
+	_debianDistributionParam = "distribution"
+	_debianComponentParam    = "component"
+	_debianFilenameParam     = "filename"
+)
+
+// HandDebianUpload returns a http.HandlerFunc that uploads a .deb file by 'PUT pool/{distribution}/{component}/upload'.
+func HandDebianUpload(artCtl *artctl.Controller) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		baseReq, err := artCtl.LoadBaseRequest(ctx, r)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		distribution, err := request.PathParamOrError(r, _debianDistributionParam)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+		component, err := request.PathParamOrError(r, _debianComponentParam)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		resWriter, err := artCtl.UploadDebian(ctx, r, baseReq, distribution, component)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		resWriter.Write(w)
+	}
+}
+
+// HandDebianPoolDownload returns a http.HandlerFunc that downloads a package file of the pool.
+func HandDebianPoolDownload(artCtrl *artctl.Controller) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		spaceRef, err := request.GetSpaceRefFromPath(r)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		reqView, err := artCtrl.ParseSpaceView(ctx, spaceRef)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		fr, meta, err := artCtrl.GetDebianPoolReader(ctx, reqView,
+			request.PathParamOrEmpty(r, _debianDistributionParam),
+			request.PathParamOrEmpty(r, _debianComponentParam),
+			request.PathParamOrEmpty(r, _debianFilenameParam))
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		meta.Write(w)
+		render.Reader(ctx, w, http.StatusOK, fr)
+	}
+}
+
+// HandDebianIndex returns a http.HandlerFunc that downloads an index file under 'dists/'.
+func HandDebianIndex(artCtrl *artctl.Controller) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		spaceRef, err := request.GetSpaceRefFromPath(r)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		reqView, err := artCtrl.ParseSpaceView(ctx, spaceRef)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		filePath, err := request.PathParamOrError(r, "*")
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		fr, meta, err := artCtrl.GetDebianIndexReader(ctx, reqView, strings.Trim(filePath, "/"))
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		meta.Write(w)
+		render.Reader(ctx, w, http.StatusOK, fr)
+	}
+}
+
+// HandDebianKey returns a http.HandlerFunc that downloads the public key verifying the repository.
+func HandDebianKey(artCtrl *artctl.Controller) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		spaceRef, err := request.GetSpaceRefFromPath(r)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		reqView, err := artCtrl.ParseSpaceView(ctx, spaceRef)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		key, err := artCtrl.GetDebianKey(ctx, reqView)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/pgp-keys")
+		render.Reader(ctx, w, http.StatusOK, strings.NewReader(key.PublicKey))
+	}
+}
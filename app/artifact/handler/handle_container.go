@@ -115,18 +115,30 @@ func HandleBlobDelete(artCtrl *artctl.Controller) http.HandlerFunc {
 func HandleBlobUploadStart(artCtrl *artctl.Controller) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
-		// cross mount feature is not implemented
-		if r.FormValue("from") != "" || r.FormValue("mount") != "" {
-			container.RenderError(ctx, w, container.ErrUnsupported.WithDetail("cross mount is not supported"))
-			return
-		}
-
 		ctnReq, err := artCtrl.LoadContainerRequest(ctx, r)
 		if err != nil {
 			container.RenderError(ctx, w, err)
 			return
 		}
 
+		// cross repository mount, a regular upload is started if the blob isn't mounted
+		if paramMount := r.FormValue("mount"); paramMount != "" {
+			dgst, e := digest.Parse(paramMount)
+			if e != nil {
+				container.RenderError(ctx, w, container.ErrDigestInvalid)
+				return
+			}
+			resWriter, mounted, e := artCtrl.MountContainerBlob(ctx, ctnReq, dgst, r.FormValue("from"))
+			if e != nil {
+				container.RenderError(ctx, w, e)
+				return
+			}
+			if mounted {
+				resWriter.Write(w)
+				return
+			}
+		}
+
 		var resWriter artctl.HttpResponseWriter
 		paramDigest := r.FormValue(request.ParamDigest)
 		if paramDigest != "" {
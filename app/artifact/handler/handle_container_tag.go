@@ -10,8 +10,11 @@ import (
 	"net/http"
 
 	"github.com/easysoft/gitfox/app/api/render"
+	"github.com/easysoft/gitfox/app/api/request"
 	"github.com/easysoft/gitfox/app/artifact/adapter/container"
 	artctl "github.com/easysoft/gitfox/app/artifact/controller"
+
+	"github.com/opencontainers/go-digest"
 )
 
 func HandleListTag(artCtrl *artctl.Controller) http.HandlerFunc {
@@ -23,11 +26,71 @@ func HandleListTag(artCtrl *artctl.Controller) http.HandlerFunc {
 			return
 		}
 
-		data, err := artCtrl.ListTags(ctx, req)
+		page, err := container.ParsePagination(r.URL.Query())
+		if err != nil {
+			container.RenderError(ctx, w, err)
+			return
+		}
+
+		data, more, err := artCtrl.ListTags(ctx, req, page)
 		if err != nil {
 			container.RenderError(ctx, w, err)
 			return
 		}
+		if more {
+			w.Header().Set("Link", page.NextLink(r.URL.Path, data.Tags[len(data.Tags)-1]))
+		}
+		render.JSON(w, http.StatusOK, data)
+	}
+}
+
+func HandleCatalog(artCtrl *artctl.Controller) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		page, err := container.ParsePagination(r.URL.Query())
+		if err != nil {
+			container.RenderError(ctx, w, err)
+			return
+		}
+
+		session, _ := request.AuthSessionFrom(ctx)
+		data, more, err := artCtrl.ListCatalog(ctx, session, page)
+		if err != nil {
+			container.RenderError(ctx, w, err)
+			return
+		}
+		if more {
+			w.Header().Set("Link", page.NextLink(r.URL.Path, data.Repositories[len(data.Repositories)-1]))
+		}
 		render.JSON(w, http.StatusOK, data)
 	}
 }
+
+func HandleReferrers(artCtrl *artctl.Controller) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		req, err := artCtrl.LoadContainerRequest(ctx, r)
+		if err != nil {
+			container.RenderError(ctx, w, err)
+			return
+		}
+
+		digestFromPath, err := request.GetDigestFromPath(r)
+		if err != nil {
+			container.RenderError(ctx, w, err)
+			return
+		}
+		subject, err := digest.Parse(digestFromPath)
+		if err != nil {
+			container.RenderError(ctx, w, container.ErrDigestInvalid)
+			return
+		}
+
+		resWriter, err := artCtrl.ListContainerReferrers(ctx, req, subject, r.URL.Query().Get("artifactType"))
+		if err != nil {
+			container.RenderError(ctx, w, err)
+			return
+		}
+		resWriter.Write(w)
+	}
+}
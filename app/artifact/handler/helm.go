@@ -29,6 +29,9 @@ import (
 )
 
 const (
+	_helmPathParamName    = "name"
+	_helmPathParamVersion = "version"
+
 	_helmPathPattern = `^(?P<name>[a-z][a-z0-9\-]+[a-z0-9])-(?P<version>` + semver.SemVerRegex + `)\.tgz$`
 )
 
@@ -53,6 +56,70 @@ func HandHelmUpload(artCtl *artctl.Controller) http.HandlerFunc {
 	}
 }
 
+// HandHelmDelete returns a http.HandlerFunc that deletes a chart version.
+func HandHelmDelete(artCtl *artctl.Controller) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		baseReq, err := artCtl.LoadBaseRequest(ctx, r)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		name, err := request.PathParamOrError(r, _helmPathParamName)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+		version, err := request.PathParamOrError(r, _helmPathParamVersion)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		resWriter, err := artCtl.DeleteHelmChart(ctx, baseReq, name, version)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		resWriter.Write(w)
+	}
+}
+
+// HandHelmYank returns a http.HandlerFunc that yanks a chart version, or restores it if yanked is false.
+func HandHelmYank(artCtl *artctl.Controller, yanked bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		baseReq, err := artCtl.LoadBaseRequest(ctx, r)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		name, err := request.PathParamOrError(r, _helmPathParamName)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+		version, err := request.PathParamOrError(r, _helmPathParamVersion)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		resWriter, err := artCtl.YankHelmChart(ctx, baseReq, name, version, yanked)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		resWriter.Write(w)
+	}
+}
+
 // HandHelmDownload returns a http.HandlerFunc that download a file.
 func HandHelmDownload(artStore store.ArtifactStore, artCtrl *artctl.Controller) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
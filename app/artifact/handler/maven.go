@@ -0,0 +1,75 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"net/http"
+
+	"github.com/easysoft/gitfox/app/api/render"
+	"github.com/easysoft/gitfox/app/api/request"
+	artctl "github.com/easysoft/gitfox/app/artifact/controller"
+)
+
+// HandMavenUpload returns a http.HandlerFunc that handles the PUT requests of mvn deploy.
+func HandMavenUpload(artCtl *artctl.Controller) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		baseReq, err := artCtl.LoadBaseRequest(ctx, r)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		filePath, err := request.PathParamOrError(r, "*")
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		resWriter, err := artCtl.UploadMaven(ctx, r, baseReq, filePath)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		resWriter.Write(w)
+	}
+}
+
+// HandMavenDownload returns a http.HandlerFunc that download a file of the maven 2 layout.
+func HandMavenDownload(artCtrl *artctl.Controller) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		spaceRef, err := request.GetSpaceRefFromPath(r)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		reqView, err := artCtrl.ParseSpaceView(ctx, spaceRef)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		filePath, err := request.PathParamOrError(r, "*")
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		fr, meta, err := artCtrl.GetMavenReader(ctx, reqView, filePath)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		meta.Write(w)
+		render.Reader(ctx, w, http.StatusOK, fr)
+	}
+}
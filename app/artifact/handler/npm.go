@@ -0,0 +1,209 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/easysoft/gitfox/app/api/render"
+	"github.com/easysoft/gitfox/app/api/request"
+	"github.com/easysoft/gitfox/app/api/usererror"
+	artctl "github.com/easysoft/gitfox/app/artifact/controller"
+)
+
+const (
+	_npmTarballSeparator = "/-/"
+	_npmDistTags         = "dist-tags"
+)
+
+// HandNpmPublish returns a http.HandlerFunc that handles npm publish.
+func HandNpmPublish(artCtl *artctl.Controller) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		baseReq, err := artCtl.LoadBaseRequest(ctx, r)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		pkgPath, err := request.PathParamOrError(r, "*")
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		resWriter, err := artCtl.UploadNpm(ctx, r, baseReq, pkgPath)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		resWriter.Write(w)
+	}
+}
+
+// HandNpmDownload returns a http.HandlerFunc that serves the packument or a tarball of a package.
+func HandNpmDownload(artCtrl *artctl.Controller) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		spaceRef, err := request.GetSpaceRefFromPath(r)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		reqView, err := artCtrl.ParseSpaceView(ctx, spaceRef)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		filePath, err := request.PathParamOrError(r, "*")
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		if pkgPath, filename, found := strings.Cut(filePath, _npmTarballSeparator); found {
+			fr, meta, e := artCtrl.GetNpmTarballReader(ctx, reqView, pkgPath, filename)
+			if e != nil {
+				render.TranslatedUserError(ctx, w, e)
+				return
+			}
+
+			meta.Write(w)
+			render.Reader(ctx, w, http.StatusOK, fr)
+			return
+		}
+
+		doc, err := artCtrl.GetNpmPackument(ctx, spaceRef, reqView, filePath)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		render.JSON(w, http.StatusOK, doc)
+	}
+}
+
+// HandNpmDistTagList returns a http.HandlerFunc that lists the dist-tags of a package.
+func HandNpmDistTagList(artCtrl *artctl.Controller) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		spaceRef, err := request.GetSpaceRefFromPath(r)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		reqView, err := artCtrl.ParseSpaceView(ctx, spaceRef)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		pkgPath, _, err := parseNpmDistTagPath(r)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		tags, err := artCtrl.ListNpmDistTags(ctx, reqView, pkgPath)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		render.JSON(w, http.StatusOK, tags)
+	}
+}
+
+// HandNpmDistTagUpdate returns a http.HandlerFunc that points a dist-tag to a version.
+func HandNpmDistTagUpdate(artCtrl *artctl.Controller) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		baseReq, err := artCtrl.LoadBaseRequest(ctx, r)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		pkgPath, tag, err := parseNpmDistTagPath(r)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		// the request body is the version as a json string
+		var version string
+		if err = json.NewDecoder(r.Body).Decode(&version); err != nil {
+			render.BadRequestf(ctx, w, "Invalid request body: %s.", err)
+			return
+		}
+
+		if err = artCtrl.SetNpmDistTag(ctx, baseReq, pkgPath, tag, version); err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		render.JSON(w, http.StatusCreated, map[string]string{tag: version})
+	}
+}
+
+// HandNpmDistTagDelete returns a http.HandlerFunc that removes a dist-tag.
+func HandNpmDistTagDelete(artCtrl *artctl.Controller) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		baseReq, err := artCtrl.LoadBaseRequest(ctx, r)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		pkgPath, tag, err := parseNpmDistTagPath(r)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		if err = artCtrl.DeleteNpmDistTag(ctx, baseReq, pkgPath, tag); err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		render.DeleteSuccessful(w)
+	}
+}
+
+// parseNpmDistTagPath splits '{package}/dist-tags/{tag}', the tag is optional
+func parseNpmDistTagPath(r *http.Request) (string, string, error) {
+	p, err := request.PathParamOrError(r, "*")
+	if err != nil {
+		return "", "", err
+	}
+
+	frames := strings.Split(strings.Trim(p, "/"), "/")
+	nameFrames := 1
+	if strings.HasPrefix(p, "@") {
+		nameFrames = 2
+	}
+
+	if len(frames) <= nameFrames || len(frames) > nameFrames+2 || frames[nameFrames] != _npmDistTags {
+		return "", "", usererror.BadRequest("invalid dist-tags path")
+	}
+
+	tag := ""
+	if len(frames) == nameFrames+2 {
+		tag = frames[nameFrames+1]
+	}
+	return strings.Join(frames[:nameFrames], "/"), tag, nil
+}
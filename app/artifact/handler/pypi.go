@@ -0,0 +1,175 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/easysoft/gitfox/app/api/render"
+	"github.com/easysoft/gitfox/app/api/request"
+	"github.com/easysoft/gitfox/app/api/usererror"
+	"github.com/easysoft/gitfox/app/artifact/adapter/pypi"
+	artctl "github.com/easysoft/gitfox/app/artifact/controller"
+)
+
+const _pypiProjectParam = "project"
+
+type pypiSimplePage interface {
+	RenderHTML(w io.Writer) error
+}
+
+// HandPypiUpload returns a http.HandlerFunc that handles the legacy upload api used by twine.
+func HandPypiUpload(artCtl *artctl.Controller) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		baseReq, err := artCtl.LoadBaseRequest(ctx, r)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		resWriter, err := artCtl.UploadPypi(ctx, r, baseReq)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		resWriter.Write(w)
+	}
+}
+
+// HandPypiSimpleIndex returns a http.HandlerFunc that lists the projects of the simple api.
+func HandPypiSimpleIndex(artCtrl *artctl.Controller) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		spaceRef, err := request.GetSpaceRefFromPath(r)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		reqView, err := artCtrl.ParseSpaceView(ctx, spaceRef)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		projects, err := artCtrl.GetPypiProjects(ctx, reqView)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		renderPypiSimplePage(ctx, w, r, projects)
+	}
+}
+
+// HandPypiSimpleProject returns a http.HandlerFunc that lists the distribution files of a project.
+func HandPypiSimpleProject(artCtrl *artctl.Controller) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		spaceRef, err := request.GetSpaceRefFromPath(r)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		name, err := request.PathParamOrError(r, _pypiProjectParam)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		// clients should use the normalized name, redirect the others as pypi.org does
+		if normalized := pypi.NormalizeName(name); normalized != name {
+			target := strings.TrimSuffix(strings.TrimSuffix(r.URL.Path, "/"), name) + normalized + "/"
+			http.Redirect(w, r, target, http.StatusMovedPermanently)
+			return
+		}
+
+		reqView, err := artCtrl.ParseSpaceView(ctx, spaceRef)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		project, err := artCtrl.GetPypiProject(ctx, spaceRef, reqView, name)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		renderPypiSimplePage(ctx, w, r, project)
+	}
+}
+
+// HandPypiDownload returns a http.HandlerFunc that downloads a distribution file by '{name}/{version}/{filename}'.
+func HandPypiDownload(artCtrl *artctl.Controller) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		spaceRef, err := request.GetSpaceRefFromPath(r)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		reqView, err := artCtrl.ParseSpaceView(ctx, spaceRef)
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		filePath, err := request.PathParamOrError(r, "*")
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		frames := strings.Split(strings.Trim(filePath, "/"), "/")
+		if len(frames) != 3 {
+			render.TranslatedUserError(ctx, w, usererror.BadRequest("invalid file path"))
+			return
+		}
+
+		fr, meta, err := artCtrl.GetPypiFileReader(ctx, reqView, frames[0], frames[1], frames[2])
+		if err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+
+		meta.Write(w)
+		render.Reader(ctx, w, http.StatusOK, fr)
+	}
+}
+
+// renderPypiSimplePage writes the page in the format negotiated by the Accept header, PEP 691.
+func renderPypiSimplePage(ctx context.Context, w http.ResponseWriter, r *http.Request, page pypiSimplePage) {
+	buf := bytes.NewBuffer(nil)
+	contentType := pypi.NegotiateContentType(r.Header.Get("Accept"))
+	if contentType == pypi.ContentTypeSimpleJSON {
+		if err := json.NewEncoder(buf).Encode(page); err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+	} else {
+		if err := page.RenderHTML(buf); err != nil {
+			render.TranslatedUserError(ctx, w, err)
+			return
+		}
+		contentType += "; charset=utf-8"
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Vary", "Accept")
+	render.Reader(ctx, w, http.StatusOK, buf)
+}
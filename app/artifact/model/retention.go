@@ -0,0 +1,111 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/easysoft/gitfox/types"
+)
+
+var ErrRetentionRuleEmpty = errors.New("one of keep_last, max_age_days or not_pulled_days is required")
+
+// RetentionRule evaluates a retention policy against the versions of a package.
+type RetentionRule struct {
+	policy      *types.ArtifactRetentionPolicy
+	keepPattern *regexp.Regexp
+}
+
+// RetentionResult is a version to be removed and the reason.
+type RetentionResult struct {
+	Version *types.ArtifactVersion
+	Reason  string
+}
+
+func NewRetentionRule(policy *types.ArtifactRetentionPolicy) (*RetentionRule, error) {
+	if policy.KeepLast < 0 || policy.MaxAgeDays < 0 || policy.NotPulledDays < 0 {
+		return nil, errors.New("keep_last, max_age_days and not_pulled_days must not be negative")
+	}
+	if policy.KeepLast == 0 && policy.MaxAgeDays == 0 && policy.NotPulledDays == 0 {
+		return nil, ErrRetentionRuleEmpty
+	}
+
+	rule := &RetentionRule{policy: policy}
+	if policy.KeepPattern != "" {
+		re, err := regexp.Compile(policy.KeepPattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid keep_pattern: %w", err)
+		}
+		rule.keepPattern = re
+	}
+	if _, err := path.Match(policy.ProtectedTags, ""); err != nil {
+		return nil, fmt.Errorf("invalid protected_tags: %w", err)
+	}
+	return rule, nil
+}
+
+// IsKept reports whether the version is kept by the pattern or protected tags regardless of the other rules.
+func (r *RetentionRule) IsKept(version string) bool {
+	if r.keepPattern != nil && r.keepPattern.MatchString(version) {
+		return true
+	}
+	if r.policy.ProtectedTags != "" {
+		if ok, _ := path.Match(r.policy.ProtectedTags, version); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Select returns the versions to be removed, the versions should belong to a package in a view.
+// The most recently created KeepLast versions are kept, the versions kept by patterns are not counted in.
+// A version is removed only if it matches all the enabled rules, the version never pulled
+// is considered pulled when it's created.
+func (r *RetentionRule) Select(versions []*types.ArtifactVersion, now time.Time) []*RetentionResult {
+	sorted := make([]*types.ArtifactVersion, 0, len(versions))
+	for _, v := range versions {
+		if !v.IsDeleted() && !r.IsKept(v.Version) {
+			sorted = append(sorted, v)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Created > sorted[j].Created
+	})
+
+	expireBefore := now.AddDate(0, 0, -r.policy.MaxAgeDays).UnixMilli()
+	pullBefore := now.AddDate(0, 0, -r.policy.NotPulledDays).UnixMilli()
+	result := make([]*RetentionResult, 0)
+	for idx, v := range sorted {
+		reasons := make([]string, 0, 3)
+		if r.policy.KeepLast > 0 {
+			if idx < r.policy.KeepLast {
+				continue
+			}
+			reasons = append(reasons, fmt.Sprintf("not in the last %d versions", r.policy.KeepLast))
+		}
+		if r.policy.MaxAgeDays > 0 {
+			if v.Updated >= expireBefore {
+				continue
+			}
+			reasons = append(reasons, fmt.Sprintf("older than %d days", r.policy.MaxAgeDays))
+		}
+		if r.policy.NotPulledDays > 0 {
+			if max(v.LastPulled, v.Created) >= pullBefore {
+				continue
+			}
+			reasons = append(reasons, fmt.Sprintf("not pulled in %d days", r.policy.NotPulledDays))
+		}
+		result = append(result, &RetentionResult{Version: v, Reason: strings.Join(reasons, ", ")})
+	}
+	return result
+}
@@ -0,0 +1,105 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/easysoft/gitfox/types"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRetentionRule(t *testing.T) {
+	tests := []struct {
+		policy  types.ArtifactRetentionPolicy
+		wantErr bool
+	}{
+		{types.ArtifactRetentionPolicy{}, true},
+		{types.ArtifactRetentionPolicy{KeepLast: -1}, true},
+		{types.ArtifactRetentionPolicy{KeepLast: 1, KeepPattern: "v(1"}, true},
+		{types.ArtifactRetentionPolicy{KeepLast: 1, ProtectedTags: "release-["}, true},
+		{types.ArtifactRetentionPolicy{MaxAgeDays: 30, KeepPattern: `^v\d+$`, ProtectedTags: "release-*"}, false},
+		{types.ArtifactRetentionPolicy{NotPulledDays: -1}, true},
+		{types.ArtifactRetentionPolicy{NotPulledDays: 30}, false},
+	}
+
+	for _, test := range tests {
+		_, err := NewRetentionRule(&test.policy)
+		require.Equal(t, test.wantErr, err != nil, "%+v", test.policy)
+	}
+}
+
+func TestRetentionRuleSelect(t *testing.T) {
+	now := time.Now()
+	daysAgo := func(n int) int64 {
+		return now.AddDate(0, 0, -n).UnixMilli()
+	}
+	versions := []*types.ArtifactVersion{
+		{Version: "1.0.0", Created: daysAgo(50), Updated: daysAgo(50), LastPulled: daysAgo(5)},
+		{Version: "release-1", Created: daysAgo(60), Updated: daysAgo(60)},
+		{Version: "1.1.0", Created: daysAgo(40), Updated: daysAgo(40), LastPulled: daysAgo(35)},
+		{Version: "stable", Created: daysAgo(90), Updated: daysAgo(90), LastPulled: daysAgo(1)},
+		{Version: "1.2.0", Created: daysAgo(10), Updated: daysAgo(10)},
+		{Version: "1.3.0", Created: daysAgo(1), Updated: daysAgo(1)},
+		{Version: "0.9.0", Updated: daysAgo(100), Deleted: daysAgo(1)},
+	}
+
+	tests := []struct {
+		name   string
+		policy types.ArtifactRetentionPolicy
+		expect []string
+	}{
+		{"keep last", types.ArtifactRetentionPolicy{KeepLast: 2},
+			[]string{"1.1.0", "1.0.0", "release-1", "stable"}},
+		{"max age", types.ArtifactRetentionPolicy{MaxAgeDays: 45},
+			[]string{"1.0.0", "release-1", "stable"}},
+		{"keep last and max age", types.ArtifactRetentionPolicy{KeepLast: 1, MaxAgeDays: 30},
+			[]string{"1.1.0", "1.0.0", "release-1", "stable"}},
+		{"protected", types.ArtifactRetentionPolicy{KeepLast: 2, KeepPattern: "^stable$", ProtectedTags: "release-*"},
+			[]string{"1.1.0", "1.0.0"}},
+		{"not pulled", types.ArtifactRetentionPolicy{NotPulledDays: 30},
+			[]string{"1.1.0", "release-1"}},
+		{"max age and not pulled", types.ArtifactRetentionPolicy{MaxAgeDays: 45, NotPulledDays: 30},
+			[]string{"release-1"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rule, err := NewRetentionRule(&test.policy)
+			require.NoError(t, err)
+
+			removed := make([]string, 0)
+			for _, res := range rule.Select(versions, now) {
+				require.NotEmpty(t, res.Reason)
+				removed = append(removed, res.Version.Version)
+			}
+			require.Equal(t, test.expect, removed)
+		})
+	}
+}
+
+func TestRetentionRuleSelectKeepLastByCreated(t *testing.T) {
+	now := time.Now()
+	daysAgo := func(n int) int64 {
+		return now.AddDate(0, 0, -n).UnixMilli()
+	}
+	// the metadata of the old version is updated recently, it should not be counted in the last versions
+	versions := []*types.ArtifactVersion{
+		{Version: "1.0.0", Created: daysAgo(30), Updated: daysAgo(1)},
+		{Version: "1.1.0", Created: daysAgo(20), Updated: daysAgo(20)},
+		{Version: "1.2.0", Created: daysAgo(10), Updated: daysAgo(10)},
+	}
+
+	rule, err := NewRetentionRule(&types.ArtifactRetentionPolicy{KeepLast: 2})
+	require.NoError(t, err)
+
+	removed := rule.Select(versions, now)
+	require.Len(t, removed, 1)
+	require.Equal(t, "1.0.0", removed[0].Version.Version)
+}
@@ -8,7 +8,7 @@ package artifact
 
 import (
 	"context"
-	"errors"
+	"fmt"
 
 	"github.com/easysoft/gitfox/pkg/storage"
 	storagedriver "github.com/easysoft/gitfox/pkg/storage/driver"
@@ -16,16 +16,26 @@ import (
 )
 
 func LoadContentStorage(ctx context.Context, c Config, storageConfig storage.Config) (storage.ContentStorage, error) {
-	var err error
-	var driver storagedriver.StorageDriver
-	if c.Storage.Provider == types.StorageProviderLocal {
-		driver, err = storage.NewDriver(ctx, string(storageConfig.Local.Driver), storageConfig.Local.Parameters)
-		if err != nil {
-			return nil, err
-		}
-	} else {
-		return nil, errors.New("unsupported s3 storage")
+	driver, err := LoadDriver(ctx, c.Storage.Provider, storageConfig)
+	if err != nil {
+		return nil, err
 	}
 
 	return storage.NewCommonContentStore(ctx, driver, storage.WithPrefix(c.Storage.Prefix))
 }
+
+// LoadDriver creates the storage driver of the provider.
+func LoadDriver(ctx context.Context, provider types.StorageProviderType, storageConfig storage.Config,
+) (storagedriver.StorageDriver, error) {
+	var driverConfig storage.DriverConfig
+	switch provider {
+	case types.StorageProviderLocal:
+		driverConfig = storageConfig.Local
+	case types.StorageProviderS3:
+		driverConfig = storageConfig.S3
+	default:
+		return nil, fmt.Errorf("unsupported storage provider '%s'", provider)
+	}
+
+	return storage.NewDriver(ctx, string(driverConfig.Driver), driverConfig.Parameters)
+}
@@ -96,6 +96,10 @@ func (a *JWTAuthenticator) Authenticate(r *http.Request) (*auth.Session, error)
 	case claims.Membership != nil:
 		metadata = a.metadataFromMembershipClaims(claims.Membership)
 	case claims.AccessPermissions != nil:
+		// lfs tokens are handed out over ssh for the lfs transfers and must not be usable with any other api
+		if claims.AccessPermissions.Source == jwt.LFSSource && !request.IsLFSRequest(ctx) {
+			return nil, errors.New("lfs token is only accepted by the git lfs api")
+		}
 		metadata = a.metadataFromAccessPermissions(claims.AccessPermissions)
 	default:
 		return nil, fmt.Errorf("jwt is missing sub-claims")
@@ -33,6 +33,7 @@ type Source string
 
 const (
 	OciSource Source = "oci"
+	LFSSource Source = "lfs"
 )
 
 // Claims defines Harness jwt claims.
@@ -66,7 +67,9 @@ type SubClaimsAccessPermissions struct {
 
 // AccessPermissions stores allowed actions on a resource.
 type AccessPermissions struct {
-	SpaceID     int64             `json:"sid,omitempty"`
+	SpaceID int64 `json:"sid,omitempty"`
+	// RepoID restricts the permissions to the repository of the space if set.
+	RepoID      int64             `json:"rid,omitempty"`
 	Permissions []enum.Permission `json:"p"`
 }
 
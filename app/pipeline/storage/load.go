@@ -8,23 +8,26 @@ package storage
 
 import (
 	"context"
-	"errors"
+	"fmt"
 
 	"github.com/easysoft/gitfox/pkg/storage"
-	storagedriver "github.com/easysoft/gitfox/pkg/storage/driver"
 	"github.com/easysoft/gitfox/types"
 )
 
 func LoadPipelineStorage(ctx context.Context, c *types.Config, storageConfig storage.Config) (PipelineStorage, error) {
-	var err error
-	var driver storagedriver.StorageDriver
-	if c.CI.Storage.Provider == types.StorageProviderLocal {
-		driver, err = storage.NewDriver(ctx, string(storageConfig.Local.Driver), storageConfig.Local.Parameters)
-		if err != nil {
-			return nil, err
-		}
-	} else {
-		return nil, errors.New("unsupported s3 storage")
+	var driverConfig storage.DriverConfig
+	switch c.CI.Storage.Provider {
+	case types.StorageProviderLocal:
+		driverConfig = storageConfig.Local
+	case types.StorageProviderS3:
+		driverConfig = storageConfig.S3
+	default:
+		return nil, fmt.Errorf("unsupported storage provider '%s'", c.CI.Storage.Provider)
+	}
+
+	driver, err := storage.NewDriver(ctx, string(driverConfig.Driver), driverConfig.Parameters)
+	if err != nil {
+		return nil, err
 	}
 
 	s, err := storage.NewCommonContentStore(ctx, driver, storage.WithPrefix(c.CI.Storage.Prefix))
@@ -28,6 +28,7 @@ import (
 	"github.com/easysoft/gitfox/app/api/controller/gitspace"
 	"github.com/easysoft/gitfox/app/api/controller/infraprovider"
 	"github.com/easysoft/gitfox/app/api/controller/keywordsearch"
+	"github.com/easysoft/gitfox/app/api/controller/lfs"
 	"github.com/easysoft/gitfox/app/api/controller/logs"
 	"github.com/easysoft/gitfox/app/api/controller/migrate"
 	"github.com/easysoft/gitfox/app/api/controller/pipeline"
@@ -58,6 +59,7 @@ import (
 	handlergitspace "github.com/easysoft/gitfox/app/api/handler/gitspace"
 	handlerinfraProvider "github.com/easysoft/gitfox/app/api/handler/infraprovider"
 	handlerkeywordsearch "github.com/easysoft/gitfox/app/api/handler/keywordsearch"
+	handlerlfs "github.com/easysoft/gitfox/app/api/handler/lfs"
 	handlerlogs "github.com/easysoft/gitfox/app/api/handler/logs"
 	handlermigrate "github.com/easysoft/gitfox/app/api/handler/migrate"
 	handlerpipeline "github.com/easysoft/gitfox/app/api/handler/pipeline"
@@ -145,6 +147,7 @@ func NewAPIHandler(
 	gitspaceCtrl *gitspace.Controller,
 	aiagentCtrl *aiagent.Controller,
 	capabilitiesCtrl *capabilities.Controller,
+	lfsCtrl *lfs.Controller,
 ) http.Handler {
 	// Use go-chi router for inner routing.
 	r := chi.NewRouter()
@@ -177,8 +180,9 @@ func NewAPIHandler(
 
 			setupRoutesV1WithAuth(r, appCtx, config, repoCtrl, repoSettingsCtrl, executionCtrl, triggerCtrl, logCtrl,
 				pipelineCtrl, connectorCtrl, templateCtrl, pluginCtrl, secretCtrl, spaceCtrl, pullreqCtrl,
-				webhookCtrl, githookCtrl, git, saCtrl, userCtrl, principalCtrl, userGroupCtrl, checkCtrl, uploadCtrl,
-				searchCtrl, runnerCtrl, gitspaceCtrl, infraProviderCtrl, migrateCtrl, aiagentCtrl, capabilitiesCtrl)
+				webhookCtrl, githookCtrl, git, saCtrl, userCtrl, principalCtrl, userGroupCtrl, checkCtrl, sysCtrl,
+				uploadCtrl, searchCtrl, runnerCtrl, gitspaceCtrl, infraProviderCtrl, migrateCtrl, aiagentCtrl,
+				capabilitiesCtrl, lfsCtrl)
 			setupRouteArtifactV1(r, appCtx, artifactCtrl, spaceCtrl)
 		})
 	})
@@ -224,6 +228,7 @@ func setupRoutesV1WithAuth(r chi.Router,
 	principalCtrl principal.Controller,
 	userGroupCtrl *usergroup.Controller,
 	checkCtrl *check.Controller,
+	sysCtrl *system.Controller,
 	uploadCtrl *upload.Controller,
 	searchCtrl *keywordsearch.Controller,
 	runnerCtrl *runner.Controller,
@@ -232,11 +237,12 @@ func setupRoutesV1WithAuth(r chi.Router,
 	migrateCtrl *migrate.Controller,
 	aiagentCtrl *aiagent.Controller,
 	capabilitiesCtrl *capabilities.Controller,
+	lfsCtrl *lfs.Controller,
 ) {
 	setupAccountWithAuth(r, userCtrl, config)
 	setupSpaces(r, appCtx, spaceCtrl, userGroupCtrl, webhookCtrl)
 	setupRepos(r, repoCtrl, repoSettingsCtrl, pipelineCtrl, executionCtrl, triggerCtrl,
-		logCtrl, pullreqCtrl, webhookCtrl, checkCtrl, uploadCtrl)
+		logCtrl, pullreqCtrl, webhookCtrl, checkCtrl, uploadCtrl, lfsCtrl)
 	setupConnectors(r, connectorCtrl)
 	setupTemplates(r, templateCtrl)
 	setupSecrets(r, secretCtrl)
@@ -245,7 +251,7 @@ func setupRoutesV1WithAuth(r chi.Router,
 	setupServiceAccounts(r, saCtrl)
 	setupPrincipals(r, principalCtrl)
 	setupInternal(r, githookCtrl, git)
-	setupAdmin(r, userCtrl)
+	setupAdmin(r, userCtrl, sysCtrl)
 	setupPlugins(r, pluginCtrl)
 	setupKeywordSearch(r, searchCtrl)
 	setupRunner(r, runnerCtrl)
@@ -267,15 +273,78 @@ func setupRouteArtifactV1(
 			r.Post("/upload", handlerartifact2.HandRawUpload(artCtrl))
 			r.Get("/versions", artifact.HandListVersions(artCtrl, types.ArtifactRawFormat))
 			r.Get("/assets", artifact.HandListAssets(artCtrl, types.ArtifactRawFormat))
+			r.Get("/sbom", artifact.HandListSbomComponents(artCtrl, types.ArtifactRawFormat))
+			r.Put("/sbom", artifact.HandUploadSbom(artCtrl, types.ArtifactRawFormat))
 		})
 		r.Route("/helm", func(r chi.Router) {
 			r.Post("/upload", handlerartifact2.HandHelmUpload(artCtrl))
 			r.Get("/versions", artifact.HandListVersions(artCtrl, types.ArtifactHelmFormat))
 			r.Get("/assets", artifact.HandListAssets(artCtrl, types.ArtifactHelmFormat))
+			r.Get("/sbom", artifact.HandListSbomComponents(artCtrl, types.ArtifactHelmFormat))
+			r.Put("/sbom", artifact.HandUploadSbom(artCtrl, types.ArtifactHelmFormat))
+		})
+		r.Route("/maven", func(r chi.Router) {
+			r.Get("/versions", artifact.HandListVersions(artCtrl, types.ArtifactMavenFormat))
+			r.Get("/assets", artifact.HandListAssets(artCtrl, types.ArtifactMavenFormat))
+			r.Get("/sbom", artifact.HandListSbomComponents(artCtrl, types.ArtifactMavenFormat))
+			r.Put("/sbom", artifact.HandUploadSbom(artCtrl, types.ArtifactMavenFormat))
+		})
+		r.Route("/npm", func(r chi.Router) {
+			r.Get("/versions", artifact.HandListVersions(artCtrl, types.ArtifactNpmFormat))
+			r.Get("/assets", artifact.HandListAssets(artCtrl, types.ArtifactNpmFormat))
+			r.Get("/sbom", artifact.HandListSbomComponents(artCtrl, types.ArtifactNpmFormat))
+			r.Put("/sbom", artifact.HandUploadSbom(artCtrl, types.ArtifactNpmFormat))
+		})
+		r.Route("/pypi", func(r chi.Router) {
+			r.Post("/upload", handlerartifact2.HandPypiUpload(artCtrl))
+			r.Get("/versions", artifact.HandListVersions(artCtrl, types.ArtifactPypiFormat))
+			r.Get("/assets", artifact.HandListAssets(artCtrl, types.ArtifactPypiFormat))
+			r.Get("/sbom", artifact.HandListSbomComponents(artCtrl, types.ArtifactPypiFormat))
+			r.Put("/sbom", artifact.HandUploadSbom(artCtrl, types.ArtifactPypiFormat))
+		})
+		r.Route("/debian", func(r chi.Router) {
+			r.Post("/upload", artifact.HandDebianUpload(artCtrl))
+			r.Get("/versions", artifact.HandListVersions(artCtrl, types.ArtifactDebianFormat))
+			r.Get("/assets", artifact.HandListAssets(artCtrl, types.ArtifactDebianFormat))
+			r.Get("/sbom", artifact.HandListSbomComponents(artCtrl, types.ArtifactDebianFormat))
+			r.Put("/sbom", artifact.HandUploadSbom(artCtrl, types.ArtifactDebianFormat))
+			r.Get("/key", artifact.HandGetDebianKey(artCtrl))
+			r.Put("/key", artifact.HandImportDebianKey(artCtrl))
+		})
+		r.Get("/sbom/components", artifact.HandSearchSbomComponents(artCtrl))
+		r.Route("/quota", func(r chi.Router) {
+			r.Get("/", artifact.HandGetQuota(artCtrl))
+			r.Group(func(r chi.Router) {
+				r.Use(middlewareprincipal.RestrictToAdmin())
+				r.Put("/", artifact.HandSaveQuota(artCtrl))
+				r.Delete("/", artifact.HandDeleteQuota(artCtrl))
+			})
+		})
+		r.Route("/views", func(r chi.Router) {
+			r.Get("/", artifact.HandListViews(artCtrl))
+			r.Post("/", artifact.HandCreateView(artCtrl))
+			r.Patch(fmt.Sprintf("/{%s}", request.PathParamArtifactView), artifact.HandUpdateView(artCtrl))
+		})
+		r.Post("/promote", artifact.HandPromoteVersion(artCtrl))
+		r.Get("/promotions", artifact.HandListPromotions(artCtrl))
+		r.Route("/retention", func(r chi.Router) {
+			r.Get("/", artifact.HandListRetentionPolicies(artCtrl))
+			r.Route(fmt.Sprintf("/{%s}", request.PathParamArtifactFormat), func(r chi.Router) {
+				r.Put("/", artifact.HandSaveRetentionPolicy(artCtrl))
+				r.Delete("/", artifact.HandDeleteRetentionPolicy(artCtrl))
+				r.Get("/dry-run", artifact.HandDryRunRetentionPolicy(artCtrl))
+			})
 		})
 		r.Route("/container", func(r chi.Router) {
 			r.Get("/versions", artifact.HandListVersions(artCtrl, types.ArtifactContainerFormat))
 			r.Get("/assets", artifact.HandListContainerImages(artCtrl))
+			r.Get("/sbom", artifact.HandListSbomComponents(artCtrl, types.ArtifactContainerFormat))
+			r.Put("/sbom", artifact.HandUploadSbom(artCtrl, types.ArtifactContainerFormat))
+			r.Route("/proxies", func(r chi.Router) {
+				r.Get("/", artifact.HandListContainerProxies(artCtrl))
+				r.Post("/", artifact.HandCreateContainerProxy(artCtrl))
+				r.Patch(fmt.Sprintf("/{%s}", request.PathParamProxyIdentifier), artifact.HandUpdateContainerProxy(artCtrl))
+			})
 		})
 	})
 	r.Post("/artifacts/nodeInfo", artifact.HandNodeInfos(artCtrl))
@@ -284,6 +353,11 @@ func setupRouteArtifactV1(
 	r.Get("/artifacts/garbage/soft-remove", artifact.HandGarbageCollectSoftRemove(artCtrl))
 	r.Get("/artifacts/capacity/container", artifact.HandContainerCapacityStatistic(artCtrl))
 	r.Get("/artifacts/capacity/all", artifact.HandCapacityStatistic(artCtrl))
+	r.Route("/artifacts/blobs/migration", func(r chi.Router) {
+		r.Use(middlewareprincipal.RestrictToAdmin())
+		r.Get("/", artifact.HandBlobMigrationProgress(artCtrl))
+		r.Post("/", artifact.HandMigrateBlobs(artCtrl))
+	})
 }
 
 // nolint: revive // it's the app context, it shouldn't be the first argument
@@ -412,6 +486,7 @@ func setupRepos(r chi.Router,
 	webhookCtrl *webhook.Controller,
 	checkCtrl *check.Controller,
 	uploadCtrl *upload.Controller,
+	lfsCtrl *lfs.Controller,
 ) {
 	r.Route("/repos", func(r chi.Router) {
 		// Create takes path and parentId via body, not uri
@@ -439,12 +514,41 @@ func setupRepos(r chi.Router,
 			})
 
 			r.Get("/summary", handlerrepo.HandleSummary(repoCtrl))
+			r.Get("/lfs/usage", handlerlfs.HandleUsage(lfsCtrl))
 
 			r.Post("/move", handlerrepo.HandleMove(repoCtrl))
 			r.Get("/service-accounts", handlerrepo.HandleListServiceAccounts(repoCtrl))
 
 			r.Get("/import-progress", handlerrepo.HandleImportProgress(repoCtrl))
 
+			r.Route("/mirror", func(r chi.Router) {
+				r.Get("/", handlerrepo.HandleMirrorStatus(repoCtrl))
+				r.Post("/sync", handlerrepo.HandleSyncMirror(repoCtrl))
+			})
+
+			r.Route("/push-mirrors", func(r chi.Router) {
+				r.Get("/", handlerrepo.HandlePushMirrorList(repoCtrl))
+				r.Post("/", handlerrepo.HandlePushMirrorCreate(repoCtrl))
+				r.Route(fmt.Sprintf("/{%s}", request.PathParamPushMirrorID), func(r chi.Router) {
+					r.Get("/", handlerrepo.HandlePushMirrorFind(repoCtrl))
+					r.Patch("/", handlerrepo.HandlePushMirrorUpdate(repoCtrl))
+					r.Delete("/", handlerrepo.HandlePushMirrorDelete(repoCtrl))
+					r.Post("/sync", handlerrepo.HandlePushMirrorSync(repoCtrl))
+				})
+			})
+
+			r.Route("/housekeeping", func(r chi.Router) {
+				r.Use(middlewareprincipal.RestrictToAdmin())
+				r.Get("/", handlerrepo.HandleHousekeeping(repoCtrl))
+				r.Post("/", handlerrepo.HandleOptimize(repoCtrl))
+			})
+
+			r.Route("/fork", func(r chi.Router) {
+				r.Post("/", handlerrepo.HandleFork(repoCtrl))
+				r.Post("/sync", handlerrepo.HandleSyncFork(repoCtrl))
+			})
+			r.Get("/forks", handlerrepo.HandleListForks(repoCtrl))
+
 			r.Post("/default-branch", handlerrepo.HandleUpdateDefaultBranch(repoCtrl))
 
 			// content operations
@@ -473,6 +577,8 @@ func setupRepos(r chi.Router,
 
 				r.Post("/calculate-divergence", handlerrepo.HandleCalculateCommitDivergence(repoCtrl))
 				r.Post("/", handlerrepo.HandleCommitFiles(repoCtrl))
+				r.Post("/cherry-pick", handlerrepo.HandleCherryPick(repoCtrl))
+				r.Post("/revert", handlerrepo.HandleRevert(repoCtrl))
 
 				// per commit operations
 				r.Route(fmt.Sprintf("/{%s}", request.PathParamCommitSHA), func(r chi.Router) {
@@ -771,6 +877,7 @@ func SetupPullReq(r chi.Router, pullreqCtrl *pullreq.Controller) {
 				r.Post("/", handlerpullreq.HandleReviewSubmit(pullreqCtrl))
 			})
 			r.Post("/merge", handlerpullreq.HandleMerge(pullreqCtrl))
+			r.Post("/revert", handlerpullreq.HandleRevert(pullreqCtrl))
 			r.Get("/commits", handlerpullreq.HandleCommits(pullreqCtrl))
 			r.Get("/metadata", handlerpullreq.HandleMetadata(pullreqCtrl))
 			r.Route("/branch", func(r chi.Router) {
@@ -962,7 +1069,7 @@ func setupInfraProviders(r chi.Router, infraProviderCtrl *infraprovider.Controll
 	})
 }
 
-func setupAdmin(r chi.Router, userCtrl *user.Controller) {
+func setupAdmin(r chi.Router, userCtrl *user.Controller, sysCtrl *system.Controller) {
 	r.Route("/admin", func(r chi.Router) {
 		r.Use(middlewareprincipal.RestrictToAdmin())
 		r.Route("/users", func(r chi.Router) {
@@ -976,6 +1083,11 @@ func setupAdmin(r chi.Router, userCtrl *user.Controller) {
 				r.Patch("/admin", handleruser.HandleUpdateAdmin(userCtrl))
 			})
 		})
+		r.Route("/signing-key", func(r chi.Router) {
+			r.Get("/", handlersystem.HandleFindSigningKey(sysCtrl))
+			r.Put("/", handlersystem.HandleSaveSigningKey(sysCtrl))
+			r.Delete("/", handlersystem.HandleDeleteSigningKey(sysCtrl))
+		})
 	})
 }
 
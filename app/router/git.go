@@ -17,8 +17,11 @@ package router
 import (
 	"fmt"
 	"net/http"
+	"strings"
 
+	"github.com/easysoft/gitfox/app/api/controller/lfs"
 	"github.com/easysoft/gitfox/app/api/controller/repo"
+	handlerlfs "github.com/easysoft/gitfox/app/api/handler/lfs"
 	handlerrepo "github.com/easysoft/gitfox/app/api/handler/repo"
 	middlewareauthn "github.com/easysoft/gitfox/app/api/middleware/authn"
 	middlewareauthz "github.com/easysoft/gitfox/app/api/middleware/authz"
@@ -43,6 +46,7 @@ func NewGitHandler(
 	urlProvider url.Provider,
 	authenticator authn.Authenticator,
 	repoCtrl *repo.Controller,
+	lfsCtrl *lfs.Controller,
 ) http.Handler {
 	// maxRepoDepth depends on config
 	maxRepoDepth := check.MaxRepoPathDepth
@@ -67,6 +71,7 @@ func NewGitHandler(
 	// for now always attempt auth - enforced per operation.
 	// use Attempts instead for multi authenticators support
 	//r.Use(middlewareauthn.Attempt(authenticator))
+	r.Use(markLFSRequest)
 	r.Use(middlewareauthn.Attempts(authenticator, authn.Get(authn.AuthKindBasic)))
 
 	r.Route(fmt.Sprintf("/{%s}", request.PathParamRepoRef), func(r chi.Router) {
@@ -88,6 +93,20 @@ func NewGitHandler(
 				enum.GitServiceTypeReceivePack, repoCtrl, urlProvider))
 			r.Get("/info/refs", handlerrepo.HandleGitInfoRefs(repoCtrl, urlProvider))
 
+			// git lfs api
+			r.Route("/info/lfs", func(r chi.Router) {
+				r.Post("/objects/batch", handlerlfs.HandleBatch(lfsCtrl, urlProvider))
+				r.Put(fmt.Sprintf("/objects/{%s}", request.PathParamLFSObjectID),
+					handlerlfs.HandleUpload(lfsCtrl, urlProvider))
+				r.Get(fmt.Sprintf("/objects/{%s}", request.PathParamLFSObjectID),
+					handlerlfs.HandleDownload(lfsCtrl, urlProvider))
+				r.Post("/locks", handlerlfs.HandleCreateLock(lfsCtrl, urlProvider))
+				r.Get("/locks", handlerlfs.HandleListLocks(lfsCtrl, urlProvider))
+				r.Post("/locks/verify", handlerlfs.HandleVerifyLocks(lfsCtrl, urlProvider))
+				r.Post(fmt.Sprintf("/locks/{%s}/unlock", request.PathParamLFSLockID),
+					handlerlfs.HandleUnlock(lfsCtrl, urlProvider))
+			})
+
 			// dumb protocol
 			r.Get("/HEAD", stubGitHandler())
 			r.Get("/objects/info/alternates", stubGitHandler())
@@ -110,3 +129,21 @@ func stubGitHandler() http.HandlerFunc {
 		w.WriteHeader(http.StatusBadGateway)
 	}
 }
+
+// markLFSRequest marks the requests of the git lfs api, the lfs tokens are rejected by any other api.
+func markLFSRequest(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		urlPath := r.URL.Path
+		if r.URL.RawPath != "" {
+			urlPath = r.URL.RawPath
+		}
+
+		// the repo ref is encoded into the first path segment by the git path encoder
+		_, subPath, _ := strings.Cut(strings.TrimPrefix(urlPath, "/"), "/")
+		if strings.HasPrefix(subPath, "info/lfs/") {
+			r = r.WithContext(request.WithLFSRequest(r.Context()))
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -97,6 +97,11 @@ func NewArtifactHandler(
 		r.Route(fmt.Sprintf("/{%s}", request.PathParamSpaceRef), func(r chi.Router) {
 			setupArtifactHelm(r, appCtx, artStore, artCtrl)
 			setupArtifactRaw(r, appCtx, artStore, artCtrl)
+			setupArtifactMaven(r, appCtx, artStore, artCtrl)
+			setupArtifactNpm(r, appCtx, artStore, artCtrl)
+			setupArtifactPypi(r, appCtx, artStore, artCtrl)
+			setupArtifactGo(r, appCtx, artStore, artCtrl)
+			setupArtifactDebian(r, appCtx, artStore, artCtrl)
 		})
 	})
 
@@ -104,6 +109,7 @@ func NewArtifactHandler(
 		// apiBase is default authorize endpoint
 		r.With(middlewareauthn.RequireContainerAccess(urlProvider)).Get("/", handler.HandleAPIBase())
 		r.Get("/token", handler.HandleToken())
+		r.With(middlewareauthn.RequireContainerAccess(urlProvider)).Get("/_catalog", handler.HandleCatalog(artCtrl))
 		r.Route("/{space}", func(r chi.Router) {
 			// write operations require a login session
 			r.With(middlewareauthn.RequireContainerAccess(urlProvider)).Route("/", func(r chi.Router) {
@@ -127,6 +133,7 @@ func NewArtifactHandler(
 			r.Get("/{name}/manifests/{reference}", handler.HandleManifestGet(artCtrl))
 
 			r.Get("/{name}/tags/list", handler.HandleListTag(artCtrl))
+			r.Get("/{name}/referrers/{digest}", handler.HandleReferrers(artCtrl))
 		})
 	})
 
@@ -138,6 +145,9 @@ func NewArtifactHandler(
 func setupArtifactHelm(r chi.Router, appCtx context.Context, artStore store.ArtifactStore, artCtrl *artctl.Controller) {
 	r.Route("/helm", func(r chi.Router) {
 		r.Post("/api/charts", handler.HandHelmUpload(artCtrl))
+		r.Delete("/api/charts/{name}/{version}", handler.HandHelmDelete(artCtrl))
+		r.Put("/api/charts/{name}/{version}/yank", handler.HandHelmYank(artCtrl, true))
+		r.Delete("/api/charts/{name}/{version}/yank", handler.HandHelmYank(artCtrl, false))
 		r.Get("/*", handler.HandHelmDownload(artStore, artCtrl))
 	})
 }
@@ -148,3 +158,50 @@ func setupArtifactRaw(r chi.Router, appCtx context.Context, artStore store.Artif
 		r.Get("/*", handler.HandRawDownload(artStore, artCtrl))
 	})
 }
+
+func setupArtifactMaven(r chi.Router, appCtx context.Context, artStore store.ArtifactStore, artCtrl *artctl.Controller) {
+	r.Route("/maven", func(r chi.Router) {
+		r.Put("/*", handler.HandMavenUpload(artCtrl))
+		r.Head("/*", handler.HandMavenDownload(artCtrl))
+		r.Get("/*", handler.HandMavenDownload(artCtrl))
+	})
+}
+
+func setupArtifactNpm(r chi.Router, appCtx context.Context, artStore store.ArtifactStore, artCtrl *artctl.Controller) {
+	r.Route("/npm", func(r chi.Router) {
+		r.Get("/-/package/*", handler.HandNpmDistTagList(artCtrl))
+		r.Put("/-/package/*", handler.HandNpmDistTagUpdate(artCtrl))
+		r.Delete("/-/package/*", handler.HandNpmDistTagDelete(artCtrl))
+
+		r.Put("/*", handler.HandNpmPublish(artCtrl))
+		r.Get("/*", handler.HandNpmDownload(artCtrl))
+	})
+}
+
+func setupArtifactPypi(r chi.Router, appCtx context.Context, artStore store.ArtifactStore, artCtrl *artctl.Controller) {
+	r.Route("/pypi", func(r chi.Router) {
+		r.Post("/", handler.HandPypiUpload(artCtrl))
+
+		r.Get("/simple", handler.HandPypiSimpleIndex(artCtrl))
+		r.Get("/simple/", handler.HandPypiSimpleIndex(artCtrl))
+		r.Get("/simple/{project}", handler.HandPypiSimpleProject(artCtrl))
+		r.Get("/simple/{project}/", handler.HandPypiSimpleProject(artCtrl))
+
+		r.Get("/files/*", handler.HandPypiDownload(artCtrl))
+	})
+}
+
+func setupArtifactGo(r chi.Router, appCtx context.Context, artStore store.ArtifactStore, artCtrl *artctl.Controller) {
+	r.Route("/go", func(r chi.Router) {
+		r.Get("/*", handler.HandGoProxy(artCtrl))
+	})
+}
+
+func setupArtifactDebian(r chi.Router, appCtx context.Context, artStore store.ArtifactStore, artCtrl *artctl.Controller) {
+	r.Route("/debian", func(r chi.Router) {
+		r.Get("/repository.key", handler.HandDebianKey(artCtrl))
+		r.Put("/pool/{distribution}/{component}/upload", handler.HandDebianUpload(artCtrl))
+		r.Get("/pool/{distribution}/{component}/{filename}", handler.HandDebianPoolDownload(artCtrl))
+		r.Get("/dists/*", handler.HandDebianIndex(artCtrl))
+	})
+}
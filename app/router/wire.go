@@ -27,6 +27,7 @@ import (
 	"github.com/easysoft/gitfox/app/api/controller/gitspace"
 	"github.com/easysoft/gitfox/app/api/controller/infraprovider"
 	"github.com/easysoft/gitfox/app/api/controller/keywordsearch"
+	"github.com/easysoft/gitfox/app/api/controller/lfs"
 	"github.com/easysoft/gitfox/app/api/controller/logs"
 	"github.com/easysoft/gitfox/app/api/controller/migrate"
 	"github.com/easysoft/gitfox/app/api/controller/pipeline"
@@ -113,6 +114,7 @@ func ProvideRouter(
 	migrateCtrl *migrate.Controller,
 	aiagentCtrl *aiagent.Controller,
 	capabilitiesCtrl *capabilities.Controller,
+	lfsCtrl *lfs.Controller,
 	urlProvider url.Provider,
 	openapi openapi.Service,
 	artStore store.ArtifactStore,
@@ -127,6 +129,7 @@ func ProvideRouter(
 		urlProvider,
 		authenticator,
 		repoCtrl,
+		lfsCtrl,
 	)
 	routers[0] = NewGitRouter(gitHandler, gitRoutingHost)
 
@@ -136,7 +139,7 @@ func ProvideRouter(
 		secretCtrl, triggerCtrl, connectorCtrl, templateCtrl, pluginCtrl, pullreqCtrl, webhookCtrl,
 		githookCtrl, git, saCtrl, userCtrl, principalCtrl, userGroupCtrl, checkCtrl, sysCtrl, blobCtrl, searchCtrl,
 		artifactCtrl, runnerCtrl,
-		infraProviderCtrl, migrateCtrl, gitspaceCtrl, aiagentCtrl, capabilitiesCtrl)
+		infraProviderCtrl, migrateCtrl, gitspaceCtrl, aiagentCtrl, capabilitiesCtrl, lfsCtrl)
 	routers[1] = NewAPIRouter(apiHandler)
 
 	artifactHandler := NewArtifactHandler(appCtx, urlProvider, config, authenticator, artifactCtrl, artStore, repoStore, fileStore)
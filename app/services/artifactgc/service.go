@@ -99,12 +99,45 @@ func (s *Service) garbageCollect(ctx context.Context, kind Kind, assets []*types
 			if e := s.artStore.Assets().DeleteById(ctx, asset.AssetId); e != nil {
 				return e
 			}
+			// the blob is shared by the assets of the promoted versions
+			refs, e := s.artStore.Assets().CountByBlob(ctx, asset.BlobId, true)
+			if e != nil {
+				return e
+			}
+			if refs > 0 {
+				gcData.Count += 1
+				logger.Info().Msgf("remove asset %d, blob %d is still referred", asset.AssetId, asset.BlobId)
+				return nil
+			}
 			if e := s.artStore.Blobs().DeleteById(ctx, asset.BlobId); e != nil {
 				return e
 			}
 			if e := s.fileStore.Delete(ctx, adapter.BlobPath(asset.BlobRef)); e != nil {
 				return e
 			}
+			if kind == KindContainer {
+				// the collected blob is released from the usage of the spaces it was counted in
+				spaces, e := s.artStore.Usages().ListBlobSpaces(ctx, asset.Path)
+				if e != nil {
+					return e
+				}
+				for _, spaceId := range spaces {
+					if e = s.artStore.Usages().RemoveBlob(ctx, spaceId, asset.Path); e != nil {
+						return e
+					}
+				}
+
+				// the collected manifest is no longer referred by any repository
+				referrers, e := s.artStore.Referrers().ListByDigest(ctx, asset.Path)
+				if e != nil {
+					return e
+				}
+				for _, r := range referrers {
+					if e = s.artStore.Referrers().DeleteByDigest(ctx, r.ViewID, r.Repository, r.Digest); e != nil {
+						return e
+					}
+				}
+			}
 			gcData.Count += 1
 			gcData.Size += asset.Size
 			logger.Info().Msgf("remove asset %d, blob %d, file %s", asset.AssetId, asset.BlobId, adapter.BlobPath(asset.BlobRef))
@@ -0,0 +1,97 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package artifactstats
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/easysoft/gitfox/app/store"
+
+	"github.com/rs/zerolog/log"
+)
+
+const defaultFlushInterval = 30 * time.Second
+
+type counter struct {
+	count      int64
+	lastPulled int64
+}
+
+// Recorder aggregates the downloads of the packages and versions in memory,
+// the counters are flushed to the database periodically instead of writing per request.
+type Recorder struct {
+	artStore store.ArtifactStore
+
+	mu       sync.Mutex
+	packages map[int64]*counter
+	versions map[int64]*counter
+}
+
+func NewRecorder(artStore store.ArtifactStore) *Recorder {
+	return &Recorder{
+		artStore: artStore,
+		packages: make(map[int64]*counter),
+		versions: make(map[int64]*counter),
+	}
+}
+
+// Record counts a download of the version, a container tag is also a version.
+func (r *Recorder) Record(packageId, versionId int64) {
+	now := time.Now().UnixMilli()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	addDownload(r.packages, packageId, now)
+	addDownload(r.versions, versionId, now)
+}
+
+func addDownload(counters map[int64]*counter, id, now int64) {
+	c, ok := counters[id]
+	if !ok {
+		c = &counter{}
+		counters[id] = c
+	}
+	c.count++
+	c.lastPulled = max(c.lastPulled, now)
+}
+
+// Flush writes the aggregated downloads to the database, the failed counters are dropped with a warning.
+func (r *Recorder) Flush(ctx context.Context) {
+	r.mu.Lock()
+	packages, versions := r.packages, r.versions
+	r.packages = make(map[int64]*counter)
+	r.versions = make(map[int64]*counter)
+	r.mu.Unlock()
+
+	for id, c := range packages {
+		if err := r.artStore.Packages().AddDownloads(ctx, id, c.count, c.lastPulled); err != nil {
+			log.Ctx(ctx).Warn().Err(err).Msgf("failed to flush %d downloads of package %d", c.count, id)
+		}
+	}
+	for id, c := range versions {
+		if err := r.artStore.Versions().AddDownloads(ctx, id, c.count, c.lastPulled); err != nil {
+			log.Ctx(ctx).Warn().Err(err).Msgf("failed to flush %d downloads of version %d", c.count, id)
+		}
+	}
+}
+
+// Run flushes the downloads every interval until the context is done, the pending downloads are flushed at last.
+func (r *Recorder) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			r.Flush(context.WithoutCancel(ctx))
+			return
+		case <-ticker.C:
+			r.Flush(ctx)
+		}
+	}
+}
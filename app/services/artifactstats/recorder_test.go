@@ -0,0 +1,74 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package artifactstats
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/easysoft/gitfox/app/store/database/artifacts"
+	"github.com/easysoft/gitfox/store/database/dbtest"
+	"github.com/easysoft/gitfox/types"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorder(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	tables := []any{new(types.ArtifactPackage), new(types.ArtifactVersion)}
+	_, gdb := dbtest.New(ctx, t, "artifacts_stats", tables...)
+	artStore := artifacts.NewStore(gdb)
+
+	pkg := &types.ArtifactPackage{OwnerID: 1, Name: "demo", Format: types.ArtifactHelmFormat}
+	require.NoError(t, artStore.Packages().Create(ctx, pkg))
+	versions := make([]*types.ArtifactVersion, 0, 2)
+	for _, v := range []string{"1.0.0", "1.1.0"} {
+		ver := &types.ArtifactVersion{PackageID: pkg.ID, ViewID: 1, Version: v}
+		require.NoError(t, artStore.Versions().Create(ctx, ver))
+		versions = append(versions, ver)
+	}
+
+	r := NewRecorder(artStore)
+	var wg sync.WaitGroup
+	// 7 downloads of 1.0.0 and 3 downloads of 1.1.0
+	for i := 0; i < 10; i++ {
+		ver := versions[0]
+		if i%3 == 2 {
+			ver = versions[1]
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.Record(ver.PackageID, ver.ID)
+		}()
+	}
+	wg.Wait()
+
+	// nothing is written until the flush
+	obj, err := artStore.Packages().GetByID(ctx, pkg.ID)
+	require.NoError(t, err)
+	require.Zero(t, obj.Downloads)
+
+	r.Flush(ctx)
+	r.Flush(ctx)
+
+	obj, err = artStore.Packages().GetByID(ctx, pkg.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(10), obj.Downloads)
+	require.NotZero(t, obj.LastPulled)
+
+	for ver, expected := range map[int64]int64{versions[0].ID: 7, versions[1].ID: 3} {
+		dbVer, err := artStore.Versions().GetByID(ctx, ver)
+		require.NoError(t, err)
+		require.Equal(t, expected, dbVer.Downloads)
+	}
+}
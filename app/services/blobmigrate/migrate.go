@@ -0,0 +1,201 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package blobmigrate
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	storagedriver "github.com/easysoft/gitfox/pkg/storage/driver"
+	"github.com/easysoft/gitfox/types"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// progressInterval limits how often the progress is saved to the job
+	progressInterval = 5 * time.Second
+
+	// maxFailedPaths limits the failed paths kept in the report, the failures beyond it are counted only
+	maxFailedPaths = 100
+)
+
+// progressFunc is called with the processed percent and the report during the migration.
+type progressFunc func(progress int, report *types.ArtifactBlobMigrationReport) error
+
+// migrator copies the files under root from the source driver to the target driver,
+// the files after report.LastPath are copied only, so an interrupted migration resumes from it.
+// The failed files before report.LastPath are listed in report.Failed and copied again.
+type migrator struct {
+	source   storagedriver.StorageDriver
+	target   storagedriver.StorageDriver
+	report   *types.ArtifactBlobMigrationReport
+	progress progressFunc
+
+	processedSize int64
+	lastReport    time.Time
+}
+
+func (m *migrator) run(ctx context.Context, root string) error {
+	checkpoint := m.report.LastPath
+
+	// count the files first to report the progress
+	err := m.walk(ctx, root, checkpoint, func(fi storagedriver.FileInfo) error {
+		m.report.TotalFiles++
+		m.report.TotalSize += fi.Size()
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to scan %s: %w", root, err)
+	}
+	log.Ctx(ctx).Info().Msgf("migrate %d blobs (%d bytes) under %s from %s to %s",
+		m.report.TotalFiles, m.report.TotalSize, root, m.report.Source, m.report.Target)
+
+	if err = m.retryFailed(ctx); err != nil {
+		return err
+	}
+
+	err = m.walk(ctx, root, checkpoint, func(fi storagedriver.FileInfo) error {
+		if e := m.copyFile(ctx, fi); e != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			log.Ctx(ctx).Warn().Err(e).Msgf("migrate blob %s failed", fi.Path())
+			m.report.FailedCount++
+			if len(m.report.Failed) < maxFailedPaths {
+				m.report.Failed = append(m.report.Failed, fi.Path())
+			}
+		}
+
+		m.report.LastPath = fi.Path()
+		m.processedSize += fi.Size()
+		return m.reportProgress(false)
+	})
+	if err != nil {
+		return err
+	}
+	return m.reportProgress(true)
+}
+
+// retryFailed copies the failed files of the interrupted migration again, a file stays
+// in the failed list until it is copied, so the report is consistent at any time.
+func (m *migrator) retryFailed(ctx context.Context) error {
+	for _, path := range slices.Clone(m.report.Failed) {
+		fi, err := m.source.Stat(ctx, path)
+		if err == nil {
+			err = m.copyFile(ctx, fi)
+		} else if errors.As(err, &storagedriver.PathNotFoundError{}) {
+			// the file is removed since
+			err = nil
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			log.Ctx(ctx).Warn().Err(err).Msgf("migrate blob %s failed again", path)
+			continue
+		}
+
+		m.report.Failed = slices.DeleteFunc(m.report.Failed, func(p string) bool { return p == path })
+		m.report.FailedCount--
+	}
+	return nil
+}
+
+// walk calls fn on the files under root after the startAfter path, a missing root has nothing to walk.
+func (m *migrator) walk(ctx context.Context, root, startAfter string, fn storagedriver.WalkFn) error {
+	err := m.source.Walk(ctx, root, func(fi storagedriver.FileInfo) error {
+		if fi.IsDir() {
+			return nil
+		}
+		return fn(fi)
+	}, storagedriver.WithStartAfterHint(startAfter))
+
+	if errors.As(err, &storagedriver.PathNotFoundError{}) {
+		return nil
+	}
+	return err
+}
+
+// copyFile copies the file to the target and verifies the copy by the sha256 checksum,
+// the file existing in the target with the same size is copied before and skipped.
+func (m *migrator) copyFile(ctx context.Context, fi storagedriver.FileInfo) error {
+	path := fi.Path()
+	if dst, err := m.target.Stat(ctx, path); err == nil && !dst.IsDir() && dst.Size() == fi.Size() {
+		m.report.Skipped++
+		return nil
+	}
+
+	reader, err := m.source.Reader(ctx, path, 0)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	writer, err := m.target.Writer(ctx, path, false)
+	if err != nil {
+		return err
+	}
+	defer writer.Close()
+
+	hash := sha256.New()
+	n, err := io.Copy(writer, io.TeeReader(reader, hash))
+	if err == nil && n != fi.Size() {
+		err = fmt.Errorf("copied %d bytes, expected %d", n, fi.Size())
+	}
+	if err != nil {
+		return errors.Join(err, writer.Cancel(ctx))
+	}
+	if err = writer.Commit(ctx); err != nil {
+		return err
+	}
+
+	if err = m.verify(ctx, path, hash.Sum(nil)); err != nil {
+		// remove the broken copy, so it is copied again by the next migration
+		return errors.Join(err, m.target.Delete(ctx, path))
+	}
+
+	m.report.Copied++
+	m.report.CopiedSize += n
+	return nil
+}
+
+func (m *migrator) verify(ctx context.Context, path string, checksum []byte) error {
+	reader, err := m.target.Reader(ctx, path, 0)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	hash := sha256.New()
+	if _, err = io.Copy(hash, reader); err != nil {
+		return err
+	}
+	if !bytes.Equal(hash.Sum(nil), checksum) {
+		return fmt.Errorf("checksum mismatch of %s", path)
+	}
+	return nil
+}
+
+func (m *migrator) reportProgress(force bool) error {
+	if m.progress == nil || (!force && time.Since(m.lastReport) < progressInterval) {
+		return nil
+	}
+	m.lastReport = time.Now()
+
+	progress := 100
+	if m.report.TotalSize > 0 {
+		progress = int(m.processedSize * 100 / m.report.TotalSize)
+	}
+	return m.progress(progress, m.report)
+}
@@ -0,0 +1,137 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package blobmigrate
+
+import (
+	"context"
+	"testing"
+
+	"github.com/easysoft/gitfox/pkg/storage/driver/inmemory"
+	"github.com/easysoft/gitfox/types"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrator(t *testing.T) {
+	ctx := context.Background()
+	files := map[string]string{
+		"/artifacts/blobs/aa/aa01":                 "content a",
+		"/artifacts/blobs/bb/bb01":                 "content b",
+		"/artifacts/container/blobs/sha256/cc/cc1": "content c",
+		"/pipelines/logs/1":                        "out of root",
+	}
+
+	tests := []struct {
+		name       string
+		existing   map[string]string
+		checkpoint string
+		failed     []string
+		expected   types.ArtifactBlobMigrationReport
+		migrated   []string
+	}{
+		{
+			name: "copy all",
+			expected: types.ArtifactBlobMigrationReport{
+				TotalFiles: 3, TotalSize: 27, Copied: 3, CopiedSize: 27,
+				LastPath: "/artifacts/container/blobs/sha256/cc/cc1",
+			},
+			migrated: []string{"/artifacts/blobs/aa/aa01", "/artifacts/blobs/bb/bb01", "/artifacts/container/blobs/sha256/cc/cc1"},
+		},
+		{
+			name: "skip copied and replace partial",
+			existing: map[string]string{
+				"/artifacts/blobs/aa/aa01": "content a",
+				"/artifacts/blobs/bb/bb01": "cont",
+			},
+			expected: types.ArtifactBlobMigrationReport{
+				TotalFiles: 3, TotalSize: 27, Copied: 2, CopiedSize: 18, Skipped: 1,
+				LastPath: "/artifacts/container/blobs/sha256/cc/cc1",
+			},
+			migrated: []string{"/artifacts/blobs/aa/aa01", "/artifacts/blobs/bb/bb01", "/artifacts/container/blobs/sha256/cc/cc1"},
+		},
+		{
+			name:       "resume after checkpoint",
+			checkpoint: "/artifacts/blobs/bb/bb01",
+			expected: types.ArtifactBlobMigrationReport{
+				TotalFiles: 1, TotalSize: 9, Copied: 1, CopiedSize: 9,
+				LastPath: "/artifacts/container/blobs/sha256/cc/cc1",
+			},
+			migrated: []string{"/artifacts/container/blobs/sha256/cc/cc1"},
+		},
+		{
+			name:       "retry failed before checkpoint",
+			checkpoint: "/artifacts/blobs/bb/bb01",
+			failed:     []string{"/artifacts/blobs/aa/aa01", "/artifacts/blobs/gone"},
+			expected: types.ArtifactBlobMigrationReport{
+				TotalFiles: 1, TotalSize: 9, Copied: 2, CopiedSize: 18,
+				Failed: []string{}, LastPath: "/artifacts/container/blobs/sha256/cc/cc1",
+			},
+			migrated: []string{"/artifacts/blobs/aa/aa01", "/artifacts/container/blobs/sha256/cc/cc1"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			source, target := inmemory.New(), inmemory.New()
+			for p, content := range files {
+				require.NoError(t, source.PutContent(ctx, p, []byte(content)))
+			}
+			for p, content := range test.existing {
+				require.NoError(t, target.PutContent(ctx, p, []byte(content)))
+			}
+
+			progress := make([]int, 0)
+			m := &migrator{
+				source: source,
+				target: target,
+				report: &types.ArtifactBlobMigrationReport{
+					LastPath:    test.checkpoint,
+					Failed:      test.failed,
+					FailedCount: int64(len(test.failed)),
+				},
+				progress: func(p int, _ *types.ArtifactBlobMigrationReport) error {
+					progress = append(progress, p)
+					return nil
+				},
+			}
+			require.NoError(t, m.run(ctx, "/artifacts"))
+			require.Equal(t, test.expected, *m.report)
+			require.Equal(t, 100, progress[len(progress)-1])
+
+			for _, p := range test.migrated {
+				content, err := target.GetContent(ctx, p)
+				require.NoError(t, err)
+				require.Equal(t, files[p], string(content))
+			}
+			_, err := target.Stat(ctx, "/pipelines/logs/1")
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestResumePoint(t *testing.T) {
+	last := &types.ArtifactBlobMigrationReport{LastPath: "/artifacts/bb", Failed: []string{"/artifacts/aa"}, FailedCount: 1}
+	checkpoint, failed := resumePoint(last)
+	require.Equal(t, "/artifacts/bb", checkpoint)
+	require.Equal(t, []string{"/artifacts/aa"}, failed)
+
+	// the failures beyond the recorded paths can't be retried, the migration starts over
+	last.FailedCount = maxFailedPaths + 1
+	checkpoint, failed = resumePoint(last)
+	require.Empty(t, checkpoint)
+	require.Nil(t, failed)
+}
+
+func TestMigratorMissingRoot(t *testing.T) {
+	m := &migrator{
+		source: inmemory.New(),
+		target: inmemory.New(),
+		report: &types.ArtifactBlobMigrationReport{},
+	}
+	require.NoError(t, m.run(context.Background(), "/artifacts"))
+	require.Zero(t, m.report.TotalFiles)
+}
@@ -0,0 +1,197 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package blobmigrate
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/easysoft/gitfox/app/api/usererror"
+	"github.com/easysoft/gitfox/app/artifact"
+	"github.com/easysoft/gitfox/job"
+	"github.com/easysoft/gitfox/pkg/storage"
+	gitfox_store "github.com/easysoft/gitfox/store"
+	"github.com/easysoft/gitfox/types"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	JobTypeArtifactBlobMigration = "gitfox:artifact:blob-migration"
+
+	// JobUIDArtifactBlobMigration is the uid of the migration job, only one migration runs at a time
+	JobUIDArtifactBlobMigration = "artifact-blob-migration"
+
+	jobMaxDurationArtifactBlobMigration = 24 * time.Hour
+	jobMaxRetriesArtifactBlobMigration  = 3
+)
+
+var ErrJobRunning = usererror.Conflict("an artifact blob migration job is already running")
+
+type Input struct {
+	Source types.StorageProviderType `json:"source"`
+	Target types.StorageProviderType `json:"target"`
+	// StartAfter is the checkpoint of the interrupted migration
+	StartAfter string `json:"start_after,omitempty"`
+	// Failed are the paths failed before the checkpoint, they are copied again
+	Failed []string `json:"failed,omitempty"`
+}
+
+// Service migrates the artifact and container blobs between the storage backends.
+// The server keeps working with the source storage during the migration, a migration
+// should be run again before switching the provider to copy the blobs written meanwhile.
+type Service struct {
+	config        artifact.Config
+	storageConfig storage.Config
+	scheduler     *job.Scheduler
+}
+
+func NewService(config artifact.Config, storageConfig storage.Config, scheduler *job.Scheduler) *Service {
+	return &Service{
+		config:        config,
+		storageConfig: storageConfig,
+		scheduler:     scheduler,
+	}
+}
+
+// Trigger starts the migration job, it resumes from the checkpoint of the last
+// migration between the same storages if the last one did not finish.
+func (s *Service) Trigger(ctx context.Context, in *types.ArtifactBlobMigrationInput) error {
+	if in.Source == in.Target {
+		return usererror.BadRequest("the source and target storage must be different")
+	}
+	for _, provider := range []types.StorageProviderType{in.Source, in.Target} {
+		if _, err := artifact.LoadDriver(ctx, provider, s.storageConfig); err != nil {
+			return usererror.BadRequestf("invalid storage '%s': %s", provider, err)
+		}
+	}
+
+	input := Input{Source: in.Source, Target: in.Target}
+	progress, err := s.scheduler.GetJobProgress(ctx, JobUIDArtifactBlobMigration)
+	if err == nil {
+		if !progress.State.IsCompleted() {
+			return ErrJobRunning
+		}
+		if progress.State != job.JobStateFinished {
+			if last := parseReport(progress.Result); last != nil && last.Source == in.Source && last.Target == in.Target {
+				input.StartAfter, input.Failed = resumePoint(last)
+			}
+		}
+		if err = s.scheduler.PurgeJobByUID(ctx, JobUIDArtifactBlobMigration); err != nil {
+			return err
+		}
+	} else if !errors.Is(err, gitfox_store.ErrResourceNotFound) {
+		return fmt.Errorf("failed to get job progress: %w", err)
+	}
+
+	data, err := json.Marshal(input)
+	if err != nil {
+		return fmt.Errorf("failed to marshal job input json: %w", err)
+	}
+	return s.scheduler.RunJob(ctx, job.Definition{
+		UID:        JobUIDArtifactBlobMigration,
+		Type:       JobTypeArtifactBlobMigration,
+		MaxRetries: jobMaxRetriesArtifactBlobMigration,
+		Timeout:    jobMaxDurationArtifactBlobMigration,
+		Data:       base64.StdEncoding.EncodeToString(data),
+	})
+}
+
+// Progress returns the progress of the migration job, the result is the json of types.ArtifactBlobMigrationReport.
+func (s *Service) Progress(ctx context.Context) (job.Progress, error) {
+	progress, err := s.scheduler.GetJobProgress(ctx, JobUIDArtifactBlobMigration)
+	if errors.Is(err, gitfox_store.ErrResourceNotFound) {
+		return job.Progress{}, usererror.NotFound("no artifact blob migration found")
+	}
+	return progress, err
+}
+
+// Handle runs the migration, the report is saved with the progress and returned on failure
+// as well, a retried job resumes from the last path of it.
+func (s *Service) Handle(ctx context.Context, data string, fn job.ProgressReporter) (string, error) {
+	raw, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return "", fmt.Errorf("failed to base64 decode job input: %w", err)
+	}
+	var input Input
+	if err = json.Unmarshal(raw, &input); err != nil {
+		return "", fmt.Errorf("failed to unmarshal job input json: %w", err)
+	}
+
+	source, err := artifact.LoadDriver(ctx, input.Source, s.storageConfig)
+	if err != nil {
+		return "", err
+	}
+	target, err := artifact.LoadDriver(ctx, input.Target, s.storageConfig)
+	if err != nil {
+		return "", err
+	}
+
+	report := &types.ArtifactBlobMigrationReport{
+		Source:   input.Source,
+		Target:   input.Target,
+		LastPath: input.StartAfter,
+		Failed:   input.Failed,
+	}
+	// the result of the failed execution is kept for the retry
+	if progress, e := s.scheduler.GetJobProgress(ctx, JobUIDArtifactBlobMigration); e == nil {
+		if last := parseReport(progress.Result); last != nil {
+			report.LastPath, report.Failed = resumePoint(last)
+		}
+	}
+	report.FailedCount = int64(len(report.Failed))
+	if report.LastPath != "" {
+		log.Ctx(ctx).Info().Msgf("resume artifact blob migration after %s", report.LastPath)
+	}
+
+	m := &migrator{
+		source: source,
+		target: target,
+		report: report,
+		progress: func(progress int, report *types.ArtifactBlobMigrationReport) error {
+			return fn(min(progress, job.ProgressMax), encodeReport(report))
+		},
+	}
+	err = m.run(ctx, s.root())
+	return encodeReport(report), err
+}
+
+// root is the directory of the artifact content storage in the driver.
+func (s *Service) root() string {
+	return "/" + strings.TrimPrefix(path.Clean(s.config.Storage.Prefix), "/")
+}
+
+// resumePoint returns the checkpoint and the failed paths the migration resumes with. The migration
+// starts over if the report misses some failed paths, the files copied before are skipped then.
+func resumePoint(last *types.ArtifactBlobMigrationReport) (string, []string) {
+	if last.FailedCount > int64(len(last.Failed)) {
+		return "", nil
+	}
+	return last.LastPath, last.Failed
+}
+
+func encodeReport(report *types.ArtifactBlobMigrationReport) string {
+	data, _ := json.Marshal(report)
+	return string(data)
+}
+
+func parseReport(result string) *types.ArtifactBlobMigrationReport {
+	if result == "" {
+		return nil
+	}
+	report := &types.ArtifactBlobMigrationReport{}
+	if err := json.Unmarshal([]byte(result), report); err != nil {
+		return nil
+	}
+	return report
+}
@@ -0,0 +1,32 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package blobmigrate
+
+import (
+	"github.com/easysoft/gitfox/app/artifact"
+	"github.com/easysoft/gitfox/job"
+	"github.com/easysoft/gitfox/pkg/storage"
+
+	"github.com/google/wire"
+)
+
+var WireSet = wire.NewSet(
+	ProvideBlobMigrateSvc,
+)
+
+func ProvideBlobMigrateSvc(
+	config artifact.Config,
+	storageConfig storage.Config,
+	scheduler *job.Scheduler,
+	executor *job.Executor,
+) (*Service, error) {
+	svc := NewService(config, storageConfig, scheduler)
+	if err := executor.Register(JobTypeArtifactBlobMigration, svc); err != nil {
+		return nil, err
+	}
+	return svc, nil
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package cleanup
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	artcontroller "github.com/easysoft/gitfox/app/artifact/controller"
+	"github.com/easysoft/gitfox/job"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	jobTypeArtifactRetention        = "gitfox:cleanup:artifact-retention"
+	jobCronArtifactRetention        = "45 3 * * *" // At 03:45 every day, before the artifact GC.
+	jobMaxDurationArtifactRetention = 30 * time.Minute
+)
+
+type artifactRetentionJob struct {
+	artCtrl *artcontroller.Controller
+}
+
+func newArtifactRetentionJob(
+	artCtrl *artcontroller.Controller,
+) *artifactRetentionJob {
+	return &artifactRetentionJob{
+		artCtrl: artCtrl,
+	}
+}
+
+// Handle soft removes the artifact versions out of the enabled retention policies.
+func (j *artifactRetentionJob) Handle(ctx context.Context, _ string, _ job.ProgressReporter) (string, error) {
+	log.Ctx(ctx).Info().Msg("start applying artifact retention policies")
+
+	reports, err := j.artCtrl.ApplyRetentionPolicies(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to apply artifact retention policies: %w", err)
+	}
+
+	removed := 0
+	for _, report := range reports {
+		removed += report.Removed
+	}
+
+	result := "no artifact version removed by retention policies"
+	if removed > 0 {
+		result = fmt.Sprintf("removed %d artifact versions by %d retention policies", removed, len(reports))
+	}
+
+	log.Ctx(ctx).Info().Msg(result)
+
+	return result, nil
+}
@@ -153,6 +153,17 @@ func (s *Service) scheduleRecurringCleanupJobs(ctx context.Context) error {
 	if err != nil {
 		return fmt.Errorf("failed to schedule artifact hard remove job: %w", err)
 	}
+
+	err = s.scheduler.AddRecurring(
+		ctx,
+		jobTypeArtifactRetention,
+		jobTypeArtifactRetention,
+		jobCronArtifactRetention,
+		jobMaxDurationArtifactRetention,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to schedule artifact retention job: %w", err)
+	}
 	return nil
 }
 
@@ -188,5 +199,14 @@ func (s *Service) registerJobHandlers() error {
 		return fmt.Errorf("failed to register job handler for deleted repos cleanup: %w", err)
 	}
 
+	if err := s.executor.Register(
+		jobTypeArtifactRetention,
+		newArtifactRetentionJob(
+			s.artCtrl,
+		),
+	); err != nil {
+		return fmt.Errorf("failed to register job handler for artifact retention: %w", err)
+	}
+
 	return nil
 }
@@ -0,0 +1,184 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package housekeeping
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/easysoft/gitfox/git"
+	gitfox_store "github.com/easysoft/gitfox/store"
+	"github.com/easysoft/gitfox/types"
+	"github.com/easysoft/gitfox/types/enum"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Optimize runs the housekeeping tasks the repository needs, with force all objects are repacked.
+func (s *Service) Optimize(ctx context.Context, repoID int64, force bool) error {
+	repo, err := s.repoStore.Find(ctx, repoID)
+	if errors.Is(err, gitfox_store.ErrResourceNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to find repo: %w", err)
+	}
+	if repo.State != enum.RepoStateActive {
+		log.Ctx(ctx).Debug().Msgf("repo %s isn't active, skip the housekeeping", repo.Path)
+		return nil
+	}
+
+	stats, tasks, err := s.plan(ctx, repo, force)
+	if err != nil {
+		return err
+	}
+	if tasks.IsEmpty() {
+		return nil
+	}
+
+	// gc rewrites all packs of the repository, the runs on the replicas are serialized,
+	// the incremental repack and the commit-graph are safe to run concurrently.
+	if tasks.GC {
+		unlock, err := s.locker.LockHousekeeping(ctx, repo.ID, jobMaxDurationOptimize)
+		if err != nil {
+			return err
+		}
+		defer unlock()
+	}
+
+	start := time.Now()
+	err = s.git.OptimizeRepository(ctx, &git.OptimizeRepositoryParams{
+		ReadParams: git.CreateReadParams(repo),
+		Tasks:      tasks,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to optimize repo: %w", err)
+	}
+
+	log.Ctx(ctx).Info().
+		Int64("loose_objects", stats.LooseObjects).
+		Int64("packs", stats.Packs).
+		Strs("tasks", taskNames(tasks)).
+		Dur("duration", time.Since(start)).
+		Msgf("repo %s optimized", repo.Path)
+
+	return nil
+}
+
+// Inspect returns the object counts of the repository and the tasks the next scheduled housekeeping runs.
+func (s *Service) Inspect(ctx context.Context, repo *types.Repository) (*types.RepositoryHousekeeping, error) {
+	stats, tasks, err := s.plan(ctx, repo, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.RepositoryHousekeeping{
+		LooseObjects:      stats.LooseObjects,
+		LooseSize:         stats.LooseSize,
+		PackedObjects:     stats.PackedObjects,
+		Packs:             stats.Packs,
+		PackSize:          stats.PackSize,
+		HasAlternates:     stats.HasAlternates,
+		HasCommitGraph:    stats.HasCommitGraph,
+		HasMultiPackIndex: stats.HasMultiPackIndex,
+		Tasks:             taskNames(tasks),
+	}, nil
+}
+
+func (s *Service) plan(
+	ctx context.Context,
+	repo *types.Repository,
+	force bool,
+) (*git.GetRepositoryStatsOutput, git.HousekeepingTasks, error) {
+	stats, err := s.git.GetRepositoryStats(ctx, &git.GetRepositoryStatsParams{
+		ReadParams: git.CreateReadParams(repo),
+	})
+	if err != nil {
+		return nil, git.HousekeepingTasks{}, fmt.Errorf("failed to get repo stats: %w", err)
+	}
+
+	hasForks, err := s.hasForks(ctx, repo.ID)
+	if err != nil {
+		return nil, git.HousekeepingTasks{}, err
+	}
+
+	return stats, s.planTasks(stats, hasForks, force), nil
+}
+
+// planTasks decides the housekeeping tasks by the object counts. Too many packs or force repack all
+// objects by gc, too many loose objects are packed incrementally. The unreachable objects of a repository
+// with forks are never pruned, the forks borrow the objects and may still reference them.
+func (s *Service) planTasks(stats *git.GetRepositoryStatsOutput, hasForks, force bool) git.HousekeepingTasks {
+	var tasks git.HousekeepingTasks
+	if stats.LooseObjects+stats.PackedObjects == 0 {
+		return tasks
+	}
+
+	switch {
+	case force || stats.Packs > s.packsLimit:
+		tasks.GC = true
+		if !hasForks {
+			tasks.PruneExpiry = s.pruneExpiry
+		}
+	case stats.LooseObjects > s.looseObjectsLimit:
+		tasks.Repack = true
+	}
+
+	// gc leaves a single pack and writes the commit-graph itself.
+	if tasks.GC {
+		return tasks
+	}
+
+	packs := stats.Packs
+	if tasks.Repack {
+		packs++
+	}
+	tasks.MultiPackIndex = packs > 1 && (tasks.Repack || !stats.HasMultiPackIndex)
+	tasks.CommitGraph = tasks.Repack || !stats.HasCommitGraph
+
+	return tasks
+}
+
+// hasForks returns true if the repository has forks, the deleted forks count until they are purged.
+func (s *Service) hasForks(ctx context.Context, repoID int64) (bool, error) {
+	now := time.Now().UnixMilli()
+	for _, filter := range []*types.RepoFilter{
+		{},
+		{DeletedBeforeOrAt: &now},
+	} {
+		count, err := s.repoStore.CountForks(ctx, repoID, filter)
+		if err != nil {
+			return false, fmt.Errorf("failed to count forks: %w", err)
+		}
+		if count > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func taskNames(tasks git.HousekeepingTasks) []string {
+	names := []string{}
+	if tasks.GC {
+		names = append(names, "gc")
+		if tasks.PruneExpiry != "" {
+			names = append(names, "prune")
+		}
+	}
+	if tasks.Repack {
+		names = append(names, "repack")
+	}
+	if tasks.MultiPackIndex {
+		names = append(names, "multi-pack-index")
+	}
+	if tasks.CommitGraph {
+		names = append(names, "commit-graph")
+	}
+	return names
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package housekeeping
+
+import (
+	"testing"
+
+	"github.com/easysoft/gitfox/git"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanTasks(t *testing.T) {
+	s := &Service{looseObjectsLimit: 100, packsLimit: 10, pruneExpiry: "2.weeks.ago"}
+
+	tests := []struct {
+		name     string
+		stats    git.GetRepositoryStatsOutput
+		hasForks bool
+		force    bool
+		want     git.HousekeepingTasks
+	}{
+		{
+			name: "empty repo",
+			want: git.HousekeepingTasks{},
+		},
+		{
+			name: "optimized repo",
+			stats: git.GetRepositoryStatsOutput{
+				LooseObjects: 10, PackedObjects: 1000, Packs: 1, HasCommitGraph: true,
+			},
+			want: git.HousekeepingTasks{},
+		},
+		{
+			name:  "missing commit-graph",
+			stats: git.GetRepositoryStatsOutput{PackedObjects: 1000, Packs: 1},
+			want:  git.HousekeepingTasks{CommitGraph: true},
+		},
+		{
+			name: "too many loose objects",
+			stats: git.GetRepositoryStatsOutput{
+				LooseObjects: 200, PackedObjects: 1000, Packs: 1, HasCommitGraph: true,
+			},
+			want: git.HousekeepingTasks{Repack: true, MultiPackIndex: true, CommitGraph: true},
+		},
+		{
+			name: "missing multi-pack-index",
+			stats: git.GetRepositoryStatsOutput{
+				PackedObjects: 1000, Packs: 3, HasCommitGraph: true,
+			},
+			want: git.HousekeepingTasks{MultiPackIndex: true},
+		},
+		{
+			name: "too many packs",
+			stats: git.GetRepositoryStatsOutput{
+				LooseObjects: 200, PackedObjects: 1000, Packs: 11, HasCommitGraph: true, HasMultiPackIndex: true,
+			},
+			want: git.HousekeepingTasks{GC: true, PruneExpiry: "2.weeks.ago"},
+		},
+		{
+			name: "too many packs with forks",
+			stats: git.GetRepositoryStatsOutput{
+				PackedObjects: 1000, Packs: 11, HasCommitGraph: true,
+			},
+			hasForks: true,
+			want:     git.HousekeepingTasks{GC: true},
+		},
+		{
+			name: "forced",
+			stats: git.GetRepositoryStatsOutput{
+				PackedObjects: 1000, Packs: 1, HasCommitGraph: true,
+			},
+			force: true,
+			want:  git.HousekeepingTasks{GC: true, PruneExpiry: "2.weeks.ago"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.want, s.planTasks(&test.stats, test.hasForks, test.force))
+		})
+	}
+}
+
+func TestTaskNames(t *testing.T) {
+	require.Equal(t, []string{}, taskNames(git.HousekeepingTasks{}))
+	require.Equal(t, []string{"gc", "prune"}, taskNames(git.HousekeepingTasks{GC: true, PruneExpiry: "now"}))
+	require.Equal(t, []string{"repack", "multi-pack-index", "commit-graph"},
+		taskNames(git.HousekeepingTasks{Repack: true, MultiPackIndex: true, CommitGraph: true}))
+}
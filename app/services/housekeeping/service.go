@@ -0,0 +1,163 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+// Package housekeeping optimizes the object databases of the repositories in the background.
+package housekeeping
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/easysoft/gitfox/app/api/usererror"
+	"github.com/easysoft/gitfox/app/services/locker"
+	"github.com/easysoft/gitfox/app/store"
+	"github.com/easysoft/gitfox/git"
+	"github.com/easysoft/gitfox/job"
+	gitfox_store "github.com/easysoft/gitfox/store"
+	"github.com/easysoft/gitfox/types"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// jobTypeScheduler is the recurring job optimizing the repositories which need it.
+	jobTypeScheduler = "repo-housekeeping-scheduler"
+	// jobTypeOptimize is the job optimizing a single repository on request.
+	jobTypeOptimize = "repo-housekeeping"
+
+	jobMaxRetriesOptimize  = 3
+	jobMaxDurationOptimize = time.Hour
+)
+
+var ErrOptimizeRunning = usererror.Conflict("the repository is already being optimized")
+
+type Service struct {
+	enabled           bool
+	cron              string
+	maxDur            time.Duration
+	numWorkers        int
+	looseObjectsLimit int64
+	packsLimit        int64
+	pruneExpiry       string
+	git               git.Interface
+	repoStore         store.RepoStore
+	locker            *locker.Locker
+	scheduler         *job.Scheduler
+}
+
+// Register registers the recurring job optimizing the repositories.
+func (s *Service) Register(ctx context.Context) error {
+	if !s.enabled {
+		return nil
+	}
+
+	err := s.scheduler.AddRecurring(ctx, jobTypeScheduler, jobTypeScheduler, s.cron, s.maxDur)
+	if err != nil {
+		return fmt.Errorf("failed to register recurring job for repo housekeeping: %w", err)
+	}
+
+	return nil
+}
+
+// Handle optimizes the repositories whose object counts exceed the limits,
+// the repositories are optimized by the workers concurrently.
+func (s *Service) Handle(ctx context.Context, _ string, _ job.ProgressReporter) (string, error) {
+	if !s.enabled {
+		return "", nil
+	}
+
+	repos, err := s.repoStore.ListSizeInfos(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to list repos: %w", err)
+	}
+	if len(repos) == 0 {
+		return "", nil
+	}
+
+	log.Ctx(ctx).Info().Msgf("start housekeeping of %d repos", len(repos))
+
+	var wg sync.WaitGroup
+	taskCh := make(chan *types.RepositorySizeInfo)
+	for i := 0; i < s.numWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for repo := range taskCh {
+				err := s.Optimize(ctx, repo.ID, false)
+				if err != nil {
+					log.Ctx(ctx).Warn().Err(err).Int64("repo_id", repo.ID).Msg("failed to optimize repo")
+				}
+			}
+		}()
+	}
+loop:
+	for _, repo := range repos {
+		select {
+		case <-ctx.Done():
+			break loop
+		case taskCh <- repo:
+		}
+	}
+	close(taskCh)
+	wg.Wait()
+
+	return "", nil
+}
+
+// Trigger starts the job optimizing the repository right away, all objects are repacked regardless of the limits.
+func (s *Service) Trigger(ctx context.Context, repo *types.Repository) error {
+	jobUID := optimizeJobUID(repo.ID)
+
+	progress, err := s.scheduler.GetJobProgress(ctx, jobUID)
+	if err == nil {
+		if !progress.State.IsCompleted() {
+			return ErrOptimizeRunning
+		}
+		if err = s.scheduler.PurgeJobByUID(ctx, jobUID); err != nil {
+			return err
+		}
+	} else if !errors.Is(err, gitfox_store.ErrResourceNotFound) {
+		return fmt.Errorf("failed to get job progress: %w", err)
+	}
+
+	data, err := json.Marshal(optimizeInput{RepoID: repo.ID})
+	if err != nil {
+		return fmt.Errorf("failed to marshal job input json: %w", err)
+	}
+	return s.scheduler.RunJob(ctx, job.Definition{
+		UID:        jobUID,
+		Type:       jobTypeOptimize,
+		MaxRetries: jobMaxRetriesOptimize,
+		Timeout:    jobMaxDurationOptimize,
+		Data:       string(data),
+	})
+}
+
+func optimizeJobUID(repoID int64) string {
+	return jobTypeOptimize + "-" + strconv.FormatInt(repoID, 10)
+}
+
+type optimizeInput struct {
+	RepoID int64 `json:"repo_id"`
+}
+
+// optimizeJob is the job handler optimizing a single repository.
+type optimizeJob struct {
+	service *Service
+}
+
+func (j *optimizeJob) Handle(ctx context.Context, data string, _ job.ProgressReporter) (string, error) {
+	var input optimizeInput
+	if err := json.Unmarshal([]byte(data), &input); err != nil {
+		return "", fmt.Errorf("failed to unmarshal job input json: %w", err)
+	}
+	return "", j.service.Optimize(ctx, input.RepoID, true)
+}
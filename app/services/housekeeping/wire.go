@@ -0,0 +1,62 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package housekeeping
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/easysoft/gitfox/app/services/locker"
+	"github.com/easysoft/gitfox/app/store"
+	"github.com/easysoft/gitfox/git"
+	"github.com/easysoft/gitfox/job"
+	"github.com/easysoft/gitfox/types"
+
+	"github.com/google/wire"
+)
+
+var WireSet = wire.NewSet(
+	ProvideService,
+)
+
+func ProvideService(
+	config *types.Config,
+	git git.Interface,
+	repoStore store.RepoStore,
+	locker *locker.Locker,
+	scheduler *job.Scheduler,
+	executor *job.Executor,
+) (*Service, error) {
+	// the writes to the repository aren't locked against gc, the objects they add must survive the prune.
+	if strings.EqualFold(config.RepoHousekeeping.PruneExpiry, "now") {
+		return nil, fmt.Errorf("prune expiry of the repo housekeeping must leave a grace period, got %q",
+			config.RepoHousekeeping.PruneExpiry)
+	}
+
+	service := &Service{
+		enabled:           config.RepoHousekeeping.Enabled,
+		cron:              config.RepoHousekeeping.CRON,
+		maxDur:            config.RepoHousekeeping.MaxDuration,
+		numWorkers:        config.RepoHousekeeping.NumWorkers,
+		looseObjectsLimit: config.RepoHousekeeping.LooseObjectsLimit,
+		packsLimit:        config.RepoHousekeeping.PacksLimit,
+		pruneExpiry:       config.RepoHousekeeping.PruneExpiry,
+		git:               git,
+		repoStore:         repoStore,
+		locker:            locker,
+		scheduler:         scheduler,
+	}
+
+	if err := executor.Register(jobTypeScheduler, service); err != nil {
+		return nil, err
+	}
+	if err := executor.Register(jobTypeOptimize, &optimizeJob{service: service}); err != nil {
+		return nil, err
+	}
+
+	return service, nil
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/easysoft/gitfox/app/githook"
 	"github.com/easysoft/gitfox/app/paths"
 	"github.com/easysoft/gitfox/app/services/keywordsearch"
+	"github.com/easysoft/gitfox/app/services/lfs"
 	"github.com/easysoft/gitfox/app/services/publicaccess"
 	"github.com/easysoft/gitfox/app/sse"
 	"github.com/easysoft/gitfox/app/store"
@@ -68,6 +69,7 @@ type Repository struct {
 	indexer       keywordsearch.Indexer
 	publicAccess  publicaccess.Service
 	auditService  audit.Service
+	lfsService    *lfs.Service
 }
 
 var _ job.Handler = (*Repository)(nil)
@@ -92,6 +94,7 @@ type Input struct {
 	CloneURL  string         `json:"clone_url"`
 	Pipelines PipelineOption `json:"pipelines"`
 	Mirror    bool           `json:"mirror"`
+	LFS       bool           `json:"lfs"`
 }
 
 const jobType = "repository_import"
@@ -109,6 +112,7 @@ func (r *Repository) Run(
 	cloneURL string,
 	pipelines PipelineOption,
 	mirror bool,
+	lfs bool,
 ) error {
 	jobDef, err := r.getJobDef(JobIDFromRepoID(repo.ID), Input{
 		RepoID:    repo.ID,
@@ -118,6 +122,7 @@ func (r *Repository) Run(
 		CloneURL:  cloneURL,
 		Pipelines: pipelines,
 		Mirror:    mirror,
+		LFS:       lfs,
 	})
 	if err != nil {
 		return err
@@ -135,6 +140,7 @@ func (r *Repository) RunMany(ctx context.Context,
 	cloneURLs []string,
 	pipelines PipelineOption,
 	mirror bool,
+	lfs bool,
 ) error {
 	if len(repoIDs) != len(cloneURLs) {
 		return fmt.Errorf("slice length mismatch: have %d repositories and %d clone URLs",
@@ -156,6 +162,7 @@ func (r *Repository) RunMany(ctx context.Context,
 			CloneURL:  cloneURL,
 			Pipelines: pipelines,
 			Mirror:    mirror,
+			LFS:       lfs,
 		})
 		if err != nil {
 			return err
@@ -349,10 +356,19 @@ func (r *Repository) Handle(ctx context.Context, data string, _ job.ProgressRepo
 				SpaceID:       repo.ParentID,
 				SyncInterval:  1,
 				EnablePrune:   true,
+				LfsEnabled:    input.LFS,
 				RemoteAddress: input.CloneURL,
 			}); err != nil {
 				log.Warn().Err(err).Msg("failed to create or update mirror status")
 			}
+			if input.LFS {
+				log.Info().Msg("fetch lfs objects")
+				// the git content is mirrored already, the import doesn't fail on the lfs objects
+				err = r.lfsService.FetchMirrorObjects(ctx, repo, cloneURLWithAuth, systemPrincipal.ID)
+				if err != nil {
+					log.Warn().Err(err).Msg("failed to fetch lfs objects")
+				}
+			}
 			return nil
 		}
 
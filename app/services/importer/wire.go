@@ -16,6 +16,7 @@ package importer
 
 import (
 	"github.com/easysoft/gitfox/app/services/keywordsearch"
+	"github.com/easysoft/gitfox/app/services/lfs"
 	"github.com/easysoft/gitfox/app/services/publicaccess"
 	"github.com/easysoft/gitfox/app/sse"
 	"github.com/easysoft/gitfox/app/store"
@@ -49,6 +50,7 @@ func ProvideRepoImporter(
 	indexer keywordsearch.Indexer,
 	publicAccess publicaccess.Service,
 	auditService audit.Service,
+	lfsService *lfs.Service,
 ) (*Repository, error) {
 	importer := &Repository{
 		defaultBranch: config.Git.DefaultBranch,
@@ -64,6 +66,7 @@ func ProvideRepoImporter(
 		indexer:       indexer,
 		publicAccess:  publicAccess,
 		auditService:  auditService,
+		lfsService:    lfsService,
 	}
 
 	err := executor.Register(jobType, importer)
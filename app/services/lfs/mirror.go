@@ -0,0 +1,257 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package lfs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/easysoft/gitfox/git"
+	"github.com/easysoft/gitfox/git/parser"
+	"github.com/easysoft/gitfox/types"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	mediaType = "application/vnd.git-lfs+json"
+	// mirrorBatchSize limits the objects requested from the remote in one batch request.
+	mirrorBatchSize = 100
+)
+
+// batchRequest and batchResponse are the subset of the batch api used to download the objects from the remote.
+type batchRequest struct {
+	Operation string         `json:"operation"`
+	Transfers []string       `json:"transfers"`
+	Objects   []batchPointer `json:"objects"`
+}
+
+type batchPointer struct {
+	OID  string `json:"oid"`
+	Size int64  `json:"size"`
+}
+
+type batchResponse struct {
+	Objects []batchObject `json:"objects"`
+}
+
+type batchObject struct {
+	OID     string                 `json:"oid"`
+	Size    int64                  `json:"size"`
+	Actions map[string]batchAction `json:"actions"`
+	Error   *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+type batchAction struct {
+	Href   string            `json:"href"`
+	Header map[string]string `json:"header"`
+}
+
+// FetchMirrorObjects downloads the lfs objects referenced in the mirrored repository from the remote,
+// the objects already stored for the repository are skipped. The remote url may carry the credentials.
+func (s *Service) FetchMirrorObjects(
+	ctx context.Context,
+	repo *types.Repository,
+	remoteURL string,
+	principalID int64,
+) error {
+	remote, err := url.Parse(remoteURL)
+	if err != nil {
+		return fmt.Errorf("failed to parse remote url: %w", err)
+	}
+
+	out, err := s.git.ListLFSPointers(ctx, &git.ListLFSPointersParams{
+		ReadParams: git.CreateReadParams(repo),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list lfs pointers: %w", err)
+	}
+
+	var failed int
+	for start := 0; start < len(out.Pointers); start += mirrorBatchSize {
+		end := min(start+mirrorBatchSize, len(out.Pointers))
+		missing, err := s.missingObjects(ctx, repo.ID, out.Pointers[start:end])
+		if err != nil {
+			return err
+		}
+		if len(missing) == 0 {
+			continue
+		}
+
+		resp, err := s.requestBatch(ctx, remote, missing)
+		if err != nil {
+			return err
+		}
+		for _, obj := range resp.Objects {
+			if err = s.fetchObject(ctx, remote, repo.ID, principalID, obj); err != nil {
+				log.Ctx(ctx).Warn().Err(err).Msgf("failed to fetch lfs object %s", obj.OID)
+				failed++
+			}
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to fetch %d lfs objects of %d", failed, len(out.Pointers))
+	}
+	return nil
+}
+
+func (s *Service) missingObjects(ctx context.Context, repoID int64, pointers []parser.LFSPointer,
+) ([]parser.LFSPointer, error) {
+	oids := make([]string, len(pointers))
+	for i, p := range pointers {
+		oids[i] = p.OID
+	}
+	existing, err := s.lfsStore.FindMany(ctx, repoID, oids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find lfs objects: %w", err)
+	}
+
+	stored := make(map[string]struct{}, len(existing))
+	for _, obj := range existing {
+		stored[obj.OID] = struct{}{}
+	}
+	missing := make([]parser.LFSPointer, 0, len(pointers))
+	for _, p := range pointers {
+		if _, ok := stored[p.OID]; !ok {
+			missing = append(missing, p)
+		}
+	}
+	return missing, nil
+}
+
+func (s *Service) requestBatch(ctx context.Context, remote *url.URL, pointers []parser.LFSPointer,
+) (*batchResponse, error) {
+	objects := make([]batchPointer, len(pointers))
+	for i, p := range pointers {
+		objects[i] = batchPointer{OID: p.OID, Size: p.Size}
+	}
+	body, err := json.Marshal(batchRequest{
+		Operation: "download",
+		Transfers: []string{"basic"},
+		Objects:   objects,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, batchURL(remote), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", mediaType)
+	req.Header.Set("Content-Type", mediaType)
+	setBasicAuth(req, remote)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to request lfs batch: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("lfs batch request failed with status %d", resp.StatusCode)
+	}
+	out := &batchResponse{}
+	if err = json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return nil, fmt.Errorf("failed to decode lfs batch response: %w", err)
+	}
+	return out, nil
+}
+
+func (s *Service) fetchObject(
+	ctx context.Context,
+	remote *url.URL,
+	repoID int64,
+	principalID int64,
+	obj batchObject,
+) error {
+	if obj.Error != nil {
+		return fmt.Errorf("remote error %d: %s", obj.Error.Code, obj.Error.Message)
+	}
+	action, ok := obj.Actions["download"]
+	if !ok {
+		return errors.New("remote returned no download action")
+	}
+
+	href, err := checkHref(remote, action.Href)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, href.String(), nil)
+	if err != nil {
+		return err
+	}
+	for k, v := range action.Header {
+		req.Header.Set(k, v)
+	}
+	// the credentials of the remote aren't sent over a downgraded scheme
+	if req.Header.Get("Authorization") == "" && href.Scheme == remote.Scheme {
+		setBasicAuth(req, remote)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("download failed with status %d", resp.StatusCode)
+	}
+	return s.StoreObject(ctx, repoID, principalID, parser.LFSPointer{OID: obj.OID, Size: obj.Size}, resp.Body)
+}
+
+// checkHref parses the download href returned by the remote, the object is downloaded from the remote host only
+// so the remote can't make the server request any other host, e.g. the internal ones.
+func checkHref(remote *url.URL, href string) (*url.URL, error) {
+	u, err := url.Parse(href)
+	if err != nil {
+		return nil, fmt.Errorf("invalid download href: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("download href scheme %q is not allowed", u.Scheme)
+	}
+	if u.Host != remote.Host {
+		return nil, fmt.Errorf("download href host %s doesn't match the remote host %s", u.Host, remote.Host)
+	}
+	return u, nil
+}
+
+// batchURL returns the batch endpoint of the remote, see
+// https://github.com/git-lfs/git-lfs/blob/main/docs/api/server-discovery.md.
+func batchURL(remote *url.URL) string {
+	u := *remote
+	u.User = nil
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	if !strings.HasSuffix(u.Path, ".git") {
+		u.Path += ".git"
+	}
+	u.Path += "/info/lfs/objects/batch"
+	return u.String()
+}
+
+func setBasicAuth(req *http.Request, remote *url.URL) {
+	if remote.User == nil {
+		return
+	}
+	password, _ := remote.User.Password()
+	if remote.User.Username() == "" && password == "" {
+		return
+	}
+	req.SetBasicAuth(remote.User.Username(), password)
+}
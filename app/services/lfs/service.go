@@ -0,0 +1,197 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+// Package lfs stores the git lfs objects of the repositories,
+// the objects are kept in the blob store and recorded to the repositories in the lfs object store.
+package lfs
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"path"
+	"time"
+
+	"github.com/easysoft/gitfox/app/api/usererror"
+	"github.com/easysoft/gitfox/app/store"
+	"github.com/easysoft/gitfox/blob"
+	"github.com/easysoft/gitfox/git"
+	"github.com/easysoft/gitfox/git/parser"
+	gitfox_store "github.com/easysoft/gitfox/store"
+	"github.com/easysoft/gitfox/types"
+
+	"github.com/rs/zerolog/log"
+)
+
+// objectBucketDir keeps the lfs objects in the blob store, the objects are stored once by the oid
+// and shared by the repositories, a repository accesses the objects recorded in the lfs object store.
+const objectBucketDir = "lfs"
+
+// clientTimeout bounds a request to the remote of a mirror, including the download of an object.
+const clientTimeout = 30 * time.Minute
+
+var (
+	ErrObjectInvalid  = usererror.BadRequest("invalid lfs object id")
+	ErrObjectNotFound = usererror.NotFound("lfs object not found")
+	ErrSizeMismatch   = usererror.New(http.StatusUnprocessableEntity, "lfs object size mismatch")
+	ErrHashMismatch   = usererror.New(http.StatusUnprocessableEntity, "lfs object hash mismatch")
+)
+
+type Service struct {
+	git       git.Interface
+	lfsStore  store.LFSObjectStore
+	blobStore blob.Store
+	client    *http.Client
+}
+
+func NewService(
+	git git.Interface,
+	lfsStore store.LFSObjectStore,
+	blobStore blob.Store,
+) *Service {
+	return &Service{
+		git:       git,
+		lfsStore:  lfsStore,
+		blobStore: blobStore,
+		client: &http.Client{
+			Timeout: clientTimeout,
+			// the redirects are followed within the host of the original request only
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if len(via) >= 10 {
+					return errors.New("stopped after 10 redirects")
+				}
+				if req.URL.Host != via[0].URL.Host {
+					return fmt.Errorf("redirect to host %s is not allowed", req.URL.Host)
+				}
+				return nil
+			},
+		},
+	}
+}
+
+// ObjectPath returns the path of the lfs object in the blob store.
+func ObjectPath(oid string) string {
+	return path.Join(objectBucketDir, oid[0:2], oid[2:4], oid)
+}
+
+// StoreObject stores the lfs object in the blob store and records it to the repository,
+// the content is verified against the oid and size.
+func (s *Service) StoreObject(
+	ctx context.Context,
+	repoID int64,
+	principalID int64,
+	pointer parser.LFSPointer,
+	content io.Reader,
+) error {
+	if !parser.IsValidLFSObjectID(pointer.OID) || pointer.Size < 0 {
+		return ErrObjectInvalid
+	}
+
+	_, err := s.lfsStore.Find(ctx, repoID, pointer.OID)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, gitfox_store.ErrResourceNotFound) {
+		return err
+	}
+
+	reader := newVerifyingReader(content, pointer)
+	objectPath := ObjectPath(pointer.OID)
+
+	// the object uploaded to another repository is shared, only the content is verified
+	// so the stored object isn't overwritten by an invalid upload
+	stored, err := s.blobStore.Download(ctx, objectPath)
+	switch {
+	case err == nil:
+		_ = stored.Close()
+		if _, err = io.Copy(io.Discard, reader); err != nil {
+			return err
+		}
+	case errors.Is(err, blob.ErrNotFound):
+		if err = s.blobStore.Upload(ctx, reader, objectPath); err != nil {
+			return fmt.Errorf("failed to upload lfs object %s: %w", pointer.OID, err)
+		}
+	default:
+		return fmt.Errorf("failed to check lfs object %s: %w", pointer.OID, err)
+	}
+
+	err = s.lfsStore.Create(ctx, &types.LFSObject{
+		OID:       pointer.OID,
+		Size:      pointer.Size,
+		RepoID:    repoID,
+		Created:   time.Now().UnixMilli(),
+		CreatedBy: principalID,
+	})
+	if errors.Is(err, gitfox_store.ErrDuplicate) {
+		log.Ctx(ctx).Debug().Msgf("lfs object %s was recorded concurrently", pointer.OID)
+		return nil
+	}
+	return err
+}
+
+// OpenObject returns the content of the lfs object of the repository and its size.
+func (s *Service) OpenObject(ctx context.Context, repoID int64, oid string) (io.ReadCloser, int64, error) {
+	if !parser.IsValidLFSObjectID(oid) {
+		return nil, 0, ErrObjectInvalid
+	}
+
+	obj, err := s.lfsStore.Find(ctx, repoID, oid)
+	if errors.Is(err, gitfox_store.ErrResourceNotFound) {
+		return nil, 0, ErrObjectNotFound
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	content, err := s.blobStore.Download(ctx, ObjectPath(oid))
+	if errors.Is(err, blob.ErrNotFound) {
+		return nil, 0, ErrObjectNotFound
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to download lfs object %s: %w", oid, err)
+	}
+	return content, obj.Size, nil
+}
+
+// verifyingReader fails the read at the end of the content if the content doesn't match the pointer.
+type verifyingReader struct {
+	reader  io.Reader
+	hash    hash.Hash
+	size    int64
+	pointer parser.LFSPointer
+}
+
+func newVerifyingReader(r io.Reader, pointer parser.LFSPointer) *verifyingReader {
+	return &verifyingReader{
+		reader:  r,
+		hash:    sha256.New(),
+		pointer: pointer,
+	}
+}
+
+func (r *verifyingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.size += int64(n)
+	_, _ = r.hash.Write(p[:n])
+
+	if r.size > r.pointer.Size {
+		return n, ErrSizeMismatch
+	}
+	if errors.Is(err, io.EOF) {
+		if r.size != r.pointer.Size {
+			return n, ErrSizeMismatch
+		}
+		if hex.EncodeToString(r.hash.Sum(nil)) != r.pointer.OID {
+			return n, ErrHashMismatch
+		}
+	}
+	return n, err
+}
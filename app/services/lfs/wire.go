@@ -0,0 +1,27 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package lfs
+
+import (
+	"github.com/easysoft/gitfox/app/store"
+	"github.com/easysoft/gitfox/blob"
+	"github.com/easysoft/gitfox/git"
+
+	"github.com/google/wire"
+)
+
+var WireSet = wire.NewSet(
+	ProvideService,
+)
+
+func ProvideService(
+	git git.Interface,
+	lfsStore store.LFSObjectStore,
+	blobStore blob.Store,
+) *Service {
+	return NewService(git, lfsStore, blobStore)
+}
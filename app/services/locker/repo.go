@@ -40,3 +40,37 @@ func (l Locker) LockDefaultBranch(
 
 	return unlockFn, nil
 }
+
+func (l Locker) LockMirrorSync(
+	ctx context.Context,
+	repoID int64,
+	expiry time.Duration,
+) (func(), error) {
+	key := strconv.FormatInt(repoID, 10) + "/mirrorSync"
+
+	log.Ctx(ctx).Debug().Msg("attempting to lock to sync the mirror")
+
+	unlockFn, err := l.lock(ctx, namespaceRepo, key, expiry)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock repo to sync the mirror: %w", err)
+	}
+
+	return unlockFn, nil
+}
+
+func (l Locker) LockHousekeeping(
+	ctx context.Context,
+	repoID int64,
+	expiry time.Duration,
+) (func(), error) {
+	key := strconv.FormatInt(repoID, 10) + "/housekeeping"
+
+	log.Ctx(ctx).Debug().Msg("attempting to lock to gc the repo")
+
+	unlockFn, err := l.lock(ctx, namespaceRepo, key, expiry)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock repo to gc: %w", err)
+	}
+
+	return unlockFn, nil
+}
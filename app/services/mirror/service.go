@@ -0,0 +1,162 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+// Package mirror synchronizes the pull mirrors with their remotes in the background.
+package mirror
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/easysoft/gitfox/app/api/usererror"
+	gitevents "github.com/easysoft/gitfox/app/events/git"
+	"github.com/easysoft/gitfox/app/services/lfs"
+	"github.com/easysoft/gitfox/app/services/locker"
+	"github.com/easysoft/gitfox/app/store"
+	"github.com/easysoft/gitfox/git"
+	"github.com/easysoft/gitfox/job"
+	gitfox_store "github.com/easysoft/gitfox/store"
+	"github.com/easysoft/gitfox/types"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// jobTypeScheduler is the recurring job syncing the due mirrors.
+	jobTypeScheduler = "mirror-sync-scheduler"
+	// jobTypeSync is the job syncing a single mirror on request.
+	jobTypeSync = "mirror-sync"
+
+	jobMaxRetriesSync  = 0
+	jobMaxDurationSync = 30 * time.Minute
+)
+
+var ErrSyncRunning = usererror.Conflict("the mirror is already being synced")
+
+type Service struct {
+	enabled     bool
+	cron        string
+	maxDur      time.Duration
+	numWorkers  int
+	git         git.Interface
+	repoStore   store.RepoStore
+	gitReporter *gitevents.Reporter
+	lfsService  *lfs.Service
+	locker      *locker.Locker
+	scheduler   *job.Scheduler
+}
+
+// Register registers the recurring job syncing the due mirrors.
+func (s *Service) Register(ctx context.Context) error {
+	if !s.enabled {
+		return nil
+	}
+
+	err := s.scheduler.AddRecurring(ctx, jobTypeScheduler, jobTypeScheduler, s.cron, s.maxDur)
+	if err != nil {
+		return fmt.Errorf("failed to register recurring job for mirror sync: %w", err)
+	}
+
+	return nil
+}
+
+// Handle syncs the mirrors whose next sync is due, the mirrors are synced by the workers concurrently.
+func (s *Service) Handle(ctx context.Context, _ string, _ job.ProgressReporter) (string, error) {
+	if !s.enabled {
+		return "", nil
+	}
+
+	mirrors, err := s.repoStore.ListDueMirrors(ctx, time.Now().Unix())
+	if err != nil {
+		return "", fmt.Errorf("failed to list due mirrors: %w", err)
+	}
+	if len(mirrors) == 0 {
+		return "", nil
+	}
+
+	log.Ctx(ctx).Info().Msgf("start syncing %d mirrors", len(mirrors))
+
+	var wg sync.WaitGroup
+	taskCh := make(chan *types.RepositoryMirror)
+	for i := 0; i < s.numWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for mirror := range taskCh {
+				if err := s.Sync(ctx, mirror.RepoID); err != nil {
+					log.Ctx(ctx).Warn().Err(err).Int64("repo_id", mirror.RepoID).Msg("failed to sync mirror")
+				}
+			}
+		}()
+	}
+loop:
+	for _, mirror := range mirrors {
+		select {
+		case <-ctx.Done():
+			break loop
+		case taskCh <- mirror:
+		}
+	}
+	close(taskCh)
+	wg.Wait()
+
+	return "", nil
+}
+
+// Trigger starts the job syncing the mirror of the repository right away.
+func (s *Service) Trigger(ctx context.Context, repo *types.Repository) error {
+	jobUID := syncJobUID(repo.ID)
+
+	progress, err := s.scheduler.GetJobProgress(ctx, jobUID)
+	if err == nil {
+		if !progress.State.IsCompleted() {
+			return ErrSyncRunning
+		}
+		if err = s.scheduler.PurgeJobByUID(ctx, jobUID); err != nil {
+			return err
+		}
+	} else if !errors.Is(err, gitfox_store.ErrResourceNotFound) {
+		return fmt.Errorf("failed to get job progress: %w", err)
+	}
+
+	data, err := json.Marshal(syncInput{RepoID: repo.ID})
+	if err != nil {
+		return fmt.Errorf("failed to marshal job input json: %w", err)
+	}
+	return s.scheduler.RunJob(ctx, job.Definition{
+		UID:        jobUID,
+		Type:       jobTypeSync,
+		MaxRetries: jobMaxRetriesSync,
+		Timeout:    jobMaxDurationSync,
+		Data:       string(data),
+	})
+}
+
+func syncJobUID(repoID int64) string {
+	return jobTypeSync + "-" + strconv.FormatInt(repoID, 10)
+}
+
+type syncInput struct {
+	RepoID int64 `json:"repo_id"`
+}
+
+// syncJob is the job handler syncing a single mirror.
+type syncJob struct {
+	service *Service
+}
+
+func (j *syncJob) Handle(ctx context.Context, data string, _ job.ProgressReporter) (string, error) {
+	var input syncInput
+	if err := json.Unmarshal([]byte(data), &input); err != nil {
+		return "", fmt.Errorf("failed to unmarshal job input json: %w", err)
+	}
+	return "", j.service.Sync(ctx, input.RepoID)
+}
@@ -0,0 +1,181 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package mirror
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/easysoft/gitfox/app/bootstrap"
+	gitevents "github.com/easysoft/gitfox/app/events/git"
+	"github.com/easysoft/gitfox/git"
+	"github.com/easysoft/gitfox/git/hook"
+	"github.com/easysoft/gitfox/types"
+	"github.com/easysoft/gitfox/types/enum"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	refPrefixBranch = "refs/heads/"
+	refPrefixTag    = "refs/tags/"
+)
+
+// Sync fetches the remote of the mirror, reports the changed branches and tags, fetches the lfs objects
+// if enabled and records the result of the sync, the next sync is scheduled by the sync interval.
+func (s *Service) Sync(ctx context.Context, repoID int64) error {
+	unlock, err := s.locker.LockMirrorSync(ctx, repoID, jobMaxDurationSync)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	repo, err := s.repoStore.Find(ctx, repoID)
+	if err != nil {
+		return fmt.Errorf("failed to find repo: %w", err)
+	}
+	if !repo.Mirror || repo.State != enum.RepoStateActive {
+		log.Ctx(ctx).Debug().Msgf("repo %s isn't an active mirror, skip the sync", repo.Path)
+		return nil
+	}
+	mirror, err := s.repoStore.GetMirror(ctx, repo.ID)
+	if err != nil {
+		return fmt.Errorf("failed to find mirror: %w", err)
+	}
+
+	syncErr := s.sync(ctx, repo, mirror)
+
+	var lastError string
+	if syncErr != nil {
+		lastError = syncErr.Error()
+	}
+	if err = s.repoStore.UpdateMirrorSyncStatus(ctx, repo.ID, lastError); err != nil {
+		return fmt.Errorf("failed to update mirror sync status: %w", err)
+	}
+	return syncErr
+}
+
+func (s *Service) sync(ctx context.Context, repo *types.Repository, mirror *types.RepositoryMirror) error {
+	out, err := s.git.MirrorSyncRepository(ctx, &git.MirrorSyncParams{
+		ReadParams: git.CreateReadParams(repo),
+		Prune:      mirror.EnablePrune,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to sync mirror: %w", err)
+	}
+
+	log.Ctx(ctx).Info().Msgf("mirror %s synced with %d references updated", repo.Path, len(out.RefUpdates))
+
+	principalID := bootstrap.NewSystemServiceSession().Principal.ID
+	for _, refUpdate := range out.RefUpdates {
+		s.reportRefUpdate(ctx, repo, principalID, refUpdate)
+	}
+
+	if mirror.LfsEnabled {
+		if err = s.lfsService.FetchMirrorObjects(ctx, repo, out.RemoteURL, principalID); err != nil {
+			return fmt.Errorf("failed to fetch lfs objects: %w", err)
+		}
+	}
+	return nil
+}
+
+// reportRefUpdate reports the git events of the fetched reference like a push does, so the pipelines
+// and webhooks are triggered by the mirrored changes.
+func (s *Service) reportRefUpdate(
+	ctx context.Context,
+	repo *types.Repository,
+	principalID int64,
+	refUpdate hook.ReferenceUpdate,
+) {
+	switch {
+	case strings.HasPrefix(refUpdate.Ref, refPrefixBranch):
+		s.reportBranchUpdate(ctx, repo, principalID, refUpdate)
+	case strings.HasPrefix(refUpdate.Ref, refPrefixTag):
+		s.reportTagUpdate(ctx, repo, principalID, refUpdate)
+	}
+}
+
+func (s *Service) reportBranchUpdate(
+	ctx context.Context,
+	repo *types.Repository,
+	principalID int64,
+	refUpdate hook.ReferenceUpdate,
+) {
+	switch {
+	case refUpdate.Old.IsNil():
+		s.gitReporter.BranchCreated(ctx, &gitevents.BranchCreatedPayload{
+			RepoID:      repo.ID,
+			PrincipalID: principalID,
+			Ref:         refUpdate.Ref,
+			SHA:         refUpdate.New.String(),
+		})
+	case refUpdate.New.IsNil():
+		s.gitReporter.BranchDeleted(ctx, &gitevents.BranchDeletedPayload{
+			RepoID:      repo.ID,
+			PrincipalID: principalID,
+			Ref:         refUpdate.Ref,
+			SHA:         refUpdate.Old.String(),
+		})
+	default:
+		result, err := s.git.IsAncestor(ctx, git.IsAncestorParams{
+			ReadParams:          git.CreateReadParams(repo),
+			AncestorCommitSHA:   refUpdate.Old,
+			DescendantCommitSHA: refUpdate.New,
+		})
+		// the branch is updated already, consider the update as forced if the history can't be checked
+		forced := true
+		if err != nil {
+			log.Ctx(ctx).Warn().Err(err).Str("ref", refUpdate.Ref).Msg("failed to check ancestor")
+		} else {
+			forced = !result.Ancestor
+		}
+
+		s.gitReporter.BranchUpdated(ctx, &gitevents.BranchUpdatedPayload{
+			RepoID:      repo.ID,
+			PrincipalID: principalID,
+			Ref:         refUpdate.Ref,
+			OldSHA:      refUpdate.Old.String(),
+			NewSHA:      refUpdate.New.String(),
+			Forced:      forced,
+		})
+	}
+}
+
+func (s *Service) reportTagUpdate(
+	ctx context.Context,
+	repo *types.Repository,
+	principalID int64,
+	refUpdate hook.ReferenceUpdate,
+) {
+	switch {
+	case refUpdate.Old.IsNil():
+		s.gitReporter.TagCreated(ctx, &gitevents.TagCreatedPayload{
+			RepoID:      repo.ID,
+			PrincipalID: principalID,
+			Ref:         refUpdate.Ref,
+			SHA:         refUpdate.New.String(),
+		})
+	case refUpdate.New.IsNil():
+		s.gitReporter.TagDeleted(ctx, &gitevents.TagDeletedPayload{
+			RepoID:      repo.ID,
+			PrincipalID: principalID,
+			Ref:         refUpdate.Ref,
+			SHA:         refUpdate.Old.String(),
+		})
+	default:
+		s.gitReporter.TagUpdated(ctx, &gitevents.TagUpdatedPayload{
+			RepoID:      repo.ID,
+			PrincipalID: principalID,
+			Ref:         refUpdate.Ref,
+			OldSHA:      refUpdate.Old.String(),
+			NewSHA:      refUpdate.New.String(),
+			// tags can only be force updated
+			Forced: true,
+		})
+	}
+}
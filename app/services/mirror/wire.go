@@ -0,0 +1,56 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package mirror
+
+import (
+	gitevents "github.com/easysoft/gitfox/app/events/git"
+	"github.com/easysoft/gitfox/app/services/lfs"
+	"github.com/easysoft/gitfox/app/services/locker"
+	"github.com/easysoft/gitfox/app/store"
+	"github.com/easysoft/gitfox/git"
+	"github.com/easysoft/gitfox/job"
+	"github.com/easysoft/gitfox/types"
+
+	"github.com/google/wire"
+)
+
+var WireSet = wire.NewSet(
+	ProvideService,
+)
+
+func ProvideService(
+	config *types.Config,
+	git git.Interface,
+	repoStore store.RepoStore,
+	gitReporter *gitevents.Reporter,
+	lfsService *lfs.Service,
+	locker *locker.Locker,
+	scheduler *job.Scheduler,
+	executor *job.Executor,
+) (*Service, error) {
+	service := &Service{
+		enabled:     config.MirrorSync.Enabled,
+		cron:        config.MirrorSync.CRON,
+		maxDur:      config.MirrorSync.MaxDuration,
+		numWorkers:  config.MirrorSync.NumWorkers,
+		git:         git,
+		repoStore:   repoStore,
+		gitReporter: gitReporter,
+		lfsService:  lfsService,
+		locker:      locker,
+		scheduler:   scheduler,
+	}
+
+	if err := executor.Register(jobTypeScheduler, service); err != nil {
+		return nil, err
+	}
+	if err := executor.Register(jobTypeSync, &syncJob{service: service}); err != nil {
+		return nil, err
+	}
+
+	return service, nil
+}
@@ -19,6 +19,8 @@ import (
 	"fmt"
 
 	"github.com/easysoft/gitfox/types"
+
+	"golang.org/x/exp/slices"
 )
 
 const TypeBranch types.RuleType = "branch"
@@ -28,6 +30,7 @@ type Branch struct {
 	Bypass    DefBypass    `json:"bypass"`
 	PullReq   DefPullReq   `json:"pullreq"`
 	Lifecycle DefLifecycle `json:"lifecycle"`
+	Push      DefPush      `json:"push"`
 }
 
 var (
@@ -44,6 +47,14 @@ func (v *Branch) MergeVerify(
 		return out, violations, fmt.Errorf("merge verify error: %w", err)
 	}
 
+	pushOut, pushViolations, err := v.Push.MergeVerify(ctx, in)
+	if err != nil {
+		return out, violations, fmt.Errorf("push merge verify error: %w", err)
+	}
+
+	out.AllowedMethods = intersectSorted(slices.Clone(out.AllowedMethods), pushOut.AllowedMethods)
+	violations = append(violations, pushViolations...)
+
 	bypassable := v.Bypass.matches(ctx, in.Actor, in.IsRepoOwner, in.ResolveUserGroupID)
 	bypassed := in.AllowBypass && bypassable
 	for i := range violations {
@@ -98,6 +109,13 @@ func (v *Branch) RefChangeVerify(
 		return nil, fmt.Errorf("lifecycle error: %w", err)
 	}
 
+	pushViolations, err := v.Push.RefChangeVerify(ctx, in)
+	if err != nil {
+		return nil, fmt.Errorf("push error: %w", err)
+	}
+
+	violations = append(violations, pushViolations...)
+
 	bypassable := v.Bypass.matches(ctx, in.Actor, in.IsRepoOwner, in.ResolveUserGroupID)
 	bypassed := in.AllowBypass && bypassable
 	for i := range violations {
@@ -129,5 +147,9 @@ func (v *Branch) Sanitize() error {
 		return fmt.Errorf("lifecycle: %w", err)
 	}
 
+	if err := v.Push.Sanitize(); err != nil {
+		return fmt.Errorf("push: %w", err)
+	}
+
 	return nil
 }
@@ -34,6 +34,9 @@ type (
 		RefAction          RefAction
 		RefType            RefType
 		RefNames           []string
+		// UnsignedCommits returns the new commits of the branch without a verified signature.
+		// It's nil if the new commits can't be checked, e.g. the ref is changed through the API.
+		UnsignedCommits func(ctx context.Context, branch string) ([]string, error)
 	}
 
 	RefType int
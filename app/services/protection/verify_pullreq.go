@@ -46,6 +46,10 @@ type (
 		Method             enum.MergeMethod
 		CheckResults       []types.CheckResult
 		CodeOwners         *codeowners.Evaluation
+		// UnsignedCommits returns the commits of the pull request without a verified signature.
+		UnsignedCommits func(ctx context.Context) ([]string, error)
+		// ServerSignedMethods are the merge methods producing commits signed by the server.
+		ServerSignedMethods []enum.MergeMethod
 	}
 
 	MergeVerifyOutput struct {
@@ -370,14 +374,6 @@ func (v *DefMerge) Sanitize() error {
 	return nil
 }
 
-type DefPush struct {
-	Block bool `json:"block,omitempty"`
-}
-
-func (v *DefPush) Sanitize() error {
-	return nil
-}
-
 type DefPullReq struct {
 	Approvals    DefApprovals    `json:"approvals"`
 	Comments     DefComments     `json:"comments"`
@@ -0,0 +1,132 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package protection
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/easysoft/gitfox/types"
+	"github.com/easysoft/gitfox/types/enum"
+
+	"golang.org/x/exp/slices"
+)
+
+type DefPush struct {
+	Block bool `json:"block,omitempty"`
+	// RequireSignedCommits rejects the new commits without a verified signature.
+	RequireSignedCommits bool `json:"require_signed_commits,omitempty"`
+}
+
+// ensures that the DefPush type implements Sanitizer and RefChangeVerifier interfaces.
+var (
+	_ Sanitizer         = (*DefPush)(nil)
+	_ RefChangeVerifier = (*DefPush)(nil)
+)
+
+const (
+	codePushRequireSignedCommits  = "push.require_signed_commits"
+	codeMergeRequireSignedCommits = "pullreq.merge.require_signed_commits"
+
+	// maxReportedUnsignedCommits limits the commits listed in a violation message.
+	maxReportedUnsignedCommits = 10
+)
+
+func (v *DefPush) RefChangeVerify(ctx context.Context, in RefChangeVerifyInput) ([]types.RuleViolations, error) {
+	if !v.RequireSignedCommits || in.UnsignedCommits == nil || in.RefAction == RefActionDelete {
+		return nil, nil
+	}
+
+	var violations types.RuleViolations
+
+	for _, branch := range in.RefNames {
+		unsigned, err := in.UnsignedCommits(ctx, branch)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find unsigned commits of branch %q: %w", branch, err)
+		}
+		if len(unsigned) == 0 {
+			continue
+		}
+
+		violations.Addf(codePushRequireSignedCommits,
+			"Commits pushed to branch %q must be signed by a verified key. Unverified commits: %s.",
+			branch, formatCommitSHAs(unsigned))
+	}
+
+	if len(violations.Violations) > 0 {
+		return []types.RuleViolations{violations}, nil
+	}
+
+	return nil, nil
+}
+
+// MergeVerify restricts the merge to the methods producing server signed commits
+// if the pull request contains unsigned commits.
+func (v *DefPush) MergeVerify(
+	ctx context.Context,
+	in MergeVerifyInput,
+) (MergeVerifyOutput, []types.RuleViolations, error) {
+	out := MergeVerifyOutput{AllowedMethods: enum.MergeMethods}
+	if !v.RequireSignedCommits || in.UnsignedCommits == nil {
+		return out, nil, nil
+	}
+
+	unsigned, err := in.UnsignedCommits(ctx)
+	if err != nil {
+		return out, nil, fmt.Errorf("failed to find unsigned commits of the pull request: %w", err)
+	}
+	if len(unsigned) == 0 {
+		return out, nil, nil
+	}
+
+	out.AllowedMethods = make([]enum.MergeMethod, 0, len(in.ServerSignedMethods))
+	for _, method := range enum.MergeMethods {
+		if slices.Contains(in.ServerSignedMethods, method) {
+			out.AllowedMethods = append(out.AllowedMethods, method)
+		}
+	}
+
+	var violations types.RuleViolations
+
+	switch {
+	case len(out.AllowedMethods) == 0:
+		violations.Addf(codeMergeRequireSignedCommits,
+			"The pull request contains commits which aren't signed by a verified key: %s.",
+			formatCommitSHAs(unsigned))
+	case in.Method != "" && !slices.Contains(out.AllowedMethods, in.Method):
+		violations.Addf(codeMergeRequireSignedCommits,
+			"The pull request contains commits which aren't signed by a verified key: %s. "+
+				"Allowed merge strategies are %v.",
+			formatCommitSHAs(unsigned), out.AllowedMethods)
+	}
+
+	if len(violations.Violations) > 0 {
+		return out, []types.RuleViolations{violations}, nil
+	}
+
+	return out, nil, nil
+}
+
+func (v *DefPush) Sanitize() error {
+	return nil
+}
+
+func formatCommitSHAs(commitSHAs []string) string {
+	shortSHAs := make([]string, 0, maxReportedUnsignedCommits)
+	for i, commitSHA := range commitSHAs {
+		if i == maxReportedUnsignedCommits {
+			shortSHAs = append(shortSHAs, fmt.Sprintf("and %d more", len(commitSHAs)-i))
+			break
+		}
+		if len(commitSHA) > 8 {
+			commitSHA = commitSHA[:8]
+		}
+		shortSHAs = append(shortSHAs, commitSHA)
+	}
+	return strings.Join(shortSHAs, ", ")
+}
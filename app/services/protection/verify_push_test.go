@@ -0,0 +1,212 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package protection
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/easysoft/gitfox/types"
+	"github.com/easysoft/gitfox/types/enum"
+)
+
+func TestDefPush_RefChangeVerify(t *testing.T) {
+	const refName = "a"
+	unsigned := func(_ context.Context, _ string) ([]string, error) {
+		return []string{"0123456789abcdef0123456789abcdef01234567"}, nil
+	}
+	signed := func(_ context.Context, _ string) ([]string, error) {
+		return nil, nil
+	}
+	failed := func(_ context.Context, _ string) ([]string, error) {
+		return nil, errors.New("failed")
+	}
+
+	tests := []struct {
+		name      string
+		def       DefPush
+		action    RefAction
+		unsigned  func(ctx context.Context, branch string) ([]string, error)
+		expCodes  []string
+		expParams [][]any
+		expErr    bool
+	}{
+		{
+			name:     "empty",
+			action:   RefActionUpdate,
+			unsigned: unsigned,
+		},
+		{
+			name:   "push.require_signed_commits-no-check",
+			def:    DefPush{RequireSignedCommits: true},
+			action: RefActionUpdate,
+		},
+		{
+			name:     "push.require_signed_commits-signed",
+			def:      DefPush{RequireSignedCommits: true},
+			action:   RefActionUpdate,
+			unsigned: signed,
+		},
+		{
+			name:     "push.require_signed_commits-delete",
+			def:      DefPush{RequireSignedCommits: true},
+			action:   RefActionDelete,
+			unsigned: failed,
+		},
+		{
+			name:      "push.require_signed_commits-fail",
+			def:       DefPush{RequireSignedCommits: true},
+			action:    RefActionCreate,
+			unsigned:  unsigned,
+			expCodes:  []string{"push.require_signed_commits"},
+			expParams: [][]any{{refName, "01234567"}},
+		},
+		{
+			name:     "push.require_signed_commits-error",
+			def:      DefPush{RequireSignedCommits: true},
+			action:   RefActionUpdateForce,
+			unsigned: failed,
+			expErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			in := RefChangeVerifyInput{
+				RefNames:        []string{refName},
+				RefAction:       test.action,
+				RefType:         RefTypeBranch,
+				UnsignedCommits: test.unsigned,
+			}
+
+			violations, err := test.def.RefChangeVerify(context.Background(), in)
+			if test.expErr {
+				if err == nil {
+					t.Error("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("got an error: %s", err.Error())
+				return
+			}
+
+			inspectBranchViolations(t, test.expCodes, test.expParams, violations)
+		})
+	}
+}
+
+func TestDefPush_MergeVerify(t *testing.T) {
+	unsigned := func(_ context.Context) ([]string, error) {
+		return []string{"0123456789abcdef0123456789abcdef01234567"}, nil
+	}
+	signed := func(_ context.Context) ([]string, error) {
+		return nil, nil
+	}
+
+	tests := []struct {
+		name          string
+		def           DefPush
+		in            MergeVerifyInput
+		expCodes      []string
+		expParams     [][]any
+		expAllowedOut []enum.MergeMethod
+	}{
+		{
+			name:          "empty",
+			in:            MergeVerifyInput{UnsignedCommits: unsigned},
+			expAllowedOut: enum.MergeMethods,
+		},
+		{
+			name:          "signed",
+			def:           DefPush{RequireSignedCommits: true},
+			in:            MergeVerifyInput{UnsignedCommits: signed},
+			expAllowedOut: enum.MergeMethods,
+		},
+		{
+			name:          "unsigned-no-server-signed-methods",
+			def:           DefPush{RequireSignedCommits: true},
+			in:            MergeVerifyInput{UnsignedCommits: unsigned},
+			expCodes:      []string{"pullreq.merge.require_signed_commits"},
+			expParams:     [][]any{{"01234567"}},
+			expAllowedOut: []enum.MergeMethod{},
+		},
+		{
+			name: "unsigned-server-signed-method",
+			def:  DefPush{RequireSignedCommits: true},
+			in: MergeVerifyInput{
+				UnsignedCommits:     unsigned,
+				ServerSignedMethods: []enum.MergeMethod{enum.MergeMethodSquash, enum.MergeMethodMerge},
+				Method:              enum.MergeMethodSquash,
+			},
+			expAllowedOut: []enum.MergeMethod{enum.MergeMethodMerge, enum.MergeMethodSquash},
+		},
+		{
+			name: "unsigned-method-not-server-signed",
+			def:  DefPush{RequireSignedCommits: true},
+			in: MergeVerifyInput{
+				UnsignedCommits:     unsigned,
+				ServerSignedMethods: []enum.MergeMethod{enum.MergeMethodSquash},
+				Method:              enum.MergeMethodFastForward,
+			},
+			expCodes: []string{"pullreq.merge.require_signed_commits"},
+			expParams: [][]any{
+				{"01234567", []enum.MergeMethod{enum.MergeMethodSquash}},
+			},
+			expAllowedOut: []enum.MergeMethod{enum.MergeMethodSquash},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			out, violations, err := test.def.MergeVerify(context.Background(), test.in)
+			if err != nil {
+				t.Errorf("got an error: %s", err.Error())
+				return
+			}
+
+			if want, got := test.expAllowedOut, out.AllowedMethods; !reflect.DeepEqual(want, got) {
+				t.Errorf("allowed methods mismatch: want=%v got=%v", want, got)
+			}
+
+			inspectBranchViolations(t, test.expCodes, test.expParams, violations)
+		})
+	}
+}
+
+func TestBranch_RefChangeVerifyPush(t *testing.T) {
+	branch := Branch{
+		Lifecycle: DefLifecycle{UpdateForceForbidden: true},
+		Push:      DefPush{RequireSignedCommits: true},
+	}
+
+	violations, err := branch.RefChangeVerify(context.Background(), RefChangeVerifyInput{
+		Actor:     &types.Principal{ID: 1},
+		RefNames:  []string{"main"},
+		RefAction: RefActionUpdateForce,
+		RefType:   RefTypeBranch,
+		UnsignedCommits: func(_ context.Context, _ string) ([]string, error) {
+			return []string{"0123456789abcdef"}, nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("got an error: %s", err.Error())
+	}
+
+	var codes []string
+	for _, ruleViolations := range violations {
+		for _, violation := range ruleViolations.Violations {
+			codes = append(codes, violation.Code)
+		}
+	}
+
+	if want := []string{"lifecycle.update.force", "push.require_signed_commits"}; !reflect.DeepEqual(want, codes) {
+		t.Errorf("violation codes mismatch: want=%v got=%v", want, codes)
+	}
+}
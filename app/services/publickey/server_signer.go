@@ -0,0 +1,311 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package publickey
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"encoding/pem"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/easysoft/gitfox/app/services/settings"
+	"github.com/easysoft/gitfox/encrypt"
+	"github.com/easysoft/gitfox/errors"
+	"github.com/easysoft/gitfox/types"
+	"github.com/easysoft/gitfox/types/enum"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+	gossh "golang.org/x/crypto/ssh"
+	"golang.org/x/exp/slices"
+)
+
+// ServerSigner signs the commits and tags created by the server with the signing key of the instance.
+// The private key is stored encrypted in the system settings, the objects stay unsigned without a key.
+type ServerSigner struct {
+	settings  *settings.Service
+	encrypter encrypt.Encrypter
+
+	mx     sync.Mutex
+	cached *serverKey
+}
+
+func NewServerSigner(settings *settings.Service, encrypter encrypt.Encrypter) *ServerSigner {
+	return &ServerSigner{
+		settings:  settings,
+		encrypter: encrypter,
+	}
+}
+
+// serverKeySetting is the stored signing key, the private key is encrypted with the encrypter.
+type serverKeySetting struct {
+	types.ServerSigningKey
+	PrivateKey []byte `json:"private_key"`
+}
+
+// serverKey is the signing key parsed from the setting.
+type serverKey struct {
+	encrypted []byte
+	pgpEntity *openpgp.Entity
+	sshSigner gossh.Signer
+}
+
+// Find returns the signing key of the instance, nil is returned if no key is set.
+func (s *ServerSigner) Find(ctx context.Context) (*types.ServerSigningKey, error) {
+	setting, err := s.findSetting(ctx)
+	if err != nil || setting == nil {
+		return nil, err
+	}
+	return &setting.ServerSigningKey, nil
+}
+
+// Save replaces the signing key of the instance with the armored PGP private key or the OpenSSH private key.
+func (s *ServerSigner) Save(
+	ctx context.Context,
+	in *types.ServerSigningKeyInput,
+	principalID int64,
+) (*types.ServerSigningKey, error) {
+	var (
+		info       types.ServerSigningKey
+		privateKey []byte
+		err        error
+	)
+	if IsPGPPrivateKey(in.PrivateKey) {
+		info, privateKey, err = preparePGPSigningKey([]byte(in.PrivateKey), in.Passphrase)
+	} else {
+		info, privateKey, err = prepareSSHSigningKey([]byte(in.PrivateKey), in.Passphrase)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	encrypted, err := s.encrypter.Encrypt(string(privateKey))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt signing key: %w", err)
+	}
+
+	info.CreatedBy = principalID
+	info.Created = time.Now().UnixMilli()
+	if err = s.settings.SystemSet(ctx, settings.KeyServerSigningKey, &serverKeySetting{
+		ServerSigningKey: info,
+		PrivateKey:       encrypted,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to save signing key: %w", err)
+	}
+
+	return &info, nil
+}
+
+// Delete removes the signing key of the instance, the objects created afterwards are unsigned.
+func (s *ServerSigner) Delete(ctx context.Context) error {
+	if err := s.settings.SystemSet(ctx, settings.KeyServerSigningKey, nil); err != nil {
+		return fmt.Errorf("failed to delete signing key: %w", err)
+	}
+	return nil
+}
+
+// Enabled reports whether the instance has a signing key.
+func (s *ServerSigner) Enabled(ctx context.Context) (bool, error) {
+	setting, err := s.findSetting(ctx)
+	return setting != nil, err
+}
+
+// Sign creates the armored signature of the commit or tag payload, nil is returned if no key is set.
+// The PGP signatures are dated with the committer or tagger time, so they are valid at the time of the object.
+func (s *ServerSigner) Sign(ctx context.Context, payload []byte) ([]byte, error) {
+	key, err := s.load(ctx)
+	if err != nil || key == nil {
+		return nil, err
+	}
+
+	return key.sign(payload)
+}
+
+func (key *serverKey) sign(payload []byte) ([]byte, error) {
+	if key.sshSigner != nil {
+		return SignSSH(key.sshSigner, payload)
+	}
+
+	signed := objectTime(payload)
+	config := &packet.Config{
+		DefaultHash: crypto.SHA256,
+		Time:        func() time.Time { return signed },
+	}
+	signature := &bytes.Buffer{}
+	if err := openpgp.ArmoredDetachSign(signature, key.pgpEntity, bytes.NewReader(payload), config); err != nil {
+		return nil, fmt.Errorf("failed to sign with PGP key: %w", err)
+	}
+	return signature.Bytes(), nil
+}
+
+func (s *ServerSigner) findSetting(ctx context.Context) (*serverKeySetting, error) {
+	var setting *serverKeySetting
+	if _, err := s.settings.SystemGet(ctx, settings.KeyServerSigningKey, &setting); err != nil {
+		return nil, fmt.Errorf("failed to find signing key: %w", err)
+	}
+	return setting, nil
+}
+
+// load returns the parsed signing key, the key is parsed again only if the setting changed.
+func (s *ServerSigner) load(ctx context.Context) (*serverKey, error) {
+	setting, err := s.findSetting(ctx)
+	if err != nil || setting == nil {
+		return nil, err
+	}
+
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	if s.cached != nil && bytes.Equal(s.cached.encrypted, setting.PrivateKey) {
+		return s.cached, nil
+	}
+
+	privateKey, err := s.encrypter.Decrypt(setting.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt signing key: %w", err)
+	}
+
+	key, err := parseServerKey(setting.Scheme, []byte(privateKey))
+	if err != nil {
+		return nil, err
+	}
+	key.encrypted = setting.PrivateKey
+
+	s.cached = key
+	return key, nil
+}
+
+// parseServerKey parses the unencrypted private key prepared by preparePGPSigningKey or prepareSSHSigningKey.
+func parseServerKey(scheme enum.PublicKeyScheme, privateKey []byte) (*serverKey, error) {
+	switch scheme {
+	case enum.PublicKeySchemePGP:
+		keyInfo, err := ParsePGPPrivateKey(privateKey, "")
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse PGP signing key: %w", err)
+		}
+		return &serverKey{pgpEntity: keyInfo.Entity}, nil
+	case enum.PublicKeySchemeSSH:
+		signer, err := gossh.ParsePrivateKey(privateKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse SSH signing key: %w", err)
+		}
+		return &serverKey{sshSigner: signer}, nil
+	default:
+		return nil, fmt.Errorf("unknown signing key scheme '%s'", scheme)
+	}
+}
+
+// preparePGPSigningKey returns the info and the decrypted armored private key of the PGP key.
+func preparePGPSigningKey(keyData []byte, passphrase string) (types.ServerSigningKey, []byte, error) {
+	keyInfo, err := ParsePGPPrivateKey(keyData, passphrase)
+	if err != nil {
+		return types.ServerSigningKey{}, nil, err
+	}
+
+	privateKey := &bytes.Buffer{}
+	w, err := armor.Encode(privateKey, openpgp.PrivateKeyType, nil)
+	if err != nil {
+		return types.ServerSigningKey{}, nil, err
+	}
+	if err = keyInfo.Entity.SerializePrivateWithoutSigning(w, nil); err != nil {
+		return types.ServerSigningKey{}, nil, fmt.Errorf("failed to serialize PGP private key: %w", err)
+	}
+	if err = w.Close(); err != nil {
+		return types.ServerSigningKey{}, nil, err
+	}
+
+	publicKey := &bytes.Buffer{}
+	w, err = armor.Encode(publicKey, openpgp.PublicKeyType, nil)
+	if err != nil {
+		return types.ServerSigningKey{}, nil, err
+	}
+	if err = keyInfo.Entity.Serialize(w); err != nil {
+		return types.ServerSigningKey{}, nil, fmt.Errorf("failed to serialize PGP public key: %w", err)
+	}
+	if err = w.Close(); err != nil {
+		return types.ServerSigningKey{}, nil, err
+	}
+
+	return types.ServerSigningKey{
+		Scheme:      enum.PublicKeySchemePGP,
+		Fingerprint: keyInfo.Fingerprint(),
+		KeyIDs:      keyInfo.KeyIDs(),
+		PublicKey:   publicKey.String(),
+	}, privateKey.Bytes(), nil
+}
+
+// prepareSSHSigningKey returns the info and the unencrypted OpenSSH private key of the SSH key.
+func prepareSSHSigningKey(keyData []byte, passphrase string) (types.ServerSigningKey, []byte, error) {
+	var (
+		rawKey any
+		err    error
+	)
+	if passphrase != "" {
+		rawKey, err = gossh.ParseRawPrivateKeyWithPassphrase(keyData, []byte(passphrase))
+	} else {
+		rawKey, err = gossh.ParseRawPrivateKey(keyData)
+	}
+	if err != nil {
+		return types.ServerSigningKey{}, nil, errors.InvalidArgument("invalid SSH private key: %s", err)
+	}
+	// the OpenSSH ed25519 keys are parsed to a pointer, which can't be marshaled
+	if key, ok := rawKey.(*ed25519.PrivateKey); ok {
+		rawKey = *key
+	}
+
+	signer, err := gossh.NewSignerFromKey(rawKey)
+	if err != nil {
+		return types.ServerSigningKey{}, nil, errors.InvalidArgument("invalid SSH private key: %s", err)
+	}
+	if !slices.Contains(AllowedTypes, signer.PublicKey().Type()) {
+		return types.ServerSigningKey{}, nil, errors.InvalidArgument("SSH keys of type %s are not allowed",
+			signer.PublicKey().Type())
+	}
+
+	block, err := gossh.MarshalPrivateKey(rawKey, "")
+	if err != nil {
+		return types.ServerSigningKey{}, nil, errors.InvalidArgument("unsupported SSH private key: %s", err)
+	}
+
+	return types.ServerSigningKey{
+		Scheme:      enum.PublicKeySchemeSSH,
+		Fingerprint: From(signer.PublicKey()).Fingerprint(),
+		PublicKey:   strings.TrimSpace(string(gossh.MarshalAuthorizedKey(signer.PublicKey()))),
+	}, pem.EncodeToMemory(block), nil
+}
+
+// objectTime returns the committer time of the commit or the tagger time of the tag payload.
+func objectTime(payload []byte) time.Time {
+	header, _, _ := bytes.Cut(payload, []byte("\n\n"))
+	for _, line := range bytes.Split(header, []byte("\n")) {
+		identity, ok := bytes.CutPrefix(line, []byte("committer "))
+		if !ok {
+			identity, ok = bytes.CutPrefix(line, []byte("tagger "))
+		}
+		if !ok {
+			continue
+		}
+
+		// the identity ends with the unix timestamp and the timezone, like '1700000000 +0100'
+		fields := bytes.Fields(identity)
+		if len(fields) < 2 {
+			break
+		}
+		if seconds, err := strconv.ParseInt(string(fields[len(fields)-2]), 10, 64); err == nil {
+			return time.Unix(seconds, 0)
+		}
+		break
+	}
+	return time.Now()
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package publickey
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/pem"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/stretchr/testify/require"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+func TestSSHSignature(t *testing.T) {
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	signer, err := gossh.NewSignerFromKey(privateKey)
+	require.NoError(t, err)
+
+	message := []byte("tree 4b825dc642cb6eb9a060e54bf8d69288fbee4904\n\ncommit message\n")
+	armored := sshSign(t, signer, "git", message)
+
+	sig, err := ParseSSHSignature(armored)
+	require.NoError(t, err)
+	require.Equal(t, From(signer.PublicKey()).Fingerprint(), sig.Fingerprint())
+	require.NoError(t, sig.Verify(message))
+	require.Error(t, sig.Verify([]byte("tampered")))
+
+	_, err = ParseSSHSignature(sshSign(t, signer, "file", message))
+	require.Error(t, err, "signatures of other namespaces are rejected")
+}
+
+func TestPGPSignature(t *testing.T) {
+	entity, err := openpgp.NewEntity("Max", "", "max@example.com", nil)
+	require.NoError(t, err)
+
+	publicKey := &bytes.Buffer{}
+	w, err := armor.Encode(publicKey, openpgp.PublicKeyType, nil)
+	require.NoError(t, err)
+	require.NoError(t, entity.Serialize(w))
+	require.NoError(t, w.Close())
+	require.True(t, IsPGP(publicKey.String()))
+
+	key, err := ParsePGP(publicKey.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, "Max <max@example.com>", key.Comment())
+	require.Len(t, key.Fingerprint(), 40)
+	require.Len(t, key.KeyIDs(), 2)
+
+	signature := &bytes.Buffer{}
+	require.NoError(t, openpgp.ArmoredDetachSign(signature, entity, bytes.NewReader([]byte("payload")), nil))
+	keyID, err := PGPSignatureKeyID(signature.Bytes())
+	require.NoError(t, err)
+	require.Contains(t, key.KeyIDs(), keyID)
+
+	_, err = ParsePGP([]byte("-----BEGIN PGP PUBLIC KEY BLOCK-----\n\ninvalid\n-----END PGP PUBLIC KEY BLOCK-----"))
+	require.Error(t, err)
+}
+
+// sshSign creates an armored SSH signature like 'ssh-keygen -Y sign'.
+func sshSign(t *testing.T, signer gossh.Signer, namespace string, message []byte) []byte {
+	h := sha512.Sum512(message)
+	signed := gossh.Marshal(struct {
+		Namespace     string
+		Reserved      string
+		HashAlgorithm string
+		Hash          []byte
+	}{Namespace: namespace, HashAlgorithm: "sha512", Hash: h[:]})
+
+	sig, err := signer.Sign(rand.Reader, append([]byte(sshSigMagic), signed...))
+	require.NoError(t, err)
+
+	blob := gossh.Marshal(struct {
+		Version       uint32
+		PublicKey     []byte
+		Namespace     string
+		Reserved      string
+		HashAlgorithm string
+		Signature     []byte
+	}{
+		Version:       sshSigVersion,
+		PublicKey:     signer.PublicKey().Marshal(),
+		Namespace:     namespace,
+		HashAlgorithm: "sha512",
+		Signature:     gossh.Marshal(sig),
+	})
+
+	return pem.EncodeToMemory(&pem.Block{Type: sshSigPEMType, Bytes: append([]byte(sshSigMagic), blob...)})
+}
@@ -0,0 +1,157 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package publickey
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/pem"
+	"fmt"
+	"hash"
+
+	gossh "golang.org/x/crypto/ssh"
+)
+
+// SSH signatures are described by the PROTOCOL.sshsig of OpenSSH.
+const (
+	sshSigMagic     = "SSHSIG"
+	sshSigVersion   = 1
+	sshSigPEMType   = "SSH SIGNATURE"
+	sshSigNamespace = "git"
+)
+
+// SSHSignature is a parsed armored SSH signature.
+type SSHSignature struct {
+	PublicKey     gossh.PublicKey
+	Namespace     string
+	HashAlgorithm string
+	Signature     *gossh.Signature
+}
+
+// ParseSSHSignature parses an armored SSH signature made with the 'git' namespace.
+func ParseSSHSignature(armored []byte) (*SSHSignature, error) {
+	block, _ := pem.Decode(bytes.TrimSpace(armored))
+	if block == nil || block.Type != sshSigPEMType {
+		return nil, fmt.Errorf("invalid armored SSH signature")
+	}
+	if !bytes.HasPrefix(block.Bytes, []byte(sshSigMagic)) {
+		return nil, fmt.Errorf("invalid SSH signature magic")
+	}
+
+	var blob struct {
+		Version       uint32
+		PublicKey     []byte
+		Namespace     string
+		Reserved      string
+		HashAlgorithm string
+		Signature     []byte
+	}
+	if err := gossh.Unmarshal(block.Bytes[len(sshSigMagic):], &blob); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal SSH signature: %w", err)
+	}
+	if blob.Version != sshSigVersion {
+		return nil, fmt.Errorf("unsupported SSH signature version %d", blob.Version)
+	}
+	if blob.Namespace != sshSigNamespace {
+		return nil, fmt.Errorf("unexpected SSH signature namespace '%s'", blob.Namespace)
+	}
+
+	publicKey, err := gossh.ParsePublicKey(blob.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse SSH signature public key: %w", err)
+	}
+	signature := &gossh.Signature{}
+	if err = gossh.Unmarshal(blob.Signature, signature); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal SSH signature blob: %w", err)
+	}
+
+	return &SSHSignature{
+		PublicKey:     publicKey,
+		Namespace:     blob.Namespace,
+		HashAlgorithm: blob.HashAlgorithm,
+		Signature:     signature,
+	}, nil
+}
+
+// Verify verifies the signature of the message against the public key of the signature.
+func (s *SSHSignature) Verify(message []byte) error {
+	var h hash.Hash
+	switch s.HashAlgorithm {
+	case "sha256":
+		h = sha256.New()
+	case "sha512":
+		h = sha512.New()
+	default:
+		return fmt.Errorf("unsupported SSH signature hash algorithm '%s'", s.HashAlgorithm)
+	}
+	_, _ = h.Write(message)
+
+	return s.PublicKey.Verify(sshSignedData(s.Namespace, s.HashAlgorithm, h.Sum(nil)), s.Signature)
+}
+
+// Fingerprint returns the fingerprint of the public key of the signature.
+func (s *SSHSignature) Fingerprint() string {
+	return From(s.PublicKey).Fingerprint()
+}
+
+// SignSSH creates an armored SSH signature of the message with the 'git' namespace like 'ssh-keygen -Y sign'.
+func SignSSH(signer gossh.Signer, message []byte) ([]byte, error) {
+	const hashAlgorithm = "sha512"
+	h := sha512.Sum512(message)
+	data := sshSignedData(sshSigNamespace, hashAlgorithm, h[:])
+
+	var (
+		signature *gossh.Signature
+		err       error
+	)
+	// the SHA-1 RSA signatures are rejected by OpenSSH, ssh-keygen signs with rsa-sha2-512 too
+	if algorithmSigner, ok := signer.(gossh.AlgorithmSigner); ok && signer.PublicKey().Type() == gossh.KeyAlgoRSA {
+		signature, err = algorithmSigner.SignWithAlgorithm(rand.Reader, data, gossh.KeyAlgoRSASHA512)
+	} else {
+		signature, err = signer.Sign(rand.Reader, data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign with SSH key: %w", err)
+	}
+
+	blob := gossh.Marshal(struct {
+		Version       uint32
+		PublicKey     []byte
+		Namespace     string
+		Reserved      string
+		HashAlgorithm string
+		Signature     []byte
+	}{
+		Version:       sshSigVersion,
+		PublicKey:     signer.PublicKey().Marshal(),
+		Namespace:     sshSigNamespace,
+		HashAlgorithm: hashAlgorithm,
+		Signature:     gossh.Marshal(signature),
+	})
+
+	return pem.EncodeToMemory(&pem.Block{
+		Type:  sshSigPEMType,
+		Bytes: append([]byte(sshSigMagic), blob...),
+	}), nil
+}
+
+// sshSignedData returns the data signed by the SSH signature for the hash of the message.
+func sshSignedData(namespace, hashAlgorithm string, hash []byte) []byte {
+	signed := gossh.Marshal(struct {
+		Namespace     string
+		Reserved      string
+		HashAlgorithm string
+		Hash          []byte
+	}{
+		Namespace:     namespace,
+		HashAlgorithm: hashAlgorithm,
+		Hash:          hash,
+	})
+	return append([]byte(sshSigMagic), signed...)
+}
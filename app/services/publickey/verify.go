@@ -0,0 +1,344 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package publickey
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/easysoft/gitfox/app/store"
+	"github.com/easysoft/gitfox/git"
+	"github.com/easysoft/gitfox/git/sha"
+	"github.com/easysoft/gitfox/types"
+	"github.com/easysoft/gitfox/types/enum"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+	"golang.org/x/exp/slices"
+)
+
+const sshSignatureHeader = "-----BEGIN SSH SIGNATURE-----"
+
+// SignatureVerifier verifies the signatures of commits and annotated tags against the signing keys
+// registered by the users and the signing key of the instance, the results are cached per repository.
+type SignatureVerifier struct {
+	git            git.Interface
+	publicKeyStore store.PublicKeyStore
+	resultStore    store.GitSignatureResultStore
+	pCache         store.PrincipalInfoCache
+	serverSigner   *ServerSigner
+}
+
+func NewSignatureVerifier(
+	git git.Interface,
+	publicKeyStore store.PublicKeyStore,
+	resultStore store.GitSignatureResultStore,
+	pCache store.PrincipalInfoCache,
+	serverSigner *ServerSigner,
+) *SignatureVerifier {
+	return &SignatureVerifier{
+		git:            git,
+		publicKeyStore: publicKeyStore,
+		resultStore:    resultStore,
+		pCache:         pCache,
+		serverSigner:   serverSigner,
+	}
+}
+
+// Verify returns the verification results of the signed commits and annotated tags of the repository,
+// keyed by the object SHA. The unsigned objects have no result.
+func (v *SignatureVerifier) Verify(
+	ctx context.Context,
+	repo *types.Repository,
+	objectSHAs []string,
+) (map[string]*types.GitSignatureResult, error) {
+	return v.verify(ctx, repo, git.CreateReadParams(repo), objectSHAs)
+}
+
+// FindUnsigned returns the commits without a verified signature: the unsigned commits, the malformed or invalid
+// signatures, the unknown keys and the keys of another committer. The objects are read with the alternate
+// object dirs, so the commits in the quarantine of a push can be checked too.
+func (v *SignatureVerifier) FindUnsigned(
+	ctx context.Context,
+	repo *types.Repository,
+	alternateObjectDirs []string,
+	commitSHAs []string,
+) ([]string, error) {
+	readParams := git.CreateReadParams(repo)
+	readParams.AlternateObjectDirs = alternateObjectDirs
+
+	results, err := v.verify(ctx, repo, readParams, commitSHAs)
+	if err != nil {
+		return nil, err
+	}
+
+	var unsigned []string
+	for _, commitSHA := range commitSHAs {
+		result := results[commitSHA]
+		if result == nil || result.Result != enum.GitSignatureVerified {
+			unsigned = append(unsigned, commitSHA)
+		}
+	}
+
+	return unsigned, nil
+}
+
+func (v *SignatureVerifier) verify(
+	ctx context.Context,
+	repo *types.Repository,
+	readParams git.ReadParams,
+	objectSHAs []string,
+) (map[string]*types.GitSignatureResult, error) {
+	cached, err := v.resultStore.Map(ctx, repo.ID, objectSHAs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cached signature results: %w", err)
+	}
+
+	results := make(map[string]*types.GitSignatureResult, len(objectSHAs))
+	for objectSHA := range cached {
+		result := cached[objectSHA]
+		results[objectSHA] = &result
+	}
+
+	missing := make([]sha.SHA, 0, len(objectSHAs))
+	for _, objectSHA := range objectSHAs {
+		if _, ok := results[objectSHA]; ok {
+			continue
+		}
+		s, err := sha.New(objectSHA)
+		if err != nil {
+			continue
+		}
+		missing = append(missing, s)
+	}
+
+	if len(missing) > 0 {
+		created, err := v.verifyObjects(ctx, repo, readParams, missing)
+		if err != nil {
+			return nil, err
+		}
+		if err = v.resultStore.TryCreateAll(ctx, created); err != nil {
+			return nil, fmt.Errorf("failed to cache signature results: %w", err)
+		}
+		for _, result := range created {
+			results[result.ObjectSHA] = result
+		}
+	}
+
+	if err = v.fillSigners(ctx, results); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+func (v *SignatureVerifier) verifyObjects(
+	ctx context.Context,
+	repo *types.Repository,
+	readParams git.ReadParams,
+	objectSHAs []sha.SHA,
+) ([]*types.GitSignatureResult, error) {
+	out, err := v.git.GetSignatures(ctx, &git.GetSignaturesParams{
+		ReadParams: readParams,
+		ObjectSHAs: objectSHAs,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get signatures: %w", err)
+	}
+
+	serverKey, err := v.serverSigner.Find(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UnixMilli()
+	results := make([]*types.GitSignatureResult, len(out.Signatures))
+	for i := range out.Signatures {
+		signature := &out.Signatures[i]
+
+		var result *types.GitSignatureResult
+		if bytes.HasPrefix(signature.Signature, []byte(sshSignatureHeader)) {
+			result, err = v.verifySSH(ctx, signature, serverKey)
+		} else {
+			result, err = v.verifyPGP(ctx, signature, serverKey)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to verify signature of %s: %w", signature.ObjectSHA, err)
+		}
+
+		result.RepoID = repo.ID
+		result.ObjectSHA = signature.ObjectSHA.String()
+		result.Created = now
+		results[i] = result
+	}
+
+	return results, nil
+}
+
+func (v *SignatureVerifier) verifyPGP(
+	ctx context.Context,
+	signature *git.ObjectSignature,
+	serverKey *types.ServerSigningKey,
+) (*types.GitSignatureResult, error) {
+	result := &types.GitSignatureResult{
+		Result:    enum.GitSignatureUnverified,
+		KeyScheme: enum.PublicKeySchemePGP,
+	}
+
+	keyID, err := PGPSignatureKeyID(signature.Signature)
+	if err != nil {
+		// the malformed signatures can't be verified by any key
+		return result, nil
+	}
+	result.KeyID = keyID
+
+	config := &packet.Config{Time: func() time.Time { return signature.Signer.When }}
+	if serverKey != nil && serverKey.Scheme == enum.PublicKeySchemePGP && slices.Contains(serverKey.KeyIDs, keyID) {
+		keyInfo, err := ParsePGP([]byte(serverKey.PublicKey))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse public key of the instance: %w", err)
+		}
+		_, err = openpgp.CheckArmoredDetachedSignature(openpgp.EntityList{keyInfo.Entity},
+			bytes.NewReader(signature.SignedContent), bytes.NewReader(signature.Signature), config)
+		if err == nil {
+			result.Result = enum.GitSignatureVerified
+		}
+		return result, nil
+	}
+
+	keys, err := v.publicKeyStore.ListByKeyIDs(ctx, []string{keyID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list public keys by key id: %w", err)
+	}
+
+	for _, key := range filterSigningKeys(keys, enum.PublicKeySchemePGP) {
+		keyInfo, err := ParsePGP([]byte(key.Content))
+		if err != nil {
+			continue
+		}
+
+		principalID := key.PrincipalID
+		result.PrincipalID = &principalID
+		_, err = openpgp.CheckArmoredDetachedSignature(openpgp.EntityList{keyInfo.Entity},
+			bytes.NewReader(signature.SignedContent), bytes.NewReader(signature.Signature), config)
+		if err != nil {
+			continue
+		}
+
+		return v.verifySigner(ctx, result, key.PrincipalID, signature.Signer.Identity.Email)
+	}
+
+	if result.PrincipalID == nil {
+		result.Result = enum.GitSignatureUnknownKey
+	}
+	return result, nil
+}
+
+func (v *SignatureVerifier) verifySSH(
+	ctx context.Context,
+	signature *git.ObjectSignature,
+	serverKey *types.ServerSigningKey,
+) (*types.GitSignatureResult, error) {
+	result := &types.GitSignatureResult{
+		Result:    enum.GitSignatureUnverified,
+		KeyScheme: enum.PublicKeySchemeSSH,
+	}
+
+	sshSignature, err := ParseSSHSignature(signature.Signature)
+	if err != nil {
+		// the malformed signatures can't be verified by any key
+		return result, nil
+	}
+	result.KeyID = sshSignature.Fingerprint()
+
+	if serverKey != nil && serverKey.Scheme == enum.PublicKeySchemeSSH && serverKey.Fingerprint == result.KeyID {
+		if sshSignature.Verify(signature.SignedContent) == nil {
+			result.Result = enum.GitSignatureVerified
+		}
+		return result, nil
+	}
+
+	keys, err := v.publicKeyStore.ListByFingerprint(ctx, result.KeyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list public keys by fingerprint: %w", err)
+	}
+
+	keyInfo := From(sshSignature.PublicKey)
+	for _, key := range filterSigningKeys(keys, enum.PublicKeySchemeSSH) {
+		if !keyInfo.Matches(key.Content) {
+			continue
+		}
+
+		principalID := key.PrincipalID
+		result.PrincipalID = &principalID
+		if sshSignature.Verify(signature.SignedContent) != nil {
+			return result, nil
+		}
+
+		return v.verifySigner(ctx, result, key.PrincipalID, signature.Signer.Identity.Email)
+	}
+
+	result.Result = enum.GitSignatureUnknownKey
+	return result, nil
+}
+
+// verifySigner marks the signature verified if the committer or tagger is the owner of the key.
+func (v *SignatureVerifier) verifySigner(
+	ctx context.Context,
+	result *types.GitSignatureResult,
+	principalID int64,
+	email string,
+) (*types.GitSignatureResult, error) {
+	principal, err := v.pCache.Get(ctx, principalID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get principal info of the key owner: %w", err)
+	}
+
+	if strings.EqualFold(principal.Email, email) {
+		result.Result = enum.GitSignatureVerified
+	}
+	return result, nil
+}
+
+func (v *SignatureVerifier) fillSigners(ctx context.Context, results map[string]*types.GitSignatureResult) error {
+	principalIDs := make([]int64, 0, len(results))
+	for _, result := range results {
+		if result.PrincipalID != nil {
+			principalIDs = append(principalIDs, *result.PrincipalID)
+		} else {
+			// only the signatures of the instance key are verified without an owner
+			result.ServerSigned = result.Result == enum.GitSignatureVerified
+		}
+	}
+	if len(principalIDs) == 0 {
+		return nil
+	}
+
+	principals, err := v.pCache.Map(ctx, principalIDs)
+	if err != nil {
+		return fmt.Errorf("failed to get principal infos of the signers: %w", err)
+	}
+	for _, result := range results {
+		if result.PrincipalID != nil {
+			result.Signer = principals[*result.PrincipalID]
+		}
+	}
+	return nil
+}
+
+func filterSigningKeys(keys []types.PublicKey, scheme enum.PublicKeyScheme) []types.PublicKey {
+	filtered := make([]types.PublicKey, 0, len(keys))
+	for _, key := range keys {
+		if key.Usage == enum.PublicKeyUsageSign && key.Scheme == scheme {
+			filtered = append(filtered, key)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,222 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package publickey
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/easysoft/gitfox/app/services/settings"
+	appstore "github.com/easysoft/gitfox/app/store"
+	"github.com/easysoft/gitfox/git"
+	"github.com/easysoft/gitfox/git/sha"
+	gitfox_store "github.com/easysoft/gitfox/store"
+	"github.com/easysoft/gitfox/types"
+	"github.com/easysoft/gitfox/types/enum"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignatureVerifier_FindUnsigned(t *testing.T) {
+	owner, err := openpgp.NewEntity("Max", "", "max@example.com", nil)
+	require.NoError(t, err)
+	stranger, err := openpgp.NewEntity("Eve", "", "eve@example.com", nil)
+	require.NoError(t, err)
+
+	ownerKey, err := ParsePGP(armoredPublicKey(t, owner))
+	require.NoError(t, err)
+
+	now := time.Now()
+	payload := testPayload(now)
+	sign := func(entity *openpgp.Entity, content []byte) []byte {
+		signature := &bytes.Buffer{}
+		require.NoError(t, openpgp.ArmoredDetachSign(signature, entity, bytes.NewReader(content), nil))
+		return signature.Bytes()
+	}
+
+	const (
+		verifiedSHA  = "1111111111111111111111111111111111111111"
+		unsignedSHA  = "2222222222222222222222222222222222222222"
+		malformedSHA = "3333333333333333333333333333333333333333"
+		badSHA       = "4444444444444444444444444444444444444444"
+		mismatchSHA  = "5555555555555555555555555555555555555555"
+		unknownSHA   = "6666666666666666666666666666666666666666"
+	)
+
+	signatures := []git.ObjectSignature{
+		{
+			ObjectSHA:     sha.Must(verifiedSHA),
+			Signer:        git.Signature{Identity: git.Identity{Email: "max@example.com"}, When: now},
+			Signature:     sign(owner, payload),
+			SignedContent: payload,
+		},
+		{
+			ObjectSHA:     sha.Must(malformedSHA),
+			Signer:        git.Signature{Identity: git.Identity{Email: "max@example.com"}, When: now},
+			Signature:     []byte("-----BEGIN PGP SIGNATURE-----\n\njunk\n-----END PGP SIGNATURE-----\n"),
+			SignedContent: payload,
+		},
+		{
+			ObjectSHA:     sha.Must(badSHA),
+			Signer:        git.Signature{Identity: git.Identity{Email: "max@example.com"}, When: now},
+			Signature:     sign(owner, []byte("other content")),
+			SignedContent: payload,
+		},
+		{
+			ObjectSHA:     sha.Must(mismatchSHA),
+			Signer:        git.Signature{Identity: git.Identity{Email: "eve@example.com"}, When: now},
+			Signature:     sign(owner, payload),
+			SignedContent: payload,
+		},
+		{
+			ObjectSHA:     sha.Must(unknownSHA),
+			Signer:        git.Signature{Identity: git.Identity{Email: "eve@example.com"}, When: now},
+			Signature:     sign(stranger, payload),
+			SignedContent: payload,
+		},
+	}
+
+	v := NewSignatureVerifier(
+		&fakeSignatureGit{signatures: signatures},
+		&fakePublicKeyStore{keys: []types.PublicKey{{
+			PrincipalID: 1,
+			Usage:       enum.PublicKeyUsageSign,
+			Scheme:      enum.PublicKeySchemePGP,
+			Content:     string(armoredPublicKey(t, owner)),
+			KeyIDs:      ownerKey.KeyIDs(),
+		}}},
+		&fakeSignatureResultStore{},
+		&fakePrincipalInfoCache{principals: map[int64]*types.PrincipalInfo{
+			1: {ID: 1, Email: "max@example.com"},
+		}},
+		NewServerSigner(settings.NewService(&fakeSettingsStore{}, nil), nil),
+	)
+
+	commitSHAs := []string{verifiedSHA, unsignedSHA, malformedSHA, badSHA, mismatchSHA, unknownSHA}
+	unsigned, err := v.FindUnsigned(context.Background(), &types.Repository{ID: 1}, nil, commitSHAs)
+	require.NoError(t, err)
+	require.Equal(t, []string{unsignedSHA, malformedSHA, badSHA, mismatchSHA, unknownSHA}, unsigned)
+
+	results, err := v.Verify(context.Background(), &types.Repository{ID: 1}, commitSHAs)
+	require.NoError(t, err)
+	require.Equal(t, enum.GitSignatureVerified, results[verifiedSHA].Result)
+	require.Nil(t, results[unsignedSHA])
+	require.Equal(t, enum.GitSignatureUnverified, results[malformedSHA].Result)
+	require.Equal(t, enum.GitSignatureUnverified, results[badSHA].Result)
+	require.Equal(t, enum.GitSignatureUnverified, results[mismatchSHA].Result)
+	require.Equal(t, enum.GitSignatureUnknownKey, results[unknownSHA].Result)
+}
+
+func armoredPublicKey(t *testing.T, entity *openpgp.Entity) []byte {
+	publicKey := &bytes.Buffer{}
+	w, err := armor.Encode(publicKey, openpgp.PublicKeyType, nil)
+	require.NoError(t, err)
+	require.NoError(t, entity.Serialize(w))
+	require.NoError(t, w.Close())
+	return publicKey.Bytes()
+}
+
+type fakeSignatureGit struct {
+	git.Interface
+	signatures []git.ObjectSignature
+}
+
+func (f *fakeSignatureGit) GetSignatures(
+	_ context.Context,
+	params *git.GetSignaturesParams,
+) (*git.GetSignaturesOutput, error) {
+	out := &git.GetSignaturesOutput{}
+	for _, signature := range f.signatures {
+		for _, objectSHA := range params.ObjectSHAs {
+			if signature.ObjectSHA.Equal(objectSHA) {
+				out.Signatures = append(out.Signatures, signature)
+			}
+		}
+	}
+	return out, nil
+}
+
+type fakePublicKeyStore struct {
+	appstore.PublicKeyStore
+	keys []types.PublicKey
+}
+
+func (f *fakePublicKeyStore) ListByKeyIDs(_ context.Context, keyIDs []string) ([]types.PublicKey, error) {
+	var keys []types.PublicKey
+	for _, key := range f.keys {
+		for _, keyID := range keyIDs {
+			if containsFold(key.KeyIDs, keyID) {
+				keys = append(keys, key)
+				break
+			}
+		}
+	}
+	return keys, nil
+}
+
+func (f *fakePublicKeyStore) ListByFingerprint(context.Context, string) ([]types.PublicKey, error) {
+	return nil, nil
+}
+
+type fakeSignatureResultStore struct {
+	appstore.GitSignatureResultStore
+}
+
+func (f *fakeSignatureResultStore) Map(context.Context, int64, []string) (map[string]types.GitSignatureResult, error) {
+	return map[string]types.GitSignatureResult{}, nil
+}
+
+func (f *fakeSignatureResultStore) TryCreateAll(context.Context, []*types.GitSignatureResult) error {
+	return nil
+}
+
+type fakePrincipalInfoCache struct {
+	principals map[int64]*types.PrincipalInfo
+}
+
+func (f *fakePrincipalInfoCache) Stats() (int64, int64) { return 0, 0 }
+
+func (f *fakePrincipalInfoCache) Get(_ context.Context, id int64) (*types.PrincipalInfo, error) {
+	principal, ok := f.principals[id]
+	if !ok {
+		return nil, gitfox_store.ErrResourceNotFound
+	}
+	return principal, nil
+}
+
+func (f *fakePrincipalInfoCache) Map(_ context.Context, ids []int64) (map[int64]*types.PrincipalInfo, error) {
+	principals := make(map[int64]*types.PrincipalInfo, len(ids))
+	for _, id := range ids {
+		if principal, ok := f.principals[id]; ok {
+			principals[id] = principal
+		}
+	}
+	return principals, nil
+}
+
+type fakeSettingsStore struct {
+	appstore.SettingsStore
+}
+
+func (f *fakeSettingsStore) Find(context.Context, enum.SettingsScope, int64, string) (json.RawMessage, error) {
+	return nil, gitfox_store.ErrResourceNotFound
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -15,13 +15,20 @@
 package publickey
 
 import (
+	"github.com/easysoft/gitfox/app/services/settings"
 	"github.com/easysoft/gitfox/app/store"
+	"github.com/easysoft/gitfox/encrypt"
+	"github.com/easysoft/gitfox/git"
+	"github.com/easysoft/gitfox/git/api"
 
 	"github.com/google/wire"
 )
 
 var WireSet = wire.NewSet(
 	ProvidePublicKey,
+	ProvideSignatureVerifier,
+	ProvideServerSigner,
+	ProvideGitSigner,
 )
 
 func ProvidePublicKey(
@@ -30,3 +37,25 @@ func ProvidePublicKey(
 ) Service {
 	return NewService(publicKeyStore, pCache)
 }
+
+func ProvideSignatureVerifier(
+	git git.Interface,
+	publicKeyStore store.PublicKeyStore,
+	resultStore store.GitSignatureResultStore,
+	pCache store.PrincipalInfoCache,
+	serverSigner *ServerSigner,
+) *SignatureVerifier {
+	return NewSignatureVerifier(git, publicKeyStore, resultStore, pCache, serverSigner)
+}
+
+func ProvideServerSigner(
+	settings *settings.Service,
+	encrypter encrypt.Encrypter,
+) *ServerSigner {
+	return NewServerSigner(settings, encrypter)
+}
+
+// ProvideGitSigner provides the signer of the objects created by the git service.
+func ProvideGitSigner(serverSigner *ServerSigner) api.Signer {
+	return serverSigner
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package pushmirror
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	gitevents "github.com/easysoft/gitfox/app/events/git"
+	"github.com/easysoft/gitfox/events"
+)
+
+func (s *Service) handleEventBranchCreated(ctx context.Context,
+	event *events.Event[*gitevents.BranchCreatedPayload]) error {
+	return s.pushOnPush(ctx, event.Payload.RepoID)
+}
+
+func (s *Service) handleEventBranchUpdated(ctx context.Context,
+	event *events.Event[*gitevents.BranchUpdatedPayload]) error {
+	return s.pushOnPush(ctx, event.Payload.RepoID)
+}
+
+func (s *Service) handleEventBranchDeleted(ctx context.Context,
+	event *events.Event[*gitevents.BranchDeletedPayload]) error {
+	return s.pushOnPush(ctx, event.Payload.RepoID)
+}
+
+func (s *Service) handleEventTagCreated(ctx context.Context,
+	event *events.Event[*gitevents.TagCreatedPayload]) error {
+	return s.pushOnPush(ctx, event.Payload.RepoID)
+}
+
+func (s *Service) handleEventTagUpdated(ctx context.Context,
+	event *events.Event[*gitevents.TagUpdatedPayload]) error {
+	return s.pushOnPush(ctx, event.Payload.RepoID)
+}
+
+func (s *Service) handleEventTagDeleted(ctx context.Context,
+	event *events.Event[*gitevents.TagDeletedPayload]) error {
+	return s.pushOnPush(ctx, event.Payload.RepoID)
+}
+
+// pushOnPush triggers the mirrors of the repository pushed on push, a single push of the mirror
+// covers all refs updated by the same git push. The push running already may miss the updated refs,
+// so the mirror is marked due and pushed again by the scheduler.
+func (s *Service) pushOnPush(ctx context.Context, repoID int64) error {
+	mirrors, err := s.pushMirrorStore.List(ctx, repoID)
+	if err != nil {
+		return fmt.Errorf("failed to list push mirrors: %w", err)
+	}
+
+	for _, mirror := range mirrors {
+		if !mirror.Enabled || !mirror.SyncOnPush {
+			continue
+		}
+		err = s.Trigger(ctx, mirror)
+		if errors.Is(err, ErrPushRunning) {
+			err = s.pushMirrorStore.MarkDue(ctx, mirror.ID, time.Now().Unix())
+		}
+		if err != nil {
+			return fmt.Errorf("failed to trigger push mirror %d: %w", mirror.ID, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,356 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package pushmirror
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	apiauth "github.com/easysoft/gitfox/app/api/auth"
+	"github.com/easysoft/gitfox/app/api/usererror"
+	"github.com/easysoft/gitfox/app/auth"
+	gitfox_errors "github.com/easysoft/gitfox/errors"
+	"github.com/easysoft/gitfox/git"
+	"github.com/easysoft/gitfox/git/api"
+	gitenum "github.com/easysoft/gitfox/git/enum"
+	gitfox_store "github.com/easysoft/gitfox/store"
+	"github.com/easysoft/gitfox/types"
+	"github.com/easysoft/gitfox/types/enum"
+
+	"github.com/guregu/null"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	refPrefixBranch = "refs/heads/"
+	refPrefixTag    = "refs/tags/"
+
+	// defaultTokenUsername is sent with the access token of the connector which has no username
+	defaultTokenUsername = "oauth2"
+)
+
+// Push pushes the branches matching the filter and all tags of the repository to the remote of
+// the mirror, the result of the push is recorded to the mirror.
+func (s *Service) Push(ctx context.Context, pushMirrorID int64) error {
+	mirror, err := s.pushMirrorStore.Find(ctx, pushMirrorID)
+	if errors.Is(err, gitfox_store.ErrResourceNotFound) {
+		log.Ctx(ctx).Debug().Msgf("push mirror %d is deleted, skip the push", pushMirrorID)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to find push mirror: %w", err)
+	}
+	if !mirror.Enabled {
+		return nil
+	}
+
+	repo, err := s.repoStore.Find(ctx, mirror.RepoID)
+	if err != nil {
+		return fmt.Errorf("failed to find repo: %w", err)
+	}
+	if repo.State != enum.RepoStateActive || repo.IsEmpty {
+		log.Ctx(ctx).Debug().Msgf("repo %s isn't active or is empty, skip the push", repo.Path)
+		return nil
+	}
+
+	attempt := time.Now().Unix()
+	pushErr := s.push(ctx, repo, mirror)
+
+	var lastError string
+	if pushErr != nil {
+		// the error of git contains the remote url
+		lastError = api.SanitizeCredentialURLs(pushErr.Error())
+	}
+	if err = s.pushMirrorStore.UpdateSyncStatus(ctx, mirror.ID, attempt, lastError); err != nil {
+		return fmt.Errorf("failed to update push mirror status: %w", err)
+	}
+	if pushErr != nil {
+		return errors.New(lastError)
+	}
+	return nil
+}
+
+func (s *Service) push(ctx context.Context, repo *types.Repository, mirror *types.PushMirror) error {
+	if err := s.checkCredentialsBy(ctx, mirror); err != nil {
+		return err
+	}
+
+	remoteURL, err := s.remoteURL(ctx, mirror)
+	if err != nil {
+		return err
+	}
+
+	readParams := git.CreateReadParams(repo)
+	refSpecs := make([]string, 0)
+	for _, spec := range RefSpecs(mirror.BranchFilter, mirror.Force) {
+		// the push fails if the branch named exactly by the filter doesn't exist
+		if branch, ok := exactBranch(spec); ok {
+			_, err = s.git.GetRef(ctx, git.GetRefParams{ReadParams: readParams, Name: branch, Type: gitenum.RefTypeBranch})
+			if gitfox_errors.IsNotFound(err) {
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("failed to get branch %s: %w", branch, err)
+			}
+		}
+		refSpecs = append(refSpecs, spec)
+	}
+
+	err = s.git.PushRemote(ctx, &git.PushRemoteParams{
+		ReadParams: readParams,
+		RemoteURL:  remoteURL,
+		RefSpecs:   refSpecs,
+		Prune:      mirror.Prune,
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Ctx(ctx).Info().Msgf("repo %s is pushed to mirror %d", repo.Path, mirror.ID)
+	return nil
+}
+
+// RefSpecs returns the refspecs pushed to the mirror, the branches matching the filter and all tags
+// are pushed, the remote refs are overwritten with force.
+func RefSpecs(branchFilter string, force bool) []string {
+	prefix := ""
+	if force {
+		prefix = "+"
+	}
+
+	patterns := splitBranchFilter(branchFilter)
+	if len(patterns) == 0 {
+		patterns = []string{"*"}
+	}
+	specs := make([]string, 0, len(patterns)+1)
+	for _, p := range patterns {
+		specs = append(specs, prefix+refPrefixBranch+p+":"+refPrefixBranch+p)
+	}
+	return append(specs, prefix+refPrefixTag+"*:"+refPrefixTag+"*")
+}
+
+func exactBranch(spec string) (string, bool) {
+	src, _, _ := strings.Cut(strings.TrimPrefix(spec, "+"), ":")
+	branch, ok := strings.CutPrefix(src, refPrefixBranch)
+	if !ok || strings.Contains(branch, "*") {
+		return "", false
+	}
+	return branch, true
+}
+
+func splitBranchFilter(branchFilter string) []string {
+	patterns := make([]string, 0)
+	for _, p := range strings.Split(branchFilter, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			patterns = append(patterns, p)
+		}
+	}
+	return patterns
+}
+
+// SanitizeBranchFilter validates the branch filter and removes the spaces, the patterns are the branch
+// names with at most one '*' matching any characters.
+func SanitizeBranchFilter(branchFilter string) (string, error) {
+	patterns := splitBranchFilter(branchFilter)
+	for _, p := range patterns {
+		if strings.Count(p, "*") > 1 {
+			return "", usererror.BadRequestf("branch pattern %q has more than one '*'", p)
+		}
+		if strings.ContainsAny(p, " :^~?[\\") || strings.Contains(p, "..") ||
+			strings.HasPrefix(p, "/") || strings.HasSuffix(p, "/") || strings.HasPrefix(p, "-") {
+			return "", usererror.BadRequestf("branch pattern %q is invalid", p)
+		}
+	}
+	return strings.Join(patterns, ","), nil
+}
+
+// SanitizeURL validates the url of the remote, the credentials are provided by the secret or the connector.
+func SanitizeURL(remoteURL string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(remoteURL))
+	if err != nil || u.Host == "" {
+		return "", usererror.BadRequest("remote url is invalid")
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", usererror.BadRequest("remote url must be a http or https url")
+	}
+	if u.User != nil {
+		return "", usererror.BadRequest("remote url must not contain credentials, use a secret or connector instead")
+	}
+	return u.String(), nil
+}
+
+// ApplyCredentials sets the secret or the connector in the space providing the credentials of the mirror,
+// the credential isn't changed if the identifier is nil and is removed if the identifier is empty.
+func (s *Service) ApplyCredentials(ctx context.Context, spaceID int64, mirror *types.PushMirror,
+	secret, connector *string,
+) error {
+	if secret != nil {
+		mirror.SecretID = null.Int{}
+		if *secret != "" {
+			res, err := s.secretStore.FindByIdentifier(ctx, spaceID, *secret)
+			if err != nil {
+				return err
+			}
+			mirror.SecretID = null.IntFrom(res.ID)
+		}
+	}
+	if connector != nil {
+		mirror.ConnectorID = null.Int{}
+		if *connector != "" {
+			res, err := s.connectorStore.FindByIdentifier(ctx, spaceID, *connector)
+			if err != nil {
+				return err
+			}
+			mirror.ConnectorID = null.IntFrom(res.ID)
+		}
+	}
+
+	if mirror.SecretID.Valid && mirror.ConnectorID.Valid {
+		return usererror.BadRequest("either secret or connector is allowed")
+	}
+	return nil
+}
+
+// AuthorizeCredentials checks the principal of the session can use the secret or the connector of the mirror.
+// The credentials are sent to the url of the mirror, so they're authorized whenever either of them changes.
+func (s *Service) AuthorizeCredentials(ctx context.Context, session *auth.Session, mirror *types.PushMirror) error {
+	if mirror.SecretID.Valid {
+		secret, err := s.secretStore.Find(ctx, mirror.SecretID.Int64)
+		if err != nil {
+			return fmt.Errorf("failed to find push mirror secret: %w", err)
+		}
+		space, err := s.spaceStore.Find(ctx, secret.SpaceID)
+		if err != nil {
+			return fmt.Errorf("failed to find space of push mirror secret: %w", err)
+		}
+		return apiauth.CheckSecret(ctx, s.authorizer, session, space.Path, secret.Identifier,
+			enum.PermissionSecretAccess)
+	}
+
+	if mirror.ConnectorID.Valid {
+		connector, err := s.connectorStore.Find(ctx, mirror.ConnectorID.Int64)
+		if err != nil {
+			return fmt.Errorf("failed to find push mirror connector: %w", err)
+		}
+		space, err := s.spaceStore.Find(ctx, connector.SpaceID)
+		if err != nil {
+			return fmt.Errorf("failed to find space of push mirror connector: %w", err)
+		}
+		return apiauth.CheckConnector(ctx, s.authorizer, session, space.Path, connector.Identifier,
+			enum.PermissionConnectorAccess)
+	}
+
+	return nil
+}
+
+// checkCredentialsBy checks the principal authorized the credentials of the mirror still has the access
+// to them, the mirror isn't pushed with the credentials the principal is no longer allowed to use.
+func (s *Service) checkCredentialsBy(ctx context.Context, mirror *types.PushMirror) error {
+	if !mirror.SecretID.Valid && !mirror.ConnectorID.Valid {
+		return nil
+	}
+	if !mirror.CredentialsBy.Valid {
+		return errors.New("the credentials of the push mirror aren't authorized")
+	}
+
+	principal, err := s.principalStore.Find(ctx, mirror.CredentialsBy.Int64)
+	if err != nil {
+		return fmt.Errorf("failed to find principal authorized the push mirror credentials: %w", err)
+	}
+	if err = s.AuthorizeCredentials(ctx, &auth.Session{Principal: *principal}, mirror); err != nil {
+		return fmt.Errorf("%s is not allowed to use the push mirror credentials: %w", principal.UID, err)
+	}
+	return nil
+}
+
+// PopulateCredentials fills the identifiers of the secret and the connector of the mirror.
+func (s *Service) PopulateCredentials(ctx context.Context, mirror *types.PushMirror) {
+	if mirror.SecretID.Valid {
+		if secret, err := s.secretStore.Find(ctx, mirror.SecretID.Int64); err == nil {
+			mirror.Secret = secret.Identifier
+		}
+	}
+	if mirror.ConnectorID.Valid {
+		if connector, err := s.connectorStore.Find(ctx, mirror.ConnectorID.Int64); err == nil {
+			mirror.Connector = connector.Identifier
+		}
+	}
+}
+
+// remoteURL returns the url of the remote with the credentials of the mirror.
+func (s *Service) remoteURL(ctx context.Context, mirror *types.PushMirror) (string, error) {
+	username, password, err := s.credentials(ctx, mirror)
+	if err != nil {
+		return "", err
+	}
+
+	u, err := url.Parse(mirror.URL)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse remote url: %w", err)
+	}
+	if password != "" {
+		if username == "" {
+			username = defaultTokenUsername
+		}
+		u.User = url.UserPassword(username, password)
+	} else if username != "" {
+		u.User = url.User(username)
+	}
+	return u.String(), nil
+}
+
+func (s *Service) credentials(ctx context.Context, mirror *types.PushMirror) (string, string, error) {
+	username := mirror.Username
+
+	if mirror.SecretID.Valid {
+		secret, err := s.secretStore.Find(ctx, mirror.SecretID.Int64)
+		if err != nil {
+			return "", "", fmt.Errorf("find push mirror secret failed: %w", err)
+		}
+		password, err := s.encrypter.Decrypt([]byte(secret.Data))
+		if err != nil {
+			return "", "", fmt.Errorf("decrypt push mirror secret failed: %w", err)
+		}
+		return username, password, nil
+	}
+
+	if mirror.ConnectorID.Valid {
+		connector, err := s.connectorStore.Find(ctx, mirror.ConnectorID.Int64)
+		if err != nil {
+			return "", "", fmt.Errorf("find push mirror connector failed: %w", err)
+		}
+		if connector.Github == nil || connector.Github.Auth == nil {
+			return "", "", fmt.Errorf("connector %s has no credentials", connector.Identifier)
+		}
+
+		connectorAuth := connector.Github.Auth
+		var ref types.SecretRef
+		switch {
+		case connectorAuth.Basic != nil:
+			username = connectorAuth.Basic.Username
+			ref = connectorAuth.Basic.Password
+		case connectorAuth.Bearer != nil:
+			ref = connectorAuth.Bearer.Token
+		}
+
+		secret, err := s.secretStore.FindByIdentifier(ctx, connector.SpaceID, ref.Identifier)
+		if err != nil {
+			return "", "", fmt.Errorf("find connector secret failed: %w", err)
+		}
+		password, err := s.encrypter.Decrypt([]byte(secret.Data))
+		if err != nil {
+			return "", "", fmt.Errorf("decrypt connector secret failed: %w", err)
+		}
+		return username, password, nil
+	}
+
+	return username, "", nil
+}
@@ -0,0 +1,151 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+// Package pushmirror replicates the repositories to the external remotes, the mirrors are pushed
+// after the repository receives a push and by the schedule of the mirror.
+package pushmirror
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/easysoft/gitfox/app/api/usererror"
+	"github.com/easysoft/gitfox/app/auth/authz"
+	"github.com/easysoft/gitfox/app/store"
+	"github.com/easysoft/gitfox/encrypt"
+	"github.com/easysoft/gitfox/git"
+	"github.com/easysoft/gitfox/job"
+	gitfox_store "github.com/easysoft/gitfox/store"
+	"github.com/easysoft/gitfox/types"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	groupGitEvents = "gitfox:pushmirror"
+
+	// jobTypeScheduler is the recurring job pushing the due mirrors.
+	jobTypeScheduler = "push-mirror-scheduler"
+	// jobTypePush is the job pushing a single mirror, the failed push is retried by the job.
+	jobTypePush = "push-mirror"
+)
+
+var ErrPushRunning = usererror.Conflict("the mirror is already being pushed")
+
+type Service struct {
+	enabled         bool
+	cron            string
+	maxDur          time.Duration
+	maxRetries      int
+	git             git.Interface
+	repoStore       store.RepoStore
+	pushMirrorStore store.PushMirrorStore
+	secretStore     store.SecretStore
+	connectorStore  store.ConnectorStore
+	spaceStore      store.SpaceStore
+	principalStore  store.PrincipalStore
+	authorizer      authz.Authorizer
+	encrypter       encrypt.Encrypter
+	scheduler       *job.Scheduler
+}
+
+// Register registers the recurring job pushing the due mirrors.
+func (s *Service) Register(ctx context.Context) error {
+	if !s.enabled {
+		return nil
+	}
+
+	err := s.scheduler.AddRecurring(ctx, jobTypeScheduler, jobTypeScheduler, s.cron, s.maxDur)
+	if err != nil {
+		return fmt.Errorf("failed to register recurring job for push mirrors: %w", err)
+	}
+
+	return nil
+}
+
+// Handle starts the push jobs of the mirrors whose next push is due, the mirrors being pushed are
+// skipped and stay due.
+func (s *Service) Handle(ctx context.Context, _ string, _ job.ProgressReporter) (string, error) {
+	if !s.enabled {
+		return "", nil
+	}
+
+	mirrors, err := s.pushMirrorStore.ListDue(ctx, time.Now().Unix())
+	if err != nil {
+		return "", fmt.Errorf("failed to list due push mirrors: %w", err)
+	}
+
+	for _, mirror := range mirrors {
+		if err = s.Trigger(ctx, mirror); err != nil && !errors.Is(err, ErrPushRunning) {
+			log.Ctx(ctx).Warn().Err(err).Int64("push_mirror_id", mirror.ID).Msg("failed to trigger push mirror")
+		}
+	}
+
+	return "", nil
+}
+
+// Trigger starts the job pushing the mirror, nothing is done if the job of the mirror is waiting
+// to run already. ErrPushRunning is returned if the mirror is being pushed right now.
+func (s *Service) Trigger(ctx context.Context, mirror *types.PushMirror) error {
+	jobUID := pushJobUID(mirror.ID)
+
+	progress, err := s.scheduler.GetJobProgress(ctx, jobUID)
+	if err == nil {
+		switch {
+		case progress.State == job.JobStateScheduled:
+			return nil
+		case !progress.State.IsCompleted():
+			return ErrPushRunning
+		}
+		if err = s.scheduler.PurgeJobByUID(ctx, jobUID); err != nil {
+			return err
+		}
+	} else if !errors.Is(err, gitfox_store.ErrResourceNotFound) {
+		return fmt.Errorf("failed to get job progress: %w", err)
+	}
+
+	data, err := json.Marshal(pushInput{PushMirrorID: mirror.ID})
+	if err != nil {
+		return fmt.Errorf("failed to marshal job input json: %w", err)
+	}
+	err = s.scheduler.RunJob(ctx, job.Definition{
+		UID:        jobUID,
+		Type:       jobTypePush,
+		MaxRetries: s.maxRetries,
+		Timeout:    s.maxDur,
+		Data:       string(data),
+	})
+	if errors.Is(err, gitfox_store.ErrDuplicate) {
+		// the job is started concurrently
+		return nil
+	}
+	return err
+}
+
+func pushJobUID(pushMirrorID int64) string {
+	return jobTypePush + "-" + strconv.FormatInt(pushMirrorID, 10)
+}
+
+type pushInput struct {
+	PushMirrorID int64 `json:"push_mirror_id"`
+}
+
+// pushJob is the job handler pushing a single mirror.
+type pushJob struct {
+	service *Service
+}
+
+func (j *pushJob) Handle(ctx context.Context, data string, _ job.ProgressReporter) (string, error) {
+	var input pushInput
+	if err := json.Unmarshal([]byte(data), &input); err != nil {
+		return "", fmt.Errorf("failed to unmarshal job input json: %w", err)
+	}
+	return "", j.service.Push(ctx, input.PushMirrorID)
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package pushmirror
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/easysoft/gitfox/app/auth/authz"
+	gitevents "github.com/easysoft/gitfox/app/events/git"
+	"github.com/easysoft/gitfox/app/store"
+	"github.com/easysoft/gitfox/encrypt"
+	"github.com/easysoft/gitfox/events"
+	"github.com/easysoft/gitfox/git"
+	"github.com/easysoft/gitfox/job"
+	"github.com/easysoft/gitfox/stream"
+	"github.com/easysoft/gitfox/types"
+
+	"github.com/google/wire"
+)
+
+var WireSet = wire.NewSet(
+	ProvideService,
+)
+
+func ProvideService(
+	ctx context.Context,
+	config *types.Config,
+	git git.Interface,
+	repoStore store.RepoStore,
+	pushMirrorStore store.PushMirrorStore,
+	secretStore store.SecretStore,
+	connectorStore store.ConnectorStore,
+	spaceStore store.SpaceStore,
+	principalStore store.PrincipalStore,
+	authorizer authz.Authorizer,
+	encrypter encrypt.Encrypter,
+	gitReaderFactory *events.ReaderFactory[*gitevents.Reader],
+	scheduler *job.Scheduler,
+	executor *job.Executor,
+) (*Service, error) {
+	service := &Service{
+		enabled:         config.PushMirror.Enabled,
+		cron:            config.PushMirror.CRON,
+		maxDur:          config.PushMirror.MaxDuration,
+		maxRetries:      config.PushMirror.MaxRetries,
+		git:             git,
+		repoStore:       repoStore,
+		pushMirrorStore: pushMirrorStore,
+		secretStore:     secretStore,
+		connectorStore:  connectorStore,
+		spaceStore:      spaceStore,
+		principalStore:  principalStore,
+		authorizer:      authorizer,
+		encrypter:       encrypter,
+		scheduler:       scheduler,
+	}
+
+	if err := executor.Register(jobTypeScheduler, service); err != nil {
+		return nil, err
+	}
+	if err := executor.Register(jobTypePush, &pushJob{service: service}); err != nil {
+		return nil, err
+	}
+
+	if !service.enabled {
+		return service, nil
+	}
+	_, err := gitReaderFactory.Launch(ctx, groupGitEvents, config.InstanceID,
+		func(r *gitevents.Reader) error {
+			const idleTimeout = 1 * time.Minute
+			r.Configure(
+				stream.WithConcurrency(config.PushMirror.Concurrency),
+				stream.WithHandlerOptions(
+					stream.WithIdleTimeout(idleTimeout),
+					stream.WithMaxRetries(config.PushMirror.MaxRetries),
+				))
+
+			_ = r.RegisterBranchCreated(service.handleEventBranchCreated)
+			_ = r.RegisterBranchUpdated(service.handleEventBranchUpdated)
+			_ = r.RegisterBranchDeleted(service.handleEventBranchDeleted)
+			_ = r.RegisterTagCreated(service.handleEventTagCreated)
+			_ = r.RegisterTagUpdated(service.handleEventTagUpdated)
+			_ = r.RegisterTagDeleted(service.handleEventTagDeleted)
+
+			return nil
+		})
+	if err != nil {
+		return nil, fmt.Errorf("failed to launch git event reader for push mirrors: %w", err)
+	}
+
+	return service, nil
+}
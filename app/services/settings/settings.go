@@ -30,4 +30,7 @@ var (
 
 	KeyInstallID     Key = "install_id"
 	DefaultInstallID     = string("")
+
+	// KeyServerSigningKey [object] is the key signing the commits and tags created by the server.
+	KeyServerSigningKey Key = "server_signing_key"
 )
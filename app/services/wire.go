@@ -19,12 +19,15 @@ import (
 	"github.com/easysoft/gitfox/app/services/gitspace"
 	"github.com/easysoft/gitfox/app/services/gitspaceevent"
 	"github.com/easysoft/gitfox/app/services/gitspaceinfraevent"
+	"github.com/easysoft/gitfox/app/services/housekeeping"
 	"github.com/easysoft/gitfox/app/services/infraprovider"
 	"github.com/easysoft/gitfox/app/services/instrument"
 	"github.com/easysoft/gitfox/app/services/keywordsearch"
 	"github.com/easysoft/gitfox/app/services/metric"
+	"github.com/easysoft/gitfox/app/services/mirror"
 	"github.com/easysoft/gitfox/app/services/notification"
 	"github.com/easysoft/gitfox/app/services/pullreq"
+	"github.com/easysoft/gitfox/app/services/pushmirror"
 	"github.com/easysoft/gitfox/app/services/repo"
 	"github.com/easysoft/gitfox/app/services/trigger"
 	"github.com/easysoft/gitfox/app/services/webhook"
@@ -53,6 +56,9 @@ type Services struct {
 	Instrumentation       instrument.Service
 	instrumentConsumer    instrument.Consumer
 	instrumentRepoCounter *instrument.RepositoryCount
+	MirrorSync            *mirror.Service
+	PushMirror            *pushmirror.Service
+	Housekeeping          *housekeeping.Service
 }
 
 type GitspaceServices struct {
@@ -92,6 +98,9 @@ func ProvideServices(
 	instrumentation instrument.Service,
 	instrumentConsumer instrument.Consumer,
 	instrumentRepoCounter *instrument.RepositoryCount,
+	mirrorSync *mirror.Service,
+	pushMirror *pushmirror.Service,
+	housekeepingSvc *housekeeping.Service,
 ) Services {
 	return Services{
 		Webhook:            webhooksSvc,
@@ -109,5 +118,8 @@ func ProvideServices(
 		Instrumentation:       instrumentation,
 		instrumentConsumer:    instrumentConsumer,
 		instrumentRepoCounter: instrumentRepoCounter,
+		MirrorSync:            mirrorSync,
+		PushMirror:            pushMirror,
+		Housekeeping:          housekeepingSvc,
 	}
 }
@@ -294,6 +294,12 @@ type (
 		// ListMulti returns a list of repos in multi space. With "DeletedBeforeOrAt" filter, lists deleted repos.
 		ListMulti(ctx context.Context, parentIDs []int64, opts *types.RepoFilter) ([]*types.Repository, error)
 
+		// CountForks of the repo. With "DeletedBeforeOrAt" filter, counts deleted forks.
+		CountForks(ctx context.Context, repoID int64, opts *types.RepoFilter) (int64, error)
+
+		// ListForks returns a list of forks of the repo. With "DeletedBeforeOrAt" filter, lists deleted forks.
+		ListForks(ctx context.Context, repoID int64, opts *types.RepoFilter) ([]*types.Repository, error)
+
 		GetPublicAccess(ctx context.Context, id int64) (bool, error)
 		SetPublicAccess(ctx context.Context, id int64, isPublic bool) error
 
@@ -301,6 +307,10 @@ type (
 		ListAllMirrorRepo(ctx context.Context) ([]*types.RepositoryMirror, error)
 		GetMirror(ctx context.Context, repoID int64) (*types.RepositoryMirror, error)
 		UpdateMirror(ctx context.Context, opts *types.RepositoryMirror) error
+		// ListDueMirrors lists the mirrors whose next sync is due at the time.
+		ListDueMirrors(ctx context.Context, now int64) ([]*types.RepositoryMirror, error)
+		// UpdateMirrorSyncStatus records the result of the last sync of the mirror.
+		UpdateMirrorSyncStatus(ctx context.Context, repoID int64, syncErr string) error
 	}
 
 	// SettingsStore defines the settings storage.
@@ -1085,6 +1095,14 @@ type (
 		MetaAssets() ArtifactMetaAssetInterface
 		Blobs() ArtifactBlobInterface
 		Nodes() ArtifactTreeNodeInterface
+		Referrers() ArtifactReferrerInterface
+		Upstreams() ArtifactUpstreamInterface
+		RetentionPolicies() ArtifactRetentionPolicyInterface
+		Quotas() ArtifactQuotaInterface
+		Usages() ArtifactUsageInterface
+		Promotions() ArtifactPromotionInterface
+		SigningKeys() ArtifactSigningKeyInterface
+		SbomComponents() ArtifactSbomComponentInterface
 		FindPackages(ctx context.Context, spaceId, viewId int64, filter *types.ArtifactFilter) ([]*types.ArtifactListItem, error)
 		GetVersion(ctx context.Context, spaceId, viewId int64, packageName, groupName, versionName string, format types.ArtifactFormat) (*types.ArtifactVersion, error)
 		GetAsset(ctx context.Context, assetId int64) (*types.ArtifactAssetsRes, error)
@@ -1102,7 +1120,69 @@ type (
 	ArtifactViewInterface interface {
 		Create(ctx context.Context, newObj *types.ArtifactView) error
 		GetDefault(ctx context.Context, spaceId int64) (*types.ArtifactView, error)
+		GetByID(ctx context.Context, viewId int64) (*types.ArtifactView, error)
 		GetByName(ctx context.Context, spaceId int64, name string) (*types.ArtifactView, error)
+		ListByKind(ctx context.Context, spaceId int64, kind enum.ArtifactRepoKind) ([]*types.ArtifactView, error)
+		List(ctx context.Context, spaceId int64) ([]*types.ArtifactView, error)
+		Update(ctx context.Context, view *types.ArtifactView) error
+	}
+
+	ArtifactUpstreamInterface interface {
+		Create(ctx context.Context, newObj *types.ArtifactUpstream) error
+		GetByViewID(ctx context.Context, viewId int64) (*types.ArtifactUpstream, error)
+		Update(ctx context.Context, upObj *types.ArtifactUpstream) error
+	}
+
+	ArtifactRetentionPolicyInterface interface {
+		Create(ctx context.Context, newObj *types.ArtifactRetentionPolicy) error
+		GetByFormat(ctx context.Context, spaceId int64, format types.ArtifactFormat) (*types.ArtifactRetentionPolicy, error)
+		ListBySpace(ctx context.Context, spaceId int64) ([]*types.ArtifactRetentionPolicy, error)
+		ListEnabled(ctx context.Context) ([]*types.ArtifactRetentionPolicy, error)
+		Update(ctx context.Context, upObj *types.ArtifactRetentionPolicy) error
+		DeleteById(ctx context.Context, id int64) error
+	}
+
+	ArtifactSbomComponentInterface interface {
+		ReplaceByVersion(ctx context.Context, versionId int64, components []*types.ArtifactSbomComponent) error
+		ListByVersion(ctx context.Context, versionId int64) ([]*types.ArtifactSbomComponent, error)
+		SearchByName(ctx context.Context, spaceId, viewId int64, name, group string) ([]*types.ArtifactSbomComponentMatch, error)
+	}
+
+	ArtifactQuotaInterface interface {
+		Create(ctx context.Context, newObj *types.ArtifactQuota) error
+		GetBySpace(ctx context.Context, spaceId int64) (*types.ArtifactQuota, error)
+		// GetBySpaceForUpdate finds the quota of the space and locks it for an update (should be called in a tx)
+		GetBySpaceForUpdate(ctx context.Context, spaceId int64) (*types.ArtifactQuota, error)
+		Update(ctx context.Context, upObj *types.ArtifactQuota) error
+		DeleteById(ctx context.Context, id int64) error
+	}
+
+	ArtifactUsageInterface interface {
+		// Get returns zero usage if nothing is counted in the space
+		Get(ctx context.Context, spaceId int64) (*types.ArtifactUsage, error)
+		// Sum returns the total usage of the spaces
+		Sum(ctx context.Context, spaceIds []int64) (*types.ArtifactUsage, error)
+		// Add changes the usage of the space by the deltas, the usage never drops below zero,
+		// a drift below zero is logged
+		Add(ctx context.Context, spaceId int64, size, packages int64) error
+		// AddBlob counts the shared blob in the usage of the space, it's counted once per space
+		AddBlob(ctx context.Context, spaceId int64, digest string, size int64) error
+		// RemoveBlob releases the shared blob from the usage of the space if it's counted
+		RemoveBlob(ctx context.Context, spaceId int64, digest string) error
+		// ListBlobSpaces returns the spaces the shared blob is counted in
+		ListBlobSpaces(ctx context.Context, digest string) ([]int64, error)
+	}
+
+	ArtifactPromotionInterface interface {
+		Create(ctx context.Context, newObj *types.ArtifactPromotion) error
+		// List returns the promotions of the space, the latest first
+		List(ctx context.Context, spaceId int64, page, size int) ([]*types.ArtifactPromotion, error)
+	}
+
+	ArtifactSigningKeyInterface interface {
+		Create(ctx context.Context, newObj *types.ArtifactSigningKey) error
+		GetBySpace(ctx context.Context, spaceId int64) (*types.ArtifactSigningKey, error)
+		Update(ctx context.Context, upObj *types.ArtifactSigningKey) error
 	}
 
 	ArtifactPackageInterface interface {
@@ -1111,11 +1191,18 @@ type (
 		GetByName(ctx context.Context, name, namespace string, ownerId int64, format types.ArtifactFormat) (*types.ArtifactPackage, error)
 		List(ctx context.Context, ownerId int64, includeDeleted bool) ([]*types.ArtifactPackage, error)
 		ListByNamespace(ctx context.Context, ownerId int64, namespace string, format types.ArtifactFormat, recurse, includeDeleted bool) ([]*types.ArtifactPackage, error)
+		// ListOwnerIDs returns the owners having the packages of the format which are not deleted
+		ListOwnerIDs(ctx context.Context, format types.ArtifactFormat) ([]int64, error)
+		// ListNames lists in order the names after the name of the packages not deleted of the owner,
+		// all the names are listed if limit is negative
+		ListNames(ctx context.Context, ownerId int64, format types.ArtifactFormat, after string, limit int) ([]string, error)
 		Update(ctx context.Context, upObj *types.ArtifactPackage) error
 		DeleteById(ctx context.Context, packageId int64) error
 		DeleteByIds(ctx context.Context, packageIds ...int64) (int64, error)
 		SoftDelete(ctx context.Context, pkg *types.ArtifactPackage) error
 		UnDelete(ctx context.Context, pkg *types.ArtifactPackage) error
+		// AddDownloads increases the downloads of the package, the last pulled time never goes back
+		AddDownloads(ctx context.Context, packageId, count, lastPulled int64) error
 		Search(ctx context.Context, options ...SearchOption) ([]*types.ArtifactPackage, error)
 	}
 
@@ -1129,6 +1216,11 @@ type (
 		Find(ctx context.Context, opt types.SearchVersionOption) ([]*types.ArtifactVersion, error)
 		SoftDelete(ctx context.Context, ver *types.ArtifactVersion) error
 		UnDelete(ctx context.Context, ver *types.ArtifactVersion) error
+		// Yank leaves the version out of the index, UnYank adds it back
+		Yank(ctx context.Context, ver *types.ArtifactVersion) error
+		UnYank(ctx context.Context, ver *types.ArtifactVersion) error
+		// AddDownloads increases the downloads of the version, the last pulled time never goes back
+		AddDownloads(ctx context.Context, versionId, count, lastPulled int64) error
 		Search(ctx context.Context, options ...SearchOption) ([]*types.ArtifactVersionInfo, error)
 	}
 
@@ -1143,6 +1235,8 @@ type (
 		Update(ctx context.Context, asset *types.ArtifactAsset) error
 		SoftDeleteById(ctx context.Context, assetId int64) error
 		SoftDeleteExcludeById(ctx context.Context, versionId int64, assetId int64) error
+		// CountByBlob counts the assets refer to the blob, the blob is shared by the promoted versions
+		CountByBlob(ctx context.Context, blobId int64, includeDeleted bool) (int64, error)
 		DeleteById(ctx context.Context, assetId int64) error
 		Search(ctx context.Context, option ...SearchOption) ([]*types.ArtifactAsset, error)
 		SearchExtendBlob(ctx context.Context, options ...SearchOption) ([]*types.ArtifactAssetExtendBlob, error)
@@ -1166,6 +1260,16 @@ type (
 		DeleteById(ctx context.Context, blobId int64) error
 	}
 
+	ArtifactReferrerInterface interface {
+		// Create indexes the referrer, it is ignored if the referrer exists in the repository
+		Create(ctx context.Context, newObj *types.ArtifactReferrer) error
+		ListBySubject(ctx context.Context, viewId int64, repository, subject, artifactType string) ([]*types.ArtifactReferrer, error)
+		List(ctx context.Context) ([]*types.ArtifactReferrer, error)
+		ListByDigest(ctx context.Context, digest string) ([]*types.ArtifactReferrer, error)
+		// DeleteByDigest removes the referrer from the index of the repository in the view
+		DeleteByDigest(ctx context.Context, viewId int64, repository, digest string) error
+	}
+
 	ArtifactTreeNodeInterface interface {
 		Create(ctx context.Context, newObj *types.ArtifactTreeNode) error
 		RecurseCreate(ctx context.Context, obj *types.ArtifactTreeNode) error
@@ -1201,6 +1305,9 @@ type (
 
 		// ListByFingerprint returns public keys given a fingerprint and key usage.
 		ListByFingerprint(ctx context.Context, fingerprint string) ([]types.PublicKey, error)
+
+		// ListByKeyIDs returns the PGP public keys having a primary key or a sub key with one of the IDs.
+		ListByKeyIDs(ctx context.Context, keyIDs []string) ([]types.PublicKey, error)
 	}
 
 	GitspaceEventStore interface {
@@ -1384,4 +1491,72 @@ type (
 		Record(ctx context.Context, aiReq *types.AIRequest) error
 		LastRecord(ctx context.Context, prID int64) (*types.AIRequest, error)
 	}
+
+	LFSObjectStore interface {
+		// Find finds the lfs object of the repository by the oid.
+		Find(ctx context.Context, repoID int64, oid string) (*types.LFSObject, error)
+
+		// FindMany finds the lfs objects of the repository by the oids, the missing objects are skipped.
+		FindMany(ctx context.Context, repoID int64, oids []string) ([]*types.LFSObject, error)
+
+		// Create creates a new lfs object.
+		Create(ctx context.Context, obj *types.LFSObject) error
+
+		// Usage counts the lfs objects and the size stored in the repository.
+		Usage(ctx context.Context, repoID int64) (*types.LFSUsage, error)
+	}
+
+	LFSLockStore interface {
+		// Find finds the lock of the repository by the id.
+		Find(ctx context.Context, repoID, id int64) (*types.LFSLock, error)
+
+		// Create creates a new lock, ErrDuplicate is returned if the path is locked already.
+		Create(ctx context.Context, lock *types.LFSLock) error
+
+		// List lists the locks of the repository matching the filter.
+		List(ctx context.Context, repoID int64, filter *types.LFSLockFilter) ([]*types.LFSLock, error)
+
+		// Delete deletes the lock of the repository by the id.
+		Delete(ctx context.Context, repoID, id int64) error
+	}
+
+	PushMirrorStore interface {
+		// Find finds the push mirror by the id.
+		Find(ctx context.Context, id int64) (*types.PushMirror, error)
+
+		// FindByRepo finds the push mirror of the repository by the id.
+		FindByRepo(ctx context.Context, repoID, id int64) (*types.PushMirror, error)
+
+		// List lists the push mirrors of the repository.
+		List(ctx context.Context, repoID int64) ([]*types.PushMirror, error)
+
+		// ListDue lists the enabled push mirrors whose next push is due at the time.
+		ListDue(ctx context.Context, now int64) ([]*types.PushMirror, error)
+
+		// Create creates a new push mirror.
+		Create(ctx context.Context, mirror *types.PushMirror) error
+
+		// Update updates the settings of the push mirror.
+		Update(ctx context.Context, mirror *types.PushMirror) error
+
+		// Delete deletes the push mirror of the repository by the id.
+		Delete(ctx context.Context, repoID, id int64) error
+
+		// MarkDue schedules the push mirror to be pushed by the scheduler at the time.
+		MarkDue(ctx context.Context, id int64, now int64) error
+
+		// UpdateSyncStatus records the result of the push attempted at the time and schedules the next push.
+		UpdateSyncStatus(ctx context.Context, id int64, attempt int64, syncErr string) error
+	}
+
+	GitSignatureResultStore interface {
+		// Map returns the cached verification results of the objects of the repository, keyed by the object SHA.
+		Map(ctx context.Context, repoID int64, objectSHAs []string) (map[string]types.GitSignatureResult, error)
+
+		// TryCreateAll caches the verification results, the results already cached are left intact.
+		TryCreateAll(ctx context.Context, results []*types.GitSignatureResult) error
+
+		// DeleteByKeyIDs removes the cached verification results of the signatures of the keys.
+		DeleteByKeyIDs(ctx context.Context, keyIDs []string) error
+	}
 )
@@ -31,6 +31,14 @@ type Store struct {
 	metaAssets *metaAssets
 	blobs      *blobs
 	nodes      *treeNode
+	referrers  *referrers
+	upstreams  *upstreams
+	policies   *retentionPolicies
+	quotas     *quotas
+	usages     *usages
+	promotions *promotions
+	keys       *signingKeys
+	components *sbomComponents
 }
 
 func NewStore(db *gorm.DB) *Store {
@@ -44,6 +52,14 @@ func NewStore(db *gorm.DB) *Store {
 		metaAssets: &metaAssets{db: db},
 		blobs:      &blobs{db: db},
 		nodes:      &treeNode{db: db},
+		referrers:  &referrers{db: db},
+		upstreams:  &upstreams{db: db},
+		policies:   &retentionPolicies{db: db},
+		quotas:     &quotas{db: db},
+		usages:     &usages{db: db},
+		promotions: &promotions{db: db},
+		keys:       &signingKeys{db: db},
+		components: &sbomComponents{db: db},
 	}
 }
 
@@ -79,6 +95,38 @@ func (s *Store) Nodes() store.ArtifactTreeNodeInterface {
 	return s.nodes
 }
 
+func (s *Store) Referrers() store.ArtifactReferrerInterface {
+	return s.referrers
+}
+
+func (s *Store) Upstreams() store.ArtifactUpstreamInterface {
+	return s.upstreams
+}
+
+func (s *Store) RetentionPolicies() store.ArtifactRetentionPolicyInterface {
+	return s.policies
+}
+
+func (s *Store) Quotas() store.ArtifactQuotaInterface {
+	return s.quotas
+}
+
+func (s *Store) Usages() store.ArtifactUsageInterface {
+	return s.usages
+}
+
+func (s *Store) Promotions() store.ArtifactPromotionInterface {
+	return s.promotions
+}
+
+func (s *Store) SigningKeys() store.ArtifactSigningKeyInterface {
+	return s.keys
+}
+
+func (s *Store) SbomComponents() store.ArtifactSbomComponentInterface {
+	return s.components
+}
+
 func (s *Store) FindPackages(ctx context.Context, spaceId, viewId int64, filter *types.ArtifactFilter) ([]*types.ArtifactListItem, error) {
 	result := make([]*types.ArtifactListItem, 0)
 
@@ -162,6 +162,18 @@ func (c *assets) SoftDeleteExcludeById(ctx context.Context, versionId int64, ass
 	return nil
 }
 
+func (c *assets) CountByBlob(ctx context.Context, blobId int64, includeDeleted bool) (int64, error) {
+	var count int64
+	stmt := dbtx.GetOrmAccessor(ctx, c.db).Model(&types.ArtifactAsset{}).Where("asset_blob_id = ?", blobId)
+	if !includeDeleted {
+		stmt = stmt.Where("asset_deleted = 0")
+	}
+	if err := stmt.Count(&count).Error; err != nil {
+		return 0, database.ProcessGormSQLErrorf(ctx, err, "exec asset count by blob failed")
+	}
+	return count, nil
+}
+
 func (c *assets) DeleteById(ctx context.Context, assetId int64) error {
 	result := dbtx.GetOrmAccessor(ctx, c.db).Delete(&types.ArtifactAsset{}, assetId)
 	if result.Error != nil {
@@ -0,0 +1,57 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package artifacts
+
+import (
+	"context"
+	"testing"
+
+	"github.com/easysoft/gitfox/store/database/dbtest"
+	"github.com/easysoft/gitfox/types"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestArtifactDownloads(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	t.Parallel()
+	tables := []any{new(types.ArtifactPackage), new(types.ArtifactVersion)}
+	_, gdb := dbtest.New(ctx, t, "artifacts_downloads", tables...)
+	s := NewStore(gdb)
+
+	pkg := &types.ArtifactPackage{OwnerID: 1, Name: "demo", Format: types.ArtifactRawFormat}
+	require.NoError(t, s.Packages().Create(ctx, pkg))
+	ver := &types.ArtifactVersion{PackageID: pkg.ID, ViewID: 1, Version: "1.0.0"}
+	require.NoError(t, s.Versions().Create(ctx, ver))
+
+	require.NoError(t, s.Versions().AddDownloads(ctx, ver.ID, 3, 2000))
+	require.NoError(t, s.Versions().AddDownloads(ctx, ver.ID, 2, 1000))
+	require.NoError(t, s.Packages().AddDownloads(ctx, pkg.ID, 5, 2000))
+
+	obj, err := s.Versions().GetByID(ctx, ver.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(5), obj.Downloads)
+	require.Equal(t, int64(2000), obj.LastPulled)
+
+	// the counters are not overwritten by the updates of the version
+	obj.Downloads = 0
+	obj.Metadata = "{}"
+	require.NoError(t, s.Versions().Update(ctx, obj))
+	obj, err = s.Versions().GetByID(ctx, ver.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(5), obj.Downloads)
+	require.Equal(t, "{}", obj.Metadata)
+
+	dbPkg, err := s.Packages().GetByID(ctx, pkg.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(5), dbPkg.Downloads)
+	require.Equal(t, int64(2000), dbPkg.LastPulled)
+}
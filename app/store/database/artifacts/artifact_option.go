@@ -9,6 +9,8 @@ package artifacts
 import (
 	"time"
 
+	"github.com/easysoft/gitfox/types"
+
 	"gorm.io/gorm"
 )
 
@@ -63,3 +65,18 @@ type VersionWithDeletedBeforeOption struct {
 func (opt VersionWithDeletedBeforeOption) Apply(db *gorm.DB) *gorm.DB {
 	return db.Where("version_deleted > 0").Where("version_deleted < ?", opt.Before.UnixMilli())
 }
+
+type PackageExcludeDeletedOption struct {
+}
+
+func (opt PackageExcludeDeletedOption) Apply(db *gorm.DB) *gorm.DB {
+	return db.Where("package_deleted = 0")
+}
+
+type PackageFormatOption struct {
+	Format types.ArtifactFormat
+}
+
+func (opt PackageFormatOption) Apply(db *gorm.DB) *gorm.DB {
+	return db.Where("package_format = ?", opt.Format)
+}
@@ -96,6 +96,34 @@ func (c *packages) ListByNamespace(ctx context.Context, ownerId int64, namespace
 	return dst, nil
 }
 
+func (c *packages) ListOwnerIDs(ctx context.Context, format types.ArtifactFormat) ([]int64, error) {
+	var dst []int64
+	err := dbtx.GetOrmAccessor(ctx, c.db).Model(&types.ArtifactPackage{}).
+		Where("package_format = ? AND package_deleted = 0", format).
+		Distinct().Order("package_owner_id").Pluck("package_owner_id", &dst).Error
+	if err != nil {
+		return nil, database.ProcessGormSQLErrorf(ctx, err, "failed executing artifact package owner list query")
+	}
+	return dst, nil
+}
+
+func (c *packages) ListNames(ctx context.Context, ownerId int64, format types.ArtifactFormat, after string, limit int) ([]string, error) {
+	stmt := dbtx.GetOrmAccessor(ctx, c.db).Model(&types.ArtifactPackage{}).
+		Where("package_owner_id = ? AND package_format = ? AND package_deleted = 0", ownerId, format)
+	if after != "" {
+		stmt = stmt.Where("package_name > ?", after)
+	}
+	if limit >= 0 {
+		stmt = stmt.Limit(limit)
+	}
+
+	var dst []string
+	if err := stmt.Order("package_name").Pluck("package_name", &dst).Error; err != nil {
+		return nil, database.ProcessGormSQLErrorf(ctx, err, "failed executing artifact package name list query")
+	}
+	return dst, nil
+}
+
 func (c *packages) Update(ctx context.Context, upObj *types.ArtifactPackage) error {
 	err := dbtx.GetOrmAccessor(ctx, c.db).Save(&upObj).Error
 	if err != nil {
@@ -156,3 +184,17 @@ func (c *packages) Search(ctx context.Context, options ...store.SearchOption) ([
 
 	return data, nil
 }
+
+func (c *packages) AddDownloads(ctx context.Context, packageId, count, lastPulled int64) error {
+	err := dbtx.GetOrmAccessor(ctx, c.db).Table("artifact_packages").
+		Where("package_id = ?", packageId).
+		UpdateColumns(map[string]any{
+			"package_downloads": gorm.Expr("package_downloads + ?", count),
+			"package_last_pulled": gorm.Expr(
+				"CASE WHEN package_last_pulled < ? THEN ? ELSE package_last_pulled END", lastPulled, lastPulled),
+		}).Error
+	if err != nil {
+		return database.ProcessGormSQLErrorf(ctx, err, "exec package add downloads failed")
+	}
+	return nil
+}
@@ -37,6 +37,7 @@ func TestArtifactPackage(t *testing.T) {
 		{"AddArtifactPkg", addPkg},
 		{"GetArtifactPkg", getPackage},
 		{"DelArtifactPkg", delPackage},
+		{"ListArtifactPkgNames", listPackageNames},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Cleanup(func() {
@@ -147,3 +148,33 @@ func delPackage(t *testing.T, ctx context.Context, pkg *packages) {
 	err = mockCtl.DeleteById(ctx, a3.ID)
 	require.ErrorContains(t, err, mysql.ErrInvalidConn.Error())
 }
+
+func listPackageNames(t *testing.T, ctx context.Context, pkg *packages) {
+	for _, p := range []*types.ArtifactPackage{
+		{Name: "web", OwnerID: 2, Format: types.ArtifactContainerFormat},
+		{Name: "api", OwnerID: 2, Format: types.ArtifactContainerFormat},
+		{Name: "db", OwnerID: 2, Format: types.ArtifactContainerFormat},
+		{Name: "old", OwnerID: 2, Format: types.ArtifactContainerFormat, Deleted: 1},
+		{Name: "chart", OwnerID: 2, Format: types.ArtifactHelmFormat},
+		{Name: "app", OwnerID: 1, Format: types.ArtifactContainerFormat},
+		{Name: "gone", OwnerID: 3, Format: types.ArtifactContainerFormat, Deleted: 1},
+	} {
+		require.NoError(t, pkg.Create(ctx, p))
+	}
+
+	owners, err := pkg.ListOwnerIDs(ctx, types.ArtifactContainerFormat)
+	require.NoError(t, err)
+	require.Equal(t, []int64{1, 2}, owners)
+
+	names, err := pkg.ListNames(ctx, 2, types.ArtifactContainerFormat, "", -1)
+	require.NoError(t, err)
+	require.Equal(t, []string{"api", "db", "web"}, names)
+
+	names, err = pkg.ListNames(ctx, 2, types.ArtifactContainerFormat, "api", 1)
+	require.NoError(t, err)
+	require.Equal(t, []string{"db"}, names)
+
+	names, err = pkg.ListNames(ctx, 2, types.ArtifactContainerFormat, "web", -1)
+	require.NoError(t, err)
+	require.Empty(t, names)
+}
@@ -0,0 +1,50 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package artifacts
+
+import (
+	"context"
+
+	"github.com/easysoft/gitfox/app/store"
+	"github.com/easysoft/gitfox/store/database"
+	"github.com/easysoft/gitfox/store/database/dbtx"
+	"github.com/easysoft/gitfox/types"
+
+	"gorm.io/gorm"
+)
+
+var _ store.ArtifactPromotionInterface = (*promotions)(nil)
+
+type promotions struct {
+	db *gorm.DB
+}
+
+func (c *promotions) Create(ctx context.Context, newObj *types.ArtifactPromotion) error {
+	if err := validatorEmpty(newObj.SpaceID, newObj.VersionID); err != nil {
+		return err
+	}
+
+	if err := dbtx.GetOrmAccessor(ctx, c.db).Create(newObj).Error; err != nil {
+		return database.ProcessGormSQLErrorf(ctx, err, "artifact promotion create failed")
+	}
+	return nil
+}
+
+func (c *promotions) List(ctx context.Context, spaceId int64, page, size int) ([]*types.ArtifactPromotion, error) {
+	var dst []*types.ArtifactPromotion
+	q := types.ArtifactPromotion{SpaceID: spaceId}
+	err := dbtx.GetOrmAccessor(ctx, c.db).Where(q).
+		Order("promotion_created desc, promotion_id desc").
+		Limit(int(database.Limit(size))).
+		Offset(int(database.Offset(page, size))).
+		Find(&dst).Error
+	if err != nil {
+		return nil, database.ProcessGormSQLErrorf(ctx, err, "exec artifact promotion list failed")
+	}
+
+	return dst, nil
+}
@@ -0,0 +1,126 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package artifacts
+
+import (
+	"context"
+	"testing"
+
+	"github.com/easysoft/gitfox/store/database/dbtest"
+	"github.com/easysoft/gitfox/types"
+
+	"github.com/guregu/null"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArtifactPromotion(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	t.Parallel()
+	tables := []any{new(types.ArtifactPromotion), new(types.ArtifactAsset), new(types.ArtifactView)}
+	_, gdb := dbtest.New(ctx, t, "artifacts_promotion", tables...)
+	artStore := NewStore(gdb)
+
+	for _, tc := range []struct {
+		name string
+		test func(t *testing.T, ctx context.Context, s *Store)
+	}{
+		{"ListPromotions", listPromotions},
+		{"CountAssetsByBlob", countAssetsByBlob},
+		{"UpdateView", updateView},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Cleanup(func() {
+				err := dbtest.ClearTables(t, gdb, tables...)
+				require.NoError(t, err)
+			})
+			tc.test(t, ctx, artStore)
+		})
+		if t.Failed() {
+			break
+		}
+	}
+}
+
+func listPromotions(t *testing.T, ctx context.Context, s *Store) {
+	err := s.Promotions().Create(ctx, &types.ArtifactPromotion{SpaceID: 1})
+	require.ErrorIs(t, err, types.ErrArgsValueEmpty)
+
+	for i, version := range []string{"1.0.0", "1.1.0", "2.0.0"} {
+		require.NoError(t, s.Promotions().Create(ctx, &types.ArtifactPromotion{
+			SpaceID: 1, PackageID: 1, Version: version, Format: types.ArtifactRawFormat,
+			SourceViewID: 1, TargetViewID: 2, VersionID: int64(i + 10),
+		}))
+	}
+	require.NoError(t, s.Promotions().Create(ctx, &types.ArtifactPromotion{
+		SpaceID: 2, PackageID: 2, Version: "1.0.0", Format: types.ArtifactRawFormat,
+		SourceViewID: 3, TargetViewID: 4, VersionID: 20, Move: true,
+	}))
+
+	list, err := s.Promotions().List(ctx, 1, 1, 2)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	require.Equal(t, "2.0.0", list[0].Version)
+	require.Equal(t, "1.1.0", list[1].Version)
+
+	list, err = s.Promotions().List(ctx, 1, 2, 2)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	require.Equal(t, "1.0.0", list[0].Version)
+
+	list, err = s.Promotions().List(ctx, 2, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	require.True(t, list[0].Move)
+}
+
+func countAssetsByBlob(t *testing.T, ctx context.Context, s *Store) {
+	for _, versionId := range []int64{1, 2} {
+		require.NoError(t, s.Assets().Create(ctx, &types.ArtifactAsset{
+			VersionID: null.IntFrom(versionId), Format: types.ArtifactRawFormat,
+			Path: "raw/demo.txt", Kind: types.AssetKindMain, BlobID: 5,
+		}))
+	}
+
+	count, err := s.Assets().CountByBlob(ctx, 5, false)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), count)
+
+	asset, err := s.Assets().GetVersionAsset(ctx, "raw/demo.txt", 1)
+	require.NoError(t, err)
+	require.NoError(t, s.Assets().SoftDeleteById(ctx, asset.ID))
+
+	count, err = s.Assets().CountByBlob(ctx, 5, false)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count)
+	count, err = s.Assets().CountByBlob(ctx, 5, true)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), count)
+}
+
+func updateView(t *testing.T, ctx context.Context, s *Store) {
+	view := &types.ArtifactView{Name: "release", SpaceID: 1}
+	require.NoError(t, s.Views().Create(ctx, view))
+	require.NoError(t, s.Views().Create(ctx, &types.ArtifactView{Name: "default", SpaceID: 1, Default: true}))
+
+	view.Protected = true
+	view.PromoteRoles = "space_owner,contributor"
+	require.NoError(t, s.Views().Update(ctx, view))
+
+	obj, err := s.Views().GetByName(ctx, 1, "release")
+	require.NoError(t, err)
+	require.True(t, obj.Protected)
+	require.Equal(t, "space_owner,contributor", obj.PromoteRoles)
+
+	list, err := s.Views().List(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	require.Equal(t, "release", list[0].Name)
+}
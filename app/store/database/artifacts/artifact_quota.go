@@ -0,0 +1,206 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package artifacts
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/easysoft/gitfox/app/store"
+	gitfox_store "github.com/easysoft/gitfox/store"
+	"github.com/easysoft/gitfox/store/database"
+	"github.com/easysoft/gitfox/store/database/dbtx"
+	"github.com/easysoft/gitfox/types"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var _ store.ArtifactQuotaInterface = (*quotas)(nil)
+var _ store.ArtifactUsageInterface = (*usages)(nil)
+
+type quotas struct {
+	db *gorm.DB
+}
+
+func (c *quotas) Create(ctx context.Context, newObj *types.ArtifactQuota) error {
+	if err := validatorEmpty(newObj.SpaceID); err != nil {
+		return err
+	}
+
+	if err := dbtx.GetOrmAccessor(ctx, c.db).Create(newObj).Error; err != nil {
+		return database.ProcessGormSQLErrorf(ctx, err, "artifact quota create failed")
+	}
+	return nil
+}
+
+func (c *quotas) GetBySpace(ctx context.Context, spaceId int64) (*types.ArtifactQuota, error) {
+	var quota types.ArtifactQuota
+	q := types.ArtifactQuota{SpaceID: spaceId}
+	if err := dbtx.GetOrmAccessor(ctx, c.db).Where(&q).First(&quota).Error; err != nil {
+		return nil, database.ProcessGormSQLErrorf(ctx, err, "query artifact quota failed")
+	}
+	return &quota, nil
+}
+
+func (c *quotas) GetBySpaceForUpdate(ctx context.Context, spaceId int64) (*types.ArtifactQuota, error) {
+	var quota types.ArtifactQuota
+	q := types.ArtifactQuota{SpaceID: spaceId}
+	err := dbtx.GetOrmAccessor(ctx, c.db).Where(&q).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Take(&quota).Error
+	if err != nil {
+		return nil, database.ProcessGormSQLErrorf(ctx, err, "query artifact quota for update failed")
+	}
+	return &quota, nil
+}
+
+func (c *quotas) Update(ctx context.Context, upObj *types.ArtifactQuota) error {
+	if err := dbtx.GetOrmAccessor(ctx, c.db).Save(upObj).Error; err != nil {
+		return database.ProcessGormSQLErrorf(ctx, err, "exec artifact quota update failed")
+	}
+	return nil
+}
+
+func (c *quotas) DeleteById(ctx context.Context, id int64) error {
+	err := dbtx.GetOrmAccessor(ctx, c.db).Delete(&types.ArtifactQuota{}, id).Error
+	if err != nil {
+		return database.ProcessGormSQLErrorf(ctx, err, "delete artifact quota %d failed", id)
+	}
+	return nil
+}
+
+type usages struct {
+	db *gorm.DB
+}
+
+func (c *usages) Get(ctx context.Context, spaceId int64) (*types.ArtifactUsage, error) {
+	var usage types.ArtifactUsage
+	q := types.ArtifactUsage{SpaceID: spaceId}
+	err := dbtx.GetOrmAccessor(ctx, c.db).Where(&q).First(&usage).Error
+	if err == nil {
+		return &usage, nil
+	}
+
+	err = database.ProcessGormSQLErrorf(ctx, err, "query artifact usage failed")
+	if errors.Is(err, gitfox_store.ErrResourceNotFound) {
+		return &types.ArtifactUsage{SpaceID: spaceId}, nil
+	}
+	return nil, err
+}
+
+func (c *usages) Sum(ctx context.Context, spaceIds []int64) (*types.ArtifactUsage, error) {
+	var usage types.ArtifactUsage
+	if len(spaceIds) == 0 {
+		return &usage, nil
+	}
+
+	err := dbtx.GetOrmAccessor(ctx, c.db).Model(&types.ArtifactUsage{}).
+		Select("COALESCE(SUM(usage_size), 0) AS usage_size, COALESCE(SUM(usage_packages), 0) AS usage_packages").
+		Where("usage_space_id IN ?", spaceIds).
+		Scan(&usage).Error
+	if err != nil {
+		return nil, database.ProcessGormSQLErrorf(ctx, err, "query artifact usage sum failed")
+	}
+	return &usage, nil
+}
+
+func (c *usages) Add(ctx context.Context, spaceId int64, size, packages int64) error {
+	if size == 0 && packages == 0 {
+		return nil
+	}
+
+	if size < 0 || packages < 0 {
+		current, err := c.Get(ctx, spaceId)
+		if err != nil {
+			return err
+		}
+		if current.Size+size < 0 || current.Packages+packages < 0 {
+			log.Ctx(ctx).Warn().Msgf("artifact usage of space %d drifted, size %d%+d, packages %d%+d, reset to zero",
+				spaceId, current.Size, size, current.Packages, packages)
+		}
+	}
+
+	newObj := types.ArtifactUsage{
+		SpaceID:  spaceId,
+		Size:     max(size, 0),
+		Packages: max(packages, 0),
+		Updated:  time.Now().UnixMilli(),
+	}
+	err := dbtx.GetOrmAccessor(ctx, c.db).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "usage_space_id"}},
+		DoUpdates: clause.Assignments(map[string]any{
+			"usage_size":     nonNegativeSum("artifact_usages.usage_size", size),
+			"usage_packages": nonNegativeSum("artifact_usages.usage_packages", packages),
+			"usage_updated":  newObj.Updated,
+		}),
+	}).Create(&newObj).Error
+	if err != nil {
+		return database.ProcessGormSQLErrorf(ctx, err, "exec artifact usage update failed")
+	}
+	return nil
+}
+
+func (c *usages) AddBlob(ctx context.Context, spaceId int64, digest string, size int64) error {
+	if err := validatorEmpty(spaceId, digest); err != nil {
+		return err
+	}
+
+	res := dbtx.GetOrmAccessor(ctx, c.db).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "usage_blob_space_id"}, {Name: "usage_blob_digest"}},
+		DoNothing: true,
+	}).Create(&types.ArtifactUsageBlob{SpaceID: spaceId, Digest: digest, Size: size})
+	if res.Error != nil {
+		return database.ProcessGormSQLErrorf(ctx, res.Error, "exec artifact usage blob create failed")
+	}
+	// the blob is counted in the space already
+	if res.RowsAffected == 0 {
+		return nil
+	}
+	return c.Add(ctx, spaceId, size, 0)
+}
+
+func (c *usages) RemoveBlob(ctx context.Context, spaceId int64, digest string) error {
+	var counted types.ArtifactUsageBlob
+	q := types.ArtifactUsageBlob{SpaceID: spaceId, Digest: digest}
+	err := dbtx.GetOrmAccessor(ctx, c.db).Where(&q).First(&counted).Error
+	if err != nil {
+		err = database.ProcessGormSQLErrorf(ctx, err, "query artifact usage blob failed")
+		if errors.Is(err, gitfox_store.ErrResourceNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	res := dbtx.GetOrmAccessor(ctx, c.db).Where(&q).Delete(&types.ArtifactUsageBlob{})
+	if res.Error != nil {
+		return database.ProcessGormSQLErrorf(ctx, res.Error, "delete artifact usage blob %s failed", digest)
+	}
+	// the blob is released concurrently
+	if res.RowsAffected == 0 {
+		return nil
+	}
+	return c.Add(ctx, spaceId, -counted.Size, 0)
+}
+
+func (c *usages) ListBlobSpaces(ctx context.Context, digest string) ([]int64, error) {
+	var dst []int64
+	err := dbtx.GetOrmAccessor(ctx, c.db).Model(&types.ArtifactUsageBlob{}).
+		Where("usage_blob_digest = ?", digest).
+		Order("usage_blob_space_id").
+		Pluck("usage_blob_space_id", &dst).Error
+	if err != nil {
+		return nil, database.ProcessGormSQLErrorf(ctx, err, "failed executing artifact usage blob list query")
+	}
+	return dst, nil
+}
+
+func nonNegativeSum(column string, delta int64) clause.Expr {
+	return gorm.Expr("CASE WHEN "+column+" + ? < 0 THEN 0 ELSE "+column+" + ? END", delta, delta)
+}
@@ -0,0 +1,159 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package artifacts
+
+import (
+	"context"
+	"testing"
+
+	gitfox_store "github.com/easysoft/gitfox/store"
+	"github.com/easysoft/gitfox/store/database/dbtest"
+	"github.com/easysoft/gitfox/types"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestArtifactQuota(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	t.Parallel()
+	tables := []any{new(types.ArtifactQuota), new(types.ArtifactUsage), new(types.ArtifactUsageBlob)}
+	_, gdb := dbtest.New(ctx, t, "artifacts_quota", tables...)
+	artStore := NewStore(gdb)
+
+	for _, tc := range []struct {
+		name string
+		test func(t *testing.T, ctx context.Context, s *Store)
+	}{
+		{"SaveQuota", saveQuota},
+		{"AddUsage", addUsage},
+		{"SumUsage", sumUsage},
+		{"AddUsageBlob", addUsageBlob},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Cleanup(func() {
+				err := dbtest.ClearTables(t, gdb, tables...)
+				require.NoError(t, err)
+			})
+			tc.test(t, ctx, artStore)
+		})
+		if t.Failed() {
+			break
+		}
+	}
+}
+
+func saveQuota(t *testing.T, ctx context.Context, s *Store) {
+	err := s.Quotas().Create(ctx, &types.ArtifactQuota{MaxSize: 1})
+	require.ErrorIs(t, err, types.ErrArgsValueEmpty)
+
+	quota := &types.ArtifactQuota{SpaceID: 1, MaxSize: 1024}
+	require.NoError(t, s.Quotas().Create(ctx, quota))
+	err = s.Quotas().Create(ctx, &types.ArtifactQuota{SpaceID: 1, MaxPackages: 1})
+	require.ErrorIs(t, err, gitfox_store.ErrDuplicate)
+
+	quota.MaxPackages = 10
+	require.NoError(t, s.Quotas().Update(ctx, quota))
+	obj, err := s.Quotas().GetBySpace(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, int64(1024), obj.MaxSize)
+	require.Equal(t, int64(10), obj.MaxPackages)
+
+	obj, err = s.Quotas().GetBySpaceForUpdate(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, quota.ID, obj.ID)
+
+	require.NoError(t, s.Quotas().DeleteById(ctx, quota.ID))
+	_, err = s.Quotas().GetBySpace(ctx, 1)
+	require.ErrorIs(t, err, gitfox_store.ErrResourceNotFound)
+	_, err = s.Quotas().GetBySpaceForUpdate(ctx, 1)
+	require.ErrorIs(t, err, gitfox_store.ErrResourceNotFound)
+}
+
+func addUsage(t *testing.T, ctx context.Context, s *Store) {
+	tests := []struct {
+		size     int64
+		packages int64
+		expected types.ArtifactUsage
+	}{
+		{100, 1, types.ArtifactUsage{SpaceID: 1, Size: 100, Packages: 1}},
+		{50, 0, types.ArtifactUsage{SpaceID: 1, Size: 150, Packages: 1}},
+		{-30, 1, types.ArtifactUsage{SpaceID: 1, Size: 120, Packages: 2}},
+		{-500, -3, types.ArtifactUsage{SpaceID: 1, Size: 0, Packages: 0}},
+	}
+
+	usage, err := s.Usages().Get(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, types.ArtifactUsage{SpaceID: 1}, *usage)
+
+	for _, test := range tests {
+		require.NoError(t, s.Usages().Add(ctx, 1, test.size, test.packages))
+		usage, err = s.Usages().Get(ctx, 1)
+		require.NoError(t, err)
+		usage.Updated = 0
+		require.Equal(t, test.expected, *usage)
+	}
+
+	// the removal in a space without usage counted keeps zero
+	require.NoError(t, s.Usages().Add(ctx, 2, -10, -1))
+	usage, err = s.Usages().Get(ctx, 2)
+	require.NoError(t, err)
+	require.Zero(t, usage.Size)
+	require.Zero(t, usage.Packages)
+}
+
+func sumUsage(t *testing.T, ctx context.Context, s *Store) {
+	require.NoError(t, s.Usages().Add(ctx, 1, 100, 1))
+	require.NoError(t, s.Usages().Add(ctx, 2, 50, 2))
+	require.NoError(t, s.Usages().Add(ctx, 3, 10, 1))
+
+	usage, err := s.Usages().Sum(ctx, []int64{1, 2, 4})
+	require.NoError(t, err)
+	require.Equal(t, int64(150), usage.Size)
+	require.Equal(t, int64(3), usage.Packages)
+
+	usage, err = s.Usages().Sum(ctx, nil)
+	require.NoError(t, err)
+	require.Zero(t, usage.Size)
+}
+
+func addUsageBlob(t *testing.T, ctx context.Context, s *Store) {
+	const dgst = "sha256:6c3c624b58dbbcd3c0dd82b4c53f04194d1247c6eebdaab7c610cf7d66709b3b"
+
+	// the blob is counted once per space
+	require.NoError(t, s.Usages().AddBlob(ctx, 1, dgst, 100))
+	require.NoError(t, s.Usages().AddBlob(ctx, 1, dgst, 100))
+	require.NoError(t, s.Usages().AddBlob(ctx, 2, dgst, 100))
+	usage, err := s.Usages().Get(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, int64(100), usage.Size)
+
+	spaces, err := s.Usages().ListBlobSpaces(ctx, dgst)
+	require.NoError(t, err)
+	require.Equal(t, []int64{1, 2}, spaces)
+
+	// the removal in the other space keeps the blob counted in the space
+	require.NoError(t, s.Usages().RemoveBlob(ctx, 2, dgst))
+	require.NoError(t, s.Usages().RemoveBlob(ctx, 2, dgst))
+	usage, err = s.Usages().Get(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, int64(100), usage.Size)
+	usage, err = s.Usages().Get(ctx, 2)
+	require.NoError(t, err)
+	require.Zero(t, usage.Size)
+
+	require.NoError(t, s.Usages().RemoveBlob(ctx, 1, dgst))
+	usage, err = s.Usages().Get(ctx, 1)
+	require.NoError(t, err)
+	require.Zero(t, usage.Size)
+	spaces, err = s.Usages().ListBlobSpaces(ctx, dgst)
+	require.NoError(t, err)
+	require.Empty(t, spaces)
+}
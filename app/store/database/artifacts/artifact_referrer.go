@@ -0,0 +1,76 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package artifacts
+
+import (
+	"context"
+
+	"github.com/easysoft/gitfox/app/store"
+	"github.com/easysoft/gitfox/store/database"
+	"github.com/easysoft/gitfox/store/database/dbtx"
+	"github.com/easysoft/gitfox/types"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var _ store.ArtifactReferrerInterface = (*referrers)(nil)
+
+type referrers struct {
+	db *gorm.DB
+}
+
+func (c *referrers) Create(ctx context.Context, newObj *types.ArtifactReferrer) error {
+	if err := validatorEmpty(newObj.ViewID, newObj.Repository, newObj.Subject, newObj.Digest); err != nil {
+		return err
+	}
+
+	err := dbtx.GetOrmAccessor(ctx, c.db).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "referrer_view_id"}, {Name: "referrer_repository"}, {Name: "referrer_digest"}},
+		DoNothing: true,
+	}).Create(newObj).Error
+	if err != nil {
+		return database.ProcessGormSQLErrorf(ctx, err, "exec artifact referrer create failed")
+	}
+	return nil
+}
+
+func (c *referrers) ListBySubject(ctx context.Context, viewId int64, repository, subject, artifactType string) ([]*types.ArtifactReferrer, error) {
+	q := types.ArtifactReferrer{ViewID: viewId, Repository: repository, Subject: subject, ArtifactType: artifactType}
+
+	var dst []*types.ArtifactReferrer
+	if err := dbtx.GetOrmAccessor(ctx, c.db).Where(q).Order("referrer_id").Find(&dst).Error; err != nil {
+		return nil, database.ProcessGormSQLErrorf(ctx, err, "failed executing artifact referrer list query")
+	}
+	return dst, nil
+}
+
+func (c *referrers) List(ctx context.Context) ([]*types.ArtifactReferrer, error) {
+	var dst []*types.ArtifactReferrer
+	if err := dbtx.GetOrmAccessor(ctx, c.db).Find(&dst).Error; err != nil {
+		return nil, database.ProcessGormSQLErrorf(ctx, err, "failed executing artifact referrer list query")
+	}
+	return dst, nil
+}
+
+func (c *referrers) ListByDigest(ctx context.Context, digest string) ([]*types.ArtifactReferrer, error) {
+	var dst []*types.ArtifactReferrer
+	if err := dbtx.GetOrmAccessor(ctx, c.db).Where("referrer_digest = ?", digest).Find(&dst).Error; err != nil {
+		return nil, database.ProcessGormSQLErrorf(ctx, err, "failed executing artifact referrer list query")
+	}
+	return dst, nil
+}
+
+func (c *referrers) DeleteByDigest(ctx context.Context, viewId int64, repository, digest string) error {
+	err := dbtx.GetOrmAccessor(ctx, c.db).
+		Where("referrer_view_id = ? AND referrer_repository = ? AND referrer_digest = ?", viewId, repository, digest).
+		Delete(&types.ArtifactReferrer{}).Error
+	if err != nil {
+		return database.ProcessGormSQLErrorf(ctx, err, "delete artifact referrer %s failed", digest)
+	}
+	return nil
+}
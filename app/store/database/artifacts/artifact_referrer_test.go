@@ -0,0 +1,161 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package artifacts
+
+import (
+	"context"
+	"testing"
+
+	"github.com/easysoft/gitfox/store/database/dbtest"
+	"github.com/easysoft/gitfox/types"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testReferrerSubject = "sha256:6c3c624b58dbbcd3c0dd82b4c53f04194d1247c6eebdaab7c610cf7d66709b3b"
+	testReferrerSig     = "sha256:d2a84f4b8b650937ec8f73cd8be2c74add5a911ba64df27458ed8229da804a26"
+	testReferrerSbom    = "sha256:4355a46b19d348dc2f57c046f8ef63d4538ebb936000f3c9ee954a27460dd865"
+)
+
+func TestArtifactReferrer(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	t.Parallel()
+	tables := []any{new(types.ArtifactReferrer)}
+	_, gdb := dbtest.New(ctx, t, "artifacts_referrer", tables...)
+	ctl := &referrers{
+		db: gdb,
+	}
+
+	for _, tc := range []struct {
+		name string
+		test func(t *testing.T, ctx context.Context, ctl *referrers)
+	}{
+		{"AddArtifactReferrer", addReferrer},
+		{"ListArtifactReferrer", listReferrer},
+		{"DelArtifactReferrer", deleteReferrer},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Cleanup(func() {
+				err := dbtest.ClearTables(t, ctl.db, tables...)
+				require.NoError(t, err)
+			})
+			tc.test(t, ctx, ctl)
+		})
+		if t.Failed() {
+			break
+		}
+	}
+}
+
+func addReferrer(t *testing.T, ctx context.Context, ctl *referrers) {
+	tests := []struct {
+		ViewID     int64
+		Repository string
+		Subject    string
+		Digest     string
+		wantErr    bool
+		expectErr  error
+	}{
+		{0, "app", testReferrerSubject, testReferrerSig, true, types.ErrArgsValueEmpty},
+		{1, "", testReferrerSubject, testReferrerSig, true, types.ErrArgsValueEmpty},
+		{1, "app", "", testReferrerSig, true, types.ErrArgsValueEmpty},
+		{1, "app", testReferrerSubject, "", true, types.ErrArgsValueEmpty},
+		{1, "app", testReferrerSubject, testReferrerSig, false, nil},
+		{1, "app", testReferrerSubject, testReferrerSig, false, nil}, // existing referrer is ignored
+		{1, "web", testReferrerSubject, testReferrerSig, false, nil},
+	}
+
+	for _, test := range tests {
+		obj := types.ArtifactReferrer{ViewID: test.ViewID, Repository: test.Repository, Subject: test.Subject, Digest: test.Digest}
+		err := ctl.Create(ctx, &obj)
+		if test.wantErr {
+			require.Error(t, err)
+			if test.expectErr != nil {
+				require.ErrorIs(t, err, test.expectErr)
+			}
+			continue
+		}
+		require.NoError(t, err)
+	}
+
+	objs, err := ctl.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, objs, 2)
+}
+
+func listReferrer(t *testing.T, ctx context.Context, ctl *referrers) {
+	for _, obj := range []*types.ArtifactReferrer{
+		{ViewID: 1, Repository: "app", Subject: testReferrerSubject, Digest: testReferrerSig, ArtifactType: "application/vnd.dev.cosign.artifact.sig.v1+json"},
+		{ViewID: 1, Repository: "app", Subject: testReferrerSubject, Digest: testReferrerSbom, ArtifactType: "application/spdx+json"},
+		{ViewID: 2, Repository: "app", Subject: testReferrerSubject, Digest: testReferrerSbom, ArtifactType: "application/spdx+json"},
+	} {
+		require.NoError(t, ctl.Create(ctx, obj))
+	}
+
+	tests := []struct {
+		name         string
+		viewId       int64
+		repository   string
+		subject      string
+		artifactType string
+		expect       []string
+	}{
+		{"all", 1, "app", testReferrerSubject, "", []string{testReferrerSig, testReferrerSbom}},
+		{"filter artifactType", 1, "app", testReferrerSubject, "application/spdx+json", []string{testReferrerSbom}},
+		{"other view", 2, "app", testReferrerSubject, "", []string{testReferrerSbom}},
+		{"other repository", 1, "web", testReferrerSubject, "", []string{}},
+		{"other subject", 1, "app", testReferrerSig, "", []string{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			objs, err := ctl.ListBySubject(ctx, test.viewId, test.repository, test.subject, test.artifactType)
+			require.NoError(t, err)
+
+			digests := make([]string, 0, len(objs))
+			for _, obj := range objs {
+				digests = append(digests, obj.Digest)
+			}
+			require.Equal(t, test.expect, digests)
+		})
+	}
+}
+
+func deleteReferrer(t *testing.T, ctx context.Context, ctl *referrers) {
+	for _, obj := range []*types.ArtifactReferrer{
+		{ViewID: 1, Repository: "app", Subject: testReferrerSubject, Digest: testReferrerSig},
+		{ViewID: 1, Repository: "web", Subject: testReferrerSubject, Digest: testReferrerSig},
+		{ViewID: 2, Repository: "app", Subject: testReferrerSubject, Digest: testReferrerSig},
+		{ViewID: 1, Repository: "app", Subject: testReferrerSubject, Digest: testReferrerSbom},
+	} {
+		require.NoError(t, ctl.Create(ctx, obj))
+	}
+
+	require.NoError(t, ctl.DeleteByDigest(ctx, 1, "app", testReferrerSig))
+
+	objs, err := ctl.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, objs, 3)
+
+	// the same digest pushed to other repositories and views is kept
+	sigs, err := ctl.ListByDigest(ctx, testReferrerSig)
+	require.NoError(t, err)
+	require.Len(t, sigs, 2)
+	for _, obj := range sigs {
+		require.False(t, obj.ViewID == 1 && obj.Repository == "app")
+	}
+
+	remain, err := ctl.ListBySubject(ctx, 1, "app", testReferrerSubject, "")
+	require.NoError(t, err)
+	require.Len(t, remain, 1)
+	require.Equal(t, testReferrerSbom, remain[0].Digest)
+}
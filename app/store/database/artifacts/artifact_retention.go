@@ -0,0 +1,76 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package artifacts
+
+import (
+	"context"
+
+	"github.com/easysoft/gitfox/app/store"
+	"github.com/easysoft/gitfox/store/database"
+	"github.com/easysoft/gitfox/store/database/dbtx"
+	"github.com/easysoft/gitfox/types"
+
+	"gorm.io/gorm"
+)
+
+var _ store.ArtifactRetentionPolicyInterface = (*retentionPolicies)(nil)
+
+type retentionPolicies struct {
+	db *gorm.DB
+}
+
+func (c *retentionPolicies) Create(ctx context.Context, newObj *types.ArtifactRetentionPolicy) error {
+	if err := validatorEmpty(newObj.SpaceID, newObj.Format); err != nil {
+		return err
+	}
+
+	if err := dbtx.GetOrmAccessor(ctx, c.db).Create(newObj).Error; err != nil {
+		return database.ProcessGormSQLErrorf(ctx, err, "artifact retention policy create failed")
+	}
+	return nil
+}
+
+func (c *retentionPolicies) GetByFormat(ctx context.Context, spaceId int64, format types.ArtifactFormat) (*types.ArtifactRetentionPolicy, error) {
+	var policy types.ArtifactRetentionPolicy
+	q := types.ArtifactRetentionPolicy{SpaceID: spaceId, Format: format}
+	if err := dbtx.GetOrmAccessor(ctx, c.db).Where(&q).First(&policy).Error; err != nil {
+		return nil, database.ProcessGormSQLErrorf(ctx, err, "query artifact retention policy failed")
+	}
+	return &policy, nil
+}
+
+func (c *retentionPolicies) ListBySpace(ctx context.Context, spaceId int64) ([]*types.ArtifactRetentionPolicy, error) {
+	var data []*types.ArtifactRetentionPolicy
+	q := types.ArtifactRetentionPolicy{SpaceID: spaceId}
+	if err := dbtx.GetOrmAccessor(ctx, c.db).Where(&q).Order("policy_format").Find(&data).Error; err != nil {
+		return nil, database.ProcessGormSQLErrorf(ctx, err, "list artifact retention policies failed")
+	}
+	return data, nil
+}
+
+func (c *retentionPolicies) ListEnabled(ctx context.Context) ([]*types.ArtifactRetentionPolicy, error) {
+	var data []*types.ArtifactRetentionPolicy
+	if err := dbtx.GetOrmAccessor(ctx, c.db).Where("policy_enabled = ?", true).Order("policy_id").Find(&data).Error; err != nil {
+		return nil, database.ProcessGormSQLErrorf(ctx, err, "list enabled artifact retention policies failed")
+	}
+	return data, nil
+}
+
+func (c *retentionPolicies) Update(ctx context.Context, upObj *types.ArtifactRetentionPolicy) error {
+	if err := dbtx.GetOrmAccessor(ctx, c.db).Save(upObj).Error; err != nil {
+		return database.ProcessGormSQLErrorf(ctx, err, "exec artifact retention policy update failed")
+	}
+	return nil
+}
+
+func (c *retentionPolicies) DeleteById(ctx context.Context, id int64) error {
+	err := dbtx.GetOrmAccessor(ctx, c.db).Delete(&types.ArtifactRetentionPolicy{}, id).Error
+	if err != nil {
+		return database.ProcessGormSQLErrorf(ctx, err, "delete artifact retention policy %d failed", id)
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package artifacts
+
+import (
+	"context"
+	"testing"
+
+	gitfox_store "github.com/easysoft/gitfox/store"
+	"github.com/easysoft/gitfox/store/database/dbtest"
+	"github.com/easysoft/gitfox/types"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestArtifactRetentionPolicy(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	t.Parallel()
+	tables := []any{new(types.ArtifactRetentionPolicy)}
+	_, gdb := dbtest.New(ctx, t, "artifacts_retention", tables...)
+	ctl := &retentionPolicies{
+		db: gdb,
+	}
+
+	for _, tc := range []struct {
+		name string
+		test func(t *testing.T, ctx context.Context, ctl *retentionPolicies)
+	}{
+		{"AddRetentionPolicy", addRetentionPolicy},
+		{"ListRetentionPolicies", listRetentionPolicies},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Cleanup(func() {
+				err := dbtest.ClearTables(t, ctl.db, tables...)
+				require.NoError(t, err)
+			})
+			tc.test(t, ctx, ctl)
+		})
+		if t.Failed() {
+			break
+		}
+	}
+}
+
+func addRetentionPolicy(t *testing.T, ctx context.Context, ctl *retentionPolicies) {
+	tests := []struct {
+		SpaceID   int64
+		Format    types.ArtifactFormat
+		wantErr   bool
+		expectErr error
+	}{
+		{0, types.ArtifactHelmFormat, true, types.ErrArgsValueEmpty},
+		{1, "", true, types.ErrArgsValueEmpty},
+		{1, types.ArtifactHelmFormat, false, nil},
+		{1, types.ArtifactHelmFormat, true, nil}, // unique key for space and format
+		{1, types.ArtifactContainerFormat, false, nil},
+	}
+
+	for _, test := range tests {
+		obj := types.ArtifactRetentionPolicy{SpaceID: test.SpaceID, Format: test.Format, KeepLast: 3}
+		err := ctl.Create(ctx, &obj)
+		if test.wantErr {
+			require.Error(t, err)
+			if test.expectErr != nil {
+				require.ErrorIs(t, err, test.expectErr)
+			}
+			continue
+		}
+		require.NoError(t, err)
+		require.NotZero(t, obj.ID)
+	}
+
+	got, err := ctl.GetByFormat(ctx, 1, types.ArtifactHelmFormat)
+	require.NoError(t, err)
+	require.Equal(t, 3, got.KeepLast)
+
+	require.NoError(t, ctl.DeleteById(ctx, got.ID))
+	_, err = ctl.GetByFormat(ctx, 1, types.ArtifactHelmFormat)
+	require.ErrorIs(t, err, gitfox_store.ErrResourceNotFound)
+}
+
+func listRetentionPolicies(t *testing.T, ctx context.Context, ctl *retentionPolicies) {
+	for _, obj := range []*types.ArtifactRetentionPolicy{
+		{SpaceID: 1, Format: types.ArtifactRawFormat, Enabled: true},
+		{SpaceID: 1, Format: types.ArtifactHelmFormat},
+		{SpaceID: 2, Format: types.ArtifactHelmFormat, Enabled: true},
+	} {
+		require.NoError(t, ctl.Create(ctx, obj))
+	}
+
+	policies, err := ctl.ListBySpace(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, policies, 2)
+	require.Equal(t, types.ArtifactHelmFormat, policies[0].Format)
+
+	enabled, err := ctl.ListEnabled(ctx)
+	require.NoError(t, err)
+	require.Len(t, enabled, 2)
+
+	policies[0].Enabled = true
+	require.NoError(t, ctl.Update(ctx, policies[0]))
+	enabled, err = ctl.ListEnabled(ctx)
+	require.NoError(t, err)
+	require.Len(t, enabled, 3)
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package artifacts
+
+import (
+	"context"
+
+	"github.com/easysoft/gitfox/app/store"
+	"github.com/easysoft/gitfox/store/database"
+	"github.com/easysoft/gitfox/store/database/dbtx"
+	"github.com/easysoft/gitfox/types"
+
+	"gorm.io/gorm"
+)
+
+var _ store.ArtifactSbomComponentInterface = (*sbomComponents)(nil)
+
+const sbomComponentBatchSize = 200
+
+type sbomComponents struct {
+	db *gorm.DB
+}
+
+// ReplaceByVersion replaces the components of the version with the ones in the latest sbom.
+func (c *sbomComponents) ReplaceByVersion(ctx context.Context, versionId int64, components []*types.ArtifactSbomComponent) error {
+	if err := validatorEmpty(versionId); err != nil {
+		return err
+	}
+
+	db := dbtx.GetOrmAccessor(ctx, c.db)
+	q := types.ArtifactSbomComponent{VersionID: versionId}
+	if err := db.Where(&q).Delete(&types.ArtifactSbomComponent{}).Error; err != nil {
+		return database.ProcessGormSQLErrorf(ctx, err, "delete sbom components of version %d failed", versionId)
+	}
+
+	if len(components) == 0 {
+		return nil
+	}
+	for _, comp := range components {
+		comp.ID = 0
+		comp.VersionID = versionId
+	}
+	if err := db.CreateInBatches(components, sbomComponentBatchSize).Error; err != nil {
+		return database.ProcessGormSQLErrorf(ctx, err, "create sbom components of version %d failed", versionId)
+	}
+	return nil
+}
+
+func (c *sbomComponents) ListByVersion(ctx context.Context, versionId int64) ([]*types.ArtifactSbomComponent, error) {
+	var data []*types.ArtifactSbomComponent
+	q := types.ArtifactSbomComponent{VersionID: versionId}
+	err := dbtx.GetOrmAccessor(ctx, c.db).Where(&q).
+		Order("component_name").Order("component_version").Find(&data).Error
+	if err != nil {
+		return nil, database.ProcessGormSQLErrorf(ctx, err, "list sbom components failed")
+	}
+	return data, nil
+}
+
+// SearchByName finds the alive package versions in the view which contain the component.
+func (c *sbomComponents) SearchByName(ctx context.Context, spaceId, viewId int64, name, group string,
+) ([]*types.ArtifactSbomComponentMatch, error) {
+	result := make([]*types.ArtifactSbomComponentMatch, 0)
+
+	stmt := dbtx.GetOrmAccessor(ctx, c.db).Model(&types.ArtifactSbomComponent{}).
+		Select(`artifact_packages.package_format, artifact_packages.package_name, artifact_packages.package_namespace,
+			artifact_versions.version, artifact_versions.version_id, artifact_versions.version_view_id,
+			artifact_sbom_components.component_name, artifact_sbom_components.component_group,
+			artifact_sbom_components.component_version, artifact_sbom_components.component_purl`).
+		Joins(`JOIN artifact_versions
+			ON artifact_sbom_components.component_version_id = artifact_versions.version_id
+				AND artifact_versions.version_deleted = 0`).
+		Joins(`JOIN artifact_packages
+			ON artifact_versions.version_package_id = artifact_packages.package_id
+				AND artifact_packages.package_deleted = 0`).
+		Where("artifact_packages.package_owner_id = ?", spaceId).
+		Where("artifact_versions.version_view_id = ?", viewId).
+		Where("artifact_sbom_components.component_name = ?", name)
+
+	if group != "" {
+		stmt = stmt.Where("artifact_sbom_components.component_group = ?", group)
+	}
+
+	err := stmt.Order("artifact_packages.package_name").Order("artifact_versions.version").Find(&result).Error
+	if err != nil {
+		return nil, database.ProcessGormSQLErrorf(ctx, err, "search sbom components failed")
+	}
+	return result, nil
+}
@@ -0,0 +1,118 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package artifacts
+
+import (
+	"context"
+	"testing"
+
+	"github.com/easysoft/gitfox/store/database/dbtest"
+	"github.com/easysoft/gitfox/types"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestArtifactSbomComponent(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	t.Parallel()
+	tables := []any{new(types.ArtifactPackage), new(types.ArtifactVersion), new(types.ArtifactSbomComponent)}
+	_, gdb := dbtest.New(ctx, t, "artifacts_sbom", tables...)
+	ctl := &sbomComponents{
+		db: gdb,
+	}
+
+	for _, tc := range []struct {
+		name string
+		test func(t *testing.T, ctx context.Context, ctl *sbomComponents)
+	}{
+		{"ReplaceSbomComponents", replaceSbomComponents},
+		{"SearchSbomComponents", searchSbomComponents},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Cleanup(func() {
+				err := dbtest.ClearTables(t, ctl.db, tables...)
+				require.NoError(t, err)
+			})
+			tc.test(t, ctx, ctl)
+		})
+		if t.Failed() {
+			break
+		}
+	}
+}
+
+func replaceSbomComponents(t *testing.T, ctx context.Context, ctl *sbomComponents) {
+	require.ErrorIs(t, ctl.ReplaceByVersion(ctx, 0, nil), types.ErrArgsValueEmpty)
+
+	require.NoError(t, ctl.ReplaceByVersion(ctx, 1, []*types.ArtifactSbomComponent{
+		{Name: "log4j-core", Group: "org.apache.logging.log4j", Version: "2.14.1", Source: "cyclonedx"},
+		{Name: "log4j-api", Group: "org.apache.logging.log4j", Version: "2.14.1", Source: "cyclonedx"},
+	}))
+	require.NoError(t, ctl.ReplaceByVersion(ctx, 2, []*types.ArtifactSbomComponent{
+		{Name: "lodash", Version: "4.17.21", Source: "spdx"},
+	}))
+
+	comps, err := ctl.ListByVersion(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, comps, 2)
+	require.Equal(t, "log4j-api", comps[0].Name)
+
+	// the components of the previous sbom are dropped
+	require.NoError(t, ctl.ReplaceByVersion(ctx, 1, []*types.ArtifactSbomComponent{
+		{Name: "log4j-core", Group: "org.apache.logging.log4j", Version: "2.17.1", Source: "spdx"},
+	}))
+	comps, err = ctl.ListByVersion(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, comps, 1)
+	require.Equal(t, "2.17.1", comps[0].Version)
+
+	comps, err = ctl.ListByVersion(ctx, 2)
+	require.NoError(t, err)
+	require.Len(t, comps, 1)
+}
+
+func searchSbomComponents(t *testing.T, ctx context.Context, ctl *sbomComponents) {
+	pkgs := &packages{db: ctl.db}
+	vers := &versions{db: ctl.db}
+
+	app := &types.ArtifactPackage{OwnerID: 1, Name: "app", Namespace: "com.example", Format: types.ArtifactMavenFormat}
+	other := &types.ArtifactPackage{OwnerID: 2, Name: "other", Format: types.ArtifactMavenFormat}
+	require.NoError(t, pkgs.Create(ctx, app))
+	require.NoError(t, pkgs.Create(ctx, other))
+
+	v1 := &types.ArtifactVersion{PackageID: app.ID, ViewID: 1, Version: "1.0.0"}
+	v2 := &types.ArtifactVersion{PackageID: app.ID, ViewID: 1, Version: "2.0.0"}
+	removed := &types.ArtifactVersion{PackageID: app.ID, ViewID: 1, Version: "0.9.0", Deleted: 1}
+	otherVer := &types.ArtifactVersion{PackageID: other.ID, ViewID: 3, Version: "1.0.0"}
+	for _, v := range []*types.ArtifactVersion{v1, v2, removed, otherVer} {
+		require.NoError(t, vers.Create(ctx, v))
+	}
+
+	for ver, compVersion := range map[*types.ArtifactVersion]string{v1: "2.14.1", v2: "2.17.1", removed: "2.0", otherVer: "2.14.1"} {
+		require.NoError(t, ctl.ReplaceByVersion(ctx, ver.ID, []*types.ArtifactSbomComponent{
+			{Name: "log4j-core", Group: "org.apache.logging.log4j", Version: compVersion, Source: "cyclonedx"},
+			{Name: "slf4j-api", Group: "org.slf4j", Version: "1.7.36", Source: "cyclonedx"},
+		}))
+	}
+
+	matches, err := ctl.SearchByName(ctx, 1, 1, "log4j-core", "")
+	require.NoError(t, err)
+	require.Len(t, matches, 2)
+	require.Equal(t, "1.0.0", matches[0].Version)
+	require.Equal(t, "2.14.1", matches[0].ComponentVersion)
+	require.Equal(t, "com.example", matches[0].Namespace)
+	require.Equal(t, types.ArtifactMavenFormat, matches[0].Format)
+	require.Equal(t, "2.17.1", matches[1].ComponentVersion)
+
+	matches, err = ctl.SearchByName(ctx, 1, 1, "log4j-core", "org.example")
+	require.NoError(t, err)
+	require.Empty(t, matches)
+}
@@ -0,0 +1,51 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package artifacts
+
+import (
+	"context"
+
+	"github.com/easysoft/gitfox/app/store"
+	"github.com/easysoft/gitfox/store/database"
+	"github.com/easysoft/gitfox/store/database/dbtx"
+	"github.com/easysoft/gitfox/types"
+
+	"gorm.io/gorm"
+)
+
+var _ store.ArtifactSigningKeyInterface = (*signingKeys)(nil)
+
+type signingKeys struct {
+	db *gorm.DB
+}
+
+func (c *signingKeys) Create(ctx context.Context, newObj *types.ArtifactSigningKey) error {
+	if err := validatorEmpty(newObj.SpaceID); err != nil {
+		return err
+	}
+
+	if err := dbtx.GetOrmAccessor(ctx, c.db).Create(newObj).Error; err != nil {
+		return database.ProcessGormSQLErrorf(ctx, err, "artifact signing key create failed")
+	}
+	return nil
+}
+
+func (c *signingKeys) GetBySpace(ctx context.Context, spaceId int64) (*types.ArtifactSigningKey, error) {
+	var key types.ArtifactSigningKey
+	q := types.ArtifactSigningKey{SpaceID: spaceId}
+	if err := dbtx.GetOrmAccessor(ctx, c.db).Where(&q).First(&key).Error; err != nil {
+		return nil, database.ProcessGormSQLErrorf(ctx, err, "query artifact signing key failed")
+	}
+	return &key, nil
+}
+
+func (c *signingKeys) Update(ctx context.Context, upObj *types.ArtifactSigningKey) error {
+	if err := dbtx.GetOrmAccessor(ctx, c.db).Save(upObj).Error; err != nil {
+		return database.ProcessGormSQLErrorf(ctx, err, "exec artifact signing key update failed")
+	}
+	return nil
+}
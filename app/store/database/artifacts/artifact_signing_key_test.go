@@ -0,0 +1,50 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package artifacts
+
+import (
+	"context"
+	"testing"
+
+	gitfox_store "github.com/easysoft/gitfox/store"
+	"github.com/easysoft/gitfox/store/database/dbtest"
+	"github.com/easysoft/gitfox/types"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestArtifactSigningKey(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	t.Parallel()
+	_, gdb := dbtest.New(ctx, t, "artifacts_signing_key", new(types.ArtifactSigningKey))
+	s := NewStore(gdb)
+
+	err := s.SigningKeys().Create(ctx, &types.ArtifactSigningKey{Fingerprint: "AB"})
+	require.ErrorIs(t, err, types.ErrArgsValueEmpty)
+
+	_, err = s.SigningKeys().GetBySpace(ctx, 1)
+	require.ErrorIs(t, err, gitfox_store.ErrResourceNotFound)
+
+	key := &types.ArtifactSigningKey{SpaceID: 1, Fingerprint: "AB", PublicKey: "public", PrivateKey: []byte{0, 1, 2}}
+	require.NoError(t, s.SigningKeys().Create(ctx, key))
+	err = s.SigningKeys().Create(ctx, &types.ArtifactSigningKey{SpaceID: 1, Fingerprint: "CD", PublicKey: "public", PrivateKey: []byte{3}})
+	require.ErrorIs(t, err, gitfox_store.ErrDuplicate)
+
+	key.Fingerprint = "CD"
+	key.PrivateKey = []byte{3, 4}
+	require.NoError(t, s.SigningKeys().Update(ctx, key))
+
+	obj, err := s.SigningKeys().GetBySpace(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, "CD", obj.Fingerprint)
+	require.Equal(t, "public", obj.PublicKey)
+	require.Equal(t, []byte{3, 4}, obj.PrivateKey)
+}
@@ -6,24 +6,46 @@
 
 package artifacts
 
-//type ArtifactProxyAsset struct {
-//	UpstreamID int
-//	Path       string
-//	BlobID     int64
-//	Created    time.Time
-//	Updated    time.Time
-//}
-//
-//type ArtifactUpstreams struct {
-//	Format     FormatType
-//	Source     string
-//	Location   string
-//	SourceType UpstreamSourceType
-//}
-
-//type UpstreamSourceType int
-//
-//const (
-//	UpstreamSourcePublic  UpstreamSourceType = 1
-//	UpstreamSourcePrivate UpstreamSourceType = 2
-//)
+import (
+	"context"
+
+	"github.com/easysoft/gitfox/app/store"
+	"github.com/easysoft/gitfox/store/database"
+	"github.com/easysoft/gitfox/store/database/dbtx"
+	"github.com/easysoft/gitfox/types"
+
+	"gorm.io/gorm"
+)
+
+var _ store.ArtifactUpstreamInterface = (*upstreams)(nil)
+
+type upstreams struct {
+	db *gorm.DB
+}
+
+func (c *upstreams) Create(ctx context.Context, newObj *types.ArtifactUpstream) error {
+	if err := validatorEmpty(newObj.ViewID, newObj.Format, newObj.URL); err != nil {
+		return err
+	}
+
+	if err := dbtx.GetOrmAccessor(ctx, c.db).Create(newObj).Error; err != nil {
+		return database.ProcessGormSQLErrorf(ctx, err, "artifact upstream create failed")
+	}
+	return nil
+}
+
+func (c *upstreams) GetByViewID(ctx context.Context, viewId int64) (*types.ArtifactUpstream, error) {
+	var upstream types.ArtifactUpstream
+	q := types.ArtifactUpstream{ViewID: viewId}
+	if err := dbtx.GetOrmAccessor(ctx, c.db).Where(&q).First(&upstream).Error; err != nil {
+		return nil, database.ProcessGormSQLErrorf(ctx, err, "query artifact upstream by view failed")
+	}
+	return &upstream, nil
+}
+
+func (c *upstreams) Update(ctx context.Context, upObj *types.ArtifactUpstream) error {
+	if err := dbtx.GetOrmAccessor(ctx, c.db).Save(upObj).Error; err != nil {
+		return database.ProcessGormSQLErrorf(ctx, err, "exec artifact upstream update failed")
+	}
+	return nil
+}
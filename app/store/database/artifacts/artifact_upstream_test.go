@@ -0,0 +1,123 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package artifacts
+
+import (
+	"context"
+	"testing"
+
+	gitfox_store "github.com/easysoft/gitfox/store"
+	"github.com/easysoft/gitfox/store/database/dbtest"
+	"github.com/easysoft/gitfox/types"
+	"github.com/easysoft/gitfox/types/enum"
+
+	"github.com/guregu/null"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArtifactUpstream(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	t.Parallel()
+	tables := []any{new(types.ArtifactView), new(types.ArtifactUpstream)}
+	_, gdb := dbtest.New(ctx, t, "artifacts_upstream", tables...)
+	ctl := &upstreams{
+		db: gdb,
+	}
+
+	for _, tc := range []struct {
+		name string
+		test func(t *testing.T, ctx context.Context, ctl *upstreams)
+	}{
+		{"AddArtifactUpstream", addUpstream},
+		{"UpdateArtifactUpstream", updateUpstream},
+		{"ListProxyViews", listProxyViews},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Cleanup(func() {
+				err := dbtest.ClearTables(t, ctl.db, tables...)
+				require.NoError(t, err)
+			})
+			tc.test(t, ctx, ctl)
+		})
+		if t.Failed() {
+			break
+		}
+	}
+}
+
+func addUpstream(t *testing.T, ctx context.Context, ctl *upstreams) {
+	tests := []struct {
+		ViewID    int64
+		URL       string
+		wantErr   bool
+		expectErr error
+	}{
+		{0, "https://registry-1.docker.io", true, types.ErrArgsValueEmpty},
+		{1, "", true, types.ErrArgsValueEmpty},
+		{1, "https://registry-1.docker.io", false, nil},
+		{1, "https://ghcr.io", true, nil}, // unique key for view_id
+	}
+
+	for _, test := range tests {
+		obj := types.ArtifactUpstream{ViewID: test.ViewID, Format: types.ArtifactContainerFormat, URL: test.URL}
+		err := ctl.Create(ctx, &obj)
+		if test.wantErr {
+			require.Error(t, err)
+			if test.expectErr != nil {
+				require.ErrorIs(t, err, test.expectErr)
+			}
+			continue
+		}
+		require.NoError(t, err)
+		require.NotZero(t, obj.ID)
+	}
+
+	_, err := ctl.GetByViewID(ctx, 2)
+	require.ErrorIs(t, err, gitfox_store.ErrResourceNotFound)
+}
+
+func updateUpstream(t *testing.T, ctx context.Context, ctl *upstreams) {
+	obj := types.ArtifactUpstream{ViewID: 1, Format: types.ArtifactContainerFormat, URL: "https://ghcr.io", ManifestTTL: 60}
+	require.NoError(t, ctl.Create(ctx, &obj))
+
+	obj.ManifestTTL = 600
+	obj.Username = "bot"
+	obj.SecretID = null.IntFrom(3)
+	require.NoError(t, ctl.Update(ctx, &obj))
+
+	got, err := ctl.GetByViewID(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, int64(600), got.ManifestTTL)
+	require.Equal(t, "bot", got.Username)
+	require.Equal(t, null.IntFrom(3), got.SecretID)
+	require.False(t, got.ConnectorID.Valid)
+}
+
+func listProxyViews(t *testing.T, ctx context.Context, ctl *upstreams) {
+	v := &views{db: ctl.db}
+	for _, view := range []*types.ArtifactView{
+		{Name: "default", SpaceID: 1, Default: true},
+		{Name: "dockerhub", SpaceID: 1, Kind: enum.ArtifactRepoKindProxy},
+		{Name: "ghcr", SpaceID: 2, Kind: enum.ArtifactRepoKindProxy},
+	} {
+		require.NoError(t, v.Create(ctx, view))
+	}
+
+	def, err := v.GetDefault(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, enum.ArtifactRepoKindStandalone, def.Kind)
+
+	proxies, err := v.ListByKind(ctx, 1, enum.ArtifactRepoKindProxy)
+	require.NoError(t, err)
+	require.Len(t, proxies, 1)
+	require.Equal(t, "dockerhub", proxies[0].Name)
+	require.True(t, proxies[0].IsProxy())
+}
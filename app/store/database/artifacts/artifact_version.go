@@ -104,6 +104,10 @@ func (c *versions) Find(ctx context.Context, opt types.SearchVersionOption) ([]*
 		stmt = stmt.Where("version_deleted = 0")
 	}
 
+	if opt.ExcludeYanked {
+		stmt = stmt.Where("version_yanked = 0")
+	}
+
 	stmt = stmt.Limit(int(database.Limit(opt.Size)))
 	stmt = stmt.Offset(int(database.Offset(opt.Page, opt.Size)))
 
@@ -127,6 +131,16 @@ func (c *versions) UnDelete(ctx context.Context, ver *types.ArtifactVersion) err
 	return c.Update(ctx, ver)
 }
 
+func (c *versions) Yank(ctx context.Context, ver *types.ArtifactVersion) error {
+	ver.Yanked = time.Now().UnixMilli()
+	return c.Update(ctx, ver)
+}
+
+func (c *versions) UnYank(ctx context.Context, ver *types.ArtifactVersion) error {
+	ver.Yanked = 0
+	return c.Update(ctx, ver)
+}
+
 func (c *versions) Search(ctx context.Context, options ...store.SearchOption) ([]*types.ArtifactVersionInfo, error) {
 	db := dbtx.GetOrmAccessor(ctx, c.db)
 	for _, opt := range options {
@@ -154,3 +168,17 @@ func (c *versions) Search(ctx context.Context, options ...store.SearchOption) ([
 
 	return data, nil
 }
+
+func (c *versions) AddDownloads(ctx context.Context, versionId, count, lastPulled int64) error {
+	err := dbtx.GetOrmAccessor(ctx, c.db).Table("artifact_versions").
+		Where("version_id = ?", versionId).
+		UpdateColumns(map[string]any{
+			"version_downloads": gorm.Expr("version_downloads + ?", count),
+			"version_last_pulled": gorm.Expr(
+				"CASE WHEN version_last_pulled < ? THEN ? ELSE version_last_pulled END", lastPulled, lastPulled),
+		}).Error
+	if err != nil {
+		return database.ProcessGormSQLErrorf(ctx, err, "exec version add downloads failed")
+	}
+	return nil
+}
@@ -38,6 +38,7 @@ func TestArtifactVersion(t *testing.T) {
 		{"GetArtifactPkgVer", getPackageVer},
 		{"DelArtifactPkgVer", delPackageVer},
 		{"SearchArtifactVer", searchVersion},
+		{"YankArtifactVer", yankVersion},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Cleanup(func() {
@@ -206,3 +207,32 @@ func searchVersion(t *testing.T, ctx context.Context, ctl *versions) {
 		require.Equalf(t, test.firstVersion, items[0].Version, "items: %+v", items)
 	}
 }
+
+func yankVersion(t *testing.T, ctx context.Context, ctl *versions) {
+	pks := addVersionTestData(ctx, ctl)
+
+	ver, err := ctl.GetByID(ctx, pks[1])
+	require.NoError(t, err)
+	require.NoError(t, ctl.Yank(ctx, ver))
+	require.True(t, ver.IsYanked())
+
+	items, err := ctl.Find(ctx, types.SearchVersionOption{PackageId: 1, ViewId: 1})
+	require.NoError(t, err)
+	require.Len(t, items, 3)
+
+	items, err = ctl.Find(ctx, types.SearchVersionOption{PackageId: 1, ViewId: 1, ExcludeYanked: true})
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	for _, item := range items {
+		require.NotEqual(t, "1.0.1", item.Version)
+	}
+
+	require.NoError(t, ctl.UnYank(ctx, ver))
+	ver, err = ctl.GetByID(ctx, pks[1])
+	require.NoError(t, err)
+	require.False(t, ver.IsYanked())
+
+	items, err = ctl.Find(ctx, types.SearchVersionOption{PackageId: 1, ViewId: 1, ExcludeYanked: true})
+	require.NoError(t, err)
+	require.Len(t, items, 3)
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/easysoft/gitfox/store/database"
 	"github.com/easysoft/gitfox/store/database/dbtx"
 	"github.com/easysoft/gitfox/types"
+	"github.com/easysoft/gitfox/types/enum"
 
 	"gorm.io/gorm"
 )
@@ -40,6 +41,15 @@ func (c *views) GetDefault(ctx context.Context, spaceId int64) (*types.ArtifactV
 	return &view, nil
 }
 
+func (c *views) GetByID(ctx context.Context, viewId int64) (*types.ArtifactView, error) {
+	var view types.ArtifactView
+	if err := dbtx.GetOrmAccessor(ctx, c.db).First(&view, viewId).Error; err != nil {
+		return nil, database.ProcessGormSQLErrorf(ctx, err, "exec view query failed")
+	}
+
+	return &view, nil
+}
+
 func (c *views) GetByName(ctx context.Context, spaceId int64, name string) (*types.ArtifactView, error) {
 	var view types.ArtifactView
 	q := types.ArtifactView{SpaceID: spaceId, Name: name}
@@ -49,3 +59,30 @@ func (c *views) GetByName(ctx context.Context, spaceId int64, name string) (*typ
 
 	return &view, nil
 }
+
+func (c *views) ListByKind(ctx context.Context, spaceId int64, kind enum.ArtifactRepoKind) ([]*types.ArtifactView, error) {
+	var dst []*types.ArtifactView
+	q := types.ArtifactView{SpaceID: spaceId, Kind: kind}
+	if err := dbtx.GetOrmAccessor(ctx, c.db).Where(q).Order("view_id").Find(&dst).Error; err != nil {
+		return nil, database.ProcessGormSQLErrorf(ctx, err, "exec view list query failed")
+	}
+
+	return dst, nil
+}
+
+func (c *views) List(ctx context.Context, spaceId int64) ([]*types.ArtifactView, error) {
+	var dst []*types.ArtifactView
+	q := types.ArtifactView{SpaceID: spaceId}
+	if err := dbtx.GetOrmAccessor(ctx, c.db).Where(q).Order("view_id").Find(&dst).Error; err != nil {
+		return nil, database.ProcessGormSQLErrorf(ctx, err, "exec view list query failed")
+	}
+
+	return dst, nil
+}
+
+func (c *views) Update(ctx context.Context, view *types.ArtifactView) error {
+	if err := dbtx.GetOrmAccessor(ctx, c.db).Save(view).Error; err != nil {
+		return database.ProcessGormSQLErrorf(ctx, err, "artifact view update failed")
+	}
+	return nil
+}
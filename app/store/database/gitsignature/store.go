@@ -0,0 +1,128 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package gitsignature
+
+import (
+	"context"
+
+	"github.com/easysoft/gitfox/app/store"
+	"github.com/easysoft/gitfox/store/database"
+	"github.com/easysoft/gitfox/store/database/dbtx"
+	"github.com/easysoft/gitfox/types"
+	"github.com/easysoft/gitfox/types/enum"
+
+	"github.com/guregu/null"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const tableGitSignatureResults = "git_signature_results"
+
+var _ store.GitSignatureResultStore = (*Store)(nil)
+
+// NewStore returns a new git signature result Store.
+func NewStore(db *gorm.DB) *Store {
+	return &Store{
+		db: db,
+	}
+}
+
+// Store implements a store.GitSignatureResultStore backed by a relational database.
+type Store struct {
+	db *gorm.DB
+}
+
+type gitSignatureResult struct {
+	RepoID      int64    `gorm:"column:git_signature_result_repo_id;primaryKey"`
+	ObjectSHA   string   `gorm:"column:git_signature_result_object_sha;primaryKey"`
+	Created     int64    `gorm:"column:git_signature_result_created"`
+	Result      string   `gorm:"column:git_signature_result_result"`
+	KeyScheme   string   `gorm:"column:git_signature_result_key_scheme"`
+	KeyID       string   `gorm:"column:git_signature_result_key_id"`
+	PrincipalID null.Int `gorm:"column:git_signature_result_principal_id"`
+}
+
+// Map returns the cached verification results of the objects of the repository, keyed by the object SHA.
+func (s *Store) Map(
+	ctx context.Context,
+	repoID int64,
+	objectSHAs []string,
+) (map[string]types.GitSignatureResult, error) {
+	if len(objectSHAs) == 0 {
+		return map[string]types.GitSignatureResult{}, nil
+	}
+
+	dst := make([]gitSignatureResult, 0, len(objectSHAs))
+	if err := dbtx.GetOrmAccessor(ctx, s.db).Table(tableGitSignatureResults).
+		Where("git_signature_result_repo_id = ? AND git_signature_result_object_sha IN ?", repoID, objectSHAs).
+		Find(&dst).Error; err != nil {
+		return nil, database.ProcessGormSQLErrorf(ctx, err, "Failed to list git signature results")
+	}
+
+	results := make(map[string]types.GitSignatureResult, len(dst))
+	for i := range dst {
+		results[dst[i].ObjectSHA] = mapToGitSignatureResult(&dst[i])
+	}
+	return results, nil
+}
+
+// TryCreateAll caches the verification results, the results already cached are left intact.
+func (s *Store) TryCreateAll(ctx context.Context, results []*types.GitSignatureResult) error {
+	if len(results) == 0 {
+		return nil
+	}
+
+	rows := make([]gitSignatureResult, len(results))
+	for i, result := range results {
+		rows[i] = mapToInternalGitSignatureResult(result)
+	}
+
+	if err := dbtx.GetOrmAccessor(ctx, s.db).Table(tableGitSignatureResults).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&rows).Error; err != nil {
+		return database.ProcessGormSQLErrorf(ctx, err, "Failed to create git signature results")
+	}
+	return nil
+}
+
+// DeleteByKeyIDs removes the cached verification results of the signatures of the keys.
+func (s *Store) DeleteByKeyIDs(ctx context.Context, keyIDs []string) error {
+	if len(keyIDs) == 0 {
+		return nil
+	}
+
+	if err := dbtx.GetOrmAccessor(ctx, s.db).Table(tableGitSignatureResults).
+		Where("git_signature_result_key_id IN ?", keyIDs).
+		Delete(&gitSignatureResult{}).Error; err != nil {
+		return database.ProcessGormSQLErrorf(ctx, err, "Failed to delete git signature results")
+	}
+	return nil
+}
+
+func mapToInternalGitSignatureResult(in *types.GitSignatureResult) gitSignatureResult {
+	return gitSignatureResult{
+		RepoID:      in.RepoID,
+		ObjectSHA:   in.ObjectSHA,
+		Created:     in.Created,
+		Result:      string(in.Result),
+		KeyScheme:   string(in.KeyScheme),
+		KeyID:       in.KeyID,
+		PrincipalID: null.IntFromPtr(in.PrincipalID),
+	}
+}
+
+func mapToGitSignatureResult(in *gitSignatureResult) types.GitSignatureResult {
+	return types.GitSignatureResult{
+		RepoID:      in.RepoID,
+		ObjectSHA:   in.ObjectSHA,
+		Created:     in.Created,
+		Result:      enum.GitSignatureResult(in.Result),
+		KeyScheme:   enum.PublicKeyScheme(in.KeyScheme),
+		KeyID:       in.KeyID,
+		PrincipalID: in.PrincipalID.Ptr(),
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package gitsignature_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/easysoft/gitfox/app/store/database/gitsignature"
+	"github.com/easysoft/gitfox/app/store/database/testsuite"
+	"github.com/easysoft/gitfox/types"
+	"github.com/easysoft/gitfox/types/enum"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	shaA = "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+	shaB = "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+)
+
+type GitSignatureSuite struct {
+	testsuite.BaseSuite
+
+	store *gitsignature.Store
+}
+
+func TestGitSignatureSuite(t *testing.T) {
+	st := &GitSignatureSuite{
+		BaseSuite: testsuite.BaseSuite{
+			Ctx:  context.Background(),
+			Name: "git_signature_results",
+		},
+	}
+
+	st.BaseSuite.Constructor = func(ts *testsuite.TestStore) {
+		st.store = gitsignature.NewStore(st.Gdb)
+
+		testsuite.AddUser(st.Ctx, t, ts.Principal, 1, true)
+		testsuite.AddSpace(st.Ctx, t, ts.Space, ts.SpacePath, 1, 1, 0)
+		testsuite.AddRepo(st.Ctx, t, ts.Repo, 1, 1, 0)
+	}
+
+	suite.Run(t, st)
+}
+
+func (suite *GitSignatureSuite) TearDownTest() {
+	suite.Gdb.WithContext(suite.Ctx).Table("git_signature_results").Where("1 = 1").Delete(nil)
+}
+
+func (suite *GitSignatureSuite) TestTryCreateAll() {
+	principalID := int64(1)
+	require.NoError(suite.T(), suite.store.TryCreateAll(suite.Ctx, []*types.GitSignatureResult{
+		{RepoID: 1, ObjectSHA: shaA, Result: enum.GitSignatureVerified, KeyScheme: enum.PublicKeySchemePGP,
+			KeyID: "0123456789ABCDEF", PrincipalID: &principalID},
+		{RepoID: 1, ObjectSHA: shaB, Result: enum.GitSignatureUnknownKey, KeyScheme: enum.PublicKeySchemeSSH,
+			KeyID: "SHA256:key"},
+	}))
+
+	// the cached results are left intact
+	require.NoError(suite.T(), suite.store.TryCreateAll(suite.Ctx, []*types.GitSignatureResult{
+		{RepoID: 1, ObjectSHA: shaA, Result: enum.GitSignatureUnverified, KeyScheme: enum.PublicKeySchemePGP,
+			KeyID: "0123456789ABCDEF"},
+	}))
+
+	results, err := suite.store.Map(suite.Ctx, 1, []string{shaA, shaB, "cccc"})
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), results, 2)
+	require.Equal(suite.T(), enum.GitSignatureVerified, results[shaA].Result)
+	require.Equal(suite.T(), &principalID, results[shaA].PrincipalID)
+	require.Equal(suite.T(), enum.GitSignatureUnknownKey, results[shaB].Result)
+	require.Nil(suite.T(), results[shaB].PrincipalID)
+}
+
+func (suite *GitSignatureSuite) TestDeleteByKeyIDs() {
+	require.NoError(suite.T(), suite.store.TryCreateAll(suite.Ctx, []*types.GitSignatureResult{
+		{RepoID: 1, ObjectSHA: shaA, Result: enum.GitSignatureUnknownKey, KeyScheme: enum.PublicKeySchemePGP,
+			KeyID: "0123456789ABCDEF"},
+		{RepoID: 1, ObjectSHA: shaB, Result: enum.GitSignatureUnknownKey, KeyScheme: enum.PublicKeySchemeSSH,
+			KeyID: "SHA256:key"},
+	}))
+
+	require.NoError(suite.T(), suite.store.DeleteByKeyIDs(suite.Ctx, []string{"SHA256:key"}))
+
+	results, err := suite.store.Map(suite.Ctx, 1, []string{shaA, shaB})
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), results, 1)
+	require.Contains(suite.T(), results, shaA)
+}
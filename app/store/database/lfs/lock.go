@@ -0,0 +1,133 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package lfs
+
+import (
+	"context"
+
+	"github.com/easysoft/gitfox/app/store"
+	gitfox_store "github.com/easysoft/gitfox/store"
+	"github.com/easysoft/gitfox/store/database"
+	"github.com/easysoft/gitfox/store/database/dbtx"
+	"github.com/easysoft/gitfox/types"
+
+	"gorm.io/gorm"
+)
+
+var _ store.LFSLockStore = (*LFSLockStore)(nil)
+
+// NewLFSLockStore returns a new LFSLockStore.
+func NewLFSLockStore(db *gorm.DB) *LFSLockStore {
+	return &LFSLockStore{
+		db: db,
+	}
+}
+
+// LFSLockStore implements a store.LFSLockStore backed by a relational database.
+type LFSLockStore struct {
+	db *gorm.DB
+}
+
+type lfsLock struct {
+	ID        int64  `gorm:"column:lfs_lock_id;primaryKey"`
+	RepoID    int64  `gorm:"column:lfs_lock_repo_id"`
+	Path      string `gorm:"column:lfs_lock_path"`
+	Ref       string `gorm:"column:lfs_lock_ref"`
+	Created   int64  `gorm:"column:lfs_lock_created"`
+	CreatedBy int64  `gorm:"column:lfs_lock_created_by"`
+}
+
+// Find finds the lock of the repository by the id.
+func (s *LFSLockStore) Find(ctx context.Context, repoID, id int64) (*types.LFSLock, error) {
+	dst := &lfsLock{}
+	if err := dbtx.GetOrmAccessor(ctx, s.db).
+		Where("lfs_lock_repo_id = ? AND lfs_lock_id = ?", repoID, id).
+		First(dst).Error; err != nil {
+		return nil, database.ProcessGormSQLErrorf(ctx, err, "Failed to find lfs lock %d", id)
+	}
+	return mapToLFSLock(dst), nil
+}
+
+// Create creates a new lock, ErrDuplicate is returned if the path is locked already.
+func (s *LFSLockStore) Create(ctx context.Context, lock *types.LFSLock) error {
+	dbLock := mapToInternalLFSLock(lock)
+	if err := dbtx.GetOrmAccessor(ctx, s.db).Create(dbLock).Error; err != nil {
+		return database.ProcessGormSQLErrorf(ctx, err, "Failed to create lfs lock %s", lock.Path)
+	}
+	lock.ID = dbLock.ID
+	return nil
+}
+
+// List lists the locks of the repository ordered by the id.
+func (s *LFSLockStore) List(ctx context.Context, repoID int64, filter *types.LFSLockFilter) ([]*types.LFSLock, error) {
+	stmt := dbtx.GetOrmAccessor(ctx, s.db).Where("lfs_lock_repo_id = ?", repoID)
+	if filter.ID > 0 {
+		stmt = stmt.Where("lfs_lock_id = ?", filter.ID)
+	}
+	if filter.Path != "" {
+		stmt = stmt.Where("lfs_lock_path = ?", filter.Path)
+	}
+	if filter.Ref != "" {
+		stmt = stmt.Where("lfs_lock_ref = ?", filter.Ref)
+	}
+	if filter.CreatedBy > 0 {
+		stmt = stmt.Where("lfs_lock_created_by = ?", filter.CreatedBy)
+	}
+	if filter.Cursor > 0 {
+		stmt = stmt.Where("lfs_lock_id > ?", filter.Cursor)
+	}
+	if filter.Limit > 0 {
+		stmt = stmt.Limit(filter.Limit)
+	}
+
+	dst := make([]*lfsLock, 0)
+	if err := stmt.Order("lfs_lock_id ASC").Find(&dst).Error; err != nil {
+		return nil, database.ProcessGormSQLErrorf(ctx, err, "Failed to list lfs locks")
+	}
+
+	res := make([]*types.LFSLock, len(dst))
+	for i := range dst {
+		res[i] = mapToLFSLock(dst[i])
+	}
+	return res, nil
+}
+
+// Delete deletes the lock of the repository by the id.
+func (s *LFSLockStore) Delete(ctx context.Context, repoID, id int64) error {
+	res := dbtx.GetOrmAccessor(ctx, s.db).
+		Where("lfs_lock_repo_id = ? AND lfs_lock_id = ?", repoID, id).
+		Delete(&lfsLock{})
+	if res.Error != nil {
+		return database.ProcessGormSQLErrorf(ctx, res.Error, "Failed to delete lfs lock %d", id)
+	}
+	if res.RowsAffected == 0 {
+		return gitfox_store.ErrResourceNotFound
+	}
+	return nil
+}
+
+func mapToLFSLock(in *lfsLock) *types.LFSLock {
+	return &types.LFSLock{
+		ID:        in.ID,
+		RepoID:    in.RepoID,
+		Path:      in.Path,
+		Ref:       in.Ref,
+		Created:   in.Created,
+		CreatedBy: in.CreatedBy,
+	}
+}
+
+func mapToInternalLFSLock(in *types.LFSLock) *lfsLock {
+	return &lfsLock{
+		ID:        in.ID,
+		RepoID:    in.RepoID,
+		Path:      in.Path,
+		Ref:       in.Ref,
+		Created:   in.Created,
+		CreatedBy: in.CreatedBy,
+	}
+}
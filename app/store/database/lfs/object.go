@@ -0,0 +1,116 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package lfs
+
+import (
+	"context"
+
+	"github.com/easysoft/gitfox/app/store"
+	"github.com/easysoft/gitfox/store/database"
+	"github.com/easysoft/gitfox/store/database/dbtx"
+	"github.com/easysoft/gitfox/types"
+
+	"gorm.io/gorm"
+)
+
+var _ store.LFSObjectStore = (*LFSObjectStore)(nil)
+
+// NewLFSObjectStore returns a new LFSObjectStore.
+func NewLFSObjectStore(db *gorm.DB) *LFSObjectStore {
+	return &LFSObjectStore{
+		db: db,
+	}
+}
+
+// LFSObjectStore implements a store.LFSObjectStore backed by a relational database.
+type LFSObjectStore struct {
+	db *gorm.DB
+}
+
+type lfsObject struct {
+	ID        int64  `gorm:"column:lfs_object_id;primaryKey"`
+	OID       string `gorm:"column:lfs_object_oid"`
+	Size      int64  `gorm:"column:lfs_object_size"`
+	RepoID    int64  `gorm:"column:lfs_object_repo_id"`
+	Created   int64  `gorm:"column:lfs_object_created"`
+	CreatedBy int64  `gorm:"column:lfs_object_created_by"`
+}
+
+// Find finds the lfs object of the repository by the oid.
+func (s *LFSObjectStore) Find(ctx context.Context, repoID int64, oid string) (*types.LFSObject, error) {
+	dst := &lfsObject{}
+	if err := dbtx.GetOrmAccessor(ctx, s.db).
+		Where("lfs_object_repo_id = ? AND lfs_object_oid = ?", repoID, oid).
+		First(dst).Error; err != nil {
+		return nil, database.ProcessGormSQLErrorf(ctx, err, "Failed to find lfs object %s", oid)
+	}
+	return mapToLFSObject(dst), nil
+}
+
+// FindMany finds the lfs objects of the repository by the oids, the missing objects are skipped.
+func (s *LFSObjectStore) FindMany(ctx context.Context, repoID int64, oids []string) ([]*types.LFSObject, error) {
+	if len(oids) == 0 {
+		return []*types.LFSObject{}, nil
+	}
+
+	dst := make([]*lfsObject, 0, len(oids))
+	if err := dbtx.GetOrmAccessor(ctx, s.db).
+		Where("lfs_object_repo_id = ? AND lfs_object_oid IN ?", repoID, oids).
+		Find(&dst).Error; err != nil {
+		return nil, database.ProcessGormSQLErrorf(ctx, err, "Failed to find lfs objects")
+	}
+
+	res := make([]*types.LFSObject, len(dst))
+	for i := range dst {
+		res[i] = mapToLFSObject(dst[i])
+	}
+	return res, nil
+}
+
+// Create creates a new lfs object.
+func (s *LFSObjectStore) Create(ctx context.Context, obj *types.LFSObject) error {
+	dbObj := mapToInternalLFSObject(obj)
+	if err := dbtx.GetOrmAccessor(ctx, s.db).Create(dbObj).Error; err != nil {
+		return database.ProcessGormSQLErrorf(ctx, err, "Failed to create lfs object %s", obj.OID)
+	}
+	obj.ID = dbObj.ID
+	return nil
+}
+
+// Usage counts the lfs objects and the size stored in the repository.
+func (s *LFSObjectStore) Usage(ctx context.Context, repoID int64) (*types.LFSUsage, error) {
+	dst := &types.LFSUsage{}
+	if err := dbtx.GetOrmAccessor(ctx, s.db).Model(&lfsObject{}).
+		Select("COUNT(*) AS objects, COALESCE(SUM(lfs_object_size), 0) AS size").
+		Where("lfs_object_repo_id = ?", repoID).
+		Scan(dst).Error; err != nil {
+		return nil, database.ProcessGormSQLErrorf(ctx, err, "Failed to count lfs usage")
+	}
+	return dst, nil
+}
+
+func mapToLFSObject(in *lfsObject) *types.LFSObject {
+	return &types.LFSObject{
+		ID:        in.ID,
+		OID:       in.OID,
+		Size:      in.Size,
+		RepoID:    in.RepoID,
+		Created:   in.Created,
+		CreatedBy: in.CreatedBy,
+	}
+}
+
+func mapToInternalLFSObject(in *types.LFSObject) *lfsObject {
+	return &lfsObject{
+		ID:        in.ID,
+		OID:       in.OID,
+		Size:      in.Size,
+		RepoID:    in.RepoID,
+		Created:   in.Created,
+		CreatedBy: in.CreatedBy,
+	}
+}
@@ -0,0 +1,17 @@
+-- Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+-- Use of this source code is covered by the following dual licenses:
+-- (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+-- (2) Affero General Public License 3.0 (AGPL 3.0)
+-- license that can be found in the LICENSE file.
+
+CREATE TABLE public_key_sub_keys (
+    public_key_sub_key_public_key_id INTEGER NOT NULL,
+    public_key_sub_key_id            VARCHAR(255) NOT NULL,
+    CONSTRAINT fk_public_key_sub_key_public_key_id FOREIGN KEY (public_key_sub_key_public_key_id)
+        REFERENCES public_keys (public_key_id)
+        ON UPDATE NO ACTION
+        ON DELETE CASCADE
+);
+
+CREATE INDEX public_key_sub_keys_id
+    ON public_key_sub_keys(public_key_sub_key_id);
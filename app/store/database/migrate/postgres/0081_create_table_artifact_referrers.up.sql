@@ -0,0 +1,21 @@
+-- Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+-- Use of this source code is covered by the following dual licenses:
+-- (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+-- (2) Affero General Public License 3.0 (AGPL 3.0)
+-- license that can be found in the LICENSE file.
+
+CREATE TABLE artifact_referrers (
+    referrer_id            SERIAL PRIMARY KEY,
+    referrer_view_id       INTEGER NOT NULL,
+    referrer_repository    VARCHAR(255) NOT NULL,
+    referrer_subject       VARCHAR(100) NOT NULL,
+    referrer_digest        VARCHAR(100) NOT NULL,
+    referrer_media_type    VARCHAR(100),
+    referrer_artifact_type VARCHAR(255),
+    referrer_size          BIGINT,
+    referrer_annotations   TEXT,
+    referrer_created       BIGINT
+);
+
+CREATE UNIQUE INDEX idx_referrer_view_repo_digest ON artifact_referrers (referrer_view_id, referrer_repository, referrer_digest);
+CREATE INDEX idx_referrer_subject ON artifact_referrers (referrer_subject);
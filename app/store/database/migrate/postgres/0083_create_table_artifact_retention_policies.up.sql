@@ -0,0 +1,21 @@
+-- Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+-- Use of this source code is covered by the following dual licenses:
+-- (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+-- (2) Affero General Public License 3.0 (AGPL 3.0)
+-- license that can be found in the LICENSE file.
+
+CREATE TABLE artifact_retention_policies (
+    policy_id             SERIAL PRIMARY KEY,
+    policy_space_id       INTEGER NOT NULL,
+    policy_format         VARCHAR(100) NOT NULL,
+    policy_keep_last      INTEGER NOT NULL DEFAULT 0,
+    policy_max_age_days   INTEGER NOT NULL DEFAULT 0,
+    policy_keep_pattern   VARCHAR(255),
+    policy_protected_tags VARCHAR(255),
+    policy_enabled        BOOLEAN NOT NULL DEFAULT FALSE,
+    policy_created_by     INTEGER,
+    policy_created        BIGINT,
+    policy_updated        BIGINT
+);
+
+CREATE UNIQUE INDEX idx_policy_space_id_format ON artifact_retention_policies (policy_space_id, policy_format);
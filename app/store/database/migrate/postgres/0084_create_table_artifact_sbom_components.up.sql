@@ -0,0 +1,20 @@
+-- Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+-- Use of this source code is covered by the following dual licenses:
+-- (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+-- (2) Affero General Public License 3.0 (AGPL 3.0)
+-- license that can be found in the LICENSE file.
+
+CREATE TABLE artifact_sbom_components (
+    component_id         SERIAL PRIMARY KEY,
+    component_version_id INTEGER NOT NULL,
+    component_name       VARCHAR(255) NOT NULL,
+    component_group      VARCHAR(255),
+    component_version    VARCHAR(255),
+    component_purl       VARCHAR(1024),
+    component_type       VARCHAR(100),
+    component_source     VARCHAR(100),
+    component_created    BIGINT
+);
+
+CREATE INDEX idx_component_version_id ON artifact_sbom_components (component_version_id);
+CREATE INDEX idx_component_name ON artifact_sbom_components (component_name);
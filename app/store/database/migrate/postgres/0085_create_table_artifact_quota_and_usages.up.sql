@@ -0,0 +1,39 @@
+-- Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+-- Use of this source code is covered by the following dual licenses:
+-- (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+-- (2) Affero General Public License 3.0 (AGPL 3.0)
+-- license that can be found in the LICENSE file.
+
+CREATE TABLE artifact_quota (
+    quota_id           SERIAL PRIMARY KEY,
+    quota_space_id     INTEGER NOT NULL,
+    quota_max_size     BIGINT NOT NULL DEFAULT 0,
+    quota_max_packages BIGINT NOT NULL DEFAULT 0,
+    quota_created_by   INTEGER,
+    quota_created      BIGINT,
+    quota_updated      BIGINT
+);
+
+CREATE UNIQUE INDEX idx_quota_space_id ON artifact_quota (quota_space_id);
+
+CREATE TABLE artifact_usages (
+    usage_space_id INTEGER PRIMARY KEY,
+    usage_size     BIGINT NOT NULL DEFAULT 0,
+    usage_packages BIGINT NOT NULL DEFAULT 0,
+    usage_updated  BIGINT
+);
+
+-- seed the usage of the existing packages, the container layers are not linked to a space and counted from now on
+INSERT INTO artifact_usages (usage_space_id, usage_size, usage_packages, usage_updated)
+SELECT p.package_owner_id, COALESCE(SUM(s.size), 0), COUNT(*), 0
+FROM artifact_packages p
+LEFT JOIN (
+    SELECT v.version_package_id AS package_id, SUM(b.blob_size) AS size
+    FROM artifact_versions v
+    JOIN artifact_assets a ON a.asset_version_id = v.version_id AND a.asset_deleted = 0
+    JOIN artifact_blobs b ON b.blob_id = a.asset_blob_id AND COALESCE(b.blob_deleted, 0) = 0
+    WHERE v.version_deleted = 0
+    GROUP BY v.version_package_id
+) s ON s.package_id = p.package_id
+WHERE p.package_deleted = 0
+GROUP BY p.package_owner_id;
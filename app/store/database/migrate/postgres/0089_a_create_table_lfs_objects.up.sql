@@ -0,0 +1,16 @@
+-- Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+-- Use of this source code is covered by the following dual licenses:
+-- (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+-- (2) Affero General Public License 3.0 (AGPL 3.0)
+-- license that can be found in the LICENSE file.
+
+CREATE TABLE lfs_objects (
+    lfs_object_id         SERIAL PRIMARY KEY,
+    lfs_object_oid        VARCHAR(64) NOT NULL,
+    lfs_object_size       BIGINT NOT NULL,
+    lfs_object_repo_id    INTEGER NOT NULL,
+    lfs_object_created    BIGINT NOT NULL,
+    lfs_object_created_by INTEGER NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_lfs_objects_repo_id_oid ON lfs_objects (lfs_object_repo_id, lfs_object_oid);
@@ -0,0 +1,23 @@
+-- Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+-- Use of this source code is covered by the following dual licenses:
+-- (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+-- (2) Affero General Public License 3.0 (AGPL 3.0)
+-- license that can be found in the LICENSE file.
+
+CREATE TABLE git_signature_results (
+    git_signature_result_repo_id      INTEGER NOT NULL,
+    git_signature_result_object_sha   TEXT NOT NULL,
+    git_signature_result_created      BIGINT NOT NULL,
+    git_signature_result_result       TEXT NOT NULL,
+    git_signature_result_key_scheme   TEXT NOT NULL,
+    git_signature_result_key_id       TEXT NOT NULL,
+    git_signature_result_principal_id INTEGER,
+    PRIMARY KEY (git_signature_result_repo_id, git_signature_result_object_sha),
+    CONSTRAINT fk_git_signature_result_repo_id FOREIGN KEY (git_signature_result_repo_id)
+        REFERENCES repositories (repo_id) MATCH SIMPLE
+        ON UPDATE NO ACTION
+        ON DELETE CASCADE
+);
+
+CREATE INDEX git_signature_results_key_id
+    ON git_signature_results(git_signature_result_key_id);
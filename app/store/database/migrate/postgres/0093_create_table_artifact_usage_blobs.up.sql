@@ -0,0 +1,16 @@
+-- Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+-- Use of this source code is covered by the following dual licenses:
+-- (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+-- (2) Affero General Public License 3.0 (AGPL 3.0)
+-- license that can be found in the LICENSE file.
+
+-- the shared blobs counted in the usage of the spaces, the blob is counted once per space
+CREATE TABLE artifact_usage_blobs (
+    usage_blob_space_id INTEGER NOT NULL,
+    usage_blob_digest   TEXT NOT NULL,
+    usage_blob_size     BIGINT NOT NULL DEFAULT 0,
+    usage_blob_created  BIGINT,
+    PRIMARY KEY (usage_blob_space_id, usage_blob_digest)
+);
+
+CREATE INDEX idx_usage_blob_digest ON artifact_usage_blobs (usage_blob_digest);
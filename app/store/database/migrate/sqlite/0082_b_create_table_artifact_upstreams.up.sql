@@ -0,0 +1,21 @@
+-- Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+-- Use of this source code is covered by the following dual licenses:
+-- (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+-- (2) Affero General Public License 3.0 (AGPL 3.0)
+-- license that can be found in the LICENSE file.
+
+CREATE TABLE artifact_upstreams (
+    upstream_id           INTEGER PRIMARY KEY AUTOINCREMENT,
+    upstream_view_id      INTEGER NOT NULL,
+    upstream_format       TEXT NOT NULL,
+    upstream_url          TEXT NOT NULL,
+    upstream_username     TEXT,
+    upstream_secret_id    INTEGER,
+    upstream_connector_id INTEGER,
+    upstream_manifest_ttl BIGINT NOT NULL DEFAULT 0,
+    upstream_created_by   INTEGER,
+    upstream_created      BIGINT,
+    upstream_updated      BIGINT
+);
+
+CREATE UNIQUE INDEX idx_upstream_view_id ON artifact_upstreams (upstream_view_id);
@@ -0,0 +1,22 @@
+-- Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+-- Use of this source code is covered by the following dual licenses:
+-- (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+-- (2) Affero General Public License 3.0 (AGPL 3.0)
+-- license that can be found in the LICENSE file.
+
+CREATE TABLE artifact_promotions (
+    promotion_id             INTEGER PRIMARY KEY AUTOINCREMENT,
+    promotion_space_id       INTEGER NOT NULL,
+    promotion_package_id     INTEGER NOT NULL,
+    promotion_version        TEXT NOT NULL,
+    promotion_format         TEXT NOT NULL,
+    promotion_source_view_id INTEGER NOT NULL,
+    promotion_target_view_id INTEGER NOT NULL,
+    promotion_version_id     INTEGER NOT NULL,
+    promotion_move           BOOLEAN NOT NULL DEFAULT FALSE,
+    promotion_created_by     INTEGER,
+    promotion_created        BIGINT
+);
+
+CREATE INDEX idx_promotion_space_id ON artifact_promotions (promotion_space_id, promotion_created);
+CREATE INDEX idx_promotion_version_id ON artifact_promotions (promotion_version_id);
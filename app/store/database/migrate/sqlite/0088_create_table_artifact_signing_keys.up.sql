@@ -0,0 +1,18 @@
+-- Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+-- Use of this source code is covered by the following dual licenses:
+-- (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+-- (2) Affero General Public License 3.0 (AGPL 3.0)
+-- license that can be found in the LICENSE file.
+
+CREATE TABLE artifact_signing_keys (
+    key_id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    key_space_id    INTEGER NOT NULL,
+    key_fingerprint VARCHAR(64) NOT NULL,
+    key_public      TEXT NOT NULL,
+    key_private     BLOB NOT NULL,
+    key_created_by  INTEGER,
+    key_created     BIGINT,
+    key_updated     BIGINT
+);
+
+CREATE UNIQUE INDEX idx_signing_key_space_id ON artifact_signing_keys (key_space_id);
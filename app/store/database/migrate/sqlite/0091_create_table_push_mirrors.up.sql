@@ -0,0 +1,30 @@
+-- Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+-- Use of this source code is covered by the following dual licenses:
+-- (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+-- (2) Affero General Public License 3.0 (AGPL 3.0)
+-- license that can be found in the LICENSE file.
+
+CREATE TABLE push_mirrors (
+    push_mirror_id            INTEGER PRIMARY KEY AUTOINCREMENT,
+    push_mirror_repo_id       INTEGER NOT NULL,
+    push_mirror_url           VARCHAR(1024) NOT NULL,
+    push_mirror_username      VARCHAR(100) NOT NULL DEFAULT '',
+    push_mirror_secret_id     INTEGER,
+    push_mirror_connector_id  INTEGER,
+    push_mirror_branch_filter VARCHAR(1024) NOT NULL DEFAULT '',
+    push_mirror_force         BOOLEAN NOT NULL DEFAULT FALSE,
+    push_mirror_prune         BOOLEAN NOT NULL DEFAULT FALSE,
+    push_mirror_sync_on_push  BOOLEAN NOT NULL DEFAULT FALSE,
+    push_mirror_sync_interval BIGINT NOT NULL DEFAULT 0,
+    push_mirror_enabled       BOOLEAN NOT NULL DEFAULT TRUE,
+    push_mirror_last_attempt  BIGINT NOT NULL DEFAULT 0,
+    push_mirror_last_success  BIGINT NOT NULL DEFAULT 0,
+    push_mirror_last_error    TEXT,
+    push_mirror_next_sync     BIGINT NOT NULL DEFAULT 0,
+    push_mirror_created_by    INTEGER NOT NULL,
+    push_mirror_created       BIGINT NOT NULL,
+    push_mirror_updated       BIGINT NOT NULL
+);
+
+CREATE INDEX idx_push_mirrors_repo_id ON push_mirrors (push_mirror_repo_id);
+CREATE INDEX idx_push_mirrors_next_sync ON push_mirrors (push_mirror_next_sync);
@@ -281,6 +281,10 @@ func (s PublicKeyStore) ListByFingerprint(
 	return mapToPublicKeys(keys), nil
 }
 
+func (s PublicKeyStore) ListByKeyIDs(ctx context.Context, keyIDs []string) ([]types.PublicKey, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
 func (PublicKeyStore) applyQueryFilter(
 	stmt squirrel.SelectBuilder,
 	filter *types.PublicKeyFilter,
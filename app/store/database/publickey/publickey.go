@@ -22,6 +22,7 @@ import (
 	"github.com/easysoft/gitfox/app/store"
 	"github.com/easysoft/gitfox/errors"
 	"github.com/easysoft/gitfox/store/database"
+	"github.com/easysoft/gitfox/store/database/dbtx"
 	"github.com/easysoft/gitfox/types"
 	"github.com/easysoft/gitfox/types/enum"
 
@@ -58,6 +59,12 @@ type publicKey struct {
 	Content     string `gorm:"column:public_key_content"`
 	Comment     string `gorm:"column:public_key_comment"`
 	Type        string `gorm:"column:public_key_type"`
+	Scheme      string `gorm:"column:public_key_scheme"`
+}
+
+type publicKeySubKey struct {
+	PublicKeyID int64  `gorm:"column:public_key_sub_key_public_key_id"`
+	KeyID       string `gorm:"column:public_key_sub_key_id"`
 }
 
 // Find fetches a job by its unique identifier.
@@ -67,9 +74,12 @@ func (s PublicKeyStore) Find(ctx context.Context, id int64) (*types.PublicKey, e
 		return nil, database.ProcessGormSQLErrorf(ctx, err, "Failed to find public key by id")
 	}
 
-	key := mapToPublicKey(result)
+	keys, err := s.mapWithKeyIDs(ctx, []publicKey{*result})
+	if err != nil {
+		return nil, err
+	}
 
-	return &key, nil
+	return &keys[0], nil
 }
 
 // FindByIdentifier returns a public key given a principal ID and an identifier.
@@ -83,21 +93,37 @@ func (s PublicKeyStore) FindByIdentifier(
 		return nil, database.ProcessGormSQLErrorf(ctx, err, "Failed to find public key by principal and identifier")
 	}
 
-	key := mapToPublicKey(result)
+	keys, err := s.mapWithKeyIDs(ctx, []publicKey{*result})
+	if err != nil {
+		return nil, err
+	}
 
-	return &key, nil
+	return &keys[0], nil
 }
 
-// Create creates a new public key.
+// Create creates a new public key, the key IDs of a PGP key are stored along.
 func (s PublicKeyStore) Create(ctx context.Context, key *types.PublicKey) error {
+	db := dbtx.GetOrmAccessor(ctx, s.db)
 	dbKey := mapToInternalPublicKey(key)
 
-	if err := s.db.Create(&dbKey).Error; err != nil {
+	if err := db.Create(&dbKey).Error; err != nil {
 		return database.ProcessGormSQLErrorf(ctx, err, "Failed to create public key")
 	}
 
 	key.ID = dbKey.ID
 
+	if len(key.KeyIDs) == 0 {
+		return nil
+	}
+
+	subKeys := make([]publicKeySubKey, len(key.KeyIDs))
+	for i, keyID := range key.KeyIDs {
+		subKeys[i] = publicKeySubKey{PublicKeyID: key.ID, KeyID: keyID}
+	}
+	if err := db.Create(&subKeys).Error; err != nil {
+		return database.ProcessGormSQLErrorf(ctx, err, "Failed to create public key sub keys")
+	}
+
 	return nil
 }
 
@@ -153,7 +179,7 @@ func (s PublicKeyStore) List(
 		return nil, database.ProcessGormSQLErrorf(ctx, err, "Failed to list public keys")
 	}
 
-	return mapToPublicKeys(keys), nil
+	return s.mapWithKeyIDs(ctx, keys)
 }
 
 // ListByFingerprint returns public keys given a fingerprint and key usage.
@@ -171,6 +197,60 @@ func (s PublicKeyStore) ListByFingerprint(
 	return mapToPublicKeys(keys), nil
 }
 
+// ListByKeyIDs returns the PGP public keys having a primary key or a sub key with one of the IDs.
+func (s PublicKeyStore) ListByKeyIDs(
+	ctx context.Context,
+	keyIDs []string,
+) ([]types.PublicKey, error) {
+	if len(keyIDs) == 0 {
+		return nil, nil
+	}
+
+	subQuery := s.db.Model(&publicKeySubKey{}).
+		Select("public_key_sub_key_public_key_id").
+		Where("public_key_sub_key_id IN ?", keyIDs)
+	stmt := s.db.Model(&publicKey{}).
+		Where("public_key_id IN (?)", subQuery).
+		Order("public_key_created ASC")
+
+	keys := make([]publicKey, 0)
+	if err := stmt.Find(&keys).Error; err != nil {
+		return nil, database.ProcessGormSQLErrorf(ctx, err, "Failed to list public keys by key IDs")
+	}
+
+	return s.mapWithKeyIDs(ctx, keys)
+}
+
+// mapWithKeyIDs maps the keys and loads the key IDs of the PGP keys.
+func (s PublicKeyStore) mapWithKeyIDs(ctx context.Context, keys []publicKey) ([]types.PublicKey, error) {
+	res := mapToPublicKeys(keys)
+
+	ids := make([]int64, 0, len(keys))
+	for _, key := range keys {
+		if key.Scheme == string(enum.PublicKeySchemePGP) {
+			ids = append(ids, key.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return res, nil
+	}
+
+	subKeys := make([]publicKeySubKey, 0)
+	if err := s.db.Where("public_key_sub_key_public_key_id IN ?", ids).Find(&subKeys).Error; err != nil {
+		return nil, database.ProcessGormSQLErrorf(ctx, err, "Failed to list public key sub keys")
+	}
+
+	keyIDs := make(map[int64][]string, len(ids))
+	for _, subKey := range subKeys {
+		keyIDs[subKey.PublicKeyID] = append(keyIDs[subKey.PublicKeyID], subKey.KeyID)
+	}
+	for i := range res {
+		res[i].KeyIDs = keyIDs[res[i].ID]
+	}
+
+	return res, nil
+}
+
 func (PublicKeyStore) applyQueryFilter(
 	stmt *gorm.DB,
 	filter *types.PublicKeyFilter,
@@ -217,6 +297,7 @@ func mapToInternalPublicKey(in *types.PublicKey) publicKey {
 		Content:     in.Content,
 		Comment:     in.Comment,
 		Type:        in.Type,
+		Scheme:      string(in.Scheme),
 	}
 }
 
@@ -232,6 +313,7 @@ func mapToPublicKey(in *publicKey) types.PublicKey {
 		Content:     in.Content,
 		Comment:     in.Comment,
 		Type:        in.Type,
+		Scheme:      enum.PublicKeyScheme(in.Scheme),
 	}
 }
 
@@ -0,0 +1,246 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package pushmirror
+
+import (
+	"context"
+	"time"
+
+	"github.com/easysoft/gitfox/app/store"
+	gitfox_store "github.com/easysoft/gitfox/store"
+	"github.com/easysoft/gitfox/store/database"
+	"github.com/easysoft/gitfox/store/database/dbtx"
+	"github.com/easysoft/gitfox/types"
+	"github.com/easysoft/gitfox/types/enum"
+
+	"github.com/guregu/null"
+	"gorm.io/gorm"
+)
+
+var _ store.PushMirrorStore = (*Store)(nil)
+
+// NewStore returns a new push mirror Store.
+func NewStore(db *gorm.DB) *Store {
+	return &Store{
+		db: db,
+	}
+}
+
+// Store implements a store.PushMirrorStore backed by a relational database.
+type Store struct {
+	db *gorm.DB
+}
+
+type pushMirror struct {
+	ID            int64       `gorm:"column:push_mirror_id;primaryKey"`
+	RepoID        int64       `gorm:"column:push_mirror_repo_id"`
+	URL           string      `gorm:"column:push_mirror_url"`
+	Username      string      `gorm:"column:push_mirror_username"`
+	SecretID      null.Int    `gorm:"column:push_mirror_secret_id"`
+	ConnectorID   null.Int    `gorm:"column:push_mirror_connector_id"`
+	CredentialsBy null.Int    `gorm:"column:push_mirror_credentials_by"`
+	BranchFilter  string      `gorm:"column:push_mirror_branch_filter"`
+	Force         bool        `gorm:"column:push_mirror_force"`
+	Prune         bool        `gorm:"column:push_mirror_prune"`
+	SyncOnPush    bool        `gorm:"column:push_mirror_sync_on_push"`
+	SyncInterval  int64       `gorm:"column:push_mirror_sync_interval"`
+	Enabled       bool        `gorm:"column:push_mirror_enabled"`
+	LastAttempt   int64       `gorm:"column:push_mirror_last_attempt"`
+	LastSuccess   int64       `gorm:"column:push_mirror_last_success"`
+	LastError     null.String `gorm:"column:push_mirror_last_error"`
+	NextSync      int64       `gorm:"column:push_mirror_next_sync"`
+	CreatedBy     int64       `gorm:"column:push_mirror_created_by"`
+	Created       int64       `gorm:"column:push_mirror_created"`
+	Updated       int64       `gorm:"column:push_mirror_updated"`
+}
+
+// Find finds the push mirror by the id.
+func (s *Store) Find(ctx context.Context, id int64) (*types.PushMirror, error) {
+	dst := &pushMirror{}
+	if err := dbtx.GetOrmAccessor(ctx, s.db).
+		Where("push_mirror_id = ?", id).
+		First(dst).Error; err != nil {
+		return nil, database.ProcessGormSQLErrorf(ctx, err, "Failed to find push mirror %d", id)
+	}
+	return mapToPushMirror(dst), nil
+}
+
+// FindByRepo finds the push mirror of the repository by the id.
+func (s *Store) FindByRepo(ctx context.Context, repoID, id int64) (*types.PushMirror, error) {
+	dst := &pushMirror{}
+	if err := dbtx.GetOrmAccessor(ctx, s.db).
+		Where("push_mirror_repo_id = ? AND push_mirror_id = ?", repoID, id).
+		First(dst).Error; err != nil {
+		return nil, database.ProcessGormSQLErrorf(ctx, err, "Failed to find push mirror %d", id)
+	}
+	return mapToPushMirror(dst), nil
+}
+
+// List lists the push mirrors of the repository ordered by the id.
+func (s *Store) List(ctx context.Context, repoID int64) ([]*types.PushMirror, error) {
+	dst := make([]*pushMirror, 0)
+	if err := dbtx.GetOrmAccessor(ctx, s.db).
+		Where("push_mirror_repo_id = ?", repoID).
+		Order("push_mirror_id ASC").
+		Find(&dst).Error; err != nil {
+		return nil, database.ProcessGormSQLErrorf(ctx, err, "Failed to list push mirrors")
+	}
+	return mapToPushMirrors(dst), nil
+}
+
+// ListDue lists the enabled push mirrors of the active repositories whose next push is due at the time.
+func (s *Store) ListDue(ctx context.Context, now int64) ([]*types.PushMirror, error) {
+	dst := make([]*pushMirror, 0)
+	if err := dbtx.GetOrmAccessor(ctx, s.db).
+		Select("push_mirrors.*").
+		Joins("INNER JOIN repositories ON repositories.repo_id = push_mirrors.push_mirror_repo_id").
+		Where("repositories.repo_deleted IS NULL AND repositories.repo_state = ?", enum.RepoStateActive).
+		Where("push_mirror_enabled = ? AND push_mirror_next_sync > 0 AND push_mirror_next_sync <= ?", true, now).
+		Order("push_mirror_next_sync ASC").
+		Find(&dst).Error; err != nil {
+		return nil, database.ProcessGormSQLErrorf(ctx, err, "Failed to list due push mirrors")
+	}
+	return mapToPushMirrors(dst), nil
+}
+
+// Create creates a new push mirror.
+func (s *Store) Create(ctx context.Context, mirror *types.PushMirror) error {
+	dbMirror := mapToInternalPushMirror(mirror)
+	if err := dbtx.GetOrmAccessor(ctx, s.db).Create(dbMirror).Error; err != nil {
+		return database.ProcessGormSQLErrorf(ctx, err, "Failed to create push mirror")
+	}
+	mirror.ID = dbMirror.ID
+	return nil
+}
+
+// Update updates the settings of the push mirror, the sync status isn't changed.
+func (s *Store) Update(ctx context.Context, mirror *types.PushMirror) error {
+	mirror.Updated = time.Now().UnixMilli()
+	dbMirror := mapToInternalPushMirror(mirror)
+	res := dbtx.GetOrmAccessor(ctx, s.db).Model(dbMirror).
+		Select("push_mirror_url", "push_mirror_username", "push_mirror_secret_id", "push_mirror_connector_id",
+			"push_mirror_credentials_by", "push_mirror_branch_filter", "push_mirror_force", "push_mirror_prune", "push_mirror_sync_on_push",
+			"push_mirror_sync_interval", "push_mirror_enabled", "push_mirror_next_sync", "push_mirror_updated").
+		Updates(dbMirror)
+	if res.Error != nil {
+		return database.ProcessGormSQLErrorf(ctx, res.Error, "Failed to update push mirror %d", mirror.ID)
+	}
+	if res.RowsAffected == 0 {
+		return gitfox_store.ErrResourceNotFound
+	}
+	return nil
+}
+
+// Delete deletes the push mirror of the repository by the id.
+func (s *Store) Delete(ctx context.Context, repoID, id int64) error {
+	res := dbtx.GetOrmAccessor(ctx, s.db).
+		Where("push_mirror_repo_id = ? AND push_mirror_id = ?", repoID, id).
+		Delete(&pushMirror{})
+	if res.Error != nil {
+		return database.ProcessGormSQLErrorf(ctx, res.Error, "Failed to delete push mirror %d", id)
+	}
+	if res.RowsAffected == 0 {
+		return gitfox_store.ErrResourceNotFound
+	}
+	return nil
+}
+
+// MarkDue schedules the push mirror to be pushed by the scheduler at the time.
+func (s *Store) MarkDue(ctx context.Context, id int64, now int64) error {
+	if err := dbtx.GetOrmAccessor(ctx, s.db).Model(&pushMirror{}).
+		Where("push_mirror_id = ?", id).
+		Update("push_mirror_next_sync", now).Error; err != nil {
+		return database.ProcessGormSQLErrorf(ctx, err, "Failed to mark push mirror %d due", id)
+	}
+	return nil
+}
+
+// UpdateSyncStatus records the result of the push attempted at the time and schedules the next push
+// by the sync interval, an empty syncErr marks the attempt succeeded. The mirror marked due during
+// the push stays due, so the refs updated meanwhile are pushed as well.
+func (s *Store) UpdateSyncStatus(ctx context.Context, id int64, attempt int64, syncErr string) error {
+	updates := map[string]any{
+		"push_mirror_last_attempt": attempt,
+		"push_mirror_last_error":   null.NewString(syncErr, syncErr != ""),
+		"push_mirror_next_sync": gorm.Expr(
+			"CASE WHEN push_mirror_next_sync >= ? THEN push_mirror_next_sync "+
+				"WHEN push_mirror_sync_interval > 0 THEN ? + push_mirror_sync_interval * 60 ELSE 0 END",
+			attempt, attempt),
+	}
+	if syncErr == "" {
+		updates["push_mirror_last_success"] = attempt
+	}
+
+	res := dbtx.GetOrmAccessor(ctx, s.db).Model(&pushMirror{}).
+		Where("push_mirror_id = ?", id).
+		Updates(updates)
+	if res.Error != nil {
+		return database.ProcessGormSQLErrorf(ctx, res.Error, "Failed to update push mirror %d status", id)
+	}
+	if res.RowsAffected == 0 {
+		return gitfox_store.ErrResourceNotFound
+	}
+	return nil
+}
+
+func mapToPushMirrors(in []*pushMirror) []*types.PushMirror {
+	res := make([]*types.PushMirror, len(in))
+	for i := range in {
+		res[i] = mapToPushMirror(in[i])
+	}
+	return res
+}
+
+func mapToPushMirror(in *pushMirror) *types.PushMirror {
+	return &types.PushMirror{
+		ID:            in.ID,
+		RepoID:        in.RepoID,
+		URL:           in.URL,
+		Username:      in.Username,
+		SecretID:      in.SecretID,
+		ConnectorID:   in.ConnectorID,
+		CredentialsBy: in.CredentialsBy,
+		BranchFilter:  in.BranchFilter,
+		Force:         in.Force,
+		Prune:         in.Prune,
+		SyncOnPush:    in.SyncOnPush,
+		SyncInterval:  in.SyncInterval,
+		Enabled:       in.Enabled,
+		LastAttempt:   in.LastAttempt,
+		LastSuccess:   in.LastSuccess,
+		LastError:     in.LastError.String,
+		NextSync:      in.NextSync,
+		CreatedBy:     in.CreatedBy,
+		Created:       in.Created,
+		Updated:       in.Updated,
+	}
+}
+
+func mapToInternalPushMirror(in *types.PushMirror) *pushMirror {
+	return &pushMirror{
+		ID:            in.ID,
+		RepoID:        in.RepoID,
+		URL:           in.URL,
+		Username:      in.Username,
+		SecretID:      in.SecretID,
+		ConnectorID:   in.ConnectorID,
+		CredentialsBy: in.CredentialsBy,
+		BranchFilter:  in.BranchFilter,
+		Force:         in.Force,
+		Prune:         in.Prune,
+		SyncOnPush:    in.SyncOnPush,
+		SyncInterval:  in.SyncInterval,
+		Enabled:       in.Enabled,
+		LastAttempt:   in.LastAttempt,
+		LastSuccess:   in.LastSuccess,
+		LastError:     null.NewString(in.LastError, in.LastError != ""),
+		NextSync:      in.NextSync,
+		CreatedBy:     in.CreatedBy,
+		Created:       in.Created,
+		Updated:       in.Updated,
+	}
+}
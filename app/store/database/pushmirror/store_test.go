@@ -0,0 +1,126 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package pushmirror_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/easysoft/gitfox/app/store/database/pushmirror"
+	"github.com/easysoft/gitfox/app/store/database/testsuite"
+	"github.com/easysoft/gitfox/types"
+
+	"github.com/guregu/null"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type PushMirrorSuite struct {
+	testsuite.BaseSuite
+
+	store *pushmirror.Store
+}
+
+func TestPushMirrorSuite(t *testing.T) {
+	st := &PushMirrorSuite{
+		BaseSuite: testsuite.BaseSuite{
+			Ctx:  context.Background(),
+			Name: "push_mirrors",
+		},
+	}
+
+	st.BaseSuite.Constructor = func(ts *testsuite.TestStore) {
+		st.store = pushmirror.NewStore(st.Gdb)
+
+		testsuite.AddUser(st.Ctx, t, ts.Principal, 1, true)
+		testsuite.AddSpace(st.Ctx, t, ts.Space, ts.SpacePath, 1, 1, 0)
+		testsuite.AddRepo(st.Ctx, t, ts.Repo, 1, 1, 0)
+	}
+
+	suite.Run(t, st)
+}
+
+func (suite *PushMirrorSuite) TearDownTest() {
+	suite.Gdb.WithContext(suite.Ctx).Table("push_mirrors").Where("1 = 1").Delete(nil)
+}
+
+func (suite *PushMirrorSuite) TestListDue() {
+	now := time.Now().Unix()
+	// the disabled mirror and the mirror without the schedule are never due
+	for _, m := range []*types.PushMirror{
+		{RepoID: 1, URL: "https://example.com/a.git", Enabled: true, SyncInterval: 10, NextSync: now},
+		{RepoID: 1, URL: "https://example.com/b.git", Enabled: false, SyncInterval: 10, NextSync: now},
+		{RepoID: 1, URL: "https://example.com/c.git", Enabled: true},
+	} {
+		require.NoError(suite.T(), suite.store.Create(suite.Ctx, m))
+	}
+
+	due, err := suite.store.ListDue(suite.Ctx, now-1)
+	require.NoError(suite.T(), err)
+	require.Empty(suite.T(), due)
+
+	due, err = suite.store.ListDue(suite.Ctx, now)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), due, 1)
+	require.Equal(suite.T(), "https://example.com/a.git", due[0].URL)
+
+	list, err := suite.store.List(suite.Ctx, 1)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), list, 3)
+}
+
+func (suite *PushMirrorSuite) TestUpdateSyncStatus() {
+	m := &types.PushMirror{RepoID: 1, URL: "https://example.com/a.git", Enabled: true, SyncInterval: 10}
+	require.NoError(suite.T(), suite.store.Create(suite.Ctx, m))
+
+	attempt := time.Now().Unix()
+	require.NoError(suite.T(), suite.store.UpdateSyncStatus(suite.Ctx, m.ID, attempt, "remote rejected"))
+	res, err := suite.store.Find(suite.Ctx, m.ID)
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), "remote rejected", res.LastError)
+	require.Equal(suite.T(), attempt, res.LastAttempt)
+	require.Zero(suite.T(), res.LastSuccess)
+	require.Equal(suite.T(), attempt+600, res.NextSync)
+
+	// the mirror marked due during the push stays due
+	require.NoError(suite.T(), suite.store.MarkDue(suite.Ctx, m.ID, attempt+1))
+	require.NoError(suite.T(), suite.store.UpdateSyncStatus(suite.Ctx, m.ID, attempt, ""))
+	res, err = suite.store.Find(suite.Ctx, m.ID)
+	require.NoError(suite.T(), err)
+	require.Empty(suite.T(), res.LastError)
+	require.Equal(suite.T(), attempt, res.LastSuccess)
+	require.Equal(suite.T(), attempt+1, res.NextSync)
+
+	// the push isn't scheduled without the sync interval
+	res.SyncInterval = 0
+	require.NoError(suite.T(), suite.store.Update(suite.Ctx, res))
+	require.NoError(suite.T(), suite.store.UpdateSyncStatus(suite.Ctx, m.ID, attempt+2, ""))
+	res, err = suite.store.Find(suite.Ctx, m.ID)
+	require.NoError(suite.T(), err)
+	require.Zero(suite.T(), res.NextSync)
+}
+
+func (suite *PushMirrorSuite) TestUpdateCredentials() {
+	m := &types.PushMirror{RepoID: 1, URL: "https://example.com/a.git", Enabled: true}
+	require.NoError(suite.T(), suite.store.Create(suite.Ctx, m))
+
+	m.SecretID = null.IntFrom(1)
+	m.CredentialsBy = null.IntFrom(1)
+	require.NoError(suite.T(), suite.store.Update(suite.Ctx, m))
+	res, err := suite.store.Find(suite.Ctx, m.ID)
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), null.IntFrom(1), res.SecretID)
+	require.Equal(suite.T(), null.IntFrom(1), res.CredentialsBy)
+
+	res.SecretID, res.CredentialsBy = null.Int{}, null.Int{}
+	require.NoError(suite.T(), suite.store.Update(suite.Ctx, res))
+	res, err = suite.store.Find(suite.Ctx, m.ID)
+	require.NoError(suite.T(), err)
+	require.False(suite.T(), res.SecretID.Valid)
+	require.False(suite.T(), res.CredentialsBy.Valid)
+}
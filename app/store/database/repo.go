@@ -971,3 +971,19 @@ func (s *RepoStore) GetMirror(ctx context.Context, id int64) (*types.RepositoryM
 func (s *RepoStore) UpdateMirror(ctx context.Context, opts *types.RepositoryMirror) error {
 	return fmt.Errorf("not implemented")
 }
+
+func (s *RepoStore) ListDueMirrors(ctx context.Context, now int64) ([]*types.RepositoryMirror, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (s *RepoStore) UpdateMirrorSyncStatus(ctx context.Context, repoID int64, syncErr string) error {
+	return fmt.Errorf("not implemented")
+}
+
+func (s *RepoStore) CountForks(ctx context.Context, repoID int64, opts *types.RepoFilter) (int64, error) {
+	return 0, fmt.Errorf("not implemented")
+}
+
+func (s *RepoStore) ListForks(ctx context.Context, repoID int64, opts *types.RepoFilter) ([]*types.Repository, error) {
+	return nil, fmt.Errorf("not implemented")
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package repo_test
+
+import (
+	"time"
+
+	"github.com/easysoft/gitfox/app/store/database/testsuite"
+	"github.com/easysoft/gitfox/types"
+
+	"github.com/stretchr/testify/require"
+)
+
+func (suite *RepoSuite) TestListForks() {
+	// repo_3, repo_4 and repo_5 are forks of repo_1, repo_6 is a fork of repo_3
+	for forkID, ids := range map[int64][]int64{1: {3, 4, 5}, 3: {6}} {
+		err := suite.Gdb.WithContext(suite.Ctx).Table(testTableRepo).
+			Where("repo_id IN ?", ids).Update("repo_fork_id", forkID).Error
+		require.NoError(suite.T(), err)
+	}
+
+	now := time.Now().UnixMilli()
+	obj, err := suite.ormStore.Find(suite.Ctx, 5)
+	require.NoError(suite.T(), err)
+	require.NoError(suite.T(), suite.ormStore.SoftDelete(suite.Ctx, obj, now-1))
+
+	tests := []struct {
+		repoID     int64
+		filter     types.RepoFilter
+		wantLength int64
+		wantCount  int64
+	}{
+		{1, types.RepoFilter{}, 2, 2},
+		{1, types.RepoFilter{Page: 2, Size: 1}, 1, 2},
+		{1, types.RepoFilter{DeletedBeforeOrAt: &now}, 1, 1},
+		{3, types.RepoFilter{}, 1, 1},
+		{2, types.RepoFilter{}, 0, 0},
+	}
+
+	for id, test := range tests {
+		repos, err := suite.ormStore.ListForks(suite.Ctx, test.repoID, &test.filter)
+		require.NoError(suite.T(), err, testsuite.InvalidLoopMsgF, id)
+		require.EqualValues(suite.T(), test.wantLength, len(repos), testsuite.InvalidLoopMsgF, id)
+		for _, fork := range repos {
+			require.Equal(suite.T(), test.repoID, fork.ForkID, testsuite.InvalidLoopMsgF, id)
+		}
+
+		count, err := suite.ormStore.CountForks(suite.Ctx, test.repoID, &test.filter)
+		require.NoError(suite.T(), err, testsuite.InvalidLoopMsgF, id)
+		require.EqualValues(suite.T(), test.wantCount, count, testsuite.InvalidLoopMsgF, id)
+	}
+}
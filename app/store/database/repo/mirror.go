@@ -13,6 +13,7 @@ import (
 
 	"github.com/easysoft/gitfox/store/database/dbtx"
 	"github.com/easysoft/gitfox/types"
+	"github.com/easysoft/gitfox/types/enum"
 
 	"gorm.io/gorm"
 )
@@ -31,6 +32,7 @@ type mirrors struct {
 	NextUpdateUnix int64  `gorm:"column:next_update_unix"`
 	LfsEnabled     bool   `gorm:"column:lfs_enabled"`
 	RemoteAddress  string `gorm:"column:remote_address"`
+	LastError      string `gorm:"column:last_error"`
 }
 
 func (s *OrmStore) mapToMirror(
@@ -44,6 +46,7 @@ func (s *OrmStore) mapToMirror(
 		NextUpdateUnix: in.NextUpdateUnix,
 		LfsEnabled:     in.LfsEnabled,
 		RemoteAddress:  in.RemoteAddress,
+		LastError:      in.LastError,
 		RepoID:         in.RepoID,
 		SpaceID:        in.SpaceID,
 	}
@@ -105,3 +108,32 @@ func (s *OrmStore) UpdateMirror(ctx context.Context, opts *types.RepositoryMirro
 	dst.NextUpdateUnix = dst.UpdatedUnix + dst.SyncInterval*60
 	return dbtx.GetOrmAccessor(ctx, s.db).Table(mirrorTable).Save(dst).Error
 }
+
+// ListDueMirrors lists the mirrors of the active repositories whose next sync is due at the time.
+func (s *OrmStore) ListDueMirrors(ctx context.Context, now int64) ([]*types.RepositoryMirror, error) {
+	var dst []*types.RepositoryMirror
+	err := dbtx.GetOrmAccessor(ctx, s.db).Table(mirrorTable).
+		Select("mirrors.*, repositories.repo_git_uid").
+		Joins("INNER JOIN repositories ON mirrors.repo_id = repositories.repo_id").
+		Where("repositories.repo_deleted IS NULL AND repositories.repo_mirror = ? AND repositories.repo_state = ?",
+			true, enum.RepoStateActive).
+		Where("mirrors.sync_interval > 0 AND (mirrors.next_update_unix IS NULL OR mirrors.next_update_unix <= ?)", now).
+		Order("mirrors.next_update_unix").
+		Scan(&dst).Error
+	return dst, err
+}
+
+// UpdateMirrorSyncStatus records the result of a sync and schedules the next sync of the mirror,
+// an empty syncErr clears the error of the last sync.
+func (s *OrmStore) UpdateMirrorSyncStatus(ctx context.Context, repoID int64, syncErr string) error {
+	dst := new(mirrors)
+	err := dbtx.GetOrmAccessor(ctx, s.db).Table(mirrorTable).Where("repo_id = ?", repoID).First(dst).Error
+	if err != nil {
+		return fmt.Errorf("failed to find mirror: %w", err)
+	}
+	now := time.Now().Unix()
+	dst.UpdatedUnix = now
+	dst.NextUpdateUnix = now + dst.SyncInterval*60
+	dst.LastError = syncErr
+	return dbtx.GetOrmAccessor(ctx, s.db).Table(mirrorTable).Save(dst).Error
+}
@@ -0,0 +1,54 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package repo_test
+
+import (
+	"time"
+
+	"github.com/easysoft/gitfox/types"
+
+	"github.com/stretchr/testify/require"
+)
+
+func (suite *RepoSuite) TestMirrorSyncStatus() {
+	defer suite.Gdb.WithContext(suite.Ctx).Table("mirrors").Where("1 = 1").Delete(nil)
+
+	err := suite.Gdb.WithContext(suite.Ctx).Table(testTableRepo).
+		Where("repo_id IN ?", []int64{1, 2}).Update("repo_mirror", true).Error
+	require.NoError(suite.T(), err)
+
+	now := time.Now().Unix()
+	// repo_2 has the scheduled sync disabled, repo_3 isn't a mirror repository
+	for _, m := range []types.RepositoryMirror{
+		{RepoID: 1, SpaceID: 1, SyncInterval: 1, RemoteAddress: "https://example.com/a.git"},
+		{RepoID: 2, SpaceID: 3, SyncInterval: 0, RemoteAddress: "https://example.com/b.git"},
+		{RepoID: 3, SpaceID: 2, SyncInterval: 1, RemoteAddress: "https://example.com/c.git"},
+	} {
+		require.NoError(suite.T(), suite.ormStore.CreateOrUpdateMirror(suite.Ctx, &m))
+	}
+
+	due, err := suite.ormStore.ListDueMirrors(suite.Ctx, now)
+	require.NoError(suite.T(), err)
+	require.Empty(suite.T(), due)
+
+	due, err = suite.ormStore.ListDueMirrors(suite.Ctx, now+120)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), due, 1)
+	require.Equal(suite.T(), int64(1), due[0].RepoID)
+	require.NotEmpty(suite.T(), due[0].RepoGitUID)
+
+	require.NoError(suite.T(), suite.ormStore.UpdateMirrorSyncStatus(suite.Ctx, 1, "remote not found"))
+	mirror, err := suite.ormStore.GetMirror(suite.Ctx, 1)
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), "remote not found", mirror.LastError)
+	require.Equal(suite.T(), mirror.UpdatedUnix+60, mirror.NextUpdateUnix)
+
+	require.NoError(suite.T(), suite.ormStore.UpdateMirrorSyncStatus(suite.Ctx, 1, ""))
+	mirror, err = suite.ormStore.GetMirror(suite.Ctx, 1)
+	require.NoError(suite.T(), err)
+	require.Empty(suite.T(), mirror.LastError)
+}
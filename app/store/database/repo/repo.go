@@ -601,6 +601,34 @@ func (s *OrmStore) ListAllWithoutParent(ctx context.Context, filter *types.RepoF
 	return s.list(ctx, []int64{}, filter)
 }
 
+// CountForks counts the active forks of the repo.
+// With "DeletedBeforeOrAt" filter, counts deleted forks by opts.DeletedBeforeOrAt.
+func (s *OrmStore) CountForks(ctx context.Context, repoID int64, filter *types.RepoFilter) (int64, error) {
+	stmt := dbtx.GetOrmAccessor(ctx, s.db).Table(repoTable).Where("repo_fork_id = ?", repoID)
+	stmt = applyQueryFilter(stmt, filter)
+
+	var count int64
+	if err := stmt.Count(&count).Error; err != nil {
+		return 0, database.ProcessGormSQLErrorf(ctx, err, "Failed executing count forks query")
+	}
+	return count, nil
+}
+
+// ListForks returns a list of active forks of the repo.
+// With "DeletedBeforeOrAt" filter, lists deleted forks by opts.DeletedBeforeOrAt.
+func (s *OrmStore) ListForks(ctx context.Context, repoID int64, filter *types.RepoFilter) ([]*types.Repository, error) {
+	stmt := dbtx.GetOrmAccessor(ctx, s.db).Table(repoTable).Where("repo_fork_id = ?", repoID)
+	stmt = applyQueryFilter(stmt, filter)
+	stmt = applySortFilter(stmt, filter)
+
+	dst := []*repository{}
+	if err := stmt.Find(&dst).Error; err != nil {
+		return nil, database.ProcessGormSQLErrorf(ctx, err, "Failed executing list forks query")
+	}
+
+	return s.mapToRepos(ctx, dst)
+}
+
 func (s *OrmStore) GetPublicAccess(ctx context.Context, id int64) (bool, error) {
 	stmt := dbtx.GetOrmAccessor(ctx, s.db).Table(repoTable).Select("repo_is_public").Where("repo_id = ?", id)
 	var isPublic bool
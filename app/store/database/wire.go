@@ -22,15 +22,18 @@ import (
 	aiorm "github.com/easysoft/gitfox/app/store/database/ai"
 	"github.com/easysoft/gitfox/app/store/database/artifacts"
 	connectorsorm "github.com/easysoft/gitfox/app/store/database/connectors"
+	gitsignatureorm "github.com/easysoft/gitfox/app/store/database/gitsignature"
 	"github.com/easysoft/gitfox/app/store/database/gitspace"
 	infraproviderorm "github.com/easysoft/gitfox/app/store/database/infraprovider"
 	labelsorm "github.com/easysoft/gitfox/app/store/database/labels"
+	lfsorm "github.com/easysoft/gitfox/app/store/database/lfs"
 	"github.com/easysoft/gitfox/app/store/database/migrate"
 	"github.com/easysoft/gitfox/app/store/database/pipeline"
 	principalorm "github.com/easysoft/gitfox/app/store/database/principal"
 	"github.com/easysoft/gitfox/app/store/database/publicaccess"
 	publickeyorm "github.com/easysoft/gitfox/app/store/database/publickey"
 	"github.com/easysoft/gitfox/app/store/database/pullreq"
+	pushmirrororm "github.com/easysoft/gitfox/app/store/database/pushmirror"
 	"github.com/easysoft/gitfox/app/store/database/repo"
 	spaceorm "github.com/easysoft/gitfox/app/store/database/space"
 	"github.com/easysoft/gitfox/app/store/database/system"
@@ -95,6 +98,10 @@ var WireSet = wire.NewSet(
 	ProvidePullReqLabelStore,
 	ProvideInfraProviderTemplateStore,
 	ProvideInfraProvisionedStore,
+	ProvideLFSObjectStore,
+	ProvideLFSLockStore,
+	ProvidePushMirrorStore,
+	ProvideGitSignatureResultStore,
 )
 
 // WireSetOrm provides a wire orm set for this package.
@@ -429,3 +436,23 @@ func ProvideInfraProvisionedStore(db *gorm.DB) store.InfraProvisionedStore {
 func ProvideAIStore(db *gorm.DB) store.AIStore {
 	return aiorm.NewAIStore(db)
 }
+
+// ProvideLFSObjectStore provides a lfs object store.
+func ProvideLFSObjectStore(db *gorm.DB) store.LFSObjectStore {
+	return lfsorm.NewLFSObjectStore(db)
+}
+
+// ProvideLFSLockStore provides a lfs lock store.
+func ProvideLFSLockStore(db *gorm.DB) store.LFSLockStore {
+	return lfsorm.NewLFSLockStore(db)
+}
+
+// ProvidePushMirrorStore provides a push mirror store.
+func ProvidePushMirrorStore(db *gorm.DB) store.PushMirrorStore {
+	return pushmirrororm.NewStore(db)
+}
+
+// ProvideGitSignatureResultStore provides a git signature result store.
+func ProvideGitSignatureResultStore(db *gorm.DB) store.GitSignatureResultStore {
+	return gitsignatureorm.NewStore(db)
+}
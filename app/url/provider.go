@@ -87,6 +87,9 @@ type Provider interface {
 	GenerateRegistryURL(segments ...string) *url.URL
 	// RegistryURL returns the url for oci token endpoint
 	RegistryURL() string
+
+	// GenerateArtifactURL return the artifact format endpoint
+	GenerateArtifactURL(segments ...string) *url.URL
 }
 
 // Provider provides the URLs of the Harness system.
@@ -259,6 +262,12 @@ func (p *provider) RegistryURL() string {
 	return p.registryURL.String()
 }
 
+func (p *provider) GenerateArtifactURL(segments ...string) *url.URL {
+	l := []string{ArtifactMount}
+	l = append(l, segments...)
+	return p.uiURL.JoinPath(l...)
+}
+
 func BuildGITCloneSSHURL(user string, sshURL *url.URL, repoPath string) string {
 	repoPath = path.Clean(repoPath)
 	if !strings.HasSuffix(repoPath, GITSuffix) {
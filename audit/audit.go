@@ -76,6 +76,7 @@ const (
 	ResourceTypeRepositorySettings    ResourceType = "repository_settings"
 	ResourceTypeRegistry              ResourceType = "registry"
 	ResourceTypeRegistryUpstreamProxy ResourceType = "registry_upstream_proxy"
+	ResourceTypePushMirror            ResourceType = "push_mirror"
 )
 
 func (a ResourceType) Validate() error {
@@ -86,7 +87,8 @@ func (a ResourceType) Validate() error {
 		ResourceTypePullRequest,
 		ResourceTypeRepositorySettings,
 		ResourceTypeRegistry,
-		ResourceTypeRegistryUpstreamProxy:
+		ResourceTypeRegistryUpstreamProxy,
+		ResourceTypePushMirror:
 		return nil
 
 	default:
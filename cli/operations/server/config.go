@@ -286,6 +286,7 @@ func ProvideDatabaseConfig(config *types.Config) database.Config {
 
 // ProvideStorageConfig loads the storage config from the main config.
 func ProvideStorageConfig(config *types.Config) storage.Config {
+	s3 := config.Registry.Storage.S3Storage
 	return storage.Config{
 		Local: storage.DriverConfig{
 			Driver: storage.DriverFilesystem,
@@ -293,7 +294,30 @@ func ProvideStorageConfig(config *types.Config) storage.Config {
 				"rootdirectory": config.Storage.Local.Directory,
 			},
 		},
-		S3: storage.DriverConfig{},
+		S3: storage.DriverConfig{
+			Driver: storage.DriverS3,
+			Parameters: map[string]interface{}{
+				"accesskey":                   s3.AccessKey,
+				"secretkey":                   s3.SecretKey,
+				"region":                      s3.Region,
+				"regionendpoint":              s3.RegionEndpoint,
+				"forcepathstyle":              s3.ForcePathStyle,
+				"accelerate":                  s3.Accelerate,
+				"bucket":                      s3.Bucket,
+				"encrypt":                     s3.Encrypt,
+				"keyid":                       s3.KeyID,
+				"secure":                      s3.Secure,
+				"v4auth":                      s3.V4Auth,
+				"chunksize":                   s3.ChunkSize,
+				"multipartcopychunksize":      s3.MultipartCopyChunkSize,
+				"multipartcopymaxconcurrency": s3.MultipartCopyMaxConcurrency,
+				"multipartcopythresholdsize":  s3.MultipartCopyThresholdSize,
+				"rootdirectory":               s3.RootDirectory,
+				"usedualstack":                s3.UseDualStack,
+				"loglevel":                    s3.LogLevel,
+				"redirect":                    s3.Redirect,
+			},
+		},
 	}
 }
 
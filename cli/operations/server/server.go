@@ -100,6 +100,21 @@ func (c *command) run(*kingpin.ParseContext) error {
 			}
 		}
 
+		if err := system.services.MirrorSync.Register(gCtx); err != nil {
+			log.Error().Err(err).Msg("failed to register mirror sync service")
+			return err
+		}
+
+		if err := system.services.PushMirror.Register(gCtx); err != nil {
+			log.Error().Err(err).Msg("failed to register push mirror service")
+			return err
+		}
+
+		if err := system.services.Housekeeping.Register(gCtx); err != nil {
+			log.Error().Err(err).Msg("failed to register repo housekeeping service")
+			return err
+		}
+
 		if err := system.services.Cleanup.Register(gCtx); err != nil {
 			log.Error().Err(err).Msg("failed to register cleanup service")
 			return err
@@ -0,0 +1,136 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/easysoft/gitfox/cli/provide"
+	"github.com/easysoft/gitfox/job"
+	"github.com/easysoft/gitfox/types"
+
+	"github.com/quicklyon/kingpin/v2"
+)
+
+const pollInterval = 5 * time.Second
+
+type migrateCommand struct {
+	source string
+	target string
+	wait   bool
+}
+
+func (c *migrateCommand) run(*kingpin.ParseContext) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	_, err := provide.Client().ArtifactBlobMigrate(ctx, &types.ArtifactBlobMigrationInput{
+		Source: types.StorageProviderType(c.source),
+		Target: types.StorageProviderType(c.target),
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("artifact blob migration from %s to %s started\n", c.source, c.target)
+
+	if !c.wait {
+		return nil
+	}
+	for {
+		time.Sleep(pollInterval)
+
+		progress, err := getProgress()
+		if err != nil {
+			return err
+		}
+		printProgress(progress)
+		if progress.State.IsCompleted() {
+			if progress.State != job.JobStateFinished {
+				return fmt.Errorf("artifact blob migration %s: %s", progress.State, progress.Failure)
+			}
+			return nil
+		}
+	}
+}
+
+type statusCommand struct {
+	json bool
+}
+
+func (c *statusCommand) run(*kingpin.ParseContext) error {
+	progress, err := getProgress()
+	if err != nil {
+		return err
+	}
+	if c.json {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(progress)
+	}
+	printProgress(progress)
+	return nil
+}
+
+func getProgress() (*job.Progress, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	return provide.Client().ArtifactBlobMigration(ctx)
+}
+
+func printProgress(progress *job.Progress) {
+	report := &types.ArtifactBlobMigrationReport{}
+	if progress.Result == "" || json.Unmarshal([]byte(progress.Result), report) != nil {
+		fmt.Printf("%s %d%%\n", progress.State, progress.Progress)
+		return
+	}
+
+	fmt.Printf("%s %d%%: %d/%d files, copied %d (%d bytes), skipped %d, failed %d\n",
+		progress.State, progress.Progress, report.Copied+report.Skipped+report.FailedCount, report.TotalFiles,
+		report.Copied, report.CopiedSize, report.Skipped, report.FailedCount)
+	for _, p := range report.Failed {
+		fmt.Printf("  failed: %s\n", p)
+	}
+	if more := report.FailedCount - int64(len(report.Failed)); more > 0 {
+		fmt.Printf("  and %d more failed\n", more)
+	}
+}
+
+// helper function registers the migrate command.
+func registerMigrate(app *kingpin.CmdClause) {
+	c := &migrateCommand{}
+
+	cmd := app.Command("migrate", "copy the artifact blobs to another storage, "+
+		"run it again before switching the storage provider to copy the blobs written meanwhile").
+		Action(c.run)
+
+	cmd.Flag("source", "source storage provider").
+		Default(string(types.StorageProviderLocal)).
+		EnumVar(&c.source, string(types.StorageProviderLocal), string(types.StorageProviderS3))
+
+	cmd.Flag("target", "target storage provider").
+		Default(string(types.StorageProviderS3)).
+		EnumVar(&c.target, string(types.StorageProviderLocal), string(types.StorageProviderS3))
+
+	cmd.Flag("wait", "wait for the migration to complete").
+		BoolVar(&c.wait)
+}
+
+// helper function registers the status command.
+func registerStatus(app *kingpin.CmdClause) {
+	c := &statusCommand{}
+
+	cmd := app.Command("status", "display the progress of the artifact blob migration").
+		Action(c.run)
+
+	cmd.Flag("json", "json encode the output").
+		BoolVar(&c.json)
+}
@@ -25,6 +25,7 @@ import (
 	"net/url"
 
 	"github.com/easysoft/gitfox/app/api/controller/user"
+	"github.com/easysoft/gitfox/job"
 	"github.com/easysoft/gitfox/pkg/util/common"
 	"github.com/easysoft/gitfox/types"
 
@@ -143,6 +144,23 @@ func (c *HTTPClient) UserDelete(ctx context.Context, key string) error {
 	return err
 }
 
+// ArtifactBlobMigrate starts the migration of the artifact blobs between the storages.
+func (c *HTTPClient) ArtifactBlobMigrate(ctx context.Context, in *types.ArtifactBlobMigrationInput,
+) (*types.JobUIDResponse, error) {
+	out := new(types.JobUIDResponse)
+	uri := fmt.Sprintf("%s/api/v1/artifacts/blobs/migration", c.base)
+	err := c.post(ctx, uri, false, in, out)
+	return out, err
+}
+
+// ArtifactBlobMigration returns the progress of the artifact blob migration.
+func (c *HTTPClient) ArtifactBlobMigration(ctx context.Context) (*job.Progress, error) {
+	out := new(job.Progress)
+	uri := fmt.Sprintf("%s/api/v1/artifacts/blobs/migration", c.base)
+	err := c.get(ctx, uri, out)
+	return out, err
+}
+
 //
 // http request helper functions
 //
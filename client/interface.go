@@ -18,6 +18,7 @@ import (
 	"context"
 
 	"github.com/easysoft/gitfox/app/api/controller/user"
+	"github.com/easysoft/gitfox/job"
 	"github.com/easysoft/gitfox/types"
 )
 
@@ -49,6 +50,12 @@ type Client interface {
 
 	// UserCreatePAT creates a new PAT for the user.
 	UserCreatePAT(ctx context.Context, in user.CreateTokenInput) (*types.TokenResponse, error)
+
+	// ArtifactBlobMigrate starts the migration of the artifact blobs between the storages.
+	ArtifactBlobMigrate(ctx context.Context, in *types.ArtifactBlobMigrationInput) (*types.JobUIDResponse, error)
+
+	// ArtifactBlobMigration returns the progress of the artifact blob migration.
+	ArtifactBlobMigration(ctx context.Context) (*job.Progress, error)
 }
 
 // remoteError store the error payload returned
@@ -21,6 +21,7 @@ import (
 	"github.com/easysoft/gitfox/cli/operations/hooks"
 	"github.com/easysoft/gitfox/cli/operations/migrate"
 	"github.com/easysoft/gitfox/cli/operations/server"
+	"github.com/easysoft/gitfox/cli/operations/storage"
 	"github.com/easysoft/gitfox/cli/operations/swagger"
 	"github.com/easysoft/gitfox/cli/operations/user"
 	"github.com/easysoft/gitfox/cli/operations/users"
@@ -45,6 +46,8 @@ func main() {
 	user.Register(app)
 	users.Register(app)
 
+	storage.Register(app)
+
 	account.RegisterLogin(app)
 	account.RegisterRegister(app)
 	account.RegisterLogout(app)
@@ -29,6 +29,7 @@ import (
 	gitspaceCtrl "github.com/easysoft/gitfox/app/api/controller/gitspace"
 	infraproviderCtrl "github.com/easysoft/gitfox/app/api/controller/infraprovider"
 	controllerkeywordsearch "github.com/easysoft/gitfox/app/api/controller/keywordsearch"
+	controllerlfs "github.com/easysoft/gitfox/app/api/controller/lfs"
 	"github.com/easysoft/gitfox/app/api/controller/limiter"
 	controllerlogs "github.com/easysoft/gitfox/app/api/controller/logs"
 	"github.com/easysoft/gitfox/app/api/controller/migrate"
@@ -87,6 +88,8 @@ import (
 	"github.com/easysoft/gitfox/app/services"
 	aiagentservice "github.com/easysoft/gitfox/app/services/aiagent"
 	"github.com/easysoft/gitfox/app/services/artifactgc"
+	"github.com/easysoft/gitfox/app/services/artifactstats"
+	"github.com/easysoft/gitfox/app/services/blobmigrate"
 	capabilitiesservice "github.com/easysoft/gitfox/app/services/capabilities"
 	"github.com/easysoft/gitfox/app/services/cleanup"
 	"github.com/easysoft/gitfox/app/services/codecomments"
@@ -94,20 +97,24 @@ import (
 	"github.com/easysoft/gitfox/app/services/exporter"
 	"github.com/easysoft/gitfox/app/services/gitspaceevent"
 	"github.com/easysoft/gitfox/app/services/gitspaceservice"
+	"github.com/easysoft/gitfox/app/services/housekeeping"
 	"github.com/easysoft/gitfox/app/services/importer"
 	"github.com/easysoft/gitfox/app/services/instrument"
 	"github.com/easysoft/gitfox/app/services/keywordsearch"
 	svclabel "github.com/easysoft/gitfox/app/services/label"
+	"github.com/easysoft/gitfox/app/services/lfs"
 	locker "github.com/easysoft/gitfox/app/services/locker"
 	messagingservice "github.com/easysoft/gitfox/app/services/messaging"
 	"github.com/easysoft/gitfox/app/services/metric"
 	migrateservice "github.com/easysoft/gitfox/app/services/migrate"
+	"github.com/easysoft/gitfox/app/services/mirror"
 	"github.com/easysoft/gitfox/app/services/notification"
 	"github.com/easysoft/gitfox/app/services/notification/mailer"
 	"github.com/easysoft/gitfox/app/services/protection"
 	"github.com/easysoft/gitfox/app/services/publicaccess"
 	"github.com/easysoft/gitfox/app/services/publickey"
 	pullreqservice "github.com/easysoft/gitfox/app/services/pullreq"
+	"github.com/easysoft/gitfox/app/services/pushmirror"
 	reposervice "github.com/easysoft/gitfox/app/services/repo"
 	"github.com/easysoft/gitfox/app/services/settings"
 	systemsvc "github.com/easysoft/gitfox/app/services/system"
@@ -145,6 +152,8 @@ import (
 func initSystem(ctx context.Context, config *types.Config) (*cliserver.System, error) {
 	wire.Build(
 		artifactgc.WireSet,
+		artifactstats.WireSet,
+		blobmigrate.WireSet,
 		cliserver.NewSystem,
 		cliserver.ProvideRedis,
 		bootstrap.WireSet,
@@ -176,6 +185,8 @@ func initSystem(ctx context.Context, config *types.Config) (*cliserver.System, e
 		serviceaccount.WireSet,
 		user.WireSet,
 		upload.WireSet,
+		controllerlfs.WireSet,
+		lfs.WireSet,
 		service.WireSet,
 		principal.WireSet,
 		usergroupservice.WireSet,
@@ -244,6 +255,9 @@ func initSystem(ctx context.Context, config *types.Config) (*cliserver.System, e
 		canceler.WireSet,
 		exporter.WireSet,
 		metric.WireSet,
+		mirror.WireSet,
+		pushmirror.WireSet,
+		housekeeping.WireSet,
 		reposervice.WireSet,
 		cliserver.ProvideCodeOwnerConfig,
 		codeowners.WireSet,
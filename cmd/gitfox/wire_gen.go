@@ -18,6 +18,7 @@ import (
 	gitspace2 "github.com/easysoft/gitfox/app/api/controller/gitspace"
 	infraprovider3 "github.com/easysoft/gitfox/app/api/controller/infraprovider"
 	keywordsearch2 "github.com/easysoft/gitfox/app/api/controller/keywordsearch"
+	lfs2 "github.com/easysoft/gitfox/app/api/controller/lfs"
 	"github.com/easysoft/gitfox/app/api/controller/limiter"
 	logs2 "github.com/easysoft/gitfox/app/api/controller/logs"
 	migrate2 "github.com/easysoft/gitfox/app/api/controller/migrate"
@@ -76,6 +77,8 @@ import (
 	"github.com/easysoft/gitfox/app/services"
 	"github.com/easysoft/gitfox/app/services/aiagent"
 	"github.com/easysoft/gitfox/app/services/artifactgc"
+	"github.com/easysoft/gitfox/app/services/artifactstats"
+	"github.com/easysoft/gitfox/app/services/blobmigrate"
 	"github.com/easysoft/gitfox/app/services/capabilities"
 	"github.com/easysoft/gitfox/app/services/cleanup"
 	"github.com/easysoft/gitfox/app/services/codecomments"
@@ -84,21 +87,25 @@ import (
 	"github.com/easysoft/gitfox/app/services/gitspace"
 	"github.com/easysoft/gitfox/app/services/gitspaceevent"
 	"github.com/easysoft/gitfox/app/services/gitspaceinfraevent"
+	"github.com/easysoft/gitfox/app/services/housekeeping"
 	"github.com/easysoft/gitfox/app/services/importer"
 	infraprovider2 "github.com/easysoft/gitfox/app/services/infraprovider"
 	"github.com/easysoft/gitfox/app/services/instrument"
 	"github.com/easysoft/gitfox/app/services/keywordsearch"
 	"github.com/easysoft/gitfox/app/services/label"
+	"github.com/easysoft/gitfox/app/services/lfs"
 	"github.com/easysoft/gitfox/app/services/locker"
 	"github.com/easysoft/gitfox/app/services/messaging"
 	"github.com/easysoft/gitfox/app/services/metric"
 	"github.com/easysoft/gitfox/app/services/migrate"
+	"github.com/easysoft/gitfox/app/services/mirror"
 	"github.com/easysoft/gitfox/app/services/notification"
 	"github.com/easysoft/gitfox/app/services/notification/mailer"
 	"github.com/easysoft/gitfox/app/services/protection"
 	"github.com/easysoft/gitfox/app/services/publicaccess"
 	"github.com/easysoft/gitfox/app/services/publickey"
 	"github.com/easysoft/gitfox/app/services/pullreq"
+	"github.com/easysoft/gitfox/app/services/pushmirror"
 	repo2 "github.com/easysoft/gitfox/app/services/repo"
 	"github.com/easysoft/gitfox/app/services/settings"
 	system2 "github.com/easysoft/gitfox/app/services/system"
@@ -167,7 +174,8 @@ func initSystem(ctx context.Context, config *types.Config) (*server.System, erro
 	principalStore := database.ProvidePrincipalStore(gormDB, principalUIDTransformation)
 	tokenStore := database.ProvideTokenStore(gormDB)
 	publicKeyStore := database.ProvidePublicKeyStore(gormDB)
-	userController := user.ProvideController(transactor, principalUID, authorizer, principalStore, tokenStore, membershipStore, publicKeyStore)
+	gitSignatureResultStore := database.ProvideGitSignatureResultStore(gormDB)
+	userController := user.ProvideController(transactor, principalUID, authorizer, principalStore, tokenStore, membershipStore, publicKeyStore, gitSignatureResultStore)
 	serviceController := service.NewController(principalUID, authorizer, principalStore)
 	bootstrapBootstrap := bootstrap.ProvideBootstrap(config, userController, serviceController)
 	authenticator := authn.ProvideAuthenticators(config, principalStore, tokenStore)
@@ -201,15 +209,17 @@ func initSystem(ctx context.Context, config *types.Config) (*server.System, erro
 		return nil, err
 	}
 	storageStore := storage.ProvideLocalStore()
-	gitInterface, err := git.ProvideService(typesConfig, apiGit, clientFactory, storageStore)
+	encrypter, err := encrypt.ProvideEncrypter(config)
 	if err != nil {
 		return nil, err
 	}
-	triggerStore := database.ProvideTriggerStore(gormDB)
-	encrypter, err := encrypt.ProvideEncrypter(config)
+	serverSigner := publickey.ProvideServerSigner(settingsService, encrypter)
+	signer := publickey.ProvideGitSigner(serverSigner)
+	gitInterface, err := git.ProvideService(typesConfig, apiGit, clientFactory, storageStore, signer)
 	if err != nil {
 		return nil, err
 	}
+	triggerStore := database.ProvideTriggerStore(gormDB)
 	jobStore := database.ProvideJobStore(gormDB)
 	pubsubConfig := server.ProvidePubsubConfig(config)
 	pubSub := pubsub.ProvidePubSub(pubsubConfig, universalClient)
@@ -225,7 +235,17 @@ func initSystem(ctx context.Context, config *types.Config) (*server.System, erro
 	localIndexSearcher := keywordsearch.ProvideLocalIndexSearcher()
 	indexer := keywordsearch.ProvideIndexer(localIndexSearcher)
 	auditService := audit.ProvideAuditService()
-	repository, err := importer.ProvideRepoImporter(config, provider, gitInterface, transactor, repoStore, pipelineStore, triggerStore, encrypter, jobScheduler, executor, streamer, indexer, publicaccessService, auditService)
+	blobConfig, err := server.ProvideBlobStoreConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	blobStore, err := blob.ProvideStore(ctx, blobConfig)
+	if err != nil {
+		return nil, err
+	}
+	lfsObjectStore := database.ProvideLFSObjectStore(gormDB)
+	lfsService := lfs.ProvideService(gitInterface, lfsObjectStore, blobStore)
+	repository, err := importer.ProvideRepoImporter(config, provider, gitInterface, transactor, repoStore, pipelineStore, triggerStore, encrypter, jobScheduler, executor, streamer, indexer, publicaccessService, auditService, lfsService)
 	if err != nil {
 		return nil, err
 	}
@@ -255,7 +275,30 @@ func initSystem(ctx context.Context, config *types.Config) (*server.System, erro
 	instrumentService := instrument.ProvideService()
 	userGroupStore := database.ProvideUserGroupStore(gormDB)
 	searchService := usergroup.ProvideSearchService()
-	repoController := repo.ProvideController(config, transactor, provider, authorizer, repoStore, spaceStore, membershipStore, pipelineStore, principalStore, executionStore, ruleStore, checkStore, pullReqStore, settingsService, principalInfoCache, protectionManager, gitInterface, repository, codeownersService, reporter, indexer, resourceLimiter, lockerLocker, auditService, mutexManager, repoIdentifier, repoCheck, publicaccessService, labelService, instrumentService, userGroupStore, searchService)
+	reporter4, err := events6.ProvideReporter(eventsSystem)
+	if err != nil {
+		return nil, err
+	}
+	mirrorService, err := mirror.ProvideService(config, gitInterface, repoStore, reporter4, lfsService, lockerLocker, jobScheduler, executor)
+	if err != nil {
+		return nil, err
+	}
+	pushMirrorStore := database.ProvidePushMirrorStore(gormDB)
+	secretStore := database.ProvideSecretStore(gormDB)
+	connectorStore := database.ProvideConnectorStore(gormDB, secretStore)
+	readerFactory, err := events6.ProvideReaderFactory(eventsSystem)
+	if err != nil {
+		return nil, err
+	}
+	pushmirrorService, err := pushmirror.ProvideService(ctx, config, gitInterface, repoStore, pushMirrorStore, secretStore, connectorStore, spaceStore, principalStore, authorizer, encrypter, readerFactory, jobScheduler, executor)
+	if err != nil {
+		return nil, err
+	}
+	housekeepingService, err := housekeeping.ProvideService(config, gitInterface, repoStore, lockerLocker, jobScheduler, executor)
+	if err != nil {
+		return nil, err
+	}
+	signatureVerifier := publickey.ProvideSignatureVerifier(gitInterface, publicKeyStore, gitSignatureResultStore, principalInfoCache, serverSigner)
 	aiStore := database.ProvideAIStore(gormDB)
 	reposettingsController := reposettings.ProvideController(authorizer, repoStore, aiStore, settingsService, auditService, reporter)
 	stageStore := database.ProvideStageStore(gormDB)
@@ -281,8 +324,6 @@ func initSystem(ctx context.Context, config *types.Config) (*server.System, erro
 	logStream := livelog.ProvideLogStream()
 	logsController := logs2.ProvideController(authorizer, executionStore, repoStore, pipelineStore, stageStore, stepStore, logStore, logStream)
 	spaceIdentifier := check.ProvideSpaceIdentifierCheck()
-	secretStore := database.ProvideSecretStore(gormDB)
-	connectorStore := database.ProvideConnectorStore(gormDB, secretStore)
 	artifactStore := database.ProvideArtifactStore(gormDB)
 	repoGitInfoView := database.ProvideRepoGitInfoView(gormDB)
 	repoGitInfoCache := cache.ProvideRepoGitInfoCache(repoGitInfoView)
@@ -311,7 +352,6 @@ func initSystem(ctx context.Context, config *types.Config) (*server.System, erro
 	factory := infraprovider.ProvideFactory(dockerProvider)
 	infraproviderService := infraprovider2.ProvideInfraProvider(transactor, infraProviderResourceStore, infraProviderConfigStore, infraProviderTemplateStore, factory, spaceStore)
 	gitspaceService := gitspace.ProvideGitspace(transactor, gitspaceConfigStore, gitspaceInstanceStore, spaceStore, infraproviderService)
-	spaceController := space.ProvideController(config, transactor, provider, streamer, spaceIdentifier, authorizer, spacePathStore, pipelineStore, secretStore, connectorStore, templateStore, spaceStore, repoStore, principalStore, artifactStore, repoController, membershipStore, listService, repository, exporterRepository, resourceLimiter, publicaccessService, auditService, gitspaceService, labelService, instrumentService, aiStore, executionStore)
 	reporter2, err := events4.ProvideReporter(eventsSystem)
 	if err != nil {
 		return nil, err
@@ -335,10 +375,6 @@ func initSystem(ctx context.Context, config *types.Config) (*server.System, erro
 		return nil, err
 	}
 	migrator := codecomments.ProvideMigrator(gitInterface)
-	readerFactory, err := events6.ProvideReaderFactory(eventsSystem)
-	if err != nil {
-		return nil, err
-	}
 	eventsReaderFactory, err := events5.ProvideReaderFactory(eventsSystem)
 	if err != nil {
 		return nil, err
@@ -348,7 +384,9 @@ func initSystem(ctx context.Context, config *types.Config) (*server.System, erro
 		return nil, err
 	}
 	pullReq := migrate.ProvidePullReqImporter(provider, gitInterface, principalStore, spaceStore, repoStore, pullReqStore, pullReqActivityStore, labelStore, labelValueStore, pullReqLabelAssignmentStore, transactor, mutexManager)
-	pullreqController := pullreq2.ProvideController(transactor, provider, authorizer, auditService, pullReqStore, pullReqActivityStore, codeCommentView, pullReqReviewStore, pullReqReviewerStore, repoStore, principalStore, userGroupStore, userGroupReviewersStore, principalInfoCache, pullReqFileViewStore, membershipStore, checkStore, aiStore, gitInterface, reporter3, migrator, pullreqService, listService, protectionManager, streamer, codeownersService, lockerLocker, pullReq, labelService, instrumentService, searchService)
+	pullreqController := pullreq2.ProvideController(transactor, provider, authorizer, auditService, pullReqStore, pullReqActivityStore, codeCommentView, pullReqReviewStore, pullReqReviewerStore, repoStore, principalStore, userGroupStore, userGroupReviewersStore, principalInfoCache, pullReqFileViewStore, membershipStore, checkStore, aiStore, gitInterface, reporter3, migrator, pullreqService, listService, protectionManager, streamer, codeownersService, lockerLocker, pullReq, labelService, instrumentService, searchService, signatureVerifier, serverSigner)
+	repoController := repo.ProvideController(config, transactor, provider, authorizer, repoStore, spaceStore, membershipStore, pipelineStore, principalStore, executionStore, ruleStore, checkStore, pullReqStore, settingsService, principalInfoCache, protectionManager, gitInterface, repository, codeownersService, reporter, indexer, resourceLimiter, lockerLocker, auditService, mutexManager, repoIdentifier, repoCheck, publicaccessService, labelService, instrumentService, userGroupStore, searchService, mirrorService, pushMirrorStore, pushmirrorService, housekeepingService, signatureVerifier, pullreqController)
+	spaceController := space.ProvideController(config, transactor, provider, streamer, spaceIdentifier, authorizer, spacePathStore, pipelineStore, secretStore, connectorStore, templateStore, spaceStore, repoStore, principalStore, artifactStore, repoController, membershipStore, listService, repository, exporterRepository, resourceLimiter, publicaccessService, auditService, gitspaceService, labelService, instrumentService, aiStore, executionStore)
 	webhookConfig := server.ProvideWebhookConfig(config)
 	webhookStore := database.ProvideWebhookStore(gormDB)
 	webhookExecutionStore := database.ProvideWebhookExecutionStore(gormDB)
@@ -359,10 +397,6 @@ func initSystem(ctx context.Context, config *types.Config) (*server.System, erro
 	}
 	preprocessor := webhook2.ProvidePreprocessor()
 	webhookController := webhook2.ProvideController(authorizer, spaceStore, repoStore, webhookService, encrypter, preprocessor)
-	reporter4, err := events6.ProvideReporter(eventsSystem)
-	if err != nil {
-		return nil, err
-	}
 	preReceiveExtender, err := githook.ProvidePreReceiveExtender()
 	if err != nil {
 		return nil, err
@@ -375,22 +409,16 @@ func initSystem(ctx context.Context, config *types.Config) (*server.System, erro
 	if err != nil {
 		return nil, err
 	}
-	githookController := githook.ProvideController(authorizer, principalStore, repoStore, reporter4, reporter, reporter3, gitInterface, pullReqStore, provider, protectionManager, clientFactory, resourceLimiter, settingsService, preReceiveExtender, updateExtender, postReceiveExtender, codeownersService, pullReqReviewerStore)
+	githookController := githook.ProvideController(authorizer, principalStore, repoStore, reporter4, reporter, reporter3, gitInterface, pullReqStore, provider, protectionManager, clientFactory, resourceLimiter, settingsService, preReceiveExtender, updateExtender, postReceiveExtender, codeownersService, pullReqReviewerStore, signatureVerifier)
 	serviceaccountController := serviceaccount.NewController(principalUID, authorizer, principalStore, spaceStore, repoStore, tokenStore)
 	principalController := principal.ProvideController(principalStore, authorizer)
 	usergroupController := usergroup2.ProvideController(userGroupStore, spaceStore, authorizer, searchService)
 	v := check2.ProvideCheckSanitizers()
 	checkController := check2.ProvideController(transactor, authorizer, repoStore, checkStore, gitInterface, v)
-	systemController := system.NewController(principalStore, config)
-	blobConfig, err := server.ProvideBlobStoreConfig(config)
-	if err != nil {
-		return nil, err
-	}
-	blobStore, err := blob.ProvideStore(ctx, blobConfig)
-	if err != nil {
-		return nil, err
-	}
+	systemController := system.NewController(principalStore, config, serverSigner)
 	uploadController := upload.ProvideController(authorizer, repoStore, blobStore)
+	lfsLockStore := database.ProvideLFSLockStore(gormDB)
+	lfsController := lfs2.ProvideController(authorizer, repoStore, principalStore, principalInfoCache, lfsObjectStore, lfsLockStore, lfsService, provider)
 	searcher := keywordsearch.ProvideSearcher(localIndexSearcher)
 	keywordsearchController := keywordsearch2.ProvideController(authorizer, searcher, repoController, spaceController)
 	artifactConfig := server.ProvideArtifactConfig(config)
@@ -402,7 +430,12 @@ func initSystem(ctx context.Context, config *types.Config) (*server.System, erro
 	if err != nil {
 		return nil, err
 	}
-	controllerController := controller.ProvideArtifactController(transactor, provider, authorizer, artifactStore, spaceStore, contentStorage, settingsService, artifactgcService)
+	blobmigrateService, err := blobmigrate.ProvideBlobMigrateSvc(artifactConfig, storageConfig, jobScheduler, executor)
+	if err != nil {
+		return nil, err
+	}
+	recorder := artifactstats.ProvideRecorder(ctx, artifactStore)
+	controllerController := controller.ProvideArtifactController(transactor, provider, authorizer, artifactStore, spaceStore, contentStorage, settingsService, artifactgcService, blobmigrateService, secretStore, connectorStore, encrypter, repoStore, gitInterface, membershipStore, recorder, config)
 	executionManager := manager.ProvideExecutionManager(config, executionStore, pipelineStore, provider, streamer, fileService, converterService, logStore, logStream, checkStore, repoStore, schedulerScheduler, secretStore, stageStore, stepStore, principalStore, publicaccessService, reporter2)
 	runnerController := runner.ProvideController(transactor, authorizer, executionManager, provider)
 	infraproviderController := infraprovider3.ProvideController(authorizer, spaceStore, infraproviderService)
@@ -450,10 +483,10 @@ func initSystem(ctx context.Context, config *types.Config) (*server.System, erro
 	}
 	aiagentController := aiagent2.ProvideController(authorizer, intelligence, repoStore, pipelineStore, executionStore, gitInterface, provider, slack)
 	openapiService := openapi.ProvideOpenAPIService()
-	routerRouter := router.ProvideRouter(ctx, config, principalStore, authenticator, repoController, reposettingsController, executionController, logsController, spaceController, pipelineController, secretController, triggerController, connectorController, templateController, pluginController, pullreqController, webhookController, githookController, gitInterface, serviceaccountController, userController, principalController, usergroupController, checkController, systemController, uploadController, keywordsearchController, controllerController, runnerController, infraproviderController, gitspaceController, migrateController, aiagentController, capabilitiesController, provider, openapiService, artifactStore, repoStore, contentStorage)
+	routerRouter := router.ProvideRouter(ctx, config, principalStore, authenticator, repoController, reposettingsController, executionController, logsController, spaceController, pipelineController, secretController, triggerController, connectorController, templateController, pluginController, pullreqController, webhookController, githookController, gitInterface, serviceaccountController, userController, principalController, usergroupController, checkController, systemController, uploadController, keywordsearchController, controllerController, runnerController, infraproviderController, gitspaceController, migrateController, aiagentController, capabilitiesController, lfsController, provider, openapiService, artifactStore, repoStore, contentStorage)
 	serverServer := server2.ProvideServer(config, routerRouter)
 	publickeyService := publickey.ProvidePublicKey(publicKeyStore, principalInfoCache)
-	sshServer := ssh.ProvideServer(config, publickeyService, repoController)
+	sshServer := ssh.ProvideServer(config, publickeyService, repoController, lfsController)
 	client := manager.ProvideExecutionClient(executionManager, provider, config)
 	resolverManager := resolver.ProvideResolver(config, pluginStore, templateStore, executionStore, repoStore)
 	stageDynamicEnvProvider := extend.ProvideDynamicStageProvider(pipelineStorage, stageStore)
@@ -527,7 +560,7 @@ func initSystem(ctx context.Context, config *types.Config) (*server.System, erro
 	if err != nil {
 		return nil, err
 	}
-	servicesServices := services.ProvideServices(webhookService, pullreqService, triggerService, jobScheduler, collector, sizeCalculator, repoService, cleanupService, notificationService, keywordsearchService, gitspaceServices, instrumentService, consumer, repositoryCount)
+	servicesServices := services.ProvideServices(webhookService, pullreqService, triggerService, jobScheduler, collector, sizeCalculator, repoService, cleanupService, notificationService, keywordsearchService, gitspaceServices, instrumentService, consumer, repositoryCount, mirrorService, pushmirrorService, housekeepingService)
 	serverSystem := server.NewSystem(bootstrapBootstrap, serverServer, sshServer, poller, resolverManager, servicesServices)
 	return serverSystem, nil
 }
@@ -15,7 +15,9 @@
 package api
 
 import (
+	"bytes"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -81,6 +83,71 @@ func TestParseTagDataFromCatFile(t *testing.T) {
 		"some message")
 }
 
+func TestParseTagDataFromCatFileSigned(t *testing.T) {
+	payload := "object " + sha.EmptyTree.String() + "\ntype commit\ntag v1.0\n" +
+		"tagger max <max@mail.com> 1666401234 -0700\n\nrelease\n"
+	for _, signature := range []string{
+		"-----BEGIN PGP SIGNATURE-----\n\nw...B\n-----END PGP SIGNATURE-----\n",
+		"-----BEGIN SSH SIGNATURE-----\nU1NIU0lH\n-----END SSH SIGNATURE-----\n",
+	} {
+		res, err := parseTagDataFromCatFile([]byte(payload + signature))
+		require.NoError(t, err)
+		require.Equal(t, "release", res.Message)
+		require.NotNil(t, res.Signature)
+		require.Equal(t, signature, res.Signature.Signature)
+		require.Equal(t, payload, res.Signature.Payload)
+	}
+}
+
+func TestCommitFromReaderSigned(t *testing.T) {
+	data := "tree " + sha.EmptyTree.String() + "\n" +
+		"author max <max@mail.com> 1666401234 -0700\n" +
+		"committer max <max@mail.com> 1666401234 -0700\n" +
+		"encoding ISO-8859-1\n" +
+		"gpgsig -----BEGIN SSH SIGNATURE-----\n U1NIU0lH\n -----END SSH SIGNATURE-----\n" +
+		"\ncommit message\n"
+
+	commit, err := CommitFromReader(sha.EmptyTree, strings.NewReader(data))
+	require.NoError(t, err)
+	require.NotNil(t, commit.Signature)
+	require.Equal(t, "-----BEGIN SSH SIGNATURE-----\nU1NIU0lH\n-----END SSH SIGNATURE-----\n",
+		commit.Signature.Signature)
+	require.Equal(t, "tree "+sha.EmptyTree.String()+"\n"+
+		"author max <max@mail.com> 1666401234 -0700\n"+
+		"committer max <max@mail.com> 1666401234 -0700\n"+
+		"encoding ISO-8859-1\n"+
+		"\ncommit message\n", commit.Signature.Payload)
+}
+
+func TestAddCommitSignature(t *testing.T) {
+	payload := "tree " + sha.EmptyTree.String() + "\n" +
+		"author max <max@mail.com> 1666401234 -0700\n" +
+		"committer max <max@mail.com> 1666401234 -0700\n" +
+		"\ncommit message\n\nbody\n"
+	signature := "-----BEGIN SSH SIGNATURE-----\nU1NIU0lH\n-----END SSH SIGNATURE-----\n"
+
+	signed := AddCommitSignature([]byte(payload), []byte(signature))
+
+	commit, err := CommitFromReader(sha.EmptyTree, bytes.NewReader(signed))
+	require.NoError(t, err)
+	require.NotNil(t, commit.Signature)
+	require.Equal(t, signature, commit.Signature.Signature)
+	require.Equal(t, payload, commit.Signature.Payload)
+	require.Equal(t, "commit message\n\nbody\n", commit.Message)
+}
+
+func TestAddTagSignature(t *testing.T) {
+	payload := "object " + sha.EmptyTree.String() + "\ntype commit\ntag v1\n" +
+		"tagger max <max@mail.com> 1666401234 -0700\n\nmessage\n"
+	signature := "-----BEGIN PGP SIGNATURE-----\n\nU1NIU0lH\n-----END PGP SIGNATURE-----\n"
+
+	tag, err := parseTagDataFromCatFile(AddTagSignature([]byte(payload), []byte(signature)))
+	require.NoError(t, err)
+	require.NotNil(t, tag.Signature)
+	require.Equal(t, payload, tag.Signature.Payload)
+	require.Equal(t, "message", tag.Message)
+}
+
 func testParseTagDataFromCatFileFor(t *testing.T, object string, typ GitObjectType, name string,
 	tagger Signature, remainder string, expectedMessage string) {
 	data := fmt.Sprintf(
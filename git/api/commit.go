@@ -223,6 +223,75 @@ func (g *Git) ListCommitSHAs(
 	return g.listCommitSHAs(ctx, repoPath, alternateObjectDirs, ref, page, limit, filter)
 }
 
+// ListNewCommitSHAs lists the commits reachable from sha which aren't reachable from oldSHA, e.g. the commits
+// a push adds to a branch. For a new branch oldSHA is empty and the commits reachable from the merge bases
+// of sha and baseRef are excluded, with neither of them all commits reachable from sha are listed.
+func (g *Git) ListNewCommitSHAs(
+	ctx context.Context,
+	repoPath string,
+	alternateObjectDirs []string,
+	sha string,
+	oldSHA string,
+	baseRef string,
+) ([]string, error) {
+	if repoPath == "" {
+		return nil, ErrRepositoryPathEmpty
+	}
+
+	var exclude []string
+	switch {
+	case oldSHA != "":
+		exclude = []string{oldSHA}
+	case baseRef != "":
+		mergeBases, err := listMergeBases(ctx, repoPath, alternateObjectDirs, baseRef, sha)
+		if err != nil {
+			return nil, err
+		}
+		exclude = mergeBases
+	}
+
+	args := []string{sha}
+	if len(exclude) > 0 {
+		args = append(args, "--not")
+		args = append(args, exclude...)
+	}
+	cmd := command.New("rev-list",
+		command.WithArg(args...),
+		command.WithAlternateObjectDirs(alternateObjectDirs...),
+	)
+	output := &bytes.Buffer{}
+	if err := cmd.Run(ctx, command.WithDir(repoPath), command.WithStdout(output)); err != nil {
+		return nil, processGitErrorf(err, "failed to list new commits of %s", sha)
+	}
+
+	return parseLinesToSlice(output.Bytes()), nil
+}
+
+// listMergeBases returns all merge bases of the two revisions, none is returned for unrelated histories.
+func listMergeBases(
+	ctx context.Context,
+	repoPath string,
+	alternateObjectDirs []string,
+	rev1 string,
+	rev2 string,
+) ([]string, error) {
+	cmd := command.New("merge-base",
+		command.WithFlag("--all"),
+		command.WithArg(rev1, rev2),
+		command.WithAlternateObjectDirs(alternateObjectDirs...),
+	)
+	output := &bytes.Buffer{}
+	if err := cmd.Run(ctx, command.WithDir(repoPath), command.WithStdout(output)); err != nil {
+		cmdErr := command.AsError(err)
+		if cmdErr != nil && cmdErr.IsExitCode(1) && len(cmdErr.StdErr) == 0 {
+			return nil, nil
+		}
+		return nil, processGitErrorf(err, "failed to get merge-bases [%s, %s]", rev1, rev2)
+	}
+
+	return parseLinesToSlice(output.Bytes()), nil
+}
+
 // ListCommits lists the commits reachable from ref.
 // Note: ref & afterRef can be Branch / Tag / CommitSHA.
 // Note: commits returned are [ref->...->afterRef).
@@ -941,6 +1010,9 @@ readLoop:
 				_, _ = signatureSB.Write(data)
 				_ = signatureSB.WriteByte('\n')
 				pgpsig = true
+			default:
+				// other headers (e.g. encoding, mergetag) are part of the signed payload
+				_, _ = payloadSB.Write(line)
 			}
 		} else {
 			_, _ = messageSB.Write(line)
@@ -8,6 +8,7 @@ package api
 
 import (
 	"context"
+	"fmt"
 	"strings"
 
 	"github.com/easysoft/gitfox/git/command"
@@ -124,7 +125,7 @@ func (g *Git) MirrorFetch(ctx context.Context, repoPath, remote string, prune bo
 		// If there is still an error (or there always was an error)
 		if err != nil {
 			log.Ctx(ctx).Err(err).Msgf("SyncMirrors [repo: %v Remote: %v]: failed to update mirror repository:\nStdout: %s\nStderr: %s\nErr: %v", repoPath, remoteURL, stdoutMessage, stderrMessage, err)
-			return nil, false
+			return fmt.Errorf("failed to fetch mirror remote: %s", strings.TrimSpace(stderrMessage)), false
 		}
 	}
 	// TODO 更新size等信息
@@ -30,11 +30,7 @@ func (g *Git) GetRemoteAddress(ctx context.Context, repoPath, remoteName string)
 	if err != nil {
 		return "", err
 	}
-	result := strings.TrimSpace(output.String())
-	if len(result) > 0 {
-		result = result[:len(result)-1]
-	}
-	return result, nil
+	return strings.TrimSpace(output.String()), nil
 }
 
 // GetRemoteURL returns the url of a specific remote of the repository.
@@ -51,6 +51,10 @@ type PushOptions struct {
 	Env            []string
 	Timeout        time.Duration
 	Mirror         bool
+	// Prune removes the remote refs matching the refspecs that don't exist locally
+	Prune bool
+	// RefSpecs are pushed instead of the Branch if provided
+	RefSpecs []string
 }
 
 // ObjectCount represents the parsed information from the `git count-objects -v` command.
@@ -362,9 +366,14 @@ func (g *Git) Push(
 	if opts.Mirror {
 		cmd.Add(command.WithFlag("--mirror"))
 	}
+	if opts.Prune {
+		cmd.Add(command.WithFlag("--prune"))
+	}
 	cmd.Add(command.WithPostSepArg(opts.Remote))
 
-	if len(opts.Branch) > 0 {
+	if len(opts.RefSpecs) > 0 {
+		cmd.Add(command.WithPostSepArg(opts.RefSpecs...))
+	} else if len(opts.Branch) > 0 {
 		cmd.Add(command.WithPostSepArg(opts.Branch))
 	}
 
@@ -0,0 +1,95 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/easysoft/gitfox/errors"
+	"github.com/easysoft/gitfox/git/sha"
+)
+
+// SignedObject is a signed commit or annotated tag,
+// Signer is the committer of the commit or the tagger of the tag.
+type SignedObject struct {
+	SHA       sha.SHA
+	Type      GitObjectType
+	Signer    Signature
+	Signature *CommitGPGSignature
+}
+
+// GetSignedObjects reads the signatures of the commits and annotated tags,
+// the unsigned and missing objects are skipped.
+func (g *Git) GetSignedObjects(
+	ctx context.Context,
+	repoPath string,
+	alternateObjectDirs []string,
+	objectSHAs []sha.SHA,
+) ([]SignedObject, error) {
+	if repoPath == "" {
+		return nil, ErrRepositoryPathEmpty
+	}
+
+	writer, reader, cancel := CatFileBatch(ctx, repoPath, alternateObjectDirs)
+	defer func() {
+		cancel()
+		_ = writer.Close()
+	}()
+
+	objects := make([]SignedObject, 0, len(objectSHAs))
+	for _, objectSHA := range objectSHAs {
+		if _, err := writer.Write([]byte(objectSHA.String() + "\n")); err != nil {
+			return nil, fmt.Errorf("failed to write object sha to cat-file: %w", err)
+		}
+		output, err := ReadBatchHeaderLine(reader)
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read cat-file header line: %w", err)
+		}
+
+		data, err := io.ReadAll(io.LimitReader(reader, output.Size))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read object %s: %w", output.SHA, err)
+		}
+		if _, err = reader.Discard(1); err != nil {
+			return nil, fmt.Errorf("cat-file reader Discard failed: %w", err)
+		}
+
+		var object SignedObject
+		switch GitObjectType(output.Type) {
+		case GitObjectTypeCommit:
+			commit, err := CommitFromReader(output.SHA, bytes.NewReader(data))
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse commit %s: %w", output.SHA, err)
+			}
+			object = SignedObject{Signer: commit.Committer, Signature: commit.Signature}
+		case GitObjectTypeTag:
+			tag, err := parseTagDataFromCatFile(data)
+			if err != nil {
+				// the tags without tagger can't be attributed to anyone
+				continue
+			}
+			object = SignedObject{Signer: tag.Tagger, Signature: tag.Signature}
+		default:
+			continue
+		}
+		if object.Signature == nil {
+			continue
+		}
+
+		object.SHA = output.SHA
+		object.Type = GitObjectType(output.Type)
+		objects = append(objects, object)
+	}
+
+	return objects, nil
+}
@@ -0,0 +1,104 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/easysoft/gitfox/git/command"
+	"github.com/easysoft/gitfox/git/sha"
+)
+
+const signatureHeader = "gpgsig"
+
+// Signer signs the commits and tags created by the server. The armored signature is returned,
+// it's nil if the server has no signing key.
+type Signer interface {
+	Sign(ctx context.Context, payload []byte) ([]byte, error)
+}
+
+// AddCommitSignature adds the signature header to the raw commit object, the signature lines
+// are continued by a leading space like git does.
+func AddCommitSignature(commit []byte, signature []byte) []byte {
+	headerEnd := bytes.Index(commit, []byte("\n\n"))
+	if headerEnd < 0 {
+		headerEnd = len(commit) - 1
+	}
+
+	lines := bytes.Split(bytes.TrimRight(signature, "\n"), []byte("\n"))
+
+	signed := bytes.NewBuffer(make([]byte, 0, len(commit)+len(signature)+len(lines)+len(signatureHeader)+1))
+	signed.Write(commit[:headerEnd+1])
+	signed.WriteString(signatureHeader)
+	for _, line := range lines {
+		signed.WriteByte(' ')
+		signed.Write(line)
+		signed.WriteByte('\n')
+	}
+	signed.Write(commit[headerEnd+1:])
+
+	return signed.Bytes()
+}
+
+// AddTagSignature appends the signature to the raw tag object.
+func AddTagSignature(tag []byte, signature []byte) []byte {
+	signed := make([]byte, 0, len(tag)+len(signature)+1)
+	signed = append(signed, tag...)
+	if len(signed) > 0 && signed[len(signed)-1] != '\n' {
+		signed = append(signed, '\n')
+	}
+	return append(signed, signature...)
+}
+
+// SignObject writes a signed copy of the commit or tag object to the repository, the SHA of the signed
+// object is returned. The object is returned as is if the signer has no key.
+func SignObject(
+	ctx context.Context,
+	repoPath string,
+	signer Signer,
+	objectType GitObjectType,
+	objectSHA sha.SHA,
+) (sha.SHA, error) {
+	payload := bytes.NewBuffer(nil)
+	cmd := command.New("cat-file", command.WithArg(string(objectType), objectSHA.String()))
+	if err := cmd.Run(ctx, command.WithDir(repoPath), command.WithStdout(payload)); err != nil {
+		return sha.None, processGitErrorf(err, "failed to read %s object", objectType)
+	}
+
+	signature, err := signer.Sign(ctx, payload.Bytes())
+	if err != nil {
+		return sha.None, fmt.Errorf("failed to sign %s object: %w", objectType, err)
+	}
+	if len(signature) == 0 {
+		return objectSHA, nil
+	}
+
+	var signed []byte
+	if objectType == GitObjectTypeTag {
+		signed = AddTagSignature(payload.Bytes(), signature)
+	} else {
+		signed = AddCommitSignature(payload.Bytes(), signature)
+	}
+
+	cmd = command.New("hash-object",
+		command.WithFlag("-t", string(objectType)),
+		command.WithFlag("-w"),
+		command.WithFlag("--stdin"))
+
+	stdout := bytes.NewBuffer(nil)
+	err = cmd.Run(ctx,
+		command.WithDir(repoPath),
+		command.WithStdin(bytes.NewReader(signed)),
+		command.WithStdout(stdout))
+	if err != nil {
+		return sha.None, processGitErrorf(err, "failed to write signed %s object", objectType)
+	}
+
+	return sha.New(stdout.String())
+}
@@ -31,6 +31,8 @@ import (
 const (
 	pgpSignatureBeginToken = "\n-----BEGIN PGP SIGNATURE-----\n" //#nosec G101
 	pgpSignatureEndToken   = "\n-----END PGP SIGNATURE-----"     //#nosec G101
+	sshSignatureBeginToken = "\n-----BEGIN SSH SIGNATURE-----\n" //#nosec G101
+	sshSignatureEndToken   = "\n-----END SSH SIGNATURE-----"     //#nosec G101
 )
 
 type Tag struct {
@@ -177,6 +179,9 @@ func getAnnotatedTags(
 
 // parseTagDataFromCatFile parses a tag from a cat-file output.
 func parseTagDataFromCatFile(data []byte) (tag Tag, err error) {
+	// the signature of a signed tag is appended to the tag message
+	data, tag.Signature = splitTagSignature(data)
+
 	// parse object Id
 	object, p, err := parseCatFileLine(data, 0, "object")
 	if err != nil {
@@ -354,6 +359,7 @@ func parseTagData(data []byte) (*Tag, error) {
 	tag := &Tag{
 		Tagger: Signature{},
 	}
+	data, tag.Signature = splitTagSignature(data)
 	// we now have the contents of the commit object. Let's investigate...
 	nextLine := 0
 l:
@@ -385,17 +391,6 @@ l:
 			break l
 		}
 	}
-	idx := strings.LastIndex(tag.Message, pgpSignatureBeginToken)
-	if idx > 0 {
-		endSigIdx := strings.Index(tag.Message[idx:], pgpSignatureEndToken)
-		if endSigIdx > 0 {
-			tag.Signature = &CommitGPGSignature{
-				Signature: tag.Message[idx+1 : idx+endSigIdx+len(pgpSignatureEndToken)],
-				Payload:   string(data[:bytes.LastIndex(data, []byte(pgpSignatureBeginToken))+1]),
-			}
-			tag.Message = tag.Message[:idx+1]
-		}
-	}
 	return tag, nil
 }
 
@@ -425,3 +420,24 @@ func (g *Git) GetTagCount(
 
 	return countLines(pipeOut)
 }
+
+// splitTagSignature splits the PGP or SSH signature appended to the tag object from the signed payload,
+// the signature is nil if the tag isn't signed.
+func splitTagSignature(data []byte) ([]byte, *CommitGPGSignature) {
+	tokens := [][2]string{
+		{pgpSignatureBeginToken, pgpSignatureEndToken},
+		{sshSignatureBeginToken, sshSignatureEndToken},
+	}
+	for _, token := range tokens {
+		idx := bytes.LastIndex(data, []byte(token[0]))
+		if idx < 0 || !bytes.Contains(data[idx:], []byte(token[1])) {
+			continue
+		}
+		payload := data[:idx+1]
+		return payload, &CommitGPGSignature{
+			Signature: string(data[idx+1:]),
+			Payload:   string(payload),
+		}
+	}
+	return data, nil
+}
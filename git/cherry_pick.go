@@ -0,0 +1,266 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package git
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/easysoft/gitfox/errors"
+	"github.com/easysoft/gitfox/git/api"
+	"github.com/easysoft/gitfox/git/hook"
+	"github.com/easysoft/gitfox/git/parser"
+	"github.com/easysoft/gitfox/git/sha"
+	"github.com/easysoft/gitfox/git/sharedrepo"
+
+	"github.com/rs/zerolog/log"
+)
+
+// CherryPickParams holds the data for applying the changes of commits to a branch,
+// it's used for both cherry-picking and reverting commits.
+type CherryPickParams struct {
+	WriteParams
+
+	// CommitSHAs are applied in the provided order.
+	// A merge commit is applied relative to its first parent.
+	CommitSHAs []sha.SHA
+
+	// ParentSHA overwrites the first parent of the commit, the changes between the ParentSHA and the commit
+	// are applied then, e.g. all changes of a merged pull request. It requires exactly one commit (optional).
+	ParentSHA sha.SHA
+
+	// Branch is the branch the commits are applied on top of (optional, default: default branch).
+	Branch string
+	// NewBranch is created with the applied commits, rather than updating the Branch (optional).
+	NewBranch string
+
+	// Message overwrites the commit messages, all changes are committed as a single commit then (optional).
+	Message string
+
+	// Committer overwrites the git committer used for the commits
+	// (optional, default: actor)
+	Committer *Identity
+	// CommitterDate overwrites the git committer date used for the commits
+	// (optional, default: current time on server)
+	CommitterDate *time.Time
+	// Author overwrites the git author of the reverts and of the single commit with the Message,
+	// cherry-picked commits keep the author of the original commit (optional, default: committer).
+	Author *Identity
+}
+
+func (p *CherryPickParams) Validate() error {
+	if err := p.WriteParams.Validate(); err != nil {
+		return err
+	}
+
+	if len(p.CommitSHAs) == 0 {
+		return errors.InvalidArgument("at least one commit is required")
+	}
+
+	if !p.ParentSHA.IsEmpty() && len(p.CommitSHAs) != 1 {
+		return errors.InvalidArgument("parent commit can only be provided for a single commit")
+	}
+
+	return nil
+}
+
+type CherryPickOutput struct {
+	// BaseSHA is the sha of the latest commit on the branch the commits were applied on top of.
+	BaseSHA sha.SHA
+	// CommitSHA is the sha of the last commit created.
+	CommitSHA sha.SHA
+	// Branch is the branch that got updated or created.
+	Branch string
+}
+
+// CherryPick applies the changes introduced by the commits on top of the branch,
+// like 'git cherry-pick -x', without the need of a working tree.
+func (s *Service) CherryPick(ctx context.Context, params *CherryPickParams) (CherryPickOutput, error) {
+	return s.applyCommits(ctx, params, false)
+}
+
+// Revert applies the inverse of the changes introduced by the commits on top of the branch,
+// like 'git revert', without the need of a working tree.
+func (s *Service) Revert(ctx context.Context, params *CherryPickParams) (CherryPickOutput, error) {
+	return s.applyCommits(ctx, params, true)
+}
+
+//nolint:gocognit
+func (s *Service) applyCommits(ctx context.Context, params *CherryPickParams, revert bool) (CherryPickOutput, error) {
+	if err := params.Validate(); err != nil {
+		return CherryPickOutput{}, err
+	}
+
+	operation := "cherry-pick"
+	if revert {
+		operation = "revert"
+	}
+
+	committer := params.Actor
+	if params.Committer != nil {
+		committer = *params.Committer
+	}
+	committerDate := time.Now().UTC()
+	if params.CommitterDate != nil {
+		committerDate = *params.CommitterDate
+	}
+	committerSig := &api.Signature{
+		Identity: api.Identity{Name: committer.Name, Email: committer.Email},
+		When:     committerDate,
+	}
+
+	author := committer
+	if params.Author != nil {
+		author = *params.Author
+	}
+	authorSig := &api.Signature{
+		Identity: api.Identity{Name: author.Name, Email: author.Email},
+		When:     committerDate,
+	}
+
+	repoPath := getFullPathForRepo(s.reposRoot, params.RepoUID)
+
+	if params.Branch == "" {
+		defaultBranch, err := s.git.GetDefaultBranch(ctx, repoPath)
+		if err != nil {
+			return CherryPickOutput{}, fmt.Errorf("failed to get default branch: %w", err)
+		}
+		params.Branch = defaultBranch
+	}
+	params.Branch = strings.TrimPrefix(strings.TrimSpace(params.Branch), gitReferenceNamePrefixBranch)
+	params.NewBranch = strings.TrimPrefix(strings.TrimSpace(params.NewBranch), gitReferenceNamePrefixBranch)
+
+	branch, err := s.git.GetBranch(ctx, repoPath, params.Branch)
+	if err != nil {
+		return CherryPickOutput{}, fmt.Errorf("failed to get branch '%s': %w", params.Branch, err)
+	}
+	baseSHA := branch.Commit.SHA
+
+	refOldSHA := baseSHA
+	branchName := params.Branch
+	if params.NewBranch != "" && params.NewBranch != params.Branch {
+		existingBranch, err := s.git.GetBranch(ctx, repoPath, params.NewBranch)
+		if existingBranch != nil {
+			return CherryPickOutput{}, errors.Conflict("branch %s already exists", existingBranch.Name)
+		}
+		if err != nil && !errors.IsNotFound(err) {
+			return CherryPickOutput{}, fmt.Errorf("failed to create new branch '%s': %w", params.NewBranch, err)
+		}
+
+		// we are creating a new branch, rather than updating the existing one
+		refOldSHA = sha.Nil
+		branchName = params.NewBranch
+	}
+
+	refUpdater, err := hook.CreateRefUpdater(s.hookClientFactory, params.EnvVars, repoPath,
+		api.GetReferenceFromBranchName(branchName))
+	if err != nil {
+		return CherryPickOutput{}, fmt.Errorf("failed to create ref updater: %w", err)
+	}
+
+	var commitSHA sha.SHA
+
+	err = sharedrepo.Run(ctx, refUpdater, s.tmpDir, repoPath, func(r *sharedrepo.SharedRepo) error {
+		r.SetSigner(s.signer)
+
+		lastCommitSHA := baseSHA
+		lastTreeSHA, err := r.GetTreeSHA(ctx, baseSHA.String())
+		if err != nil {
+			return fmt.Errorf("failed to get tree sha for base: %w", err)
+		}
+		baseTreeSHA := lastTreeSHA
+
+		for _, sourceSHA := range params.CommitSHAs {
+			commit, err := api.GetCommit(ctx, r.Directory(), sourceSHA.String())
+			if err != nil {
+				return fmt.Errorf("failed to get commit %s: %w", sourceSHA, err)
+			}
+
+			parentSHA := params.ParentSHA
+			if parentSHA.IsEmpty() {
+				if len(commit.ParentSHAs) == 0 {
+					return errors.InvalidArgument("Commit %s has no parent and can't be applied.", commit.SHA)
+				}
+				parentSHA = commit.ParentSHAs[0]
+			}
+
+			// merge-tree with the parent as the merge base applies only the changes introduced by the commit,
+			// swapping the commit with its parent applies the inverse of the changes.
+			var treeSHA sha.SHA
+			var conflicts []string
+			if revert {
+				treeSHA, conflicts, err = r.MergeTree(ctx, commit.SHA, lastCommitSHA, parentSHA)
+			} else {
+				treeSHA, conflicts, err = r.MergeTree(ctx, parentSHA, lastCommitSHA, commit.SHA)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to merge tree of commit %s: %w", commit.SHA, err)
+			}
+			if len(conflicts) > 0 {
+				return errors.Conflict("Failed to %s commit %s, conflicts in files: %s.",
+					operation, commit.SHA, strings.Join(conflicts, ", "))
+			}
+
+			// skip the commit if its changes already exist on the branch, like git does on default.
+			if treeSHA.Equal(lastTreeSHA) {
+				log.Ctx(ctx).Debug().Msgf("skipping commit %s as it's empty after %s", commit.SHA, operation)
+				continue
+			}
+
+			commitAuthor := authorSig
+			var message string
+			if revert {
+				message = fmt.Sprintf("Revert %q\n\nThis reverts commit %s.", commit.Title, commit.SHA)
+			} else {
+				commitAuthor = &commit.Author
+				message = CommitMessage(commit.Title, commit.Message) +
+					fmt.Sprintf("\n\n(cherry picked from commit %s)", commit.SHA)
+			}
+
+			lastCommitSHA, err = r.CommitTree(ctx, commitAuthor, committerSig, treeSHA, message, false, lastCommitSHA)
+			if err != nil {
+				return fmt.Errorf("failed to commit tree of commit %s: %w", commit.SHA, err)
+			}
+			lastTreeSHA = treeSHA
+		}
+
+		if lastTreeSHA.Equal(baseTreeSHA) {
+			return errors.InvalidArgument("No effective changes.")
+		}
+
+		if params.Message != "" {
+			// the intermediate commits are discarded, all changes are committed as a single commit.
+			lastCommitSHA, err = r.CommitTree(ctx, authorSig, committerSig, lastTreeSHA,
+				parser.CleanUpWhitespace(params.Message), false, baseSHA)
+			if err != nil {
+				return fmt.Errorf("failed to commit tree: %w", err)
+			}
+		}
+
+		commitSHA = lastCommitSHA
+
+		if err := refUpdater.Init(ctx, refOldSHA, commitSHA); err != nil {
+			return fmt.Errorf("failed to init ref updater old=%s new=%s: %w", refOldSHA, commitSHA, err)
+		}
+
+		return nil
+	})
+	if errors.IsConflict(err) || errors.IsInvalidArgument(err) {
+		return CherryPickOutput{}, err
+	}
+	if err != nil {
+		return CherryPickOutput{}, fmt.Errorf("failed to %s commits in shared repository: %w", operation, err)
+	}
+
+	return CherryPickOutput{
+		BaseSHA:   baseSHA,
+		CommitSHA: commitSHA,
+		Branch:    branchName,
+	}, nil
+}
@@ -10,6 +10,7 @@ import (
 	"context"
 
 	"github.com/easysoft/gitfox/git/api"
+	"github.com/easysoft/gitfox/git/sha"
 )
 
 func (s *Service) ListCommitSHAs(ctx context.Context, params *ListCommitsParams) ([]string, error) {
@@ -42,3 +43,37 @@ func (s *Service) ListCommitSHAs(ctx context.Context, params *ListCommitsParams)
 
 	return gitCommits, nil
 }
+
+type ListNewCommitsParams struct {
+	ReadParams
+	// SHA is the commit the new commits are reachable from.
+	SHA sha.SHA
+	// OldSHA is the commit the branch pointed to before, the commits reachable from it aren't new.
+	OldSHA sha.SHA
+	// BaseBranch is used for a new branch without OldSHA, the commits reachable from
+	// the merge base of SHA and the branch aren't new.
+	BaseBranch string
+}
+
+// ListNewCommitSHAs lists the commits reachable from SHA which aren't reachable from OldSHA,
+// or from the merge base with BaseBranch for a new branch.
+func (s *Service) ListNewCommitSHAs(ctx context.Context, params *ListNewCommitsParams) ([]string, error) {
+	if params == nil {
+		return nil, ErrNoParamsProvided
+	}
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	repoPath := getFullPathForRepo(s.reposRoot, params.RepoUID)
+
+	var oldSHA, baseRef string
+	if !params.OldSHA.IsEmpty() && !params.OldSHA.IsNil() {
+		oldSHA = params.OldSHA.String()
+	}
+	if params.BaseBranch != "" {
+		baseRef = api.GetReferenceFromBranchName(params.BaseBranch)
+	}
+
+	return s.git.ListNewCommitSHAs(ctx, repoPath, params.AlternateObjectDirs, params.SHA.String(), oldSHA, baseRef)
+}
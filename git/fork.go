@@ -0,0 +1,162 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package git
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/easysoft/gitfox/errors"
+	"github.com/easysoft/gitfox/git/command"
+	"github.com/easysoft/gitfox/git/sha"
+
+	"github.com/rs/zerolog/log"
+)
+
+// forkRefSpecs are the references copied from the upstream repository when forking,
+// the internal references like the pull request heads stay in the upstream.
+var forkRefSpecs = []string{"+refs/heads/*:refs/heads/*", "+refs/tags/*:refs/tags/*"}
+
+type ForkRepositoryParams struct {
+	// RepoUID of the WriteParams is the UID of the new fork repository.
+	WriteParams
+	UpstreamRepoUID string
+	DefaultBranch   string
+}
+
+func (p *ForkRepositoryParams) Validate() error {
+	if p == nil {
+		return ErrNoParamsProvided
+	}
+	if err := p.WriteParams.Validate(); err != nil {
+		return err
+	}
+	if p.UpstreamRepoUID == "" {
+		return errors.InvalidArgument("upstream repository id cannot be empty")
+	}
+	if p.DefaultBranch == "" {
+		return errors.InvalidArgument("default branch cannot be empty")
+	}
+	return nil
+}
+
+// ForkRepository creates the repository as a fork of the upstream repository. The fork borrows
+// the objects of the upstream using git alternates, only the branches and tags are copied.
+func (s *Service) ForkRepository(ctx context.Context, params *ForkRepositoryParams) error {
+	if err := params.Validate(); err != nil {
+		return err
+	}
+
+	upstreamPath := getFullPathForRepo(s.reposRoot, params.UpstreamRepoUID)
+	if _, err := os.Stat(upstreamPath); err != nil {
+		return errors.NotFound("upstream repository not found")
+	}
+
+	err := s.createRepositoryInternal(ctx, &params.WriteParams, params.DefaultBranch,
+		nil, nil, time.Time{}, nil, time.Time{})
+	if err != nil {
+		return err
+	}
+
+	repoPath := getFullPathForRepo(s.reposRoot, params.RepoUID)
+	defer func() {
+		if err != nil {
+			if cleanupErr := s.DeleteRepositoryBestEffort(ctx, params.RepoUID); cleanupErr != nil {
+				log.Ctx(ctx).Warn().Err(cleanupErr).Msg("failed to cleanup fork repo dir")
+			}
+		}
+	}()
+
+	alternates := filepath.Join(repoPath, "objects", "info", "alternates")
+	if err = os.WriteFile(alternates, []byte(filepath.Join(upstreamPath, "objects")+"\n"), 0o600); err != nil {
+		return errors.Internal(err, "failed to write alternates of the fork")
+	}
+
+	// the objects are borrowed from the upstream, the fetch only creates the references.
+	if err = s.git.Sync(ctx, repoPath, upstreamPath, forkRefSpecs); err != nil {
+		return fmt.Errorf("failed to fetch references of the upstream repo: %w", err)
+	}
+
+	return nil
+}
+
+type FetchObjectsParams struct {
+	WriteParams
+	SourceRepoUID string
+	ObjectSHAs    []sha.SHA
+}
+
+func (p *FetchObjectsParams) Validate() error {
+	if p == nil {
+		return ErrNoParamsProvided
+	}
+	if err := p.WriteParams.Validate(); err != nil {
+		return err
+	}
+	if p.SourceRepoUID == "" {
+		return errors.InvalidArgument("source repository id cannot be empty")
+	}
+	if len(p.ObjectSHAs) == 0 {
+		return errors.InvalidArgument("no objects to fetch")
+	}
+	return nil
+}
+
+// FetchObjects copies the objects reachable from the commits of the source repository to the repository,
+// no references are updated. It's used to make the commits of a fork available in the upstream repository.
+func (s *Service) FetchObjects(ctx context.Context, params *FetchObjectsParams) error {
+	if err := params.Validate(); err != nil {
+		return err
+	}
+
+	repoPath := getFullPathForRepo(s.reposRoot, params.RepoUID)
+	sourcePath := getFullPathForRepo(s.reposRoot, params.SourceRepoUID)
+
+	refSpecs := make([]string, len(params.ObjectSHAs))
+	for i, objectSHA := range params.ObjectSHAs {
+		refSpecs[i] = objectSHA.String()
+	}
+
+	if err := s.git.Sync(ctx, repoPath, sourcePath, refSpecs); err != nil {
+		return fmt.Errorf("failed to fetch objects from source repo: %w", err)
+	}
+
+	return nil
+}
+
+type DissociateRepositoryParams struct {
+	WriteParams
+}
+
+// DissociateRepository copies the objects borrowed from the alternates into the repository and removes
+// the alternates, it's required before the upstream repository of a fork is deleted.
+func (s *Service) DissociateRepository(ctx context.Context, params *DissociateRepositoryParams) error {
+	if err := params.Validate(); err != nil {
+		return err
+	}
+
+	repoPath := getFullPathForRepo(s.reposRoot, params.RepoUID)
+	alternates := filepath.Join(repoPath, "objects", "info", "alternates")
+	if _, err := os.Stat(alternates); errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+
+	cmd := command.New("repack", command.WithFlag("-a", "-d", "-q"))
+	if err := cmd.Run(ctx, command.WithDir(repoPath)); err != nil {
+		return errors.Internal(err, "failed to repack the repository")
+	}
+
+	if err := os.Remove(alternates); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return errors.Internal(err, "failed to remove alternates of the repository")
+	}
+
+	return nil
+}
@@ -0,0 +1,160 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package git
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/easysoft/gitfox/errors"
+	"github.com/easysoft/gitfox/git/command"
+
+	"github.com/rs/zerolog/log"
+)
+
+type GetRepositoryStatsParams struct {
+	ReadParams
+}
+
+type GetRepositoryStatsOutput struct {
+	// LooseObjects is the number of the loose objects, LooseSize is their size in KiB.
+	LooseObjects int64
+	LooseSize    int64
+	// PackedObjects is the number of the objects in Packs, PackSize is the size of the packs in KiB.
+	PackedObjects int64
+	Packs         int64
+	PackSize      int64
+	// Garbage is the number of the files in the object database that are neither objects nor packs.
+	Garbage int64
+	// HasAlternates is true if the repository borrows objects from another repository, e.g. a fork.
+	HasAlternates     bool
+	HasCommitGraph    bool
+	HasMultiPackIndex bool
+}
+
+// GetRepositoryStats counts the objects and packs of the repository,
+// the counts decide which housekeeping tasks the repository needs.
+func (s *Service) GetRepositoryStats(
+	ctx context.Context,
+	params *GetRepositoryStatsParams,
+) (*GetRepositoryStatsOutput, error) {
+	if params == nil {
+		return nil, ErrNoParamsProvided
+	}
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	repoPath := getFullPathForRepo(s.reposRoot, params.RepoUID)
+	count, err := s.git.CountObjects(ctx, repoPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count objects for repo: %w", err)
+	}
+
+	objectsPath := filepath.Join(repoPath, "objects")
+	return &GetRepositoryStatsOutput{
+		LooseObjects:  int64(count.Count),
+		LooseSize:     count.Size,
+		PackedObjects: int64(count.InPack),
+		Packs:         int64(count.Packs),
+		PackSize:      count.SizePack,
+		Garbage:       int64(count.Garbage),
+		HasAlternates: fileExists(filepath.Join(objectsPath, "info", "alternates")),
+		HasCommitGraph: fileExists(filepath.Join(objectsPath, "info", "commit-graph")) ||
+			fileExists(filepath.Join(objectsPath, "info", "commit-graphs", "commit-graph-chain")),
+		HasMultiPackIndex: fileExists(filepath.Join(objectsPath, "pack", "multi-pack-index")),
+	}, nil
+}
+
+// HousekeepingTasks are the tasks optimizing the repository, they run in the order of the fields.
+type HousekeepingTasks struct {
+	// Repack packs the loose objects into a new pack, the existing packs are kept.
+	Repack bool
+	// GC repacks all objects into a single pack and packs the references.
+	GC bool
+	// PruneExpiry is the expiry date of the unreachable loose objects removed by GC, like "2.weeks.ago".
+	// If empty, no object is pruned, which is required for the repositories whose objects are borrowed.
+	PruneExpiry string
+	// MultiPackIndex writes the index over all packs, it speeds up the lookup of repositories with many packs.
+	MultiPackIndex bool
+	// CommitGraph writes the commit-graph file, it speeds up the history walks.
+	CommitGraph bool
+}
+
+// IsEmpty returns true if there is no task to run.
+func (t HousekeepingTasks) IsEmpty() bool {
+	return !t.Repack && !t.GC && !t.MultiPackIndex && !t.CommitGraph
+}
+
+type OptimizeRepositoryParams struct {
+	ReadParams
+	Tasks HousekeepingTasks
+}
+
+// OptimizeRepository runs the housekeeping tasks on the repository. The tasks don't block the writes to
+// the repository, gc only prunes the unreachable objects older than the expiry so the objects of a running
+// push or merge are kept. Only the local objects are repacked, the objects borrowed using alternates stay
+// where they are.
+func (s *Service) OptimizeRepository(ctx context.Context, params *OptimizeRepositoryParams) error {
+	if params == nil {
+		return ErrNoParamsProvided
+	}
+	if err := params.Validate(); err != nil {
+		return err
+	}
+	if params.Tasks.IsEmpty() {
+		return nil
+	}
+
+	repoPath := getFullPathForRepo(s.reposRoot, params.RepoUID)
+	tasks := params.Tasks
+
+	switch {
+	case tasks.GC:
+		prune := "--prune=never"
+		if tasks.PruneExpiry != "" {
+			prune = "--prune=" + tasks.PruneExpiry
+		}
+		cmd := command.New("gc", command.WithFlag("--quiet", prune))
+		if err := cmd.Run(ctx, command.WithDir(repoPath)); err != nil {
+			return errors.Internal(err, "failed to run gc on the repository")
+		}
+	case tasks.Repack:
+		cmd := command.New("repack", command.WithFlag("-d", "-l", "-q"))
+		if err := cmd.Run(ctx, command.WithDir(repoPath)); err != nil {
+			return errors.Internal(err, "failed to repack the repository")
+		}
+	}
+
+	if tasks.MultiPackIndex {
+		cmd := command.New("multi-pack-index", command.WithAction("write"))
+		if err := cmd.Run(ctx, command.WithDir(repoPath)); err != nil {
+			return errors.Internal(err, "failed to write the multi-pack-index of the repository")
+		}
+	}
+
+	if tasks.CommitGraph {
+		cmd := command.New("commit-graph",
+			command.WithAction("write"),
+			command.WithFlag("--reachable"),
+		)
+		if err := cmd.Run(ctx, command.WithDir(repoPath)); err != nil {
+			return errors.Internal(err, "failed to write the commit-graph of the repository")
+		}
+	}
+
+	log.Ctx(ctx).Debug().Msgf("optimized repo %s with tasks %+v", params.RepoUID, tasks)
+
+	return nil
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
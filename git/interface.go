@@ -49,7 +49,19 @@ type Interface interface {
 	UpdateRef(ctx context.Context, params UpdateRefParams) error
 
 	SyncRepository(ctx context.Context, params *SyncRepositoryParams) (*SyncRepositoryOutput, error)
-	MirrorSyncRepository(ctx context.Context, params *MirrorSyncParams) (bool, error)
+	MirrorSyncRepository(ctx context.Context, params *MirrorSyncParams) (*MirrorSyncOutput, error)
+
+	// ForkRepository creates a fork of the upstream repository sharing the upstream objects.
+	ForkRepository(ctx context.Context, params *ForkRepositoryParams) error
+	// FetchObjects copies the commits of the source repository without updating any reference.
+	FetchObjects(ctx context.Context, params *FetchObjectsParams) error
+	// DissociateRepository stops borrowing objects from the upstream repository.
+	DissociateRepository(ctx context.Context, params *DissociateRepositoryParams) error
+
+	// GetRepositoryStats counts the objects and packs of a repo to plan its housekeeping.
+	GetRepositoryStats(ctx context.Context, params *GetRepositoryStatsParams) (*GetRepositoryStatsOutput, error)
+	// OptimizeRepository runs the housekeeping tasks like repack, gc and commit-graph on a repo.
+	OptimizeRepository(ctx context.Context, params *OptimizeRepositoryParams) error
 
 	MatchFiles(ctx context.Context, params *MatchFilesParams) (*MatchFilesOutput, error)
 
@@ -59,6 +71,8 @@ type Interface interface {
 	GetCommit(ctx context.Context, params *GetCommitParams) (*GetCommitOutput, error)
 	ListCommits(ctx context.Context, params *ListCommitsParams) (*ListCommitsOutput, error)
 	ListCommitSHAs(ctx context.Context, params *ListCommitsParams) ([]string, error)
+	// ListNewCommitSHAs lists the commits a push adds to a branch.
+	ListNewCommitSHAs(ctx context.Context, params *ListNewCommitsParams) ([]string, error)
 	ListCommitTags(ctx context.Context, params *ListCommitTagsParams) (*ListCommitTagsOutput, error)
 	CountCommits(ctx context.Context, params *CountCommitsParams) (*CountCommitsOutput, error)
 	CountCommitsWithShortstat(ctx context.Context, params *CountCommitsParams) (*CountCommitsShortstatOutput, error)
@@ -70,6 +84,9 @@ type Interface interface {
 		ctx context.Context,
 		params *FindOversizeFilesParams,
 	) (*FindOversizeFilesOutput, error)
+	ListLFSPointers(ctx context.Context, params *ListLFSPointersParams) (*ListLFSPointersOutput, error)
+	// GetSignatures reads the signatures of the signed commits and annotated tags.
+	GetSignatures(ctx context.Context, params *GetSignaturesParams) (*GetSignaturesOutput, error)
 
 	/*
 	 * Git Cli Service
@@ -99,6 +116,8 @@ type Interface interface {
 	 * Merge services
 	 */
 	Merge(ctx context.Context, in *MergeParams) (MergeOutput, error)
+	CherryPick(ctx context.Context, params *CherryPickParams) (CherryPickOutput, error)
+	Revert(ctx context.Context, params *CherryPickParams) (CherryPickOutput, error)
 
 	/*
 	 * Blame services
@@ -0,0 +1,95 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package git
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/easysoft/gitfox/git/api"
+	"github.com/easysoft/gitfox/git/command"
+	"github.com/easysoft/gitfox/git/parser"
+)
+
+type ListLFSPointersParams struct {
+	ReadParams
+}
+
+type ListLFSPointersOutput struct {
+	Pointers []parser.LFSPointer
+}
+
+// ListLFSPointers lists the git lfs pointers of all blobs in the repository,
+// the pointers of the same object are listed once.
+func (s *Service) ListLFSPointers(
+	ctx context.Context,
+	params *ListLFSPointersParams,
+) (*ListLFSPointersOutput, error) {
+	if params == nil {
+		return nil, ErrNoParamsProvided
+	}
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+	repoPath := getFullPathForRepo(s.reposRoot, params.RepoUID)
+
+	cmd := command.New("cat-file",
+		command.WithFlag("--batch-check"),
+		command.WithFlag("--batch-all-objects"),
+		command.WithFlag("--unordered"),
+		command.WithFlag("-Z"),
+		command.WithAlternateObjectDirs(params.AlternateObjectDirs...),
+	)
+	buffer := bytes.NewBuffer(nil)
+	if err := cmd.Run(ctx, command.WithDir(repoPath), command.WithStdout(buffer)); err != nil {
+		return nil, fmt.Errorf("failed to cat-file batch check all objects: %w", err)
+	}
+	objects, err := parser.CatFileBatchCheckAllObjects(buffer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse output of cat-file batch check all objects: %w", err)
+	}
+
+	writer, reader, cancel := api.CatFileBatch(ctx, repoPath, params.AlternateObjectDirs)
+	defer cancel()
+
+	seen := make(map[string]struct{})
+	pointers := make([]parser.LFSPointer, 0)
+	for _, obj := range objects {
+		if obj.Type != string(TreeNodeTypeBlob) || obj.Size > parser.LFSPointerMaxSize {
+			continue
+		}
+
+		if _, err = writer.Write([]byte(obj.SHA.String() + "\n")); err != nil {
+			return nil, fmt.Errorf("failed to write blob sha to cat-file batch: %w", err)
+		}
+		header, err := api.ReadBatchHeaderLine(reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read cat-file batch header: %w", err)
+		}
+		// the content is followed by a LF
+		content := make([]byte, header.Size+1)
+		if _, err = io.ReadFull(reader, content); err != nil {
+			return nil, fmt.Errorf("failed to read blob %s: %w", obj.SHA, err)
+		}
+
+		pointer, ok := parser.ParseLFSPointer(content[:header.Size])
+		if !ok {
+			continue
+		}
+		if _, ok = seen[pointer.OID]; ok {
+			continue
+		}
+		seen[pointer.OID] = struct{}{}
+		pointers = append(pointers, pointer)
+	}
+
+	return &ListLFSPointersOutput{
+		Pointers: pointers,
+	}, nil
+}
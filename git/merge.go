@@ -38,7 +38,6 @@ type MergeParams struct {
 	BaseBranch string
 
 	// HeadRepoUID specifies the UID of the repo that contains the head branch (required for forking).
-	// WARNING: This field is currently not supported yet!
 	HeadRepoUID string
 	HeadBranch  string
 
@@ -187,7 +186,13 @@ func (s *Service) Merge(ctx context.Context, params *MergeParams) (MergeOutput,
 		}
 	}
 
-	headCommitSHA, err := s.git.GetFullCommitID(ctx, repoPath, params.HeadBranch)
+	// the head branch of a pull request from a fork is in the fork repository.
+	headRepoPath := repoPath
+	if params.HeadRepoUID != "" && params.HeadRepoUID != params.RepoUID {
+		headRepoPath = getFullPathForRepo(s.reposRoot, params.HeadRepoUID)
+	}
+
+	headCommitSHA, err := s.git.GetFullCommitID(ctx, headRepoPath, params.HeadBranch)
 	if err != nil {
 		return MergeOutput{}, fmt.Errorf("failed to get head branch commit SHA: %w", err)
 	}
@@ -200,6 +205,13 @@ func (s *Service) Merge(ctx context.Context, params *MergeParams) (MergeOutput,
 			params.HeadExpectedSHA)
 	}
 
+	if headRepoPath != repoPath {
+		// the commits of the fork must exist in the base repository to be merged.
+		if err = s.git.Sync(ctx, repoPath, headRepoPath, []string{headCommitSHA.String()}); err != nil {
+			return MergeOutput{}, fmt.Errorf("failed to fetch head commit from the head repo: %w", err)
+		}
+	}
+
 	mergeBaseCommitSHA, _, err := s.git.GetMergeBase(ctx, repoPath, "origin",
 		baseCommitSHA.String(), headCommitSHA.String())
 	if err != nil {
@@ -273,6 +285,7 @@ func (s *Service) Merge(ctx context.Context, params *MergeParams) (MergeOutput,
 	mergeCommitSHA, conflicts, err := mergeFunc(
 		ctx,
 		refUpdater,
+		s.signer,
 		repoPath, s.tmpDir,
 		&author, &committer,
 		message,
@@ -36,6 +36,7 @@ var (
 type Func func(
 	ctx context.Context,
 	refUpdater *hook.RefUpdater,
+	signer api.Signer,
 	repoPath, tmpDir string,
 	author, committer *api.Signature,
 	message string,
@@ -46,6 +47,7 @@ type Func func(
 func Merge(
 	ctx context.Context,
 	refUpdater *hook.RefUpdater,
+	signer api.Signer,
 	repoPath, tmpDir string,
 	author, committer *api.Signature,
 	message string,
@@ -53,6 +55,7 @@ func Merge(
 ) (mergeSHA sha.SHA, conflicts []string, err error) {
 	return mergeInternal(ctx,
 		refUpdater,
+		signer,
 		repoPath, tmpDir,
 		author, committer,
 		message,
@@ -64,6 +67,7 @@ func Merge(
 func Squash(
 	ctx context.Context,
 	refUpdater *hook.RefUpdater,
+	signer api.Signer,
 	repoPath, tmpDir string,
 	author, committer *api.Signature,
 	message string,
@@ -71,6 +75,7 @@ func Squash(
 ) (mergeSHA sha.SHA, conflicts []string, err error) {
 	return mergeInternal(ctx,
 		refUpdater,
+		signer,
 		repoPath, tmpDir,
 		author, committer,
 		message,
@@ -82,6 +87,7 @@ func Squash(
 func mergeInternal(
 	ctx context.Context,
 	refUpdater *hook.RefUpdater,
+	signer api.Signer,
 	repoPath, tmpDir string,
 	author, committer *api.Signature,
 	message string,
@@ -89,6 +95,8 @@ func mergeInternal(
 	squash bool,
 ) (mergeSHA sha.SHA, conflicts []string, err error) {
 	err = sharedrepo.Run(ctx, refUpdater, tmpDir, repoPath, func(s *sharedrepo.SharedRepo) error {
+		s.SetSigner(signer)
+
 		var err error
 
 		var treeSHA sha.SHA
@@ -132,12 +140,15 @@ func mergeInternal(
 func Rebase(
 	ctx context.Context,
 	refUpdater *hook.RefUpdater,
+	signer api.Signer,
 	repoPath, tmpDir string,
 	_, committer *api.Signature, // commit author isn't used here - it's copied from every commit
 	_ string, // commit message isn't used here
 	mergeBaseSHA, targetSHA, sourceSHA sha.SHA,
 ) (mergeSHA sha.SHA, conflicts []string, err error) {
 	err = sharedrepo.Run(ctx, refUpdater, tmpDir, repoPath, func(s *sharedrepo.SharedRepo) error {
+		s.SetSigner(signer)
+
 		sourceSHAs, err := s.CommitSHAsForRebase(ctx, mergeBaseSHA, sourceSHA)
 		if err != nil {
 			return fmt.Errorf("failed to find commit list in rebase merge: %w", err)
@@ -220,6 +231,7 @@ func Rebase(
 func FastForward(
 	ctx context.Context,
 	refUpdater *hook.RefUpdater,
+	_ api.Signer, // the commits of the source are kept as is
 	repoPath, tmpDir string,
 	_, _ *api.Signature, // commit author and committer aren't used here
 	_ string, // commit message isn't used here
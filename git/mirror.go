@@ -8,6 +8,12 @@ package git
 
 import (
 	"context"
+	"errors"
+	"fmt"
+
+	"github.com/easysoft/gitfox/git/api"
+	"github.com/easysoft/gitfox/git/hook"
+	"github.com/easysoft/gitfox/git/sha"
 )
 
 type MirrorSyncParams struct {
@@ -15,8 +21,82 @@ type MirrorSyncParams struct {
 	Prune bool
 }
 
-func (s *Service) MirrorSyncRepository(ctx context.Context, params *MirrorSyncParams) (bool, error) {
+type MirrorSyncOutput struct {
+	// RefUpdates lists the references changed by the sync, the created references have a nil old sha
+	// and the deleted references have a nil new sha.
+	RefUpdates []hook.ReferenceUpdate
+	// RemoteURL is the url of the mirrored remote, it may carry the credentials.
+	RemoteURL string
+}
+
+// MirrorSyncRepository fetches the mirror remote into the repository.
+func (s *Service) MirrorSyncRepository(ctx context.Context, params *MirrorSyncParams) (*MirrorSyncOutput, error) {
+	if params == nil {
+		return nil, ErrNoParamsProvided
+	}
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
 	repoPath := getFullPathForRepo(s.reposRoot, params.RepoUID)
-	err, sync := s.git.MirrorFetch(ctx, repoPath, "", params.Prune)
-	return sync, err
+
+	remoteURL, err := s.git.GetRemoteAddress(ctx, repoPath, s.git.GetRemoteName())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mirror remote: %w", err)
+	}
+
+	before, err := s.listMirrorRefs(ctx, repoPath)
+	if err != nil {
+		return nil, err
+	}
+	if err, _ = s.git.MirrorFetch(ctx, repoPath, "", params.Prune); err != nil {
+		return nil, err
+	}
+	after, err := s.listMirrorRefs(ctx, repoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := make([]hook.ReferenceUpdate, 0)
+	for ref, newSHA := range after {
+		oldSHA, ok := before[ref]
+		if !ok {
+			oldSHA = sha.Nil
+		}
+		if !oldSHA.Equal(newSHA) {
+			updates = append(updates, hook.ReferenceUpdate{Ref: ref, Old: oldSHA, New: newSHA})
+		}
+	}
+	for ref, oldSHA := range before {
+		if _, ok := after[ref]; !ok {
+			updates = append(updates, hook.ReferenceUpdate{Ref: ref, Old: oldSHA, New: sha.Nil})
+		}
+	}
+
+	return &MirrorSyncOutput{
+		RefUpdates: updates,
+		RemoteURL:  remoteURL,
+	}, nil
+}
+
+// listMirrorRefs lists the branches and tags of the repository.
+func (s *Service) listMirrorRefs(ctx context.Context, repoPath string) (map[string]sha.SHA, error) {
+	refs := make(map[string]sha.SHA)
+	err := s.git.WalkReferences(ctx, repoPath, func(e api.WalkReferencesEntry) error {
+		ref, ok := e[api.GitReferenceFieldRefName]
+		if !ok {
+			return errors.New("ref entry didn't contain the ref name")
+		}
+		objectSHA, err := sha.New(e[api.GitReferenceFieldObjectName])
+		if err != nil {
+			return fmt.Errorf("invalid sha of ref %s: %w", ref, err)
+		}
+		refs[ref] = objectSHA
+		return nil
+	}, &api.WalkReferencesOptions{
+		Patterns: []string{"refs/heads/", "refs/tags/"},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list references: %w", err)
+	}
+	return refs, nil
 }
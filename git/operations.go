@@ -155,6 +155,8 @@ func (s *Service) CommitFiles(ctx context.Context, params *CommitFilesParams) (C
 	// run the actions in a shared repo
 
 	err = sharedrepo.Run(ctx, refUpdater, s.tmpDir, repoPath, func(r *sharedrepo.SharedRepo) error {
+		r.SetSigner(s.signer)
+
 		var parentCommits []sha.SHA
 		var oldTreeSHA sha.SHA
 
@@ -0,0 +1,73 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package parser
+
+import (
+	"bufio"
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	// LFSPointerMaxSize is the max size of a git lfs pointer file, larger blobs are never pointers.
+	LFSPointerMaxSize = 1024
+
+	lfsPointerVersion = "version https://git-lfs.github.com/spec/v1"
+	lfsPointerOID     = "oid sha256:"
+	lfsPointerSize    = "size "
+)
+
+var regexpLFSObjectID = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// LFSPointer is the content of a git lfs pointer file.
+type LFSPointer struct {
+	OID  string
+	Size int64
+}
+
+// IsValidLFSObjectID reports whether the oid is a sha256 hash in hex.
+func IsValidLFSObjectID(oid string) bool {
+	return regexpLFSObjectID.MatchString(oid)
+}
+
+// ParseLFSPointer parses the git lfs pointer file, false is returned if the content isn't a pointer,
+// see https://github.com/git-lfs/git-lfs/blob/main/docs/spec.md.
+func ParseLFSPointer(content []byte) (LFSPointer, bool) {
+	if len(content) > LFSPointerMaxSize {
+		return LFSPointer{}, false
+	}
+
+	var (
+		pointer    LFSPointer
+		hasVersion bool
+		hasSize    bool
+	)
+	scan := bufio.NewScanner(bytes.NewReader(content))
+	for scan.Scan() {
+		line := scan.Text()
+		switch {
+		case line == lfsPointerVersion:
+			hasVersion = true
+		case strings.HasPrefix(line, lfsPointerOID):
+			pointer.OID = strings.TrimPrefix(line, lfsPointerOID)
+		case strings.HasPrefix(line, lfsPointerSize):
+			size, err := strconv.ParseInt(strings.TrimPrefix(line, lfsPointerSize), 10, 64)
+			if err != nil || size < 0 {
+				return LFSPointer{}, false
+			}
+			pointer.Size = size
+			hasSize = true
+		}
+	}
+	if scan.Err() != nil || !hasVersion || !hasSize || !IsValidLFSObjectID(pointer.OID) {
+		return LFSPointer{}, false
+	}
+
+	return pointer, true
+}
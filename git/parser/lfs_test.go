@@ -0,0 +1,61 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testLFSOID = "4d7a214614ab2935c943f9e0ff69d22eadbb8f32b1258daaa5e2ca24d17e2393"
+
+func TestParseLFSPointer(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    LFSPointer
+		ok      bool
+	}{
+		{
+			name:    "valid",
+			content: "version https://git-lfs.github.com/spec/v1\noid sha256:" + testLFSOID + "\nsize 12345\n",
+			want:    LFSPointer{OID: testLFSOID, Size: 12345},
+			ok:      true,
+		},
+		{
+			name: "extension keys",
+			content: "version https://git-lfs.github.com/spec/v1\next-0-foo sha256:" + testLFSOID +
+				"\noid sha256:" + testLFSOID + "\nsize 0\n",
+			want: LFSPointer{OID: testLFSOID, Size: 0},
+			ok:   true,
+		},
+		{
+			name:    "missing version",
+			content: "oid sha256:" + testLFSOID + "\nsize 12345\n",
+		},
+		{
+			name:    "invalid oid",
+			content: "version https://git-lfs.github.com/spec/v1\noid sha256:abc\nsize 12345\n",
+		},
+		{
+			name:    "missing size",
+			content: "version https://git-lfs.github.com/spec/v1\noid sha256:" + testLFSOID + "\n",
+		},
+		{
+			name:    "regular file",
+			content: "package main\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ParseLFSPointer([]byte(tt.content))
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -25,6 +25,10 @@ import (
 type PushRemoteParams struct {
 	ReadParams
 	RemoteURL string
+	// RefSpecs are pushed to the remote, all refs are mirrored to the remote if empty.
+	RefSpecs []string
+	// Prune removes the remote refs matching the RefSpecs which don't exist in the repo.
+	Prune bool
 }
 
 func (p *PushRemoteParams) Validate() error {
@@ -57,10 +61,12 @@ func (s *Service) PushRemote(ctx context.Context, params *PushRemoteParams) erro
 	}
 
 	err = s.git.Push(ctx, repoPath, api.PushOptions{
-		Remote: params.RemoteURL,
-		Force:  false,
-		Env:    nil,
-		Mirror: true,
+		Remote:   params.RemoteURL,
+		Force:    false,
+		Env:      nil,
+		Mirror:   len(params.RefSpecs) == 0,
+		Prune:    params.Prune,
+		RefSpecs: params.RefSpecs,
 	})
 	if err != nil {
 		return fmt.Errorf("PushRemote: failed to push to remote repository: %w", err)
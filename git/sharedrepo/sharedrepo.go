@@ -44,6 +44,7 @@ import (
 type SharedRepo struct {
 	repoPath       string
 	sourceRepoPath string
+	signer         api.Signer
 }
 
 // NewSharedRepo creates a new temporary bare repository.
@@ -386,7 +387,7 @@ func (r *SharedRepo) CommitTree(
 		cmd.Add(command.WithFlag("-p", parentCommit.String()))
 	}
 
-	// temporary no signing
+	// the commit is signed by the server signer, never by the git config of the host
 	cmd.Add(command.WithFlag("--no-gpg-sign"))
 
 	messageBytes := new(bytes.Buffer)
@@ -410,7 +411,12 @@ func (r *SharedRepo) CommitTree(
 		return sha.None, fmt.Errorf("failed to commit-tree in shared repo: %w", err)
 	}
 
-	return sha.New(stdout.String())
+	commitSHA, err := sha.New(stdout.String())
+	if err != nil {
+		return sha.None, err
+	}
+
+	return r.signObject(ctx, api.GitObjectTypeCommit, commitSHA)
 }
 
 // CommitSHAsForRebase returns list of SHAs of the commits between the two git revisions
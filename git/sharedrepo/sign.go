@@ -0,0 +1,37 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package sharedrepo
+
+import (
+	"context"
+
+	"github.com/easysoft/gitfox/git/api"
+	"github.com/easysoft/gitfox/git/sha"
+)
+
+// SetSigner sets the signer of the commits and tags created in the shared repository.
+func (r *SharedRepo) SetSigner(signer api.Signer) {
+	r.signer = signer
+}
+
+// SignTag signs the annotated tag, the SHA of the signed tag object is returned.
+// The tag is returned as is if there's no signer.
+func (r *SharedRepo) SignTag(ctx context.Context, tagSHA sha.SHA) (sha.SHA, error) {
+	return r.signObject(ctx, api.GitObjectTypeTag, tagSHA)
+}
+
+// signObject writes a signed copy of the commit or tag object.
+func (r *SharedRepo) signObject(
+	ctx context.Context,
+	objectType api.GitObjectType,
+	objectSHA sha.SHA,
+) (sha.SHA, error) {
+	if r.signer == nil {
+		return objectSHA, nil
+	}
+	return api.SignObject(ctx, r.repoPath, r.signer, objectType, objectSHA)
+}
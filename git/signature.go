@@ -0,0 +1,78 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package git
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/easysoft/gitfox/errors"
+	"github.com/easysoft/gitfox/git/sha"
+)
+
+type GetSignaturesParams struct {
+	ReadParams
+	// ObjectSHAs are the commits and annotated tags to read the signatures of.
+	ObjectSHAs []sha.SHA
+}
+
+func (p *GetSignaturesParams) Validate() error {
+	if err := p.ReadParams.Validate(); err != nil {
+		return err
+	}
+	if len(p.ObjectSHAs) == 0 {
+		return errors.InvalidArgument("object SHAs are mandatory")
+	}
+	return nil
+}
+
+// ObjectSignature is the signature of a commit or an annotated tag,
+// Signer is the committer of the commit or the tagger of the tag.
+type ObjectSignature struct {
+	ObjectSHA sha.SHA
+	Signer    Signature
+	// Signature is the armored PGP or SSH signature.
+	Signature []byte
+	// SignedContent is the object content without the signature.
+	SignedContent []byte
+}
+
+type GetSignaturesOutput struct {
+	Signatures []ObjectSignature
+}
+
+// GetSignatures reads the signatures of the commits and annotated tags, the unsigned objects are skipped.
+func (s *Service) GetSignatures(ctx context.Context, params *GetSignaturesParams) (*GetSignaturesOutput, error) {
+	if params == nil {
+		return nil, ErrNoParamsProvided
+	}
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+	repoPath := getFullPathForRepo(s.reposRoot, params.RepoUID)
+
+	objects, err := s.git.GetSignedObjects(ctx, repoPath, params.AlternateObjectDirs, params.ObjectSHAs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get signed objects: %w", err)
+	}
+
+	signatures := make([]ObjectSignature, len(objects))
+	for i := range objects {
+		signer, err := mapSignature(&objects[i].Signer)
+		if err != nil {
+			return nil, fmt.Errorf("failed to map signer of %s: %w", objects[i].SHA, err)
+		}
+		signatures[i] = ObjectSignature{
+			ObjectSHA:     objects[i].SHA,
+			Signer:        *signer,
+			Signature:     []byte(objects[i].Signature.Signature),
+			SignedContent: []byte(objects[i].Signature.Payload),
+		}
+	}
+
+	return &GetSignaturesOutput{Signatures: signatures}, nil
+}
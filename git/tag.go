@@ -296,7 +296,15 @@ func (s *Service) CreateCommitTag(ctx context.Context, params *CreateCommitTagPa
 			return fmt.Errorf("failed to read annotated tag after creation: %w", err)
 		}
 
-		if err := refUpdater.Init(ctx, sha.Nil, tag.Sha); err != nil {
+		tagSHA := tag.Sha
+		if params.Message != "" {
+			r.SetSigner(s.signer)
+			if tagSHA, err = r.SignTag(ctx, tag.Sha); err != nil {
+				return fmt.Errorf("failed to sign tag '%s': %w", tagName, err)
+			}
+		}
+
+		if err := refUpdater.Init(ctx, sha.Nil, tagSHA); err != nil {
 			return fmt.Errorf("failed to init ref updater: %w", err)
 		}
 
@@ -23,6 +23,7 @@ import (
 
 	"github.com/easysoft/gitfox/errors"
 	"github.com/easysoft/gitfox/git/api"
+	"github.com/easysoft/gitfox/git/command"
 
 	"github.com/rs/zerolog/log"
 )
@@ -79,6 +80,9 @@ func (s *Service) addFilesAndPush(
 	if err != nil {
 		return fmt.Errorf("failed to commit files: %w", err)
 	}
+	if err = s.signHead(ctx, repoPath); err != nil {
+		return fmt.Errorf("failed to sign commit: %w", err)
+	}
 
 	err = s.git.Push(ctx, repoPath, api.PushOptions{
 		// TODO: Don't hard-code
@@ -95,6 +99,28 @@ func (s *Service) addFilesAndPush(
 	return nil
 }
 
+// signHead replaces the commit at HEAD with a signed copy before it's pushed.
+func (s *Service) signHead(ctx context.Context, repoPath string) error {
+	if s.signer == nil {
+		return nil
+	}
+
+	head, err := s.git.ResolveRev(ctx, repoPath, "HEAD")
+	if err != nil {
+		return err
+	}
+	signed, err := api.SignObject(ctx, repoPath, s.signer, api.GitObjectTypeCommit, head)
+	if err != nil || signed.Equal(head) {
+		return err
+	}
+
+	cmd := command.New("update-ref", command.WithArg("HEAD", signed.String(), head.String()))
+	if err = cmd.Run(ctx, command.WithDir(repoPath)); err != nil {
+		return fmt.Errorf("failed to update HEAD to the signed commit: %w", err)
+	}
+	return nil
+}
+
 func (s *Service) handleFileUploadIfAvailable(
 	ctx context.Context,
 	basePath string,
@@ -12,6 +12,7 @@ require (
 	github.com/Masterminds/semver v1.5.0
 	github.com/Masterminds/semver/v3 v3.2.1
 	github.com/Masterminds/squirrel v1.5.4
+	github.com/ProtonMail/go-crypto v1.1.6
 	github.com/adrg/xdg v0.5.0
 	github.com/antonmedv/expr v1.15.5
 	github.com/aws/aws-sdk-go v1.55.2
@@ -113,6 +114,7 @@ require (
 	github.com/beorn7/perks v1.0.1 // indirect
 	github.com/charmbracelet/lipgloss v0.12.1 // indirect
 	github.com/charmbracelet/x/ansi v0.1.4 // indirect
+	github.com/cloudflare/circl v1.3.7 // indirect
 	github.com/coreos/go-semver v0.3.1 // indirect
 	github.com/drone/envsubst v1.0.3 // indirect
 	github.com/drone/spec v0.0.0-20230920145636-3827abdce961 // indirect
@@ -227,7 +229,7 @@ require (
 	github.com/swaggest/refl v1.1.0 // indirect
 	github.com/vearutop/statigz v1.4.0 // indirect
 	github.com/yuin/goldmark v1.4.13
-	golang.org/x/mod v0.19.0 // indirect
+	golang.org/x/mod v0.19.0
 	golang.org/x/net v0.29.0
 	golang.org/x/sys v0.25.0 // indirect
 	golang.org/x/tools v0.23.0 // indirect
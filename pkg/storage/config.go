@@ -10,6 +10,7 @@ type DriverType string
 
 const (
 	DriverFilesystem DriverType = "filesystem"
+	DriverS3         DriverType = "s3aws"
 )
 
 type DriverConfig struct {
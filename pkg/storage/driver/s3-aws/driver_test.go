@@ -0,0 +1,598 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package s3
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	storagedriver "github.com/easysoft/gitfox/pkg/storage/driver"
+	"github.com/easysoft/gitfox/pkg/storage/driver/testsuites"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testBucket = "gitfox"
+	xmlTime    = "2006-01-02T15:04:05.000Z"
+)
+
+func newDriverConstructor(tb testing.TB, params map[string]interface{}) testsuites.DriverConstructor {
+	server := httptest.NewServer(newFakeS3())
+	tb.Cleanup(server.Close)
+
+	parameters := map[string]interface{}{
+		"accesskey":      "gitfox",
+		"secretkey":      "gitfox",
+		"region":         "us-east-1",
+		"regionendpoint": server.URL,
+		"bucket":         testBucket,
+		"rootdirectory":  "/gitfox",
+		"chunksize":      minChunkSize,
+		"secure":         false,
+		"forcepathstyle": true,
+	}
+	for k, v := range params {
+		parameters[k] = v
+	}
+
+	return func() (storagedriver.StorageDriver, error) {
+		return FromParameters(context.Background(), parameters)
+	}
+}
+
+// newRealDriverConstructor connects to the real s3 configured by the environment,
+// nil is returned if S3_BUCKET is not set.
+func newRealDriverConstructor() testsuites.DriverConstructor {
+	bucket := os.Getenv("S3_BUCKET")
+	if bucket == "" {
+		return nil
+	}
+
+	parameters := map[string]interface{}{
+		"accesskey":     os.Getenv("AWS_ACCESS_KEY"),
+		"secretkey":     os.Getenv("AWS_SECRET_KEY"),
+		"region":        os.Getenv("AWS_REGION"),
+		"bucket":        bucket,
+		"rootdirectory": "/gitfox-driver-test",
+		"redirect":      true,
+	}
+	if endpoint := os.Getenv("S3_REGION_ENDPOINT"); endpoint != "" {
+		parameters["regionendpoint"] = endpoint
+		parameters["forcepathstyle"] = true
+	}
+
+	return func() (storagedriver.StorageDriver, error) {
+		return FromParameters(context.Background(), parameters)
+	}
+}
+
+// TestS3DriverSuite runs the suite against the fake s3 server, the large streams are only written to
+// a real s3, which is used if S3_BUCKET is set.
+func TestS3DriverSuite(t *testing.T) {
+	if constructor := newRealDriverConstructor(); constructor != nil {
+		testsuites.Driver(t, constructor)
+		return
+	}
+	testsuites.DriverSkipLargeStreams(t, newDriverConstructor(t, map[string]interface{}{"redirect": true}))
+}
+
+func TestRedirectDisabled(t *testing.T) {
+	d, err := newDriverConstructor(t, nil)()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, d.PutContent(ctx, "/a/b", []byte("content")))
+
+	url, err := d.RedirectURL(httptest.NewRequest(http.MethodGet, "/a/b", nil), "/a/b")
+	require.NoError(t, err)
+	require.Empty(t, url)
+}
+
+func TestMoveMultipartCopy(t *testing.T) {
+	d, err := newDriverConstructor(t, map[string]interface{}{
+		"multipartcopythresholdsize":  minChunkSize,
+		"multipartcopychunksize":      minChunkSize,
+		"multipartcopymaxconcurrency": 2,
+	})()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	contents := bytes.Repeat([]byte("0123456789abcdef"), (12<<20)/16)
+	require.NoError(t, d.PutContent(ctx, "/src/blob", contents))
+	require.NoError(t, d.Move(ctx, "/src/blob", "/dst/blob"))
+
+	read, err := d.GetContent(ctx, "/dst/blob")
+	require.NoError(t, err)
+	require.Equal(t, contents, read)
+
+	_, err = d.Stat(ctx, "/src/blob")
+	require.ErrorAs(t, err, &storagedriver.PathNotFoundError{})
+}
+
+func TestFromParametersImpl(t *testing.T) {
+	base := map[string]interface{}{"region": "us-east-1", "bucket": testBucket}
+	with := func(params map[string]interface{}) map[string]interface{} {
+		result := map[string]interface{}{}
+		for k, v := range base {
+			result[k] = v
+		}
+		for k, v := range params {
+			result[k] = v
+		}
+		return result
+	}
+
+	tests := []struct {
+		params   map[string]interface{}
+		expected func(p *DriverParameters)
+		pass     bool
+	}{
+		{params: map[string]interface{}{"bucket": testBucket}, pass: false},
+		{params: map[string]interface{}{"region": "us-east-1"}, pass: false},
+		{
+			params: with(nil),
+			expected: func(p *DriverParameters) {
+				require.Equal(t, defaultChunkSize, p.ChunkSize)
+				require.Equal(t, int64(defaultMultipartCopyChunkSize), p.MultipartCopyChunkSize)
+				require.Equal(t, int64(defaultMultipartCopyThresholdSize), p.MultipartCopyThresholdSize)
+				require.Equal(t, int64(defaultMultipartCopyMaxConcurrency), p.MultipartCopyMaxConcurrency)
+				require.Equal(t, "STANDARD", p.StorageClass)
+				require.True(t, p.Secure)
+				require.True(t, p.V4Auth)
+				require.False(t, p.Redirect)
+			},
+			pass: true,
+		},
+		{
+			params: with(map[string]interface{}{"chunksize": "6291456", "redirect": "true", "storageclass": "none"}),
+			expected: func(p *DriverParameters) {
+				require.Equal(t, 6<<20, p.ChunkSize)
+				require.Equal(t, noStorageClass, p.StorageClass)
+				require.True(t, p.Redirect)
+			},
+			pass: true,
+		},
+		{params: with(map[string]interface{}{"chunksize": 1024}), pass: false},
+		{params: with(map[string]interface{}{"chunksize": "fail"}), pass: false},
+		{params: with(map[string]interface{}{"secure": "fail"}), pass: false},
+		{params: with(map[string]interface{}{"storageclass": "unknown"}), pass: false},
+		{params: with(map[string]interface{}{"multipartcopymaxconcurrency": 0}), pass: false},
+	}
+
+	for i, item := range tests {
+		params, err := fromParametersImpl(item.params)
+		if !item.pass {
+			require.Error(t, err, "test case %d", i)
+			continue
+		}
+		require.NoError(t, err, "test case %d", i)
+		item.expected(params)
+	}
+}
+
+// fakeS3 is an in-memory S3 compatible server in path style, it implements the
+// subset of the API used by the driver and ignores the authentication.
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string]*fakeObject
+	uploads map[string]*fakeUpload
+	nextID  int
+}
+
+type fakeObject struct {
+	data     []byte
+	etag     string
+	modified time.Time
+}
+
+type fakeUpload struct {
+	key       string
+	parts     map[int64]*fakeObject
+	initiated time.Time
+}
+
+func newFakeS3() *fakeS3 {
+	return &fakeS3{
+		objects: make(map[string]*fakeObject),
+		uploads: make(map[string]*fakeUpload),
+	}
+}
+
+func newFakeObject(data []byte) *fakeObject {
+	sum := md5.Sum(data)
+	return &fakeObject{data: data, etag: `"` + hex.EncodeToString(sum[:]) + `"`, modified: time.Now().UTC()}
+}
+
+func (s *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	query := r.URL.Query()
+
+	switch {
+	case r.Method == http.MethodGet && key == "" && query.Has("uploads"):
+		s.listUploads(w, query)
+	case r.Method == http.MethodGet && key == "":
+		s.listObjects(w, query)
+	case r.Method == http.MethodGet && query.Has("uploadId"):
+		s.listParts(w, key, query)
+	case r.Method == http.MethodGet || r.Method == http.MethodHead:
+		s.getObject(w, r, key)
+	case r.Method == http.MethodPut && query.Has("uploadId"):
+		s.uploadPart(w, r, key, query)
+	case r.Method == http.MethodPut:
+		s.putObject(w, r, key)
+	case r.Method == http.MethodPost && query.Has("delete"):
+		s.deleteObjects(w, r)
+	case r.Method == http.MethodPost && query.Has("uploads"):
+		s.nextID++
+		id := strconv.Itoa(s.nextID)
+		s.uploads[id] = &fakeUpload{key: key, parts: make(map[int64]*fakeObject), initiated: time.Now().UTC()}
+		writeXML(w, http.StatusOK, struct {
+			XMLName  xml.Name `xml:"InitiateMultipartUploadResult"`
+			Bucket   string
+			Key      string
+			UploadId string
+		}{Bucket: testBucket, Key: key, UploadId: id})
+	case r.Method == http.MethodPost && query.Has("uploadId"):
+		s.completeUpload(w, r, key, query.Get("uploadId"))
+	case r.Method == http.MethodDelete && query.Has("uploadId"):
+		delete(s.uploads, query.Get("uploadId"))
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodDelete:
+		delete(s.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusNotImplemented, "NotImplemented")
+	}
+}
+
+func (s *fakeS3) getObject(w http.ResponseWriter, r *http.Request, key string) {
+	obj, ok := s.objects[key]
+	if !ok {
+		writeError(w, http.StatusNotFound, "NoSuchKey")
+		return
+	}
+
+	w.Header().Set("ETag", obj.etag)
+	w.Header().Set("Last-Modified", obj.modified.Format(http.TimeFormat))
+	data := obj.data
+	status := http.StatusOK
+	if rng := r.Header.Get("Range"); rng != "" {
+		start, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(rng, "bytes="), "-"))
+		if err != nil || start >= len(data) {
+			writeError(w, http.StatusRequestedRangeNotSatisfiable, "InvalidRange")
+			return
+		}
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, len(data)-1, len(data)))
+		data = data[start:]
+		status = http.StatusPartialContent
+	}
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.WriteHeader(status)
+	if r.Method == http.MethodGet {
+		_, _ = w.Write(data)
+	}
+}
+
+func (s *fakeS3) putObject(w http.ResponseWriter, r *http.Request, key string) {
+	if r.Header.Get("x-amz-copy-source") != "" {
+		src, ok := s.copySource(w, r)
+		if !ok {
+			return
+		}
+		obj := newFakeObject(append([]byte(nil), src...))
+		s.objects[key] = obj
+		writeXML(w, http.StatusOK, struct {
+			XMLName      xml.Name `xml:"CopyObjectResult"`
+			ETag         string
+			LastModified string
+		}{ETag: obj.etag, LastModified: obj.modified.Format(xmlTime)})
+		return
+	}
+
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "IncompleteBody")
+		return
+	}
+	obj := newFakeObject(data)
+	s.objects[key] = obj
+	w.Header().Set("ETag", obj.etag)
+}
+
+func (s *fakeS3) uploadPart(w http.ResponseWriter, r *http.Request, key string, query url.Values) {
+	upload, ok := s.uploads[query.Get("uploadId")]
+	if !ok || upload.key != key {
+		writeError(w, http.StatusNotFound, "NoSuchUpload")
+		return
+	}
+	partNumber, _ := strconv.ParseInt(query.Get("partNumber"), 10, 64)
+
+	if r.Header.Get("x-amz-copy-source") != "" {
+		src, ok := s.copySource(w, r)
+		if !ok {
+			return
+		}
+		part := newFakeObject(append([]byte(nil), src...))
+		upload.parts[partNumber] = part
+		writeXML(w, http.StatusOK, struct {
+			XMLName      xml.Name `xml:"CopyPartResult"`
+			ETag         string
+			LastModified string
+		}{ETag: part.etag, LastModified: part.modified.Format(xmlTime)})
+		return
+	}
+
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "IncompleteBody")
+		return
+	}
+	part := newFakeObject(data)
+	upload.parts[partNumber] = part
+	w.Header().Set("ETag", part.etag)
+}
+
+// copySource returns the content of the object in the copy source header, limited by the copy source range.
+func (s *fakeS3) copySource(w http.ResponseWriter, r *http.Request) ([]byte, bool) {
+	source, _ := url.PathUnescape(r.Header.Get("x-amz-copy-source"))
+	_, key, _ := strings.Cut(strings.TrimPrefix(source, "/"), "/")
+	obj, ok := s.objects[key]
+	if !ok {
+		writeError(w, http.StatusNotFound, "NoSuchKey")
+		return nil, false
+	}
+
+	rng := r.Header.Get("x-amz-copy-source-range")
+	if rng == "" {
+		return obj.data, true
+	}
+	first, last, _ := strings.Cut(strings.TrimPrefix(rng, "bytes="), "-")
+	start, err1 := strconv.Atoi(first)
+	end, err2 := strconv.Atoi(last)
+	if err1 != nil || err2 != nil || start > end || end >= len(obj.data) {
+		writeError(w, http.StatusBadRequest, "InvalidArgument")
+		return nil, false
+	}
+	return obj.data[start : end+1], true
+}
+
+func (s *fakeS3) completeUpload(w http.ResponseWriter, r *http.Request, key, uploadID string) {
+	upload, ok := s.uploads[uploadID]
+	if !ok || upload.key != key {
+		writeError(w, http.StatusNotFound, "NoSuchUpload")
+		return
+	}
+
+	var body struct {
+		Parts []struct {
+			PartNumber int64
+			ETag       string
+		} `xml:"Part"`
+	}
+	if err := xml.NewDecoder(r.Body).Decode(&body); err != nil || len(body.Parts) == 0 {
+		writeError(w, http.StatusBadRequest, "MalformedXML")
+		return
+	}
+
+	var data []byte
+	for i, p := range body.Parts {
+		part, ok := upload.parts[p.PartNumber]
+		if !ok || part.etag != p.ETag {
+			writeError(w, http.StatusBadRequest, "InvalidPart")
+			return
+		}
+		if i < len(body.Parts)-1 && len(part.data) < minChunkSize {
+			writeError(w, http.StatusBadRequest, "EntityTooSmall")
+			return
+		}
+		data = append(data, part.data...)
+	}
+
+	delete(s.uploads, uploadID)
+	obj := newFakeObject(data)
+	s.objects[key] = obj
+	writeXML(w, http.StatusOK, struct {
+		XMLName xml.Name `xml:"CompleteMultipartUploadResult"`
+		Bucket  string
+		Key     string
+		ETag    string
+	}{Bucket: testBucket, Key: key, ETag: obj.etag})
+}
+
+func (s *fakeS3) deleteObjects(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Objects []struct {
+			Key string
+		} `xml:"Object"`
+	}
+	if err := xml.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "MalformedXML")
+		return
+	}
+
+	type deleted struct {
+		Key string
+	}
+	result := struct {
+		XMLName xml.Name  `xml:"DeleteResult"`
+		Deleted []deleted `xml:"Deleted"`
+	}{}
+	for _, o := range body.Objects {
+		delete(s.objects, o.Key)
+		result.Deleted = append(result.Deleted, deleted{Key: o.Key})
+	}
+	writeXML(w, http.StatusOK, result)
+}
+
+func (s *fakeS3) listObjects(w http.ResponseWriter, query url.Values) {
+	prefix, delimiter := query.Get("prefix"), query.Get("delimiter")
+	maxKeys := listMax
+	if v, err := strconv.Atoi(query.Get("max-keys")); err == nil && v > 0 {
+		maxKeys = v
+	}
+
+	keys := make([]string, 0)
+	for k := range s.objects {
+		if strings.HasPrefix(k, prefix) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	// the entries are either objects or common prefixes, in key order
+	type entry struct {
+		key      string
+		isPrefix bool
+	}
+	entries := make([]entry, 0, len(keys))
+	for _, k := range keys {
+		if delimiter != "" {
+			if i := strings.Index(k[len(prefix):], delimiter); i >= 0 {
+				p := k[:len(prefix)+i+len(delimiter)]
+				if len(entries) == 0 || entries[len(entries)-1].key != p {
+					entries = append(entries, entry{key: p, isPrefix: true})
+				}
+				continue
+			}
+		}
+		entries = append(entries, entry{key: k})
+	}
+
+	start, _ := strconv.Atoi(query.Get("continuation-token"))
+	end := min(start+maxKeys, len(entries))
+	start = min(start, end)
+
+	type content struct {
+		Key          string
+		LastModified string
+		ETag         string
+		Size         int
+		StorageClass string
+	}
+	type commonPrefix struct {
+		Prefix string
+	}
+	result := struct {
+		XMLName               xml.Name `xml:"ListBucketResult"`
+		Name                  string
+		Prefix                string
+		Delimiter             string
+		MaxKeys               int
+		KeyCount              int
+		IsTruncated           bool
+		NextContinuationToken string         `xml:",omitempty"`
+		Contents              []content      `xml:"Contents"`
+		CommonPrefixes        []commonPrefix `xml:"CommonPrefixes"`
+	}{Name: testBucket, Prefix: prefix, Delimiter: delimiter, MaxKeys: maxKeys, KeyCount: end - start}
+
+	for _, e := range entries[start:end] {
+		if e.isPrefix {
+			result.CommonPrefixes = append(result.CommonPrefixes, commonPrefix{Prefix: e.key})
+			continue
+		}
+		obj := s.objects[e.key]
+		result.Contents = append(result.Contents, content{
+			Key: e.key, LastModified: obj.modified.Format(xmlTime), ETag: obj.etag,
+			Size: len(obj.data), StorageClass: "STANDARD",
+		})
+	}
+	if end < len(entries) {
+		result.IsTruncated = true
+		result.NextContinuationToken = strconv.Itoa(end)
+	}
+	writeXML(w, http.StatusOK, result)
+}
+
+func (s *fakeS3) listUploads(w http.ResponseWriter, query url.Values) {
+	type upload struct {
+		Key       string
+		UploadId  string
+		Initiated string
+	}
+	result := struct {
+		XMLName     xml.Name `xml:"ListMultipartUploadsResult"`
+		Bucket      string
+		IsTruncated bool
+		Uploads     []upload `xml:"Upload"`
+	}{Bucket: testBucket}
+
+	for id, u := range s.uploads {
+		if strings.HasPrefix(u.key, query.Get("prefix")) {
+			result.Uploads = append(result.Uploads, upload{Key: u.key, UploadId: id, Initiated: u.initiated.Format(xmlTime)})
+		}
+	}
+	sort.Slice(result.Uploads, func(i, j int) bool {
+		return result.Uploads[i].Key < result.Uploads[j].Key
+	})
+	writeXML(w, http.StatusOK, result)
+}
+
+func (s *fakeS3) listParts(w http.ResponseWriter, key string, query url.Values) {
+	u, ok := s.uploads[query.Get("uploadId")]
+	if !ok || u.key != key {
+		writeError(w, http.StatusNotFound, "NoSuchUpload")
+		return
+	}
+
+	type part struct {
+		PartNumber   int64
+		LastModified string
+		ETag         string
+		Size         int
+	}
+	result := struct {
+		XMLName     xml.Name `xml:"ListPartsResult"`
+		Bucket      string
+		Key         string
+		UploadId    string
+		IsTruncated bool
+		Parts       []part `xml:"Part"`
+	}{Bucket: testBucket, Key: key, UploadId: query.Get("uploadId")}
+
+	for n, p := range u.parts {
+		result.Parts = append(result.Parts, part{
+			PartNumber: n, LastModified: p.modified.Format(xmlTime), ETag: p.etag, Size: len(p.data),
+		})
+	}
+	sort.Slice(result.Parts, func(i, j int) bool {
+		return result.Parts[i].PartNumber < result.Parts[j].PartNumber
+	})
+	writeXML(w, http.StatusOK, result)
+}
+
+func writeXML(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	_ = xml.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, code string) {
+	writeXML(w, status, struct {
+		XMLName xml.Name `xml:"Error"`
+		Code    string
+		Message string
+	}{Code: code, Message: code})
+}
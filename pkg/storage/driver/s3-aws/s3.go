@@ -0,0 +1,1114 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+// Package s3 provides a storagedriver.StorageDriver implementation to
+// store blobs in Amazon S3 cloud storage or the S3 compatible services.
+//
+// This package leverages the official aws client library for interfacing with
+// S3.
+//
+// Because S3 is a key, value store the Stat call does not support last modification
+// time for directories (directories are an abstraction for key, value stores)
+//
+// Keep in mind that S3 guarantees only read-after-write consistency for new
+// objects, but no read-after-update or list-after-write consistency.
+package s3
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	storagedriver "github.com/easysoft/gitfox/pkg/storage/driver"
+	"github.com/easysoft/gitfox/pkg/storage/driver/base"
+	"github.com/easysoft/gitfox/pkg/storage/driver/factory"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+const driverName = "s3aws"
+
+const (
+	// minChunkSize defines the minimum multipart upload chunk size
+	// S3 API requires multipart upload chunks to be at least 5MB
+	minChunkSize = 5 << 20
+
+	// maxChunkSize defines the maximum multipart upload chunk size allowed by S3.
+	maxChunkSize = 5 << 30
+
+	defaultChunkSize = 2 * minChunkSize
+
+	// defaultMultipartCopyChunkSize defines the default chunk size for all
+	// but the last Upload Part - Copy operation of a multipart copy.
+	// Empirically, 32 MB is optimal.
+	defaultMultipartCopyChunkSize = 32 << 20
+
+	// defaultMultipartCopyMaxConcurrency defines the default maximum number
+	// of concurrent Upload Part - Copy operations for a multipart copy.
+	defaultMultipartCopyMaxConcurrency = 100
+
+	// defaultMultipartCopyThresholdSize defines the default object size
+	// above which multipart copy will be used. (PUT Object - Copy is used
+	// for objects at or below this size.)  Empirically, 32 MB is optimal.
+	defaultMultipartCopyThresholdSize = 32 << 20
+
+	// listMax is the largest amount of objects you can request from S3 in a list call
+	listMax = 1000
+
+	// redirectExpiry is the lifetime of the pre-signed url for redirected requests
+	redirectExpiry = 20 * time.Minute
+
+	noStorageClass = "NONE"
+)
+
+// DriverParameters is a struct that encapsulates all of the driver parameters after all values have been set
+type DriverParameters struct {
+	AccessKey                   string
+	SecretKey                   string
+	Bucket                      string
+	Region                      string
+	RegionEndpoint              string
+	ForcePathStyle              bool
+	Encrypt                     bool
+	KeyID                       string
+	Secure                      bool
+	SkipVerify                  bool
+	V4Auth                      bool
+	ChunkSize                   int
+	MultipartCopyChunkSize      int64
+	MultipartCopyMaxConcurrency int64
+	MultipartCopyThresholdSize  int64
+	RootDirectory               string
+	StorageClass                string
+	UserAgent                   string
+	LogLevel                    aws.LogLevelType
+	UseDualStack                bool
+	Accelerate                  bool
+	Redirect                    bool
+}
+
+func init() {
+	factory.Register(driverName, &s3DriverFactory{})
+}
+
+// s3DriverFactory implements the factory.StorageDriverFactory interface
+type s3DriverFactory struct{}
+
+func (factory *s3DriverFactory) Create(ctx context.Context, parameters map[string]interface{}) (storagedriver.StorageDriver, error) {
+	return FromParameters(ctx, parameters)
+}
+
+type driver struct {
+	S3                          *s3.S3
+	Bucket                      string
+	ChunkSize                   int
+	Encrypt                     bool
+	KeyID                       string
+	MultipartCopyChunkSize      int64
+	MultipartCopyMaxConcurrency int64
+	MultipartCopyThresholdSize  int64
+	RootDirectory               string
+	StorageClass                string
+	Redirect                    bool
+
+	pool *sync.Pool
+}
+
+type baseEmbed struct {
+	base.Base
+}
+
+// Driver is a storagedriver.StorageDriver implementation backed by Amazon S3
+// Objects are stored at absolute keys in the provided bucket.
+type Driver struct {
+	baseEmbed
+}
+
+// FromParameters constructs a new Driver with a given parameters map
+// Required parameters:
+// - region
+// - bucket
+// Optional parameters:
+// - accesskey, secretkey
+// - regionendpoint, forcepathstyle, secure, skipverify, v4auth
+// - encrypt, keyid
+// - chunksize, multipartcopychunksize, multipartcopymaxconcurrency, multipartcopythresholdsize
+// - rootdirectory, storageclass, useragent, usedualstack, accelerate, loglevel
+// - redirect
+func FromParameters(ctx context.Context, parameters map[string]interface{}) (*Driver, error) {
+	params, err := fromParametersImpl(parameters)
+	if err != nil {
+		return nil, err
+	}
+	return New(ctx, *params)
+}
+
+func fromParametersImpl(parameters map[string]interface{}) (*DriverParameters, error) {
+	if parameters == nil {
+		parameters = make(map[string]interface{})
+	}
+
+	params := &DriverParameters{
+		AccessKey:      getString(parameters, "accesskey"),
+		SecretKey:      getString(parameters, "secretkey"),
+		Bucket:         getString(parameters, "bucket"),
+		Region:         getString(parameters, "region"),
+		RegionEndpoint: getString(parameters, "regionendpoint"),
+		KeyID:          getString(parameters, "keyid"),
+		RootDirectory:  getString(parameters, "rootdirectory"),
+		UserAgent:      getString(parameters, "useragent"),
+		StorageClass:   s3.StorageClassStandard,
+	}
+
+	if params.Region == "" {
+		return nil, errors.New("no region parameter provided")
+	}
+	if params.Bucket == "" {
+		return nil, errors.New("no bucket parameter provided")
+	}
+
+	var err error
+	boolParams := []struct {
+		name   string
+		target *bool
+		def    bool
+	}{
+		{"forcepathstyle", &params.ForcePathStyle, true},
+		{"encrypt", &params.Encrypt, false},
+		{"secure", &params.Secure, true},
+		{"skipverify", &params.SkipVerify, false},
+		{"v4auth", &params.V4Auth, true},
+		{"usedualstack", &params.UseDualStack, false},
+		{"accelerate", &params.Accelerate, false},
+		{"redirect", &params.Redirect, false},
+	}
+	for _, p := range boolParams {
+		if *p.target, err = getBool(parameters, p.name, p.def); err != nil {
+			return nil, err
+		}
+	}
+
+	chunkSize, err := getInt64(parameters, "chunksize", defaultChunkSize, minChunkSize, maxChunkSize)
+	if err != nil {
+		return nil, err
+	}
+	params.ChunkSize = int(chunkSize)
+
+	params.MultipartCopyChunkSize, err = getInt64(parameters, "multipartcopychunksize",
+		defaultMultipartCopyChunkSize, minChunkSize, maxChunkSize)
+	if err != nil {
+		return nil, err
+	}
+	params.MultipartCopyMaxConcurrency, err = getInt64(parameters, "multipartcopymaxconcurrency",
+		defaultMultipartCopyMaxConcurrency, 1, math.MaxInt64)
+	if err != nil {
+		return nil, err
+	}
+	params.MultipartCopyThresholdSize, err = getInt64(parameters, "multipartcopythresholdsize",
+		defaultMultipartCopyThresholdSize, 0, maxChunkSize)
+	if err != nil {
+		return nil, err
+	}
+
+	if class := getString(parameters, "storageclass"); class != "" {
+		class = strings.ToUpper(class)
+		if class != noStorageClass && !containsString(s3.StorageClass_Values(), class) {
+			return nil, fmt.Errorf("the storageclass parameter must be one of %v or %s, %s invalid",
+				s3.StorageClass_Values(), noStorageClass, class)
+		}
+		params.StorageClass = class
+	}
+
+	switch level := strings.ToLower(getString(parameters, "loglevel")); level {
+	case "debug":
+		params.LogLevel = aws.LogDebug
+	case "debugwithhttpbody":
+		params.LogLevel = aws.LogDebugWithHTTPBody
+	default:
+		params.LogLevel = aws.LogOff
+	}
+
+	return params, nil
+}
+
+// New constructs a new Driver with the given AWS credentials, region, encryption flag, and
+// bucketName
+func New(ctx context.Context, params DriverParameters) (*Driver, error) {
+	if !params.V4Auth && (params.RegionEndpoint == "" || strings.Contains(params.RegionEndpoint, "s3.amazonaws.com")) {
+		return nil, errors.New("on Amazon S3 this storage driver can only be used with v4 authentication")
+	}
+
+	awsConfig := aws.NewConfig().
+		WithRegion(params.Region).
+		WithS3ForcePathStyle(params.ForcePathStyle).
+		WithS3UseAccelerate(params.Accelerate).
+		WithUseDualStack(params.UseDualStack).
+		WithDisableSSL(!params.Secure).
+		WithLogLevel(params.LogLevel)
+
+	if params.RegionEndpoint != "" {
+		awsConfig.WithEndpoint(params.RegionEndpoint)
+	}
+	if params.AccessKey != "" || params.SecretKey != "" {
+		awsConfig.WithCredentials(credentials.NewStaticCredentials(params.AccessKey, params.SecretKey, ""))
+	}
+	if params.SkipVerify {
+		httpTransport := http.DefaultTransport.(*http.Transport).Clone()
+		httpTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true} //nolint:gosec
+		awsConfig.WithHTTPClient(&http.Client{Transport: httpTransport})
+	}
+
+	sess, err := session.NewSession(awsConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create new session with aws config: %w", err)
+	}
+
+	s3obj := s3.New(sess)
+	if params.UserAgent != "" {
+		s3obj.Handlers.Build.PushBack(request.MakeAddToUserAgentFreeFormHandler(params.UserAgent))
+	}
+
+	chunkSize := params.ChunkSize
+	d := &driver{
+		S3:                          s3obj,
+		Bucket:                      params.Bucket,
+		ChunkSize:                   chunkSize,
+		Encrypt:                     params.Encrypt,
+		KeyID:                       params.KeyID,
+		MultipartCopyChunkSize:      params.MultipartCopyChunkSize,
+		MultipartCopyMaxConcurrency: params.MultipartCopyMaxConcurrency,
+		MultipartCopyThresholdSize:  params.MultipartCopyThresholdSize,
+		RootDirectory:               params.RootDirectory,
+		StorageClass:                params.StorageClass,
+		Redirect:                    params.Redirect,
+		pool: &sync.Pool{
+			New: func() any { return bytes.NewBuffer(make([]byte, 0, chunkSize)) },
+		},
+	}
+
+	return &Driver{
+		baseEmbed: baseEmbed{
+			Base: base.Base{
+				StorageDriver: d,
+			},
+		},
+	}, nil
+}
+
+// Implement the storagedriver.StorageDriver interface
+
+func (d *driver) Name() string {
+	return driverName
+}
+
+// GetContent retrieves the content stored at "path" as a []byte.
+func (d *driver) GetContent(ctx context.Context, path string) ([]byte, error) {
+	reader, err := d.Reader(ctx, path, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	return io.ReadAll(reader)
+}
+
+// PutContent stores the []byte content at a location designated by "path".
+func (d *driver) PutContent(ctx context.Context, path string, contents []byte) error {
+	_, err := d.S3.PutObjectWithContext(ctx, &s3.PutObjectInput{
+		Bucket:               aws.String(d.Bucket),
+		Key:                  aws.String(d.s3Path(path)),
+		ContentType:          d.getContentType(),
+		ACL:                  d.getACL(),
+		ServerSideEncryption: d.getEncryptionMode(),
+		SSEKMSKeyId:          d.getSSEKMSKeyID(),
+		StorageClass:         d.getStorageClass(),
+		Body:                 bytes.NewReader(contents),
+	})
+	return parseError(path, err)
+}
+
+// Reader retrieves an io.ReadCloser for the content stored at "path" with a
+// given byte offset.
+func (d *driver) Reader(ctx context.Context, path string, offset int64) (io.ReadCloser, error) {
+	resp, err := d.S3.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(d.Bucket),
+		Key:    aws.String(d.s3Path(path)),
+		Range:  aws.String("bytes=" + strconv.FormatInt(offset, 10) + "-"),
+	})
+	if err != nil {
+		// the offset is out of the content, including reading an empty object
+		if s3Err, ok := err.(awserr.Error); ok && s3Err.Code() == "InvalidRange" {
+			return io.NopCloser(bytes.NewReader(nil)), nil
+		}
+		return nil, parseError(path, err)
+	}
+	return resp.Body, nil
+}
+
+// Writer returns a FileWriter which will store the content written to it
+// at the location designated by "path" after the call to Commit.
+// It only allows appending to paths with zero size committed content,
+// in which the existing content is overridden with the new content.
+// It returns storagedriver.Error when appending to paths with non-zero
+// committed content.
+func (d *driver) Writer(ctx context.Context, path string, appendMode bool) (storagedriver.FileWriter, error) {
+	key := d.s3Path(path)
+	if !appendMode {
+		uploadID, err := d.createMultipartUpload(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		return d.newWriter(ctx, key, uploadID, nil), nil
+	}
+
+	listMultipartUploadsInput := &s3.ListMultipartUploadsInput{
+		Bucket: aws.String(d.Bucket),
+		Prefix: aws.String(key),
+	}
+	for {
+		resp, err := d.S3.ListMultipartUploadsWithContext(ctx, listMultipartUploadsInput)
+		if err != nil {
+			return nil, parseError(path, err)
+		}
+
+		// resp.Uploads can only be empty on the first call, the content committed
+		// with zero size is appended with a new upload
+		if len(resp.Uploads) == 0 {
+			fi, err := d.Stat(ctx, path)
+			if err != nil {
+				return nil, parseError(path, err)
+			}
+			if fi.Size() == 0 {
+				uploadID, err := d.createMultipartUpload(ctx, key)
+				if err != nil {
+					return nil, err
+				}
+				return d.newWriter(ctx, key, uploadID, nil), nil
+			}
+			return nil, storagedriver.Error{
+				DriverName: driverName,
+				Detail:     fmt.Errorf("append to non-zero committed content is not supported: %s", path),
+			}
+		}
+
+		for _, multi := range resp.Uploads {
+			if key != aws.StringValue(multi.Key) {
+				continue
+			}
+
+			parts, err := d.listParts(ctx, key, aws.StringValue(multi.UploadId))
+			if err != nil {
+				return nil, parseError(path, err)
+			}
+			return d.newWriter(ctx, key, aws.StringValue(multi.UploadId), parts), nil
+		}
+
+		if !aws.BoolValue(resp.IsTruncated) {
+			break
+		}
+		listMultipartUploadsInput.KeyMarker = resp.NextKeyMarker
+		listMultipartUploadsInput.UploadIdMarker = resp.NextUploadIdMarker
+	}
+	return nil, storagedriver.PathNotFoundError{Path: path}
+}
+
+// Stat retrieves the FileInfo for the given path, including the current size
+// in bytes and the creation time.
+func (d *driver) Stat(ctx context.Context, path string) (storagedriver.FileInfo, error) {
+	key := d.s3Path(path)
+	fi := storagedriver.FileInfoFields{
+		Path: path,
+	}
+
+	if key != "" {
+		resp, err := d.S3.HeadObjectWithContext(ctx, &s3.HeadObjectInput{
+			Bucket: aws.String(d.Bucket),
+			Key:    aws.String(key),
+		})
+		if err == nil {
+			fi.Size = aws.Int64Value(resp.ContentLength)
+			fi.ModTime = aws.TimeValue(resp.LastModified)
+			return storagedriver.FileInfoInternal{FileInfoFields: fi}, nil
+		}
+		if _, ok := parseError(path, err).(storagedriver.PathNotFoundError); !ok {
+			return nil, err
+		}
+		key += "/"
+	}
+
+	// the path is a directory if any object is stored under it
+	resp, err := d.S3.ListObjectsV2WithContext(ctx, &s3.ListObjectsV2Input{
+		Bucket:  aws.String(d.Bucket),
+		Prefix:  aws.String(key),
+		MaxKeys: aws.Int64(1),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Contents) == 0 {
+		return nil, storagedriver.PathNotFoundError{Path: path}
+	}
+
+	fi.IsDir = true
+	return storagedriver.FileInfoInternal{FileInfoFields: fi}, nil
+}
+
+// List returns a list of the objects that are direct descendants of the given path.
+func (d *driver) List(ctx context.Context, opath string) ([]string, error) {
+	path := opath
+	if path != "/" && path[len(path)-1] != '/' {
+		path += "/"
+	}
+
+	// This is to cover for the cases when the rootDirectory of the driver is either "" or "/".
+	// In those cases, there is no root prefix to replace and we must actually add a "/" to all
+	// results in order to keep them as valid paths as recognized by storagedriver.PathRegexp
+	prefix := ""
+	if d.s3Path("") == "" {
+		prefix = "/"
+	}
+
+	listObjectsInput := &s3.ListObjectsV2Input{
+		Bucket:    aws.String(d.Bucket),
+		Prefix:    aws.String(d.s3Path(path)),
+		Delimiter: aws.String("/"),
+		MaxKeys:   aws.Int64(listMax),
+	}
+
+	files := make([]string, 0)
+	directories := make([]string, 0)
+	for {
+		resp, err := d.S3.ListObjectsV2WithContext(ctx, listObjectsInput)
+		if err != nil {
+			return nil, parseError(opath, err)
+		}
+
+		for _, key := range resp.Contents {
+			files = append(files, strings.Replace(aws.StringValue(key.Key), d.s3Path(""), prefix, 1))
+		}
+		for _, commonPrefix := range resp.CommonPrefixes {
+			p := aws.StringValue(commonPrefix.Prefix)
+			directories = append(directories, strings.Replace(p[0:len(p)-1], d.s3Path(""), prefix, 1))
+		}
+
+		if !aws.BoolValue(resp.IsTruncated) {
+			break
+		}
+		listObjectsInput.ContinuationToken = resp.NextContinuationToken
+	}
+
+	if opath != "/" && len(files) == 0 && len(directories) == 0 {
+		// Treat empty response as missing directory, since we don't actually
+		// have directories in s3.
+		return nil, storagedriver.PathNotFoundError{Path: opath}
+	}
+	return append(files, directories...), nil
+}
+
+// Move moves an object stored at sourcePath to destPath, removing the original
+// object.
+func (d *driver) Move(ctx context.Context, sourcePath string, destPath string) error {
+	if err := d.copy(ctx, sourcePath, destPath); err != nil {
+		return err
+	}
+	return d.Delete(ctx, sourcePath)
+}
+
+// copy copies an object stored at sourcePath to destPath.
+func (d *driver) copy(ctx context.Context, sourcePath string, destPath string) error {
+	// S3 can copy objects up to 5 GB in size with a single PUT Object - Copy
+	// operation. For larger objects, the multipart upload API must be used.
+	//
+	// Empirically, multipart copy is fastest with 32 MB parts and is faster
+	// than PUT Object - Copy for objects larger than 32 MB.
+	fileInfo, err := d.Stat(ctx, sourcePath)
+	if err != nil {
+		return parseError(sourcePath, err)
+	}
+	if fileInfo.IsDir() {
+		return storagedriver.PathNotFoundError{Path: sourcePath}
+	}
+
+	copySource := aws.String(d.Bucket + "/" + d.s3Path(sourcePath))
+	if fileInfo.Size() <= d.MultipartCopyThresholdSize {
+		_, err = d.S3.CopyObjectWithContext(ctx, &s3.CopyObjectInput{
+			Bucket:               aws.String(d.Bucket),
+			Key:                  aws.String(d.s3Path(destPath)),
+			ContentType:          d.getContentType(),
+			ACL:                  d.getACL(),
+			ServerSideEncryption: d.getEncryptionMode(),
+			SSEKMSKeyId:          d.getSSEKMSKeyID(),
+			StorageClass:         d.getStorageClass(),
+			CopySource:           copySource,
+		})
+		return parseError(sourcePath, err)
+	}
+
+	destKey := d.s3Path(destPath)
+	uploadID, err := d.createMultipartUpload(ctx, destKey)
+	if err != nil {
+		return err
+	}
+
+	numParts := (fileInfo.Size() + d.MultipartCopyChunkSize - 1) / d.MultipartCopyChunkSize
+	completedParts := make([]*s3.CompletedPart, numParts)
+	errChan := make(chan error, numParts)
+	limiter := make(chan struct{}, d.MultipartCopyMaxConcurrency)
+
+	for i := range completedParts {
+		i := int64(i)
+		go func() {
+			limiter <- struct{}{}
+			defer func() { <-limiter }()
+
+			firstByte := i * d.MultipartCopyChunkSize
+			lastByte := min(firstByte+d.MultipartCopyChunkSize-1, fileInfo.Size()-1)
+			resp, err := d.S3.UploadPartCopyWithContext(ctx, &s3.UploadPartCopyInput{
+				Bucket:          aws.String(d.Bucket),
+				CopySource:      copySource,
+				Key:             aws.String(destKey),
+				PartNumber:      aws.Int64(i + 1),
+				UploadId:        aws.String(uploadID),
+				CopySourceRange: aws.String(fmt.Sprintf("bytes=%d-%d", firstByte, lastByte)),
+			})
+			if err == nil {
+				completedParts[i] = &s3.CompletedPart{
+					ETag:       resp.CopyPartResult.ETag,
+					PartNumber: aws.Int64(i + 1),
+				}
+			}
+			errChan <- err
+		}()
+	}
+
+	for range completedParts {
+		if err := <-errChan; err != nil {
+			return errors.Join(err, d.abortMultipartUpload(ctx, destKey, uploadID))
+		}
+	}
+
+	_, err = d.S3.CompleteMultipartUploadWithContext(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(d.Bucket),
+		Key:             aws.String(destKey),
+		UploadId:        aws.String(uploadID),
+		MultipartUpload: &s3.CompletedMultipartUpload{Parts: completedParts},
+	})
+	return err
+}
+
+// Delete recursively deletes all objects stored at "path" and its subpaths.
+// We must be careful since S3 does not guarantee read after delete consistency
+func (d *driver) Delete(ctx context.Context, path string) error {
+	s3Objects := make([]*s3.ObjectIdentifier, 0, listMax)
+	s3Path := d.s3Path(path)
+	listObjectsInput := &s3.ListObjectsV2Input{
+		Bucket: aws.String(d.Bucket),
+		Prefix: aws.String(s3Path),
+	}
+
+	found := false
+	for {
+		resp, err := d.S3.ListObjectsV2WithContext(ctx, listObjectsInput)
+		if err != nil {
+			return parseError(path, err)
+		}
+
+		for _, key := range resp.Contents {
+			k := aws.StringValue(key.Key)
+			// Skip if we encounter a key that is not a subpath (so that deleting "/a" does not delete "/ab").
+			if len(k) > len(s3Path) && k[len(s3Path)] != '/' {
+				continue
+			}
+			s3Objects = append(s3Objects, &s3.ObjectIdentifier{Key: key.Key})
+		}
+
+		// Delete objects only if the list is not empty, otherwise S3 API returns a cryptic error
+		if len(s3Objects) > 0 {
+			found = true
+			resp, err := d.S3.DeleteObjectsWithContext(ctx, &s3.DeleteObjectsInput{
+				Bucket: aws.String(d.Bucket),
+				Delete: &s3.Delete{
+					Objects: s3Objects,
+					Quiet:   aws.Bool(false),
+				},
+			})
+			if err != nil {
+				return err
+			}
+			if len(resp.Errors) > 0 {
+				errs := storagedriver.Errors{DriverName: driverName}
+				for _, e := range resp.Errors {
+					errs.Errs = append(errs.Errs, errors.New(e.String()))
+				}
+				return errs
+			}
+		}
+		// NOTE: we don't want to reallocate the slice so we simply "reset" it
+		s3Objects = s3Objects[:0]
+
+		if !aws.BoolValue(resp.IsTruncated) {
+			break
+		}
+		listObjectsInput.ContinuationToken = resp.NextContinuationToken
+	}
+
+	if !found {
+		return storagedriver.PathNotFoundError{Path: path}
+	}
+	return nil
+}
+
+// RedirectURL returns a pre-signed URL for the content stored at path if the redirect is enabled,
+// only GET and HEAD requests are redirected.
+func (d *driver) RedirectURL(r *http.Request, path string) (string, error) {
+	if !d.Redirect {
+		return "", nil
+	}
+
+	var req *request.Request
+	switch r.Method {
+	case http.MethodGet:
+		req, _ = d.S3.GetObjectRequest(&s3.GetObjectInput{
+			Bucket: aws.String(d.Bucket),
+			Key:    aws.String(d.s3Path(path)),
+		})
+	case http.MethodHead:
+		req, _ = d.S3.HeadObjectRequest(&s3.HeadObjectInput{
+			Bucket: aws.String(d.Bucket),
+			Key:    aws.String(d.s3Path(path)),
+		})
+	default:
+		return "", nil
+	}
+	return req.Presign(redirectExpiry)
+}
+
+// Walk traverses a filesystem defined within driver, starting
+// from the given path, calling f on each file
+func (d *driver) Walk(ctx context.Context, path string, f storagedriver.WalkFn, options ...func(*storagedriver.WalkOptions)) error {
+	return storagedriver.WalkFallback(ctx, d, path, f, options...)
+}
+
+func (d *driver) s3Path(path string) string {
+	return strings.TrimLeft(strings.TrimRight(d.RootDirectory, "/")+path, "/")
+}
+
+func (d *driver) createMultipartUpload(ctx context.Context, key string) (string, error) {
+	resp, err := d.S3.CreateMultipartUploadWithContext(ctx, &s3.CreateMultipartUploadInput{
+		Bucket:               aws.String(d.Bucket),
+		Key:                  aws.String(key),
+		ContentType:          d.getContentType(),
+		ACL:                  d.getACL(),
+		ServerSideEncryption: d.getEncryptionMode(),
+		SSEKMSKeyId:          d.getSSEKMSKeyID(),
+		StorageClass:         d.getStorageClass(),
+	})
+	if err != nil {
+		return "", err
+	}
+	return aws.StringValue(resp.UploadId), nil
+}
+
+func (d *driver) abortMultipartUpload(ctx context.Context, key, uploadID string) error {
+	_, err := d.S3.AbortMultipartUploadWithContext(ctx, &s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(d.Bucket),
+		Key:      aws.String(key),
+		UploadId: aws.String(uploadID),
+	})
+	return err
+}
+
+func (d *driver) listParts(ctx context.Context, key, uploadID string) ([]*s3.Part, error) {
+	input := &s3.ListPartsInput{
+		Bucket:   aws.String(d.Bucket),
+		Key:      aws.String(key),
+		UploadId: aws.String(uploadID),
+	}
+
+	parts := make([]*s3.Part, 0)
+	for {
+		resp, err := d.S3.ListPartsWithContext(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, resp.Parts...)
+		if !aws.BoolValue(resp.IsTruncated) {
+			return parts, nil
+		}
+		input.PartNumberMarker = resp.NextPartNumberMarker
+	}
+}
+
+func (d *driver) getEncryptionMode() *string {
+	if !d.Encrypt {
+		return nil
+	}
+	if d.KeyID == "" {
+		return aws.String(s3.ServerSideEncryptionAes256)
+	}
+	return aws.String(s3.ServerSideEncryptionAwsKms)
+}
+
+func (d *driver) getSSEKMSKeyID() *string {
+	if d.KeyID != "" {
+		return aws.String(d.KeyID)
+	}
+	return nil
+}
+
+func (d *driver) getContentType() *string {
+	return aws.String("application/octet-stream")
+}
+
+func (d *driver) getACL() *string {
+	return aws.String(s3.ObjectCannedACLPrivate)
+}
+
+func (d *driver) getStorageClass() *string {
+	if d.StorageClass == noStorageClass {
+		return nil
+	}
+	return aws.String(d.StorageClass)
+}
+
+func parseError(path string, err error) error {
+	if s3Err, ok := err.(awserr.Error); ok && (s3Err.Code() == s3.ErrCodeNoSuchKey || s3Err.Code() == "NotFound") {
+		return storagedriver.PathNotFoundError{Path: path}
+	}
+	return err
+}
+
+// writer attempts to upload parts to S3 in a buffered fashion where the last
+// part is at least as large as the chunksize, so the multipart upload could be
+// cleanly resumed in the future. This is violated if Close is called after less
+// than a full chunk is written.
+type writer struct {
+	ctx       context.Context
+	driver    *driver
+	key       string
+	uploadID  string
+	parts     []*s3.Part
+	size      int64
+	buf       *bytes.Buffer
+	closed    bool
+	committed bool
+	cancelled bool
+}
+
+func (d *driver) newWriter(ctx context.Context, key, uploadID string, parts []*s3.Part) storagedriver.FileWriter {
+	var size int64
+	for _, part := range parts {
+		size += aws.Int64Value(part.Size)
+	}
+	return &writer{
+		ctx:      ctx,
+		driver:   d,
+		key:      key,
+		uploadID: uploadID,
+		parts:    parts,
+		size:     size,
+		buf:      d.pool.Get().(*bytes.Buffer),
+	}
+}
+
+type completedParts []*s3.CompletedPart
+
+func (a completedParts) Len() int           { return len(a) }
+func (a completedParts) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+func (a completedParts) Less(i, j int) bool { return *a[i].PartNumber < *a[j].PartNumber }
+
+func (w *writer) Write(p []byte) (int, error) {
+	if err := w.checkWritable(); err != nil {
+		return 0, err
+	}
+
+	// If the last written part is smaller than minChunkSize, we need to make a
+	// new multipart upload :sadface:
+	if len(w.parts) > 0 && aws.Int64Value(w.parts[len(w.parts)-1].Size) < minChunkSize {
+		if err := w.restartUpload(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, _ := w.buf.Write(p)
+
+	for w.buf.Len() >= w.driver.ChunkSize {
+		if err := w.flush(); err != nil {
+			return 0, fmt.Errorf("flush: %w", err)
+		}
+	}
+	return n, nil
+}
+
+// restartUpload completes the current upload and starts a new one with the
+// completed content, since only the last part of an upload may be smaller than minChunkSize.
+func (w *writer) restartUpload() error {
+	if err := w.complete(); err != nil {
+		return err
+	}
+
+	uploadID, err := w.driver.createMultipartUpload(w.ctx, w.key)
+	if err != nil {
+		return err
+	}
+	w.uploadID = uploadID
+
+	// If the entire written file is smaller than minChunkSize, we need to make
+	// a new part from scratch :double sad face:
+	if w.size < minChunkSize {
+		resp, err := w.driver.S3.GetObjectWithContext(w.ctx, &s3.GetObjectInput{
+			Bucket: aws.String(w.driver.Bucket),
+			Key:    aws.String(w.key),
+		})
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		// the content is uploaded again with the next part
+		w.parts = nil
+		w.size = 0
+		w.buf.Reset()
+		_, err = io.Copy(w.buf, resp.Body)
+		return err
+	}
+
+	// Otherwise we can use the old file as the new first part
+	copyPartResp, err := w.driver.S3.UploadPartCopyWithContext(w.ctx, &s3.UploadPartCopyInput{
+		Bucket:     aws.String(w.driver.Bucket),
+		CopySource: aws.String(w.driver.Bucket + "/" + w.key),
+		Key:        aws.String(w.key),
+		PartNumber: aws.Int64(1),
+		UploadId:   aws.String(w.uploadID),
+	})
+	if err != nil {
+		return err
+	}
+	w.parts = []*s3.Part{{
+		ETag:       copyPartResp.CopyPartResult.ETag,
+		PartNumber: aws.Int64(1),
+		Size:       aws.Int64(w.size),
+	}}
+	return nil
+}
+
+func (w *writer) Size() int64 {
+	return w.size
+}
+
+func (w *writer) Close() error {
+	if w.closed {
+		return fmt.Errorf("already closed")
+	}
+	w.closed = true
+	defer w.releaseBuffer()
+
+	return w.flush()
+}
+
+func (w *writer) Cancel(ctx context.Context) error {
+	if w.closed {
+		return fmt.Errorf("already closed")
+	} else if w.committed {
+		return fmt.Errorf("already committed")
+	}
+	w.cancelled = true
+	defer w.releaseBuffer()
+
+	return w.driver.abortMultipartUpload(ctx, w.key, w.uploadID)
+}
+
+func (w *writer) Commit(ctx context.Context) error {
+	if err := w.checkWritable(); err != nil {
+		return err
+	}
+	if err := w.flush(); err != nil {
+		return err
+	}
+	w.committed = true
+
+	// An upload can not be completed without any part, the empty content is
+	// uploaded as a zero byte part.
+	if len(w.parts) == 0 {
+		resp, err := w.driver.S3.UploadPartWithContext(ctx, &s3.UploadPartInput{
+			Bucket:     aws.String(w.driver.Bucket),
+			Key:        aws.String(w.key),
+			PartNumber: aws.Int64(1),
+			UploadId:   aws.String(w.uploadID),
+			Body:       bytes.NewReader(nil),
+		})
+		if err != nil {
+			return err
+		}
+		w.parts = append(w.parts, &s3.Part{ETag: resp.ETag, PartNumber: aws.Int64(1), Size: aws.Int64(0)})
+	}
+	return w.complete()
+}
+
+// complete completes the multipart upload with the uploaded parts, the upload is aborted on failure.
+func (w *writer) complete() error {
+	uploadedParts := make(completedParts, len(w.parts))
+	for i, part := range w.parts {
+		uploadedParts[i] = &s3.CompletedPart{ETag: part.ETag, PartNumber: part.PartNumber}
+	}
+	sort.Sort(uploadedParts)
+
+	_, err := w.driver.S3.CompleteMultipartUploadWithContext(w.ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(w.driver.Bucket),
+		Key:             aws.String(w.key),
+		UploadId:        aws.String(w.uploadID),
+		MultipartUpload: &s3.CompletedMultipartUpload{Parts: uploadedParts},
+	})
+	if err != nil {
+		return errors.Join(err, w.driver.abortMultipartUpload(w.ctx, w.key, w.uploadID))
+	}
+	return nil
+}
+
+// flush writes at most [w.driver.ChunkSize] of the buffer to the remote S3 multipart upload
+func (w *writer) flush() error {
+	if w.buf.Len() == 0 {
+		return nil
+	}
+
+	r := bytes.NewReader(w.buf.Next(w.driver.ChunkSize))
+	partSize := r.Len()
+	partNumber := aws.Int64(int64(len(w.parts) + 1))
+
+	resp, err := w.driver.S3.UploadPartWithContext(w.ctx, &s3.UploadPartInput{
+		Bucket:     aws.String(w.driver.Bucket),
+		Key:        aws.String(w.key),
+		PartNumber: partNumber,
+		UploadId:   aws.String(w.uploadID),
+		Body:       r,
+	})
+	if err != nil {
+		return err
+	}
+
+	w.parts = append(w.parts, &s3.Part{
+		ETag:       resp.ETag,
+		PartNumber: partNumber,
+		Size:       aws.Int64(int64(partSize)),
+	})
+	w.size += int64(partSize)
+	return nil
+}
+
+func (w *writer) checkWritable() error {
+	if w.closed {
+		return fmt.Errorf("already closed")
+	} else if w.committed {
+		return fmt.Errorf("already committed")
+	} else if w.cancelled {
+		return fmt.Errorf("already cancelled")
+	}
+	return nil
+}
+
+func (w *writer) releaseBuffer() {
+	if w.buf == nil {
+		return
+	}
+	w.buf.Reset()
+	w.driver.pool.Put(w.buf)
+	w.buf = nil
+}
+
+func getString(parameters map[string]interface{}, name string) string {
+	v, ok := parameters[name]
+	if !ok || v == nil {
+		return ""
+	}
+	return fmt.Sprint(v)
+}
+
+func getBool(parameters map[string]interface{}, name string, def bool) (bool, error) {
+	switch v := parameters[name].(type) {
+	case nil:
+		return def, nil
+	case bool:
+		return v, nil
+	case string:
+		if v == "" {
+			return def, nil
+		}
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return false, fmt.Errorf("the %s parameter should be a boolean", name)
+		}
+		return b, nil
+	default:
+		return false, fmt.Errorf("the %s parameter should be a boolean", name)
+	}
+}
+
+func getInt64(parameters map[string]interface{}, name string, def, minimum, maximum int64) (int64, error) {
+	var rv int64
+	switch v := parameters[name].(type) {
+	case nil:
+		return def, nil
+	case string:
+		if v == "" {
+			return def, nil
+		}
+		var err error
+		if rv, err = strconv.ParseInt(v, 0, 64); err != nil {
+			return 0, fmt.Errorf("%s parameter must be an integer, %v invalid", name, v)
+		}
+	case int64:
+		rv = v
+	case int, uint, int32, uint32, uint64:
+		rv = reflectInt(v)
+	default:
+		return 0, fmt.Errorf("invalid value for %s: %#v", name, v)
+	}
+
+	if rv < minimum || rv > maximum {
+		return 0, fmt.Errorf("the %s %#v parameter should be a number between %d and %d (inclusive)", name, rv, minimum, maximum)
+	}
+	return rv, nil
+}
+
+func reflectInt(v interface{}) int64 {
+	switch n := v.(type) {
+	case int:
+		return int64(n)
+	case uint:
+		return int64(n) //nolint:gosec
+	case int32:
+		return int64(n)
+	case uint32:
+		return int64(n)
+	case uint64:
+		return int64(n) //nolint:gosec
+	}
+	return 0
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
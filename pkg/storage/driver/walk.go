@@ -66,7 +66,10 @@ func WalkFallback(ctx context.Context, driver StorageDriver, from string, f Walk
 			case PathNotFoundError:
 				// dir doesn't exist, so nothing to walk
 			default:
-				return err
+				// the hint may be a file, which has nothing to walk
+				if base != startAfterHint || !isFile(ctx, driver, base) {
+					return err
+				}
 			}
 			if base == from {
 				break
@@ -82,6 +85,11 @@ func WalkFallback(ctx context.Context, driver StorageDriver, from string, f Walk
 	return nil
 }
 
+func isFile(ctx context.Context, driver StorageDriver, path string) bool {
+	fi, err := driver.Stat(ctx, path)
+	return err == nil && !fi.IsDir()
+}
+
 // doWalkFallback performs a depth first walk using recursion.
 // from is the directory that this iteration of the function should walk.
 // startAfterHint is the child within from to start the walk after. It should only ever be a child of from, or the empty string.
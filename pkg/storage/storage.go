@@ -14,6 +14,7 @@ import (
 
 	_ "github.com/easysoft/gitfox/pkg/storage/driver/filesystem"
 	_ "github.com/easysoft/gitfox/pkg/storage/driver/inmemory"
+	_ "github.com/easysoft/gitfox/pkg/storage/driver/s3-aws"
 )
 
 func NewDriver(ctx context.Context, name string, parameters map[string]interface{}) (driver.StorageDriver, error) {
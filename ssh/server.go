@@ -19,6 +19,7 @@ import (
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
+	"encoding/json"
 	"encoding/pem"
 	"fmt"
 	"io"
@@ -30,6 +31,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/easysoft/gitfox/app/api/controller/lfs"
 	"github.com/easysoft/gitfox/app/api/controller/repo"
 	"github.com/easysoft/gitfox/app/auth"
 	"github.com/easysoft/gitfox/app/services/publickey"
@@ -49,10 +51,14 @@ type contextKey string
 
 const principalKey = contextKey("principalKey")
 
+// lfsAuthenticateCommand is run by the git lfs client to get the credentials of the lfs api.
+const lfsAuthenticateCommand = "git-lfs-authenticate"
+
 var (
 	allowedCommands = []string{
 		"git-upload-pack",
 		"git-receive-pack",
+		lfsAuthenticateCommand,
 	}
 	defaultCiphers = []string{
 		"chacha20-poly1305@openssh.com",
@@ -99,6 +105,7 @@ type Server struct {
 
 	Verifier publickey.Service
 	RepoCtrl *repo.Controller
+	LFSCtrl  *lfs.Controller
 }
 
 func (s *Server) sanitize() error {
@@ -225,6 +232,11 @@ func (s *Server) sessionHandler(session ssh.Session) {
 		return
 	}
 
+	if gitCommand == lfsAuthenticateCommand {
+		s.lfsAuthenticate(session, principal, parts[1:])
+		return
+	}
+
 	gitServicePack := strings.TrimPrefix(gitCommand, "git-")
 	service, err := enum.ParseGitServiceType(gitServicePack)
 	if err != nil {
@@ -258,17 +270,7 @@ func (s *Server) sessionHandler(session ssh.Session) {
 
 	err = s.RepoCtrl.GitServicePack(
 		ctxssh.WithContext(ctx, session),
-		&auth.Session{
-			Principal: types.Principal{
-				ID:          principal.ID,
-				UID:         principal.UID,
-				Email:       principal.Email,
-				Type:        principal.Type,
-				DisplayName: principal.DisplayName,
-				Created:     principal.Created,
-				Updated:     principal.Updated,
-			},
-		},
+		newSession(principal),
 		repoRef,
 		api.ServicePackOptions{
 			Service:  service,
@@ -287,6 +289,48 @@ func (s *Server) sessionHandler(session ssh.Session) {
 	}
 }
 
+// lfsAuthenticate writes the credentials of the lfs api for the repository,
+// the args are the repository path and the operation: 'upload' or 'download'.
+func (s *Server) lfsAuthenticate(session ssh.Session, principal *types.PrincipalInfo, args []string) {
+	if len(args) < 2 {
+		_, _ = fmt.Fprintf(session.Stderr(), "usage: %s <repository> <upload|download>\n", lfsAuthenticateCommand)
+		_ = session.Exit(1)
+		return
+	}
+	repoRef := strings.TrimSuffix(strings.Trim(args[0], "'"), ".git")
+
+	resp, err := s.LFSCtrl.Authenticate(
+		ctxssh.WithContext(session.Context(), session),
+		newSession(principal),
+		repoRef,
+		args[1],
+	)
+	if err != nil {
+		log.Error().Err(err).Msg("git lfs authenticate failed")
+		_, _ = fmt.Fprintf(session.Stderr(), "%s\n", err)
+		_ = session.Exit(1)
+		return
+	}
+
+	if err = json.NewEncoder(session).Encode(resp); err != nil {
+		log.Error().Err(err).Msg("error writing lfs credentials to session")
+	}
+}
+
+func newSession(principal *types.PrincipalInfo) *auth.Session {
+	return &auth.Session{
+		Principal: types.Principal{
+			ID:          principal.ID,
+			UID:         principal.UID,
+			Email:       principal.Email,
+			Type:        principal.Type,
+			DisplayName: principal.DisplayName,
+			Created:     principal.Created,
+			Updated:     principal.Updated,
+		},
+	}
+}
+
 func sendKeepAliveMsg(ctx context.Context, session ssh.Session, interval time.Duration) {
 	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
@@ -15,6 +15,7 @@
 package ssh
 
 import (
+	"github.com/easysoft/gitfox/app/api/controller/lfs"
 	"github.com/easysoft/gitfox/app/api/controller/repo"
 	"github.com/easysoft/gitfox/app/services/publickey"
 	"github.com/easysoft/gitfox/types"
@@ -30,6 +31,7 @@ func ProvideServer(
 	config *types.Config,
 	vierifier publickey.Service,
 	repoctrl *repo.Controller,
+	lfsctrl *lfs.Controller,
 ) *Server {
 	return &Server{
 		Host:                    config.SSH.Host,
@@ -45,5 +47,6 @@ func ProvideServer(
 		ServerHostKeysDir:       config.SSH.ServerHostKeysDir,
 		Verifier:                vierifier,
 		RepoCtrl:                repoctrl,
+		LFSCtrl:                 lfsctrl,
 	}
 }
@@ -10,6 +10,7 @@ import (
 	"errors"
 	"fmt"
 	"path/filepath"
+	"strings"
 
 	"github.com/easysoft/gitfox/types/enum"
 
@@ -26,9 +27,10 @@ const (
 	ArtifactHelmFormat      ArtifactFormat = "helm"
 	ArtifactPypiFormat      ArtifactFormat = "pypi"
 	ArtifactNpmFormat       ArtifactFormat = "npm"
+	ArtifactDebianFormat    ArtifactFormat = "debian"
 )
 
-var AllArtifactFormatList = []ArtifactFormat{ArtifactRawFormat, ArtifactMavenFormat, ArtifactContainerFormat, ArtifactHelmFormat}
+var AllArtifactFormatList = []ArtifactFormat{ArtifactRawFormat, ArtifactMavenFormat, ArtifactContainerFormat, ArtifactHelmFormat, ArtifactNpmFormat, ArtifactPypiFormat, ArtifactDebianFormat}
 
 type AssetKind string
 
@@ -64,11 +66,148 @@ type ArtifactRepository struct {
 }
 
 type ArtifactView struct {
-	ID          int64  `gorm:"column:view_id;primaryKey" json:"id"`
-	Name        string `gorm:"column:view_name"`
-	Description string `gorm:"column:view_description"`
-	SpaceID     int64  `gorm:"column:view_space_id"`
-	Default     bool   `gorm:"column:view_is_default"`
+	ID          int64                 `gorm:"column:view_id;primaryKey" json:"id"`
+	Name        string                `gorm:"column:view_name"`
+	Description string                `gorm:"column:view_description"`
+	SpaceID     int64                 `gorm:"column:view_space_id"`
+	Default     bool                  `gorm:"column:view_is_default"`
+	Kind        enum.ArtifactRepoKind `gorm:"column:view_kind;default:standalone"`
+	// Protected views only accept the versions promoted by the PromoteRoles
+	Protected bool `gorm:"column:view_protected"`
+	// PromoteRoles is a comma separated list of the membership roles allowed to promote into the view
+	PromoteRoles string `gorm:"column:view_promote_roles"`
+}
+
+func (v *ArtifactView) IsProxy() bool {
+	return v.Kind == enum.ArtifactRepoKindProxy
+}
+
+// GetPromoteRoles returns the roles allowed to promote into the protected view,
+// only the space owners are allowed if no role is set.
+func (v *ArtifactView) GetPromoteRoles() []enum.MembershipRole {
+	roles := make([]enum.MembershipRole, 0)
+	for _, role := range strings.Split(v.PromoteRoles, ",") {
+		if role = strings.TrimSpace(role); role != "" {
+			roles = append(roles, enum.MembershipRole(role))
+		}
+	}
+	if len(roles) == 0 {
+		roles = append(roles, enum.MembershipRoleSpaceOwner)
+	}
+	return roles
+}
+
+// ArtifactUpstream stores the upstream registry of a proxy view
+type ArtifactUpstream struct {
+	ID     int64          `gorm:"column:upstream_id;primaryKey" json:"-"`
+	ViewID int64          `gorm:"column:upstream_view_id"       json:"-"`
+	Format ArtifactFormat `gorm:"column:upstream_format"        json:"format"`
+	URL    string         `gorm:"column:upstream_url"           json:"url"`
+	// Username is used with the password stored in the secret
+	Username    string   `gorm:"column:upstream_username"     json:"username,omitempty"`
+	SecretID    null.Int `gorm:"column:upstream_secret_id"    json:"-"`
+	ConnectorID null.Int `gorm:"column:upstream_connector_id" json:"-"`
+	// ManifestTTL is the seconds a cached tag is served before checking the upstream again
+	ManifestTTL int64 `gorm:"column:upstream_manifest_ttl" json:"manifest_ttl"`
+	CreatedBy   int64 `gorm:"column:upstream_created_by"   json:"created_by"`
+	Created     int64 `gorm:"column:upstream_created;autoCreateTime:milli" json:"created"`
+	Updated     int64 `gorm:"column:upstream_updated;autoUpdateTime:milli" json:"updated"`
+}
+
+// ArtifactRetentionPolicy declares which versions of a format in the space are kept,
+// the rules are disabled with zero value.
+type ArtifactRetentionPolicy struct {
+	ID      int64          `gorm:"column:policy_id;primaryKey" json:"-"`
+	SpaceID int64          `gorm:"column:policy_space_id"      json:"-"`
+	Format  ArtifactFormat `gorm:"column:policy_format"        json:"format"`
+	// KeepLast keeps the most recent versions of each package
+	KeepLast int `gorm:"column:policy_keep_last" json:"keep_last"`
+	// MaxAgeDays removes the versions not updated for the days
+	MaxAgeDays int `gorm:"column:policy_max_age_days" json:"max_age_days"`
+	// KeepPattern is a regular expression, the matched versions are always kept
+	KeepPattern string `gorm:"column:policy_keep_pattern" json:"keep_pattern"`
+	// ProtectedTags is a glob like 'release-*', the matched versions are never removed
+	ProtectedTags string `gorm:"column:policy_protected_tags" json:"protected_tags"`
+	// NotPulledDays removes the versions not pulled for the days
+	NotPulledDays int   `gorm:"column:policy_not_pulled_days" json:"not_pulled_days"`
+	Enabled       bool  `gorm:"column:policy_enabled"        json:"enabled"`
+	CreatedBy     int64 `gorm:"column:policy_created_by"     json:"created_by"`
+	Created       int64 `gorm:"column:policy_created;autoCreateTime:milli" json:"created"`
+	Updated       int64 `gorm:"column:policy_updated;autoUpdateTime:milli" json:"updated"`
+}
+
+// ArtifactSbomComponent is a component listed in the sbom of the version,
+// Source is the sbom format the component comes from.
+type ArtifactSbomComponent struct {
+	ID        int64  `gorm:"column:component_id;primaryKey" json:"-"`
+	VersionID int64  `gorm:"column:component_version_id"    json:"-"`
+	Name      string `gorm:"column:component_name"          json:"name"`
+	Group     string `gorm:"column:component_group"         json:"group,omitempty"`
+	Version   string `gorm:"column:component_version"       json:"version"`
+	Purl      string `gorm:"column:component_purl"          json:"purl,omitempty"`
+	Type      string `gorm:"column:component_type"          json:"type,omitempty"`
+	Source    string `gorm:"column:component_source"        json:"source"`
+	Created   int64  `gorm:"column:component_created;autoCreateTime:milli" json:"created"`
+}
+
+// ArtifactQuota limits the artifact storage of the space, the limit is disabled with zero value.
+// The quota limits the total usage of the space and its sub spaces, a sub space with its own quota
+// is limited by its own quota.
+type ArtifactQuota struct {
+	ID          int64 `gorm:"column:quota_id;primaryKey"  json:"-"`
+	SpaceID     int64 `gorm:"column:quota_space_id"       json:"space_id"`
+	MaxSize     int64 `gorm:"column:quota_max_size"       json:"max_size"`
+	MaxPackages int64 `gorm:"column:quota_max_packages"   json:"max_packages"`
+	CreatedBy   int64 `gorm:"column:quota_created_by"     json:"created_by"`
+	Created     int64 `gorm:"column:quota_created;autoCreateTime:milli" json:"created"`
+	Updated     int64 `gorm:"column:quota_updated;autoUpdateTime:milli" json:"updated"`
+}
+
+// ArtifactUsage counts the blob size and packages stored in the space,
+// it is maintained along with the uploads and removals.
+type ArtifactUsage struct {
+	SpaceID  int64 `gorm:"column:usage_space_id;primaryKey;autoIncrement:false" json:"-"`
+	Size     int64 `gorm:"column:usage_size"     json:"size"`
+	Packages int64 `gorm:"column:usage_packages" json:"packages"`
+	Updated  int64 `gorm:"column:usage_updated;autoUpdateTime:milli" json:"updated"`
+}
+
+// ArtifactUsageBlob records a blob shared by the spaces, e.g. the container layer, is counted in the usage of the space.
+// The blob is counted once per space and released when the space doesn't refer to it anymore.
+type ArtifactUsageBlob struct {
+	SpaceID int64  `gorm:"column:usage_blob_space_id;primaryKey;autoIncrement:false"`
+	Digest  string `gorm:"column:usage_blob_digest;primaryKey"`
+	Size    int64  `gorm:"column:usage_blob_size"`
+	Created int64  `gorm:"column:usage_blob_created;autoCreateTime:milli"`
+}
+
+// ArtifactPromotion records a version promoted from a view to another view of the space,
+// VersionID is the version in the target view.
+type ArtifactPromotion struct {
+	ID           int64          `gorm:"column:promotion_id;primaryKey"   json:"id"`
+	SpaceID      int64          `gorm:"column:promotion_space_id"        json:"-"`
+	PackageID    int64          `gorm:"column:promotion_package_id"      json:"-"`
+	Version      string         `gorm:"column:promotion_version"         json:"version"`
+	Format       ArtifactFormat `gorm:"column:promotion_format"          json:"format"`
+	SourceViewID int64          `gorm:"column:promotion_source_view_id"  json:"-"`
+	TargetViewID int64          `gorm:"column:promotion_target_view_id"  json:"-"`
+	VersionID    int64          `gorm:"column:promotion_version_id"      json:"-"`
+	Move         bool           `gorm:"column:promotion_move"            json:"move"`
+	CreatedBy    int64          `gorm:"column:promotion_created_by"      json:"created_by"`
+	Created      int64          `gorm:"column:promotion_created;autoCreateTime:milli" json:"created"`
+}
+
+// ArtifactSigningKey is the gpg key of the space signing the repository indexes, like the apt Release files.
+// PrivateKey is the armored private key encrypted by the server.
+type ArtifactSigningKey struct {
+	ID          int64  `gorm:"column:key_id;primaryKey"  json:"-"`
+	SpaceID     int64  `gorm:"column:key_space_id"       json:"space_id"`
+	Fingerprint string `gorm:"column:key_fingerprint"    json:"fingerprint"`
+	PublicKey   string `gorm:"column:key_public"         json:"public_key"`
+	PrivateKey  []byte `gorm:"column:key_private"        json:"-"`
+	CreatedBy   int64  `gorm:"column:key_created_by"     json:"created_by"`
+	Created     int64  `gorm:"column:key_created;autoCreateTime:milli" json:"created"`
+	Updated     int64  `gorm:"column:key_updated;autoUpdateTime:milli" json:"updated"`
 }
 
 type ArtifactPackage struct {
@@ -80,6 +219,9 @@ type ArtifactPackage struct {
 	Created   int64          `gorm:"column:package_created;autoCreateTime:milli" json:"created"`
 	Updated   int64          `gorm:"column:package_updated;autoUpdateTime:milli" json:"updated"`
 	Deleted   int64          `gorm:"column:package_deleted"`
+	// Downloads and LastPulled are read only, they are counted by the download recorder
+	Downloads  int64 `gorm:"column:package_downloads;->"   json:"downloads"`
+	LastPulled int64 `gorm:"column:package_last_pulled;->" json:"last_pulled"`
 }
 
 func (p *ArtifactPackage) IsDeleted() bool {
@@ -98,6 +240,12 @@ type ArtifactVersion struct {
 	Created int64 `gorm:"column:version_created;autoCreateTime:milli" json:"created"`
 	Updated int64 `gorm:"column:version_updated;autoUpdateTime:milli" json:"updated"`
 	Deleted int64 `gorm:"column:version_deleted"`
+	// Yanked is the time the version is yanked, a yanked version is left out of the index
+	// but it's still downloaded by the exact reference.
+	Yanked int64 `gorm:"column:version_yanked" json:"yanked"`
+	// Downloads and LastPulled are read only, they are counted by the download recorder
+	Downloads  int64 `gorm:"column:version_downloads;->"   json:"downloads"`
+	LastPulled int64 `gorm:"column:version_last_pulled;->" json:"last_pulled"`
 }
 
 type ArtifactVersionInfo struct {
@@ -118,6 +266,10 @@ func (v *ArtifactVersion) IsDeleted() bool {
 	return v.Deleted > 0
 }
 
+func (v *ArtifactVersion) IsYanked() bool {
+	return v.Yanked > 0
+}
+
 type ArtifactAsset struct {
 	ID int64 `gorm:"column:asset_id;primaryKey"`
 	// VersionID binding with package(with format), view
@@ -173,6 +325,22 @@ type ArtifactBlob struct {
 	Creator  int64    `gorm:"column:blob_creator"`
 }
 
+// ArtifactReferrer indexes a container manifest refers to a subject manifest by the OCI 1.1 subject field
+type ArtifactReferrer struct {
+	ID         int64  `gorm:"column:referrer_id;primaryKey"`
+	ViewID     int64  `gorm:"column:referrer_view_id"`
+	Repository string `gorm:"column:referrer_repository"`
+	// Subject is the digest of the manifest referred to
+	Subject      string `gorm:"column:referrer_subject"`
+	Digest       string `gorm:"column:referrer_digest"`
+	MediaType    string `gorm:"column:referrer_media_type"`
+	ArtifactType string `gorm:"column:referrer_artifact_type"`
+	Size         int64  `gorm:"column:referrer_size"`
+	// Annotations store the manifest annotations as json
+	Annotations string `gorm:"column:referrer_annotations"`
+	Created     int64  `gorm:"column:referrer_created;autoCreateTime:milli"`
+}
+
 type ArtifactTreeNode struct {
 	ID       int64                `gorm:"column:node_id;primaryKey"`
 	ParentID null.Int             `gorm:"column:node_parent_id"`
@@ -22,6 +22,7 @@ type SearchVersionOption struct {
 	Size           int    `json:"size"`
 	Query          string `json:"query"`
 	IncludeDeleted bool   `json:"include_deleted"`
+	ExcludeYanked  bool   `json:"exclude_yanked"`
 }
 
 func (opt SearchVersionOption) Apply(db *gorm.DB) *gorm.DB {
@@ -81,6 +81,8 @@ type ArtifactVersionsRes struct {
 	Version     string `json:"version"`
 	CreatorName string `json:"creator_name"`
 	Updated     int64  `json:"updated"`
+	Downloads   int64  `json:"downloads"`
+	LastPulled  int64  `json:"last_pulled"`
 }
 
 type ArtifactTreeNodeType string
@@ -189,3 +191,100 @@ type ArtifactVersionCapacityRes struct {
 	Size          int64  `json:"size"`
 	ExclusiveSize int64  `json:"exclusive_size"`
 }
+
+// ArtifactProxyRepository is an artifact view caches the artifacts of the upstream registry
+type ArtifactProxyRepository struct {
+	Identifier  string                `json:"identifier"`
+	Description string                `json:"description"`
+	Kind        enum.ArtifactRepoKind `json:"kind"`
+	Upstream    *ArtifactUpstream     `json:"upstream"`
+	Secret      string                `json:"secret,omitempty"`
+	Connector   string                `json:"connector,omitempty"`
+}
+
+// ArtifactRetentionCandidate is a version would be removed by the retention policy
+type ArtifactRetentionCandidate struct {
+	Package   string `json:"package"`
+	Namespace string `json:"namespace,omitempty"`
+	Version   string `json:"version"`
+	VersionID int64  `json:"version_id"`
+	ViewID    int64  `json:"view_id"`
+	Updated   int64  `json:"updated"`
+	Reason    string `json:"reason"`
+}
+
+type ArtifactRetentionReport struct {
+	Policy     *ArtifactRetentionPolicy      `json:"policy"`
+	DryRun     bool                          `json:"dry_run"`
+	Candidates []*ArtifactRetentionCandidate `json:"candidates"`
+	Removed    int                           `json:"removed"`
+}
+
+// ArtifactQuotaRes is the effective quota and the usage of the space,
+// Quota is nil if neither the space nor its parents limit the storage.
+type ArtifactQuotaRes struct {
+	Quota     *ArtifactQuota `json:"quota"`
+	Inherited bool           `json:"inherited"`
+	Usage     ArtifactUsage  `json:"usage"`
+}
+
+// ArtifactSbomComponentMatch is a package version which contains the searched component
+type ArtifactSbomComponentMatch struct {
+	Format           ArtifactFormat `gorm:"column:package_format"    json:"format"`
+	Package          string         `gorm:"column:package_name"      json:"package"`
+	Namespace        string         `gorm:"column:package_namespace" json:"namespace,omitempty"`
+	Version          string         `gorm:"column:version"           json:"version"`
+	VersionID        int64          `gorm:"column:version_id"        json:"version_id"`
+	ViewID           int64          `gorm:"column:version_view_id"   json:"view_id"`
+	ComponentName    string         `gorm:"column:component_name"    json:"component_name"`
+	ComponentGroup   string         `gorm:"column:component_group"   json:"component_group,omitempty"`
+	ComponentVersion string         `gorm:"column:component_version" json:"component_version"`
+	Purl             string         `gorm:"column:component_purl"    json:"purl,omitempty"`
+}
+
+type ArtifactSbomUploadRes struct {
+	Format     string `json:"format"`
+	Components int    `json:"components"`
+}
+
+// ArtifactBlobMigrationInput selects the storage providers the artifact blobs are copied between.
+type ArtifactBlobMigrationInput struct {
+	Source StorageProviderType `json:"source"`
+	Target StorageProviderType `json:"target"`
+}
+
+// ArtifactBlobMigrationReport is the result of the blob migration job, it is saved with the job
+// progress and the LastPath is used as the checkpoint to resume an interrupted migration.
+type ArtifactBlobMigrationReport struct {
+	Source     StorageProviderType `json:"source"`
+	Target     StorageProviderType `json:"target"`
+	TotalFiles int64               `json:"total_files"`
+	TotalSize  int64               `json:"total_size"`
+	Copied     int64               `json:"copied"`
+	CopiedSize int64               `json:"copied_size"`
+	Skipped    int64               `json:"skipped"`
+	// Failed lists the first failed paths, FailedCount counts all of them
+	Failed      []string `json:"failed,omitempty"`
+	FailedCount int64    `json:"failed_count"`
+	LastPath    string   `json:"last_path,omitempty"`
+}
+
+// ArtifactViewRes is an artifact view of the space, the packages of a view
+// are pulled and pushed by '{space}@{identifier}'.
+type ArtifactViewRes struct {
+	Identifier   string                `json:"identifier"`
+	Description  string                `json:"description"`
+	Kind         enum.ArtifactRepoKind `json:"kind"`
+	Default      bool                  `json:"default"`
+	Protected    bool                  `json:"protected"`
+	PromoteRoles []enum.MembershipRole `json:"promote_roles"`
+}
+
+// ArtifactPromotionRes is a promotion with the names of the package and views
+type ArtifactPromotionRes struct {
+	*ArtifactPromotion
+	Package    string `json:"package"`
+	Namespace  string `json:"namespace,omitempty"`
+	SourceView string `json:"source_view"`
+	TargetView string `json:"target_view"`
+}
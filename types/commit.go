@@ -19,3 +19,12 @@ type CommitFilesResponse struct {
 	CommitID string `json:"commit_id"`
 	DryRunRulesOutput
 }
+
+// CherryPickResponse is the result of cherry-picking or reverting commits,
+// PullReq is set if a pull request was opened for the changes.
+type CherryPickResponse struct {
+	CommitID string   `json:"commit_id"`
+	Branch   string   `json:"branch"`
+	PullReq  *PullReq `json:"pull_request,omitempty"`
+	DryRunRulesOutput
+}
@@ -202,6 +202,9 @@ type Config struct {
 			Provider StorageProviderType `envconfig:"GITFOX_ARTIFACT_STORAGE_PROVIDER" default:"local"`
 			Prefix   string              `envconfig:"GITFOX_ARTIFACT_STORAGE_PREFIX" default:"artifacts"`
 		}
+
+		// SbomMaxSize is the maximum size in bytes of the sbom documents which are parsed.
+		SbomMaxSize int64 `envconfig:"GITFOX_ARTIFACT_SBOM_MAX_SIZE" default:"33554432"`
 	}
 
 	// Database defines the database configuration parameters.
@@ -415,6 +418,38 @@ type Config struct {
 		NumWorkers  int           `envconfig:"GITFOX_REPO_SIZE_NUM_WORKERS" default:"5"`
 	}
 
+	RepoHousekeeping struct {
+		Enabled     bool          `envconfig:"GITFOX_REPO_HOUSEKEEPING_ENABLED" default:"true"`
+		CRON        string        `envconfig:"GITFOX_REPO_HOUSEKEEPING_CRON" default:"30 2 * * *"`
+		MaxDuration time.Duration `envconfig:"GITFOX_REPO_HOUSEKEEPING_MAX_DURATION" default:"2h"`
+		NumWorkers  int           `envconfig:"GITFOX_REPO_HOUSEKEEPING_NUM_WORKERS" default:"2"`
+		// LooseObjectsLimit is the number of the loose objects above which the loose objects are repacked.
+		LooseObjectsLimit int64 `envconfig:"GITFOX_REPO_HOUSEKEEPING_LOOSE_OBJECTS_LIMIT" default:"1024"`
+		// PacksLimit is the number of the packs above which all objects are repacked by gc.
+		PacksLimit int64 `envconfig:"GITFOX_REPO_HOUSEKEEPING_PACKS_LIMIT" default:"32"`
+		// PruneExpiry is the expiry date of the unreachable objects pruned by gc, like "2.weeks.ago".
+		// The objects of the repositories with forks are never pruned. The pushes don't wait for gc,
+		// "now" isn't allowed as it would prune the objects of a running push.
+		PruneExpiry string `envconfig:"GITFOX_REPO_HOUSEKEEPING_PRUNE_EXPIRY" default:"2.weeks.ago"`
+	}
+
+	MirrorSync struct {
+		Enabled     bool          `envconfig:"GITFOX_MIRROR_SYNC_ENABLED" default:"true"`
+		CRON        string        `envconfig:"GITFOX_MIRROR_SYNC_CRON" default:"* * * * *"`
+		MaxDuration time.Duration `envconfig:"GITFOX_MIRROR_SYNC_MAX_DURATION" default:"30m"`
+		NumWorkers  int           `envconfig:"GITFOX_MIRROR_SYNC_NUM_WORKERS" default:"4"`
+	}
+
+	PushMirror struct {
+		Enabled bool   `envconfig:"GITFOX_PUSH_MIRROR_ENABLED" default:"true"`
+		CRON    string `envconfig:"GITFOX_PUSH_MIRROR_CRON" default:"* * * * *"`
+		// MaxDuration is the timeout of a single push
+		MaxDuration time.Duration `envconfig:"GITFOX_PUSH_MIRROR_MAX_DURATION" default:"30m"`
+		// MaxRetries is the times a failed push is retried
+		MaxRetries  int `envconfig:"GITFOX_PUSH_MIRROR_MAX_RETRIES" default:"3"`
+		Concurrency int `envconfig:"GITFOX_PUSH_MIRROR_CONCURRENCY" default:"4"`
+	}
+
 	CodeOwners struct {
 		FilePaths []string `envconfig:"GITFOX_CODEOWNERS_FILEPATH" default:"CODEOWNERS,.gitfox/CODEOWNERS"`
 	}
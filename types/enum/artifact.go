@@ -13,6 +13,8 @@ const (
 	ArtifactRepoKindProduct     ArtifactRepoKind = "product"
 	ArtifactRepoKindProductLine ArtifactRepoKind = "product_line"
 	ArtifactRepoKindGitRepo     ArtifactRepoKind = "git_repo"
+	// ArtifactRepoKindProxy caches the artifacts pulled from an upstream registry
+	ArtifactRepoKindProxy ArtifactRepoKind = "proxy"
 )
 
 func (ArtifactRepoKind) Enum() []interface{} { return toInterfaceSlice(ArtifactRepoKinds) }
@@ -30,6 +32,7 @@ var ArtifactRepoKinds = sortEnum([]ArtifactRepoKind{
 	ArtifactRepoKindProduct,
 	ArtifactRepoKindProductLine,
 	ArtifactRepoKindGitRepo,
+	ArtifactRepoKindProxy,
 })
 
 func (ArtifactRepoKind) CreatableEnum() []interface{} {
@@ -25,6 +25,7 @@ const (
 
 var publicKeyTypes = sortEnum([]PublicKeyUsage{
 	PublicKeyUsageAuth,
+	PublicKeyUsageSign,
 })
 
 func (PublicKeyUsage) Enum() []interface{} { return toInterfaceSlice(publicKeyTypes) }
@@ -35,6 +36,49 @@ func GetAllPublicKeyUsages() ([]PublicKeyUsage, PublicKeyUsage) {
 	return publicKeyTypes, PublicKeyUsageAuth
 }
 
+// PublicKeyScheme represents the scheme of public key.
+type PublicKeyScheme string
+
+// PublicKeyScheme enumeration.
+const (
+	PublicKeySchemeSSH PublicKeyScheme = "ssh"
+	PublicKeySchemePGP PublicKeyScheme = "pgp"
+)
+
+var publicKeySchemes = sortEnum([]PublicKeyScheme{
+	PublicKeySchemeSSH,
+	PublicKeySchemePGP,
+})
+
+func (PublicKeyScheme) Enum() []interface{} { return toInterfaceSlice(publicKeySchemes) }
+func (s PublicKeyScheme) Sanitize() (PublicKeyScheme, bool) {
+	return Sanitize(s, GetAllPublicKeySchemes)
+}
+func GetAllPublicKeySchemes() ([]PublicKeyScheme, PublicKeyScheme) {
+	return publicKeySchemes, PublicKeySchemeSSH
+}
+
+// GitSignatureResult represents the result of the verification of a git object signature.
+type GitSignatureResult string
+
+// GitSignatureResult enumeration.
+const (
+	// GitSignatureVerified is the valid signature of a registered key of the committer.
+	GitSignatureVerified GitSignatureResult = "verified"
+	// GitSignatureUnverified is the invalid signature, or the key isn't of the committer.
+	GitSignatureUnverified GitSignatureResult = "unverified"
+	// GitSignatureUnknownKey is the signature of a key that isn't registered.
+	GitSignatureUnknownKey GitSignatureResult = "unknown_key"
+)
+
+var gitSignatureResults = sortEnum([]GitSignatureResult{
+	GitSignatureVerified,
+	GitSignatureUnverified,
+	GitSignatureUnknownKey,
+})
+
+func (GitSignatureResult) Enum() []interface{} { return toInterfaceSlice(gitSignatureResults) }
+
 // PublicKeySort is used to specify sorting of public keys.
 type PublicKeySort string
 
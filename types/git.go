@@ -96,6 +96,8 @@ type Commit struct {
 	Author     Signature    `json:"author"`
 	Committer  Signature    `json:"committer"`
 	Stats      *CommitStats `json:"stats,omitempty"`
+
+	Signature *GitSignatureResult `json:"signature,omitempty"`
 }
 
 type Signature struct {
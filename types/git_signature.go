@@ -0,0 +1,51 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package types
+
+import "github.com/easysoft/gitfox/types/enum"
+
+// GitSignatureResult is the cached result of the verification of a signed commit or tag.
+type GitSignatureResult struct {
+	RepoID    int64  `json:"-"`
+	ObjectSHA string `json:"-"`
+	Created   int64  `json:"created"`
+
+	Result enum.GitSignatureResult `json:"result"`
+
+	// KeyScheme and KeyID identify the key used for signing,
+	// the KeyID is the PGP key ID or the fingerprint of the SSH key.
+	KeyScheme enum.PublicKeyScheme `json:"key_scheme"`
+	KeyID     string               `json:"key_id"`
+
+	// PrincipalID is the owner of the key, if the key is registered.
+	PrincipalID *int64         `json:"-"`
+	Signer      *PrincipalInfo `json:"signer,omitempty"`
+
+	// ServerSigned is set if the object is signed by the signing key of the instance,
+	// it is derived from the result and not stored.
+	ServerSigned bool `json:"server_signed,omitempty"`
+}
+
+// ServerSigningKey is the key the instance signs the commits and tags created by the server with,
+// like merge commits and the commits of the web editor.
+type ServerSigningKey struct {
+	Scheme      enum.PublicKeyScheme `json:"scheme"`
+	Fingerprint string               `json:"fingerprint"`
+	// KeyIDs are the IDs of the PGP primary key and sub keys.
+	KeyIDs    []string `json:"key_ids,omitempty"`
+	PublicKey string   `json:"public_key"`
+
+	CreatedBy int64 `json:"created_by"`
+	Created   int64 `json:"created"`
+}
+
+// ServerSigningKeyInput is used to set the signing key of the instance.
+type ServerSigningKeyInput struct {
+	// PrivateKey is an armored PGP private key or an OpenSSH private key.
+	PrivateKey string `json:"private_key"`
+	Passphrase string `json:"passphrase"`
+}
@@ -0,0 +1,44 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package types
+
+// LFSObject is a git lfs object uploaded to the repository, the content is stored in the blob store by the OID.
+type LFSObject struct {
+	ID        int64  `json:"id"`
+	OID       string `json:"oid"`
+	Size      int64  `json:"size"`
+	RepoID    int64  `json:"repo_id"`
+	Created   int64  `json:"created"`
+	CreatedBy int64  `json:"created_by"`
+}
+
+// LFSLock is a git lfs file lock of the repository.
+type LFSLock struct {
+	ID        int64  `json:"id"`
+	RepoID    int64  `json:"repo_id"`
+	Path      string `json:"path"`
+	Ref       string `json:"ref,omitempty"`
+	Created   int64  `json:"created"`
+	CreatedBy int64  `json:"created_by"`
+}
+
+// LFSLockFilter filters the locks listed, the fields are ignored with zero value.
+type LFSLockFilter struct {
+	ID        int64
+	Path      string
+	Ref       string
+	CreatedBy int64
+	// Cursor is the id of the last lock of the previous page
+	Cursor int64
+	Limit  int
+}
+
+// LFSUsage counts the lfs objects stored in the repository.
+type LFSUsage struct {
+	Objects int64 `json:"objects"`
+	Size    int64 `json:"size"`
+}
@@ -17,16 +17,20 @@ package types
 import "github.com/easysoft/gitfox/types/enum"
 
 type PublicKey struct {
-	ID          int64               `json:"-"` // frontend doesn't need it
-	PrincipalID int64               `json:"-"` // API always returns keys for the same user
-	Created     int64               `json:"created"`
-	Verified    *int64              `json:"verified"`
-	Identifier  string              `json:"identifier"`
-	Usage       enum.PublicKeyUsage `json:"usage"`
-	Fingerprint string              `json:"fingerprint"`
-	Content     string              `json:"-"`
-	Comment     string              `json:"comment"`
-	Type        string              `json:"type"`
+	ID          int64                `json:"-"` // frontend doesn't need it
+	PrincipalID int64                `json:"-"` // API always returns keys for the same user
+	Created     int64                `json:"created"`
+	Verified    *int64               `json:"verified"`
+	Identifier  string               `json:"identifier"`
+	Usage       enum.PublicKeyUsage  `json:"usage"`
+	Fingerprint string               `json:"fingerprint"`
+	Content     string               `json:"-"`
+	Comment     string               `json:"comment"`
+	Type        string               `json:"type"`
+	Scheme      enum.PublicKeyScheme `json:"scheme"`
+
+	// KeyIDs are the IDs of the primary key and the sub keys of a PGP key.
+	KeyIDs []string `json:"key_ids,omitempty"`
 }
 
 type PublicKeyFilter struct {
@@ -0,0 +1,45 @@
+// Copyright (c) 2023-2024 北京渠成软件有限公司(Beijing Qucheng Software Co., Ltd. www.qucheng.com) All rights reserved.
+// Use of this source code is covered by the following dual licenses:
+// (1) Z PUBLIC LICENSE 1.2 (ZPL 1.2)
+// (2) Affero General Public License 3.0 (AGPL 3.0)
+// license that can be found in the LICENSE file.
+
+package types
+
+import "github.com/guregu/null"
+
+// PushMirror replicates the branches and tags of the repository to an external remote.
+type PushMirror struct {
+	ID     int64  `json:"id"`
+	RepoID int64  `json:"repo_id"`
+	URL    string `json:"url"`
+	// Username is used with the password stored in the secret
+	Username    string   `json:"username,omitempty"`
+	SecretID    null.Int `json:"-"`
+	ConnectorID null.Int `json:"-"`
+	// Secret and Connector are the identifiers of the credentials in the space of the repository
+	Secret    string `json:"secret,omitempty"`
+	Connector string `json:"connector,omitempty"`
+	// CredentialsBy is the principal authorized the use of the secret or the connector by the mirror,
+	// the access of the principal is checked again before each push
+	CredentialsBy null.Int `json:"-"`
+	// BranchFilter lists the branches pushed separated by comma like 'main,release/*', all branches if empty
+	BranchFilter string `json:"branch_filter"`
+	// Force overwrites the remote branches diverged from the repository
+	Force bool `json:"force"`
+	// Prune removes the remote branches and tags which don't exist in the repository
+	Prune bool `json:"prune"`
+	// SyncOnPush pushes the mirror right after the repository receives a push
+	SyncOnPush bool `json:"sync_on_push"`
+	// SyncInterval is the minutes between the scheduled pushes, the schedule is disabled with zero
+	SyncInterval int64  `json:"sync_interval"`
+	Enabled      bool   `json:"enabled"`
+	LastAttempt  int64  `json:"last_attempt"`
+	LastSuccess  int64  `json:"last_success"`
+	LastError    string `json:"last_error,omitempty"`
+	// NextSync is the time the mirror is pushed by the scheduler, zero if not scheduled
+	NextSync  int64 `json:"next_sync"`
+	CreatedBy int64 `json:"created_by"`
+	Created   int64 `json:"created"`
+	Updated   int64 `json:"updated"`
+}
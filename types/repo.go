@@ -123,6 +123,7 @@ type RepositoryMirror struct {
 	NextUpdateUnix int64  `json:"next_update_unix"`
 	LfsEnabled     bool   `json:"lfs_enabled"`
 	RemoteAddress  string `json:"remote_address"`
+	LastError      string `json:"last_error,omitempty"`
 	Token          string `json:"token,omitempty"`
 	RepoID         int64  `json:"repo_id,omitempty"`
 	SpaceID        int64  `json:"space_id,omitempty"`
@@ -134,3 +135,19 @@ type RepositoryCount struct {
 	SpaceUID string `json:"space_uid"`
 	Total    int    `json:"total"`
 }
+
+// RepositoryHousekeeping is the object database state of a repository and the housekeeping tasks it needs.
+type RepositoryHousekeeping struct {
+	LooseObjects int64 `json:"loose_objects"`
+	// LooseSize is the size of the loose objects in KiB.
+	LooseSize     int64 `json:"loose_size"`
+	PackedObjects int64 `json:"packed_objects"`
+	Packs         int64 `json:"packs"`
+	// PackSize is the size of the packs in KiB.
+	PackSize          int64 `json:"pack_size"`
+	HasAlternates     bool  `json:"has_alternates"`
+	HasCommitGraph    bool  `json:"has_commit_graph"`
+	HasMultiPackIndex bool  `json:"has_multi_pack_index"`
+	// Tasks are the housekeeping tasks the next scheduled run performs, like "repack" or "commit-graph".
+	Tasks []string `json:"tasks"`
+}